@@ -41,3 +41,9 @@ func (fb *filterBackend) GetBorBlockLogs(ctx context.Context, hash common.Hash)
 func (fb *filterBackend) SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) event.Subscription {
 	return fb.bc.SubscribeStateSyncEvent(ch)
 }
+
+// SubscribeStateSyncCommitEvent subscribes to the state syncs committed on the
+// canonical chain
+func (fb *filterBackend) SubscribeStateSyncCommitEvent(ch chan<- core.StateSyncCommitEvent) event.Subscription {
+	return fb.bc.SubscribeStateSyncCommitEvent(ch)
+}
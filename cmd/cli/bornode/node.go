@@ -0,0 +1,153 @@
+// Package bornode allows constructing, starting and controlling a Bor node from
+// Go code, without exec-ing the CLI. It is meant for integration tests and for
+// custom orchestration binaries.
+//
+// A node is configured through typed options on top of the CLI defaults:
+//
+//	n, err := bornode.New(
+//		bornode.WithChain("amoy"),
+//		bornode.WithDataDir("/var/lib/bor"),
+//		bornode.WithRole(bornode.RoleArchive),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	if err := n.Start(); err != nil {
+//		return err
+//	}
+//	defer n.Stop()
+package bornode
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/internal/cli/server"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// ErrNodeRunning is returned when starting a node that is already running.
+	ErrNodeRunning = errors.New("node already running")
+
+	// ErrNodeStopped is returned when accessing a node that is not running.
+	ErrNodeStopped = errors.New("node not running")
+
+	// errNoSigner is returned when configuring a validator without a signer.
+	errNoSigner = errors.New("validator role requires a signer")
+)
+
+// Role is the part a node plays in the network.
+type Role int
+
+const (
+	// RoleFull syncs and serves the chain, pruning historical state.
+	RoleFull Role = iota
+
+	// RoleArchive syncs and serves the chain, retaining all historical state.
+	RoleArchive
+
+	// RoleValidator seals blocks with the configured signer on top of syncing.
+	RoleValidator
+)
+
+// Node is a Bor node embedded in the running process. A stopped node can be
+// started again, reusing its configuration and data directory.
+type Node struct {
+	config   *server.Config
+	heimdall bor.IHeimdallClient
+
+	srv  *server.Server
+	lock sync.Mutex
+}
+
+// New creates a node from the CLI defaults and the given options. The node is
+// not started.
+func New(opts ...Option) (*Node, error) {
+	n := &Node{
+		config: server.DefaultConfig(),
+	}
+
+	for _, opt := range opts {
+		if err := opt(n); err != nil {
+			return nil, err
+		}
+	}
+
+	if n.config.Sealer.Enabled && n.config.Sealer.Etherbase == "" && !n.config.Developer.Enabled {
+		return nil, errNoSigner
+	}
+
+	return n, nil
+}
+
+// Start starts the node, returning once its services are running.
+func (n *Node) Start() error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.srv != nil {
+		return ErrNodeRunning
+	}
+
+	srv, err := server.NewServer(n.config, server.WithHeimdallClient(n.heimdall))
+	if err != nil {
+		return err
+	}
+
+	n.srv = srv
+
+	return nil
+}
+
+// Stop stops the node and all its services. Stopping a node that is not
+// running is a no-op.
+func (n *Node) Stop() {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.srv == nil {
+		return
+	}
+
+	n.srv.Stop()
+	n.srv = nil
+}
+
+// Ethereum returns the ethereum backend of the running node.
+func (n *Node) Ethereum() (*eth.Ethereum, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.srv == nil {
+		return nil, ErrNodeStopped
+	}
+
+	return n.srv.Backend(), nil
+}
+
+// Stack returns the node stack (p2p server, rpc endpoints, databases) of the
+// running node.
+func (n *Node) Stack() (*node.Node, error) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.srv == nil {
+		return nil, ErrNodeStopped
+	}
+
+	return n.srv.Node(), nil
+}
+
+// Attach creates an in-process RPC client to the running node.
+func (n *Node) Attach() (*rpc.Client, error) {
+	stack, err := n.Stack()
+	if err != nil {
+		return nil, err
+	}
+
+	return stack.Attach(), nil
+}
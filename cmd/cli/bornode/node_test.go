@@ -0,0 +1,66 @@
+package bornode
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestNodeLifecycle(t *testing.T) {
+	t.Parallel()
+
+	n, err := New(
+		WithDeveloper(1),
+		WithDataDir(t.TempDir()),
+		WithHTTP("127.0.0.1", 0),
+		WithP2P(0, false),
+		WithVerbosity(0),
+	)
+	require.NoError(t, err)
+
+	_, err = n.Ethereum()
+	require.ErrorIs(t, err, ErrNodeStopped)
+
+	require.NoError(t, n.Start())
+	defer n.Stop()
+
+	require.ErrorIs(t, n.Start(), ErrNodeRunning)
+
+	client, err := n.Attach()
+	require.NoError(t, err)
+
+	defer client.Close()
+
+	// The developer chain seals a block every second
+	require.Eventually(t, func() bool {
+		var number hexutil.Uint64
+		if err := client.CallContext(context.Background(), &number, "eth_blockNumber"); err != nil {
+			return false
+		}
+
+		return number > 0
+	}, 10*time.Second, 100*time.Millisecond)
+
+	backend, err := n.Ethereum()
+	require.NoError(t, err)
+	require.True(t, backend.IsMining())
+
+	n.Stop()
+
+	_, err = n.Stack()
+	require.ErrorIs(t, err, ErrNodeStopped)
+}
+
+func TestValidatorRequiresSigner(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(WithRole(RoleValidator))
+	require.ErrorIs(t, err, errNoSigner)
+
+	_, err = New(WithRole(Role(42)))
+	require.Error(t, err)
+}
@@ -0,0 +1,146 @@
+package bornode
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+)
+
+// Option configures a node created with New.
+type Option func(n *Node) error
+
+// WithChain sets the chain to sync, either by name ('amoy', 'mumbai',
+// 'mainnet') or as the path to a genesis file.
+func WithChain(chain string) Option {
+	return func(n *Node) error {
+		n.config.Chain = chain
+		return nil
+	}
+}
+
+// WithDataDir sets the directory the node stores its data in.
+func WithDataDir(dir string) Option {
+	return func(n *Node) error {
+		n.config.DataDir = dir
+		return nil
+	}
+}
+
+// WithRole sets the role of the node. Validators require WithSigner.
+func WithRole(role Role) Option {
+	return func(n *Node) error {
+		switch role {
+		case RoleFull:
+			n.config.GcMode = "full"
+			n.config.Sealer.Enabled = false
+		case RoleArchive:
+			n.config.GcMode = "archive"
+			n.config.Sealer.Enabled = false
+		case RoleValidator:
+			n.config.Sealer.Enabled = true
+		default:
+			return fmt.Errorf("unknown role %d", role)
+		}
+
+		return nil
+	}
+}
+
+// WithSigner sets the account the node seals blocks with, unlocking it from
+// the keystore in the data directory with the password in passwordFile.
+func WithSigner(signer common.Address, passwordFile string) Option {
+	return func(n *Node) error {
+		n.config.Sealer.Etherbase = signer.Hex()
+		n.config.Accounts.Unlock = []string{signer.Hex()}
+		n.config.Accounts.PasswordFile = passwordFile
+
+		return nil
+	}
+}
+
+// WithHeimdall sets the URL of the heimdall service to connect to.
+func WithHeimdall(url string) Option {
+	return func(n *Node) error {
+		n.config.Heimdall.URL = url
+		return nil
+	}
+}
+
+// WithHeimdallGRPC sets the address of the heimdall gRPC server to connect to.
+func WithHeimdallGRPC(addr string) Option {
+	return func(n *Node) error {
+		n.config.Heimdall.GRPCAddress = addr
+		return nil
+	}
+}
+
+// WithHeimdallClient makes the node use the given heimdall client instead of
+// connecting to a heimdall service, e.g. a mock in integration tests.
+func WithHeimdallClient(client bor.IHeimdallClient) Option {
+	return func(n *Node) error {
+		n.heimdall = client
+		return nil
+	}
+}
+
+// WithHeimdallReplay makes the node serve the heimdall responses recorded in the
+// given file (see --bor.heimdallrecord) instead of connecting to heimdall.
+func WithHeimdallReplay(path string) Option {
+	return func(n *Node) error {
+		n.config.Heimdall.Replay = path
+		return nil
+	}
+}
+
+// WithoutHeimdall runs the node without any heimdall service. This is only
+// meant for testing.
+func WithoutHeimdall() Option {
+	return func(n *Node) error {
+		n.config.Heimdall.Without = true
+		return nil
+	}
+}
+
+// WithHTTP enables the HTTP JSON-RPC server on the given address. Port 0
+// chooses a free port.
+func WithHTTP(host string, port uint64) Option {
+	return func(n *Node) error {
+		n.config.JsonRPC.Http.Enabled = true
+		n.config.JsonRPC.Http.Host = host
+		n.config.JsonRPC.Http.Port = port
+
+		return nil
+	}
+}
+
+// WithP2P sets the port the node listens on for peers, and whether peers are
+// discovered or only the given static nodes are dialed.
+func WithP2P(port uint64, discovery bool, staticNodes ...string) Option {
+	return func(n *Node) error {
+		n.config.P2P.Port = port
+		n.config.P2P.NoDiscover = !discovery
+		n.config.P2P.Discovery.StaticNodes = staticNodes
+
+		return nil
+	}
+}
+
+// WithDeveloper runs an ephemeral single-node chain sealing a block every
+// period seconds (0 = on demand), with a pre-funded developer account.
+func WithDeveloper(period uint64) Option {
+	return func(n *Node) error {
+		n.config.Developer.Enabled = true
+		n.config.Developer.Period = period
+
+		return nil
+	}
+}
+
+// WithVerbosity sets the log level (0 = silent, 5 = trace).
+func WithVerbosity(level int) Option {
+	return func(n *Node) error {
+		n.config.Verbosity = level
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Stages of the lifecycle of a block, recorded as spans by RecordBlockStage.
+const (
+	BlockAnnounce  = "announce"  // The block was announced or propagated by a peer
+	BlockFetch     = "fetch"     // The announced block was retrieved from the peer
+	BlockVerify    = "verify"    // The header of the block was verified
+	BlockExecute   = "execute"   // The block was executed and its state validated
+	BlockCommit    = "commit"    // The block and its state were written to the database
+	BlockBroadcast = "broadcast" // The block was propagated or announced to peers
+	BlockSeal      = "seal"      // The block produced locally was sealed
+)
+
+const blockTracerName = "bor/block"
+
+var blockTracing atomic.Bool
+
+// EnableBlockTracing enables recording the lifecycle of blocks to the global
+// tracer provider.
+func EnableBlockTracing() {
+	blockTracing.Store(true)
+}
+
+// BlockTracingEnabled returns whether the lifecycle of blocks is recorded.
+func BlockTracingEnabled() bool {
+	return blockTracing.Load()
+}
+
+// blockSpanContext returns the span context the stages of a block are recorded
+// under. Its trace id is derived from the block hash, so the stages of a block
+// are grouped into one trace on every node tracing them, without propagating
+// any context through the network.
+func blockSpanContext(hash common.Hash) trace.SpanContext {
+	var (
+		traceID trace.TraceID
+		spanID  trace.SpanID
+	)
+
+	copy(traceID[:], hash[:16])
+	copy(spanID[:], hash[16:24])
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+// RecordBlockStage records a span of a lifecycle stage of a block, lasting from
+// start to end. It's a no-op unless block tracing is enabled.
+func RecordBlockStage(stage string, hash common.Hash, number uint64, start, end time.Time, attrs ...attribute.KeyValue) {
+	if !BlockTracingEnabled() {
+		return
+	}
+
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), blockSpanContext(hash))
+
+	attrs = append(attrs,
+		attribute.String("block.hash", hash.Hex()),
+		attribute.Int64("block.number", int64(number)),
+	)
+
+	_, span := otel.Tracer(blockTracerName).Start(ctx, stage, trace.WithTimestamp(start), trace.WithAttributes(attrs...))
+	span.End(trace.WithTimestamp(end))
+}
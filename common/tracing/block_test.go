@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRecordBlockStage(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	otel.SetTracerProvider(provider)
+
+	var (
+		hash  = common.HexToHash("0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20")
+		start = time.Now()
+		end   = start.Add(time.Second)
+	)
+
+	// Nothing is recorded until block tracing is enabled
+	RecordBlockStage(BlockExecute, hash, 10, start, end)
+	require.Empty(t, recorder.Ended())
+
+	EnableBlockTracing()
+
+	RecordBlockStage(BlockExecute, hash, 10, start, end)
+	RecordBlockStage(BlockCommit, hash, 10, end, end)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	require.Equal(t, BlockExecute, spans[0].Name())
+	require.Equal(t, start, spans[0].StartTime())
+	require.Equal(t, end, spans[0].EndTime())
+
+	// The stages of a block share a trace derived from its hash
+	require.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	traceID := spans[0].SpanContext().TraceID()
+	require.Equal(t, hash[:16], traceID[:])
+}
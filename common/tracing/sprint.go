@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Steps of the work done by bor at sprint boundaries, recorded as spans by
+// StartSprintStep.
+const (
+	SprintSpanFetch     = "span-fetch"     // The next span was fetched from heimdall and committed
+	SprintValidatorSet  = "validator-set"  // The validator set of the next sprint was retrieved or applied
+	SprintStateSync     = "state-sync"     // The state-sync events were fetched from heimdall and committed
+	SprintSnapshotStore = "snapshot-store" // The bor snapshot was stored to the database
+)
+
+const sprintTracerName = "bor/sprint"
+
+// sprintTraceMarker prefixes the trace ids of sprint boundaries, so they never
+// collide with the traces of blocks derived from block hashes.
+var sprintTraceMarker = [8]byte{'b', 'o', 'r', 's', 'p', 'r', 'n', 't'}
+
+var sprintTracing atomic.Bool
+
+// EnableSprintTracing enables recording the work done at sprint boundaries to
+// the global tracer provider.
+func EnableSprintTracing() {
+	sprintTracing.Store(true)
+}
+
+// SprintTracingEnabled returns whether the work done at sprint boundaries is
+// recorded.
+func SprintTracingEnabled() bool {
+	return sprintTracing.Load()
+}
+
+// sprintSpanContext returns the span context the steps of a sprint boundary
+// are recorded under. Its trace id is derived from the first block of the
+// sprint, so the steps preparing a sprint are grouped into one trace even
+// though they run at different blocks.
+func sprintSpanContext(boundary uint64) trace.SpanContext {
+	var (
+		traceID trace.TraceID
+		spanID  trace.SpanID
+	)
+
+	copy(traceID[:8], sprintTraceMarker[:])
+	binary.BigEndian.PutUint64(traceID[8:], boundary)
+
+	copy(spanID[:], sprintTraceMarker[:])
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+// StartSprintStep starts a span of a step of the work preparing the sprint
+// starting at the given block, returning the function ending it with the
+// outcome of the step. It's a no-op unless sprint tracing is enabled.
+func StartSprintStep(step string, boundary uint64, attrs ...attribute.KeyValue) func(err error) {
+	if !SprintTracingEnabled() {
+		return func(error) {}
+	}
+
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), sprintSpanContext(boundary))
+
+	attrs = append(attrs, attribute.Int64("sprint.start", int64(boundary)))
+
+	_, span := otel.Tracer(sprintTracerName).Start(ctx, step, trace.WithAttributes(attrs...))
+
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}
+}
@@ -0,0 +1,45 @@
+package tracing
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartSprintStep(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	otel.SetTracerProvider(provider)
+
+	// Nothing is recorded until sprint tracing is enabled
+	StartSprintStep(SprintSpanFetch, 64)(nil)
+	require.Empty(t, recorder.Ended())
+
+	EnableSprintTracing()
+
+	StartSprintStep(SprintValidatorSet, 64)(nil)
+	StartSprintStep(SprintStateSync, 64)(errors.New("heimdall unreachable"))
+	StartSprintStep(SprintStateSync, 80)(nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, SprintValidatorSet, spans[0].Name())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+
+	// The steps of a sprint boundary share a trace derived from its first block
+	require.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	require.NotEqual(t, spans[0].SpanContext().TraceID(), spans[2].SpanContext().TraceID())
+
+	traceID := spans[0].SpanContext().TraceID()
+	require.Equal(t, sprintTraceMarker[:], traceID[:8])
+	require.Equal(t, uint64(64), binary.BigEndian.Uint64(traceID[8:]))
+}
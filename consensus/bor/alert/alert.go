@@ -0,0 +1,139 @@
+// Package alert implements a lightweight client posting consensus-critical
+// alerts to an external webhook.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Kind identifies the condition an alert is raised for.
+type Kind string
+
+const (
+	MissedSlot          Kind = "missed-slot"          // the node didn't seal a block in its own slot
+	HeadStalled         Kind = "head-stalled"         // the chain head didn't move for too long
+	HeimdallUnreachable Kind = "heimdall-unreachable" // heimdall couldn't be reached for too long
+	SnapshotDivergence  Kind = "snapshot-divergence"  // the local validator set differs from the header one
+	DoubleSign          Kind = "double-sign"          // a validator sealed two different blocks at the same height
+	ClockSkew           Kind = "clock-skew"           // the local clock is off by more than allowed, sealing is refused
+	BlockAnomaly        Kind = "block-anomaly"        // a block deviates strongly from the profile of its producer
+	SealingStalled      Kind = "sealing-stalled"      // the sealing loop didn't attempt work for too long and was restarted
+	ForkIncompatible    Kind = "fork-incompatible"    // the local fork schedule differs from the network manifest
+	SpanUnhealthy       Kind = "span-unhealthy"       // the producer set of the upcoming span looks unhealthy
+)
+
+const (
+	// requestTimeout is the maximum time a single webhook request may take.
+	requestTimeout = 10 * time.Second
+
+	// defaultCooldown is the minimum time between two alerts of the same kind.
+	defaultCooldown = 10 * time.Minute
+)
+
+// payload is the body posted to the webhook. The `text` field is understood by
+// Slack compatible webhooks, while `summary`, `severity` and `source` follow the
+// PagerDuty event payload.
+type payload struct {
+	Text     string `json:"text"`
+	Summary  string `json:"summary"`
+	Severity string `json:"severity"`
+	Source   string `json:"source"`
+	Kind     Kind   `json:"kind"`
+}
+
+// Client posts alerts to a webhook. Alerts of the same kind are rate limited,
+// and requests are sent in the background so that callers on the consensus
+// path never wait for the webhook.
+//
+// A nil client is valid and discards all alerts.
+type Client struct {
+	url      string
+	source   string
+	cooldown time.Duration
+	client   *http.Client
+
+	last map[Kind]time.Time // Time of the last alert sent per kind
+	lock sync.Mutex
+}
+
+// NewClient creates an alert client posting to the given webhook url, or nil
+// if no url is configured. The source identifies the node in the alerts.
+func NewClient(url string, source string) *Client {
+	if url == "" {
+		return nil
+	}
+
+	return &Client{
+		url:      url,
+		source:   source,
+		cooldown: defaultCooldown,
+		client:   &http.Client{Timeout: requestTimeout},
+		last:     make(map[Kind]time.Time),
+	}
+}
+
+// Notify raises an alert of the given kind, unless an alert of the same kind was
+// raised within the cooldown period.
+func (c *Client) Notify(kind Kind, msg string) {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	if last, ok := c.last[kind]; ok && time.Since(last) < c.cooldown {
+		c.lock.Unlock()
+		return
+	}
+	c.last[kind] = time.Now()
+	c.lock.Unlock()
+
+	go func() {
+		if err := c.send(kind, msg); err != nil {
+			log.Warn("Failed to send consensus alert", "kind", kind, "err", err)
+		}
+	}()
+}
+
+// send posts a single alert to the webhook.
+func (c *Client) send(kind Kind, msg string) error {
+	body, err := json.Marshal(&payload{
+		Text:     fmt.Sprintf("[%s] %s: %s", c.source, kind, msg),
+		Summary:  msg,
+		Severity: "critical",
+		Source:   c.source,
+		Kind:     kind,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("unexpected webhook response status %d", res.StatusCode)
+	}
+
+	return nil
+}
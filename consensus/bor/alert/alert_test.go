@@ -0,0 +1,68 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotify(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan payload, 4)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- p
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "validator-1")
+
+	client.Notify(HeadStalled, "head stalled for 60s")
+
+	select {
+	case p := <-received:
+		require.Equal(t, HeadStalled, p.Kind)
+		require.Equal(t, "validator-1", p.Source)
+		require.Equal(t, "head stalled for 60s", p.Summary)
+		require.Equal(t, "[validator-1] head-stalled: head stalled for 60s", p.Text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("alert not received")
+	}
+
+	// Alerts of the same kind are rate limited, other kinds are not
+	client.Notify(HeadStalled, "head stalled for 120s")
+	client.Notify(MissedSlot, "missed slot")
+
+	select {
+	case p := <-received:
+		require.Equal(t, MissedSlot, p.Kind)
+	case <-time.After(5 * time.Second):
+		t.Fatal("alert not received")
+	}
+
+	select {
+	case p := <-received:
+		t.Fatalf("unexpected alert: %v", p)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNilClient(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient("", "validator-1")
+	require.Nil(t, client)
+
+	// Must not panic
+	client.Notify(MissedSlot, "missed slot")
+}
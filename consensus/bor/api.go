@@ -1,7 +1,10 @@
 package bor
 
 import (
+	"bytes"
+	"context"
 	"encoding/hex"
+	"fmt"
 	"math"
 	"math/big"
 	"sort"
@@ -10,12 +13,14 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/evidence"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
 	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 
-	lru "github.com/hashicorp/golang-lru"
 	"github.com/xsleonard/go-merkle"
 	"golang.org/x/crypto/sha3"
 )
@@ -28,9 +33,8 @@ var (
 // API is a user facing RPC API to allow controlling the signer and voting
 // mechanisms of the proof-of-authority scheme.
 type API struct {
-	chain         consensus.ChainHeaderReader
-	bor           *Bor
-	rootHashCache *lru.ARCCache
+	chain consensus.ChainHeaderReader
+	bor   *Bor
 }
 
 // GetSnapshot retrieves the state snapshot at a given block.
@@ -44,10 +48,12 @@ func (api *API) GetSnapshot(number *rpc.BlockNumber) (*Snapshot, error) {
 	}
 	// Ensure we have an actually valid block and return its snapshot
 	if header == nil {
-		return nil, errUnknownBlock
+		return nil, apiError(errUnknownBlock)
 	}
 
-	return api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	snap, err := api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+
+	return snap, apiError(err)
 }
 
 type BlockSigners struct {
@@ -95,7 +101,7 @@ func (api *API) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOrHash
 	}
 
 	if header == nil {
-		return BlockSigners{}, errUnknownBlock
+		return BlockSigners{}, apiError(errUnknownBlock)
 	}
 
 	snapNumber := rpc.BlockNumber(header.Number.Int64() - 1)
@@ -104,7 +110,7 @@ func (api *API) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOrHash
 	var difficulties = make(map[common.Address]uint64)
 
 	if err != nil {
-		return BlockSigners{}, err
+		return BlockSigners{}, apiError(err)
 	}
 
 	proposer := snap.ValidatorSet.GetProposer().Address
@@ -124,7 +130,7 @@ func (api *API) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOrHash
 
 	author, err := api.GetAuthor(blockNrOrHash)
 	if err != nil {
-		return BlockSigners{}, err
+		return BlockSigners{}, apiError(err)
 	}
 
 	diff := int(difficulties[*author])
@@ -158,14 +164,14 @@ func (api *API) GetSnapshotProposer(blockNrOrHash *rpc.BlockNumberOrHash) (commo
 	}
 
 	if header == nil {
-		return common.Address{}, errUnknownBlock
+		return common.Address{}, apiError(errUnknownBlock)
 	}
 
 	snapNumber := rpc.BlockNumber(header.Number.Int64() - 1)
 	snap, err := api.GetSnapshot(&snapNumber)
 
 	if err != nil {
-		return common.Address{}, err
+		return common.Address{}, apiError(err)
 	}
 
 	return snap.ValidatorSet.GetProposer().Address, nil
@@ -194,22 +200,59 @@ func (api *API) GetAuthor(blockNrOrHash *rpc.BlockNumberOrHash) (*common.Address
 
 	// Ensure we have an actually valid block and return its snapshot
 	if header == nil {
-		return nil, errUnknownBlock
+		return nil, apiError(errUnknownBlock)
 	}
 
 	author, err := api.bor.Author(header)
 
-	return &author, err
+	return &author, apiError(err)
 }
 
 // GetSnapshotAtHash retrieves the state snapshot at a given block.
 func (api *API) GetSnapshotAtHash(hash common.Hash) (*Snapshot, error) {
 	header := api.chain.GetHeaderByHash(hash)
 	if header == nil {
-		return nil, errUnknownBlock
+		return nil, apiError(errUnknownBlock)
 	}
 
-	return api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	snap, err := api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+
+	return snap, apiError(err)
+}
+
+// PurgeSnapshot drops the snapshot of a block along with the ones derived from
+// it, in memory and in the database, returning the number of stored snapshots
+// quarantined. They are rebuilt from the headers when next needed, recovering a
+// node wedged on a corrupt snapshot without a restart.
+func (api *API) PurgeSnapshot(hash common.Hash) (int, error) {
+	header := api.chain.GetHeaderByHash(hash)
+	if header == nil {
+		return 0, apiError(errUnknownBlock)
+	}
+
+	purged, err := api.bor.purgeSnapshots(api.chain, header)
+
+	return purged, apiError(err)
+}
+
+// RebuildSnapshot purges the snapshot of a canonical block along with the ones
+// derived from it, and rebuilds them from the headers right away, returning the
+// rebuilt snapshot of the block.
+func (api *API) RebuildSnapshot(number rpc.BlockNumber) (*Snapshot, error) {
+	var header *types.Header
+	if number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+
+	if header == nil {
+		return nil, apiError(errUnknownBlock)
+	}
+
+	snap, err := api.bor.rebuildSnapshot(api.chain, header)
+
+	return snap, apiError(err)
 }
 
 // GetSigners retrieves the list of authorized signers at the specified block.
@@ -223,13 +266,13 @@ func (api *API) GetSigners(number *rpc.BlockNumber) ([]common.Address, error) {
 	}
 	// Ensure we have an actually valid block and return the signers from its snapshot
 	if header == nil {
-		return nil, errUnknownBlock
+		return nil, apiError(errUnknownBlock)
 	}
 
 	snap, err := api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
 
 	if err != nil {
-		return nil, err
+		return nil, apiError(err)
 	}
 
 	return snap.signers(), nil
@@ -239,23 +282,90 @@ func (api *API) GetSigners(number *rpc.BlockNumber) ([]common.Address, error) {
 func (api *API) GetSignersAtHash(hash common.Hash) ([]common.Address, error) {
 	header := api.chain.GetHeaderByHash(hash)
 	if header == nil {
-		return nil, errUnknownBlock
+		return nil, apiError(errUnknownBlock)
 	}
 
 	snap, err := api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
 
 	if err != nil {
-		return nil, err
+		return nil, apiError(err)
 	}
 
 	return snap.signers(), nil
 }
 
+// SignerStatus is the signing activity of the validators over recent blocks.
+type SignerStatus struct {
+	InturnPercent float64                `json:"inturnPercent"`
+	SigningStatus map[common.Address]int `json:"sealerActivity"`
+	NumBlocks     uint64                 `json:"numBlocks"`
+}
+
+// Status returns the signing activity of the validators over the last 64
+// blocks, in the same shape as clique_status.
+func (api *API) Status() (*SignerStatus, error) {
+	var (
+		numBlocks = uint64(64)
+		header    = api.chain.CurrentHeader()
+		end       = header.Number.Uint64()
+		optimals  = 0
+	)
+
+	snap, err := api.bor.snapshot(api.chain, end, header.Hash(), nil)
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	if numBlocks > end {
+		numBlocks = end
+	}
+
+	signStatus := make(map[common.Address]int)
+	for _, s := range snap.signers() {
+		signStatus[s] = 0
+	}
+
+	for n := end - numBlocks + 1; n <= end; n++ {
+		h := api.chain.GetHeaderByNumber(n)
+		if h == nil {
+			return nil, fmt.Errorf("missing block %d", n)
+		}
+
+		sealer, err := api.bor.Author(h)
+		if err != nil {
+			return nil, apiError(err)
+		}
+
+		signStatus[sealer]++
+
+		// A block is sealed in-turn if its sealer was the proposer at the parent
+		parentSnap, err := api.bor.snapshot(api.chain, n-1, h.ParentHash, nil)
+		if err != nil {
+			return nil, apiError(err)
+		}
+
+		if succession, err := parentSnap.GetSignerSuccessionNumber(sealer); err == nil && succession == 0 {
+			optimals++
+		}
+	}
+
+	status := &SignerStatus{
+		SigningStatus: signStatus,
+		NumBlocks:     numBlocks,
+	}
+
+	if numBlocks > 0 {
+		status.InturnPercent = float64(100*optimals) / float64(numBlocks)
+	}
+
+	return status, nil
+}
+
 // GetCurrentProposer gets the current proposer
 func (api *API) GetCurrentProposer() (common.Address, error) {
 	snap, err := api.GetSnapshot(nil)
 	if err != nil {
-		return common.Address{}, err
+		return common.Address{}, apiError(err)
 	}
 
 	return snap.ValidatorSet.GetProposer().Address, nil
@@ -265,36 +375,555 @@ func (api *API) GetCurrentProposer() (common.Address, error) {
 func (api *API) GetCurrentValidators() ([]*valset.Validator, error) {
 	snap, err := api.GetSnapshot(nil)
 	if err != nil {
-		return make([]*valset.Validator, 0), err
+		return make([]*valset.Validator, 0), apiError(err)
 	}
 
-	return snap.ValidatorSet.Validators, nil
+	// The snapshot is shared with the engine, hand out a copy of the validators
+	return snap.ValidatorSet.Copy().Validators, nil
 }
 
-// GetRootHash returns the merkle root of the start to end block headers
-func (api *API) GetRootHash(start uint64, end uint64) (string, error) {
-	if err := api.initializeRootHashCache(); err != nil {
-		return "", err
+// GetValidatorsAtBlock returns the validators in charge after the given block
+// (or the head if none requested). The set is read from the validator archive
+// of the last sprint end when available, and from the snapshot otherwise.
+func (api *API) GetValidatorsAtBlock(number *rpc.BlockNumber) ([]*valset.Validator, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+
+	if header == nil {
+		return nil, apiError(errUnknownBlock)
 	}
 
-	key := getRootHashKey(start, end)
+	if end, ok := lastSprintEnd(header.Number.Uint64(), api.bor.config.CalculateSprint(header.Number.Uint64())); ok {
+		if sprintEnd := api.chain.GetHeaderByNumber(end); sprintEnd != nil {
+			validators, err := readArchivedValidators(api.bor.db, sprintEnd.Hash(), end)
+			if err != nil {
+				return nil, apiError(err)
+			}
 
-	if root, known := api.rootHashCache.Get(key); known {
-		return root.(string), nil
+			if validators != nil {
+				return validators, nil
+			}
+		}
 	}
 
-	length := end - start + 1
+	snap, err := api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, apiError(err)
+	}
 
-	if length > MaxCheckpointLength {
-		return "", &MaxCheckpointLengthExceededError{start, end}
+	return snap.ValidatorSet.Copy().Validators, nil
+}
+
+// TimedBlock is the canonical block in effect at a point in time.
+type TimedBlock struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+	Time   uint64      `json:"time"`
+}
+
+// TimedValidators is the validator set in effect at a point in time, the one
+// sealing the child of the block in effect at the time.
+type TimedValidators struct {
+	TimedBlock
+	Validators []*valset.Validator `json:"validators"`
+	Proposer   common.Address      `json:"proposer"` // In-turn producer of the child block
+}
+
+// BlockAtTime returns the canonical block in effect at the given unix time, the
+// last one sealed at or before it. The head is in effect at any later time.
+func (api *API) BlockAtTime(timestamp uint64) (*TimedBlock, error) {
+	header, err := blockAtTime(api.chain, timestamp)
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	return &TimedBlock{Number: header.Number.Uint64(), Hash: header.Hash(), Time: header.Time}, nil
+}
+
+// ValidatorsAtTime returns the validator set and the proposer in effect at the
+// given unix time, the ones of the snapshot of the block in effect at the time.
+func (api *API) ValidatorsAtTime(timestamp uint64) (*TimedValidators, error) {
+	header, err := blockAtTime(api.chain, timestamp)
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	snap, err := api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	// The snapshot is shared with the engine, hand out a copy of the validators
+	validators := snap.ValidatorSet.Copy()
+
+	return &TimedValidators{
+		TimedBlock: TimedBlock{Number: header.Number.Uint64(), Hash: header.Hash(), Time: header.Time},
+		Validators: validators.Validators,
+		Proposer:   validators.GetProposer().Address,
+	}, nil
+}
+
+// ProducerSlot is the earliest time a validator may seal a block at.
+type ProducerSlot struct {
+	Signer common.Address `json:"signer"`
+	Rank   int            `json:"rank"`  // Position in the backup producer order, 0 for the in-turn producer
+	Delay  uint64         `json:"delay"` // Seconds after the parent block
+	Time   uint64         `json:"time"`
+}
+
+// GetProducerSlots returns the expected block times of each validator for the
+// child of the given block (or the head if none requested), in producer order.
+func (api *API) GetProducerSlots(number *rpc.BlockNumber) ([]*ProducerSlot, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+
+	if header == nil {
+		return nil, apiError(errUnknownBlock)
 	}
 
+	snap, err := api.bor.snapshot(api.chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	slots := make([]*ProducerSlot, 0, len(snap.ValidatorSet.Validators))
+
+	for _, validator := range snap.ValidatorSet.Validators {
+		time, rank, err := ExpectedBlockTime(snap, header, validator.Address, api.bor.config)
+		if err != nil {
+			return nil, apiError(err)
+		}
+
+		slots = append(slots, &ProducerSlot{
+			Signer: validator.Address,
+			Rank:   rank,
+			Delay:  time - header.Time,
+			Time:   time,
+		})
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Rank < slots[j].Rank })
+
+	return slots, nil
+}
+
+// GetSprintByBlock returns the sprint containing the given block (or the head
+// if none requested), with its span and producer set.
+func (api *API) GetSprintByBlock(number *rpc.BlockNumber) (*Sprint, error) {
+	var blockNumber uint64
+	if number == nil || *number == rpc.LatestBlockNumber {
+		blockNumber = api.chain.CurrentHeader().Number.Uint64()
+	} else if *number < 0 {
+		return nil, apiError(fmt.Errorf("%w: %d", errInvalidBlockNumber, *number))
+	} else {
+		blockNumber = uint64(number.Int64())
+	}
+
+	sprint, err := api.bor.GetSprint(context.Background(), api.chain, blockNumber)
+
+	return sprint, apiError(err)
+}
+
+// GetSpanById returns the producer span with the given id, fetching it from
+// heimdall if it's not stored locally.
+//
+//nolint:revive,stylecheck
+func (api *API) GetSpanById(id uint64) (*span.HeimdallSpan, error) {
+	heimdallSpan, err := api.bor.spanStore.GetSpanById(context.Background(), id)
+
+	return heimdallSpan, apiError(err)
+}
+
+// CheckNextSpan validates the producer set of the span after the one of the
+// head ahead of its boundary, listing the issues found with it.
+func (api *API) CheckNextSpan() (*SpanCheck, error) {
+	check, err := api.bor.CheckNextSpan(context.Background(), api.chain, api.chain.CurrentHeader())
+
+	return check, apiError(err)
+}
+
+// GetDoubleSignEvidence returns the evidence of validators sealing different
+// blocks at the same height, detected within the given block range.
+func (api *API) GetDoubleSignEvidence(from uint64, to uint64) ([]*evidence.DoubleSign, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid block range %d > %d", from, to)
+	}
+
+	return api.bor.doubleSigns.evidence(from, to)
+}
+
+// NonCanonicalHeader is a reorged-out header retained in the database, along
+// with the validator that sealed it.
+type NonCanonicalHeader struct {
+	Hash   common.Hash    `json:"hash"`
+	Signer common.Address `json:"signer"`
+	Header *types.Header  `json:"header"`
+}
+
+// GetNonCanonicalHeaders returns the non-canonical headers retained at the
+// given height, for forensic analysis of reorgs. Unlike the other methods, it
+// never resolves canonical blocks. Non-canonical headers are only retained
+// until pruned (see --bor.noncanonicalretention) or frozen.
+func (api *API) GetNonCanonicalHeaders(number uint64) ([]*NonCanonicalHeader, error) {
+	headers := make([]*NonCanonicalHeader, 0)
+
+	for _, hash := range rawdb.ReadNonCanonicalHashes(api.bor.db, number) {
+		header := rawdb.ReadHeader(api.bor.db, hash, number)
+		if header == nil {
+			continue
+		}
+
+		signer, err := ecrecover(header, api.bor.signatures, api.bor.config)
+		if err != nil {
+			return nil, apiError(err)
+		}
+
+		headers = append(headers, &NonCanonicalHeader{Hash: hash, Signer: signer, Header: header})
+	}
+
+	return headers, nil
+}
+
+// ExportForkIncident exports the fork ending with the given non-canonical
+// block, for checking it in as a regression test of the fork choice. See
+// testdata/forkincidents/README.md.
+func (api *API) ExportForkIncident(hash common.Hash) (*ForkIncident, error) {
+	return api.bor.ExportForkIncident(api.chain, hash)
+}
+
+// maxRecentsPage is the maximum number of recent signers returned by a single
+// GetRecents call.
+const maxRecentsPage = 256
+
+// RecentSigner is the signer of a block in the recents window of a snapshot.
+type RecentSigner struct {
+	Number uint64         `json:"number"`
+	Signer common.Address `json:"signer"`
+}
+
+// RecentsPage is a page of the recents window of a snapshot, along with the
+// signing activity of the validators over the whole window.
+type RecentsPage struct {
+	Number uint64      `json:"number"` // Block of the snapshot
+	Hash   common.Hash `json:"hash"`
+
+	// Limit is the number of blocks a signer is kept in the recents window for,
+	// i.e. the sprint length. Unlike clique, bor never bars a recent signer from
+	// sealing again, the window only backs the spam protection bookkeeping.
+	Limit       uint64 `json:"limit"`
+	WindowStart uint64 `json:"windowStart"` // Oldest block that can be in the window
+
+	Recents []*RecentSigner `json:"recents"`
+	Next    *uint64         `json:"next,omitempty"` // Block to resume paging from, if any
+
+	// Signed is the number of blocks sealed by each validator in the window, and
+	// Idle the validators which didn't seal any, the first suspects when blocks
+	// stop being produced.
+	Signed map[common.Address]int `json:"signed"`
+	Idle   []common.Address       `json:"idle"`
+}
+
+// GetRecents returns the recents window of the snapshot at the given block (or
+// the head if none requested), paged by block number: up to count signers of
+// the blocks from the given one on, with the block to resume from if more.
+func (api *API) GetRecents(number *rpc.BlockNumber, from uint64, count uint64) (*RecentsPage, error) {
+	snap, err := api.GetSnapshot(number)
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	return recentsPage(snap, api.bor.config.CalculateSprint(snap.Number), from, count), nil
+}
+
+// recentsPage builds a page of the recents window of the snapshot, of which
+// the signers are kept for a sprint of the given length.
+func recentsPage(snap *Snapshot, sprint uint64, from uint64, count uint64) *RecentsPage {
+	if count == 0 || count > maxRecentsPage {
+		count = maxRecentsPage
+	}
+
+	page := &RecentsPage{
+		Number:  snap.Number,
+		Hash:    snap.Hash,
+		Limit:   sprint,
+		Recents: make([]*RecentSigner, 0),
+		Signed:  make(map[common.Address]int),
+		Idle:    make([]common.Address, 0),
+	}
+
+	if snap.Number >= sprint {
+		page.WindowStart = snap.Number - sprint + 1
+	}
+
+	numbers := make([]uint64, 0, len(snap.Recents))
+	for number, signer := range snap.Recents {
+		numbers = append(numbers, number)
+		page.Signed[signer]++
+	}
+
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+	for _, number := range numbers {
+		if number < from {
+			continue
+		}
+
+		if uint64(len(page.Recents)) == count {
+			next := number
+			page.Next = &next
+
+			break
+		}
+
+		page.Recents = append(page.Recents, &RecentSigner{Number: number, Signer: snap.Recents[number]})
+	}
+
+	for _, validator := range snap.ValidatorSet.Validators {
+		if _, ok := page.Signed[validator.Address]; !ok {
+			page.Idle = append(page.Idle, validator.Address)
+		}
+	}
+
+	return page
+}
+
+// maxSignerMetricsRange is the maximum number of blocks covered by a single
+// GetSignerMetrics call.
+const maxSignerMetricsRange = 8192
+
+// SignerMetrics is the block production record of a validator over a range of
+// blocks.
+type SignerMetrics struct {
+	Signer common.Address `json:"signer"`
+
+	Produced uint64 `json:"produced"` // Blocks sealed, in turn or as a backup
+	InTurn   uint64 `json:"inTurn"`   // Blocks sealed as the in-turn producer
+	Backup   uint64 `json:"backup"`   // Blocks sealed in place of the in-turn producer
+	Slots    uint64 `json:"slots"`    // Blocks the validator was the in-turn producer of
+	Missed   uint64 `json:"missed"`   // In-turn blocks sealed by a backup instead
+	Sprints  uint64 `json:"sprints"`  // Sprints the validator was the in-turn producer of
+
+	// Uptime is the percentage of the in-turn slots the validator sealed, nil
+	// if it wasn't the in-turn producer of any block in the range
+	Uptime *float64 `json:"uptime,omitempty"`
+}
+
+// SignerMetricsReport is the block production record of the validators over a
+// range of blocks.
+type SignerMetricsReport struct {
+	StartBlock uint64           `json:"startBlock"`
+	EndBlock   uint64           `json:"endBlock"`
+	Signers    []*SignerMetrics `json:"signers"`
+}
+
+// signerMetricsTracker accumulates the block production record of the
+// validators block by block.
+type signerMetricsTracker struct {
+	signers map[common.Address]*SignerMetrics
+}
+
+func newSignerMetricsTracker() *signerMetricsTracker {
+	return &signerMetricsTracker{signers: make(map[common.Address]*SignerMetrics)}
+}
+
+func (t *signerMetricsTracker) signer(address common.Address) *SignerMetrics {
+	metrics, ok := t.signers[address]
+	if !ok {
+		metrics = &SignerMetrics{Signer: address}
+		t.signers[address] = metrics
+	}
+
+	return metrics
+}
+
+// sprint records the start of a sprint of the given validators, or of the
+// range, with the in-turn producer. Validators are reported even if they
+// never sealed a block in the range.
+func (t *signerMetricsTracker) sprint(validators []*valset.Validator, proposer common.Address) {
+	for _, validator := range validators {
+		t.signer(validator.Address)
+	}
+
+	t.signer(proposer).Sprints++
+}
+
+// block records a block sealed by the author while proposer was in turn.
+func (t *signerMetricsTracker) block(proposer common.Address, author common.Address) {
+	t.signer(proposer).Slots++
+
+	sealer := t.signer(author)
+	sealer.Produced++
+
+	if author == proposer {
+		sealer.InTurn++
+		return
+	}
+
+	sealer.Backup++
+	t.signer(proposer).Missed++
+}
+
+// report returns the accumulated records, sorted by signer address.
+func (t *signerMetricsTracker) report() []*SignerMetrics {
+	signers := make([]*SignerMetrics, 0, len(t.signers))
+
+	for _, metrics := range t.signers {
+		if metrics.Slots > 0 {
+			uptime := 100 * float64(metrics.InTurn) / float64(metrics.Slots)
+			metrics.Uptime = &uptime
+		}
+
+		signers = append(signers, metrics)
+	}
+
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(signers[i].Signer[:], signers[j].Signer[:]) < 0
+	})
+
+	return signers
+}
+
+// GetSignerMetrics returns the blocks produced by each validator between the
+// given blocks (inclusive), in turn and as a backup, and the share of their
+// in-turn slots they sealed. The in-turn producer changes only at sprint
+// boundaries, so a snapshot is resolved per sprint rather than per block.
+func (api *API) GetSignerMetrics(startBlock uint64, endBlock uint64) (*SignerMetricsReport, error) {
+	if startBlock == 0 || startBlock > endBlock {
+		return nil, fmt.Errorf("invalid block range %d-%d", startBlock, endBlock)
+	}
+
+	if endBlock-startBlock >= maxSignerMetricsRange {
+		return nil, fmt.Errorf("block range %d-%d too large, %d blocks at most", startBlock, endBlock, maxSignerMetricsRange)
+	}
+
+	var (
+		tracker  = newSignerMetricsTracker()
+		proposer common.Address
+	)
+
+	for number := startBlock; number <= endBlock; number++ {
+		header := api.chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, apiError(errUnknownBlock)
+		}
+
+		if number == startBlock || IsSprintStart(number, api.bor.config.CalculateSprint(number)) {
+			snap, err := api.bor.snapshot(api.chain, number-1, header.ParentHash, nil)
+			if err != nil {
+				return nil, apiError(err)
+			}
+
+			proposer = snap.ValidatorSet.GetProposer().Address
+			tracker.sprint(snap.ValidatorSet.Validators, proposer)
+		}
+
+		author, err := ecrecover(header, api.bor.signatures, api.bor.config)
+		if err != nil {
+			return nil, apiError(err)
+		}
+
+		tracker.block(proposer, author)
+	}
+
+	return &SignerMetricsReport{
+		StartBlock: startBlock,
+		EndBlock:   endBlock,
+		Signers:    tracker.report(),
+	}, nil
+}
+
+// GetProofBundle returns the proof that the target block descends from the
+// given checkpointed block and was sealed by the validators, for exchanges to
+// check offline with VerifyProofBundle before crediting deposits.
+func (api *API) GetProofBundle(checkpointed uint64, target uint64) (*ProofBundle, error) {
+	if checkpointed >= target {
+		return nil, fmt.Errorf("invalid block range %d >= %d", checkpointed, target)
+	}
+
+	if target-checkpointed > maxProofBundleLength {
+		return nil, fmt.Errorf("block range %d-%d too large, %d blocks at most", checkpointed, target, maxProofBundleLength)
+	}
+
+	// Collect the headers backwards from the target, so that they're chained
+	// even if the canonical chain changes meanwhile
+	header := api.chain.GetHeaderByNumber(target)
+	if header == nil {
+		return nil, apiError(errUnknownBlock)
+	}
+
+	headers := make([]*types.Header, target-checkpointed+1)
+	headers[len(headers)-1] = header
+
+	for i := len(headers) - 2; i >= 0; i-- {
+		header = api.chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+		if header == nil {
+			return nil, consensus.ErrUnknownAncestor
+		}
+
+		headers[i] = header
+	}
+
+	snap, err := api.bor.snapshot(api.chain, checkpointed, headers[0].Hash(), nil)
+	if err != nil {
+		return nil, apiError(err)
+	}
+
+	bundle := &ProofBundle{
+		Validators: snap.ValidatorSet.Copy().Validators,
+		Headers:    headers,
+		Signers:    make([]common.Address, 0, len(headers)-1),
+	}
+
+	for _, header := range headers[1:] {
+		signer, err := ecrecover(header, api.bor.signatures, api.bor.config)
+		if err != nil {
+			return nil, apiError(err)
+		}
+
+		bundle.Signers = append(bundle.Signers, signer)
+	}
+
+	return bundle, nil
+}
+
+// GetRootHash returns the merkle root of the start to end block headers, as
+// computed by Heimdall to verify a checkpoint. The roots are cached by the
+// range and the hash of its end block, so a reorg of the range is never
+// answered with a stale root.
+func (api *API) GetRootHash(start uint64, end uint64) (string, error) {
 	currentHeaderNumber := api.chain.CurrentHeader().Number.Uint64()
 
 	if start > end || end > currentHeaderNumber {
 		return "", &valset.InvalidStartEndBlockError{Start: start, End: end, CurrentHeader: currentHeaderNumber}
 	}
 
+	length := end - start + 1
+
+	if length > MaxCheckpointLength {
+		return "", &MaxCheckpointLengthExceededError{start, end}
+	}
+
+	endHeader := api.chain.GetHeaderByNumber(end)
+	if endHeader == nil {
+		return "", apiError(errUnknownBlock)
+	}
+
+	key := getRootHashKey(start, end, endHeader.Hash())
+
+	if api.bor.rootHashes != nil {
+		if root, known := api.bor.rootHashes.Get(key); known {
+			return root.(string), nil
+		}
+	}
+
 	blockHeaders := make([]*types.Header, end-start+1)
 	wg := new(sync.WaitGroup)
 	concurrent := make(chan bool, 20)
@@ -319,7 +948,7 @@ func (api *API) GetRootHash(start uint64, end uint64) (string, error) {
 		blockHeader := blockHeaders[i]
 		// Handle no header case, which is possible if ancient pruning was done
 		if blockHeader == nil {
-			return "", errUnknownBlock
+			return "", apiError(errUnknownBlock)
 		}
 		header := crypto.Keccak256(appendBytes32(
 			blockHeader.Number.Bytes(),
@@ -336,24 +965,18 @@ func (api *API) GetRootHash(start uint64, end uint64) (string, error) {
 
 	tree := merkle.NewTreeWithOpts(merkle.TreeOptions{EnableHashSorting: false, DisableHashLeaves: true})
 	if err := tree.Generate(convert(headers), sha3.NewLegacyKeccak256()); err != nil {
-		return "", err
+		return "", apiError(err)
 	}
 
 	root := hex.EncodeToString(tree.Root().Hash)
-	api.rootHashCache.Add(key, root)
 
-	return root, nil
-}
-
-func (api *API) initializeRootHashCache() error {
-	var err error
-	if api.rootHashCache == nil {
-		api.rootHashCache, err = lru.NewARC(10)
+	if api.bor.rootHashes != nil {
+		api.bor.rootHashes.Add(key, root)
 	}
 
-	return err
+	return root, nil
 }
 
-func getRootHashKey(start uint64, end uint64) string {
-	return strconv.FormatUint(start, 10) + "-" + strconv.FormatUint(end, 10)
+func getRootHashKey(start uint64, end uint64, endHash common.Hash) string {
+	return strconv.FormatUint(start, 10) + "-" + strconv.FormatUint(end, 10) + "-" + endHash.Hex()
 }
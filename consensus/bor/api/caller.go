@@ -3,14 +3,20 @@ package api
 import (
 	"context"
 
-	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/state"
-	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// Caller executes the read-only contract calls of the bor engine (validator
+// set, span and state receiver queries) against the chain state.
+//
 //go:generate mockgen -destination=./caller_mock.go -package=api . Caller
 type Caller interface {
-	Call(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, overrides *ethapi.StateOverride, blockOverrides *ethapi.BlockOverrides) (hexutil.Bytes, error)
-	CallWithState(ctx context.Context, args ethapi.TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash, state *state.StateDB, overrides *ethapi.StateOverride, blockOverrides *ethapi.BlockOverrides) (hexutil.Bytes, error)
+	// Call executes the message on top of the state at the given block.
+	Call(ctx context.Context, msg ethereum.CallMsg, blockNrOrHash *rpc.BlockNumberOrHash) ([]byte, error)
+
+	// CallWithState executes the message on top of the given state, which is
+	// used instead of the state at the given block if not nil.
+	CallWithState(ctx context.Context, msg ethereum.CallMsg, blockNrOrHash *rpc.BlockNumberOrHash, state *state.StateDB) ([]byte, error)
 }
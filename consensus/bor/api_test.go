@@ -0,0 +1,164 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// rootHashChain is a canonical chain of headers indexed by number.
+type rootHashChain struct {
+	headers []*types.Header
+}
+
+func newRootHashChain(length int, txHash common.Hash) *rootHashChain {
+	chain := &rootHashChain{}
+
+	for i := 0; i < length; i++ {
+		chain.headers = append(chain.headers, &types.Header{
+			Number: big.NewInt(int64(i)),
+			Time:   uint64(i) * 2,
+			TxHash: txHash,
+		})
+	}
+
+	return chain
+}
+
+func (c *rootHashChain) Config() *params.ChainConfig { return params.TestChainConfig }
+func (c *rootHashChain) CurrentHeader() *types.Header {
+	return c.headers[len(c.headers)-1]
+}
+func (c *rootHashChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	return c.GetHeaderByNumber(number)
+}
+func (c *rootHashChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+
+	return c.headers[number]
+}
+func (c *rootHashChain) GetHeaderByHash(hash common.Hash) *types.Header { return nil }
+func (c *rootHashChain) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+func TestGetRootHash(t *testing.T) {
+	t.Parallel()
+
+	rootHashes, _ := lru.NewARC(inmemoryRootHashes)
+	b := &Bor{rootHashes: rootHashes}
+
+	chain := newRootHashChain(32, common.Hash{})
+	api := &API{chain: chain, bor: b}
+
+	root, err := api.GetRootHash(1, 16)
+	require.NoError(t, err)
+	require.Len(t, root, 64)
+
+	// Roots are cached, but a reorg of the range yields a fresh root
+	cached, err := api.GetRootHash(1, 16)
+	require.NoError(t, err)
+	require.Equal(t, root, cached)
+
+	api.chain = newRootHashChain(32, common.Hash{0x1})
+
+	reorged, err := api.GetRootHash(1, 16)
+	require.NoError(t, err)
+	require.NotEqual(t, root, reorged)
+
+	// Inverted and future ranges are rejected as invalid
+	var rangeErr *valset.InvalidStartEndBlockError
+
+	_, err = api.GetRootHash(16, 1)
+	require.ErrorAs(t, err, &rangeErr)
+
+	_, err = api.GetRootHash(1, 32)
+	require.ErrorAs(t, err, &rangeErr)
+}
+
+func TestRecentsPage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = common.HexToAddress("0x01")
+		bob   = common.HexToAddress("0x02")
+		carol = common.HexToAddress("0x03")
+	)
+
+	snap := &Snapshot{
+		Number: 20,
+		Hash:   common.HexToHash("0xaa"),
+		ValidatorSet: valset.NewValidatorSet([]*valset.Validator{
+			valset.NewValidator(alice, 10),
+			valset.NewValidator(bob, 10),
+			valset.NewValidator(carol, 10),
+		}),
+		Recents: map[uint64]common.Address{17: alice, 18: bob, 19: alice, 20: bob},
+	}
+
+	page := recentsPage(snap, 16, 0, 3)
+	require.Equal(t, uint64(16), page.Limit)
+	require.Equal(t, uint64(5), page.WindowStart)
+	require.Equal(t, []*RecentSigner{{17, alice}, {18, bob}, {19, alice}}, page.Recents)
+	require.NotNil(t, page.Next)
+	require.Equal(t, uint64(20), *page.Next)
+
+	// The signing activity covers the whole window regardless of the page
+	require.Equal(t, map[common.Address]int{alice: 2, bob: 2}, page.Signed)
+	require.Equal(t, []common.Address{carol}, page.Idle)
+
+	page = recentsPage(snap, 16, *page.Next, 3)
+	require.Equal(t, []*RecentSigner{{20, bob}}, page.Recents)
+	require.Nil(t, page.Next)
+
+	// Early snapshots have a window starting at genesis
+	snap.Number = 3
+	require.Equal(t, uint64(0), recentsPage(snap, 16, 0, 0).WindowStart)
+}
+
+func TestSignerMetricsTracker(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a    = common.Address{0x01}
+		b    = common.Address{0x02}
+		idle = common.Address{0x03}
+
+		validators = []*valset.Validator{
+			valset.NewValidator(a, 10),
+			valset.NewValidator(b, 10),
+			valset.NewValidator(idle, 10),
+		}
+	)
+
+	tracker := newSignerMetricsTracker()
+
+	// a is in turn and misses one of its four blocks, sealed by b instead
+	tracker.sprint(validators, a)
+	tracker.block(a, a)
+	tracker.block(a, b)
+	tracker.block(a, a)
+	tracker.block(a, a)
+
+	// b is in turn and seals all its blocks
+	tracker.sprint(validators, b)
+	tracker.block(b, b)
+	tracker.block(b, b)
+
+	report := tracker.report()
+	require.Len(t, report, 3)
+
+	uptime := func(v float64) *float64 { return &v }
+
+	require.Equal(t, &SignerMetrics{Signer: a, Produced: 3, InTurn: 3, Slots: 4, Missed: 1, Sprints: 1, Uptime: uptime(75)}, report[0])
+	require.Equal(t, &SignerMetrics{Signer: b, Produced: 3, InTurn: 2, Backup: 1, Slots: 2, Sprints: 1, Uptime: uptime(100)}, report[1])
+	require.Equal(t, &SignerMetrics{Signer: idle}, report[2])
+}
@@ -0,0 +1,71 @@
+package bor
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// inmemoryBackupBlocks is the number of recently reported backup blocks kept to
+// report every block once, even if its seal is verified again.
+const inmemoryBackupBlocks = 256
+
+var backupBlocksCounter = metrics.NewRegisteredCounter("bor/blocks/backup", nil)
+
+// BackupBlockEvent is posted for every verified block sealed by a backup
+// producer instead of the in-turn proposer.
+type BackupBlockEvent struct {
+	Number     uint64
+	Hash       common.Hash
+	Signer     common.Address // Backup producer which sealed the block
+	Proposer   common.Address // In-turn proposer which didn't
+	Succession int            // Rank of the signer in the backup producer order
+}
+
+// backupReporter reports the blocks sealed out of turn through a metric,
+// the logs and a feed, so that operators notice a validator being down even
+// though the chain keeps progressing.
+type backupReporter struct {
+	reported *lru.ARCCache
+	feed     event.Feed
+}
+
+func newBackupReporter() *backupReporter {
+	reported, _ := lru.NewARC(inmemoryBackupBlocks)
+	return &backupReporter{reported: reported}
+}
+
+// report reports a block sealed out of turn, unless it was already reported.
+func (r *backupReporter) report(header *types.Header, signer common.Address, proposer common.Address, succession int) {
+	if r == nil {
+		return
+	}
+
+	hash := header.Hash()
+	if r.reported.Contains(hash) {
+		return
+	}
+	r.reported.Add(hash, struct{}{})
+
+	backupBlocksCounter.Inc(1)
+
+	log.Info("Block sealed out of turn", "number", header.Number, "hash", hash, "signer", signer, "proposer", proposer, "succession", succession)
+
+	r.feed.Send(BackupBlockEvent{
+		Number:     header.Number.Uint64(),
+		Hash:       hash,
+		Signer:     signer,
+		Proposer:   proposer,
+		Succession: succession,
+	})
+}
+
+// SubscribeBackupBlocks registers a subscription for the verified blocks sealed
+// by a backup producer instead of the in-turn proposer.
+func (c *Bor) SubscribeBackupBlocks(ch chan<- BackupBlockEvent) event.Subscription {
+	return c.backups.feed.Subscribe(ch)
+}
@@ -0,0 +1,36 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestBackupReporter(t *testing.T) {
+	t.Parallel()
+
+	b := &Bor{backups: newBackupReporter()}
+
+	events := make(chan BackupBlockEvent, 4)
+	sub := b.SubscribeBackupBlocks(events)
+
+	defer sub.Unsubscribe()
+
+	header := &types.Header{Number: big.NewInt(10)}
+	signer, proposer := common.Address{0x01}, common.Address{0x02}
+
+	// Blocks are reported once even if their seal is verified again
+	b.backups.report(header, signer, proposer, 1)
+	b.backups.report(header, signer, proposer, 1)
+
+	require.Equal(t, BackupBlockEvent{Number: 10, Hash: header.Hash(), Signer: signer, Proposer: proposer, Succession: 1}, <-events)
+	require.Empty(t, events)
+
+	// A nil reporter is a no-op
+	var reporter *backupReporter
+	reporter.report(header, signer, proposer, 1)
+}
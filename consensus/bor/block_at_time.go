@@ -0,0 +1,55 @@
+package bor
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var errBeforeGenesis = errors.New("time before the genesis block")
+
+// blockAtTime returns the canonical block in effect at the given time, that is
+// the last one with a timestamp not after it. Timestamps strictly increase
+// along the chain, so the headers are binary searched, and the head is in
+// effect at any time past it.
+func blockAtTime(chain consensus.ChainHeaderReader, timestamp uint64) (*types.Header, error) {
+	head := chain.CurrentHeader()
+	if head == nil {
+		return nil, errUnknownBlock
+	}
+
+	if head.Time <= timestamp {
+		return head, nil
+	}
+
+	var missing error
+
+	// Number of the first block sealed after the time
+	after := sort.Search(int(head.Number.Uint64()), func(i int) bool {
+		header := chain.GetHeaderByNumber(uint64(i))
+		if header == nil {
+			missing = fmt.Errorf("%w: missing canonical header %d", errUnknownBlock, i)
+			return true
+		}
+
+		return header.Time > timestamp
+	})
+
+	if missing != nil {
+		return nil, missing
+	}
+
+	if after == 0 {
+		return nil, errBeforeGenesis
+	}
+
+	header := chain.GetHeaderByNumber(uint64(after - 1))
+	if header == nil {
+		return nil, fmt.Errorf("%w: missing canonical header %d", errUnknownBlock, after-1)
+	}
+
+	return header, nil
+}
@@ -0,0 +1,57 @@
+package bor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBlockAtTime(t *testing.T) {
+	t.Parallel()
+
+	engine, chain := newHealEngine(t, 32)
+	api := &API{chain: chain, bor: engine}
+
+	// Blocks are sealed every 2 seconds from the genesis at time 0
+	for timestamp := uint64(0); timestamp < 64; timestamp++ {
+		block, err := api.BlockAtTime(timestamp)
+		require.NoError(t, err)
+		require.Equal(t, timestamp/2, block.Number, "time %d", timestamp)
+		require.Equal(t, chain.headers[timestamp/2].Hash(), block.Hash)
+	}
+
+	// The head is in effect past its time
+	block, err := api.BlockAtTime(1000)
+	require.NoError(t, err)
+	require.Equal(t, uint64(31), block.Number)
+
+	validators, err := api.ValidatorsAtTime(9)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), validators.Number)
+	require.Len(t, validators.Validators, 1)
+	require.Equal(t, validators.Validators[0].Address, validators.Proposer)
+}
+
+func TestBlockAtTimeOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	chain := newRootHashChain(8, common.Hash{})
+	for _, header := range chain.headers {
+		header.Time += 10
+	}
+
+	_, err := blockAtTime(chain, 9)
+	require.ErrorIs(t, err, errBeforeGenesis)
+
+	header, err := blockAtTime(chain, 10)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), header.Number.Uint64())
+
+	// Headers missing from the canonical chain fail the search
+	chain.headers[3] = nil
+
+	_, err = blockAtTime(chain, 13)
+	require.ErrorIs(t, err, errUnknownBlock)
+}
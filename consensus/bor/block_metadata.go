@@ -0,0 +1,59 @@
+package bor
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockMetadata is the bor context of a block known locally.
+type BlockMetadata struct {
+	Signer *common.Address // Signer of the block, nil for the genesis and unsealed blocks
+	InTurn *bool           // Whether the signer was the in-turn producer, nil if unknown
+	SpanID *uint64         // Id of the span containing the block, nil if unknown
+}
+
+// LocalBlockMetadata returns the bor context of the given block from the
+// snapshots and spans held in memory or on disk. Unlike the bor APIs, it never
+// replays headers, writes snapshots or queries heimdall, so that it's cheap
+// enough to serve with every block: the context which isn't available locally
+// is left unknown.
+func (c *Bor) LocalBlockMetadata(header *types.Header) *BlockMetadata {
+	var (
+		metadata = new(BlockMetadata)
+		number   = header.Number.Uint64()
+	)
+
+	// The pending block isn't sealed yet, and the genesis has no signer
+	if signer, err := c.Author(header); err == nil && number > 0 {
+		metadata.Signer = &signer
+
+		if snap := c.localSnapshot(header.ParentHash); snap != nil {
+			inTurn := snap.ValidatorSet.GetProposer().Address == signer
+			metadata.InTurn = &inTurn
+		}
+	}
+
+	if heimdallSpan, err := c.spanStore.storedSpanByBlock(number); err == nil {
+		metadata.SpanID = &heimdallSpan.ID
+	}
+
+	return metadata
+}
+
+// localSnapshot returns the snapshot at the given block if it's held in memory
+// or on disk, or nil if it would have to be rebuilt from the headers.
+func (c *Bor) localSnapshot(hash common.Hash) *Snapshot {
+	if s, ok := c.recents.Get(hash); ok {
+		return s.(*Snapshot)
+	}
+
+	if latest := c.latestSnap.Load(); latest != nil && latest.Hash == hash {
+		return latest
+	}
+
+	if s, err := loadSnapshot(c.chainConfig, c.config, c.signatures, c.db, hash); err == nil {
+		return s
+	}
+
+	return nil
+}
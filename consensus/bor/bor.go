@@ -17,21 +17,26 @@ import (
 
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/holiman/uint256"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/crypto/sha3"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/tracing"
 	balance_tracing "github.com/ethereum/go-ethereum/core/tracing"
 
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
 	"github.com/ethereum/go-ethereum/consensus/bor/api"
 	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/clock"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
 	"github.com/ethereum/go-ethereum/consensus/bor/statefull"
 	"github.com/ethereum/go-ethereum/consensus/bor/valset"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -44,9 +49,12 @@ import (
 )
 
 const (
-	checkpointInterval = 1024 // Number of blocks after which to save the vote snapshot to the database
+	checkpointInterval = 1024 // Default number of blocks after which to save the vote snapshot to the database
 	inmemorySnapshots  = 128  // Number of recent vote snapshots to keep in memory
-	inmemorySignatures = 4096 // Number of recent block signatures to keep in memory
+	inmemoryUndos      = 1024 // Number of snapshot undo records to keep in memory
+	snapshotUndoDepth  = 64   // Maximum reorg depth resolved by rewinding the latest snapshot
+	inmemorySignatures = 4096 // Default number of recent block signatures to keep in memory
+	inmemoryRootHashes = 64   // Number of recent checkpoint root hashes to keep in memory
 )
 
 // Bor protocol constants.
@@ -56,8 +64,6 @@ var (
 	} // Default number of blocks after which to checkpoint and reset the pending votes
 
 	uncleHash = types.CalcUncleHash(nil) // Always Keccak256(RLP([])) as uncles are meaningless outside of PoW.
-
-	validatorHeaderBytesLength = common.AddressLength + 20 // address + power
 )
 
 // Various error messages to mark blocks invalid. These should be private to
@@ -69,6 +75,10 @@ var (
 	// that is not part of the local blockchain.
 	errUnknownBlock = errors.New("unknown block")
 
+	// errSnapshotCorrupt is returned if a snapshot loaded from the database fails
+	// its integrity check, in which case it's rebuilt from the headers.
+	errSnapshotCorrupt = errors.New("snapshot corrupt")
+
 	// errMissingVanity is returned if a block's extra-data section is shorter than
 	// 32 bytes, which is required to store the signer vanity.
 	errMissingVanity = errors.New("extra-data 32 byte vanity prefix missing")
@@ -85,8 +95,9 @@ var (
 	// invalid list of validators (i.e. non divisible by 40 bytes).
 	errInvalidSpanValidators = errors.New("invalid validator list on sprint end block")
 
-	// errInvalidMixDigest is returned if a block's mix digest is non-zero.
-	errInvalidMixDigest = errors.New("non-zero mix digest")
+	// errInvalidMixDigest is returned if a block's mix digest is non-zero, or
+	// doesn't carry the expected data after the mix digest fork.
+	errInvalidMixDigest = errors.New("invalid mix digest")
 
 	// errInvalidUncleHash is returned if a block contains an non-empty uncle list.
 	errInvalidUncleHash = errors.New("non empty uncle hash")
@@ -104,6 +115,14 @@ var (
 
 	errUncleDetected     = errors.New("uncles not allowed")
 	errUnknownValidators = errors.New("unknown validators")
+
+	// errMissingBLSPublicKey is returned if a validator to commit in the v2 layout
+	// has no BLS public key committed before nor registered in heimdall.
+	errMissingBLSPublicKey = errors.New("missing BLS public key")
+
+	// errInvalidBLSPublicKey is returned if a validator committed in the v2 layout
+	// has no BLS public key, or a different one than committed for it before.
+	errInvalidBLSPublicKey = errors.New("invalid BLS public key")
 )
 
 // SignerFn is a signer callback function to request a header to be signed by a
@@ -115,8 +134,12 @@ func ecrecover(header *types.Header, sigcache *lru.ARCCache, c *params.BorConfig
 	// If the signature's already cached, return that
 	hash := header.Hash()
 	if address, known := sigcache.Get(hash); known {
+		sigcacheHitCounter.Inc(1)
 		return address.(common.Address), nil
 	}
+
+	sigcacheMissCounter.Inc(1)
+
 	// Retrieve the signature from the header extra-data
 	if len(header.Extra) < types.ExtraSealLength {
 		return common.Address{}, errMissingSignature
@@ -188,12 +211,26 @@ func CalcProducerDelay(number uint64, succession int, c *params.BorConfig) uint6
 	}
 
 	if succession > 0 {
-		delay += uint64(succession) * c.CalculateBackupMultiplier(number)
+		delay += c.CalculateBackupDelay(number, succession)
 	}
 
 	return delay
 }
 
+// ExpectedBlockTime returns the earliest timestamp the given signer may seal the
+// child of parent at, along with its rank in the backup producer order. The
+// snapshot must be the one at parent.
+func ExpectedBlockTime(snap *Snapshot, parent *types.Header, signer common.Address, c *params.BorConfig) (uint64, int, error) {
+	number := parent.Number.Uint64() + 1
+
+	rank, err := snap.GetSignerBackoffRank(signer, c.IsBackoffByStake(number))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return parent.Time + CalcProducerDelay(number, rank, c), rank, nil
+}
+
 // BorRLP returns the rlp bytes which needs to be signed for the bor
 // sealing. The RLP to sign consists of the entire header apart from the 65 byte signature
 // contained at the end of the extra data.
@@ -215,7 +252,21 @@ type Bor struct {
 	db          ethdb.Database      // Database to store and retrieve snapshot checkpoints
 
 	recents    *lru.ARCCache // Snapshots for recent block to speed up reorgs
+	undos      *lru.ARCCache // Undo records of recently applied headers to rewind snapshots on reorgs
 	signatures *lru.ARCCache // Signatures of recent blocks to speed up mining
+	rootHashes *lru.ARCCache // Root hashes of recently requested checkpoint ranges
+
+	latestSnap atomic.Pointer[Snapshot] // Snapshot of the most recent block applied, rewound on reorgs
+
+	stateSyncPrefetched atomic.Uint64 // Number of the latest sprint start block whose state-sync events were prefetched
+
+	verifyWorkers int // Number of workers recovering the signers of header batches being verified, 0 for one per CPU
+
+	snapshotInterval uint64 // Number of blocks after which to save the snapshot to the database, 0 for the default
+	sprintSnapshots  bool   // Whether to save the snapshot of every sprint to the database
+	snapshotDiffs    bool   // Whether to save the snapshots between the ones of the interval as diffs, every sprint
+	validatorArchive bool   // Whether to archive the validator set of every sprint to the database
+	verifySpans      bool   // Whether to check the spans served by heimdall against the committed span, reporting the ones failing it
 
 	authorizedSigner atomic.Pointer[signer] // Ethereum address and sign function of the signing key
 
@@ -223,11 +274,23 @@ type Bor struct {
 	spanner                Spanner
 	GenesisContractsClient GenesisContract
 	HeimdallClient         IHeimdallClient
+	stateSyncSource        StateSyncSource // State-sync events committed when running without heimdall, nil if skipped
+	spanStore              *SpanStore      // Spans fetched from heimdall, persisted across restarts
+
+	alerts      *alert.Client       // Webhook client for consensus alerts, nil if disabled
+	doubleSigns *doubleSignDetector // Tracks seals across forks to detect double signing
+	backups     *backupReporter     // Reports the blocks sealed out of turn
+	signGuard   *signGuard          // Records the blocks signed locally to refuse double signing
+	clock       *clock.Checker      // Clock sanity check sealing is refused on, nil if disabled
+	tracer      *consensusTracer    // Run-time switchable trace of consensus decisions
+	mixDigestFn MixDigestFn         // Consensus data carried in the mix digest after the mix digest fork, nil if none
 
 	// The fields below are for testing only
 	fakeDiff      bool // Skip difficulty verifications
 	devFakeAuthor bool
 
+	ctx       context.Context    // Cancelled on Close to abort the heimdall and contract calls in flight
+	cancel    context.CancelFunc // Cancels ctx
 	closeOnce sync.Once
 }
 
@@ -255,7 +318,9 @@ func New(
 	}
 	// Allocate the snapshot caches and create the engine
 	recents, _ := lru.NewARC(inmemorySnapshots)
+	undos, _ := lru.NewARC(inmemoryUndos)
 	signatures, _ := lru.NewARC(inmemorySignatures)
+	rootHashes, _ := lru.NewARC(inmemoryRootHashes)
 
 	c := &Bor{
 		chainConfig:            chainConfig,
@@ -263,13 +328,22 @@ func New(
 		db:                     db,
 		ethAPI:                 ethAPI,
 		recents:                recents,
+		undos:                  undos,
 		signatures:             signatures,
+		rootHashes:             rootHashes,
 		spanner:                spanner,
 		GenesisContractsClient: genesisContracts,
 		HeimdallClient:         heimdallClient,
+		spanStore:              NewSpanStore(db, heimdallClient),
+		doubleSigns:            newDoubleSignDetector(db, borConfig),
+		backups:                newBackupReporter(),
+		signGuard:              newSignGuard(db),
+		tracer:                 newConsensusTracer(),
 		devFakeAuthor:          devFakeAuthor,
 	}
 
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
 	c.authorizedSigner.Store(&signer{
 		common.Address{},
 		func(_ accounts.Account, _ string, i []byte) ([]byte, error) {
@@ -285,6 +359,10 @@ func New(
 		}
 	}
 
+	if db != nil {
+		checkStateSyncIntent(db)
+	}
+
 	return c
 }
 
@@ -296,7 +374,10 @@ func (c *Bor) Author(header *types.Header) (common.Address, error) {
 
 // VerifyHeader checks whether a header conforms to the consensus rules.
 func (c *Bor) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header) error {
-	return c.verifyHeader(chain, header, nil)
+	err := c.verifyHeader(chain, header, nil)
+	c.tracer.record(TraceVerification, header, "verifyHeader", err, nil)
+
+	return err
 }
 
 func (c *Bor) GetSpanner() Spanner {
@@ -314,9 +395,14 @@ func (c *Bor) VerifyHeaders(chain consensus.ChainHeaderReader, headers []*types.
 	abort := make(chan struct{})
 	results := make(chan error, len(headers))
 
+	// Recover the signers concurrently ahead of the verification, which has to
+	// apply the snapshots sequentially
+	go c.recoverSigners(headers, abort)
+
 	go func() {
 		for i, header := range headers {
 			err := c.verifyHeader(chain, header, headers[:i])
+			c.tracer.record(TraceVerification, header, "verifyHeader", err, nil)
 
 			select {
 			case <-abort:
@@ -359,19 +445,32 @@ func (c *Bor) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 		return errExtraValidators
 	}
 
-	if isSprintEnd && signersBytes%validatorHeaderBytesLength != 0 {
-		log.Warn("Invalid validator set", "number", number, "signersBytes", signersBytes)
-		return errInvalidSpanValidators
+	if isSprintEnd {
+		validatorBytes := header.GetValidatorBytes(c.chainConfig)
+
+		if _, err := valset.ParseValidators(validatorBytes); err != nil {
+			log.Warn("Invalid validator set", "number", number, "signersBytes", signersBytes, "err", err)
+			return errInvalidSpanValidators
+		}
+
+		// The validator set must be in the layout of the active fork
+		if version := valset.ValidatorBytesVersion(validatorBytes); version != c.validatorBytesVersion(header.Number) {
+			log.Warn("Invalid validator set layout", "number", number, "version", version)
+			return errInvalidSpanValidators
+		}
 	}
 
-	// Ensure that the mix digest is zero as we don't have fork protection currently
-	if header.MixDigest != (common.Hash{}) {
-		return errInvalidMixDigest
+	// Once strict, the validator set must also be sorted, unique and powered
+	if isSprintEnd && c.config.IsStrictExtra(header.Number) {
+		if _, err := valset.ParseValidatorsStrict(header.GetValidatorBytes(c.chainConfig)); err != nil {
+			log.Warn("Invalid validator set", "number", number, "err", err)
+			return err
+		}
 	}
 
-	// Ensure that the block doesn't contain any uncles which are meaningless in PoA
-	if header.UncleHash != uncleHash {
-		return errInvalidUncleHash
+	// Ensure that the unused Ethereum fields carry their constant values
+	if err := c.verifyUnusedFields(chain, header); err != nil {
+		return err
 	}
 
 	// Ensure that the block's difficulty is meaningful (may not be correct at this point)
@@ -457,6 +556,10 @@ func (c *Bor) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 		return ErrInvalidTimestamp
 	}
 
+	if err := c.verifyMilestoneReference(header, parent); err != nil {
+		return err
+	}
+
 	// Retrieve the snapshot needed to verify this header and cache it
 	snap, err := c.snapshot(chain, number-1, header.ParentHash, parents)
 	if err != nil {
@@ -465,7 +568,9 @@ func (c *Bor) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 
 	// Verify the validator list match the local contract
 	if IsSprintStart(number+1, c.config.CalculateSprint(number)) {
-		newValidators, err := c.spanner.GetCurrentValidatorsByBlockNrOrHash(context.Background(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), number+1)
+		endStep := tracing.StartSprintStep(tracing.SprintValidatorSet, number+1, attribute.String("source", "contract"))
+		newValidators, err := c.spanner.GetCurrentValidatorsByBlockNrOrHash(c.engineCtx(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), number+1)
+		endStep(err)
 
 		if err != nil {
 			return err
@@ -480,29 +585,38 @@ func (c *Bor) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 
 		if len(newValidators) != len(headerVals) {
 			log.Warn("Invalid validator set", "block number", number, "newValidators", newValidators, "headerVals", headerVals)
+			c.alerts.Notify(alert.SnapshotDivergence, fmt.Sprintf("validator set of block %d has %d validators, local snapshot has %d", number, len(headerVals), len(newValidators)))
+
 			return errInvalidSpanValidators
 		}
 
 		for i, val := range newValidators {
 			if !bytes.Equal(val.HeaderBytes(), headerVals[i].HeaderBytes()) {
 				log.Warn("Invalid validator set", "block number", number, "index", i, "local validator", val, "header validator", headerVals[i])
+				c.alerts.Notify(alert.SnapshotDivergence, fmt.Sprintf("validator %d of block %d differs from the local snapshot", i, number))
+
 				return errInvalidSpanValidators
 			}
 		}
+
+		// The contract doesn't know the BLS public keys, they're checked against
+		// the ones committed by the previous sprint end headers instead
+		if c.validatorBytesVersion(header.Number) == valset.ValidatorBytesV2 {
+			if err := verifyBLSPublicKeys(headerVals, snap.ValidatorSet); err != nil {
+				return err
+			}
+		}
 	}
 
 	// verify the validator list in the last sprint block
 	if IsSprintStart(number, c.config.CalculateSprint(number)) {
 		parentValidatorBytes := parent.GetValidatorBytes(c.chainConfig)
-		validatorsBytes := make([]byte, len(snap.ValidatorSet.Validators)*validatorHeaderBytesLength)
 
 		currentValidators := snap.ValidatorSet.Copy().Validators
 		// sort validator by address
 		sort.Sort(valset.ValidatorsByAddress(currentValidators))
 
-		for i, validator := range currentValidators {
-			copy(validatorsBytes[i*validatorHeaderBytesLength:], validator.HeaderBytes())
-		}
+		validatorsBytes := valset.EncodeValidators(currentValidators, c.validatorBytesVersion(parent.Number))
 		// len(header.Extra) >= extraVanity+extraSeal has already been validated in validateHeaderExtraField, so this won't result in a panic
 		if !bytes.Equal(parentValidatorBytes, validatorsBytes) {
 			return &MismatchingValidatorsError{number - 1, validatorsBytes, parentValidatorBytes}
@@ -532,6 +646,7 @@ func (c *Bor) snapshot(chain consensus.ChainHeaderReader, number uint64, hash co
 	var snap *Snapshot
 
 	headers := make([]*types.Header, 0, 16)
+	rewinder := newSnapshotRewinder(c.latestSnap.Load(), c.undos)
 
 	//nolint:govet
 	for snap == nil {
@@ -542,15 +657,34 @@ func (c *Bor) snapshot(chain consensus.ChainHeaderReader, number uint64, hash co
 			break
 		}
 
+		// If the block is a recent ancestor of the latest snapshot (i.e. we're
+		// on a shallow reorg), rewind the latest snapshot to it
+		if s := rewinder.rewind(number, hash); s != nil {
+			log.Trace("Rewound snapshot for reorg", "number", number, "hash", hash)
+
+			snap = s
+
+			break
+		}
+
 		// If an on-disk checkpoint snapshot can be found, use that
-		if number%checkpointInterval == 0 {
-			if s, err := loadSnapshot(c.chainConfig, c.config, c.signatures, c.db, hash); err == nil {
+		if c.persistSnapshot(number) {
+			s, err := loadSnapshot(c.chainConfig, c.config, c.signatures, c.db, hash)
+			if err == nil {
 				log.Trace("Loaded snapshot from disk", "number", number, "hash", hash)
 
 				snap = s
 
 				break
 			}
+
+			if errors.Is(err, errSnapshotCorrupt) {
+				log.Warn("Quarantining corrupt snapshot, rebuilding from headers", "number", number, "hash", hash, "err", err)
+
+				if err := quarantineSnapshot(c.db, hash); err != nil {
+					log.Error("Failed to quarantine corrupt snapshot", "number", number, "hash", hash, "err", err)
+				}
+			}
 		}
 
 		// If we're at the genesis, snapshot the initial state. Alternatively if we're
@@ -567,7 +701,7 @@ func (c *Bor) snapshot(chain consensus.ChainHeaderReader, number uint64, hash co
 				hash := checkpoint.Hash()
 
 				// get validators and current span
-				validators, err := c.spanner.GetCurrentValidatorsByHash(context.Background(), hash, number+1)
+				validators, err := c.spanner.GetCurrentValidatorsByHash(c.engineCtx(), hash, number+1)
 				if err != nil {
 					return nil, err
 				}
@@ -623,9 +757,19 @@ func (c *Bor) snapshot(chain consensus.ChainHeaderReader, number uint64, hash co
 
 	c.recents.Add(snap.Hash, snap)
 
+	// Track the snapshot near the chain head to rewind from on reorgs, ignoring
+	// snapshots of old blocks resolved by RPC queries
+	if latest := c.latestSnap.Load(); latest == nil || snap.Number+snapshotUndoDepth >= latest.Number {
+		c.latestSnap.Store(snap)
+	}
+
 	// If we've generated a new checkpoint snapshot, save to disk
-	if snap.Number%checkpointInterval == 0 && len(headers) > 0 {
-		if err = snap.store(c.db); err != nil {
+	if c.persistSnapshot(snap.Number) && len(headers) > 0 {
+		endStep := tracing.StartSprintStep(tracing.SprintSnapshotStore, snap.Number)
+		err = c.storeSnapshot(snap)
+		endStep(err)
+
+		if err != nil {
 			return nil, err
 		}
 
@@ -675,12 +819,22 @@ func (c *Bor) verifySeal(chain consensus.ChainHeaderReader, header *types.Header
 
 	if !snap.ValidatorSet.HasAddress(signer) {
 		// Check the UnauthorizedSignerError.Error() msg to see why we pass number-1
-		return &UnauthorizedSignerError{number - 1, signer.Bytes()}
+		return newSealError(snap, header, signer, &UnauthorizedSignerError{number - 1, signer.Bytes()})
+	}
+
+	// Any header sealed by a validator counts towards double signing, even if
+	// it fails the remaining checks
+	if ev := c.doubleSigns.observe(header, signer); ev != nil {
+		log.Warn("Detected double signing validator", "number", number, "signer", signer, "first", ev.First.Hash(), "second", ev.Second.Hash())
+
+		if c.alerts != nil {
+			c.alerts.Notify(alert.DoubleSign, fmt.Sprintf("validator %s sealed blocks %s and %s at height %d", signer, ev.First.Hash(), ev.Second.Hash(), number))
+		}
 	}
 
-	succession, err := snap.GetSignerSuccessionNumber(signer)
+	succession, err := snap.GetSignerBackoffRank(signer, c.config.IsBackoffByStake(number))
 	if err != nil {
-		return err
+		return newSealError(snap, header, signer, err)
 	}
 
 	var parent *types.Header
@@ -691,14 +845,34 @@ func (c *Bor) verifySeal(chain consensus.ChainHeaderReader, header *types.Header
 	}
 
 	if IsBlockOnTime(parent, header, number, succession, c.config) {
-		return &BlockTooSoonError{number, succession}
+		return newSealError(snap, header, signer, &BlockTooSoonError{number, succession})
 	}
 
 	// Ensure that the difficulty corresponds to the turn-ness of the signer
 	if !c.fakeDiff {
 		difficulty := Difficulty(snap.ValidatorSet, signer)
 		if header.Difficulty.Uint64() != difficulty {
-			return &WrongDifficultyError{number, difficulty, header.Difficulty.Uint64(), signer.Bytes()}
+			return newSealError(snap, header, signer, &WrongDifficultyError{number, difficulty, header.Difficulty.Uint64(), signer.Bytes()})
+		}
+	}
+
+	if c.tracer.enabled(TraceVerification) {
+		c.tracer.record(TraceVerification, header, "verifySeal", nil, map[string]interface{}{
+			"signer":     signer,
+			"succession": succession,
+			"difficulty": header.Difficulty.Uint64(),
+			"proposer":   snap.ValidatorSet.GetProposer().Address,
+		})
+	}
+
+	if succession > 0 {
+		c.backups.report(header, signer, snap.ValidatorSet.GetProposer().Address, succession)
+	}
+
+	// Raise an alert if another producer sealed the block in our own slot
+	if succession > 0 && c.alerts != nil {
+		if currentSigner := c.authorizedSigner.Load().signer; currentSigner != (common.Address{}) && snap.ValidatorSet.GetProposer().Address == currentSigner {
+			c.alerts.Notify(alert.MissedSlot, fmt.Sprintf("block %d was sealed by %s with succession %d", number, signer, succession))
 		}
 	}
 
@@ -737,24 +911,28 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 
 	// get validator set if number
 	if IsSprintStart(number+1, c.config.CalculateSprint(number)) {
-		newValidators, err := c.spanner.GetCurrentValidatorsByHash(context.Background(), header.ParentHash, number+1)
+		endStep := tracing.StartSprintStep(tracing.SprintValidatorSet, number+1, attribute.String("source", "contract"))
+		newValidators, err := c.spanner.GetCurrentValidatorsByHash(c.engineCtx(), header.ParentHash, number+1)
+		endStep(err)
+
 		if err != nil {
 			return errUnknownValidators
 		}
 
+		if err := c.setBLSPublicKeys(c.engineCtx(), snap.ValidatorSet, newValidators, number); err != nil {
+			return err
+		}
+
 		// sort validator by address
 		sort.Sort(valset.ValidatorsByAddress(newValidators))
 
-		if c.chainConfig.IsCancun(header.Number) {
-			var tempValidatorBytes []byte
-
-			for _, validator := range newValidators {
-				tempValidatorBytes = append(tempValidatorBytes, validator.HeaderBytes()...)
-			}
+		validatorBytes := valset.EncodeValidators(newValidators, c.validatorBytesVersion(header.Number))
 
+		if c.chainConfig.IsCancun(header.Number) {
 			blockExtraData := &types.BlockExtraData{
-				ValidatorBytes: tempValidatorBytes,
-				TxDependency:   nil,
+				ValidatorBytes:  validatorBytes,
+				TxDependency:    nil,
+				MilestoneNumber: c.milestoneReference(chain, header),
 			}
 
 			blockExtraDataBytes, err := rlp.EncodeToBytes(blockExtraData)
@@ -765,14 +943,13 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 
 			header.Extra = append(header.Extra, blockExtraDataBytes...)
 		} else {
-			for _, validator := range newValidators {
-				header.Extra = append(header.Extra, validator.HeaderBytes()...)
-			}
+			header.Extra = append(header.Extra, validatorBytes...)
 		}
 	} else if c.chainConfig.IsCancun(header.Number) {
 		blockExtraData := &types.BlockExtraData{
-			ValidatorBytes: nil,
-			TxDependency:   nil,
+			ValidatorBytes:  nil,
+			TxDependency:    nil,
+			MilestoneNumber: c.milestoneReference(chain, header),
 		}
 
 		blockExtraDataBytes, err := rlp.EncodeToBytes(blockExtraData)
@@ -787,8 +964,10 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 	// add extra seal space
 	header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
 
-	// Mix digest is reserved for now, set to empty
-	header.MixDigest = common.Hash{}
+	// Mix digest is reserved, empty unless repurposed by the mix digest fork
+	if header.MixDigest, err = c.mixDigest(chain, header); err != nil {
+		return err
+	}
 
 	// Ensure the timestamp has the correct delay
 	parent := chain.GetHeader(header.ParentHash, number-1)
@@ -799,7 +978,7 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header) e
 	var succession int
 	// if signer is not empty
 	if currentSigner.signer != (common.Address{}) {
-		succession, err = snap.GetSignerSuccessionNumber(currentSigner.signer)
+		succession, err = snap.GetSignerBackoffRank(currentSigner.signer, c.config.IsBackoffByStake(number))
 		if err != nil {
 			return err
 		}
@@ -830,14 +1009,15 @@ func (c *Bor) Finalize(chain consensus.ChainHeaderReader, header *types.Header,
 		start := time.Now()
 		cx := statefull.ChainContext{Chain: chain, Bor: c}
 		// check and commit span
-		if err := c.checkAndCommitSpan(state, header, cx); err != nil {
+		if err := c.checkAndCommitSpan(c.engineCtx(), state, header, cx); err != nil {
 			log.Error("Error while committing span", "error", err)
 			return
 		}
 
-		if c.HeimdallClient != nil {
-			// commit states
-			stateSyncData, err = c.CommitStates(state, header, cx)
+		// State syncs are skipped when running without heimdall, unless replayed from a source
+		if c.stateSyncs() != nil {
+			// commit states, the block being imported
+			stateSyncData, err = c.commitStates(c.engineCtx(), state, header, cx, true)
 			if err != nil {
 				log.Error("Error while committing states", "error", err)
 				return
@@ -857,8 +1037,36 @@ func (c *Bor) Finalize(chain consensus.ChainHeaderReader, header *types.Header,
 	header.UncleHash = types.CalcUncleHash(nil)
 
 	// Set state sync data to blockchain
+	state.BorStateSyncData = stateSyncData
 	bc := chain.(*core.BlockChain)
 	bc.SetStateSync(stateSyncData)
+
+	c.maybePrefetchStateSync(chain, header, state)
+}
+
+// ApplySystemCalls executes the system calls of the given block on top of the
+// state after its transactions, the way Finalize does: the commit of the next
+// span and of the state-sync events at a sprint start. The tracing hooks set on
+// the state are notified of each system call as a transaction of its own.
+func (c *Bor) ApplySystemCalls(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) error {
+	headerNumber := header.Number.Uint64()
+	if !IsSprintStart(headerNumber, c.config.CalculateSprint(headerNumber)) {
+		return nil
+	}
+
+	cx := statefull.ChainContext{Chain: chain, Bor: c}
+
+	if err := c.checkAndCommitSpan(c.engineCtx(), state, header, cx); err != nil {
+		return fmt.Errorf("committing span: %w", err)
+	}
+
+	if c.stateSyncs() != nil {
+		if _, err := c.CommitStates(c.engineCtx(), state, header, cx); err != nil {
+			return fmt.Errorf("committing states: %w", err)
+		}
+	}
+
+	return nil
 }
 
 func decodeGenesisAlloc(i interface{}) (types.GenesisAlloc, error) {
@@ -916,14 +1124,16 @@ func (c *Bor) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *typ
 		cx := statefull.ChainContext{Chain: chain, Bor: c}
 
 		// check and commit span
-		if err = c.checkAndCommitSpan(state, header, cx); err != nil {
+		if err = c.checkAndCommitSpan(c.engineCtx(), state, header, cx); err != nil {
 			log.Error("Error while committing span", "error", err)
 			return nil, err
 		}
 
-		if c.HeimdallClient != nil {
-			// commit states
-			stateSyncData, err = c.CommitStates(state, header, cx)
+		// State syncs are skipped when running without heimdall, unless replayed from a source
+		if c.stateSyncs() != nil {
+			// commit states, the block being assembled isn't sealed yet, so there's
+			// no block hash to record the intent under
+			stateSyncData, err = c.commitStates(c.engineCtx(), state, header, cx, false)
 			if err != nil {
 				log.Error("Error while committing states", "error", err)
 				return nil, err
@@ -945,7 +1155,10 @@ func (c *Bor) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *typ
 	// Assemble block
 	block := types.NewBlock(header, body, receipts, trie.NewStackTrie(nil))
 
+	c.maybePrefetchStateSync(chain, header, state)
+
 	// set state sync
+	state.BorStateSyncData = stateSyncData
 	bc := chain.(core.BorStateSyncer)
 	bc.SetStateSync(stateSyncData)
 
@@ -960,6 +1173,12 @@ func (c *Bor) Authorize(currentSigner common.Address, signFn SignerFn) {
 		signer: currentSigner,
 		signFn: signFn,
 	})
+
+	// Surface where the signer left off, the sign guard refuses to seal any
+	// other block at that height after a restart
+	if number, sealHash, time, ok := c.signGuard.lastSeal(currentSigner); ok {
+		log.Info("Resuming signer after last attempted seal", "signer", currentSigner, "number", number, "sealhash", sealHash, "time", time)
+	}
 }
 
 // Seal implements consensus.Engine, attempting to create a sealed block using
@@ -977,6 +1196,11 @@ func (c *Bor) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 		return nil
 	}
 
+	// Refuse to seal on a skewed clock, as the block would be produced out of turn
+	if err := c.clock.Err(); err != nil {
+		return err
+	}
+
 	// Don't hold the signer fields for the entire sealing procedure
 	currentSigner := *c.authorizedSigner.Load()
 
@@ -991,19 +1215,31 @@ func (c *Bor) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 		return &UnauthorizedSignerError{number - 1, currentSigner.signer.Bytes()}
 	}
 
-	successionNumber, err := snap.GetSignerSuccessionNumber(currentSigner.signer)
+	successionNumber, err := snap.GetSignerBackoffRank(currentSigner.signer, c.config.IsBackoffByStake(number))
 	if err != nil {
 		return err
 	}
 
+	// Refuse early if we already signed a different block at this height
+	sealHash := SealHash(header, c.config)
+	if err := c.signGuard.check(currentSigner.signer, number, sealHash); err != nil {
+		return err
+	}
+
 	// Sweet, the protocol permits us to sign the block, wait for our time
 	delay := time.Unix(int64(header.Time), 0).Sub(time.Now()) // nolint: gosimple
 	// wiggle was already accounted for in header.Time, this is just for logging
-	wiggle := time.Duration(successionNumber) * time.Duration(c.config.CalculateBackupMultiplier(number)) * time.Second
+	wiggle := time.Duration(c.config.CalculateBackupDelay(number, successionNumber)) * time.Second
+
+	// Record the block as signed before signing it, so that it's never signed
+	// again with different contents. Signing ahead of the slot keeps the round
+	// trip of remote signers off the propagation.
+	if err := c.signGuard.record(currentSigner.signer, number, sealHash, header.Time); err != nil {
+		return err
+	}
 
 	// Sign all the things!
-	err = Sign(currentSigner.signFn, currentSigner.signer, header, c.config)
-	if err != nil {
+	if err := Sign(currentSigner.signFn, currentSigner.signer, header, c.config); err != nil {
 		return err
 	}
 
@@ -1014,6 +1250,13 @@ func (c *Bor) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 		select {
 		case <-stop:
 			log.Debug("Discarding sealing operation for block", "number", number)
+			c.releaseSeal(currentSigner.signer, number, sealHash)
+
+			return
+		case <-c.engineCtx().Done():
+			log.Debug("Discarding sealing operation for block on shutdown", "number", number)
+			c.releaseSeal(currentSigner.signer, number, sealHash)
+
 			return
 		case <-time.After(delay):
 			if wiggle > 0 {
@@ -1034,6 +1277,7 @@ func (c *Bor) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 				"headerDifficulty", header.Difficulty,
 			)
 		}
+
 		select {
 		case results <- block.WithSeal(header):
 		default:
@@ -1044,6 +1288,15 @@ func (c *Bor) Seal(chain consensus.ChainHeaderReader, block *types.Block, result
 	return nil
 }
 
+// releaseSeal forgets the block signed by a discarded sealing operation, whose
+// signature never left the node, so that the miner can seal a block with other
+// contents at the same height, e.g. when resubmitting work.
+func (c *Bor) releaseSeal(signer common.Address, number uint64, sealHash common.Hash) {
+	if err := c.signGuard.release(signer, number, sealHash); err != nil {
+		log.Warn("Failed to release discarded seal", "number", number, "sealhash", sealHash, "err", err)
+	}
+}
+
 func Sign(signFn SignerFn, signer common.Address, header *types.Header, c *params.BorConfig) error {
 	sighash, err := signFn(accounts.Account{Address: signer}, accounts.MimetypeBor, BorRLP(header, c))
 	if err != nil {
@@ -1080,12 +1333,27 @@ func (c *Bor) APIs(chain consensus.ChainHeaderReader) []rpc.API {
 		Version:   "1.0",
 		Service:   &API{chain: chain, bor: c},
 		Public:    false,
+	}, {
+		Namespace: "clique",
+		Version:   "1.0",
+		Service:   &CliqueAPI{api: &API{chain: chain, bor: c}},
+		Public:    false,
+	}, {
+		Namespace: "debug",
+		Version:   "1.0",
+		Service:   &TraceAPI{bor: c},
+		Public:    false,
 	}}
 }
 
-// Close implements consensus.Engine. It's a noop for bor as there are no background threads.
+// Close implements consensus.Engine, aborting the heimdall and contract calls
+// in flight and tearing down the heimdall client.
 func (c *Bor) Close() error {
 	c.closeOnce.Do(func() {
+		if c.cancel != nil {
+			c.cancel()
+		}
+
 		if c.HeimdallClient != nil {
 			c.HeimdallClient.Close()
 		}
@@ -1094,14 +1362,27 @@ func (c *Bor) Close() error {
 	return nil
 }
 
+// engineCtx returns the context of the engine's lifetime, which is cancelled
+// once the engine is closed.
+func (c *Bor) engineCtx() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+
+	return c.ctx
+}
+
 func (c *Bor) checkAndCommitSpan(
+	ctx context.Context,
 	state *state.StateDB,
 	header *types.Header,
 	chain core.ChainContext,
-) error {
-	var ctx = context.Background()
+) (err error) {
 	headerNumber := header.Number.Uint64()
 
+	endStep := tracing.StartSprintStep(tracing.SprintSpanFetch, headerNumber)
+	defer func() { endStep(err) }()
+
 	span, err := c.spanner.GetCurrentSpan(ctx, header.ParentHash)
 	if err != nil {
 		return err
@@ -1143,19 +1424,28 @@ func (c *Bor) FetchAndCommitSpan(
 	var heimdallSpan span.HeimdallSpan
 
 	if c.HeimdallClient == nil {
-		// fixme: move to a new mock or fake and remove c.HeimdallClient completely
-		s, err := c.getNextHeimdallSpanForTest(ctx, newSpanID, header, chain)
+		s, err := c.nextLocalSpan(ctx, newSpanID, header, chain)
 		if err != nil {
 			return err
 		}
 
 		heimdallSpan = *s
 	} else {
-		response, err := c.HeimdallClient.Span(ctx, newSpanID)
+		response, err := c.spanStore.GetSpanById(ctx, newSpanID)
 		if err != nil {
 			return err
 		}
 
+		// The check is a heuristic, not an authentication of the span: a valid
+		// span rotating more of the stake fails it as well, so it's only reported
+		// and never fails the block
+		if c.verifySpans {
+			if err := c.verifyHeimdallSpan(ctx, response, header); err != nil {
+				log.Warn("Committing heimdall span failing verification", "id", newSpanID, "err", err)
+				c.alerts.Notify(alert.SpanUnhealthy, fmt.Sprintf("span %d failed verification: %v", newSpanID, err))
+			}
+		}
+
 		heimdallSpan = *response
 	}
 
@@ -1173,23 +1463,38 @@ func (c *Bor) FetchAndCommitSpan(
 
 // CommitStates commit states
 func (c *Bor) CommitStates(
+	ctx context.Context,
 	state *state.StateDB,
 	header *types.Header,
 	chain statefull.ChainContext,
 ) ([]*types.StateSyncData, error) {
+	return c.commitStates(ctx, state, header, chain, false)
+}
+
+// commitStates commits the state-sync events due in the given sprint start
+// block, writing ahead the intent to commit them if the block is imported.
+func (c *Bor) commitStates(
+	ctx context.Context,
+	state *state.StateDB,
+	header *types.Header,
+	chain statefull.ChainContext,
+	importing bool,
+) (_ []*types.StateSyncData, err error) {
 	fetchStart := time.Now()
 	number := header.Number.Uint64()
 
+	endStep := tracing.StartSprintStep(tracing.SprintStateSync, number)
+	defer func() { endStep(err) }()
+
 	var (
 		lastStateIDBig *big.Int
 		from           uint64
 		to             time.Time
-		err            error
 	)
 
 	if c.config.IsIndore(header.Number) {
 		// Fetch the LastStateId from contract via current state instance
-		lastStateIDBig, err = c.GenesisContractsClient.LastStateId(state.Copy(), number-1, header.ParentHash)
+		lastStateIDBig, err = c.GenesisContractsClient.LastStateId(ctx, state.Copy(), number-1, header.ParentHash)
 		if err != nil {
 			return nil, err
 		}
@@ -1197,7 +1502,7 @@ func (c *Bor) CommitStates(
 		stateSyncDelay := c.config.CalculateStateSyncDelay(number)
 		to = time.Unix(int64(header.Time-stateSyncDelay), 0)
 	} else {
-		lastStateIDBig, err = c.GenesisContractsClient.LastStateId(nil, number-1, header.ParentHash)
+		lastStateIDBig, err = c.GenesisContractsClient.LastStateId(ctx, nil, number-1, header.ParentHash)
 		if err != nil {
 			return nil, err
 		}
@@ -1208,19 +1513,38 @@ func (c *Bor) CommitStates(
 	lastStateID := lastStateIDBig.Uint64()
 	from = lastStateID + 1
 
+	// The contract is the source of truth, but on top of the canonical head it
+	// should agree with the last id committed locally.
+	if persisted := rawdb.ReadBorLastStateSyncID(c.db); persisted != nil && *persisted != lastStateID && rawdb.ReadHeadBlockHash(c.db) == header.ParentHash {
+		log.Warn("Last state-sync id differs from the one committed locally", "number", number, "contract", lastStateID, "local", *persisted)
+	}
+
 	log.Info(
 		"Fetching state updates from Heimdall",
 		"fromID", from,
 		"to", to.Format(time.RFC3339))
 
-	eventRecords, err := c.HeimdallClient.StateSyncEvents(context.Background(), from, to.Unix())
+	eventRecords, err := c.stateSyncs().StateSyncEvents(ctx, from, to.Unix())
 	if err != nil {
 		log.Error("Error occurred when fetching state sync events", "fromID", from, "to", to.Unix(), "err", err)
 	}
 
+	// The overrides apply to the records as served by heimdall, which may be
+	// fewer than the override
 	if c.config.OverrideStateSyncRecords != nil {
 		if val, ok := c.config.OverrideStateSyncRecords[strconv.FormatUint(number, 10)]; ok {
-			eventRecords = eventRecords[0:val]
+			eventRecords = eventRecords[0:min(val, len(eventRecords))]
+		}
+	}
+
+	// Refuse to skip ids if heimdall returned incomplete or out of order results,
+	// the events past a gap are fetched again at the next sprint. Before the fork
+	// the events are committed in the order served, up to the first one out of
+	// sequence.
+	if c.config.IsContiguousStateSync(header.Number) {
+		eventRecords, err = clerk.Contiguous(eventRecords, from)
+		if err != nil {
+			log.Warn("Deferring state-sync events past a gap", "number", number, "fromID", from, "err", err)
 		}
 	}
 
@@ -1229,9 +1553,15 @@ func (c *Bor) CommitStates(
 	totalGas := 0 /// limit on gas for state sync per block
 	chainID := c.chainConfig.ChainID.String()
 	stateSyncs := make([]*types.StateSyncData, 0, len(eventRecords))
+	budget := newStateSyncBudget(c.config.CalculateStateSyncGasLimit(number), c.config.CalculateStateSyncSizeLimit(number))
 
 	var gasUsed uint64
 
+	var intent *rawdb.BorStateSyncIntent
+	if importing {
+		intent = c.beginStateSync(header, eventRecords)
+	}
+
 	for _, eventRecord := range eventRecords {
 		if eventRecord.ID <= lastStateID {
 			continue
@@ -1242,6 +1572,12 @@ func (c *Bor) CommitStates(
 			break
 		}
 
+		// the remaining events are carried over to the next sprint
+		if !budget.fits(len(eventRecord.Data)) {
+			log.Info("State sync budget exhausted, deferring events to the next sprint", "number", number, "gas", budget.gasUsed, "size", budget.size, "nextStateID", eventRecord.ID)
+			break
+		}
+
 		stateData := types.StateSyncData{
 			ID:       eventRecord.ID,
 			Contract: eventRecord.Contract,
@@ -1260,10 +1596,13 @@ func (c *Bor) CommitStates(
 		}
 
 		totalGas += int(gasUsed)
+		budget.add(len(eventRecord.Data), gasUsed)
 
 		lastStateID++
 	}
 
+	c.completeStateSync(intent, len(stateSyncs))
+
 	processTime := time.Since(processStart)
 
 	log.Info("StateSyncData", "gas", totalGas, "number", number, "lastStateID", lastStateID, "total records", len(eventRecords), "fetch time", int(fetchTime.Milliseconds()), "process time", int(processTime.Milliseconds()))
@@ -1282,6 +1621,40 @@ func validateEventRecord(eventRecord *clerk.EventRecordWithTime, number uint64,
 
 func (c *Bor) SetHeimdallClient(h IHeimdallClient) {
 	c.HeimdallClient = h
+	c.spanStore.setHeimdallClient(h)
+}
+
+// SetStateSyncSource sets the source of the state-sync events committed when
+// running without heimdall, e.g. recorded mainnet events replayed into a devnet
+// forked from mainnet state. Heimdall takes precedence if both are set.
+func (c *Bor) SetStateSyncSource(source StateSyncSource) {
+	c.stateSyncSource = source
+}
+
+// stateSyncs returns where the state-sync events are fetched from, nil if state
+// syncs are skipped.
+func (c *Bor) stateSyncs() StateSyncSource {
+	if c.HeimdallClient != nil {
+		return c.HeimdallClient
+	}
+
+	return c.stateSyncSource
+}
+
+// SetEvidenceReporter sets the hook submitting the evidence of double signing
+// validators for slashing.
+func (c *Bor) SetEvidenceReporter(reporter EvidenceReporter) {
+	c.doubleSigns.setReporter(reporter)
+}
+
+// SetClockChecker sets the clock sanity check consulted before sealing.
+func (c *Bor) SetClockChecker(checker *clock.Checker) {
+	c.clock = checker
+}
+
+// SetAlertClient sets the webhook client used to raise consensus alerts.
+func (c *Bor) SetAlertClient(a *alert.Client) {
+	c.alerts = a
 }
 
 func (c *Bor) GetCurrentValidators(ctx context.Context, headerHash common.Hash, blockNumber uint64) ([]*valset.Validator, error) {
@@ -1292,7 +1665,9 @@ func (c *Bor) GetCurrentValidators(ctx context.Context, headerHash common.Hash,
 // Private methods
 //
 
-func (c *Bor) getNextHeimdallSpanForTest(
+// nextLocalSpan generates the span following the current one locally when
+// running without heimdall, with the genesis validators producing every span.
+func (c *Bor) nextLocalSpan(
 	ctx context.Context,
 	newSpanID uint64,
 	header *types.Header,
@@ -1307,8 +1682,13 @@ func (c *Bor) getNextHeimdallSpanForTest(
 
 	// get local chain context object
 	localContext := chain.(statefull.ChainContext)
-	// Retrieve the snapshot needed to verify this header and cache it
-	snap, err := c.snapshot(localContext.Chain, headerNumber-1, header.ParentHash, nil)
+
+	genesis := localContext.Chain.GetHeaderByNumber(0)
+	if genesis == nil {
+		return nil, errUnknownBlock
+	}
+
+	snap, err := c.snapshot(localContext.Chain, 0, genesis.Hash(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1330,7 +1710,7 @@ func (c *Bor) getNextHeimdallSpanForTest(
 
 	heimdallSpan := &span.HeimdallSpan{
 		Span:              *spanBor,
-		ValidatorSet:      *snap.ValidatorSet,
+		ValidatorSet:      *snap.ValidatorSet.Copy(),
 		SelectedProducers: selectedProducers,
 		ChainID:           c.chainConfig.ChainID.String(),
 	}
@@ -1338,25 +1718,35 @@ func (c *Bor) getNextHeimdallSpanForTest(
 	return heimdallSpan, nil
 }
 
-func validatorContains(a []*valset.Validator, x *valset.Validator) (*valset.Validator, bool) {
-	for _, n := range a {
-		if n.Address == x.Address {
-			return n, true
-		}
-	}
+// NextValidatorSet returns the validator set of the sprint following a sprint
+// end header carrying the given validators, sealed under the given set, as the
+// snapshots rotate it. The given set is left alone.
+func NextValidatorSet(current *valset.ValidatorSet, validators []*valset.Validator) *valset.ValidatorSet {
+	next := getUpdatedValidatorSet(current.Copy(), validators)
+	next.IncrementProposerPriority(1)
 
-	return nil, false
+	return next
 }
 
 func getUpdatedValidatorSet(oldValidatorSet *valset.ValidatorSet, newVals []*valset.Validator) *valset.ValidatorSet {
 	v := oldValidatorSet
 	oldVals := v.Validators
 
-	changes := make([]*valset.Validator, 0, len(oldVals))
+	// Index both lists by address, so large sets don't take quadratic time
+	newByAddress := make(map[common.Address]*valset.Validator, len(newVals))
+	for i := len(newVals) - 1; i >= 0; i-- {
+		newByAddress[newVals[i].Address] = newVals[i]
+	}
+
+	changes := make([]*valset.Validator, 0, len(oldVals)+len(newVals))
+	changed := make(map[common.Address]struct{}, len(oldVals)+len(newVals))
 
 	for _, ov := range oldVals {
-		if f, ok := validatorContains(newVals, ov); ok {
+		changed[ov.Address] = struct{}{}
+
+		if f, ok := newByAddress[ov.Address]; ok {
 			ov.VotingPower = f.VotingPower
+			ov.BLSPublicKey = f.BLSPublicKey
 		} else {
 			ov.VotingPower = 0
 		}
@@ -1365,7 +1755,8 @@ func getUpdatedValidatorSet(oldValidatorSet *valset.ValidatorSet, newVals []*val
 	}
 
 	for _, nv := range newVals {
-		if _, ok := validatorContains(changes, nv); !ok {
+		if _, ok := changed[nv.Address]; !ok {
+			changed[nv.Address] = struct{}{}
 			changes = append(changes, nv)
 		}
 	}
@@ -1382,6 +1773,111 @@ func getUpdatedValidatorSet(oldValidatorSet *valset.ValidatorSet, newVals []*val
 	return v
 }
 
+// validatorBytesVersion returns the layout of the validator set in the
+// extra-data of the sprint end header at the given block.
+func (c *Bor) validatorBytesVersion(number *big.Int) byte {
+	if c.config.IsValidatorExtraV2(number) {
+		return valset.ValidatorBytesV2
+	}
+
+	return valset.ValidatorBytesV1
+}
+
+// committedBLSPublicKey returns the BLS public key committed for the validator
+// with the given address by the sprint end headers the given set was applied
+// from, or nil if none was.
+func committedBLSPublicKey(validators *valset.ValidatorSet, address common.Address) []byte {
+	if _, validator := validators.GetByAddress(address); validator != nil && len(validator.BLSPublicKey) == valset.BLSPublicKeyLength {
+		return validator.BLSPublicKey
+	}
+
+	return nil
+}
+
+// verifyBLSPublicKeys checks the BLS public keys of the validators committed in
+// the v2 layout of a sprint end header. Every validator must have a key, and
+// keep the one committed for it before if it's in the current validator set.
+func verifyBLSPublicKeys(validators []*valset.Validator, current *valset.ValidatorSet) error {
+	zero := make([]byte, valset.BLSPublicKeyLength)
+
+	for _, validator := range validators {
+		if len(validator.BLSPublicKey) != valset.BLSPublicKeyLength || bytes.Equal(validator.BLSPublicKey, zero) {
+			return fmt.Errorf("%w: validator %v has none", errInvalidBLSPublicKey, validator.Address)
+		}
+
+		if committed := committedBLSPublicKey(current, validator.Address); committed != nil && !bytes.Equal(committed, validator.BLSPublicKey) {
+			return fmt.Errorf("%w: validator %v has %x, committed %x", errInvalidBLSPublicKey, validator.Address, validator.BLSPublicKey, committed)
+		}
+	}
+
+	return nil
+}
+
+// setBLSPublicKeys sets the BLS public keys of the validators to commit in the
+// sprint end header at the given block. The validators of the current set keep
+// the key committed for them before, the keys of the others are the ones they
+// registered in heimdall for the span they take effect in. The keys are only
+// carried by the v2 layout, so the validators are left alone before its fork.
+func (c *Bor) setBLSPublicKeys(ctx context.Context, current *valset.ValidatorSet, validators []*valset.Validator, number uint64) error {
+	if c.validatorBytesVersion(new(big.Int).SetUint64(number)) != valset.ValidatorBytesV2 {
+		return nil
+	}
+
+	var joining []*valset.Validator
+
+	for _, validator := range validators {
+		if key := committedBLSPublicKey(current, validator.Address); key != nil {
+			validator.BLSPublicKey = common.CopyBytes(key)
+		} else {
+			joining = append(joining, validator)
+		}
+	}
+
+	if len(joining) == 0 {
+		return nil
+	}
+
+	heimdallSpan, err := c.spanStore.GetSpanByBlock(ctx, number+1)
+	if err != nil {
+		return err
+	}
+
+	return setSpanBLSPublicKeys(joining, heimdallSpan)
+}
+
+// setSpanBLSPublicKeys sets the BLS public keys of the validators to the ones
+// registered for them in the given span. Every validator must have one, so that
+// no header commits to a zero key.
+func setSpanBLSPublicKeys(validators []*valset.Validator, heimdallSpan *span.HeimdallSpan) error {
+	keys := make(map[common.Address][]byte, len(heimdallSpan.ValidatorSet.Validators)+len(heimdallSpan.SelectedProducers))
+	for _, validator := range heimdallSpan.ValidatorSet.Validators {
+		keys[validator.Address] = validator.BLSPublicKey
+	}
+
+	for _, producer := range heimdallSpan.SelectedProducers {
+		if len(producer.BLSPublicKey) != 0 {
+			keys[producer.Address] = producer.BLSPublicKey
+		}
+	}
+
+	for _, validator := range validators {
+		key := keys[validator.Address]
+		if len(key) != valset.BLSPublicKeyLength {
+			return fmt.Errorf("%w: validator %v in span %d", errMissingBLSPublicKey, validator.Address, heimdallSpan.ID)
+		}
+
+		validator.BLSPublicKey = common.CopyBytes(key)
+	}
+
+	return nil
+}
+
+// IsSprintStart reports whether the given block starts a sprint of the given
+// length. No block does if the sprint length is zero.
 func IsSprintStart(number, sprint uint64) bool {
+	if sprint == 0 {
+		return false
+	}
+
 	return number%sprint == 0
 }
@@ -1,14 +1,17 @@
 package bor
 
 import (
+	"context"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil" //nolint:typecheck
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -162,3 +165,42 @@ func TestEncodeSigHeaderJaipur(t *testing.T) {
 	hash = SealHash(h, &params.BorConfig{JaipurBlock: big.NewInt(10)})
 	require.Equal(t, hash, hashWithoutBaseFee)
 }
+
+func TestCloseAbortsHeimdallCalls(t *testing.T) {
+	t.Parallel()
+
+	chainConfig := &params.ChainConfig{
+		Bor: &params.BorConfig{
+			Sprint: map[string]uint64{"0": 16},
+		},
+	}
+
+	client := heimdall.NewHeimdallClient("http://localhost:1")
+	b := New(chainConfig, rawdb.NewMemoryDatabase(), nil, nil, client, nil, false)
+
+	ctx := b.engineCtx()
+	require.NoError(t, ctx.Err())
+
+	require.NoError(t, b.Close())
+	require.NoError(t, b.Close(), "closing twice must not fail")
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	// The heimdall calls made on behalf of the closed engine fail right away
+	// instead of being retried
+	start := time.Now()
+	_, err := client.StateSyncEvents(ctx, 1, 0)
+	require.ErrorIs(t, err, heimdall.ErrShutdownDetected)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestIsSprintStart(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsSprintStart(0, 16))
+	require.True(t, IsSprintStart(32, 16))
+	require.False(t, IsSprintStart(33, 16))
+
+	// No block starts a sprint without a sprint length
+	require.False(t, IsSprintStart(0, 0))
+	require.False(t, IsSprintStart(32, 0))
+}
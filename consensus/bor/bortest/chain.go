@@ -0,0 +1,408 @@
+// Package bortest implements a deterministic in-memory chain of headers sealed
+// by simulated validators, verified by a bor engine. It's the bor counterpart
+// of the clique tester: tests seal headers in or out of turn, schedule new
+// validator sets, and check that the snapshots of the engine evolve as the bor
+// rules mandate.
+//
+// The chain carries headers only, there's no state, no transactions and no
+// heimdall. The validator sets are served by a spanner standing in for the
+// validator set contract.
+package bortest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// GenesisTime is the timestamp of the genesis header. It's fixed, so that
+	// the chain is the same on every run, and far enough in the past that the
+	// headers sealed on top aren't in the future.
+	GenesisTime = 1600000000
+
+	// gasLimit is the gas limit of every header.
+	gasLimit = 30000000
+)
+
+// errNotHead is returned if a header inserted into the chain doesn't extend it.
+var errNotHead = errors.New("header doesn't extend the head of the chain")
+
+// DefaultConfig returns the bor configuration of the chains created without
+// one: 2 second blocks, sprints of 4 blocks and a 4 second producer delay.
+func DefaultConfig() *params.BorConfig {
+	return &params.BorConfig{
+		Period:           map[string]uint64{"0": 2},
+		ProducerDelay:    map[string]uint64{"0": 4},
+		Sprint:           map[string]uint64{"0": 4},
+		BackupMultiplier: map[string]uint64{"0": 2},
+	}
+}
+
+// Chain is an in-memory chain of headers sealed by simulated validators. It
+// implements consensus.ChainHeaderReader, and tracks the validator set it
+// expects the snapshots of the engine to have, independently of the engine.
+type Chain struct {
+	t       testing.TB
+	config  *params.ChainConfig
+	engine  *bor.Bor
+	api     *bor.API
+	spanner *spanner
+
+	headers    []*types.Header               // Headers of the chain, indexed by number
+	hashes     map[common.Hash]*types.Header // Headers of the chain by hash
+	tds        []*big.Int                    // Total difficulties of the chain, indexed by number
+	validators *valset.ValidatorSet          // Validator set expected at the head
+	keys       map[common.Address]*Validator // Validators ever scheduled, by address
+	lock       sync.RWMutex
+}
+
+// New creates a chain sealed by the given validators from the genesis on, with
+// the given bor configuration or DefaultConfig if nil. The engine is closed
+// once the test finishes.
+func New(t testing.TB, config *params.BorConfig, validators []*Validator) *Chain {
+	t.Helper()
+
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	genesis := &types.Header{
+		Number:     big.NewInt(0),
+		Time:       GenesisTime,
+		GasLimit:   gasLimit,
+		Difficulty: big.NewInt(1),
+		UncleHash:  types.EmptyUncleHash,
+		Extra:      make([]byte, types.ExtraVanityLength+types.ExtraSealLength),
+	}
+
+	c := &Chain{
+		t:       t,
+		config:  &params.ChainConfig{ChainID: big.NewInt(1337), Bor: config},
+		spanner: newSpanner(validators),
+		headers: []*types.Header{genesis},
+		hashes:  map[common.Hash]*types.Header{genesis.Hash(): genesis},
+		tds:     []*big.Int{genesis.Difficulty},
+		keys:    make(map[common.Address]*Validator),
+	}
+
+	c.engine = bor.New(c.config, rawdb.NewMemoryDatabase(), nil, c.spanner, nil, nil, false)
+	c.api = c.engine.APIs(c)[0].Service.(*bor.API)
+
+	t.Cleanup(func() { c.engine.Close() })
+
+	c.register(validators)
+	c.validators = valset.NewValidatorSet(c.spanner.validatorsAt(1))
+
+	return c
+}
+
+// register records the keys of the given validators.
+func (c *Chain) register(validators []*Validator) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, validator := range validators {
+		c.keys[validator.Address] = validator
+	}
+}
+
+// Engine returns the bor engine verifying the chain.
+func (c *Chain) Engine() *bor.Bor {
+	return c.engine
+}
+
+// SetSpan schedules the validator set taking over from the given block on. It
+// enters the chain with the sprint end header preceding the block.
+func (c *Chain) SetSpan(start uint64, validators []*Validator) {
+	c.register(validators)
+	c.spanner.schedule(start, validators)
+}
+
+// Validators returns a copy of the validator set expected at the head.
+func (c *Chain) Validators() *valset.ValidatorSet {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.validators.Copy()
+}
+
+// Proposer returns the validator expected to seal the next header in turn.
+func (c *Chain) Proposer() *Validator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.keys[c.validators.GetProposer().Address]
+}
+
+// Seal creates the next header on top of the chain sealed by the given signer,
+// timed and weighted according to the signer's turn. The edits are applied to
+// the header before it's sealed, to craft invalid ones.
+func (c *Chain) Seal(signer *Validator, edits ...func(header *types.Header)) *types.Header {
+	c.t.Helper()
+
+	var (
+		parent     = c.CurrentHeader()
+		validators = c.Validators()
+		number     = parent.Number.Uint64() + 1
+		borConfig  = c.config.Bor
+	)
+
+	// Signers outside of the validator set are timed like the proposer
+	snap := &bor.Snapshot{Number: number - 1, Hash: parent.Hash(), ValidatorSet: validators}
+
+	succession, err := snap.GetSignerBackoffRank(signer.Address, borConfig.IsBackoffByStake(number))
+	if err != nil {
+		succession = 0
+	}
+
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Number:     new(big.Int).SetUint64(number),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + bor.CalcProducerDelay(number, succession, borConfig),
+		Difficulty: new(big.Int).SetUint64(bor.Difficulty(validators, signer.Address)),
+		Extra:      make([]byte, types.ExtraVanityLength),
+	}
+
+	if bor.IsSprintStart(number+1, borConfig.CalculateSprint(number)) {
+		version := valset.ValidatorBytesV1
+		if borConfig.IsValidatorExtraV2(header.Number) {
+			version = valset.ValidatorBytesV2
+		}
+
+		validators := c.spanner.validatorsAt(number + 1)
+		if version == valset.ValidatorBytesV2 {
+			c.setBLSPublicKeys(validators)
+		}
+
+		header.Extra = append(header.Extra, valset.EncodeValidators(validators, version)...)
+	}
+
+	header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
+
+	for _, edit := range edits {
+		edit(header)
+	}
+
+	sig, err := crypto.Sign(bor.SealHash(header, borConfig).Bytes(), signer.Key)
+	require.NoError(c.t, err)
+
+	copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sig)
+
+	return header
+}
+
+// setBLSPublicKeys sets the BLS public keys of the given validators to the ones
+// of the simulated validators.
+func (c *Chain) setBLSPublicKeys(validators []*valset.Validator) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	for _, validator := range validators {
+		validator.BLSPublicKey = common.CopyBytes(c.keys[validator.Address].BLSPublicKey)
+	}
+}
+
+// Insert verifies the given headers with the engine and appends them to the
+// chain, stopping at the first header failing verification.
+func (c *Chain) Insert(headers ...*types.Header) error {
+	abort, results := c.engine.VerifyHeaders(c, headers)
+	defer close(abort)
+
+	for _, header := range headers {
+		if err := <-results; err != nil {
+			return fmt.Errorf("block %d: %w", header.Number, err)
+		}
+
+		if err := c.append(header); err != nil {
+			return fmt.Errorf("block %d: %w", header.Number, err)
+		}
+	}
+
+	return nil
+}
+
+// append appends a verified header to the chain, moving the expected validator
+// set on at sprint ends.
+func (c *Chain) append(header *types.Header) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	head := c.headers[len(c.headers)-1]
+	if header.ParentHash != head.Hash() {
+		return errNotHead
+	}
+
+	number := header.Number.Uint64()
+
+	if bor.IsSprintStart(number+1, c.config.Bor.CalculateSprint(number)) {
+		validators, err := valset.ParseValidators(header.GetValidatorBytes(c.config))
+		if err != nil {
+			return err
+		}
+
+		if c.validators, err = nextValidators(c.validators, validators); err != nil {
+			return err
+		}
+	}
+
+	c.headers = append(c.headers, header)
+	c.hashes[header.Hash()] = header
+	c.tds = append(c.tds, new(big.Int).Add(c.tds[number-1], header.Difficulty))
+
+	return nil
+}
+
+// nextValidators returns the validator set following a sprint end header that
+// carries the given validators: the validators missing from the header leave
+// the set, the others take the voting power it carries, and the proposer moves
+// on by one.
+func nextValidators(current *valset.ValidatorSet, validators []*valset.Validator) (*valset.ValidatorSet, error) {
+	next := current.Copy()
+
+	carried := make(map[common.Address]bool, len(validators))
+	changes := make([]*valset.Validator, 0, len(validators)+len(next.Validators))
+
+	for _, validator := range validators {
+		carried[validator.Address] = true
+		changes = append(changes, validator.Copy())
+	}
+
+	for _, validator := range next.Validators {
+		if !carried[validator.Address] {
+			changes = append(changes, valset.NewValidator(validator.Address, 0))
+		}
+	}
+
+	if err := next.UpdateWithChangeSet(changes); err != nil {
+		return nil, err
+	}
+
+	next.IncrementProposerPriority(1)
+
+	return next, nil
+}
+
+// Mine seals n headers on top of the chain by the proposers in turn, failing
+// the test if any is rejected.
+func (c *Chain) Mine(n int) []*types.Header {
+	c.t.Helper()
+
+	headers := make([]*types.Header, n)
+
+	for i := range headers {
+		headers[i] = c.Seal(c.Proposer())
+		require.NoError(c.t, c.Insert(headers[i]))
+	}
+
+	return headers
+}
+
+// Snapshot returns the snapshot of the engine at the head of the chain.
+func (c *Chain) Snapshot() *bor.Snapshot {
+	c.t.Helper()
+
+	snap, err := c.api.GetSnapshot(nil)
+	require.NoError(c.t, err)
+
+	return snap
+}
+
+// CheckSnapshot checks that the snapshot of the engine at the head of the chain
+// has the validator set and proposer the chain expects.
+func (c *Chain) CheckSnapshot() {
+	c.t.Helper()
+
+	var (
+		snap     = c.Snapshot()
+		head     = c.CurrentHeader()
+		expected = c.Validators()
+	)
+
+	require.Equal(c.t, head.Number.Uint64(), snap.Number)
+	require.Equal(c.t, head.Hash(), snap.Hash)
+	require.Equal(c.t, withoutIDs(expected), withoutIDs(snap.ValidatorSet), "validator set of block %d", snap.Number)
+	require.Equal(c.t, expected.GetProposer().Address, snap.ValidatorSet.GetProposer().Address, "proposer of block %d", snap.Number)
+}
+
+// withoutIDs returns the validators of a set without their IDs, which are not
+// carried by the headers.
+func withoutIDs(validators *valset.ValidatorSet) []valset.Validator {
+	result := make([]valset.Validator, len(validators.Validators))
+
+	for i, validator := range validators.Validators {
+		result[i] = *validator
+		result[i].ID = 0
+	}
+
+	return result
+}
+
+// Config implements consensus.ChainHeaderReader.
+func (c *Chain) Config() *params.ChainConfig {
+	return c.config
+}
+
+// CurrentHeader implements consensus.ChainHeaderReader.
+func (c *Chain) CurrentHeader() *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.headers[len(c.headers)-1]
+}
+
+// GetHeader implements consensus.ChainHeaderReader.
+func (c *Chain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header := c.GetHeaderByHash(hash)
+	if header == nil || header.Number.Uint64() != number {
+		return nil
+	}
+
+	return header
+}
+
+// GetHeaderByNumber implements consensus.ChainHeaderReader.
+func (c *Chain) GetHeaderByNumber(number uint64) *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+
+	return c.headers[number]
+}
+
+// GetHeaderByHash implements consensus.ChainHeaderReader.
+func (c *Chain) GetHeaderByHash(hash common.Hash) *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.hashes[hash]
+}
+
+// GetTd implements consensus.ChainHeaderReader.
+func (c *Chain) GetTd(hash common.Hash, number uint64) *big.Int {
+	if c.GetHeader(hash, number) == nil {
+		return nil
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return new(big.Int).Set(c.tds[number])
+}
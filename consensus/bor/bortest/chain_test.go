@@ -0,0 +1,331 @@
+package bortest
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that validators of equal power take turns proposing a sprint each.
+func TestProposerRotation(t *testing.T) {
+	t.Parallel()
+
+	var (
+		validators = NewValidators(4)
+		chain      = New(t, nil, validators)
+	)
+
+	// Align the chain on the first sprint start
+	chain.Mine(3)
+	chain.CheckSnapshot()
+
+	proposers := make(map[common.Address]int)
+
+	for sprint := 0; sprint < 2*len(validators); sprint++ {
+		proposer := chain.Proposer()
+
+		for _, header := range chain.Mine(4) {
+			author, err := chain.Engine().Author(header)
+			require.NoError(t, err)
+			require.Equal(t, proposer.Address, author, "block %d", header.Number)
+			require.Equal(t, uint64(len(validators)), header.Difficulty.Uint64())
+		}
+
+		chain.CheckSnapshot()
+
+		proposers[proposer.Address]++
+	}
+
+	require.Len(t, proposers, len(validators))
+
+	for address, sprints := range proposers {
+		require.Equal(t, 2, sprints, "sprints proposed by %s", address)
+	}
+}
+
+// Tests that a scheduled validator set enters the chain at the sprint end
+// preceding it, and that the new validators propose from then on.
+func TestValidatorSetChange(t *testing.T) {
+	t.Parallel()
+
+	var (
+		validators = NewValidators(5)
+		chain      = New(t, nil, validators[:3])
+	)
+
+	chain.SetSpan(16, validators[2:])
+
+	chain.Mine(14)
+	chain.CheckSnapshot()
+	require.Len(t, chain.Snapshot().ValidatorSet.Validators, 3)
+
+	// The sprint end header carries the next validator set
+	chain.Mine(1)
+	chain.CheckSnapshot()
+
+	snap := chain.Snapshot()
+	require.Len(t, snap.ValidatorSet.Validators, 3)
+
+	for _, validator := range validators[2:] {
+		require.True(t, snap.ValidatorSet.HasAddress(validator.Address))
+	}
+
+	// The new validators keep the chain going
+	proposers := make(map[common.Address]bool)
+
+	for i := 0; i < 6; i++ {
+		proposers[chain.Proposer().Address] = true
+
+		chain.Mine(4)
+		chain.CheckSnapshot()
+	}
+
+	require.False(t, proposers[validators[0].Address])
+	require.False(t, proposers[validators[1].Address])
+	require.Len(t, proposers, 3)
+}
+
+// Tests that validators of higher voting power propose more often.
+func TestProposerWeighting(t *testing.T) {
+	t.Parallel()
+
+	validators := NewValidators(2)
+	validators[0].Power = 30
+
+	chain := New(t, nil, validators)
+	chain.Mine(3)
+
+	proposed := make(map[common.Address]int)
+
+	for sprint := 0; sprint < 8; sprint++ {
+		proposed[chain.Proposer().Address]++
+
+		chain.Mine(4)
+		chain.CheckSnapshot()
+	}
+
+	require.Equal(t, 6, proposed[validators[0].Address])
+	require.Equal(t, 2, proposed[validators[1].Address])
+}
+
+// Tests that the backup validators may seal after their backoff delay, with
+// a lower difficulty than the proposer.
+func TestBackupSealer(t *testing.T) {
+	t.Parallel()
+
+	// The backup seals in the middle of a sprint, not rotating the proposer
+	chain := New(t, nil, NewValidators(3))
+	chain.Mine(1)
+
+	var (
+		parent   = chain.CurrentHeader()
+		proposer = chain.Proposer()
+		backup   *Validator
+	)
+
+	for _, validator := range NewValidators(3) {
+		if validator.Address != proposer.Address {
+			backup = validator
+			break
+		}
+	}
+
+	header := chain.Seal(backup)
+	require.Less(t, header.Difficulty.Uint64(), uint64(3))
+	require.Greater(t, header.Time, parent.Time+2)
+
+	require.NoError(t, chain.Insert(header))
+	chain.CheckSnapshot()
+
+	// The proposer is unaffected by a backup sealing in its turn
+	require.Equal(t, proposer.Address, chain.Proposer().Address)
+}
+
+// Tests that a backup delay schedule switched on at a block doesn't change the
+// delays of the backup validators before it.
+func TestBackupDelaysBeforeSchedule(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultConfig()
+	config.BackupDelays = map[string][]uint64{"100": {10, 20}}
+
+	chain := New(t, config, NewValidators(3))
+	chain.Mine(2)
+
+	var (
+		parent   = chain.CurrentHeader()
+		proposer = chain.Proposer()
+		backup   *Validator
+	)
+
+	for _, validator := range NewValidators(3) {
+		if validator.Address != proposer.Address {
+			backup = validator
+			break
+		}
+	}
+
+	succession, err := chain.Snapshot().GetSignerBackoffRank(backup.Address, false)
+	require.NoError(t, err)
+
+	// The wiggle is still linear in the succession, not the one of the schedule
+	header := chain.Seal(backup, func(header *types.Header) {
+		header.Time = parent.Time + 2 + uint64(succession)*2
+	})
+
+	require.NoError(t, chain.Insert(header))
+	chain.CheckSnapshot()
+}
+
+// Tests that the engine rejects headers sealed out of turn or by outsiders.
+func TestInvalidSeals(t *testing.T) {
+	t.Parallel()
+
+	validators := NewValidators(4)
+
+	chain := New(t, nil, validators[:3])
+	chain.Mine(2)
+
+	var (
+		head     = chain.CurrentHeader()
+		proposer = chain.Proposer()
+	)
+
+	err := chain.Insert(chain.Seal(validators[3]))
+
+	var unauthorized *bor.UnauthorizedSignerError
+
+	require.ErrorAs(t, err, &unauthorized)
+
+	err = chain.Insert(chain.Seal(proposer, func(header *types.Header) {
+		header.Difficulty = big.NewInt(1)
+	}))
+
+	var difficulty *bor.WrongDifficultyError
+
+	require.ErrorAs(t, err, &difficulty)
+
+	for _, validator := range validators[:3] {
+		if validator.Address == proposer.Address {
+			continue
+		}
+
+		err = chain.Insert(chain.Seal(validator, func(header *types.Header) {
+			header.Time = head.Time + 2
+		}))
+
+		var tooSoon *bor.BlockTooSoonError
+
+		require.ErrorAs(t, err, &tooSoon)
+	}
+
+	// None of the rejected headers made it into the chain
+	require.Equal(t, head.Hash(), chain.CurrentHeader().Hash())
+	chain.CheckSnapshot()
+}
+
+// Tests that the BLS public keys committed in the v2 layout are verified against
+// the ones committed before, without heimdall.
+func TestValidatorExtraV2(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultConfig()
+	config.ValidatorExtraV2Block = big.NewInt(0)
+
+	var (
+		validators = NewValidators(4)
+		chain      = New(t, config, validators[:3])
+	)
+
+	// The keys of the first v2 sprint end header are taken as committed
+	chain.Mine(3)
+	chain.CheckSnapshot()
+
+	for _, validator := range validators[:3] {
+		_, committed := chain.Snapshot().ValidatorSet.GetByAddress(validator.Address)
+		require.Equal(t, validator.BLSPublicKey, committed.BLSPublicKey)
+	}
+
+	// A joining validator commits its own key
+	chain.SetSpan(12, validators[1:])
+	chain.Mine(8)
+	chain.CheckSnapshot()
+
+	_, joined := chain.Snapshot().ValidatorSet.GetByAddress(validators[3].Address)
+	require.Equal(t, validators[3].BLSPublicKey, joined.BLSPublicKey)
+
+	// The validators already in the set can't change their key, nor drop it
+	chain.Mine(3)
+
+	var (
+		head     = chain.CurrentHeader()
+		proposer = chain.Proposer()
+	)
+
+	rotated := validators[1].BLSPublicKey
+	validators[1].BLSPublicKey = bytes.Repeat([]byte{0xff}, valset.BLSPublicKeyLength)
+	require.ErrorContains(t, chain.Insert(chain.Seal(proposer)), "invalid BLS public key")
+
+	validators[1].BLSPublicKey = make([]byte, valset.BLSPublicKeyLength)
+	require.ErrorContains(t, chain.Insert(chain.Seal(proposer)), "invalid BLS public key")
+
+	require.Equal(t, head.Hash(), chain.CurrentHeader().Hash())
+
+	validators[1].BLSPublicKey = rotated
+	chain.Mine(1)
+	chain.CheckSnapshot()
+}
+
+// Tests that the metadata of blocks is served from the snapshots held locally,
+// leaving out the turn of the blocks whose snapshot would have to be rebuilt.
+func TestLocalBlockMetadata(t *testing.T) {
+	t.Parallel()
+
+	chain := New(t, nil, NewValidators(3))
+	chain.Mine(2)
+
+	// The genesis has no signer
+	metadata := chain.Engine().LocalBlockMetadata(chain.GetHeaderByNumber(0))
+	require.Nil(t, metadata.Signer)
+	require.Nil(t, metadata.InTurn)
+
+	var (
+		proposer = chain.Proposer()
+		backup   *Validator
+	)
+
+	for _, validator := range NewValidators(3) {
+		if validator.Address != proposer.Address {
+			backup = validator
+			break
+		}
+	}
+
+	// The snapshot at the head is only built once its child is verified
+	header := chain.Seal(backup)
+
+	metadata = chain.Engine().LocalBlockMetadata(header)
+	require.Equal(t, &backup.Address, metadata.Signer)
+	require.Nil(t, metadata.InTurn)
+	require.Nil(t, metadata.SpanID, "no span is stored without heimdall")
+
+	require.NoError(t, chain.Insert(header))
+
+	metadata = chain.Engine().LocalBlockMetadata(header)
+	require.NotNil(t, metadata.InTurn)
+	require.False(t, *metadata.InTurn)
+
+	header = chain.Seal(chain.Proposer())
+	require.NoError(t, chain.Insert(header))
+
+	metadata = chain.Engine().LocalBlockMetadata(header)
+	require.NotNil(t, metadata.InTurn)
+	require.True(t, *metadata.InTurn)
+}
@@ -0,0 +1,131 @@
+package bortest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Validator is a simulated validator sealing the headers of a Chain.
+type Validator struct {
+	Key          *ecdsa.PrivateKey
+	Address      common.Address
+	ID           uint64
+	Power        int64
+	BLSPublicKey []byte // Committed along with the validator in the v2 layout
+}
+
+// NewValidators creates n validators of equal voting power. The keys are
+// derived from the index of the validator, so they are the same on every run.
+// The BLS public keys are placeholders, nothing is signed with them.
+func NewValidators(n int) []*Validator {
+	validators := make([]*Validator, n)
+
+	for i := range validators {
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("bortest validator %d", i))))
+		if err != nil {
+			panic(err)
+		}
+
+		validators[i] = &Validator{
+			Key:     key,
+			Address: crypto.PubkeyToAddress(key.PublicKey),
+			ID:      uint64(i + 1),
+			Power:   10,
+
+			BLSPublicKey: bytes.Repeat([]byte{byte(i + 1)}, valset.BLSPublicKeyLength),
+		}
+	}
+
+	return validators
+}
+
+// validator returns the validator as a member of a validator set, as served by
+// the validator set contract which doesn't know the BLS public keys.
+func (v *Validator) validator() *valset.Validator {
+	return &valset.Validator{
+		ID:          v.ID,
+		Address:     v.Address,
+		VotingPower: v.Power,
+	}
+}
+
+// scheduledSpan is a validator set taking over from a given block on.
+type scheduledSpan struct {
+	start      uint64
+	validators []*Validator
+}
+
+// spanner is a bor.Spanner serving the validator sets scheduled on a Chain, in
+// place of the validator set contract.
+type spanner struct {
+	spans []*scheduledSpan // Scheduled validator sets, sorted by first block
+	lock  sync.RWMutex
+}
+
+// newSpanner creates a spanner serving the given validators from the genesis
+// block on.
+func newSpanner(validators []*Validator) *spanner {
+	return &spanner{spans: []*scheduledSpan{{validators: validators}}}
+}
+
+// schedule replaces the validator set from the given block on.
+func (s *spanner) schedule(start uint64, validators []*Validator) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].start >= start })
+	s.spans = append(s.spans[:i], &scheduledSpan{start: start, validators: validators})
+}
+
+// validatorsAt returns the validator set active at the given block, sorted by
+// address like in the extra-data of the sprint end headers.
+func (s *spanner) validatorsAt(number uint64) []*valset.Validator {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	scheduled := s.spans[sort.Search(len(s.spans), func(i int) bool { return s.spans[i].start > number })-1]
+
+	validators := make([]*valset.Validator, len(scheduled.validators))
+	for i, validator := range scheduled.validators {
+		validators[i] = validator.validator()
+	}
+
+	sort.Sort(valset.ValidatorsByAddress(validators))
+
+	return validators
+}
+
+// GetCurrentSpan implements bor.Spanner. The validator sets aren't organized
+// in heimdall spans, so there's only ever the empty span.
+func (s *spanner) GetCurrentSpan(_ context.Context, _ common.Hash) (*span.Span, error) {
+	return &span.Span{}, nil
+}
+
+// GetCurrentValidatorsByHash implements bor.Spanner.
+func (s *spanner) GetCurrentValidatorsByHash(_ context.Context, _ common.Hash, blockNumber uint64) ([]*valset.Validator, error) {
+	return s.validatorsAt(blockNumber), nil
+}
+
+// GetCurrentValidatorsByBlockNrOrHash implements bor.Spanner.
+func (s *spanner) GetCurrentValidatorsByBlockNrOrHash(_ context.Context, _ rpc.BlockNumberOrHash, blockNumber uint64) ([]*valset.Validator, error) {
+	return s.validatorsAt(blockNumber), nil
+}
+
+// CommitSpan implements bor.Spanner. The chain has no state, the validator sets
+// are scheduled with Chain.SetSpan instead.
+func (s *spanner) CommitSpan(_ context.Context, _ span.HeimdallSpan, _ *state.StateDB, _ *types.Header, _ core.ChainContext) error {
+	return nil
+}
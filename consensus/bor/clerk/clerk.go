@@ -2,6 +2,7 @@ package clerk
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -48,3 +49,40 @@ func (e *EventRecordWithTime) BuildEventRecord() *EventRecord {
 		ChainID:  e.ChainID,
 	}
 }
+
+// GapError is returned by Contiguous if the event records skip a state id.
+type GapError struct {
+	Want uint64 // Next state id expected
+	Have uint64 // State id of the record found instead
+}
+
+func (e *GapError) Error() string {
+	return fmt.Sprintf("state-sync event %d missing, next event is %d", e.Want, e.Have)
+}
+
+// Contiguous orders the event records by state id and returns the run of
+// consecutive ids starting at fromID, dropping duplicates and records below
+// fromID. If the records skip an id, the run ends before the gap and a
+// GapError is returned along with it.
+func Contiguous(records []*EventRecordWithTime, fromID uint64) ([]*EventRecordWithTime, error) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+
+	contiguous := make([]*EventRecordWithTime, 0, len(records))
+	next := fromID
+
+	for _, record := range records {
+		switch {
+		case record.ID < next:
+			continue
+		case record.ID > next:
+			return contiguous, &GapError{Want: next, Have: record.ID}
+		}
+
+		contiguous = append(contiguous, record)
+		next++
+	}
+
+	return contiguous, nil
+}
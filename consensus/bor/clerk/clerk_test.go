@@ -0,0 +1,52 @@
+package clerk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func records(ids ...uint64) []*EventRecordWithTime {
+	records := make([]*EventRecordWithTime, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, &EventRecordWithTime{EventRecord: EventRecord{ID: id}})
+	}
+
+	return records
+}
+
+func ids(records []*EventRecordWithTime) []uint64 {
+	ids := make([]uint64, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+
+	return ids
+}
+
+func TestContiguous(t *testing.T) {
+	t.Parallel()
+
+	// Out of order and duplicated records are ordered and deduplicated
+	contiguous, err := Contiguous(records(12, 10, 11, 11, 9, 13), 10)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{10, 11, 12, 13}, ids(contiguous))
+
+	// A gap ends the run instead of skipping ids
+	contiguous, err = Contiguous(records(10, 11, 13, 14), 10)
+	require.Equal(t, []uint64{10, 11}, ids(contiguous))
+
+	var gap *GapError
+	require.ErrorAs(t, err, &gap)
+	require.Equal(t, uint64(12), gap.Want)
+	require.Equal(t, uint64(13), gap.Have)
+
+	// So does a missing first record
+	contiguous, err = Contiguous(records(11, 12), 10)
+	require.Empty(t, contiguous)
+	require.ErrorAs(t, err, &gap)
+
+	contiguous, err = Contiguous(nil, 10)
+	require.NoError(t, err)
+	require.Empty(t, contiguous)
+}
@@ -0,0 +1,29 @@
+package bor
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CliqueAPI serves the read-only part of the clique API from the bor engine,
+// so that existing PoA tooling (e.g. signer-ratio dashboards) works unchanged
+// against bor nodes.
+type CliqueAPI struct {
+	api *API
+}
+
+// GetSigners retrieves the list of authorized signers at the specified block.
+func (api *CliqueAPI) GetSigners(number *rpc.BlockNumber) ([]common.Address, error) {
+	return api.api.GetSigners(number)
+}
+
+// GetSignersAtHash retrieves the list of authorized signers at the specified block.
+func (api *CliqueAPI) GetSignersAtHash(hash common.Hash) ([]common.Address, error) {
+	return api.api.GetSignersAtHash(hash)
+}
+
+// Status returns the status of the last 64 blocks: the number of blocks each
+// signer sealed and the percentage of blocks sealed in-turn.
+func (api *CliqueAPI) Status() (*SignerStatus, error) {
+	return api.api.Status()
+}
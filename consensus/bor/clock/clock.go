@@ -0,0 +1,152 @@
+// Package clock implements a clock sanity check against a set of NTP servers,
+// allowing the block producer to refuse sealing on a skewed local clock.
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// queryTimeout is the maximum time a single NTP query may take.
+	queryTimeout = 5 * time.Second
+
+	// ntpEpochOffset is the offset between the NTP (1900) and Unix (1970) epochs.
+	ntpEpochOffset = 2208988800
+)
+
+var (
+	// DefaultServers are the NTP servers queried if none are configured. They're
+	// operated independently, so that a single faulty source can't skew the result.
+	DefaultServers = []string{"pool.ntp.org", "time.google.com", "time.cloudflare.com"}
+
+	// ErrClockSkew is returned if the local clock is off by more than allowed.
+	ErrClockSkew = errors.New("local clock skew exceeds the allowed bound")
+
+	// errNoServers is returned if none of the NTP servers could be queried.
+	errNoServers = errors.New("no NTP server reachable")
+)
+
+// Checker measures the offset of the local clock against a set of NTP servers,
+// taking the median of the responding ones.
+type Checker struct {
+	servers   []string
+	maxOffset time.Duration
+
+	query func(server string) (time.Duration, error) // Measures the offset against a single server
+
+	offset  time.Duration // Last measured offset, positive if the local clock is ahead
+	checked bool          // Whether an offset was measured at all
+	lock    sync.RWMutex
+}
+
+// NewChecker creates a clock checker, or nil if no bound is configured.
+func NewChecker(servers []string, maxOffset time.Duration) *Checker {
+	if maxOffset <= 0 {
+		return nil
+	}
+
+	if len(servers) == 0 {
+		servers = DefaultServers
+	}
+
+	return &Checker{
+		servers:   servers,
+		maxOffset: maxOffset,
+		query:     sntpOffset,
+	}
+}
+
+// Check measures the clock offset against the configured servers and returns
+// ErrClockSkew if it exceeds the bound. If no server can be reached, the last
+// measurement is kept and the error returned.
+func (c *Checker) Check() (time.Duration, error) {
+	var offsets []time.Duration
+
+	for _, server := range c.servers {
+		offset, err := c.query(server)
+		if err != nil {
+			continue
+		}
+
+		offsets = append(offsets, offset)
+	}
+
+	if len(offsets) == 0 {
+		return 0, errNoServers
+	}
+
+	slices.Sort(offsets)
+	offset := offsets[len(offsets)/2]
+
+	c.lock.Lock()
+	c.offset, c.checked = offset, true
+	c.lock.Unlock()
+
+	return offset, c.Err()
+}
+
+// Err returns ErrClockSkew if the last measured offset exceeds the bound. It is
+// cheap and meant to be consulted before sealing.
+func (c *Checker) Err() error {
+	if c == nil {
+		return nil
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.checked && (c.offset > c.maxOffset || c.offset < -c.maxOffset) {
+		return fmt.Errorf("%w: offset %v, allowed %v", ErrClockSkew, c.offset, c.maxOffset)
+	}
+
+	return nil
+}
+
+// sntpOffset measures the offset of the local clock against a single NTP server
+// using the simple version of NTP, assuming an answer time of RTT/2.
+func sntpOffset(server string) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(server, "123"), queryTimeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(queryTimeout)); err != nil {
+		return 0, err
+	}
+
+	// Construct the time request (empty package with only 2 fields set):
+	//   Bits 3-5: Protocol version, 3
+	//   Bits 6-8: Mode of operation, client, 3
+	request := make([]byte, 48)
+	request[0] = 3<<3 | 3
+
+	sent := time.Now()
+	if _, err := conn.Write(request); err != nil {
+		return 0, err
+	}
+
+	reply := make([]byte, 48)
+	if _, err := conn.Read(reply); err != nil {
+		return 0, err
+	}
+
+	elapsed := time.Since(sent)
+
+	// Reconstruct the transmit time from the reply data
+	sec := uint64(reply[43]) | uint64(reply[42])<<8 | uint64(reply[41])<<16 | uint64(reply[40])<<24
+	frac := uint64(reply[47]) | uint64(reply[46])<<8 | uint64(reply[45])<<16 | uint64(reply[44])<<24
+
+	if sec == 0 {
+		return 0, fmt.Errorf("invalid reply from %s", server)
+	}
+
+	remote := time.Unix(int64(sec-ntpEpochOffset), int64((frac*1e9)>>32))
+
+	return sent.Add(elapsed / 2).Sub(remote), nil
+}
@@ -0,0 +1,52 @@
+package clock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewChecker(nil, 0))
+
+	offsets := map[string]time.Duration{
+		"a": 100 * time.Millisecond,
+		"b": 3 * time.Second,
+		"c": 200 * time.Millisecond,
+	}
+
+	checker := NewChecker([]string{"a", "b", "c", "d"}, time.Second)
+	checker.query = func(server string) (time.Duration, error) {
+		if offset, ok := offsets[server]; ok {
+			return offset, nil
+		}
+
+		return 0, errors.New("unreachable")
+	}
+
+	// No measurement yet, sealing is allowed
+	require.NoError(t, checker.Err())
+
+	// A single faulty server doesn't skew the median
+	offset, err := checker.Check()
+	require.NoError(t, err)
+	require.Equal(t, 200*time.Millisecond, offset)
+
+	// A majority of skewed servers does
+	offsets["a"], offsets["c"] = -2*time.Second, -3*time.Second
+
+	_, err = checker.Check()
+	require.ErrorIs(t, err, ErrClockSkew)
+	require.ErrorIs(t, checker.Err(), ErrClockSkew)
+
+	// Unreachable servers keep the last measurement
+	checker.servers = []string{"d"}
+
+	_, err = checker.Check()
+	require.ErrorIs(t, err, errNoServers)
+	require.ErrorIs(t, checker.Err(), ErrClockSkew)
+}
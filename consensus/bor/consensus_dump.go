@@ -0,0 +1,243 @@
+package bor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// consensusDumpVersion is the version of the consensus state dump format.
+const consensusDumpVersion = 1
+
+var errNoBorChain = errors.New("database without a bor chain")
+
+// ConsensusDumpSummary is what a consensus state dump carries.
+type ConsensusDumpSummary struct {
+	Snapshot        uint64  // Block of the snapshot
+	Spans           int     // Number of spans
+	StateSyncBlocks int     // Number of blocks committing state-sync events
+	StateSyncs      int     // Number of state-sync events
+	LastStateSyncID *uint64 // Id of the last state-sync event committed, if any
+}
+
+// consensusDumpHeader is the first line of a consensus state dump, identifying
+// the chain and the head it was taken at.
+type consensusDumpHeader struct {
+	Version uint64      `json:"version"`
+	Genesis common.Hash `json:"genesis"`
+	Number  uint64      `json:"number"`
+	Hash    common.Hash `json:"hash"`
+}
+
+// blockStateSyncs are the state-sync events committed by a block.
+type blockStateSyncs struct {
+	Number uint64                 `json:"number"`
+	Hash   common.Hash            `json:"hash"`
+	Events []*types.StateSyncData `json:"events"`
+}
+
+// consensusRecord is a line of a consensus state dump, with a single field set.
+type consensusRecord struct {
+	Header          *consensusDumpHeader `json:"header,omitempty"`
+	Snapshot        *Snapshot            `json:"snapshot,omitempty"`
+	Span            *span.HeimdallSpan   `json:"span,omitempty"`
+	StateSyncs      *blockStateSyncs     `json:"stateSyncs,omitempty"`
+	LastStateSyncID *uint64              `json:"lastStateSyncId,omitempty"`
+}
+
+// DumpConsensusState writes the bor consensus state of the canonical chain in
+// the database as one JSON record per line: the latest snapshot stored up to
+// the head, the spans stored, the state-sync events committed from the given
+// block on and the id of the last one. A node holding the same chain takes
+// over from the dumped snapshot without replaying the sprints before it.
+func DumpConsensusState(db ethdb.Database, w io.Writer, from uint64) (*ConsensusDumpSummary, error) {
+	genesis := rawdb.ReadCanonicalHash(db, 0)
+
+	chainConfig := rawdb.ReadChainConfig(db, genesis)
+	if chainConfig == nil || chainConfig.Bor == nil {
+		return nil, errNoBorChain
+	}
+
+	head := rawdb.ReadHeadHeader(db)
+	if head == nil {
+		return nil, errors.New("failed to load head header")
+	}
+
+	var (
+		enc     = json.NewEncoder(w)
+		summary = new(ConsensusDumpSummary)
+	)
+
+	header := &consensusDumpHeader{Version: consensusDumpVersion, Genesis: genesis, Number: head.Number.Uint64(), Hash: head.Hash()}
+	if err := enc.Encode(&consensusRecord{Header: header}); err != nil {
+		return nil, err
+	}
+
+	// Snapshots stored as diffs are dumped in full
+	var snap *Snapshot
+
+	for number := head.Number.Uint64(); ; number-- {
+		hash := rawdb.ReadCanonicalHash(db, number)
+
+		if ok, _ := db.Has(snapshotKey(hash)); ok {
+			var err error
+			if snap, err = loadSnapshot(chainConfig, chainConfig.Bor, nil, db, hash); err != nil {
+				return nil, fmt.Errorf("snapshot of block %d: %w", number, err)
+			}
+
+			break
+		}
+
+		if number == 0 {
+			return nil, errors.New("no snapshot stored on the canonical chain")
+		}
+	}
+
+	if err := enc.Encode(&consensusRecord{Snapshot: snap}); err != nil {
+		return nil, err
+	}
+
+	summary.Snapshot = snap.Number
+
+	it := db.NewIterator(spanPrefix, nil)
+	defer it.Release()
+
+	for it.Next() {
+		if len(it.Key()) != len(spanPrefix)+8 {
+			continue
+		}
+
+		heimdallSpan, err := decodeSpan(it.Value())
+		if err != nil {
+			return nil, fmt.Errorf("span %x: %w", it.Key()[len(spanPrefix):], err)
+		}
+
+		if err := enc.Encode(&consensusRecord{Span: heimdallSpan}); err != nil {
+			return nil, err
+		}
+
+		summary.Spans++
+	}
+
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+
+	for number := from; number <= head.Number.Uint64(); number++ {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if !rawdb.HasBorStateSyncEvents(db, hash, number) {
+			continue
+		}
+
+		events := rawdb.ReadBorStateSyncEvents(db, hash, number)
+		if err := enc.Encode(&consensusRecord{StateSyncs: &blockStateSyncs{Number: number, Hash: hash, Events: events}}); err != nil {
+			return nil, err
+		}
+
+		summary.StateSyncBlocks++
+		summary.StateSyncs += len(events)
+	}
+
+	if id := rawdb.ReadBorLastStateSyncID(db); id != nil {
+		if err := enc.Encode(&consensusRecord{LastStateSyncID: id}); err != nil {
+			return nil, err
+		}
+
+		summary.LastStateSyncID = id
+	}
+
+	return summary, nil
+}
+
+// LoadConsensusState stores the bor consensus state dumped by
+// DumpConsensusState into the database, which must hold the same chain, or at
+// least its genesis block if the chain is imported afterwards.
+func LoadConsensusState(db ethdb.Database, r io.Reader) (*ConsensusDumpSummary, error) {
+	var (
+		dec     = json.NewDecoder(r)
+		spans   = NewSpanStore(db, nil)
+		summary = new(ConsensusDumpSummary)
+		header  *consensusDumpHeader
+	)
+
+	for line := 1; ; line++ {
+		record := new(consensusRecord)
+
+		if err := dec.Decode(record); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+
+		if header == nil && record.Header == nil {
+			return nil, errors.New("consensus state dump without header")
+		}
+
+		switch {
+		case record.Header != nil:
+			if header != nil {
+				return nil, fmt.Errorf("record %d: duplicate header", line)
+			}
+
+			if record.Header.Version != consensusDumpVersion {
+				return nil, fmt.Errorf("unsupported consensus state dump version %d", record.Header.Version)
+			}
+
+			if genesis := rawdb.ReadCanonicalHash(db, 0); genesis != record.Header.Genesis {
+				return nil, fmt.Errorf("consensus state of genesis %x, database of genesis %x", record.Header.Genesis, genesis)
+			}
+
+			header = record.Header
+
+		case record.Snapshot != nil:
+			snap := record.Snapshot
+			if snap.ValidatorSet == nil {
+				return nil, fmt.Errorf("record %d: snapshot without validators", line)
+			}
+
+			if canonical := rawdb.ReadCanonicalHash(db, snap.Number); canonical != (common.Hash{}) && canonical != snap.Hash {
+				return nil, fmt.Errorf("record %d: snapshot of block %d not on the canonical chain", line, snap.Number)
+			}
+
+			if err := snap.store(db); err != nil {
+				return nil, err
+			}
+
+			summary.Snapshot = snap.Number
+
+		case record.Span != nil:
+			if err := spans.store(record.Span); err != nil {
+				return nil, err
+			}
+
+			summary.Spans++
+
+		case record.StateSyncs != nil:
+			syncs := record.StateSyncs
+
+			rawdb.WriteBorStateSyncEvents(db, syncs.Hash, syncs.Number, syncs.Events)
+			rawdb.WriteBorStateSyncLookupEntries(db, syncs.Number, syncs.Events)
+
+			summary.StateSyncBlocks++
+			summary.StateSyncs += len(syncs.Events)
+
+		case record.LastStateSyncID != nil:
+			rawdb.WriteBorLastStateSyncID(db, *record.LastStateSyncID)
+
+			summary.LastStateSyncID = record.LastStateSyncID
+		}
+	}
+
+	if header == nil {
+		return nil, errors.New("empty consensus state dump")
+	}
+
+	return summary, nil
+}
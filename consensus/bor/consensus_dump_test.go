@@ -0,0 +1,101 @@
+package bor
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// Tests that the consensus state dumped from a node is loaded into another one,
+// which takes over from the dumped snapshot.
+func TestConsensusDump(t *testing.T) {
+	t.Parallel()
+
+	engine, chain := newHealEngine(t, 41)
+
+	for _, header := range chain.headers {
+		rawdb.WriteHeader(engine.db, header)
+		rawdb.WriteCanonicalHash(engine.db, header.Hash(), header.Number.Uint64())
+	}
+
+	rawdb.WriteHeadHeaderHash(engine.db, chain.headers[40].Hash())
+	rawdb.WriteChainConfig(engine.db, chain.headers[0].Hash(), chain.config)
+
+	// The snapshot of block 32 is the last one stored below the head
+	snap, err := engine.snapshot(chain, 32, chain.headers[32].Hash(), nil)
+	require.NoError(t, err)
+
+	_, err = engine.snapshot(chain, 40, chain.headers[40].Hash(), nil)
+	require.NoError(t, err)
+
+	spans := NewSpanStore(engine.db, nil)
+	for id := uint64(1); id <= 2; id++ {
+		require.NoError(t, spans.store(&span.HeimdallSpan{Span: span.Span{ID: id, StartBlock: id * 16, EndBlock: id*16 + 15}, ChainID: "1"}))
+	}
+
+	events := []*types.StateSyncData{
+		{ID: 1, Contract: common.Address{0x1}, Data: "01", TxHash: common.Hash{0x1}},
+		{ID: 2, Contract: common.Address{0x1}, Data: "02", TxHash: common.Hash{0x2}},
+	}
+	rawdb.WriteBorStateSyncEvents(engine.db, chain.headers[36].Hash(), 36, events)
+	rawdb.WriteBorLastStateSyncID(engine.db, 2)
+
+	var dump bytes.Buffer
+
+	summary, err := DumpConsensusState(engine.db, &dump, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(32), summary.Snapshot)
+	require.Equal(t, 2, summary.Spans)
+	require.Equal(t, 1, summary.StateSyncBlocks)
+	require.Equal(t, 2, summary.StateSyncs)
+	require.Equal(t, uint64(2), *summary.LastStateSyncID)
+
+	// The other node only holds the genesis block, the chain is imported later
+	newDB := func() ethdb.Database {
+		db := rawdb.NewMemoryDatabase()
+		rawdb.WriteCanonicalHash(db, chain.headers[0].Hash(), 0)
+
+		return db
+	}
+
+	db := newDB()
+
+	loaded, err := LoadConsensusState(db, bytes.NewReader(dump.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, summary, loaded)
+
+	stored, err := loadSnapshot(chain.config, chain.config.Bor, nil, db, chain.headers[32].Hash())
+	require.NoError(t, err)
+	require.Equal(t, uint64(32), stored.Number)
+	require.Equal(t, snap.ValidatorSet.Validators[0].Address, stored.ValidatorSet.Validators[0].Address)
+
+	heimdallSpan, err := NewSpanStore(db, nil).GetSpanById(context.Background(), 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(32), heimdallSpan.StartBlock)
+
+	require.Equal(t, events, rawdb.ReadBorStateSyncEvents(db, chain.headers[36].Hash(), 36))
+	require.Equal(t, uint64(36), *rawdb.ReadBorStateSyncLookupEntry(db, 2))
+	require.Equal(t, uint64(2), *rawdb.ReadBorLastStateSyncID(db))
+
+	// The state of another chain is refused
+	db = rawdb.NewMemoryDatabase()
+	rawdb.WriteCanonicalHash(db, common.Hash{0x1}, 0)
+
+	_, err = LoadConsensusState(db, bytes.NewReader(dump.Bytes()))
+	require.ErrorContains(t, err, "genesis")
+
+	// So is a snapshot of a block reorged out of the chain held
+	db = newDB()
+	rawdb.WriteCanonicalHash(db, common.Hash{0x1}, 32)
+
+	_, err = LoadConsensusState(db, bytes.NewReader(dump.Bytes()))
+	require.ErrorContains(t, err, "not on the canonical chain")
+}
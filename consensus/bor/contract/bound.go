@@ -0,0 +1,67 @@
+package contract
+
+import (
+	"context"
+	"math"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	borabi "github.com/ethereum/go-ethereum/consensus/bor/abi"
+	"github.com/ethereum/go-ethereum/consensus/bor/api"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// callGas is the gas allowance of the read-only genesis contract calls.
+const callGas = uint64(math.MaxUint64 / 2)
+
+// boundContract routes the calls of a typed binding to the contract
+// deployed at address through the engine's api.Caller.
+type boundContract struct {
+	name    string
+	abi     borabi.ABI
+	caller  api.Caller
+	address common.Address
+}
+
+// pack abi encodes the call data of method, wrapping failures in a CallError.
+func (c *boundContract) pack(method string, args ...interface{}) ([]byte, error) {
+	data, err := c.abi.Pack(method, args...)
+	if err != nil {
+		return nil, newCallError(c.name, method, err)
+	}
+
+	return data, nil
+}
+
+// call executes method on top of the state at blockNrOrHash, or on top of
+// statedb if not nil, and unpacks the returned values into out.
+func (c *boundContract) call(ctx context.Context, out interface{}, blockNrOrHash rpc.BlockNumberOrHash, statedb *state.StateDB, method string, args ...interface{}) error {
+	data, err := c.pack(method, args...)
+	if err != nil {
+		return err
+	}
+
+	msg := ethereum.CallMsg{
+		Gas:  callGas,
+		To:   &c.address,
+		Data: data,
+	}
+
+	var result []byte
+	if statedb != nil {
+		result, err = c.caller.CallWithState(ctx, msg, &blockNrOrHash, statedb)
+	} else {
+		result, err = c.caller.Call(ctx, msg, &blockNrOrHash)
+	}
+
+	if err != nil {
+		return newCallError(c.name, method, err)
+	}
+
+	if err := c.abi.UnpackIntoInterface(out, method, result); err != nil {
+		return newCallError(c.name, method, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/bor/api"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type testRevertError struct {
+	data []byte
+}
+
+func (e *testRevertError) Error() string          { return "execution reverted" }
+func (e *testRevertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+func revertData(t *testing.T, reason string) []byte {
+	t.Helper()
+
+	typ, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+
+	enc, err := abi.Arguments{{Type: typ}}.Pack(reason)
+	require.NoError(t, err)
+
+	return append(common.FromHex("0x08c379a0"), enc...)
+}
+
+func TestValidatorSetCallerDecodesRevert(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revert := &testRevertError{data: revertData(t, "span not found")}
+
+	caller := api.NewMockCaller(ctrl)
+	caller.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, revert)
+
+	validatorSet := NewValidatorSetCaller(ValidatorSet(), caller, common.HexToAddress("0x1000"))
+
+	_, err := validatorSet.GetCurrentSpan(context.Background(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	require.Error(t, err)
+
+	var callErr *CallError
+	require.True(t, errors.As(err, &callErr))
+	require.Equal(t, "ValidatorSet", callErr.Contract)
+	require.Equal(t, "getCurrentSpan", callErr.Method)
+	require.Equal(t, "span not found", callErr.Reason)
+	require.ErrorIs(t, err, revert)
+}
+
+func TestStateReceiverCallerUnpacks(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	caller := api.NewMockCaller(ctrl)
+	caller.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).Return(common.LeftPadBytes([]byte{42}, 32), nil)
+
+	stateReceiver := NewStateReceiverCaller(StateReceiver(), caller, common.HexToAddress("0x1001"))
+
+	id, err := stateReceiver.LastStateId(context.Background(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), id.Uint64())
+
+	// Undecodable return data is reported as a call error of the method.
+	caller.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any()).Return([]byte{1}, nil)
+
+	_, err = stateReceiver.LastStateId(context.Background(), rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), nil)
+
+	var callErr *CallError
+	require.True(t, errors.As(err, &callErr))
+	require.Equal(t, "lastStateId", callErr.Method)
+	require.Empty(t, callErr.Reason)
+}
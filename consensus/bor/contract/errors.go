@@ -0,0 +1,55 @@
+package contract
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CallError is returned by the genesis contract bindings when a call to one
+// of the contracts fails. Reverts carry the decoded revert reason, if any.
+type CallError struct {
+	Contract string
+	Method   string
+	Reason   string // decoded revert reason, empty if the call did not revert with one
+	Err      error
+}
+
+func (e *CallError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s.%s reverted: %s", e.Contract, e.Method, e.Reason)
+	}
+
+	return fmt.Sprintf("%s.%s: %v", e.Contract, e.Method, e.Err)
+}
+
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+// newCallError wraps an error returned while calling, packing or unpacking
+// the given contract method. If the error carries revert data, the revert
+// reason is decoded from it.
+func newCallError(contract, method string, err error) error {
+	callErr := &CallError{
+		Contract: contract,
+		Method:   method,
+		Err:      err,
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			if revert, decodeErr := hexutil.Decode(data); decodeErr == nil {
+				if reason, unpackErr := abi.UnpackRevert(revert); unpackErr == nil {
+					callErr.Reason = reason
+				}
+			}
+		}
+	}
+
+	return callErr
+}
@@ -0,0 +1,54 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	borabi "github.com/ethereum/go-ethereum/consensus/bor/abi"
+	"github.com/ethereum/go-ethereum/consensus/bor/api"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const stateReceiverABI = `[{"constant":true,"inputs":[],"name":"SYSTEM_ADDRESS","outputs":[{"internalType":"address","name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"lastStateId","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"internalType":"uint256","name":"syncTime","type":"uint256"},{"internalType":"bytes","name":"recordBytes","type":"bytes"}],"name":"commitState","outputs":[{"internalType":"bool","name":"success","type":"bool"}],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
+// StateReceiverCaller is a typed binding for the StateReceiver genesis contract.
+type StateReceiverCaller struct {
+	contract boundContract
+}
+
+// NewStateReceiverCaller binds the StateReceiver contract deployed at address,
+// calling it through caller. The stateReceiver ABI is usually StateReceiver().
+func NewStateReceiverCaller(stateReceiver borabi.ABI, caller api.Caller, address common.Address) *StateReceiverCaller {
+	return &StateReceiverCaller{
+		contract: boundContract{
+			name:    "StateReceiver",
+			abi:     stateReceiver,
+			caller:  caller,
+			address: address,
+		},
+	}
+}
+
+// Address returns the address of the bound contract.
+func (s *StateReceiverCaller) Address() common.Address {
+	return s.contract.address
+}
+
+// LastStateId calls lastStateId() (uint256). If statedb is not nil the call
+// is executed on top of it instead of the state at blockNrOrHash.
+func (s *StateReceiverCaller) LastStateId(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, statedb *state.StateDB) (*big.Int, error) {
+	var out *big.Int
+	if err := s.contract.call(ctx, &out, blockNrOrHash, statedb, "lastStateId"); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// PackCommitState packs the call data of the commitState(uint256 syncTime,
+// bytes recordBytes) system call.
+func (s *StateReceiverCaller) PackCommitState(syncTime *big.Int, recordBytes []byte) ([]byte, error) {
+	return s.contract.pack("commitState", syncTime, recordBytes)
+}
@@ -0,0 +1,128 @@
+package contract
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	borabi "github.com/ethereum/go-ethereum/consensus/bor/abi"
+	"github.com/ethereum/go-ethereum/consensus/bor/api"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const validatorsetABI = `[{"constant":true,"inputs":[],"name":"SPRINT","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"SYSTEM_ADDRESS","outputs":[{"internalType":"address","name":"","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"CHAIN","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"FIRST_END_BLOCK","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"","type":"uint256"},{"internalType":"uint256","name":"","type":"uint256"}],"name":"producers","outputs":[{"internalType":"uint256","name":"id","type":"uint256"},{"internalType":"uint256","name":"power","type":"uint256"},{"internalType":"address","name":"signer","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"ROUND_TYPE","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"BOR_ID","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"spanNumbers","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"VOTE_TYPE","outputs":[{"internalType":"uint8","name":"","type":"uint8"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"","type":"uint256"},{"internalType":"uint256","name":"","type":"uint256"}],"name":"validators","outputs":[{"internalType":"uint256","name":"id","type":"uint256"},{"internalType":"uint256","name":"power","type":"uint256"},{"internalType":"address","name":"signer","type":"address"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"","type":"uint256"}],"name":"spans","outputs":[{"internalType":"uint256","name":"number","type":"uint256"},{"internalType":"uint256","name":"startBlock","type":"uint256"},{"internalType":"uint256","name":"endBlock","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"inputs":[],"payable":false,"stateMutability":"nonpayable","type":"constructor"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"uint256","name":"id","type":"uint256"},{"indexed":true,"internalType":"uint256","name":"startBlock","type":"uint256"},{"indexed":true,"internalType":"uint256","name":"endBlock","type":"uint256"}],"name":"NewSpan","type":"event"},{"constant":true,"inputs":[],"name":"currentSprint","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"span","type":"uint256"}],"name":"getSpan","outputs":[{"internalType":"uint256","name":"number","type":"uint256"},{"internalType":"uint256","name":"startBlock","type":"uint256"},{"internalType":"uint256","name":"endBlock","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"getCurrentSpan","outputs":[{"internalType":"uint256","name":"number","type":"uint256"},{"internalType":"uint256","name":"startBlock","type":"uint256"},{"internalType":"uint256","name":"endBlock","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"getNextSpan","outputs":[{"internalType":"uint256","name":"number","type":"uint256"},{"internalType":"uint256","name":"startBlock","type":"uint256"},{"internalType":"uint256","name":"endBlock","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"number","type":"uint256"}],"name":"getSpanByBlock","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"currentSpanNumber","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"span","type":"uint256"}],"name":"getValidatorsTotalStakeBySpan","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"span","type":"uint256"}],"name":"getProducersTotalStakeBySpan","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"span","type":"uint256"},{"internalType":"address","name":"signer","type":"address"}],"name":"getValidatorBySigner","outputs":[{"components":[{"internalType":"uint256","name":"id","type":"uint256"},{"internalType":"uint256","name":"power","type":"uint256"},{"internalType":"address","name":"signer","type":"address"}],"internalType":"struct BorValidatorSet.Validator","name":"result","type":"tuple"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"span","type":"uint256"},{"internalType":"address","name":"signer","type":"address"}],"name":"isValidator","outputs":[{"internalType":"bool","name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"span","type":"uint256"},{"internalType":"address","name":"signer","type":"address"}],"name":"isProducer","outputs":[{"internalType":"bool","name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"address","name":"signer","type":"address"}],"name":"isCurrentValidator","outputs":[{"internalType":"bool","name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"address","name":"signer","type":"address"}],"name":"isCurrentProducer","outputs":[{"internalType":"bool","name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"number","type":"uint256"}],"name":"getBorValidators","outputs":[{"internalType":"address[]","name":"","type":"address[]"},{"internalType":"uint256[]","name":"","type":"uint256[]"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"getInitialValidators","outputs":[{"internalType":"address[]","name":"","type":"address[]"},{"internalType":"uint256[]","name":"","type":"uint256[]"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[],"name":"getValidators","outputs":[{"internalType":"address[]","name":"","type":"address[]"},{"internalType":"uint256[]","name":"","type":"uint256[]"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":false,"inputs":[{"internalType":"uint256","name":"newSpan","type":"uint256"},{"internalType":"uint256","name":"startBlock","type":"uint256"},{"internalType":"uint256","name":"endBlock","type":"uint256"},{"internalType":"bytes","name":"validatorBytes","type":"bytes"},{"internalType":"bytes","name":"producerBytes","type":"bytes"}],"name":"commitSpan","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":true,"inputs":[{"internalType":"uint256","name":"span","type":"uint256"},{"internalType":"bytes32","name":"dataHash","type":"bytes32"},{"internalType":"bytes","name":"sigs","type":"bytes"}],"name":"getStakePowerBySigs","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"},{"constant":true,"inputs":[{"internalType":"bytes32","name":"rootHash","type":"bytes32"},{"internalType":"bytes32","name":"leaf","type":"bytes32"},{"internalType":"bytes","name":"proof","type":"bytes"}],"name":"checkMembership","outputs":[{"internalType":"bool","name":"","type":"bool"}],"payable":false,"stateMutability":"pure","type":"function"},{"constant":true,"inputs":[{"internalType":"bytes32","name":"d","type":"bytes32"}],"name":"leafNode","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"payable":false,"stateMutability":"pure","type":"function"},{"constant":true,"inputs":[{"internalType":"bytes32","name":"left","type":"bytes32"},{"internalType":"bytes32","name":"right","type":"bytes32"}],"name":"innerNode","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"payable":false,"stateMutability":"pure","type":"function"},{"constant":true,"inputs":[],"name":"producerCount","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// ValidatorSetSpan is the span returned by the ValidatorSet contract.
+type ValidatorSetSpan struct {
+	Number     *big.Int
+	StartBlock *big.Int
+	EndBlock   *big.Int
+}
+
+// ValidatorSetValidator is a validator (or producer) entry of the
+// ValidatorSet contract.
+type ValidatorSetValidator struct {
+	Id     *big.Int
+	Power  *big.Int
+	Signer common.Address
+}
+
+// ValidatorSetCaller is a typed binding for the ValidatorSet genesis contract.
+type ValidatorSetCaller struct {
+	contract boundContract
+}
+
+// NewValidatorSetCaller binds the ValidatorSet contract deployed at address,
+// calling it through caller. The validatorSet ABI is usually ValidatorSet().
+func NewValidatorSetCaller(validatorSet borabi.ABI, caller api.Caller, address common.Address) *ValidatorSetCaller {
+	return &ValidatorSetCaller{
+		contract: boundContract{
+			name:    "ValidatorSet",
+			abi:     validatorSet,
+			caller:  caller,
+			address: address,
+		},
+	}
+}
+
+// Address returns the address of the bound contract.
+func (v *ValidatorSetCaller) Address() common.Address {
+	return v.contract.address
+}
+
+// GetCurrentSpan calls getCurrentSpan() (uint256 number, uint256 startBlock, uint256 endBlock).
+func (v *ValidatorSetCaller) GetCurrentSpan(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*ValidatorSetSpan, error) {
+	out := new(ValidatorSetSpan)
+	if err := v.contract.call(ctx, out, blockNrOrHash, nil, "getCurrentSpan"); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// GetSpanByBlock calls getSpanByBlock(uint256 number) (uint256).
+func (v *ValidatorSetCaller) GetSpanByBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, number *big.Int) (*big.Int, error) {
+	var out *big.Int
+	if err := v.contract.call(ctx, &out, blockNrOrHash, nil, "getSpanByBlock", number); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Producers calls producers(uint256 span, uint256 index) (uint256 id, uint256 power, address signer).
+func (v *ValidatorSetCaller) Producers(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, span *big.Int, index *big.Int) (*ValidatorSetValidator, error) {
+	out := new(ValidatorSetValidator)
+	if err := v.contract.call(ctx, out, blockNrOrHash, nil, "producers", span, index); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// FirstEndBlock calls FIRST_END_BLOCK() (uint256).
+func (v *ValidatorSetCaller) FirstEndBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*big.Int, error) {
+	var out *big.Int
+	if err := v.contract.call(ctx, &out, blockNrOrHash, nil, "FIRST_END_BLOCK"); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// ProducerCount calls producerCount() (uint256). If statedb is not nil the
+// call is executed on top of it instead of the state at blockNrOrHash.
+func (v *ValidatorSetCaller) ProducerCount(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, statedb *state.StateDB) (*big.Int, error) {
+	var out *big.Int
+	if err := v.contract.call(ctx, &out, blockNrOrHash, statedb, "producerCount"); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// GetBorValidators calls getBorValidators(uint256 number) (address[], uint256[]).
+func (v *ValidatorSetCaller) GetBorValidators(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, number *big.Int) ([]common.Address, []*big.Int, error) {
+	var (
+		signers = new([]common.Address)
+		powers  = new([]*big.Int)
+	)
+
+	out := &[]interface{}{
+		signers,
+		powers,
+	}
+
+	if err := v.contract.call(ctx, out, blockNrOrHash, nil, "getBorValidators", number); err != nil {
+		return nil, nil, err
+	}
+
+	return *signers, *powers, nil
+}
+
+// PackCommitSpan packs the call data of the commitSpan(uint256 newSpan, uint256 startBlock,
+// uint256 endBlock, bytes validatorBytes, bytes producerBytes) system call.
+func (v *ValidatorSetCaller) PackCommitSpan(newSpan, startBlock, endBlock *big.Int, validatorBytes, producerBytes []byte) ([]byte, error) {
+	return v.contract.pack("commitSpan", newSpan, startBlock, endBlock, validatorBytes, producerBytes)
+}
@@ -1,10 +1,15 @@
 package bor
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 type MaxCheckpointLengthExceededError struct {
@@ -98,6 +103,69 @@ func (e *WrongDifficultyError) Error() string {
 	)
 }
 
+// SealError is returned if the seal of a block is rejected, wrapping the reason
+// with the context of the block: its number and hash, the signer recovered from
+// the seal and the proposer whose turn it was.
+type SealError struct {
+	Number   uint64
+	Hash     common.Hash
+	Signer   common.Address
+	Proposer common.Address // In-turn proposer of the block, zero if unknown
+	Err      error
+}
+
+var _ consensus.BlockError = (*SealError)(nil)
+
+// newSealError wraps the rejection of the seal of a header by the given signer,
+// validated against the given snapshot of its parent.
+func newSealError(snap *Snapshot, header *types.Header, signer common.Address, err error) *SealError {
+	e := &SealError{
+		Number: header.Number.Uint64(),
+		Hash:   header.Hash(),
+		Signer: signer,
+		Err:    err,
+	}
+
+	if snap != nil && snap.ValidatorSet != nil {
+		if proposer := snap.ValidatorSet.GetProposer(); proposer != nil {
+			e.Proposer = proposer.Address
+		}
+	}
+
+	return e
+}
+
+func (e *SealError) Error() string {
+	return fmt.Sprintf(
+		"%v [number: %d, hash: %x, signer: %x, expected proposer: %x]",
+		e.Err,
+		e.Number,
+		e.Hash,
+		e.Signer,
+		e.Proposer,
+	)
+}
+
+func (e *SealError) Unwrap() error {
+	return e.Err
+}
+
+// LogContext implements consensus.BlockError.
+func (e *SealError) LogContext() []interface{} {
+	return []interface{}{"number", e.Number, "hash", e.Hash, "signer", e.Signer, "proposer", e.Proposer}
+}
+
+// ErrorData implements rpc.DataError, returning the context of the block to RPC
+// clients along with the error message.
+func (e *SealError) ErrorData() interface{} {
+	return map[string]interface{}{
+		"number":   hexutil.Uint64(e.Number),
+		"hash":     e.Hash,
+		"signer":   e.Signer,
+		"proposer": e.Proposer,
+	}
+}
+
 type InvalidStateReceivedError struct {
 	Number      uint64
 	LastStateID uint64
@@ -114,3 +182,81 @@ func (e *InvalidStateReceivedError) Error() string {
 		e.LastStateID,
 	)
 }
+
+// JSON-RPC error codes of the bor specific failures, so that clients can branch
+// on them instead of parsing the error messages.
+const (
+	ErrCodeUnknownSnapshot      = -32101 // No snapshot of the requested block, which is unknown
+	ErrCodePrunedValidators     = -32102 // The validator history of the requested block is no longer available
+	ErrCodeFinalityNotAvailable = -32103 // No block is finalized by a checkpoint or milestone yet
+	ErrCodeHeimdallUnreachable  = -32104 // Heimdall didn't serve the data requested
+	ErrCodeInvalidParams        = -32602 // The parameters of the request are invalid
+)
+
+// rpcErrorReasons are the names of the bor specific JSON-RPC error codes,
+// returned as the data of the errors.
+var rpcErrorReasons = map[int]string{
+	ErrCodeUnknownSnapshot:      "unknownSnapshot",
+	ErrCodePrunedValidators:     "prunedValidators",
+	ErrCodeFinalityNotAvailable: "finalityNotAvailable",
+	ErrCodeHeimdallUnreachable:  "heimdallUnreachable",
+	ErrCodeInvalidParams:        "invalidParams",
+}
+
+// ErrFinalityNotAvailable is returned if the finalized block is requested before
+// any block is finalized.
+var ErrFinalityNotAvailable = &RPCError{Code: ErrCodeFinalityNotAvailable, Err: errors.New("finalized block not found")}
+
+// errHeimdallUnavailable is returned if heimdall fails to serve the data needed.
+var errHeimdallUnavailable = errors.New("heimdall unavailable")
+
+// errInvalidBlockNumber is returned if a block tag is requested where only the
+// head or a block number is accepted.
+var errInvalidBlockNumber = errors.New("invalid block number")
+
+// RPCError is a bor specific failure, reported to JSON-RPC clients with its
+// own error code and the name of the code as data.
+type RPCError struct {
+	Code int
+	Err  error
+}
+
+func (e *RPCError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RPCError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode implements rpc.Error.
+func (e *RPCError) ErrorCode() int {
+	return e.Code
+}
+
+// ErrorData implements rpc.DataError.
+func (e *RPCError) ErrorData() interface{} {
+	return map[string]string{"reason": rpcErrorReasons[e.Code]}
+}
+
+// apiError returns the bor specific failures among the errors of the API with
+// their JSON-RPC error code, and other errors as they are.
+func apiError(err error) error {
+	var rpcErr *RPCError
+
+	switch {
+	case err == nil || errors.As(err, &rpcErr):
+		return err
+	case errors.Is(err, errUnknownBlock):
+		return &RPCError{Code: ErrCodeUnknownSnapshot, Err: err}
+	case errors.Is(err, consensus.ErrUnknownAncestor):
+		return &RPCError{Code: ErrCodePrunedValidators, Err: err}
+	case errors.Is(err, errHeimdallUnavailable), errors.Is(err, errUnknownSpan):
+		// Spans which aren't stored are only served by heimdall
+		return &RPCError{Code: ErrCodeHeimdallUnreachable, Err: err}
+	case errors.Is(err, errInvalidBlockNumber):
+		return &RPCError{Code: ErrCodeInvalidParams, Err: err}
+	}
+
+	return err
+}
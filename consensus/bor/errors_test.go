@@ -0,0 +1,82 @@
+package bor
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestSealError(t *testing.T) {
+	t.Parallel()
+
+	var (
+		proposer = common.HexToAddress("0x01")
+		signer   = common.HexToAddress("0x02")
+		header   = &types.Header{Number: big.NewInt(64)}
+		snap     = &Snapshot{ValidatorSet: valset.NewValidatorSet([]*valset.Validator{valset.NewValidator(proposer, 10)})}
+	)
+
+	cause := &UnauthorizedSignerError{63, signer.Bytes()}
+	err := error(newSealError(snap, header, signer, cause))
+
+	var signerErr *UnauthorizedSignerError
+
+	require.ErrorAs(t, err, &signerErr)
+	require.Equal(t, cause, signerErr)
+	require.Contains(t, err.Error(), cause.Error())
+
+	require.Equal(t, []interface{}{"number", uint64(64), "hash", header.Hash(), "signer", signer, "proposer", proposer}, consensus.ErrorContext(err))
+	require.Equal(t, consensus.ErrorContext(err), consensus.ErrorContext(errors.Join(errors.New("import failed"), err)))
+	require.Nil(t, consensus.ErrorContext(cause))
+
+	data, ok := err.(interface{ ErrorData() interface{} })
+	require.True(t, ok)
+	require.Equal(t, hexutil.Uint64(64), data.ErrorData().(map[string]interface{})["number"])
+
+	// The proposer is unknown without a validator set
+	require.Equal(t, common.Address{}, newSealError(nil, header, signer, cause).Proposer)
+}
+
+func TestAPIError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{errUnknownBlock, ErrCodeUnknownSnapshot},
+		{fmt.Errorf("%w: missing canonical header %d", errUnknownBlock, 10), ErrCodeUnknownSnapshot},
+		{consensus.ErrUnknownAncestor, ErrCodePrunedValidators},
+		{fmt.Errorf("%w: %w", errHeimdallUnavailable, errors.New("connection refused")), ErrCodeHeimdallUnreachable},
+		{fmt.Errorf("span %d not available: %w", 2, errUnknownSpan), ErrCodeHeimdallUnreachable},
+		{fmt.Errorf("%w: %d", errInvalidBlockNumber, -2), ErrCodeInvalidParams},
+		{ErrFinalityNotAvailable, ErrCodeFinalityNotAvailable},
+	}
+
+	for _, test := range tests {
+		err := apiError(test.err)
+
+		var rpcErr *RPCError
+
+		require.ErrorAs(t, err, &rpcErr)
+		require.Equal(t, test.code, rpcErr.ErrorCode())
+		require.Equal(t, test.err.Error(), err.Error())
+		require.ErrorIs(t, err, test.err)
+		require.NotEmpty(t, rpcErr.ErrorData().(map[string]string)["reason"])
+	}
+
+	// Other errors are returned as they are
+	other := errors.New("other")
+
+	require.Equal(t, other, apiError(other))
+	require.NoError(t, apiError(nil))
+}
@@ -0,0 +1,187 @@
+package bor
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/evidence"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	inmemorySeals = 4096 // Number of recent (height, signer) seals to track for double signing
+
+	// evidenceSubmitTimeout is the maximum time a single evidence submission may take.
+	evidenceSubmitTimeout = 30 * time.Second
+)
+
+// evidencePrefix + number (uint64 big endian) + signer -> double sign evidence
+var evidencePrefix = []byte("bor-evidence-")
+
+// evidenceKey = evidencePrefix + number (uint64 big endian) + signer
+func evidenceKey(number uint64, signer common.Address) []byte {
+	key := binary.BigEndian.AppendUint64(append([]byte{}, evidencePrefix...), number)
+	return append(key, signer.Bytes()...)
+}
+
+// EvidenceReporter submits the evidence of double signing validators for
+// slashing. It is implemented by the heimdall HTTP client.
+type EvidenceReporter interface {
+	SubmitDoubleSignEvidence(ctx context.Context, ev *evidence.DoubleSign) error
+}
+
+// sealKey identifies the seal of a signer at a given height.
+type sealKey struct {
+	number uint64
+	signer common.Address
+}
+
+// doubleSignDetector tracks the headers sealed by every signer at every recent
+// height, across all forks seen, and persists the evidence of any validator
+// sealing two different headers at the same height.
+type doubleSignDetector struct {
+	db       ethdb.Database
+	config   *params.BorConfig
+	seals    *lru.ARCCache    // First header seen per (height, signer)
+	reporter EvidenceReporter // Optional hook submitting the evidence, nil if disabled
+
+	lock sync.Mutex
+}
+
+func newDoubleSignDetector(db ethdb.Database, config *params.BorConfig) *doubleSignDetector {
+	seals, _ := lru.NewARC(inmemorySeals)
+
+	return &doubleSignDetector{
+		db:     db,
+		config: config,
+		seals:  seals,
+	}
+}
+
+// setReporter sets the hook submitting newly detected evidence.
+func (d *doubleSignDetector) setReporter(reporter EvidenceReporter) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.reporter = reporter
+}
+
+// observe records a header sealed by the given signer, returning the evidence
+// of double signing if the signer already signed a different payload at the
+// same height. Evidence is only returned the first time it's detected.
+//
+// Headers are compared by seal hash rather than hash, which covers the seal:
+// the high-s form of a signature recovers the same signer, so anyone could
+// otherwise relay a malleated copy of an honest header as a second one. Seals
+// not in the canonical low-s form aren't recorded at all.
+func (d *doubleSignDetector) observe(header *types.Header, signer common.Address) *evidence.DoubleSign {
+	if !canonicalSeal(header) {
+		return nil
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	key := sealKey{number: header.Number.Uint64(), signer: signer}
+
+	prev, ok := d.seals.Get(key)
+	if !ok {
+		d.seals.Add(key, types.CopyHeader(header))
+		return nil
+	}
+
+	first := prev.(*types.Header)
+	if SealHash(first, d.config) == SealHash(header, d.config) {
+		return nil
+	}
+
+	if has, _ := d.db.Has(evidenceKey(key.number, signer)); has {
+		return nil
+	}
+
+	ev := &evidence.DoubleSign{
+		Number:     key.number,
+		Signer:     signer,
+		First:      first,
+		Second:     types.CopyHeader(header),
+		DetectedAt: uint64(time.Now().Unix()),
+	}
+
+	blob, err := json.Marshal(ev)
+	if err != nil {
+		log.Error("Failed to encode double sign evidence", "number", key.number, "signer", signer, "err", err)
+		return ev
+	}
+
+	if err := d.db.Put(evidenceKey(key.number, signer), blob); err != nil {
+		log.Error("Failed to store double sign evidence", "number", key.number, "signer", signer, "err", err)
+	}
+
+	if d.reporter != nil {
+		go submitEvidence(d.reporter, ev)
+	}
+
+	return ev
+}
+
+// canonicalSeal reports whether the header carries a seal in the canonical
+// form, with a low s value and a recovery id of 0 or 1.
+func canonicalSeal(header *types.Header) bool {
+	if len(header.Extra) < types.ExtraSealLength {
+		return false
+	}
+
+	signature := header.Extra[len(header.Extra)-types.ExtraSealLength:]
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+
+	return crypto.ValidateSignatureValues(signature[64], r, s, true)
+}
+
+// submitEvidence hands the evidence over to the reporter, logging failures.
+func submitEvidence(reporter EvidenceReporter, ev *evidence.DoubleSign) {
+	ctx, cancel := context.WithTimeout(context.Background(), evidenceSubmitTimeout)
+	defer cancel()
+
+	if err := reporter.SubmitDoubleSignEvidence(ctx, ev); err != nil {
+		log.Warn("Failed to submit double sign evidence", "number", ev.Number, "signer", ev.Signer, "err", err)
+		return
+	}
+
+	log.Info("Submitted double sign evidence", "number", ev.Number, "signer", ev.Signer)
+}
+
+// evidence returns the persisted double sign evidence of the given block range.
+func (d *doubleSignDetector) evidence(from, to uint64) ([]*evidence.DoubleSign, error) {
+	it := d.db.NewIterator(evidencePrefix, binary.BigEndian.AppendUint64(nil, from))
+	defer it.Release()
+
+	list := make([]*evidence.DoubleSign, 0)
+
+	for it.Next() {
+		ev := new(evidence.DoubleSign)
+		if err := json.Unmarshal(it.Value(), ev); err != nil {
+			return nil, err
+		}
+
+		if ev.Number > to {
+			break
+		}
+
+		list = append(list, ev)
+	}
+
+	return list, it.Error()
+}
@@ -0,0 +1,150 @@
+package bor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/evidence"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type testEvidenceReporter struct {
+	submitted chan *evidence.DoubleSign
+}
+
+func (r *testEvidenceReporter) SubmitDoubleSignEvidence(ctx context.Context, ev *evidence.DoubleSign) error {
+	r.submitted <- ev
+	return nil
+}
+
+// sealEvidenceHeader returns a header at the given height with the given
+// parent, sealed by the given key.
+func sealEvidenceHeader(t *testing.T, config *params.BorConfig, key *ecdsa.PrivateKey, number int64, parent common.Hash) *types.Header {
+	t.Helper()
+
+	header := &types.Header{Number: big.NewInt(number), ParentHash: parent, Extra: make([]byte, types.ExtraVanityLength+types.ExtraSealLength)}
+
+	sig, err := crypto.Sign(SealHash(header, config).Bytes(), key)
+	require.NoError(t, err)
+
+	copy(header.Extra[types.ExtraVanityLength:], sig)
+
+	return header
+}
+
+// malleate returns a copy of the header with its seal in the high-s form,
+// which recovers the same signer.
+func malleate(header *types.Header) *types.Header {
+	malleated := types.CopyHeader(header)
+	sig := malleated.Extra[len(malleated.Extra)-types.ExtraSealLength:]
+
+	s := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(sig[32:64]))
+	s.FillBytes(sig[32:64])
+	sig[64] ^= 1
+
+	return malleated
+}
+
+func TestDoubleSignDetection(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config   = &params.BorConfig{}
+		detector = newDoubleSignDetector(rawdb.NewMemoryDatabase(), config)
+		reporter = &testEvidenceReporter{submitted: make(chan *evidence.DoubleSign, 1)}
+		signer   = common.HexToAddress("0x01")
+		other    = common.HexToAddress("0x02")
+	)
+
+	detector.setReporter(reporter)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	first := sealEvidenceHeader(t, config, key, 10, common.HexToHash("0xaa"))
+	second := sealEvidenceHeader(t, config, key, 10, common.HexToHash("0xbb"))
+
+	// Seeing the same header again, or another signer at the same height, is fine
+	require.Nil(t, detector.observe(first, signer))
+	require.Nil(t, detector.observe(first, signer))
+	require.Nil(t, detector.observe(second, other))
+
+	// A second header of the same signer at the same height is an equivocation
+	ev := detector.observe(second, signer)
+	require.NotNil(t, ev)
+	require.Equal(t, uint64(10), ev.Number)
+	require.Equal(t, signer, ev.Signer)
+	require.Equal(t, first.Hash(), ev.First.Hash())
+	require.Equal(t, second.Hash(), ev.Second.Hash())
+
+	select {
+	case submitted := <-reporter.submitted:
+		require.Equal(t, ev, submitted)
+	case <-time.After(5 * time.Second):
+		t.Fatal("evidence not submitted")
+	}
+
+	// Evidence is only reported once
+	require.Nil(t, detector.observe(second, signer))
+
+	// And persisted for retrieval
+	list, err := detector.evidence(0, 100)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, second.Hash(), list[0].Second.Hash())
+
+	list, err = detector.evidence(11, 100)
+	require.NoError(t, err)
+	require.Empty(t, list)
+
+	list, err = detector.evidence(0, 9)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+// Tests that a malleated copy of a header, recovering the same signer with a
+// different hash, isn't taken for a second header of the signer.
+func TestDoubleSignMalleatedSeal(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config   = &params.BorConfig{}
+		detector = newDoubleSignDetector(rawdb.NewMemoryDatabase(), config)
+	)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	var (
+		signer    = crypto.PubkeyToAddress(key.PublicKey)
+		header    = sealEvidenceHeader(t, config, key, 10, common.HexToHash("0xaa"))
+		malleated = malleate(header)
+	)
+
+	pubkey, err := crypto.Ecrecover(SealHash(malleated, config).Bytes(), malleated.Extra[types.ExtraVanityLength:])
+	require.NoError(t, err)
+	require.Equal(t, signer, common.BytesToAddress(crypto.Keccak256(pubkey[1:])[12:]))
+	require.NotEqual(t, header.Hash(), malleated.Hash())
+
+	require.Nil(t, detector.observe(header, signer))
+	require.Nil(t, detector.observe(malleated, signer))
+
+	// Nor is a malleated seal recorded as the first one of its signer
+	other := sealEvidenceHeader(t, config, key, 11, common.HexToHash("0xaa"))
+
+	require.Nil(t, detector.observe(malleate(other), signer))
+	require.Nil(t, detector.observe(other, signer))
+
+	list, err := detector.evidence(0, 100)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
@@ -0,0 +1,109 @@
+package bor
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// maxForkIncidentLength is the maximum number of headers of the reorged branch
+// of an exported fork incident.
+const maxForkIncidentLength = 1024
+
+var errCanonicalBlock = errors.New("block is canonical")
+
+// ForkIncident is a fork observed by the node, along with everything needed to
+// replay the fork choice between its branches offline: the snapshot and total
+// difficulty of their common ancestor, and the headers of both branches up to
+// the height of the reorged one.
+type ForkIncident struct {
+	Description string              `json:"description,omitempty"`
+	Config      *params.ChainConfig `json:"config"`
+	Ancestor    *Snapshot           `json:"ancestor"`   // Snapshot of the last block common to the branches
+	AncestorTd  *big.Int            `json:"ancestorTd"` // Total difficulty of the last block common to the branches
+	Canonical   []*types.Header     `json:"canonical"`  // Branch the node settled on
+	Reorged     []*types.Header     `json:"reorged"`    // Branch the node abandoned, ending with the given block
+}
+
+// ExportForkIncident exports the fork ending with the given non-canonical
+// block, so that it can be checked in as a regression test of the fork choice.
+// The reorged branch is walked back to the canonical chain, which must still
+// hold its headers, snapshot and total difficulty.
+func (c *Bor) ExportForkIncident(chain consensus.ChainHeaderReader, hash common.Hash) (*ForkIncident, error) {
+	number := rawdb.ReadHeaderNumber(c.db, hash)
+	if number == nil {
+		return nil, errUnknownBlock
+	}
+
+	header := rawdb.ReadHeader(c.db, hash, *number)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+
+	var reorged []*types.Header
+
+	for {
+		if canonical := chain.GetHeaderByNumber(header.Number.Uint64()); canonical != nil && canonical.Hash() == header.Hash() {
+			break
+		}
+
+		if len(reorged) == maxForkIncidentLength {
+			return nil, fmt.Errorf("reorged branch longer than %d blocks", maxForkIncidentLength)
+		}
+
+		reorged = append(reorged, header)
+
+		if header.Number.Uint64() == 0 {
+			return nil, fmt.Errorf("%w: reorged branch down to the genesis", errUnknownBlock)
+		}
+
+		child := header.Number.Uint64()
+		if header = rawdb.ReadHeader(c.db, header.ParentHash, child-1); header == nil {
+			return nil, fmt.Errorf("%w: missing parent of reorged block %d", errUnknownBlock, child)
+		}
+	}
+
+	if len(reorged) == 0 {
+		return nil, errCanonicalBlock
+	}
+
+	for i, j := 0, len(reorged)-1; i < j; i, j = i+1, j-1 {
+		reorged[i], reorged[j] = reorged[j], reorged[i]
+	}
+
+	ancestor := header.Number.Uint64()
+
+	td := rawdb.ReadTd(c.db, header.Hash(), ancestor)
+	if td == nil {
+		return nil, fmt.Errorf("missing total difficulty of ancestor %d", ancestor)
+	}
+
+	snap, err := c.snapshot(chain, ancestor, header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	incident := &ForkIncident{
+		Config:     chain.Config(),
+		Ancestor:   snap,
+		AncestorTd: td,
+		Reorged:    reorged,
+	}
+
+	for number := ancestor + 1; number <= reorged[len(reorged)-1].Number.Uint64(); number++ {
+		canonical := chain.GetHeaderByNumber(number)
+		if canonical == nil {
+			break
+		}
+
+		incident.Canonical = append(incident.Canonical, canonical)
+	}
+
+	return incident, nil
+}
@@ -0,0 +1,281 @@
+package bor
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// incidentChain serves the total difficulties of the replayed branches of a
+// fork incident to the fork choice.
+type incidentChain struct {
+	config *params.ChainConfig
+	tds    map[common.Hash]*big.Int
+}
+
+func (c *incidentChain) Config() *params.ChainConfig                    { return c.config }
+func (c *incidentChain) GetTd(hash common.Hash, number uint64) *big.Int { return c.tds[hash] }
+
+// loadForkIncident decodes a fork incident exported by bor_exportForkIncident.
+func loadForkIncident(blob []byte) (*ForkIncident, error) {
+	incident := new(ForkIncident)
+	if err := json.Unmarshal(blob, incident); err != nil {
+		return nil, err
+	}
+
+	if incident.Config == nil || incident.Config.Bor == nil || incident.Ancestor == nil || incident.Ancestor.ValidatorSet == nil || incident.AncestorTd == nil {
+		return nil, errors.New("incomplete fork incident")
+	}
+
+	incident.Ancestor.ValidatorSet.UpdateValidatorMap()
+
+	if err := incident.Ancestor.ValidatorSet.UpdateTotalVotingPower(); err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// replayBranch applies a branch of the fork incident onto the snapshot of the
+// common ancestor, checking that every header is sealed by a validator with
+// the difficulty of its turn, and records the total difficulty of each.
+func replayBranch(incident *ForkIncident, branch []*types.Header, sigcache *lru.ARCCache, tds map[common.Hash]*big.Int) error {
+	snap := incident.Ancestor.copy()
+	snap.chainConfig = incident.Config
+	snap.sigcache = sigcache
+
+	td := incident.AncestorTd
+
+	for _, header := range branch {
+		number := header.Number.Uint64()
+
+		if header.ParentHash != snap.Hash {
+			return fmt.Errorf("block %d doesn't extend block %d %x", number, snap.Number, snap.Hash)
+		}
+
+		signer, err := ecrecover(header, sigcache, incident.Config.Bor)
+		if err != nil {
+			return fmt.Errorf("block %d: %w", number, err)
+		}
+
+		if want := Difficulty(snap.ValidatorSet, signer); header.Difficulty.Uint64() != want {
+			return fmt.Errorf("block %d sealed by %v with difficulty %v, want %d", number, signer, header.Difficulty, want)
+		}
+
+		if snap, err = snap.apply([]*types.Header{header}, nil); err != nil {
+			return fmt.Errorf("block %d: %w", number, err)
+		}
+
+		td = new(big.Int).Add(td, header.Difficulty)
+		tds[header.Hash()] = td
+	}
+
+	return nil
+}
+
+// checkForkIncident replays both branches of the fork incident and checks that
+// the fork choice settles on the canonical one, whichever arrives first.
+func checkForkIncident(incident *ForkIncident) error {
+	if len(incident.Canonical) == 0 || len(incident.Reorged) == 0 {
+		return errors.New("fork incident without both branches")
+	}
+
+	sigcache, _ := lru.NewARC(inmemorySignatures)
+
+	chain := &incidentChain{config: incident.Config, tds: make(map[common.Hash]*big.Int)}
+
+	if err := replayBranch(incident, incident.Canonical, sigcache, chain.tds); err != nil {
+		return fmt.Errorf("canonical branch: %w", err)
+	}
+
+	if err := replayBranch(incident, incident.Reorged, sigcache, chain.tds); err != nil {
+		return fmt.Errorf("reorged branch: %w", err)
+	}
+
+	var (
+		canonical  = incident.Canonical[len(incident.Canonical)-1]
+		reorged    = incident.Reorged[len(incident.Reorged)-1]
+		forkChoice = core.NewForkChoice(chain, nil, nil)
+	)
+
+	reorg, err := forkChoice.ReorgNeeded(reorged, canonical)
+	if err != nil {
+		return err
+	}
+
+	if !reorg {
+		return fmt.Errorf("canonical block %d (td %v) not chosen over reorged block %d (td %v)",
+			canonical.Number, chain.tds[canonical.Hash()], reorged.Number, chain.tds[reorged.Hash()])
+	}
+
+	if reorg, err = forkChoice.ReorgNeeded(canonical, reorged); err != nil {
+		return err
+	}
+
+	if reorg {
+		return fmt.Errorf("reorged block %d (td %v) chosen over canonical block %d (td %v)",
+			reorged.Number, chain.tds[reorged.Hash()], canonical.Number, chain.tds[canonical.Hash()])
+	}
+
+	return nil
+}
+
+// Tests that the fork choice still settles on the canonical branch of the fork
+// incidents observed on live networks, see testdata/forkincidents/README.md.
+func TestForkIncidents(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob(filepath.Join("testdata", "forkincidents", "*.json"))
+	require.NoError(t, err)
+
+	for _, file := range files {
+		file := file
+
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			t.Parallel()
+
+			blob, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			incident, err := loadForkIncident(blob)
+			require.NoError(t, err)
+			require.NoError(t, checkForkIncident(incident))
+		})
+	}
+}
+
+// newForkIncidentEngine creates an engine over a chain of two validators, the
+// in-turn one sealing the canonical blocks 1 and 2 while the other seals the
+// reorged blocks 1 and 2, which are only retained in the database.
+func newForkIncidentEngine(t *testing.T) (*Bor, *healChain, []*types.Header) {
+	t.Helper()
+
+	var (
+		chain = &healChain{config: &params.ChainConfig{
+			ChainID: big.NewInt(1),
+			Bor: &params.BorConfig{
+				Sprint: map[string]uint64{"0": 4},
+				Period: map[string]uint64{"0": 2},
+			},
+		}}
+		keys       = make(map[common.Address]*ecdsa.PrivateKey)
+		validators []*valset.Validator
+		engine     = New(chain.config, rawdb.NewMemoryDatabase(), nil, nil, nil, nil, false)
+	)
+
+	for i := 0; i < 2; i++ {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		keys[address] = key
+		validators = append(validators, valset.NewValidator(address, 10))
+	}
+
+	genesis := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1)}
+	chain.headers = append(chain.headers, genesis)
+
+	snap := newSnapshot(chain.config, engine.signatures, 0, genesis.Hash(), validators)
+	require.NoError(t, snap.store(engine.db))
+	rawdb.WriteHeader(engine.db, genesis)
+	rawdb.WriteTd(engine.db, genesis.Hash(), 0, genesis.Difficulty)
+
+	seal := func(parent *types.Header, signer common.Address) *types.Header {
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, big.NewInt(1)),
+			Difficulty: new(big.Int).SetUint64(Difficulty(snap.ValidatorSet, signer)),
+			Time:       parent.Time + 2,
+			Extra:      make([]byte, types.ExtraVanityLength+types.ExtraSealLength),
+		}
+
+		sig, err := crypto.Sign(SealHash(header, chain.config.Bor).Bytes(), keys[signer])
+		require.NoError(t, err)
+
+		copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sig)
+
+		return header
+	}
+
+	var (
+		proposer = snap.ValidatorSet.GetProposer().Address
+		other    = validators[0].Address
+		reorged  []*types.Header
+	)
+
+	if other == proposer {
+		other = validators[1].Address
+	}
+
+	parent := genesis
+
+	for i := 0; i < 2; i++ {
+		canonical := seal(chain.headers[i], proposer)
+		rawdb.WriteHeader(engine.db, canonical)
+		chain.headers = append(chain.headers, canonical)
+
+		header := seal(parent, other)
+		rawdb.WriteHeader(engine.db, header)
+		reorged = append(reorged, header)
+		parent = header
+	}
+
+	return engine, chain, reorged
+}
+
+// Tests that a fork is exported with what it takes to replay it offline, and
+// that replaying it checks the fork choice.
+func TestExportForkIncident(t *testing.T) {
+	t.Parallel()
+
+	engine, chain, reorged := newForkIncidentEngine(t)
+
+	_, err := engine.ExportForkIncident(chain, chain.headers[2].Hash())
+	require.ErrorIs(t, err, errCanonicalBlock)
+
+	_, err = engine.ExportForkIncident(chain, common.Hash{0x1})
+	require.ErrorIs(t, err, errUnknownBlock)
+
+	incident, err := engine.ExportForkIncident(chain, reorged[1].Hash())
+	require.NoError(t, err)
+	require.Equal(t, chain.headers[0].Hash(), incident.Ancestor.Hash)
+	require.Equal(t, big.NewInt(1), incident.AncestorTd)
+	require.Equal(t, chain.headers[1:], incident.Canonical)
+	require.Equal(t, reorged, incident.Reorged)
+
+	// The exported incident replays the same once checked in
+	blob, err := json.Marshal(incident)
+	require.NoError(t, err)
+
+	incident, err = loadForkIncident(blob)
+	require.NoError(t, err)
+	require.NoError(t, checkForkIncident(incident))
+
+	// The out of turn branch has the lower total difficulty, so a fork choice
+	// settling on it is a regression
+	incident.Canonical, incident.Reorged = incident.Reorged, incident.Canonical
+	require.ErrorContains(t, checkForkIncident(incident), "not chosen over reorged block")
+
+	// Headers sealed with a difficulty other than the one of their turn are
+	// rejected before reaching the fork choice
+	incident.Canonical, incident.Reorged = incident.Reorged, incident.Canonical
+	incident.Reorged = []*types.Header{types.CopyHeader(reorged[0])}
+	incident.Reorged[0].Difficulty = big.NewInt(2)
+	require.ErrorContains(t, checkForkIncident(incident), "reorged branch")
+}
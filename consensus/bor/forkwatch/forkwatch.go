@@ -0,0 +1,265 @@
+// Package forkwatch implements a monitor comparing the local fork schedule
+// against a signed manifest published for the network, so that validators
+// running a binary or chain config lacking an upcoming fork find out well
+// before it splits them off the chain.
+package forkwatch
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// requestTimeout is the maximum time fetching the manifest may take.
+	requestTimeout = 30 * time.Second
+
+	// maxManifestSize is the maximum size of the published manifest.
+	maxManifestSize = 1024 * 1024
+)
+
+var (
+	// ErrInvalidSignature is returned if the manifest isn't signed by the
+	// configured signer.
+	ErrInvalidSignature = errors.New("manifest not signed by the trusted signer")
+
+	// ErrChainMismatch is returned if the manifest is published for another chain.
+	ErrChainMismatch = errors.New("manifest published for another chain")
+)
+
+// Manifest is the fork schedule of a network as published by its maintainers.
+type Manifest struct {
+	ChainID uint64            `json:"chainId"`
+	Forks   map[string]uint64 `json:"forks"` // Fork blocks keyed by their chain config field, e.g. "ahmedabadBlock"
+}
+
+// signedManifest is the encoding the manifest is published in. The signature
+// is over the keccak256 hash of the manifest JSON with insignificant whitespace
+// removed, so that it doesn't depend on how the document is indented.
+type signedManifest struct {
+	Manifest  json.RawMessage `json:"manifest"`
+	Signature hexutil.Bytes   `json:"signature"`
+}
+
+// SignManifest encodes and signs a manifest for publishing.
+func SignManifest(manifest *Manifest, key *ecdsa.PrivateKey) ([]byte, error) {
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := crypto.Sign(manifestHash(raw), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(&signedManifest{Manifest: raw, Signature: sig}, "", "  ")
+}
+
+// VerifyManifest decodes a published manifest, checking it is signed by the
+// given signer.
+func VerifyManifest(blob []byte, signer common.Address) (*Manifest, error) {
+	var signed signedManifest
+	if err := json.Unmarshal(blob, &signed); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	pubkey, err := crypto.SigToPub(manifestHash(signed.Manifest), signed.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	if recovered := crypto.PubkeyToAddress(*pubkey); recovered != signer {
+		return nil, fmt.Errorf("%w: signed by %v", ErrInvalidSignature, recovered)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(signed.Manifest, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// manifestHash returns the hash the signature of a raw manifest is over.
+func manifestHash(raw []byte) []byte {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return crypto.Keccak256(raw)
+	}
+
+	return crypto.Keccak256(compact.Bytes())
+}
+
+// Incompatibility is a fork of the manifest the local node doesn't activate at
+// the same block as the network.
+type Incompatibility struct {
+	Name  string   // Chain config field of the fork
+	Block uint64   // Block the network activates the fork at
+	Local *big.Int // Block the local config activates the fork at, nil if never
+	Known bool     // Whether this binary implements the fork at all
+}
+
+// String implements the stringer interface.
+func (i Incompatibility) String() string {
+	switch {
+	case !i.Known:
+		return fmt.Sprintf("fork %s at block %d is not implemented by this binary", i.Name, i.Block)
+	case i.Local == nil:
+		return fmt.Sprintf("fork %s at block %d is not scheduled by the local chain config", i.Name, i.Block)
+	default:
+		return fmt.Sprintf("fork %s at block %d is scheduled at block %v by the local chain config", i.Name, i.Block, i.Local)
+	}
+}
+
+// Compare returns the forks of the manifest the given chain config doesn't
+// activate at the same block, ordered by the block the network activates them
+// at. Local forks missing from the manifest aren't reported, as manifests may
+// only list the forks still relevant to the network.
+func Compare(config *params.ChainConfig, manifest *Manifest) []Incompatibility {
+	local := localForks(config)
+
+	var issues []Incompatibility
+
+	for name, block := range manifest.Forks {
+		scheduled, known := local[name]
+
+		if known && scheduled != nil && scheduled.IsUint64() && scheduled.Uint64() == block {
+			continue
+		}
+
+		issues = append(issues, Incompatibility{Name: name, Block: block, Local: scheduled, Known: known})
+	}
+
+	slices.SortFunc(issues, func(a, b Incompatibility) int {
+		if a.Block != b.Block {
+			return cmp.Compare(a.Block, b.Block)
+		}
+
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return issues
+}
+
+// localForks returns the block based forks implemented by this binary, keyed
+// by their chain config field, along with the block the given config activates
+// them at.
+func localForks(config *params.ChainConfig) map[string]*big.Int {
+	forks := map[string]*big.Int{
+		"homesteadBlock":      config.HomesteadBlock,
+		"daoForkBlock":        config.DAOForkBlock,
+		"eip150Block":         config.EIP150Block,
+		"eip155Block":         config.EIP155Block,
+		"eip158Block":         config.EIP158Block,
+		"byzantiumBlock":      config.ByzantiumBlock,
+		"constantinopleBlock": config.ConstantinopleBlock,
+		"petersburgBlock":     config.PetersburgBlock,
+		"istanbulBlock":       config.IstanbulBlock,
+		"muirGlacierBlock":    config.MuirGlacierBlock,
+		"berlinBlock":         config.BerlinBlock,
+		"londonBlock":         config.LondonBlock,
+		"arrowGlacierBlock":   config.ArrowGlacierBlock,
+		"grayGlacierBlock":    config.GrayGlacierBlock,
+		"mergeNetsplitBlock":  config.MergeNetsplitBlock,
+		"shanghaiBlock":       config.ShanghaiBlock,
+		"cancunBlock":         config.CancunBlock,
+		"pragueBlock":         config.PragueBlock,
+		"verkleBlock":         config.VerkleBlock,
+	}
+
+	// Bor forks are known to the binary even if the chain doesn't run bor
+	bor := config.Bor
+	if bor == nil {
+		bor = new(params.BorConfig)
+	}
+
+	for _, fork := range bor.Forks() {
+		forks[fork.Name] = fork.Block
+	}
+
+	return forks
+}
+
+// Watcher fetches the manifest of the network and compares it against the
+// local chain config.
+type Watcher struct {
+	url    string
+	signer common.Address
+	config *params.ChainConfig
+	client *http.Client
+}
+
+// NewWatcher creates a fork watcher, or nil if no manifest URL is configured.
+func NewWatcher(url string, signer common.Address, config *params.ChainConfig) *Watcher {
+	if url == "" {
+		return nil
+	}
+
+	return &Watcher{
+		url:    url,
+		signer: signer,
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Fetch downloads the manifest and verifies its signature and chain.
+func (w *Watcher) Fetch(ctx context.Context) (*Manifest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("manifest request failed: %s", res.Status)
+	}
+
+	blob, err := io.ReadAll(io.LimitReader(res.Body, maxManifestSize))
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := VerifyManifest(blob, w.signer)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.config.ChainID == nil || !w.config.ChainID.IsUint64() || w.config.ChainID.Uint64() != manifest.ChainID {
+		return nil, fmt.Errorf("%w: manifest %d, local %v", ErrChainMismatch, manifest.ChainID, w.config.ChainID)
+	}
+
+	return manifest, nil
+}
+
+// Check fetches the manifest and returns the forks the local node doesn't
+// activate at the same block as the network.
+func (w *Watcher) Check(ctx context.Context) ([]Incompatibility, error) {
+	manifest, err := w.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compare(w.config, manifest), nil
+}
@@ -0,0 +1,131 @@
+package forkwatch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestVerifyManifest(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+
+	manifest := &Manifest{ChainID: 137, Forks: map[string]uint64{"ahmedabadBlock": 100}}
+
+	blob, err := SignManifest(manifest, key)
+	require.NoError(t, err)
+
+	verified, err := VerifyManifest(blob, crypto.PubkeyToAddress(key.PublicKey))
+	require.NoError(t, err)
+	require.Equal(t, manifest, verified)
+
+	_, err = VerifyManifest(blob, crypto.PubkeyToAddress(other.PublicKey))
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	// Reindenting keeps the signature valid, tampering with the manifest doesn't
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, blob, "", "\t"))
+
+	_, err = VerifyManifest(indented.Bytes(), crypto.PubkeyToAddress(key.PublicKey))
+	require.NoError(t, err)
+
+	tampered := bytes.Replace(blob, []byte(`"ahmedabadBlock": 100`), []byte(`"ahmedabadBlock": 200`), 1)
+	require.NotEqual(t, blob, tampered)
+
+	_, err = VerifyManifest(tampered, crypto.PubkeyToAddress(key.PublicKey))
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	config := &params.ChainConfig{
+		ChainID:     big.NewInt(137),
+		LondonBlock: big.NewInt(10),
+		Bor: &params.BorConfig{
+			AhmedabadBlock:    big.NewInt(100),
+			MilestoneRefBlock: big.NewInt(300),
+		},
+	}
+
+	issues := Compare(config, &Manifest{
+		ChainID: 137,
+		Forks: map[string]uint64{
+			"londonBlock":       10,
+			"ahmedabadBlock":    100,
+			"milestoneRefBlock": 200,
+			"mixDigestBlock":    400,
+			"futureBlock":       500,
+		},
+	})
+
+	require.Equal(t, []Incompatibility{
+		{Name: "milestoneRefBlock", Block: 200, Local: big.NewInt(300), Known: true},
+		{Name: "mixDigestBlock", Block: 400, Known: true},
+		{Name: "futureBlock", Block: 500},
+	}, issues)
+
+	require.Equal(t, "fork milestoneRefBlock at block 200 is scheduled at block 300 by the local chain config", issues[0].String())
+	require.Equal(t, "fork mixDigestBlock at block 400 is not scheduled by the local chain config", issues[1].String())
+	require.Equal(t, "fork futureBlock at block 500 is not implemented by this binary", issues[2].String())
+
+	// Bor forks are missing from the config of other engines
+	issues = Compare(&params.ChainConfig{ChainID: big.NewInt(1)}, &Manifest{ChainID: 1, Forks: map[string]uint64{"ahmedabadBlock": 100}})
+	require.Equal(t, []Incompatibility{{Name: "ahmedabadBlock", Block: 100, Known: true}}, issues)
+}
+
+func TestWatcherCheck(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, NewWatcher("", [20]byte{}, params.TestChainConfig))
+
+	key, _ := crypto.GenerateKey()
+
+	var manifest *Manifest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		blob, err := SignManifest(manifest, key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		_, _ = w.Write(blob)
+	}))
+	defer server.Close()
+
+	config := &params.ChainConfig{
+		ChainID: big.NewInt(137),
+		Bor:     &params.BorConfig{AhmedabadBlock: big.NewInt(100)},
+	}
+	watcher := NewWatcher(server.URL, crypto.PubkeyToAddress(key.PublicKey), config)
+
+	manifest = &Manifest{ChainID: 137, Forks: map[string]uint64{"ahmedabadBlock": 100}}
+
+	issues, err := watcher.Check(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, issues)
+
+	manifest = &Manifest{ChainID: 137, Forks: map[string]uint64{"ahmedabadBlock": 100, "futureBlock": 200}}
+
+	issues, err = watcher.Check(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []Incompatibility{{Name: "futureBlock", Block: 200}}, issues)
+
+	// Manifests of other chains are rejected rather than compared
+	manifest = &Manifest{ChainID: 80002, Forks: map[string]uint64{"futureBlock": 200}}
+
+	_, err = watcher.Check(context.Background())
+	require.ErrorIs(t, err, ErrChainMismatch)
+}
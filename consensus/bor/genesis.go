@@ -1,6 +1,7 @@
 package bor
 
 import (
+	"context"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -13,5 +14,5 @@ import (
 //go:generate mockgen -destination=./genesis_contract_mock.go -package=bor . GenesisContract
 type GenesisContract interface {
 	CommitState(event *clerk.EventRecordWithTime, state *state.StateDB, header *types.Header, chCtx statefull.ChainContext) (uint64, error)
-	LastStateId(state *state.StateDB, number uint64, hash common.Hash) (*big.Int, error)
+	LastStateId(ctx context.Context, state *state.StateDB, number uint64, hash common.Hash) (*big.Int, error)
 }
@@ -0,0 +1,100 @@
+package bor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// errInvalidNonce is returned if a block's nonce is non-zero.
+var errInvalidNonce = errors.New("non-zero nonce")
+
+// InvalidHeaderFieldError is returned if a header carries an unexpected value
+// in one of the Ethereum header fields Bor doesn't use. It matches the error
+// of the field, e.g. errInvalidMixDigest, with errors.Is.
+type InvalidHeaderFieldError struct {
+	Number uint64
+	Field  string
+	Have   string
+	Want   string
+
+	err error
+}
+
+func (e *InvalidHeaderFieldError) Error() string {
+	return fmt.Sprintf("invalid %s of block %d: have %s, want %s", e.Field, e.Number, e.Have, e.Want)
+}
+
+func (e *InvalidHeaderFieldError) Unwrap() error {
+	return e.err
+}
+
+// MixDigestFn computes the consensus data carried in the mix digest of the
+// given header once the field is repurposed by the mix digest fork. The
+// header's parent is available through chain.
+type MixDigestFn func(chain consensus.ChainHeaderReader, header *types.Header) (common.Hash, error)
+
+// SetMixDigestFn sets the function computing the mix digest of the headers
+// after the mix digest fork. Without one, the field stays empty after the fork.
+func (c *Bor) SetMixDigestFn(fn MixDigestFn) {
+	c.mixDigestFn = fn
+}
+
+// mixDigest returns the mix digest the given header is expected to carry:
+// the empty hash, unless the field is repurposed at the header's block.
+func (c *Bor) mixDigest(chain consensus.ChainHeaderReader, header *types.Header) (common.Hash, error) {
+	if !c.config.IsMixDigest(header.Number) || c.mixDigestFn == nil {
+		return common.Hash{}, nil
+	}
+
+	return c.mixDigestFn(chain, header)
+}
+
+// verifyUnusedFields checks that the Ethereum header fields which are
+// meaningless in Bor carry their constant values, with the exception of the
+// mix digest once it's repurposed.
+func (c *Bor) verifyUnusedFields(chain consensus.ChainHeaderReader, header *types.Header) error {
+	number := header.Number.Uint64()
+
+	mixDigest, err := c.mixDigest(chain, header)
+	if err != nil {
+		return err
+	}
+
+	if header.MixDigest != mixDigest {
+		return &InvalidHeaderFieldError{
+			Number: number,
+			Field:  "mix digest",
+			Have:   header.MixDigest.Hex(),
+			Want:   mixDigest.Hex(),
+			err:    errInvalidMixDigest,
+		}
+	}
+
+	// Uncles are meaningless in PoA
+	if header.UncleHash != uncleHash {
+		return &InvalidHeaderFieldError{
+			Number: number,
+			Field:  "uncle hash",
+			Have:   header.UncleHash.Hex(),
+			Want:   uncleHash.Hex(),
+			err:    errInvalidUncleHash,
+		}
+	}
+
+	if header.Nonce != (types.BlockNonce{}) {
+		return &InvalidHeaderFieldError{
+			Number: number,
+			Field:  "nonce",
+			Have:   hexutil.Encode(header.Nonce[:]),
+			Want:   hexutil.Encode(make([]byte, len(header.Nonce))),
+			err:    errInvalidNonce,
+		}
+	}
+
+	return nil
+}
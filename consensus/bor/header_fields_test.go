@@ -0,0 +1,72 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestVerifyUnusedFields(t *testing.T) {
+	t.Parallel()
+
+	borConfig := &params.BorConfig{
+		MixDigestBlock: big.NewInt(100),
+	}
+
+	b := &Bor{
+		chainConfig: &params.ChainConfig{Bor: borConfig},
+		config:      borConfig,
+	}
+
+	header := func(number int64) *types.Header {
+		return &types.Header{
+			Number:    big.NewInt(number),
+			UncleHash: uncleHash,
+		}
+	}
+
+	require.NoError(t, b.verifyUnusedFields(nil, header(50)))
+
+	var fieldErr *InvalidHeaderFieldError
+
+	h := header(50)
+	h.MixDigest = common.Hash{0x1}
+	require.ErrorIs(t, b.verifyUnusedFields(nil, h), errInvalidMixDigest)
+	require.ErrorAs(t, b.verifyUnusedFields(nil, h), &fieldErr)
+	require.Equal(t, "mix digest", fieldErr.Field)
+
+	h = header(50)
+	h.UncleHash = common.Hash{0x1}
+	require.ErrorIs(t, b.verifyUnusedFields(nil, h), errInvalidUncleHash)
+
+	h = header(50)
+	h.Nonce = types.EncodeNonce(1)
+	require.ErrorIs(t, b.verifyUnusedFields(nil, h), errInvalidNonce)
+
+	// Without a mix digest function the field stays empty after the fork
+	h = header(150)
+	h.MixDigest = common.Hash{0x1}
+	require.ErrorIs(t, b.verifyUnusedFields(nil, h), errInvalidMixDigest)
+
+	// After the fork the mix digest must carry the repurposed data
+	b.SetMixDigestFn(func(chain consensus.ChainHeaderReader, header *types.Header) (common.Hash, error) {
+		return common.BigToHash(header.Number), nil
+	})
+
+	h = header(150)
+	h.MixDigest = common.BigToHash(big.NewInt(150))
+	require.NoError(t, b.verifyUnusedFields(nil, h))
+
+	h.MixDigest = common.Hash{}
+	require.ErrorIs(t, b.verifyUnusedFields(nil, h), errInvalidMixDigest)
+
+	// Before the fork it's still required to be empty
+	h = header(50)
+	require.NoError(t, b.verifyUnusedFields(nil, h))
+}
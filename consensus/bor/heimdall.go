@@ -9,6 +9,11 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
 )
 
+// StateSyncSource serves the state-sync events committed at the start of sprints.
+type StateSyncSource interface {
+	StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error)
+}
+
 //go:generate mockgen -destination=../../tests/bor/mocks/IHeimdallClient.go -package=mocks . IHeimdallClient
 type IHeimdallClient interface {
 	StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error)
@@ -1,6 +1,7 @@
 package heimdall
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,10 +11,12 @@ import (
 	"net/url"
 	"path"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/evidence"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
 	"github.com/ethereum/go-ethereum/log"
@@ -51,6 +54,7 @@ type HeimdallClient struct {
 	urlString string
 	client    http.Client
 	closeCh   chan struct{}
+	closeOnce sync.Once
 }
 
 type Request struct {
@@ -84,6 +88,8 @@ const (
 	fetchMilestoneID        = "/milestone/ID/%s"
 
 	fetchSpanFormat = "bor/span/%d"
+
+	submitDoubleSignEvidence = "bor/evidence/double-sign"
 )
 
 func (h *HeimdallClient) StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
@@ -115,7 +121,20 @@ func (h *HeimdallClient) StateSyncEvents(ctx context.Context, fromID uint64, to
 			break
 		}
 
-		fromID += uint64(stateFetchLimit)
+		// Resume after the highest id received, pages aren't guaranteed to be
+		// contiguous. Stop if the page didn't move forward.
+		next := fromID
+		for _, record := range response.Result {
+			if record.ID >= next {
+				next = record.ID + 1
+			}
+		}
+
+		if next == fromID {
+			break
+		}
+
+		fromID = next
 	}
 
 	sort.SliceStable(eventRecords, func(i, j int) bool {
@@ -272,6 +291,18 @@ func (h *HeimdallClient) FetchMilestoneID(ctx context.Context, milestoneID strin
 
 // FetchWithRetry returns data from heimdall with retry
 func FetchWithRetry[T any](ctx context.Context, client http.Client, url *url.URL, closeCh chan struct{}) (*T, error) {
+	// abort the request in flight too, not only the retries, once the client is closed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	// request data once
 	request := &Request{client: client, url: url, start: time.Now()}
 	result, err := Fetch[T](ctx, request)
@@ -280,6 +311,10 @@ func FetchWithRetry[T any](ctx context.Context, client http.Client, url *url.URL
 		return result, nil
 	}
 
+	if err := interrupted(ctx, closeCh); err != nil {
+		return nil, err
+	}
+
 	// 503 (Service Unavailable) is thrown when an endpoint isn't activated
 	// yet in heimdall. E.g. when the hardfork hasn't hit yet but heimdall
 	// is upgraded.
@@ -318,6 +353,12 @@ retryLoop:
 			request = &Request{client: client, url: url, start: time.Now()}
 			result, err = Fetch[T](ctx, request)
 
+			if err != nil {
+				if err := interrupted(ctx, closeCh); err != nil {
+					return nil, err
+				}
+			}
+
 			if errors.Is(err, ErrServiceUnavailable) {
 				log.Debug("Heimdall service unavailable at the moment", "path", url.Path, "error", err)
 				return nil, err
@@ -336,6 +377,17 @@ retryLoop:
 	}
 }
 
+// interrupted returns the error a request aborted by closing the client or by
+// its context should fail with, or nil if it was not aborted.
+func interrupted(ctx context.Context, closeCh chan struct{}) error {
+	select {
+	case <-closeCh:
+		return ErrShutdownDetected
+	default:
+		return ctx.Err()
+	}
+}
+
 // Fetch returns data from heimdall
 func Fetch[T any](ctx context.Context, request *Request) (*T, error) {
 	isSuccessful := false
@@ -476,8 +528,47 @@ func internalFetchWithTimeout(ctx context.Context, client http.Client, url *url.
 	return internalFetch(ctx, client, url)
 }
 
+// SubmitDoubleSignEvidence posts the evidence of a double signing validator to
+// heimdall for slashing.
+func (h *HeimdallClient) SubmitDoubleSignEvidence(ctx context.Context, ev *evidence.DoubleSign) error {
+	u, err := makeURL(h.urlString, submitDoubleSignEvidence, "")
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, apiHeimdallTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%w: response code %d", ErrNotSuccessfulResponse, res.StatusCode)
+	}
+
+	return nil
+}
+
 // Close sends a signal to stop the running process
 func (h *HeimdallClient) Close() {
-	close(h.closeCh)
-	h.client.CloseIdleConnections()
+	h.closeOnce.Do(func() {
+		close(h.closeCh)
+		h.client.CloseIdleConnections()
+	})
 }
@@ -299,6 +299,26 @@ func TestFetchShutdown(t *testing.T) {
 	_, err = client.FetchCheckpoint(context.Background(), -1)
 	require.Equal(t, ErrShutdownDetected.Error(), err.Error(), "expect the function error to be a shutdown detected error")
 
+	// Case4 - Testing close while a request is in flight: the server holds the request until
+	// the client gives up on it. Closing the client should abort the request itself rather
+	// than waiting for the http timeout. Expect shutdown detected error.
+	handler.handleFetchCheckpoint = func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}
+
+	client = NewHeimdallClient(fmt.Sprintf("http://localhost:%d", port))
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		client.Close()
+		client.Close() // closing twice must not panic
+	}()
+
+	start := time.Now()
+	_, err = client.FetchCheckpoint(context.Background(), -1)
+	require.Equal(t, ErrShutdownDetected.Error(), err.Error(), "expect the function error to be a shutdown detected error")
+	require.Less(t, time.Since(start), apiHeimdallTimeout, "expect the request to be aborted before the http timeout")
+
 	// Shutdown the server
 	err = srv.Shutdown(context.TODO())
 	require.NoError(t, err, "expect no error in shutting down mock heimdall server")
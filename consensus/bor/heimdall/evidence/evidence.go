@@ -0,0 +1,16 @@
+package evidence
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// DoubleSign is the proof of a validator sealing two different blocks at the
+// same height, submitted to heimdall for slashing.
+type DoubleSign struct {
+	Number     uint64         `json:"number"`
+	Signer     common.Address `json:"signer"`
+	First      *types.Header  `json:"first"`
+	Second     *types.Header  `json:"second"`
+	DetectedAt uint64         `json:"detected_at"` // Unix time the equivocation was detected
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/bor/abi"
 	"github.com/ethereum/go-ethereum/consensus/bor/api"
+	"github.com/ethereum/go-ethereum/consensus/bor/contract"
 	"github.com/ethereum/go-ethereum/consensus/bor/valset"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -39,8 +40,6 @@ func TestGetCurrentValidatorsByBlockNrOrHash(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
-					gomock.Any(),
-					gomock.Any(),
 				).Return(common.FromHex("0x0000000000000000000000000000000000000000000000000000000000000000"), nil).AnyTimes()
 			},
 			mockAbiExpected: func(mockAbi *abi.MockABI) {
@@ -54,17 +53,17 @@ func TestGetCurrentValidatorsByBlockNrOrHash(t *testing.T) {
 				).DoAndReturn(func(v interface{}, name string, data []byte) error {
 					defer func() { callCount++ }()
 
-					resp, _ := v.(*contractValidator)
+					resp, _ := v.(*contract.ValidatorSetValidator)
 
 					if callCount == 0 {
-						*resp = contractValidator{
+						*resp = contract.ValidatorSetValidator{
 							Id:     big.NewInt(1),
 							Signer: common.HexToAddress("0x1111111111111111111111111111111111111111"),
 							Power:  big.NewInt(10),
 						}
 					}
 					if callCount == 1 {
-						*resp = contractValidator{
+						*resp = contract.ValidatorSetValidator{
 							Id:     big.NewInt(2),
 							Signer: common.HexToAddress("0x2222222222222222222222222222222222222222"),
 							Power:  big.NewInt(15),
@@ -95,8 +94,6 @@ func TestGetCurrentValidatorsByBlockNrOrHash(t *testing.T) {
 					gomock.Any(),
 					gomock.Any(),
 					gomock.Any(),
-					gomock.Any(),
-					gomock.Any(),
 				).Return(common.FromHex("0x0000000000000000000000000000000000000000000000000000000000000000"), nil).AnyTimes()
 			},
 			mockAbiExpected: func(mockAbi *abi.MockABI) {
@@ -202,3 +199,18 @@ func basicMocks(mockAbi *abi.MockABI) {
 		return nil
 	}).AnyTimes()
 }
+
+func TestLimitProducers(t *testing.T) {
+	t.Parallel()
+
+	producers := []valset.Validator{
+		{ID: 1, Address: common.HexToAddress("0x1"), VotingPower: 100},
+		{ID: 2, Address: common.HexToAddress("0x2"), VotingPower: 200},
+		{ID: 3, Address: common.HexToAddress("0x3"), VotingPower: 300},
+	}
+
+	assert.Equal(t, producers, LimitProducers(producers, 0), "a zero count doesn't cap the producers")
+	assert.Equal(t, producers, LimitProducers(producers, 3))
+	assert.Equal(t, producers, LimitProducers(producers, 10))
+	assert.Equal(t, producers[:2], LimitProducers(producers, 2), "heimdall's order is kept")
+}
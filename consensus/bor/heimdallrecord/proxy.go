@@ -0,0 +1,212 @@
+package heimdallrecord
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// proxyTimeout is the maximum time a request forwarded to heimdall may take.
+	proxyTimeout = time.Minute
+
+	// maxResponseSize is the maximum size of a recorded heimdall response.
+	maxResponseSize = 128 * 1024 * 1024
+)
+
+// exchange is a recorded request to the heimdall REST API and its response.
+type exchange struct {
+	Request string `json:"request"` // Path and query of the request
+	Status  int    `json:"status"`
+	Body    string `json:"body,omitempty"`
+}
+
+// Proxy is an HTTP proxy in front of the heimdall REST API, recording the
+// responses to the requests of the nodes using it. Unlike a Recorder it records
+// the raw responses, so that a recording captured in production by pointing
+// any node at the proxy replays through the full heimdall client of the node
+// under test, including its decoding and error handling.
+//
+// A recording is a file of JSON lines, one per request:
+//
+//	{"request":"/bor/span/12","status":200,"body":"{...}"}
+type Proxy struct {
+	target *url.URL
+	client *http.Client
+
+	file *os.File
+	enc  *json.Encoder
+	lock sync.Mutex
+}
+
+// NewProxy creates a proxy forwarding requests to the heimdall REST API at
+// target and recording them into the file at path. An existing recording is
+// appended to.
+func NewProxy(target string, path string) (*Proxy, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Proxy{
+		target: u,
+		client: &http.Client{Timeout: proxyTimeout},
+		file:   file,
+		enc:    json.NewEncoder(file),
+	}, nil
+}
+
+// ServeHTTP forwards a request to heimdall and relays its response. Only
+// queries are recorded, submissions (e.g. of evidence) are forwarded as is.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u := *p.target
+	u.Path = strings.TrimSuffix(u.Path, "/") + r.URL.Path
+	u.RawQuery = r.URL.RawQuery
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, u.String(), r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	req.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		// Heimdall being unreachable is an outcome worth replaying as well
+		p.record(r, http.StatusBadGateway, err.Error())
+		http.Error(w, err.Error(), http.StatusBadGateway)
+
+		return
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	p.record(r, res.StatusCode, string(body))
+
+	if contentType := res.Header.Get("Content-Type"); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+
+	w.WriteHeader(res.StatusCode)
+
+	if _, err := w.Write(body); err != nil {
+		log.Debug("Failed to relay heimdall response", "request", r.URL, "err", err)
+	}
+}
+
+// record appends the response to a query to the recording.
+func (p *Proxy) record(r *http.Request, status int, body string) {
+	if r.Method != http.MethodGet {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if err := p.enc.Encode(&exchange{Request: r.URL.RequestURI(), Status: status, Body: body}); err != nil {
+		log.Warn("Failed to record heimdall response", "request", r.URL, "err", err)
+	}
+}
+
+// Close closes the recording.
+func (p *Proxy) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.file.Close()
+}
+
+// HTTPReplayer is an HTTP server serving the responses of a proxy recording in
+// place of the heimdall REST API, typically wrapped in an httptest server by
+// end-to-end tests. Requests recorded several times are answered with the
+// recorded responses in order, the last one being repeated once they are
+// exhausted, and requests missing in the recording fail with 404.
+type HTTPReplayer struct {
+	exchanges map[string][]*exchange
+	lock      sync.Mutex
+}
+
+// NewHTTPReplayer creates a server replaying the proxy recording in the file at
+// path.
+func NewHTTPReplayer(path string) (*HTTPReplayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := &HTTPReplayer{
+		exchanges: make(map[string][]*exchange),
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 2*maxResponseSize)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		e := new(exchange)
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			return nil, fmt.Errorf("invalid heimdall recording %s, line %d: %w", path, line, err)
+		}
+
+		r.exchanges[e.Request] = append(r.exchanges[e.Request], e)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// ServeHTTP answers a request with its next recorded response.
+func (r *HTTPReplayer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	request := req.URL.RequestURI()
+
+	r.lock.Lock()
+
+	exchanges := r.exchanges[request]
+	if len(exchanges) == 0 {
+		r.lock.Unlock()
+		http.Error(w, fmt.Sprintf("%v: %s", ErrNotRecorded, request), http.StatusNotFound)
+
+		return
+	}
+
+	e := exchanges[0]
+	if len(exchanges) > 1 {
+		r.exchanges[request] = exchanges[1:]
+	}
+
+	r.lock.Unlock()
+
+	if e.Status == http.StatusOK {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
+	w.WriteHeader(e.Status)
+	_, _ = io.WriteString(w, e.Body)
+}
@@ -0,0 +1,90 @@
+package heimdallrecord
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+)
+
+func TestProxyRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx   = context.Background()
+		path  = filepath.Join(t.TempDir(), "heimdall.jsonl")
+		count atomic.Int64
+	)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bor/span/1":
+			fmt.Fprint(w, `{"height":"10","result":{"span_id":1,"start_block":256,"end_block":6655,"bor_chain_id":"15001"}}`)
+		case "/milestone/count":
+			fmt.Fprintf(w, `{"height":"10","result":{"count":%d}}`, count.Add(1))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer upstream.Close()
+
+	proxy, err := NewProxy(upstream.URL, path)
+	require.NoError(t, err)
+
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+
+	// Record a live run through the heimdall client of the node
+	client := heimdall.NewHeimdallClient(proxyServer.URL)
+
+	sp, err := client.Span(ctx, 1)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.FetchMilestoneCount(ctx)
+		require.NoError(t, err)
+	}
+
+	_, err = client.FetchMilestone(ctx)
+	require.ErrorIs(t, err, heimdall.ErrServiceUnavailable)
+
+	client.Close()
+	require.NoError(t, proxy.Close())
+
+	// And replay it without heimdall
+	replayer, err := NewHTTPReplayer(path)
+	require.NoError(t, err)
+
+	replayServer := httptest.NewServer(replayer)
+	defer replayServer.Close()
+
+	client = heimdall.NewHeimdallClient(replayServer.URL)
+	defer client.Close()
+
+	replayedSpan, err := client.Span(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, sp, replayedSpan)
+
+	// Repeated requests are replayed in order, repeating the last response
+	for _, expected := range []int64{1, 2, 2} {
+		count, err := client.FetchMilestoneCount(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, count)
+	}
+
+	_, err = client.FetchMilestone(ctx)
+	require.ErrorIs(t, err, heimdall.ErrServiceUnavailable)
+
+	// Requests missing in the recording fail
+	res, err := http.Get(replayServer.URL + "/bor/span/2")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+	require.NoError(t, res.Body.Close())
+}
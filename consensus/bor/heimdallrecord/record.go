@@ -0,0 +1,204 @@
+// Package heimdallrecord implements a heimdall client recording the responses
+// of a live heimdall service to a file, and one replaying such a recording, so
+// that integration tests and local devnets can run deterministically without a
+// heimdall service.
+//
+// A recording is a file of JSON lines, one per call:
+//
+//	{"call":"Span(12)","result":{...}}
+//	{"call":"FetchMilestone()","error":"service unavailable"}
+//
+// The Proxy and HTTPReplayer do the same at the level of the heimdall REST API,
+// so that recordings can be captured from any node and replayed through the
+// heimdall client of the node under test.
+package heimdallrecord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/evidence"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errNoEvidenceReporter is returned when submitting evidence through a recorder
+// whose client can't submit evidence.
+var errNoEvidenceReporter = errors.New("recorded heimdall client can't submit evidence")
+
+// entry is a recorded call and its outcome.
+type entry struct {
+	Call   string          `json:"call"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// call formats a call of the given method with the given arguments into the
+// key it's recorded under.
+func call(method string, args ...any) string {
+	key := method + "("
+
+	for i, arg := range args {
+		if i > 0 {
+			key += ","
+		}
+
+		key += fmt.Sprint(arg)
+	}
+
+	return key + ")"
+}
+
+// Recorder is a heimdall client forwarding all calls to another client and
+// appending them, along with their responses, to a recording.
+type Recorder struct {
+	client bor.IHeimdallClient
+
+	file *os.File
+	enc  *json.Encoder
+	lock sync.Mutex
+}
+
+var (
+	_ bor.IHeimdallClient  = (*Recorder)(nil)
+	_ bor.EvidenceReporter = (*Recorder)(nil)
+)
+
+// NewRecorder creates a client recording the calls to the given client into the
+// file at path. An existing recording is appended to.
+func NewRecorder(client bor.IHeimdallClient, path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{
+		client: client,
+		file:   file,
+		enc:    json.NewEncoder(file),
+	}, nil
+}
+
+// record appends a call and its outcome to the recording. Calls aborted by a
+// shutdown of the node aren't recorded.
+func (r *Recorder) record(call string, result any, err error) {
+	if errors.Is(err, heimdall.ErrShutdownDetected) || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	e := entry{Call: call}
+
+	if err != nil {
+		e.Error = err.Error()
+	} else if result != nil {
+		blob, err := json.Marshal(result)
+		if err != nil {
+			log.Warn("Failed to encode heimdall response for recording", "call", call, "err", err)
+			return
+		}
+
+		e.Result = blob
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.enc.Encode(&e); err != nil {
+		log.Warn("Failed to record heimdall call", "call", call, "err", err)
+	}
+}
+
+func (r *Recorder) StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	res, err := r.client.StateSyncEvents(ctx, fromID, to)
+	r.record(call("StateSyncEvents", fromID, to), res, err)
+
+	return res, err
+}
+
+func (r *Recorder) Span(ctx context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	res, err := r.client.Span(ctx, spanID)
+	r.record(call("Span", spanID), res, err)
+
+	return res, err
+}
+
+func (r *Recorder) FetchCheckpoint(ctx context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	res, err := r.client.FetchCheckpoint(ctx, number)
+	r.record(call("FetchCheckpoint", number), res, err)
+
+	return res, err
+}
+
+func (r *Recorder) FetchCheckpointCount(ctx context.Context) (int64, error) {
+	res, err := r.client.FetchCheckpointCount(ctx)
+	r.record(call("FetchCheckpointCount"), res, err)
+
+	return res, err
+}
+
+func (r *Recorder) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	res, err := r.client.FetchMilestone(ctx)
+	r.record(call("FetchMilestone"), res, err)
+
+	return res, err
+}
+
+func (r *Recorder) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	res, err := r.client.FetchMilestoneCount(ctx)
+	r.record(call("FetchMilestoneCount"), res, err)
+
+	return res, err
+}
+
+func (r *Recorder) FetchNoAckMilestone(ctx context.Context, milestoneID string) error {
+	err := r.client.FetchNoAckMilestone(ctx, milestoneID)
+	r.record(call("FetchNoAckMilestone", milestoneID), nil, err)
+
+	return err
+}
+
+func (r *Recorder) FetchLastNoAckMilestone(ctx context.Context) (string, error) {
+	res, err := r.client.FetchLastNoAckMilestone(ctx)
+	r.record(call("FetchLastNoAckMilestone"), res, err)
+
+	return res, err
+}
+
+func (r *Recorder) FetchMilestoneID(ctx context.Context, milestoneID string) error {
+	err := r.client.FetchMilestoneID(ctx, milestoneID)
+	r.record(call("FetchMilestoneID", milestoneID), nil, err)
+
+	return err
+}
+
+// SubmitDoubleSignEvidence forwards the evidence to the recorded client if it
+// can submit evidence. Submissions aren't recorded.
+func (r *Recorder) SubmitDoubleSignEvidence(ctx context.Context, ev *evidence.DoubleSign) error {
+	reporter, ok := r.client.(bor.EvidenceReporter)
+	if !ok {
+		return errNoEvidenceReporter
+	}
+
+	return reporter.SubmitDoubleSignEvidence(ctx, ev)
+}
+
+// Close closes the recorded client and the recording.
+func (r *Recorder) Close() {
+	r.client.Close()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.file.Close(); err != nil {
+		log.Warn("Failed to close heimdall recording", "err", err)
+	}
+}
@@ -0,0 +1,95 @@
+package heimdallrecord
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/tests/bor/mocks"
+)
+
+func TestRecordReplay(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx  = context.Background()
+		ctrl = gomock.NewController(t)
+		path = filepath.Join(t.TempDir(), "heimdall.jsonl")
+
+		validator = valset.NewValidator(common.HexToAddress("0x01"), 10)
+		sp        = &span.HeimdallSpan{
+			Span:              span.Span{ID: 1, StartBlock: 256, EndBlock: 6655},
+			ValidatorSet:      *valset.NewValidatorSet([]*valset.Validator{validator}),
+			SelectedProducers: []valset.Validator{*validator},
+			ChainID:           "15001",
+		}
+		cp = &checkpoint.Checkpoint{
+			Proposer:   validator.Address,
+			StartBlock: big.NewInt(0),
+			EndBlock:   big.NewInt(255),
+			RootHash:   common.HexToHash("0x02"),
+			BorChainID: "15001",
+		}
+	)
+
+	client := mocks.NewMockIHeimdallClient(ctrl)
+	client.EXPECT().Span(gomock.Any(), uint64(1)).Return(sp, nil)
+	client.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(cp, nil)
+	client.EXPECT().FetchCheckpointCount(gomock.Any()).Return(int64(1), nil)
+	client.EXPECT().FetchCheckpointCount(gomock.Any()).Return(int64(2), nil)
+	client.EXPECT().FetchMilestoneID(gomock.Any(), "id").Return(heimdall.ErrServiceUnavailable)
+	client.EXPECT().Close()
+
+	recorder, err := NewRecorder(client, path)
+	require.NoError(t, err)
+
+	_, err = recorder.Span(ctx, 1)
+	require.NoError(t, err)
+	_, err = recorder.FetchCheckpoint(ctx, -1)
+	require.NoError(t, err)
+	_, err = recorder.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	_, err = recorder.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.ErrorIs(t, recorder.FetchMilestoneID(ctx, "id"), heimdall.ErrServiceUnavailable)
+
+	recorder.Close()
+
+	replayer, err := NewReplayer(path)
+	require.NoError(t, err)
+
+	defer replayer.Close()
+
+	replayedSpan, err := replayer.Span(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, sp.Span, replayedSpan.Span)
+	require.Equal(t, sp.ValidatorSet.Validators, replayedSpan.ValidatorSet.Validators)
+	require.Equal(t, sp.SelectedProducers, replayedSpan.SelectedProducers)
+
+	replayedCheckpoint, err := replayer.FetchCheckpoint(ctx, -1)
+	require.NoError(t, err)
+	require.Equal(t, cp, replayedCheckpoint)
+
+	// Repeated calls are replayed in order, repeating the last response
+	for _, expected := range []int64{1, 2, 2} {
+		count, err := replayer.FetchCheckpointCount(ctx)
+		require.NoError(t, err)
+		require.Equal(t, expected, count)
+	}
+
+	// Heimdall errors survive the round trip
+	require.ErrorIs(t, replayer.FetchMilestoneID(ctx, "id"), heimdall.ErrServiceUnavailable)
+
+	// Calls missing in the recording fail
+	_, err = replayer.Span(ctx, 2)
+	require.ErrorIs(t, err, ErrNotRecorded)
+}
@@ -0,0 +1,198 @@
+package heimdallrecord
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/milestone"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+)
+
+// ErrNotRecorded is returned by a replayer for calls missing in the recording.
+var ErrNotRecorded = errors.New("call not recorded")
+
+// replayedErrors are the heimdall errors callers check for, which are restored
+// when replaying a recorded error.
+var replayedErrors = []error{
+	heimdall.ErrShutdownDetected,
+	heimdall.ErrNoResponse,
+	heimdall.ErrNotSuccessfulResponse,
+	heimdall.ErrNotInRejectedList,
+	heimdall.ErrNotInMilestoneList,
+	heimdall.ErrServiceUnavailable,
+}
+
+// replayedError is an error read from a recording.
+type replayedError struct {
+	msg string
+	err error // Heimdall error contained in the message, if any
+}
+
+func (e *replayedError) Error() string { return e.msg }
+func (e *replayedError) Unwrap() error { return e.err }
+
+// Replayer is a heimdall client serving the responses of a recording. Calls
+// recorded several times are answered with the recorded responses in order,
+// the last one being repeated once they are exhausted.
+type Replayer struct {
+	entries map[string][]*entry
+	lock    sync.Mutex
+}
+
+var _ bor.IHeimdallClient = (*Replayer)(nil)
+
+// NewReplayer creates a client replaying the recording in the file at path.
+func NewReplayer(path string) (*Replayer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := &Replayer{
+		entries: make(map[string][]*entry),
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 128*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		e := new(entry)
+		if err := json.Unmarshal(scanner.Bytes(), e); err != nil {
+			return nil, fmt.Errorf("invalid heimdall recording %s, line %d: %w", path, line, err)
+		}
+
+		r.entries[e.Call] = append(r.entries[e.Call], e)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// replay decodes the next recorded response to the given call into result.
+func (r *Replayer) replay(call string, result any) error {
+	r.lock.Lock()
+
+	entries := r.entries[call]
+	if len(entries) == 0 {
+		r.lock.Unlock()
+		return fmt.Errorf("%w: %s", ErrNotRecorded, call)
+	}
+
+	e := entries[0]
+	if len(entries) > 1 {
+		r.entries[call] = entries[1:]
+	}
+
+	r.lock.Unlock()
+
+	if e.Error != "" {
+		err := &replayedError{msg: e.Error}
+
+		for _, known := range replayedErrors {
+			if strings.Contains(e.Error, known.Error()) {
+				err.err = known
+				break
+			}
+		}
+
+		return err
+	}
+
+	if result == nil || len(e.Result) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(e.Result, result)
+}
+
+func (r *Replayer) StateSyncEvents(_ context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	var res []*clerk.EventRecordWithTime
+	if err := r.replay(call("StateSyncEvents", fromID, to), &res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *Replayer) Span(_ context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	res := new(span.HeimdallSpan)
+	if err := r.replay(call("Span", spanID), res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *Replayer) FetchCheckpoint(_ context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	res := new(checkpoint.Checkpoint)
+	if err := r.replay(call("FetchCheckpoint", number), res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *Replayer) FetchCheckpointCount(_ context.Context) (int64, error) {
+	var res int64
+	if err := r.replay(call("FetchCheckpointCount"), &res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (r *Replayer) FetchMilestone(_ context.Context) (*milestone.Milestone, error) {
+	res := new(milestone.Milestone)
+	if err := r.replay(call("FetchMilestone"), res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (r *Replayer) FetchMilestoneCount(_ context.Context) (int64, error) {
+	var res int64
+	if err := r.replay(call("FetchMilestoneCount"), &res); err != nil {
+		return 0, err
+	}
+
+	return res, nil
+}
+
+func (r *Replayer) FetchNoAckMilestone(_ context.Context, milestoneID string) error {
+	return r.replay(call("FetchNoAckMilestone", milestoneID), nil)
+}
+
+func (r *Replayer) FetchLastNoAckMilestone(_ context.Context) (string, error) {
+	var res string
+	if err := r.replay(call("FetchLastNoAckMilestone"), &res); err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
+
+func (r *Replayer) FetchMilestoneID(_ context.Context, milestoneID string) error {
+	return r.replay(call("FetchMilestoneID", milestoneID), nil)
+}
+
+// Close is a no-op, the recording is fully read on creation.
+func (r *Replayer) Close() {}
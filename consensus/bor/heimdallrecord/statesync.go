@@ -0,0 +1,152 @@
+package heimdallrecord
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+)
+
+// StateSyncFeed serves recorded state-sync events, so that a devnet or shadow
+// fork running from mainnet state without heimdall keeps committing the bridge
+// events mainnet applications depend on.
+//
+// The events are read from a file of JSON lines, each being either an event as
+// served by heimdall or a recorded StateSyncEvents call, so both a dump of the
+// events and a heimdall recording can be replayed. Events are served by id
+// regardless of the calls they were recorded under, once the block committing
+// them is past their record time, and rewritten to the chain id of the devnet
+// so they pass validation.
+type StateSyncFeed struct {
+	events  []*clerk.EventRecordWithTime // Recorded events, sorted by id
+	chainID string
+}
+
+var _ bor.StateSyncSource = (*StateSyncFeed)(nil)
+
+// NewStateSyncFeed creates a feed serving the state-sync events in the file at
+// path to the chain with the given id.
+func NewStateSyncFeed(path string, chainID string) (*StateSyncFeed, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := make(map[uint64]*clerk.EventRecordWithTime)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 128*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		records, err := decodeStateSyncLine(scanner.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("invalid state-sync events %s, line %d: %w", path, line, err)
+		}
+
+		// Recorded calls overlap, the events they share are identical
+		for _, record := range records {
+			events[record.ID] = record
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	f := &StateSyncFeed{
+		events:  make([]*clerk.EventRecordWithTime, 0, len(events)),
+		chainID: chainID,
+	}
+
+	for _, record := range events {
+		f.events = append(f.events, record)
+	}
+
+	sort.Slice(f.events, func(i, j int) bool { return f.events[i].ID < f.events[j].ID })
+
+	return f, nil
+}
+
+// decodeStateSyncLine decodes the events of a line of a state-sync events file,
+// either a single event or a recorded heimdall call. Recorded calls other than
+// successful StateSyncEvents calls carry no events.
+func decodeStateSyncLine(line []byte) ([]*clerk.EventRecordWithTime, error) {
+	e := new(entry)
+	if err := json.Unmarshal(line, e); err != nil {
+		return nil, err
+	}
+
+	if e.Call != "" {
+		if !strings.HasPrefix(e.Call, "StateSyncEvents(") || e.Error != "" || len(e.Result) == 0 {
+			return nil, nil
+		}
+
+		var records []*clerk.EventRecordWithTime
+		if err := json.Unmarshal(e.Result, &records); err != nil {
+			return nil, err
+		}
+
+		return records, nil
+	}
+
+	record := new(clerk.EventRecordWithTime)
+	if err := json.Unmarshal(line, record); err != nil {
+		return nil, err
+	}
+
+	if record.ID == 0 {
+		return nil, errors.New("missing event id")
+	}
+
+	return []*clerk.EventRecordWithTime{record}, nil
+}
+
+// StateSyncEvents returns the recorded events from the given id on recorded
+// before the given time, like heimdall does.
+func (f *StateSyncFeed) StateSyncEvents(_ context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	var (
+		start = sort.Search(len(f.events), func(i int) bool { return f.events[i].ID >= fromID })
+		until = time.Unix(to, 0)
+		res   []*clerk.EventRecordWithTime
+	)
+
+	for _, record := range f.events[start:] {
+		if !record.Time.Before(until) {
+			break
+		}
+
+		event := *record
+		event.ChainID = f.chainID
+
+		res = append(res, &event)
+	}
+
+	return res, nil
+}
+
+// Len returns the number of events in the feed.
+func (f *StateSyncFeed) Len() int {
+	return len(f.events)
+}
+
+// Range returns the ids of the first and last events in the feed.
+func (f *StateSyncFeed) Range() (uint64, uint64) {
+	if len(f.events) == 0 {
+		return 0, 0
+	}
+
+	return f.events[0].ID, f.events[len(f.events)-1].ID
+}
@@ -0,0 +1,70 @@
+package heimdallrecord
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateSyncFeed(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "statesync.jsonl")
+
+	// Events dumped one per line, mixed with a heimdall recording repeating some
+	lines := `{"id":3,"contract":"0x0000000000000000000000000000000000000003","data":"0x03","tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000003","log_index":0,"bor_chain_id":"137","record_time":"2024-01-01T00:00:30Z"}
+{"call":"Span(1)","result":{}}
+{"call":"StateSyncEvents(1,1704067230)","result":[{"id":1,"contract":"0x0000000000000000000000000000000000000001","data":"0x01","tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000001","log_index":0,"bor_chain_id":"137","record_time":"2024-01-01T00:00:10Z"},{"id":2,"contract":"0x0000000000000000000000000000000000000002","data":"0x02","tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000002","log_index":1,"bor_chain_id":"137","record_time":"2024-01-01T00:00:20Z"}]}
+{"call":"StateSyncEvents(2,1704067240)","error":"service unavailable"}
+
+{"id":2,"contract":"0x0000000000000000000000000000000000000002","data":"0x02","tx_hash":"0x0000000000000000000000000000000000000000000000000000000000000002","log_index":1,"bor_chain_id":"137","record_time":"2024-01-01T00:00:20Z"}
+`
+	require.NoError(t, os.WriteFile(path, []byte(lines), 0o600))
+
+	feed, err := NewStateSyncFeed(path, "1337")
+	require.NoError(t, err)
+	require.Equal(t, 3, feed.Len())
+
+	first, last := feed.Range()
+	require.Equal(t, uint64(1), first)
+	require.Equal(t, uint64(3), last)
+
+	ids := func(fromID uint64, to time.Time) []uint64 {
+		t.Helper()
+
+		events, err := feed.StateSyncEvents(context.Background(), fromID, to.Unix())
+		require.NoError(t, err)
+
+		var res []uint64
+
+		for _, event := range events {
+			require.Equal(t, "1337", event.ChainID)
+
+			res = append(res, event.ID)
+		}
+
+		return res
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, []uint64{1, 2, 3}, ids(1, base.Add(time.Minute)))
+	require.Equal(t, []uint64{2, 3}, ids(2, base.Add(time.Minute)))
+	require.Equal(t, []uint64{1}, ids(1, base.Add(20*time.Second)))
+	require.Empty(t, ids(1, base.Add(10*time.Second)))
+	require.Empty(t, ids(4, base.Add(time.Minute)))
+
+	// The recorded events are left untouched
+	events, err := feed.StateSyncEvents(context.Background(), 1, base.Add(time.Minute).Unix())
+	require.NoError(t, err)
+
+	events[0].ChainID = "1"
+	require.Equal(t, "137", feed.events[0].ChainID)
+
+	_, err = NewStateSyncFeed(filepath.Join(t.TempDir(), "missing.jsonl"), "1337")
+	require.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package bor
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Sampling of the messages logged for every header applied to a snapshot. The
+// few messages allowed per interval cover a live chain, while a full sync only
+// logs a handful of headers every interval instead of flooding the output.
+const (
+	snapshotLogInterval = 8 * time.Second
+	snapshotLogBurst    = 8
+)
+
+// logSampler limits a message logged on a hot path to a burst of messages per
+// interval, counting the ones dropped in between.
+type logSampler struct {
+	level    slog.Level
+	interval time.Duration
+	burst    int
+
+	lock    sync.Mutex
+	start   time.Time // Start of the current interval
+	logged  int       // Messages logged in the current interval
+	dropped int       // Messages dropped since the last one logged
+}
+
+func newLogSampler(level slog.Level, interval time.Duration, burst int) *logSampler {
+	return &logSampler{
+		level:    level,
+		interval: interval,
+		burst:    burst,
+	}
+}
+
+// sample returns whether the next message should be logged, along with the
+// number of messages dropped since the last one logged. Nothing is sampled if
+// the level of the message isn't logged anyway.
+func (s *logSampler) sample(now time.Time) (bool, int) {
+	if !log.Root().Enabled(context.Background(), s.level) {
+		return false, 0
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if now.Sub(s.start) >= s.interval {
+		s.start, s.logged = now, 0
+	}
+
+	if s.logged >= s.burst {
+		s.dropped++
+		return false, 0
+	}
+
+	dropped := s.dropped
+	s.logged, s.dropped = s.logged+1, 0
+
+	return true, dropped
+}
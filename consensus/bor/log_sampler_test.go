@@ -0,0 +1,51 @@
+package bor
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+//nolint:paralleltest // Replaces the root logger
+func TestLogSampler(t *testing.T) {
+	root := log.Root()
+	defer log.SetDefault(root)
+
+	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(io.Discard, log.LevelDebug, false)))
+
+	var (
+		sampler = newLogSampler(log.LevelDebug, time.Second, 2)
+		start   = time.Unix(1700000000, 0)
+	)
+
+	sample := func(now time.Time, logged bool, dropped int) {
+		t.Helper()
+
+		ok, n := sampler.sample(now)
+		require.Equal(t, logged, ok)
+		require.Equal(t, dropped, n)
+	}
+
+	// A burst is logged per interval, the rest dropped and reported
+	sample(start, true, 0)
+	sample(start.Add(100*time.Millisecond), true, 0)
+	sample(start.Add(200*time.Millisecond), false, 0)
+	sample(start.Add(300*time.Millisecond), false, 0)
+	sample(start.Add(time.Second), true, 2)
+	sample(start.Add(1100*time.Millisecond), true, 0)
+	sample(start.Add(1200*time.Millisecond), false, 0)
+
+	// Levels not logged aren't sampled
+	trace := newLogSampler(log.LevelTrace, time.Second, 2)
+
+	for i := 0; i < 4; i++ {
+		ok, _ := trace.sample(start)
+		require.False(t, ok)
+	}
+
+	require.Zero(t, trace.dropped)
+}
@@ -0,0 +1,45 @@
+package bor
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maintenanceSprintMargin is the number of blocks at the end of a sprint which
+// are never considered a maintenance window, as the producers of the next
+// sprint are only known once the sprint end block is applied.
+const maintenanceSprintMargin = 2
+
+// IsMaintenanceWindow reports whether the local signer is neither the in-turn
+// nor the first backup producer of the block following the given header, so
+// that expensive background work can run without delaying our own blocks.
+func (c *Bor) IsMaintenanceWindow(chain consensus.ChainHeaderReader, header *types.Header) bool {
+	signer := c.authorizedSigner.Load().signer
+	if signer == (common.Address{}) {
+		return true
+	}
+
+	number := header.Number.Uint64() + 1
+
+	sprint := c.config.CalculateSprint(number)
+	if sprint > 0 && sprint-number%sprint <= maintenanceSprintMargin {
+		return false
+	}
+
+	snap, err := c.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return false
+	}
+
+	if !snap.ValidatorSet.HasAddress(signer) {
+		return true
+	}
+
+	succession, err := snap.GetSignerBackoffRank(signer, c.config.IsBackoffByStake(number))
+	if err != nil {
+		return false
+	}
+
+	return succession > 1
+}
@@ -0,0 +1,75 @@
+package bor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// errUnexpectedMilestoneRef is returned if a block references a milestone
+// before the milestone reference fork.
+var errUnexpectedMilestoneRef = errors.New("milestone reference before fork")
+
+// InvalidMilestoneRefError is returned if a block references a milestone
+// behind the one referenced by its parent, or one not below the block itself.
+type InvalidMilestoneRefError struct {
+	Number    uint64
+	Milestone uint64
+	Parent    uint64
+}
+
+func (e *InvalidMilestoneRefError) Error() string {
+	return fmt.Sprintf("invalid milestone reference of block %d: have %d, parent %d", e.Number, e.Milestone, e.Parent)
+}
+
+// milestoneReference returns the milestone the given header, about to be
+// sealed, references: the latest milestone whitelisted locally, never behind
+// the one referenced by its parent nor at or beyond the header itself.
+func (c *Bor) milestoneReference(chain consensus.ChainHeaderReader, header *types.Header) uint64 {
+	if !c.config.IsMilestoneRef(header.Number) {
+		return 0
+	}
+
+	number := header.Number.Uint64()
+
+	var previous uint64
+	if parent := chain.GetHeader(header.ParentHash, number-1); parent != nil {
+		previous = parent.GetMilestoneNumber(c.chainConfig)
+	}
+
+	milestone, _, err := rawdb.ReadFinality[*rawdb.Milestone](c.db)
+	if err != nil || milestone < previous || milestone >= number {
+		return previous
+	}
+
+	return milestone
+}
+
+// verifyMilestoneReference checks that the milestone referenced by the header
+// is monotonic along the chain and lies below the header.
+func (c *Bor) verifyMilestoneReference(header *types.Header, parent *types.Header) error {
+	milestone := header.GetMilestoneNumber(c.chainConfig)
+
+	if !c.config.IsMilestoneRef(header.Number) {
+		if milestone != 0 {
+			return errUnexpectedMilestoneRef
+		}
+
+		return nil
+	}
+
+	previous := parent.GetMilestoneNumber(c.chainConfig)
+
+	if milestone < previous || milestone >= header.Number.Uint64() {
+		return &InvalidMilestoneRefError{
+			Number:    header.Number.Uint64(),
+			Milestone: milestone,
+			Parent:    previous,
+		}
+	}
+
+	return nil
+}
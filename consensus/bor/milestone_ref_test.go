@@ -0,0 +1,59 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func milestoneRefHeader(t *testing.T, number uint64, milestone uint64) *types.Header {
+	t.Helper()
+
+	extra, err := rlp.EncodeToBytes(&types.BlockExtraData{MilestoneNumber: milestone})
+	require.NoError(t, err)
+
+	extra = append(make([]byte, types.ExtraVanityLength), extra...)
+	extra = append(extra, make([]byte, types.ExtraSealLength)...)
+
+	return &types.Header{
+		Number: new(big.Int).SetUint64(number),
+		Extra:  extra,
+	}
+}
+
+func TestVerifyMilestoneReference(t *testing.T) {
+	t.Parallel()
+
+	borConfig := &params.BorConfig{
+		MilestoneRefBlock: big.NewInt(100),
+	}
+
+	b := &Bor{
+		chainConfig: &params.ChainConfig{
+			CancunBlock: big.NewInt(0),
+			Bor:         borConfig,
+		},
+		config: borConfig,
+	}
+
+	// Blocks before the fork can't reference a milestone
+	require.NoError(t, b.verifyMilestoneReference(milestoneRefHeader(t, 50, 0), milestoneRefHeader(t, 49, 0)))
+	require.ErrorIs(t, b.verifyMilestoneReference(milestoneRefHeader(t, 50, 40), milestoneRefHeader(t, 49, 0)), errUnexpectedMilestoneRef)
+
+	// The first block of the fork may reference any milestone below it
+	require.NoError(t, b.verifyMilestoneReference(milestoneRefHeader(t, 100, 0), milestoneRefHeader(t, 99, 0)))
+	require.NoError(t, b.verifyMilestoneReference(milestoneRefHeader(t, 100, 90), milestoneRefHeader(t, 99, 0)))
+
+	// The reference is monotonic and stays below the block
+	require.NoError(t, b.verifyMilestoneReference(milestoneRefHeader(t, 120, 90), milestoneRefHeader(t, 119, 90)))
+	require.NoError(t, b.verifyMilestoneReference(milestoneRefHeader(t, 120, 110), milestoneRefHeader(t, 119, 90)))
+
+	var refErr *InvalidMilestoneRefError
+	require.ErrorAs(t, b.verifyMilestoneReference(milestoneRefHeader(t, 120, 80), milestoneRefHeader(t, 119, 90)), &refErr)
+	require.ErrorAs(t, b.verifyMilestoneReference(milestoneRefHeader(t, 120, 120), milestoneRefHeader(t, 119, 90)), &refErr)
+}
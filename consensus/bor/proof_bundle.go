@@ -0,0 +1,117 @@
+package bor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// maxProofBundleLength is the maximum number of headers following the
+// checkpointed block in a proof bundle.
+const maxProofBundleLength = 4096
+
+var (
+	// errEmptyProofBundle is returned when verifying a proof bundle without
+	// headers.
+	errEmptyProofBundle = errors.New("empty proof bundle")
+
+	// errBrokenProofBundle is returned when the headers of a proof bundle don't
+	// form a chain.
+	errBrokenProofBundle = errors.New("proof bundle headers not chained")
+)
+
+// ProofBundle proves that a block descends from a checkpointed block and was
+// sealed by the validators of its time, so that it can be checked offline by
+// a verifier trusting the checkpointed block, e.g. through the checkpoint root
+// hash submitted to L1.
+type ProofBundle struct {
+	// Validators is the validator set authorized to seal the block following
+	// the checkpointed one.
+	Validators []*valset.Validator `json:"validators"`
+
+	// Headers are the headers from the checkpointed block to the target block.
+	// The sprint end headers among them carry the validator set changes.
+	Headers []*types.Header `json:"headers"`
+
+	// Signers are the signers of the headers following the checkpointed one,
+	// for convenience: the verifier recovers them from the seals.
+	Signers []common.Address `json:"signers"`
+}
+
+// VerifyProofBundle checks that the headers of the bundle form a chain from the
+// checkpointed block with the given hash, and that each of them was sealed by a
+// validator authorized at its height. It returns the target header. The turn of
+// the signers isn't checked: the bundle proves that a block was sealed by the
+// validators, not that it was the block they had to seal.
+func VerifyProofBundle(bundle *ProofBundle, checkpoint common.Hash, chainConfig *params.ChainConfig) (*types.Header, error) {
+	if len(bundle.Headers) == 0 {
+		return nil, errEmptyProofBundle
+	}
+
+	if hash := bundle.Headers[0].Hash(); hash != checkpoint {
+		return nil, fmt.Errorf("proof bundle starts at %x, not the checkpointed block %x", hash, checkpoint)
+	}
+
+	authorized := make(map[common.Address]bool, len(bundle.Validators))
+	for _, validator := range bundle.Validators {
+		authorized[validator.Address] = true
+	}
+
+	for i := 1; i < len(bundle.Headers); i++ {
+		parent, header := bundle.Headers[i-1], bundle.Headers[i]
+
+		if header.ParentHash != parent.Hash() || header.Number == nil || parent.Number == nil || header.Number.Uint64() != parent.Number.Uint64()+1 {
+			return nil, fmt.Errorf("%w: block %d", errBrokenProofBundle, i)
+		}
+
+		number := header.Number.Uint64()
+
+		signer, err := recoverSigner(header, chainConfig.Bor)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", number, err)
+		}
+
+		if !authorized[signer] {
+			return nil, &UnauthorizedSignerError{number - 1, signer.Bytes()}
+		}
+
+		// The validator set carried by a sprint end header is authorized from the
+		// following block on
+		if IsSprintStart(number+1, chainConfig.Bor.CalculateSprint(number)) {
+			validators, err := valset.ParseValidators(header.GetValidatorBytes(chainConfig))
+			if err != nil {
+				return nil, fmt.Errorf("block %d: %w", number, err)
+			}
+
+			authorized = make(map[common.Address]bool, len(validators))
+			for _, validator := range validators {
+				authorized[validator.Address] = true
+			}
+		}
+	}
+
+	return bundle.Headers[len(bundle.Headers)-1], nil
+}
+
+// recoverSigner recovers the signer of a header without a signature cache.
+func recoverSigner(header *types.Header, config *params.BorConfig) (common.Address, error) {
+	if len(header.Extra) < types.ExtraSealLength {
+		return common.Address{}, errMissingSignature
+	}
+
+	pubkey, err := crypto.Ecrecover(SealHash(header, config).Bytes(), header.Extra[len(header.Extra)-types.ExtraSealLength:])
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	var signer common.Address
+
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	return signer, nil
+}
@@ -0,0 +1,99 @@
+package bor
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestVerifyProofBundle(t *testing.T) {
+	t.Parallel()
+
+	var (
+		first, _  = crypto.GenerateKey()
+		second, _ = crypto.GenerateKey()
+
+		chainConfig = &params.ChainConfig{
+			ChainID: big.NewInt(1),
+			Bor:     &params.BorConfig{Sprint: map[string]uint64{"0": 4}},
+		}
+	)
+
+	validator := func(key *ecdsa.PrivateKey) *valset.Validator {
+		return valset.NewValidator(crypto.PubkeyToAddress(key.PublicKey), 10)
+	}
+
+	// Blocks 1 to 3 are sealed by the first validator, which hands over to the
+	// second one at the end of the sprint, and back at the end of the next one
+	sealers := []*ecdsa.PrivateKey{nil, first, first, first, second, second, second, second, first, first}
+	handovers := map[int]*valset.Validator{3: validator(second), 7: validator(first)}
+
+	headers := make([]*types.Header, len(sealers))
+	for i := range headers {
+		header := &types.Header{
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(1),
+			Extra:      make([]byte, types.ExtraVanityLength),
+		}
+
+		if i > 0 {
+			header.ParentHash = headers[i-1].Hash()
+		}
+
+		if next, ok := handovers[i]; ok {
+			header.Extra = append(header.Extra, next.HeaderBytes()...)
+		}
+
+		header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
+
+		if sealers[i] != nil {
+			sig, err := crypto.Sign(SealHash(header, chainConfig.Bor).Bytes(), sealers[i])
+			require.NoError(t, err)
+
+			copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sig)
+		}
+
+		headers[i] = header
+	}
+
+	bundle := func(from int) *ProofBundle {
+		return &ProofBundle{
+			Validators: []*valset.Validator{validator(first)},
+			Headers:    headers[from:],
+		}
+	}
+
+	target, err := VerifyProofBundle(bundle(0), headers[0].Hash(), chainConfig)
+	require.NoError(t, err)
+	require.Equal(t, headers[9].Hash(), target.Hash())
+
+	// The bundle must start at the trusted block
+	_, err = VerifyProofBundle(bundle(0), headers[1].Hash(), chainConfig)
+	require.Error(t, err)
+
+	// Blocks sealed by validators not authorized at their height are refused
+	unauthorized := bundle(0)
+	unauthorized.Validators = []*valset.Validator{validator(second)}
+
+	var signerErr *UnauthorizedSignerError
+
+	_, err = VerifyProofBundle(unauthorized, headers[0].Hash(), chainConfig)
+	require.ErrorAs(t, err, &signerErr)
+
+	// The headers must be chained
+	broken := bundle(0)
+	broken.Headers = append(append([]*types.Header{}, headers[:5]...), headers[6:]...)
+
+	_, err = VerifyProofBundle(broken, headers[0].Hash(), chainConfig)
+	require.ErrorIs(t, err, errBrokenProofBundle)
+
+	_, err = VerifyProofBundle(&ProofBundle{}, headers[0].Hash(), chainConfig)
+	require.ErrorIs(t, err, errEmptyProofBundle)
+}
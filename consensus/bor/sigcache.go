@@ -0,0 +1,25 @@
+package bor
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+var (
+	sigcacheHitCounter  = metrics.NewRegisteredCounter("bor/sigcache/hit", nil)
+	sigcacheMissCounter = metrics.NewRegisteredCounter("bor/sigcache/miss", nil)
+)
+
+// SetSignatureCache sets the number of recent block signers kept in memory, 0
+// for the default. The cache is shared by the header verification, the
+// snapshots and the RPC author lookups, so archive nodes serving historical
+// queries may want it well above the default. It must be set before the engine
+// is used, as the snapshots hold on to the cache they were created with.
+func (c *Bor) SetSignatureCache(size int) {
+	if size <= 0 || size == inmemorySignatures {
+		return
+	}
+
+	c.signatures, _ = lru.NewARC(size)
+}
@@ -0,0 +1,50 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+func TestSetSignatureCache(t *testing.T) {
+	t.Parallel()
+
+	signatures, _ := lru.NewARC(inmemorySignatures)
+
+	// The default size keeps the cache
+	c := &Bor{config: &params.BorConfig{Sprint: map[string]uint64{"0": 16}}, signatures: signatures}
+	c.SetSignatureCache(0)
+	require.Same(t, signatures, c.signatures)
+
+	c.SetSignatureCache(2)
+	require.NotSame(t, signatures, c.signatures)
+
+	key, _ := crypto.GenerateKey()
+
+	for i := 1; i <= 3; i++ {
+		header := &types.Header{
+			Number:     big.NewInt(int64(i)),
+			Difficulty: big.NewInt(1),
+			Extra:      make([]byte, types.ExtraVanityLength+types.ExtraSealLength),
+		}
+
+		sig, err := crypto.Sign(SealHash(header, c.config).Bytes(), key)
+		require.NoError(t, err)
+
+		copy(header.Extra[types.ExtraVanityLength:], sig)
+
+		signer, err := c.Author(header)
+		require.NoError(t, err)
+		require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), signer)
+	}
+
+	// Lookups share the resized cache
+	require.Equal(t, 2, c.signatures.Len())
+}
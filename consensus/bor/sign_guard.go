@@ -0,0 +1,143 @@
+package bor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// signGuardHistory is the number of heights below the highest signed block the
+// signed hashes are remembered for. Older heights are refused altogether.
+const signGuardHistory = 1024
+
+// signGuardPrefix is the database table the blocks signed by the local signers
+// are recorded in:
+//
+//	signer                             -> last attempted seal: highest signed number (uint64 big endian) + seal hash + block time (uint64 big endian)
+//	signer + number (uint64 big endian) -> seal hash
+//
+// Records written before the last attempted seal was tracked only hold the
+// highest signed number.
+var signGuardPrefix = "bor-sign-guard-"
+
+// errDoubleSignRefused is returned if the local signer is asked to sign a block
+// conflicting with one it signed before.
+var errDoubleSignRefused = errors.New("refusing to double sign")
+
+// signGuard persists the blocks signed by the local signers, so that a signer
+// never signs two different blocks at the same height, even across restarts.
+type signGuard struct {
+	db   ethdb.Database
+	lock sync.Mutex
+}
+
+func newSignGuard(db ethdb.Database) *signGuard {
+	return &signGuard{db: rawdb.NewTable(db, signGuardPrefix)}
+}
+
+// signGuardKey = signer + number (uint64 big endian)
+func signGuardKey(signer common.Address, number uint64) []byte {
+	return binary.BigEndian.AppendUint64(signer.Bytes(), number)
+}
+
+// check returns an error if signing the block with the given seal hash at the
+// given height would conflict with a block signed before.
+func (g *signGuard) check(signer common.Address, number uint64, sealHash common.Hash) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	return g.checkLocked(signer, number, sealHash)
+}
+
+func (g *signGuard) checkLocked(signer common.Address, number uint64, sealHash common.Hash) error {
+	if blob, err := g.db.Get(signer.Bytes()); err == nil && len(blob) >= 8 {
+		if highest := binary.BigEndian.Uint64(blob); number+signGuardHistory <= highest {
+			return fmt.Errorf("%w: block %d too far below highest signed block %d", errDoubleSignRefused, number, highest)
+		}
+	}
+
+	if blob, err := g.db.Get(signGuardKey(signer, number)); err == nil {
+		if signed := common.BytesToHash(blob); signed != sealHash {
+			return fmt.Errorf("%w: block %d already signed with seal hash %x", errDoubleSignRefused, number, signed)
+		}
+	}
+
+	return nil
+}
+
+// record checks the block against the blocks signed before and records it as
+// signed. It must be called, and succeed, right before signing the block.
+func (g *signGuard) record(signer common.Address, number uint64, sealHash common.Hash, time uint64) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if err := g.checkLocked(signer, number, sealHash); err != nil {
+		return err
+	}
+
+	batch := g.db.NewBatch()
+
+	if err := batch.Put(signGuardKey(signer, number), sealHash.Bytes()); err != nil {
+		return err
+	}
+
+	blob, _ := g.db.Get(signer.Bytes())
+	if len(blob) < 8 || binary.BigEndian.Uint64(blob) <= number {
+		last := binary.BigEndian.AppendUint64(nil, number)
+		last = append(last, sealHash.Bytes()...)
+		last = binary.BigEndian.AppendUint64(last, time)
+
+		if err := batch.Put(signer.Bytes(), last); err != nil {
+			return err
+		}
+	}
+
+	// Forget the height sliding out of the history window
+	if number >= signGuardHistory {
+		if err := batch.Delete(signGuardKey(signer, number-signGuardHistory)); err != nil {
+			return err
+		}
+	}
+
+	return batch.Write()
+}
+
+// release forgets the block with the given seal hash recorded as signed at the
+// given height. It must only be called for blocks whose signature never left
+// the node, e.g. sealing operations discarded before their slot, so that the
+// block can be re-assembled with different contents.
+func (g *signGuard) release(signer common.Address, number uint64, sealHash common.Hash) error {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	blob, err := g.db.Get(signGuardKey(signer, number))
+	if err != nil || common.BytesToHash(blob) != sealHash {
+		return nil
+	}
+
+	return g.db.Delete(signGuardKey(signer, number))
+}
+
+// lastSeal returns the highest block the signer attempted to seal, i.e. passed
+// to record, with its seal hash and block time. The seal hash and time are
+// zero for records older than the tracking of the last attempted seal.
+func (g *signGuard) lastSeal(signer common.Address) (number uint64, sealHash common.Hash, time uint64, ok bool) {
+	blob, err := g.db.Get(signer.Bytes())
+	if err != nil || len(blob) < 8 {
+		return 0, common.Hash{}, 0, false
+	}
+
+	number = binary.BigEndian.Uint64(blob)
+
+	if len(blob) == 8+common.HashLength+8 {
+		sealHash = common.BytesToHash(blob[8 : 8+common.HashLength])
+		time = binary.BigEndian.Uint64(blob[8+common.HashLength:])
+	}
+
+	return number, sealHash, time, true
+}
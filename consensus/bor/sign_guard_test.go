@@ -0,0 +1,108 @@
+package bor
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+func TestSignGuard(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		guard  = newSignGuard(db)
+		signer = common.HexToAddress("0x01")
+		other  = common.HexToAddress("0x02")
+		first  = common.HexToHash("0xaa")
+		second = common.HexToHash("0xbb")
+	)
+
+	require.NoError(t, guard.check(signer, 10, first))
+	require.NoError(t, guard.record(signer, 10, first, 100))
+
+	// Re-signing the same block is fine, a different one at the same height is not
+	require.NoError(t, guard.check(signer, 10, first))
+	require.NoError(t, guard.record(signer, 10, first, 100))
+	require.ErrorIs(t, guard.check(signer, 10, second), errDoubleSignRefused)
+	require.ErrorIs(t, guard.record(signer, 10, second, 100), errDoubleSignRefused)
+
+	// Other signers and heights are unaffected
+	require.NoError(t, guard.record(other, 10, second, 100))
+	require.NoError(t, guard.record(signer, 11, second, 100))
+	require.NoError(t, guard.record(signer, 9, second, 100))
+
+	// The record survives a restart, along with the last attempted seal
+	guard = newSignGuard(db)
+	require.ErrorIs(t, guard.check(signer, 10, second), errDoubleSignRefused)
+
+	number, sealHash, time, ok := guard.lastSeal(signer)
+	require.True(t, ok)
+	require.Equal(t, uint64(11), number)
+	require.Equal(t, second, sealHash)
+	require.Equal(t, uint64(100), time)
+
+	_, _, _, ok = guard.lastSeal(common.HexToAddress("0x03"))
+	require.False(t, ok)
+
+	// Heights beyond the remembered history are refused altogether
+	require.NoError(t, guard.record(signer, 11+signGuardHistory, first, 100))
+	require.ErrorIs(t, guard.check(signer, 11, first), errDoubleSignRefused)
+	require.NoError(t, guard.check(signer, 12, first))
+}
+
+func TestSignGuardRelease(t *testing.T) {
+	t.Parallel()
+
+	var (
+		guard  = newSignGuard(rawdb.NewMemoryDatabase())
+		signer = common.HexToAddress("0x01")
+		first  = common.HexToHash("0xaa")
+		second = common.HexToHash("0xbb")
+	)
+
+	require.NoError(t, guard.record(signer, 10, first, 100))
+	require.ErrorIs(t, guard.check(signer, 10, second), errDoubleSignRefused)
+
+	// Releasing another block leaves the signed one alone
+	require.NoError(t, guard.release(signer, 10, second))
+	require.ErrorIs(t, guard.check(signer, 10, second), errDoubleSignRefused)
+
+	// A discarded seal doesn't prevent sealing other contents at the height
+	require.NoError(t, guard.release(signer, 10, first))
+	require.NoError(t, guard.record(signer, 10, second, 100))
+	require.ErrorIs(t, guard.check(signer, 10, first), errDoubleSignRefused)
+}
+
+func TestSignGuardLegacyRecord(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		signer = common.HexToAddress("0x01")
+	)
+
+	// Records written before the last attempted seal was tracked only hold the
+	// highest signed number
+	require.NoError(t, rawdb.NewTable(db, signGuardPrefix).Put(signer.Bytes(), binary.BigEndian.AppendUint64(nil, 10)))
+
+	guard := newSignGuard(db)
+
+	number, sealHash, _, ok := guard.lastSeal(signer)
+	require.True(t, ok)
+	require.Equal(t, uint64(10), number)
+	require.Equal(t, common.Hash{}, sealHash)
+
+	// The next seal upgrades the record
+	require.NoError(t, guard.record(signer, 11, common.HexToHash("0xaa"), 100))
+
+	number, sealHash, time, ok := guard.lastSeal(signer)
+	require.True(t, ok)
+	require.Equal(t, uint64(11), number)
+	require.Equal(t, common.HexToHash("0xaa"), sealHash)
+	require.Equal(t, uint64(100), time)
+}
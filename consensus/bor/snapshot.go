@@ -1,21 +1,30 @@
 package bor
 
 import (
-	"context"
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common/tracing"
 	"github.com/ethereum/go-ethereum/consensus/bor/valset"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/golang/snappy"
 	lru "github.com/hashicorp/golang-lru"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/params"
 )
 
 // Snapshot is the state of the authorization voting at a given point in time.
+// Snapshots are shared between the verifier, the miner and the RPC handlers
+// once cached, so they are copy-on-write: apply and revert only ever modify a
+// copy, and readers must never modify a snapshot or its validator set.
 type Snapshot struct {
 	chainConfig *params.ChainConfig
 
@@ -25,6 +34,8 @@ type Snapshot struct {
 	Hash         common.Hash               `json:"hash"`         // Block hash where the snapshot was created
 	ValidatorSet *valset.ValidatorSet      `json:"validatorSet"` // Validator set at this moment
 	Recents      map[uint64]common.Address `json:"recents"`      // Set of recent signers for spam protections
+
+	diff *snapshotDiffTrack // Changes since the last stored block the snapshot descends from, nil if untracked
 }
 
 // newSnapshot creates a new snapshot with the specified startup parameters. This
@@ -49,17 +60,71 @@ func newSnapshot(
 	return snap
 }
 
-// loadSnapshot loads an existing snapshot from the database.
+const (
+	// snapshotChecksumVersion prefixes persisted snapshots carrying a checksum.
+	// The layout is version byte || keccak256(json) || json. Legacy snapshots are
+	// plain json, which can never start with a version byte.
+	snapshotChecksumVersion = 0x01
+
+	// snapshotSnappyVersion prefixes persisted snapshots compressed with snappy.
+	// The layout is version byte || keccak256(json) || snappy(json).
+	snapshotSnappyVersion = 0x02
+
+	// snapshotDiffVersion prefixes snapshots persisted as a diff over the one of
+	// an ancestor, see snapshotDiff. The layout is the one of snapshotSnappyVersion.
+	snapshotDiffVersion = 0x03
+)
+
+// snapshotKey = "bor-" + hash
+func snapshotKey(hash common.Hash) []byte {
+	return append([]byte("bor-"), hash[:]...)
+}
+
+// snapshotQuarantineKey = "bor-quarantine-" + hash
+func snapshotQuarantineKey(hash common.Hash) []byte {
+	return append([]byte("bor-quarantine-"), hash[:]...)
+}
+
+// loadSnapshot loads an existing snapshot from the database, returning
+// errSnapshotCorrupt if it fails the integrity check.
 func loadSnapshot(chainConfig *params.ChainConfig, config *params.BorConfig, sigcache *lru.ARCCache, db ethdb.Database, hash common.Hash) (*Snapshot, error) {
-	blob, err := db.Get(append([]byte("bor-"), hash[:]...))
+	blob, err := db.Get(snapshotKey(hash))
 	if err != nil {
 		return nil, err
 	}
 
+	if len(blob) > 0 && (blob[0] == snapshotChecksumVersion || blob[0] == snapshotSnappyVersion || blob[0] == snapshotDiffVersion) {
+		if len(blob) < 1+common.HashLength {
+			return nil, fmt.Errorf("%w: truncated blob of %d bytes", errSnapshotCorrupt, len(blob))
+		}
+
+		checksum, data := blob[1:1+common.HashLength], blob[1+common.HashLength:]
+
+		if blob[0] == snapshotSnappyVersion || blob[0] == snapshotDiffVersion {
+			if data, err = snappy.Decode(nil, data); err != nil {
+				return nil, fmt.Errorf("%w: %v", errSnapshotCorrupt, err)
+			}
+		}
+
+		if !bytes.Equal(checksum, crypto.Keccak256(data)) {
+			return nil, fmt.Errorf("%w: checksum mismatch", errSnapshotCorrupt)
+		}
+
+		if blob[0] == snapshotDiffVersion {
+			return loadSnapshotDiff(chainConfig, config, sigcache, db, hash, data)
+		}
+
+		blob = data
+	}
+
 	snap := new(Snapshot)
 
 	if err := json.Unmarshal(blob, snap); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", errSnapshotCorrupt, err)
+	}
+
+	if snap.ValidatorSet == nil || snap.Hash != hash {
+		return nil, fmt.Errorf("%w: invalid content", errSnapshotCorrupt)
 	}
 
 	snap.ValidatorSet.UpdateValidatorMap()
@@ -77,12 +142,47 @@ func loadSnapshot(chainConfig *params.ChainConfig, config *params.BorConfig, sig
 
 // store inserts the snapshot into the database.
 func (s *Snapshot) store(db ethdb.Database) error {
-	blob, err := json.Marshal(s)
+	return storeSnapshotBlob(db, s.Hash, snapshotSnappyVersion, s)
+}
+
+// storeSnapshotBlob inserts the json encoding of a snapshot or a snapshot diff
+// into the database under the snapshot key of the given block, compressed and
+// prefixed with the given version and the checksum.
+func storeSnapshotBlob(db ethdb.Database, hash common.Hash, version byte, v interface{}) error {
+	blob, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	compressed := snappy.Encode(nil, blob)
+
+	enc := make([]byte, 0, 1+common.HashLength+len(compressed))
+	enc = append(enc, version)
+	enc = append(enc, crypto.Keccak256(blob)...)
+	enc = append(enc, compressed...)
+
+	return db.Put(snapshotKey(hash), enc)
+}
+
+// quarantineSnapshot moves the snapshot of the given block out of the way of
+// loadSnapshot, keeping the blob under a quarantine prefix for inspection.
+func quarantineSnapshot(db ethdb.Database, hash common.Hash) error {
+	blob, err := db.Get(snapshotKey(hash))
 	if err != nil {
 		return err
 	}
 
-	return db.Put(append([]byte("bor-"), s.Hash[:]...), blob)
+	batch := db.NewBatch()
+
+	if err := batch.Put(snapshotQuarantineKey(hash), blob); err != nil {
+		return err
+	}
+
+	if err := batch.Delete(snapshotKey(hash)); err != nil {
+		return err
+	}
+
+	return batch.Write()
 }
 
 // copy creates a deep copy of the snapshot, though not the individual votes.
@@ -94,6 +194,7 @@ func (s *Snapshot) copy() *Snapshot {
 		Hash:         s.Hash,
 		ValidatorSet: s.ValidatorSet.Copy(),
 		Recents:      make(map[uint64]common.Address),
+		diff:         s.diff,
 	}
 	for block, signer := range s.Recents {
 		cpy.Recents[block] = signer
@@ -102,6 +203,13 @@ func (s *Snapshot) copy() *Snapshot {
 	return cpy
 }
 
+// Samplers of the messages logged while applying headers, shared by all
+// snapshots as the headers of a sync are applied in many batches.
+var (
+	appliedHeaderLogs = newLogSampler(log.LevelTrace, snapshotLogInterval, snapshotLogBurst)
+	validatorSetLogs  = newLogSampler(log.LevelDebug, snapshotLogInterval, snapshotLogBurst)
+)
+
 func (s *Snapshot) apply(headers []*types.Header, c *Bor) (*Snapshot, error) {
 	// Allow passing in no headers for cleaner code
 	if len(headers) == 0 {
@@ -124,9 +232,21 @@ func (s *Snapshot) apply(headers []*types.Header, c *Bor) (*Snapshot, error) {
 		// Remove any votes on checkpoint blocks
 		number := header.Number.Uint64()
 
+		undo := &snapshotUndo{parent: header.ParentHash}
+
+		// Track the changes since the last stored block, to store the next one as a diff
+		if c != nil && c.snapshotDiffs && c.persistSnapshot(number-1) {
+			snap.diff = &snapshotDiffTrack{number: number - 1, hash: header.ParentHash}
+		}
+
 		// Delete the oldest signer from the recent list to allow it signing again
 		if number >= s.chainConfig.Bor.CalculateSprint(number) {
-			delete(snap.Recents, number-s.chainConfig.Bor.CalculateSprint(number))
+			evicted := number - s.chainConfig.Bor.CalculateSprint(number)
+
+			undo.evictedNumber = evicted
+			undo.evicted, undo.hasEvicted = snap.Recents[evicted]
+
+			delete(snap.Recents, evicted)
 		}
 
 		// Resolve the authorization key and check against signers
@@ -137,11 +257,11 @@ func (s *Snapshot) apply(headers []*types.Header, c *Bor) (*Snapshot, error) {
 
 		// check if signer is in validator set
 		if !snap.ValidatorSet.HasAddress(signer) {
-			return nil, &UnauthorizedSignerError{number, signer.Bytes()}
+			return nil, newSealError(snap, header, signer, &UnauthorizedSignerError{number, signer.Bytes()})
 		}
 
 		if _, err = snap.GetSignerSuccessionNumber(signer); err != nil {
-			return nil, err
+			return nil, newSealError(snap, header, signer, err)
 		}
 
 		// add recents
@@ -153,19 +273,68 @@ func (s *Snapshot) apply(headers []*types.Header, c *Bor) (*Snapshot, error) {
 				return nil, err
 			}
 
+			endStep := tracing.StartSprintStep(tracing.SprintValidatorSet, number+1, attribute.String("source", "header"))
 			validatorBytes := header.GetValidatorBytes(s.chainConfig)
 
-			// get validators from headers and use that for new validator set
-			newVals, _ := valset.ParseValidators(validatorBytes)
+			// get validators from headers and use that for new validator set. Malformed
+			// validator bytes yield an empty update until the strict extra-data fork.
+			var newVals []*valset.Validator
+
+			if s.chainConfig.Bor.IsStrictExtra(header.Number) {
+				if newVals, err = valset.ParseValidatorsStrict(validatorBytes); err != nil {
+					endStep(err)
+					return nil, err
+				}
+			} else {
+				newVals, _ = valset.ParseValidators(validatorBytes)
+			}
 			v := getUpdatedValidatorSet(snap.ValidatorSet.Copy(), newVals)
 			v.IncrementProposerPriority(1)
 
-			if v.CheckEmptyId() {
-				log.Warn("Empty id found on validator set. Querying on the validatorSet contract")
-				valsWithId, _ := c.spanner.GetCurrentValidatorsByHash(context.Background(), header.Hash(), number+1)
+			if c != nil && c.spanner != nil && v.CheckEmptyId() {
+				log.Warn("Empty id found on validator set. Querying on the validatorSet contract", "number", number, "hash", header.Hash())
+				valsWithId, _ := c.spanner.GetCurrentValidatorsByHash(c.engineCtx(), header.Hash(), number+1)
 				v.IncludeIds(valsWithId)
 			}
+
+			if snap.diff != nil {
+				snap.diff = snap.diff.withSprintEnd(number, newVals, snap.ValidatorSet, v)
+			}
+
+			undo.validators = snap.ValidatorSet.Copy()
 			snap.ValidatorSet = v
+
+			endStep(nil)
+
+			if ok, dropped := validatorSetLogs.sample(time.Now()); ok {
+				log.Debug("Updated snapshot validator set", "number", number, "hash", header.Hash(),
+					"validators", len(v.Validators), "proposer", v.GetProposer().Address, "dropped", dropped)
+			}
+
+			if c != nil && c.validatorArchive {
+				c.archiveValidators(header, v)
+			}
+
+			if c != nil && c.tracer.enabled(TraceSnapshots) {
+				c.tracer.record(TraceSnapshots, header, "validatorSetUpdate", nil, map[string]interface{}{
+					"validators": len(v.Validators),
+					"proposer":   v.GetProposer().Address,
+				})
+			}
+		}
+
+		if ok, dropped := appliedHeaderLogs.sample(time.Now()); ok {
+			log.Trace("Applied header to snapshot", "number", number, "hash", header.Hash(), "signer", signer, "dropped", dropped)
+		}
+
+		if c != nil && c.tracer.enabled(TraceSnapshots) {
+			c.tracer.record(TraceSnapshots, header, "applySnapshot", nil, map[string]interface{}{
+				"signer": signer,
+			})
+		}
+
+		if c != nil && c.undos != nil {
+			c.undos.Add(header.Hash(), undo)
 		}
 	}
 
@@ -175,6 +344,94 @@ func (s *Snapshot) apply(headers []*types.Header, c *Bor) (*Snapshot, error) {
 	return snap, nil
 }
 
+// snapshotUndo records the changes a single header made to a snapshot, so that
+// they can be reverted if the header is reorged out.
+type snapshotUndo struct {
+	parent        common.Hash          // Hash of the block the header was applied on
+	evictedNumber uint64               // Block number of the recent signer evicted by the header
+	evicted       common.Address       // Recent signer evicted by the header
+	hasEvicted    bool                 // Whether a recent signer was evicted at all
+	validators    *valset.ValidatorSet // Validator set replaced by a sprint end header, nil otherwise
+}
+
+// revert undoes the application of the snapshot's head header, moving the
+// snapshot back to the parent block.
+func (s *Snapshot) revert(undo *snapshotUndo) {
+	delete(s.Recents, s.Number)
+
+	if undo.hasEvicted {
+		s.Recents[undo.evictedNumber] = undo.evicted
+	}
+
+	if undo.validators != nil {
+		s.ValidatorSet = undo.validators.Copy()
+	}
+
+	s.Number--
+	s.Hash = undo.parent
+
+	// The changes tracked since the last stored block can't be rewound
+	s.diff = nil
+}
+
+// snapshotRewinder walks a snapshot back header by header using the undo records
+// of the applied headers. It allows resolving the snapshot of a recent ancestor
+// after a shallow reorg without replaying the headers from an older snapshot.
+type snapshotRewinder struct {
+	snap   *Snapshot     // Snapshot being rewound, nil if rewinding is no longer possible
+	undos  *lru.ARCCache // Undo records of recently applied headers
+	limit  uint64        // Lowest block number the snapshot may be rewound to
+	copied bool          // Whether snap is a private copy safe to modify
+}
+
+// newSnapshotRewinder creates a rewinder starting at the given snapshot, which
+// is only copied once it actually needs to be modified.
+func newSnapshotRewinder(snap *Snapshot, undos *lru.ARCCache) *snapshotRewinder {
+	r := &snapshotRewinder{snap: snap, undos: undos}
+	if snap != nil && snap.Number > snapshotUndoDepth {
+		r.limit = snap.Number - snapshotUndoDepth
+	}
+
+	return r
+}
+
+// rewind attempts to rewind the snapshot to the given block, returning nil if
+// the block is not an ancestor reachable through the undo records. Blocks must
+// be requested in descending order.
+func (r *snapshotRewinder) rewind(number uint64, hash common.Hash) *Snapshot {
+	if r.snap == nil || r.undos == nil {
+		return nil
+	}
+
+	if number < r.limit {
+		r.snap = nil
+		return nil
+	}
+
+	for r.snap.Number > number {
+		undo, ok := r.undos.Get(r.snap.Hash)
+		if !ok {
+			r.snap = nil
+			return nil
+		}
+
+		if !r.copied {
+			r.snap, r.copied = r.snap.copy(), true
+		}
+
+		r.snap.revert(undo.(*snapshotUndo))
+	}
+
+	if r.snap.Number != number || r.snap.Hash != hash {
+		return nil
+	}
+
+	snap := r.snap
+	r.snap = nil
+
+	return snap
+}
+
 // GetSignerSuccessionNumber returns the relative position of signer in terms of the in-turn proposer
 func (s *Snapshot) GetSignerSuccessionNumber(signer common.Address) (int, error) {
 	validators := s.ValidatorSet.Validators
@@ -201,6 +458,38 @@ func (s *Snapshot) GetSignerSuccessionNumber(signer common.Address) (int, error)
 	return tempIndex - proposerIndex, nil
 }
 
+// GetSignerBackoffRank returns the position of signer in the backup producer
+// order. By default this is the succession number, if byStake is set, backup
+// producers are ordered by voting power instead, falling back to the
+// succession number among equal stakes.
+func (s *Snapshot) GetSignerBackoffRank(signer common.Address, byStake bool) (int, error) {
+	succession, err := s.GetSignerSuccessionNumber(signer)
+	if err != nil || !byStake || succession == 0 {
+		return succession, err
+	}
+
+	var (
+		validators       = s.ValidatorSet.Validators
+		proposerIndex, _ = s.ValidatorSet.GetByAddress(s.ValidatorSet.GetProposer().Address)
+		signerIndex, _   = s.ValidatorSet.GetByAddress(signer)
+		power            = validators[signerIndex].VotingPower
+		rank             = 1
+	)
+
+	for i, other := range validators {
+		if i == signerIndex || i == proposerIndex {
+			continue
+		}
+
+		otherSuccession := (i - proposerIndex + len(validators)) % len(validators)
+		if other.VotingPower > power || (other.VotingPower == power && otherSuccession < succession) {
+			rank++
+		}
+	}
+
+	return rank, nil
+}
+
 // signers retrieves the list of authorized signers in ascending order.
 func (s *Snapshot) signers() []common.Address {
 	sigs := make([]common.Address, 0, len(s.ValidatorSet.Validators))
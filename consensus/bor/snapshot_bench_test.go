@@ -0,0 +1,143 @@
+package bor
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	benchSprint  = 16        // Sprint length of the benchmarked chain
+	benchHeaders = 1_000_000 // Number of headers a snapshot is reconstructed over
+)
+
+// benchChainConfig is the chain snapshot reconstruction is benchmarked on.
+var benchChainConfig = &params.ChainConfig{
+	ChainID: big.NewInt(1),
+	Bor:     &params.BorConfig{Sprint: map[string]uint64{"0": benchSprint}},
+}
+
+// benchChain generates the headers of a chain in batches, so that a million of
+// them never need to be held in memory at once. The headers aren't signed, the
+// signers are put in the signature cache instead, keeping the cost of the
+// elliptic curve recovery out of the measurements.
+type benchChain struct {
+	validators []*valset.Validator
+	sigcache   *lru.ARCCache
+
+	number uint64
+	parent common.Hash
+}
+
+// newBenchChain creates a chain of n validators of differing voting power.
+func newBenchChain(n int) *benchChain {
+	validators := make([]*valset.Validator, n)
+	for i := range validators {
+		validators[i] = &valset.Validator{
+			ID:          uint64(i + 1),
+			Address:     common.BigToAddress(big.NewInt(int64(i + 1))),
+			VotingPower: int64(100 + i%50),
+		}
+	}
+
+	sigcache, _ := lru.NewARC(inmemorySignatures)
+
+	return &benchChain{
+		validators: validators,
+		sigcache:   sigcache,
+		parent:     common.HexToHash("0x01"),
+	}
+}
+
+// next generates the next count headers of the chain. The sprints are signed by
+// the validators in turn, and every sprint end header changes the voting power
+// of one validator, so that the validator set update isn't a no-op.
+func (c *benchChain) next(count int) []*types.Header {
+	headers := make([]*types.Header, count)
+
+	for i := range headers {
+		c.number++
+
+		header := &types.Header{
+			ParentHash: c.parent,
+			Number:     new(big.Int).SetUint64(c.number),
+			Difficulty: big.NewInt(1),
+			Extra:      make([]byte, types.ExtraVanityLength),
+		}
+
+		sprint := c.number / benchSprint
+
+		if (c.number+1)%benchSprint == 0 {
+			// Replace rather than modify the validator, it may be shared with the snapshot
+			index := sprint % uint64(len(c.validators))
+
+			validator := *c.validators[index]
+			validator.VotingPower ^= 1
+			c.validators[index] = &validator
+
+			header.Extra = append(header.Extra, valset.EncodeValidators(c.validators, valset.ValidatorBytesV1)...)
+		}
+
+		header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
+
+		c.parent = header.Hash()
+		c.sigcache.Add(c.parent, c.validators[sprint%uint64(len(c.validators))].Address)
+
+		headers[i] = header
+	}
+
+	return headers
+}
+
+// benchmarkSnapshotApply measures reconstructing the snapshot of a chain of n
+// validators from the genesis, applying the headers in batches of the
+// checkpoint interval like the engine does between persisted snapshots.
+func benchmarkSnapshotApply(b *testing.B, n int) {
+	headers := uint64(benchHeaders)
+	if testing.Short() {
+		headers = 16 * checkpointInterval
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+
+		var (
+			chain    = newBenchChain(n)
+			undos, _ = lru.NewARC(inmemoryUndos)
+			engine   = &Bor{undos: undos}
+			snap     = newSnapshot(benchChainConfig, chain.sigcache, 0, chain.parent, chain.validators)
+		)
+
+		for snap.Number < headers {
+			batch := chain.next(int(min(checkpointInterval, headers-snap.Number)))
+
+			b.StartTimer()
+
+			var err error
+			if snap, err = snap.apply(batch, engine); err != nil {
+				b.Fatal(err)
+			}
+
+			b.StopTimer()
+		}
+	}
+
+	b.ReportMetric(float64(headers*uint64(b.N))/b.Elapsed().Seconds(), "headers/s")
+}
+
+func BenchmarkSnapshotApply(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("validators=%d", n), func(b *testing.B) {
+			benchmarkSnapshotApply(b, n)
+		})
+	}
+}
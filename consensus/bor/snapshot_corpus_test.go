@@ -0,0 +1,170 @@
+package bor
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// corpusValidator is a validator of the snapshot corpus.
+type corpusValidator struct {
+	Signer       common.Address `json:"signer"`
+	Power        int64          `json:"power"`
+	Accum        int64          `json:"accum"`
+	BLSPublicKey hexutil.Bytes  `json:"blsPubKey,omitempty"`
+}
+
+// corpusEntry is a snapshot of the corpus along with the state expected once
+// loaded, and once a sprint end is applied on top.
+type corpusEntry struct {
+	File        string            `json:"file"`
+	Description string            `json:"description"`
+	Number      uint64            `json:"number"`
+	Hash        common.Hash       `json:"hash"`
+	Proposer    common.Address    `json:"proposer"`
+	SprintEnd   []corpusValidator `json:"sprintEnd"`
+	Applied     struct {
+		Proposer   common.Address    `json:"proposer"`
+		Validators []corpusValidator `json:"validators"`
+	} `json:"applied"`
+}
+
+// Tests that the snapshots persisted by previous releases can still be loaded
+// and applied, see testdata/snapshots/README.md.
+func TestSnapshotCorpus(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("testdata", "snapshots")
+
+	manifest, err := os.ReadFile(filepath.Join(dir, "corpus.json"))
+	require.NoError(t, err)
+
+	var entries []*corpusEntry
+	require.NoError(t, json.Unmarshal(manifest, &entries))
+	require.NotEmpty(t, entries)
+
+	keys := make(map[common.Address]*ecdsa.PrivateKey)
+
+	for i := 0; i < 3; i++ {
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("snapshot corpus %d", i))))
+		require.NoError(t, err)
+
+		keys[crypto.PubkeyToAddress(key.PublicKey)] = key
+	}
+
+	chainConfig := &params.ChainConfig{
+		ChainID: big.NewInt(1),
+		Bor:     &params.BorConfig{Sprint: map[string]uint64{"0": 16}},
+	}
+
+	for _, entry := range entries {
+		entry := entry
+
+		t.Run(entry.File, func(t *testing.T) {
+			t.Parallel()
+
+			blob, err := os.ReadFile(filepath.Join(dir, entry.File))
+			require.NoError(t, err)
+
+			if filepath.Ext(entry.File) == ".hex" {
+				blob, err = hex.DecodeString(strings.TrimSpace(string(blob)))
+				require.NoError(t, err)
+			}
+
+			db := rawdb.NewMemoryDatabase()
+			require.NoError(t, db.Put(snapshotKey(entry.Hash), blob))
+
+			sigcache, _ := lru.NewARC(inmemorySignatures)
+
+			snap, err := loadSnapshot(chainConfig, chainConfig.Bor, sigcache, db, entry.Hash)
+			require.NoError(t, err)
+			require.Equal(t, entry.Number, snap.Number)
+			require.Equal(t, entry.Proposer, snap.ValidatorSet.GetProposer().Address)
+
+			// Seal the rest of the sprint with the proposer, the sprint end header
+			// carrying the next validator set
+			var (
+				sprintEnd = make([]*valset.Validator, len(entry.SprintEnd))
+				version   = byte(valset.ValidatorBytesV1)
+			)
+
+			for i, validator := range entry.SprintEnd {
+				sprintEnd[i] = valset.NewValidator(validator.Signer, validator.Power)
+				sprintEnd[i].BLSPublicKey = validator.BLSPublicKey
+
+				if len(validator.BLSPublicKey) > 0 {
+					version = valset.ValidatorBytesV2
+				}
+			}
+
+			var (
+				headers = make([]*types.Header, 16)
+				parent  = entry.Hash
+			)
+
+			for i := range headers {
+				header := &types.Header{
+					ParentHash: parent,
+					Number:     new(big.Int).SetUint64(entry.Number + uint64(i) + 1),
+					Difficulty: big.NewInt(1),
+					Extra:      make([]byte, types.ExtraVanityLength),
+				}
+
+				if i == len(headers)-1 {
+					header.Extra = append(header.Extra, valset.EncodeValidators(sprintEnd, version)...)
+				}
+
+				header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
+
+				sig, err := crypto.Sign(SealHash(header, chainConfig.Bor).Bytes(), keys[entry.Proposer])
+				require.NoError(t, err)
+
+				copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sig)
+
+				headers[i], parent = header, header.Hash()
+			}
+
+			applied, err := snap.apply(headers, nil)
+			require.NoError(t, err)
+			require.Equal(t, headers[len(headers)-1].Hash(), applied.Hash)
+			require.Len(t, applied.Recents, 16)
+			require.Equal(t, entry.Applied.Proposer, applied.ValidatorSet.GetProposer().Address)
+
+			validators := make([]corpusValidator, len(applied.ValidatorSet.Validators))
+			for i, validator := range applied.ValidatorSet.Validators {
+				validators[i] = corpusValidator{
+					Signer:       validator.Address,
+					Power:        validator.VotingPower,
+					Accum:        validator.ProposerPriority,
+					BLSPublicKey: validator.BLSPublicKey,
+				}
+			}
+
+			require.Equal(t, entry.Applied.Validators, validators)
+
+			// The snapshot is persisted in the current format and loaded back
+			require.NoError(t, applied.store(db))
+
+			reloaded, err := loadSnapshot(chainConfig, chainConfig.Bor, sigcache, db, applied.Hash)
+			require.NoError(t, err)
+			require.Equal(t, applied.ValidatorSet.Validators, reloaded.ValidatorSet.Validators)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package bor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// snapshotDiff is a snapshot stored as its changes over the snapshot stored
+// before it: the validator set updates of the sprint ends in between, and the
+// recents window. It's reconstructed by replaying the updates on the parent
+// snapshot, without reading or recovering the signers of the headers.
+type snapshotDiff struct {
+	Parent     common.Hash               `json:"parent"` // Block of the stored snapshot the diff applies to
+	Number     uint64                    `json:"number"`
+	Hash       common.Hash               `json:"hash"`
+	SprintEnds []*snapshotSprintEnd      `json:"sprintEnds,omitempty"`
+	Recents    map[uint64]common.Address `json:"recents"`
+}
+
+// snapshotSprintEnd is the validator set update of a sprint end header.
+type snapshotSprintEnd struct {
+	Number     uint64              `json:"number"`
+	Validators []*valset.Validator `json:"validators,omitempty"` // Validators of the header with their ids, nil if the same as before
+}
+
+// snapshotDiffTrack is the change of a snapshot since the last stored block it
+// descends from. It's never modified once created, as it's shared by copies.
+type snapshotDiffTrack struct {
+	number     uint64      // Number of the last stored block
+	hash       common.Hash // Hash of the last stored block
+	sprintEnds []*snapshotSprintEnd
+}
+
+// withSprintEnd returns the tracked changes along with the validator set update
+// of the sprint end header of the given block, from the old to the new set.
+func (t *snapshotDiffTrack) withSprintEnd(number uint64, newVals []*valset.Validator, old *valset.ValidatorSet, updated *valset.ValidatorSet) *snapshotDiffTrack {
+	sprintEnd := &snapshotSprintEnd{Number: number}
+
+	// The ids are resolved once the set is updated
+	validators := make([]*valset.Validator, len(newVals))
+	for i, val := range newVals {
+		validators[i] = val.Copy()
+		validators[i].ProposerPriority = 0
+
+		if _, resolved := updated.GetByAddress(val.Address); resolved != nil {
+			validators[i].ID = resolved.ID
+		}
+	}
+
+	if !sameValidators(validators, old) {
+		sprintEnd.Validators = validators
+	}
+
+	sprintEnds := make([]*snapshotSprintEnd, len(t.sprintEnds), len(t.sprintEnds)+1)
+	copy(sprintEnds, t.sprintEnds)
+
+	return &snapshotDiffTrack{number: t.number, hash: t.hash, sprintEnds: append(sprintEnds, sprintEnd)}
+}
+
+// sameValidators reports whether the validators are the ones of the set, with
+// the same voting power, key and id.
+func sameValidators(validators []*valset.Validator, set *valset.ValidatorSet) bool {
+	if len(validators) != len(set.Validators) {
+		return false
+	}
+
+	seen := make(map[common.Address]struct{}, len(validators))
+
+	for _, val := range validators {
+		_, existing := set.GetByAddress(val.Address)
+		if existing == nil || existing.VotingPower != val.VotingPower || existing.ID != val.ID || string(existing.BLSPublicKey) != string(val.BLSPublicKey) {
+			return false
+		}
+
+		if _, ok := seen[val.Address]; ok {
+			return false
+		}
+
+		seen[val.Address] = struct{}{}
+	}
+
+	return true
+}
+
+// storeSnapshot stores the snapshot to the database, as a diff over the stored
+// snapshot before it if diffs are enabled, the snapshot is not one of those
+// stored in full and its changes since the one before are tracked.
+func (c *Bor) storeSnapshot(snap *Snapshot) error {
+	if c.snapshotDiffs && snap.Number%c.fullSnapshotInterval(snap.Number) != 0 && snap.diff != nil && c.nextStoredSnapshot(snap.diff.number) == snap.Number {
+		// A parent only computed in the middle of a batch of headers isn't stored
+		if ok, _ := c.db.Has(snapshotKey(snap.diff.hash)); ok {
+			return snap.storeDiff(c.db)
+		}
+	}
+
+	return snap.store(c.db)
+}
+
+// storeDiff inserts the snapshot into the database as a diff over the last
+// stored block it descends from.
+func (s *Snapshot) storeDiff(db ethdb.Database) error {
+	diff := &snapshotDiff{
+		Parent:     s.diff.hash,
+		Number:     s.Number,
+		Hash:       s.Hash,
+		SprintEnds: s.diff.sprintEnds,
+		Recents:    s.Recents,
+	}
+
+	return storeSnapshotBlob(db, s.Hash, snapshotDiffVersion, diff)
+}
+
+// loadSnapshotDiff reconstructs a snapshot stored as a diff from the stored
+// snapshot of its parent, which may be a diff itself.
+func loadSnapshotDiff(chainConfig *params.ChainConfig, config *params.BorConfig, sigcache *lru.ARCCache, db ethdb.Database, hash common.Hash, blob []byte) (*Snapshot, error) {
+	diff := new(snapshotDiff)
+
+	if err := json.Unmarshal(blob, diff); err != nil {
+		return nil, fmt.Errorf("%w: %v", errSnapshotCorrupt, err)
+	}
+
+	if diff.Hash != hash || diff.Recents == nil {
+		return nil, fmt.Errorf("%w: invalid diff content", errSnapshotCorrupt)
+	}
+
+	parent, err := loadSnapshot(chainConfig, config, sigcache, db, diff.Parent)
+	if err != nil {
+		return nil, fmt.Errorf("parent %x of diff: %w", diff.Parent, err)
+	}
+
+	if parent.Number >= diff.Number {
+		return nil, fmt.Errorf("%w: diff of block %d over block %d", errSnapshotCorrupt, diff.Number, parent.Number)
+	}
+
+	for _, sprintEnd := range diff.SprintEnds {
+		if sprintEnd.Number <= parent.Number || sprintEnd.Number > diff.Number {
+			return nil, fmt.Errorf("%w: sprint end %d out of diff range", errSnapshotCorrupt, sprintEnd.Number)
+		}
+	}
+
+	return parent.applyDiff(diff)
+}
+
+// applyDiff returns the snapshot the diff over this one stands for. The sprint
+// end updates are applied the way apply does, with the ids of the validators
+// resolved at the time rather than queried again.
+func (s *Snapshot) applyDiff(diff *snapshotDiff) (*Snapshot, error) {
+	snap := s.copy()
+	snap.diff = nil
+
+	for _, sprintEnd := range diff.SprintEnds {
+		var newVals []*valset.Validator
+
+		if sprintEnd.Validators != nil {
+			newVals = make([]*valset.Validator, len(sprintEnd.Validators))
+			for i, val := range sprintEnd.Validators {
+				newVals[i] = val.Copy()
+			}
+		} else {
+			newVals = snap.ValidatorSet.Copy().Validators
+		}
+
+		v := getUpdatedValidatorSet(snap.ValidatorSet.Copy(), newVals)
+		v.IncrementProposerPriority(1)
+
+		for _, val := range newVals {
+			if i, _ := v.GetByAddress(val.Address); i >= 0 {
+				v.Validators[i].ID = val.ID
+			}
+		}
+
+		snap.ValidatorSet = v
+	}
+
+	if err := snap.ValidatorSet.UpdateTotalVotingPower(); err != nil {
+		return nil, err
+	}
+
+	snap.Number = diff.Number
+	snap.Hash = diff.Hash
+	snap.Recents = make(map[uint64]common.Address, len(diff.Recents))
+
+	for number, signer := range diff.Recents {
+		snap.Recents[number] = signer
+	}
+
+	return snap, nil
+}
@@ -0,0 +1,135 @@
+package bor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the snapshots between the ones of the interval are stored as
+// diffs once enabled, and reconstructed into the snapshots they stand for.
+func TestSnapshotDiffs(t *testing.T) {
+	t.Parallel()
+
+	engine, chain := newHealEngine(t, 101)
+	engine.SetSnapshotDiffs(true)
+
+	// Resolve the snapshots block by block, as when following the chain head
+	snaps := make(map[uint64]*Snapshot)
+
+	for number := uint64(1); number <= 100; number++ {
+		snap, err := engine.snapshot(chain, number, chain.headers[number].Hash(), nil)
+		require.NoError(t, err)
+
+		snaps[number] = snap
+	}
+
+	for number := uint64(4); number <= 100; number += 4 {
+		hash := chain.headers[number].Hash()
+
+		blob, err := engine.db.Get(snapshotKey(hash))
+		require.NoError(t, err)
+
+		if number%16 == 0 {
+			require.Equal(t, byte(snapshotSnappyVersion), blob[0], "block %d", number)
+		} else {
+			require.Equal(t, byte(snapshotDiffVersion), blob[0], "block %d", number)
+		}
+
+		loaded, err := loadSnapshot(chain.config, chain.config.Bor, engine.signatures, engine.db, hash)
+		require.NoError(t, err)
+		require.Equal(t, snaps[number].Number, loaded.Number)
+		require.Equal(t, snaps[number].Hash, loaded.Hash)
+		require.Equal(t, snaps[number].Recents, loaded.Recents)
+		require.Equal(t, snaps[number].ValidatorSet.Validators, loaded.ValidatorSet.Validators)
+		require.Equal(t, snaps[number].ValidatorSet.Proposer, loaded.ValidatorSet.Proposer)
+	}
+
+	// Diffs whose parent is gone can't be reconstructed, the snapshot is rebuilt
+	// from the headers instead
+	require.NoError(t, quarantineSnapshot(engine.db, chain.headers[88].Hash()))
+
+	_, err := loadSnapshot(chain.config, chain.config.Bor, engine.signatures, engine.db, chain.headers[92].Hash())
+	require.Error(t, err)
+	require.NotErrorIs(t, err, errSnapshotCorrupt)
+
+	engine.recents.Purge()
+	engine.latestSnap.Store(nil)
+
+	snap, err := engine.snapshot(chain, 92, chain.headers[92].Hash(), nil)
+	require.NoError(t, err)
+	require.Equal(t, snaps[92].ValidatorSet.Validators, snap.ValidatorSet.Validators)
+}
+
+// Tests that the validator set updates of the sprint ends are replayed from a
+// diff the same way they were applied from the headers.
+func TestSnapshotDiffValidatorUpdates(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = common.Address{0x1}
+		bob   = common.Address{0x2}
+		carol = common.Address{0x3}
+
+		config = &params.ChainConfig{Bor: &params.BorConfig{Sprint: map[string]uint64{"0": 4}}}
+		db     = rawdb.NewMemoryDatabase()
+	)
+
+	validator := func(address common.Address, power int64, id uint64) *valset.Validator {
+		val := valset.NewValidator(address, power)
+		val.ID = id
+
+		return val
+	}
+
+	parent := newSnapshot(config, nil, 16, common.Hash{0x16}, []*valset.Validator{validator(alice, 10, 1), validator(bob, 10, 2)})
+	require.NoError(t, parent.store(db))
+
+	var (
+		track = &snapshotDiffTrack{number: parent.Number, hash: parent.Hash}
+		set   = parent.ValidatorSet
+	)
+
+	// Validator updates as carried by the sprint end headers, without ids: carol
+	// joins and bob's power changes, then nothing changes
+	for i, newVals := range [][]*valset.Validator{
+		{validator(alice, 10, 0), validator(bob, 20, 0), validator(carol, 5, 0)},
+		{validator(alice, 10, 0), validator(bob, 20, 0), validator(carol, 5, 0)},
+	} {
+		v := getUpdatedValidatorSet(set.Copy(), newVals)
+		v.IncrementProposerPriority(1)
+
+		if v.CheckEmptyId() {
+			v.IncludeIds([]*valset.Validator{validator(alice, 10, 1), validator(bob, 20, 2), validator(carol, 5, 3)})
+		}
+
+		track = track.withSprintEnd(uint64(19+4*i), newVals, set, v)
+		set = v
+	}
+
+	require.NotNil(t, track.sprintEnds[0].Validators)
+	require.Nil(t, track.sprintEnds[1].Validators)
+
+	snap := &Snapshot{
+		chainConfig:  config,
+		Number:       24,
+		Hash:         common.Hash{0x24},
+		ValidatorSet: set,
+		Recents:      map[uint64]common.Address{21: alice, 22: bob, 23: carol, 24: alice},
+		diff:         track,
+	}
+	require.NoError(t, snap.storeDiff(db))
+
+	loaded, err := loadSnapshot(config, config.Bor, nil, db, snap.Hash)
+	require.NoError(t, err)
+	require.Equal(t, snap.Recents, loaded.Recents)
+	require.Equal(t, set.Validators, loaded.ValidatorSet.Validators)
+	require.Equal(t, set.Proposer, loaded.ValidatorSet.Proposer)
+	require.Equal(t, set.TotalVotingPower(), loaded.ValidatorSet.TotalVotingPower())
+	require.Equal(t, uint64(3), loaded.ValidatorSet.Validators[2].ID)
+}
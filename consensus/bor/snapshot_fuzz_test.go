@@ -0,0 +1,288 @@
+package bor
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	fuzzSprint         = 4  // Sprint length of the fuzzed chain
+	fuzzMaxHeaders     = 64 // Maximum number of headers applied per input, the undo depth
+	fuzzValidatorCount = 4  // Number of validators the fuzzed chain starts with
+
+	// fuzzHeadersEnv names a file of blocks exported by `bor export --format jsonl
+	// --sprint-end`, whose validator sets are added to the seed corpus.
+	fuzzHeadersEnv = "BOR_FUZZ_HEADERS"
+)
+
+// fuzzChainConfig is the chain the snapshot fuzzer applies headers of. The
+// strict extra-data and Cancun forks activate midway, so that both layouts and
+// both parsers of the validator set are exercised.
+var fuzzChainConfig = &params.ChainConfig{
+	ChainID:     big.NewInt(1),
+	CancunBlock: big.NewInt(32),
+	Bor: &params.BorConfig{
+		Sprint:           map[string]uint64{"0": fuzzSprint},
+		StrictExtraBlock: big.NewInt(16),
+	},
+}
+
+// fuzzKeys returns the keys of the validators the fuzzed chain starts with,
+// followed by a key outside of the validator set.
+func fuzzKeys(t testing.TB) []*ecdsa.PrivateKey {
+	t.Helper()
+
+	keys := make([]*ecdsa.PrivateKey, fuzzValidatorCount+1)
+
+	for i := range keys {
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("snapshot fuzz %d", i))))
+		require.NoError(t, err)
+
+		keys[i] = key
+	}
+
+	return keys
+}
+
+// decodeFuzzHeaders turns a fuzzer input into a chain of signed headers on
+// top of the given parent. Every header takes a byte selecting its signer, and
+// sprint end headers take a big endian uint16 length followed by as many bytes
+// of validator set, wrapped in the block extra data after Cancun.
+func decodeFuzzHeaders(t testing.TB, keys []*ecdsa.PrivateKey, parent common.Hash, data []byte) []*types.Header {
+	t.Helper()
+
+	headers := make([]*types.Header, 0, fuzzMaxHeaders)
+
+	for number := uint64(1); len(data) > 0 && number <= fuzzMaxHeaders; number++ {
+		key := keys[int(data[0])%len(keys)]
+		data = data[1:]
+
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: big.NewInt(1),
+			Extra:      make([]byte, types.ExtraVanityLength),
+		}
+
+		if (number+1)%fuzzSprint == 0 {
+			var validators []byte
+
+			if len(data) >= 2 {
+				n := min(int(binary.BigEndian.Uint16(data)), len(data)-2)
+				validators, data = data[2:2+n], data[2+n:]
+			} else {
+				data = nil
+			}
+
+			if fuzzChainConfig.IsCancun(header.Number) {
+				extra, err := rlp.EncodeToBytes(&types.BlockExtraData{ValidatorBytes: validators})
+				require.NoError(t, err)
+
+				validators = extra
+			}
+
+			header.Extra = append(header.Extra, validators...)
+		}
+
+		header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
+
+		sig, err := crypto.Sign(SealHash(header, fuzzChainConfig.Bor).Bytes(), key)
+		require.NoError(t, err)
+
+		copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sig)
+
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+
+	return headers
+}
+
+// encodeFuzzHeaders is the inverse of decodeFuzzHeaders, encoding the signer
+// of every header and the validator sets of the sprint end headers.
+func encodeFuzzHeaders(signers []byte, sets [][]byte) []byte {
+	var data []byte
+
+	for i, signer := range signers {
+		data = append(data, signer)
+
+		if (uint64(i)+2)%fuzzSprint == 0 {
+			var validators []byte
+			if len(sets) > 0 {
+				validators, sets = sets[0], sets[1:]
+			}
+
+			data = binary.BigEndian.AppendUint16(data, uint16(len(validators)))
+			data = append(data, validators...)
+		}
+	}
+
+	return data
+}
+
+// fuzzValidatorSet encodes a validator set of the given keys in the layout of
+// the given version.
+func fuzzValidatorSet(keys []*ecdsa.PrivateKey, version byte, indexes ...int) []byte {
+	validators := make([]*valset.Validator, len(indexes))
+
+	for i, index := range indexes {
+		validators[i] = valset.NewValidator(crypto.PubkeyToAddress(keys[index].PublicKey), int64(10*(i+1)))
+
+		if version == valset.ValidatorBytesV2 {
+			validators[i].BLSPublicKey = bytes.Repeat([]byte{byte(index + 1)}, 48)
+		}
+	}
+
+	return valset.EncodeValidators(validators, version)
+}
+
+// addExportedSeeds adds the validator sets of the sprint end headers exported
+// to the file named by fuzzHeadersEnv to the seed corpus, if set.
+func addExportedSeeds(f *testing.F, signers []byte) {
+	f.Helper()
+
+	path := os.Getenv(fuzzHeadersEnv)
+	if path == "" {
+		return
+	}
+
+	file, err := os.Open(path)
+	require.NoError(f, err)
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024)
+
+	for scanner.Scan() {
+		var block struct {
+			Header *types.Header `json:"header"`
+		}
+
+		require.NoError(f, json.Unmarshal(scanner.Bytes(), &block))
+
+		if block.Header == nil || validateHeaderExtraField(block.Header.Extra) != nil {
+			continue
+		}
+
+		if validators := block.Header.GetValidatorBytes(params.BorMainnetChainConfig); len(validators) > 0 {
+			f.Add(encodeFuzzHeaders(signers, [][]byte{validators}))
+		}
+	}
+
+	require.NoError(f, scanner.Err())
+}
+
+// FuzzSnapshotApply applies adversarial chains of headers to a snapshot. It
+// checks that applying never panics, that applying the headers in a single
+// batch or one by one agrees, that the snapshot applied on is left untouched,
+// and that the result survives persisting and rewinding back to the start.
+func FuzzSnapshotApply(f *testing.F) {
+	keys := fuzzKeys(f)
+
+	var (
+		steady   = bytes.Repeat([]byte{0}, fuzzMaxHeaders)
+		rotating = make([]byte, fuzzMaxHeaders)
+		current  = fuzzValidatorSet(keys, valset.ValidatorBytesV1, 0, 1, 2, 3)
+	)
+
+	for i := range rotating {
+		rotating[i] = byte(i / fuzzSprint % fuzzValidatorCount)
+	}
+
+	f.Add(encodeFuzzHeaders(steady, nil))
+	f.Add(encodeFuzzHeaders(steady, [][]byte{current, current, current, current, current, current, current, current}))
+	f.Add(encodeFuzzHeaders(rotating, [][]byte{
+		current,
+		fuzzValidatorSet(keys, valset.ValidatorBytesV1, 0, 1, 2, 3, 4),
+		fuzzValidatorSet(keys, valset.ValidatorBytesV2, 1, 2, 3),
+		fuzzValidatorSet(keys, valset.ValidatorBytesV2, 0, 1, 2, 3),
+		{0xff, 0x00},
+		fuzzValidatorSet(keys, valset.ValidatorBytesV1, 1, 1, 2),
+		fuzzValidatorSet(keys, valset.ValidatorBytesV1),
+		fuzzValidatorSet(keys, valset.ValidatorBytesV2, 0, 1, 2, 3),
+	}))
+	f.Add(encodeFuzzHeaders([]byte{0, 4, 0}, nil))
+
+	addExportedSeeds(f, steady)
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		validators := make([]*valset.Validator, fuzzValidatorCount)
+		for i := range validators {
+			validators[i] = &valset.Validator{ID: uint64(i + 1), Address: crypto.PubkeyToAddress(keys[i].PublicKey), VotingPower: 10}
+		}
+
+		sigcache, _ := lru.NewARC(inmemorySignatures)
+
+		base := newSnapshot(fuzzChainConfig, sigcache, 0, common.HexToHash("0x01"), validators)
+		untouched := base.copy()
+
+		headers := decodeFuzzHeaders(t, keys, base.Hash, data)
+		if len(headers) == 0 {
+			return
+		}
+
+		undos, _ := lru.NewARC(inmemoryUndos)
+
+		batch, batchErr := base.apply(headers, &Bor{undos: undos})
+
+		var (
+			single    = base
+			singleErr error
+		)
+
+		for _, header := range headers {
+			if single, singleErr = single.apply([]*types.Header{header}, nil); singleErr != nil {
+				break
+			}
+		}
+
+		require.Equal(t, untouched.Number, base.Number)
+		require.Equal(t, untouched.Recents, base.Recents)
+		require.Equal(t, untouched.ValidatorSet.Validators, base.ValidatorSet.Validators)
+
+		if batchErr != nil {
+			require.EqualError(t, singleErr, batchErr.Error())
+			return
+		}
+
+		require.NoError(t, singleErr)
+		require.Equal(t, headers[len(headers)-1].Hash(), batch.Hash)
+		require.Equal(t, single.Number, batch.Number)
+		require.Equal(t, single.Hash, batch.Hash)
+		require.Equal(t, single.Recents, batch.Recents)
+		require.Equal(t, single.ValidatorSet.Validators, batch.ValidatorSet.Validators)
+		require.Equal(t, single.ValidatorSet.GetProposer().Address, batch.ValidatorSet.GetProposer().Address)
+
+		db := rawdb.NewMemoryDatabase()
+		require.NoError(t, batch.store(db))
+
+		loaded, err := loadSnapshot(fuzzChainConfig, fuzzChainConfig.Bor, sigcache, db, batch.Hash)
+		require.NoError(t, err)
+		require.Equal(t, batch.Recents, loaded.Recents)
+		require.Equal(t, batch.ValidatorSet.Validators, loaded.ValidatorSet.Validators)
+
+		rewound := newSnapshotRewinder(batch, undos).rewind(base.Number, base.Hash)
+		require.NotNil(t, rewound)
+		require.Equal(t, untouched.Recents, rewound.Recents)
+		require.Equal(t, untouched.ValidatorSet.Validators, rewound.ValidatorSet.Validators)
+	})
+}
@@ -0,0 +1,130 @@
+package bor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// snapshotHealIntervals is the number of persistence intervals the last stored
+// snapshot may lag behind the chain head at startup before the missing ones are
+// rebuilt. A cleanly stopped node lags by less than one interval.
+const snapshotHealIntervals = 2
+
+var snapshotHealedCounter = metrics.NewRegisteredCounter("bor/snapshots/healed", nil)
+
+// healSnapshots checks whether the snapshots stored to the database lag far
+// behind the chain head, as they do after an unclean shutdown, and if so
+// rebuilds and stores the missing ones, one persistence interval at a time, so
+// that every step only replays the headers of a single interval. The
+// verification of the next sprint would replay all the headers since the last
+// stored snapshot on the critical path otherwise.
+func (c *Bor) healSnapshots(chain consensus.ChainHeaderReader) {
+	head := chain.CurrentHeader()
+	if head == nil {
+		return
+	}
+
+	number := head.Number.Uint64()
+
+	stored, ok := c.lastStoredSnapshot(chain, number)
+	if !ok || number-stored <= snapshotHealIntervals*c.persistInterval(number) {
+		return
+	}
+
+	log.Warn("Stored snapshots lag behind the chain head, rebuilding", "stored", stored, "head", number, "lag", number-stored)
+
+	start := time.Now()
+
+	healed, err := c.rebuildSnapshots(chain, stored, number)
+	snapshotHealedCounter.Inc(int64(healed))
+
+	if err != nil {
+		// Rebuilding is only aborted silently by the engine shutting down
+		if c.engineCtx().Err() == nil {
+			log.Warn("Failed to rebuild snapshots", "err", err)
+		}
+
+		return
+	}
+
+	log.Info("Rebuilt snapshots", "from", stored, "head", number, "snapshots", healed, "elapsed", common.PrettyDuration(time.Since(start)))
+}
+
+// rebuildSnapshots rebuilds and stores the snapshots of the canonical blocks
+// after the given one up to the given head, one persistence interval at a time,
+// returning the number of snapshots rebuilt.
+func (c *Bor) rebuildSnapshots(chain consensus.ChainHeaderReader, from uint64, head uint64) (int, error) {
+	var (
+		start   = time.Now()
+		logged  = start
+		rebuilt int
+	)
+
+	for next := c.nextStoredSnapshot(from); next <= head; next = c.nextStoredSnapshot(next) {
+		if err := c.engineCtx().Err(); err != nil {
+			return rebuilt, err
+		}
+
+		header := chain.GetHeaderByNumber(next)
+		if header == nil {
+			return rebuilt, fmt.Errorf("missing header %d", next)
+		}
+
+		if _, err := c.snapshot(chain, next, header.Hash(), nil); err != nil {
+			return rebuilt, fmt.Errorf("block %d: %w", next, err)
+		}
+
+		rebuilt++
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Rebuilding snapshots", "number", next, "head", head, "elapsed", common.PrettyDuration(time.Since(start)))
+
+			logged = time.Now()
+		}
+	}
+
+	return rebuilt, nil
+}
+
+// lastStoredSnapshot returns the number of the last canonical block up to the
+// given one whose snapshot is stored to the database, or false if the search is
+// aborted by the engine closing.
+func (c *Bor) lastStoredSnapshot(chain consensus.ChainHeaderReader, number uint64) (uint64, bool) {
+	number -= number % c.persistInterval(number)
+
+	for ; number > 0; number -= number % c.persistInterval(number) {
+		select {
+		case <-c.engineCtx().Done():
+			return 0, false
+		default:
+		}
+
+		if header := chain.GetHeaderByNumber(number); header != nil {
+			if ok, _ := c.db.Has(snapshotKey(header.Hash())); ok {
+				return number, true
+			}
+		}
+
+		number--
+	}
+
+	return 0, true
+}
+
+// nextStoredSnapshot returns the number of the first block after the given one
+// whose snapshot is stored to the database.
+func (c *Bor) nextStoredSnapshot(number uint64) uint64 {
+	number++
+
+	interval := c.persistInterval(number)
+	if rem := number % interval; rem != 0 {
+		number += interval - rem
+	}
+
+	return number
+}
@@ -0,0 +1,162 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// healChain is a canonical chain of headers sealed by a single validator.
+type healChain struct {
+	config  *params.ChainConfig
+	headers []*types.Header
+}
+
+func (c *healChain) Config() *params.ChainConfig { return c.config }
+func (c *healChain) CurrentHeader() *types.Header {
+	return c.headers[len(c.headers)-1]
+}
+func (c *healChain) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if header := c.GetHeaderByNumber(number); header != nil && header.Hash() == hash {
+		return header
+	}
+
+	return nil
+}
+func (c *healChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+
+	return c.headers[number]
+}
+func (c *healChain) GetHeaderByHash(hash common.Hash) *types.Header { return nil }
+func (c *healChain) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+// newHealEngine creates an engine storing a snapshot every 16 blocks, and a
+// chain of the given length sealed by the single validator of the genesis
+// snapshot.
+func newHealEngine(t *testing.T, length int) (*Bor, *healChain) {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	var (
+		chain = &healChain{config: &params.ChainConfig{
+			ChainID: big.NewInt(1),
+			Bor: &params.BorConfig{
+				Sprint: map[string]uint64{"0": 4},
+				Period: map[string]uint64{"0": 2},
+			},
+		}}
+		validators = []*valset.Validator{valset.NewValidator(crypto.PubkeyToAddress(key.PublicKey), 10)}
+		engine     = New(chain.config, rawdb.NewMemoryDatabase(), nil, nil, nil, nil, false)
+	)
+
+	engine.SetSnapshotPersistence(16, false)
+
+	genesis := &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1)}
+	chain.headers = append(chain.headers, genesis)
+
+	require.NoError(t, newSnapshot(chain.config, engine.signatures, 0, genesis.Hash(), validators).store(engine.db))
+
+	for number := uint64(1); number < uint64(length); number++ {
+		header := &types.Header{
+			ParentHash: chain.headers[number-1].Hash(),
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: big.NewInt(1),
+			Time:       number * 2,
+			Extra:      make([]byte, types.ExtraVanityLength),
+		}
+
+		if (number+1)%4 == 0 {
+			header.Extra = append(header.Extra, valset.EncodeValidators(validators, valset.ValidatorBytesV1)...)
+		}
+
+		header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
+
+		sig, err := crypto.Sign(SealHash(header, chain.config.Bor).Bytes(), key)
+		require.NoError(t, err)
+
+		copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sig)
+
+		chain.headers = append(chain.headers, header)
+	}
+
+	return engine, chain
+}
+
+// storedSnapshots returns the numbers of the blocks whose snapshots are stored.
+func storedSnapshots(t *testing.T, engine *Bor, chain *healChain) []uint64 {
+	t.Helper()
+
+	var stored []uint64
+
+	for _, header := range chain.headers {
+		ok, err := engine.db.Has(snapshotKey(header.Hash()))
+		require.NoError(t, err)
+
+		if ok {
+			stored = append(stored, header.Number.Uint64())
+		}
+	}
+
+	return stored
+}
+
+// Tests that the snapshots missing after an unclean shutdown are rebuilt and
+// stored up to the chain head.
+func TestHealSnapshots(t *testing.T) {
+	t.Parallel()
+
+	engine, chain := newHealEngine(t, 101)
+
+	// The node went down right after storing the snapshot of block 32
+	_, err := engine.snapshot(chain, 32, chain.headers[32].Hash(), nil)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 32}, storedSnapshots(t, engine, chain))
+
+	stored, ok := engine.lastStoredSnapshot(chain, 100)
+	require.True(t, ok)
+	require.Equal(t, uint64(32), stored)
+
+	engine.healSnapshots(chain)
+	require.Equal(t, []uint64{0, 32, 48, 64, 80, 96}, storedSnapshots(t, engine, chain))
+
+	// The rebuilt snapshots match the ones replayed from the genesis
+	healed, err := loadSnapshot(chain.config, chain.config.Bor, engine.signatures, engine.db, chain.headers[96].Hash())
+	require.NoError(t, err)
+
+	genesis, err := loadSnapshot(chain.config, chain.config.Bor, engine.signatures, engine.db, chain.headers[0].Hash())
+	require.NoError(t, err)
+
+	replayed, err := genesis.apply(chain.headers[1:97], nil)
+	require.NoError(t, err)
+
+	require.Equal(t, replayed.Number, healed.Number)
+	require.Equal(t, replayed.Recents, healed.Recents)
+	require.Equal(t, replayed.ValidatorSet.Validators, healed.ValidatorSet.Validators)
+}
+
+// Tests that snapshots lagging by no more than a couple of intervals are left
+// to be rebuilt on demand.
+func TestHealSnapshotsRecent(t *testing.T) {
+	t.Parallel()
+
+	engine, chain := newHealEngine(t, 101)
+
+	_, err := engine.snapshot(chain, 80, chain.headers[80].Hash(), nil)
+	require.NoError(t, err)
+
+	engine.healSnapshots(chain)
+	require.Equal(t, []uint64{0, 80}, storedSnapshots(t, engine, chain))
+}
@@ -0,0 +1,61 @@
+package bor
+
+// SetSnapshotPersistence sets the number of blocks after which the snapshot is
+// stored to the database, 0 for the default, and whether the snapshot of every
+// sprint is stored instead, so that historical validator set queries on archive
+// nodes never replay more than a sprint of headers.
+func (c *Bor) SetSnapshotPersistence(interval uint64, everySprint bool) {
+	c.snapshotInterval = interval
+	c.sprintSnapshots = everySprint
+}
+
+// persistSnapshot reports whether the snapshot at the given block is stored to
+// the database. Stored snapshots are aligned to the sprints, so that they are
+// taken right after the validator set changes rather than in the middle of a
+// sprint.
+func (c *Bor) persistSnapshot(number uint64) bool {
+	return number%c.persistInterval(number) == 0
+}
+
+// SetSnapshotDiffs sets whether the snapshots between the ones of the
+// persistence interval are stored as diffs over the previous stored one, every
+// sprint. Diffs only hold the validator set updates of the sprint ends since
+// and the recents window, and are reconstructed without replaying headers.
+func (c *Bor) SetSnapshotDiffs(enabled bool) {
+	c.snapshotDiffs = enabled
+}
+
+// persistInterval returns the number of blocks between the snapshots stored to
+// the database around the given block, in full or as diffs.
+func (c *Bor) persistInterval(number uint64) uint64 {
+	if sprint := c.config.CalculateSprint(number); sprint != 0 && c.snapshotDiffs {
+		return sprint
+	}
+
+	return c.fullSnapshotInterval(number)
+}
+
+// fullSnapshotInterval returns the number of blocks between the snapshots
+// stored in full to the database around the given block.
+func (c *Bor) fullSnapshotInterval(number uint64) uint64 {
+	sprint := c.config.CalculateSprint(number)
+	if sprint == 0 {
+		return checkpointInterval
+	}
+
+	if c.sprintSnapshots {
+		return sprint
+	}
+
+	interval := c.snapshotInterval
+	if interval == 0 {
+		interval = checkpointInterval
+	}
+
+	// Round the interval down to a multiple of the sprint, at least one sprint
+	if interval = interval / sprint * sprint; interval == 0 {
+		interval = sprint
+	}
+
+	return interval
+}
@@ -0,0 +1,41 @@
+package bor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestPersistSnapshot(t *testing.T) {
+	t.Parallel()
+
+	b := &Bor{config: &params.BorConfig{Sprint: map[string]uint64{"0": 48}}}
+
+	// The default interval is rounded down to a multiple of the sprint
+	require.True(t, b.persistSnapshot(0))
+	require.True(t, b.persistSnapshot(1008))
+	require.False(t, b.persistSnapshot(1024))
+
+	b.SetSnapshotPersistence(100, false)
+	require.True(t, b.persistSnapshot(96))
+	require.False(t, b.persistSnapshot(100))
+
+	// Intervals shorter than a sprint persist every sprint
+	b.SetSnapshotPersistence(10, false)
+	require.True(t, b.persistSnapshot(48))
+	require.False(t, b.persistSnapshot(10))
+
+	b.SetSnapshotPersistence(0, true)
+	require.True(t, b.persistSnapshot(48))
+	require.True(t, b.persistSnapshot(96))
+	require.False(t, b.persistSnapshot(1008+1))
+
+	// Diffs are stored every sprint, between the snapshots stored in full
+	b.SetSnapshotPersistence(0, false)
+	b.SetSnapshotDiffs(true)
+	require.True(t, b.persistSnapshot(96))
+	require.False(t, b.persistSnapshot(100))
+	require.Equal(t, uint64(1008), b.fullSnapshotInterval(96))
+}
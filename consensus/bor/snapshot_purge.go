@@ -0,0 +1,89 @@
+package bor
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var snapshotPurgedCounter = metrics.NewRegisteredCounter("bor/snapshots/purged", nil)
+
+// purgeSnapshots drops the snapshot of the given block along with the ones
+// derived from it: the snapshots of later blocks held in memory and, if the
+// block is canonical, the stored snapshots of later canonical blocks. Stored
+// snapshots are quarantined rather than deleted, to keep them for post-mortems.
+// The dropped snapshots are rebuilt from the headers when next needed.
+//
+// It returns the number of stored snapshots quarantined.
+func (c *Bor) purgeSnapshots(chain consensus.ChainHeaderReader, header *types.Header) (int, error) {
+	number := header.Number.Uint64()
+
+	for _, key := range c.recents.Keys() {
+		if snap, ok := c.recents.Peek(key); ok && snap.(*Snapshot).Number >= number {
+			c.recents.Remove(key)
+		}
+	}
+
+	if latest := c.latestSnap.Load(); latest != nil && latest.Number >= number {
+		c.latestSnap.CompareAndSwap(latest, nil)
+	}
+
+	hashes := []common.Hash{header.Hash()}
+
+	if canonical := chain.GetHeaderByNumber(number); canonical != nil && canonical.Hash() == header.Hash() {
+		head := chain.CurrentHeader().Number.Uint64()
+
+		for next := c.nextStoredSnapshot(number); next <= head; next = c.nextStoredSnapshot(next) {
+			if header := chain.GetHeaderByNumber(next); header != nil {
+				hashes = append(hashes, header.Hash())
+			}
+		}
+	}
+
+	var purged int
+
+	for _, hash := range hashes {
+		if ok, _ := c.db.Has(snapshotKey(hash)); !ok {
+			continue
+		}
+
+		if err := quarantineSnapshot(c.db, hash); err != nil {
+			return purged, err
+		}
+
+		purged++
+	}
+
+	snapshotPurgedCounter.Inc(int64(purged))
+
+	log.Warn("Purged snapshots", "number", number, "hash", header.Hash(), "stored", purged)
+
+	return purged, nil
+}
+
+// rebuildSnapshot purges the snapshot of the given canonical block and the ones
+// derived from it, then rebuilds it from the headers since the last stored
+// snapshot before it, along with the stored snapshots up to the chain head.
+func (c *Bor) rebuildSnapshot(chain consensus.ChainHeaderReader, header *types.Header) (*Snapshot, error) {
+	if _, err := c.purgeSnapshots(chain, header); err != nil {
+		return nil, err
+	}
+
+	number := header.Number.Uint64()
+
+	snap, err := c.snapshot(chain, number, header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rebuilt, err := c.rebuildSnapshots(chain, number, chain.CurrentHeader().Number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Rebuilt snapshots", "number", number, "hash", header.Hash(), "stored", rebuilt)
+
+	return snap, nil
+}
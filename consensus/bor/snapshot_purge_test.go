@@ -0,0 +1,55 @@
+package bor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that purging a snapshot drops the ones derived from it, and that they
+// are rebuilt from the headers.
+func TestPurgeRebuildSnapshot(t *testing.T) {
+	t.Parallel()
+
+	engine, chain := newHealEngine(t, 101)
+
+	rebuilt, err := engine.rebuildSnapshots(chain, 0, 100)
+	require.NoError(t, err)
+	require.Equal(t, 6, rebuilt)
+	require.Equal(t, []uint64{0, 16, 32, 48, 64, 80, 96}, storedSnapshots(t, engine, chain))
+
+	purged, err := engine.purgeSnapshots(chain, chain.headers[48])
+	require.NoError(t, err)
+	require.Equal(t, 4, purged)
+	require.Equal(t, []uint64{0, 16, 32}, storedSnapshots(t, engine, chain))
+
+	// The purged snapshots are quarantined, and dropped from memory
+	for _, number := range []int{48, 64, 80, 96} {
+		ok, err := engine.db.Has(snapshotQuarantineKey(chain.headers[number].Hash()))
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.False(t, engine.recents.Contains(chain.headers[number].Hash()))
+	}
+
+	require.True(t, engine.recents.Contains(chain.headers[32].Hash()))
+
+	if latest := engine.latestSnap.Load(); latest != nil {
+		require.Less(t, latest.Number, uint64(48))
+	}
+
+	snap, err := engine.rebuildSnapshot(chain, chain.headers[48])
+	require.NoError(t, err)
+	require.Equal(t, uint64(48), snap.Number)
+	require.Equal(t, []uint64{0, 16, 32, 48, 64, 80, 96}, storedSnapshots(t, engine, chain))
+
+	// The rebuilt snapshots match the ones replayed from the genesis
+	genesis, err := loadSnapshot(chain.config, chain.config.Bor, engine.signatures, engine.db, chain.headers[0].Hash())
+	require.NoError(t, err)
+
+	replayed, err := genesis.apply(chain.headers[1:49], nil)
+	require.NoError(t, err)
+
+	require.Equal(t, replayed.Recents, snap.Recents)
+	require.Equal(t, replayed.ValidatorSet.Validators, snap.ValidatorSet.Validators)
+}
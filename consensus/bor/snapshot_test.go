@@ -1,10 +1,13 @@
 package bor
 
 import (
+	"crypto/ecdsa"
+	"encoding/json"
 	"math/big"
 	"sort"
 	"testing"
 
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/maticnetwork/crand"
 	"github.com/stretchr/testify/require"
 	"pgregory.net/rapid"
@@ -12,6 +15,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	unique "github.com/ethereum/go-ethereum/common/set"
 	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 )
 
 const (
@@ -88,6 +95,37 @@ func TestGetSignerSuccessionNumber_SignerIndexIsSmaller(t *testing.T) {
 	require.Equal(t, signerIndex+numVals-proposerIndex, successionNumber)
 }
 
+func TestGetSignerBackoffRank(t *testing.T) {
+	t.Parallel()
+
+	validators := buildRandomValidatorSet(5)
+
+	// give the highest voting power to the first val, so that they become the proposer
+	for i, power := range []int64{200, 1, 50, 50, 10} {
+		validators[i].VotingPower = power
+	}
+
+	snap := Snapshot{
+		ValidatorSet: valset.NewValidatorSet(validators),
+	}
+	require.Equal(t, validators[0].Address, snap.ValidatorSet.GetProposer().Address)
+
+	for i, want := range []int{0, 4, 1, 2, 3} {
+		signer := snap.ValidatorSet.Validators[i].Address
+
+		rank, err := snap.GetSignerBackoffRank(signer, false)
+		require.NoError(t, err)
+		require.Equal(t, i, rank, "validator %d", i)
+
+		rank, err = snap.GetSignerBackoffRank(signer, true)
+		require.NoError(t, err)
+		require.Equal(t, want, rank, "validator %d", i)
+	}
+
+	_, err := snap.GetSignerBackoffRank(randomAddress(), true)
+	require.Error(t, err)
+}
+
 func TestGetSignerSuccessionNumber_ProposerNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -223,3 +261,173 @@ func toAddresses(vals []*valset.Validator) []common.Address {
 
 	return addrs
 }
+
+func TestSnapshotStoreLoad(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = params.TestChainConfig
+		hash   = common.HexToHash("0x01")
+		snap   = newSnapshot(config, nil, 64, hash, buildRandomValidatorSet(4))
+	)
+
+	require.NoError(t, snap.store(db))
+
+	loaded, err := loadSnapshot(config, nil, nil, db, hash)
+	require.NoError(t, err)
+	require.Equal(t, snap.Number, loaded.Number)
+	require.Equal(t, snap.ValidatorSet.Validators, loaded.ValidatorSet.Validators)
+
+	// Snapshots are compressed
+	blob, err := db.Get(snapshotKey(hash))
+	require.NoError(t, err)
+	require.Equal(t, byte(snapshotSnappyVersion), blob[0])
+
+	// Snapshots persisted before checksums and compression were added still load
+	legacy, err := json.Marshal(snap)
+	require.NoError(t, err)
+	require.NoError(t, db.Put(snapshotKey(hash), legacy))
+
+	_, err = loadSnapshot(config, nil, nil, db, hash)
+	require.NoError(t, err)
+
+	checksummed := append([]byte{snapshotChecksumVersion}, crypto.Keccak256(legacy)...)
+	checksummed = append(checksummed, legacy...)
+	require.NoError(t, db.Put(snapshotKey(hash), checksummed))
+
+	_, err = loadSnapshot(config, nil, nil, db, hash)
+	require.NoError(t, err)
+
+	// Any modification of a checksummed blob is detected
+	require.NoError(t, snap.store(db))
+
+	blob, err = db.Get(snapshotKey(hash))
+	require.NoError(t, err)
+
+	blob[len(blob)-2] ^= 0xff
+	require.NoError(t, db.Put(snapshotKey(hash), blob))
+
+	_, err = loadSnapshot(config, nil, nil, db, hash)
+	require.ErrorIs(t, err, errSnapshotCorrupt)
+
+	// So are truncated and garbage blobs
+	require.NoError(t, db.Put(snapshotKey(hash), blob[:10]))
+
+	_, err = loadSnapshot(config, nil, nil, db, hash)
+	require.ErrorIs(t, err, errSnapshotCorrupt)
+
+	require.NoError(t, db.Put(snapshotKey(hash), []byte("{garbage")))
+
+	_, err = loadSnapshot(config, nil, nil, db, hash)
+	require.ErrorIs(t, err, errSnapshotCorrupt)
+}
+
+func TestQuarantineSnapshot(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = params.TestChainConfig
+		hash   = common.HexToHash("0x01")
+		snap   = newSnapshot(config, nil, 64, hash, buildRandomValidatorSet(4))
+	)
+
+	require.NoError(t, snap.store(db))
+
+	blob, err := db.Get(snapshotKey(hash))
+	require.NoError(t, err)
+
+	truncated := blob[:len(blob)/2]
+	require.NoError(t, db.Put(snapshotKey(hash), truncated))
+
+	_, err = loadSnapshot(config, nil, nil, db, hash)
+	require.ErrorIs(t, err, errSnapshotCorrupt)
+
+	// The corrupt blob is kept aside, and no longer found by loadSnapshot so
+	// that the snapshot gets rebuilt from headers
+	require.NoError(t, quarantineSnapshot(db, hash))
+
+	quarantined, err := db.Get(snapshotQuarantineKey(hash))
+	require.NoError(t, err)
+	require.Equal(t, truncated, quarantined)
+
+	_, err = loadSnapshot(config, nil, nil, db, hash)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, errSnapshotCorrupt)
+
+	// A rebuilt snapshot is stored and loaded as usual
+	require.NoError(t, snap.store(db))
+
+	_, err = loadSnapshot(config, nil, nil, db, hash)
+	require.NoError(t, err)
+}
+
+func TestSnapshotRewind(t *testing.T) {
+	t.Parallel()
+
+	var (
+		config      = params.BorUnittestChainConfig
+		sigcache, _ = lru.NewARC(inmemorySignatures)
+		keys        = make([]*ecdsa.PrivateKey, 2)
+		validators  = make([]*valset.Validator, 2)
+	)
+
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+		validators[i] = &valset.Validator{ID: uint64(i + 1), Address: crypto.PubkeyToAddress(keys[i].PublicKey), VotingPower: 1}
+	}
+
+	// Start from a snapshot with a full sprint of recent signers
+	base := newSnapshot(config, sigcache, 32, common.HexToHash("0x20"), validators)
+	for number := uint64(1); number <= 32; number++ {
+		base.Recents[number] = validators[number%2].Address
+	}
+
+	parent := base.Hash
+	headers := make([]*types.Header, 0, 18)
+
+	for number := uint64(33); number <= 50; number++ {
+		header := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: big.NewInt(1),
+			Extra:      make([]byte, types.ExtraVanityLength+types.ExtraSealLength),
+		}
+
+		sig, err := crypto.Sign(SealHash(header, config.Bor).Bytes(), keys[number%2])
+		require.NoError(t, err)
+
+		copy(header.Extra[types.ExtraVanityLength:], sig)
+
+		headers = append(headers, header)
+		parent = header.Hash()
+	}
+
+	undos, _ := lru.NewARC(inmemoryUndos)
+
+	head, err := base.apply(headers, &Bor{undos: undos})
+	require.NoError(t, err)
+
+	want, err := base.apply(headers[:8], &Bor{})
+	require.NoError(t, err)
+
+	// Rewinding skips forks and stops at the requested ancestor
+	rewinder := newSnapshotRewinder(head, undos)
+	require.Nil(t, rewinder.rewind(45, common.HexToHash("0xdead")))
+
+	have := rewinder.rewind(40, headers[7].Hash())
+	require.NotNil(t, have)
+	require.Equal(t, want.Number, have.Number)
+	require.Equal(t, want.Hash, have.Hash)
+	require.Equal(t, want.Recents, have.Recents)
+	require.Equal(t, want.ValidatorSet.Validators, have.ValidatorSet.Validators)
+
+	// The snapshot rewound from is left untouched
+	require.Equal(t, uint64(50), head.Number)
+	require.Len(t, head.Recents, 32)
+
+	// Rewinding is impossible without undo records
+	empty, _ := lru.NewARC(inmemoryUndos)
+	require.Nil(t, newSnapshotRewinder(head, empty).rewind(40, headers[7].Hash()))
+}
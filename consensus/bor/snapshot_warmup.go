@@ -0,0 +1,55 @@
+package bor
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var snapshotWarmupTimer = metrics.NewRegisteredTimer("bor/snapshots/warmup", nil)
+
+// PrepareSnapshots readies the snapshots in the background at startup: the
+// missing stored snapshots are rebuilt if they lag far behind the chain head,
+// then the snapshot of the chain head is loaded into memory.
+func (c *Bor) PrepareSnapshots(chain consensus.ChainHeaderReader) {
+	if c.devFakeAuthor {
+		return
+	}
+
+	go func() {
+		c.healSnapshots(chain)
+		c.warmSnapshot(chain)
+	}()
+}
+
+// warmSnapshot loads the snapshot of the chain head into memory, replaying the
+// headers since the last stored snapshot. The first block verified after a
+// restart finds the snapshot of its parent there, instead of replaying up to
+// a persistence interval of headers on the critical path.
+func (c *Bor) warmSnapshot(chain consensus.ChainHeaderReader) {
+	head := chain.CurrentHeader()
+	if head == nil || head.Number.Sign() == 0 {
+		return
+	}
+
+	select {
+	case <-c.engineCtx().Done():
+		return
+	default:
+	}
+
+	start := time.Now()
+
+	snap, err := c.snapshot(chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		log.Warn("Failed to warm up snapshot", "number", head.Number, "hash", head.Hash(), "err", err)
+		return
+	}
+
+	snapshotWarmupTimer.UpdateSince(start)
+
+	log.Info("Warmed up snapshot", "number", snap.Number, "hash", snap.Hash, "elapsed", common.PrettyDuration(time.Since(start)))
+}
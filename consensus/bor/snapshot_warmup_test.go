@@ -0,0 +1,35 @@
+package bor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Tests that the snapshot of the chain head is loaded into memory at startup,
+// replayed from the last stored snapshot.
+func TestWarmSnapshot(t *testing.T) {
+	t.Parallel()
+
+	engine, chain := newHealEngine(t, 101)
+
+	_, err := engine.snapshot(chain, 96, chain.headers[96].Hash(), nil)
+	require.NoError(t, err)
+
+	// Restart the engine on the same database
+	restarted := New(chain.config, engine.db, nil, nil, nil, nil, false)
+	restarted.SetSnapshotPersistence(16, false)
+
+	head := chain.CurrentHeader().Hash()
+	require.False(t, restarted.recents.Contains(head))
+
+	restarted.warmSnapshot(chain)
+	require.True(t, restarted.recents.Contains(head))
+
+	warm, err := restarted.snapshot(chain, 100, head, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), warm.Number)
+
+	// Warming up doesn't store the snapshots in between
+	require.Equal(t, []uint64{0, 96}, storedSnapshots(t, restarted, chain))
+}
@@ -0,0 +1,266 @@
+package bor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// errUnverifiedSpan is returned if a span served by heimdall fails the check
+// against the span committed on chain.
+var errUnverifiedSpan = errors.New("unverified span")
+
+// SpanCheck is the outcome of a dry-run validation of the producer set of the
+// span after the one of the chain head.
+type SpanCheck struct {
+	SpanID     uint64           `json:"spanId"`
+	StartBlock uint64           `json:"startBlock"`
+	Producers  []common.Address `json:"producers"`
+	Committed  bool             `json:"committed"` // Whether the producers were checked against the validator contract
+	Issues     []string         `json:"issues,omitempty"`
+}
+
+// CheckNextSpan validates the producer set the node will adopt at the start of
+// the span after the one of the given head, so that an unhealthy span is found
+// ahead of the boundary rather than at it. The span served by heimdall is
+// checked for consistency with the current one, and once the span is committed
+// to the validator contract in the last sprint of the current one, the set the
+// sprint end header will carry is checked to parse as the verification will.
+//
+// An error is returned if the span of the head or the next span isn't known,
+// issues found with the next span are listed in the returned check.
+func (c *Bor) CheckNextSpan(ctx context.Context, chain consensus.ChainHeaderReader, head *types.Header) (*SpanCheck, error) {
+	number := head.Number.Uint64()
+
+	current, err := c.spanStore.GetSpanByBlock(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := c.spanStore.GetSpanById(ctx, current.ID+1)
+	if err != nil {
+		return nil, fmt.Errorf("span %d not available: %w", current.ID+1, err)
+	}
+
+	producers, err := c.spanProducers(ctx, chain, next)
+	if err != nil {
+		return nil, fmt.Errorf("producer count of span %d: %w", next.ID, err)
+	}
+
+	check := &SpanCheck{
+		SpanID:     next.ID,
+		StartBlock: next.StartBlock,
+		Producers:  make([]common.Address, 0, len(producers)),
+		Issues:     checkSpan(next, current, c.chainConfig.ChainID),
+	}
+
+	for _, producer := range producers {
+		check.Producers = append(check.Producers, producer.Address)
+	}
+
+	if number+c.config.CalculateSprint(number) <= current.EndBlock || c.spanner == nil {
+		return check, nil
+	}
+
+	validators, err := c.spanner.GetCurrentValidatorsByHash(ctx, head.Hash(), next.StartBlock)
+	if err != nil {
+		check.Issues = append(check.Issues, fmt.Sprintf("validator contract: %v", err))
+		return check, nil
+	}
+
+	check.Committed = true
+	check.Issues = append(check.Issues, c.checkBoundaryValidators(validators, next, producers)...)
+
+	return check, nil
+}
+
+// checkSpan returns the issues of a span proposed by heimdall to follow the
+// given one.
+func checkSpan(next *span.HeimdallSpan, current *span.HeimdallSpan, chainID *big.Int) []string {
+	var issues []string
+
+	if next.StartBlock != current.EndBlock+1 {
+		issues = append(issues, fmt.Sprintf("span starts at block %d instead of %d", next.StartBlock, current.EndBlock+1))
+	}
+
+	if next.EndBlock < next.StartBlock {
+		issues = append(issues, fmt.Sprintf("span ends at block %d before it starts", next.EndBlock))
+	}
+
+	if chainID != nil && next.ChainID != chainID.String() {
+		issues = append(issues, fmt.Sprintf("span of chain %s", next.ChainID))
+	}
+
+	producers := make([]*valset.Validator, len(next.SelectedProducers))
+	for i := range next.SelectedProducers {
+		producers[i] = &next.SelectedProducers[i]
+	}
+
+	issues = append(issues, checkProducers(producers)...)
+
+	for _, producer := range producers {
+		if _, validator := next.ValidatorSet.GetByAddress(producer.Address); validator == nil {
+			issues = append(issues, fmt.Sprintf("producer %v not in the validator set", producer.Address))
+		}
+	}
+
+	return issues
+}
+
+// SetSpanVerification sets whether the spans served by heimdall are checked
+// against the span committed on chain, reporting the ones failing it.
+func (c *Bor) SetSpanVerification(enabled bool) {
+	c.verifySpans = enabled
+}
+
+// verifyHeimdallSpan checks a span served by heimdall against the current span
+// and its producers as committed to the validator contract at the parent of the
+// given header, which the chain agrees on independently of heimdall: it must
+// follow the current span, and producers holding more than two thirds of its
+// voting power must remain validators. Heimdall doesn't serve the signatures of
+// its validators over spans, so this is a heuristic catching a faulty heimdall
+// swapping the producers out at once, not an authentication, and a valid span
+// rotating more of the stake fails it as well.
+func (c *Bor) verifyHeimdallSpan(ctx context.Context, next *span.HeimdallSpan, header *types.Header) error {
+	if next.ID == 0 {
+		return nil
+	}
+
+	committed, err := c.spanner.GetCurrentSpan(ctx, header.ParentHash)
+	if err != nil {
+		return fmt.Errorf("%w: span %d: committed span: %v", errUnverifiedSpan, next.ID, err)
+	}
+
+	producers, err := c.spanner.GetCurrentValidatorsByHash(ctx, header.ParentHash, header.Number.Uint64())
+	if err != nil {
+		return fmt.Errorf("%w: span %d: committed producers: %v", errUnverifiedSpan, next.ID, err)
+	}
+
+	if len(producers) == 0 {
+		return fmt.Errorf("%w: span %d: no producers committed for span %d", errUnverifiedSpan, next.ID, committed.ID)
+	}
+
+	current := &span.HeimdallSpan{
+		Span:         *committed,
+		ValidatorSet: *valset.NewValidatorSet(producers),
+	}
+
+	return verifySpan(next, current, c.chainConfig.ChainID)
+}
+
+// verifySpan returns an error if a span proposed by heimdall to follow the
+// given trusted one can't be authenticated.
+func verifySpan(next *span.HeimdallSpan, current *span.HeimdallSpan, chainID *big.Int) error {
+	if issues := checkSpan(next, current, chainID); len(issues) > 0 {
+		return fmt.Errorf("%w: span %d: %s", errUnverifiedSpan, next.ID, strings.Join(issues, ", "))
+	}
+
+	carried := make([]common.Address, 0, len(next.ValidatorSet.Validators))
+	for _, validator := range next.ValidatorSet.Validators {
+		carried = append(carried, validator.Address)
+	}
+
+	if !current.ValidatorSet.HasTwoThirdsMajority(carried) {
+		return fmt.Errorf("%w: span %d: validators of %d/%d voting power of span %d carried over", errUnverifiedSpan, next.ID,
+			current.ValidatorSet.VotingPowerOf(carried), current.ValidatorSet.TotalVotingPower(), current.ID)
+	}
+
+	return nil
+}
+
+// checkProducers returns the issues of a producer set which would fail the
+// validator set update at the span boundary.
+func checkProducers(producers []*valset.Validator) []string {
+	if len(producers) == 0 {
+		return []string{"no producers"}
+	}
+
+	var (
+		issues []string
+		seen   = make(map[common.Address]bool, len(producers))
+	)
+
+	for _, producer := range producers {
+		switch {
+		case producer.Address == (common.Address{}):
+			issues = append(issues, "producer with a zero address")
+		case seen[producer.Address]:
+			issues = append(issues, fmt.Sprintf("duplicate producer %v", producer.Address))
+		case producer.VotingPower <= 0:
+			issues = append(issues, fmt.Sprintf("producer %v without voting power", producer.Address))
+		}
+
+		seen[producer.Address] = true
+	}
+
+	return issues
+}
+
+// checkBoundaryValidators returns the issues of the validators committed to
+// the contract for the next span: they're encoded into the sprint end header
+// before the span and parsed back the way its verification does, and must
+// match the given producers selected by heimdall, as capped by the contract.
+func (c *Bor) checkBoundaryValidators(validators []*valset.Validator, next *span.HeimdallSpan, producers []valset.Validator) []string {
+	// Sorted as the producer of the sprint end header will
+	sorted := make([]*valset.Validator, len(validators))
+	for i, validator := range validators {
+		sorted[i] = validator.Copy()
+	}
+
+	sort.Sort(valset.ValidatorsByAddress(sorted))
+
+	boundary := new(big.Int).SetUint64(next.StartBlock - 1)
+	version := c.validatorBytesVersion(boundary)
+
+	if version == valset.ValidatorBytesV2 {
+		if err := setSpanBLSPublicKeys(sorted, next); err != nil {
+			return []string{fmt.Sprintf("sprint end validators can't be committed: %v", err)}
+		}
+	}
+
+	validatorBytes := valset.EncodeValidators(sorted, version)
+
+	parse := valset.ParseValidators
+	if c.config.IsStrictExtra(boundary) {
+		parse = valset.ParseValidatorsStrict
+	}
+
+	parsed, err := parse(validatorBytes)
+	if err != nil {
+		return []string{fmt.Sprintf("sprint end validators don't parse: %v", err)}
+	}
+
+	issues := checkProducers(parsed)
+
+	selected := make(map[common.Address]bool, len(producers))
+	for _, producer := range producers {
+		selected[producer.Address] = true
+	}
+
+	committed := make(map[common.Address]bool, len(parsed))
+
+	for _, validator := range parsed {
+		committed[validator.Address] = true
+
+		if !selected[validator.Address] {
+			issues = append(issues, fmt.Sprintf("committed producer %v not selected by heimdall", validator.Address))
+		}
+	}
+
+	for address := range selected {
+		if !committed[address] {
+			issues = append(issues, fmt.Sprintf("producer %v selected by heimdall not committed", address))
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,418 @@
+package bor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testNextSpan returns the span following one ending at block 255, with the
+// given producers selected among the validators 0x1, 0x2 and 0x3.
+func testNextSpan(producers ...valset.Validator) *span.HeimdallSpan {
+	validators := []*valset.Validator{
+		valset.NewValidator(common.Address{0x1}, 10),
+		valset.NewValidator(common.Address{0x2}, 10),
+		valset.NewValidator(common.Address{0x3}, 10),
+	}
+
+	return &span.HeimdallSpan{
+		Span:              span.Span{ID: 1, StartBlock: 256, EndBlock: 6655},
+		ValidatorSet:      *valset.NewValidatorSet(validators),
+		SelectedProducers: producers,
+		ChainID:           "15001",
+	}
+}
+
+func TestCheckSpan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		current = &span.HeimdallSpan{Span: span.Span{ID: 0, StartBlock: 0, EndBlock: 255}}
+		chainID = big.NewInt(15001)
+
+		alice = valset.Validator{Address: common.Address{0x1}, VotingPower: 10}
+		bob   = valset.Validator{Address: common.Address{0x2}, VotingPower: 10}
+	)
+
+	require.Empty(t, checkSpan(testNextSpan(alice, bob), current, chainID))
+
+	gap := testNextSpan(alice)
+	gap.StartBlock = 300
+	require.Equal(t, []string{"span starts at block 300 instead of 256"}, checkSpan(gap, current, chainID))
+
+	require.Equal(t, []string{"span of chain 15001"}, checkSpan(testNextSpan(alice), current, big.NewInt(137)))
+
+	require.Equal(t, []string{"no producers"}, checkSpan(testNextSpan(), current, chainID))
+
+	powerless := bob
+	powerless.VotingPower = 0
+
+	require.Equal(t, []string{
+		"duplicate producer 0x0100000000000000000000000000000000000000",
+		"producer 0x0200000000000000000000000000000000000000 without voting power",
+	}, checkSpan(testNextSpan(alice, alice, powerless), current, chainID))
+
+	require.Equal(t, []string{
+		"producer 0x0400000000000000000000000000000000000000 not in the validator set",
+	}, checkSpan(testNextSpan(alice, valset.Validator{Address: common.Address{0x4}, VotingPower: 10}), current, chainID))
+}
+
+func TestCheckBoundaryValidators(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = valset.Validator{Address: common.Address{0x1}, VotingPower: 10}
+		bob   = valset.Validator{Address: common.Address{0x2}, VotingPower: 10}
+		carol = valset.Validator{Address: common.Address{0x3}, VotingPower: 10}
+
+		next   = testNextSpan(alice, bob)
+		engine = &Bor{config: &params.BorConfig{StrictExtraBlock: big.NewInt(0)}}
+	)
+
+	// The contract returns the validators in any order, they're sorted as the
+	// sprint end header producer will
+	require.Empty(t, engine.checkBoundaryValidators([]*valset.Validator{bob.Copy(), alice.Copy()}, next, next.SelectedProducers))
+
+	require.Equal(t, []string{
+		"committed producer 0x0300000000000000000000000000000000000000 not selected by heimdall",
+		"producer 0x0200000000000000000000000000000000000000 selected by heimdall not committed",
+	}, engine.checkBoundaryValidators([]*valset.Validator{alice.Copy(), carol.Copy()}, next, next.SelectedProducers))
+
+	// Validators failing the strict validation of the sprint end header
+	powerless := bob.Copy()
+	powerless.VotingPower = 0
+
+	issues := engine.checkBoundaryValidators([]*valset.Validator{alice.Copy(), powerless}, next, next.SelectedProducers)
+	require.Len(t, issues, 1)
+	require.Contains(t, issues[0], "sprint end validators don't parse")
+
+	// Before strict validation they parse, but still can't be adopted
+	engine.config.StrictExtraBlock = big.NewInt(1000)
+
+	require.Equal(t, []string{
+		"producer 0x0200000000000000000000000000000000000000 without voting power",
+	}, engine.checkBoundaryValidators([]*valset.Validator{alice.Copy(), powerless}, next, next.SelectedProducers))
+
+	// Producers left out by the producer count of the contract aren't expected
+	require.Empty(t, engine.checkBoundaryValidators([]*valset.Validator{alice.Copy()}, next, next.SelectedProducers[:1]))
+}
+
+// Tests that the validators committed in the v2 layout carry the BLS public
+// keys registered in heimdall for the span, and can't be committed without.
+func TestCheckBoundaryValidatorsBLSPublicKeys(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = valset.Validator{Address: common.Address{0x1}, VotingPower: 10}
+		bob   = valset.Validator{Address: common.Address{0x2}, VotingPower: 10}
+
+		next   = testNextSpan(alice, bob)
+		engine = &Bor{config: &params.BorConfig{StrictExtraBlock: big.NewInt(0), ValidatorExtraV2Block: big.NewInt(0)}}
+	)
+
+	require.Equal(t, []string{
+		"sprint end validators can't be committed: missing BLS public key: validator 0x0100000000000000000000000000000000000000 in span 1",
+	}, engine.checkBoundaryValidators([]*valset.Validator{alice.Copy(), bob.Copy()}, next, next.SelectedProducers))
+
+	for i, validator := range next.ValidatorSet.Validators {
+		validator.BLSPublicKey = bytes.Repeat([]byte{byte(i + 1)}, valset.BLSPublicKeyLength)
+	}
+
+	validators := []*valset.Validator{bob.Copy(), alice.Copy()}
+	require.Empty(t, engine.checkBoundaryValidators(validators, next, next.SelectedProducers))
+
+	// The validators of the caller are left alone
+	require.Nil(t, validators[0].BLSPublicKey)
+
+	// The keys are set from the span
+	sorted := []*valset.Validator{alice.Copy(), bob.Copy()}
+	require.NoError(t, setSpanBLSPublicKeys(sorted, next))
+	require.Equal(t, next.ValidatorSet.Validators[0].BLSPublicKey, sorted[0].BLSPublicKey)
+	require.Equal(t, next.ValidatorSet.Validators[1].BLSPublicKey, sorted[1].BLSPublicKey)
+}
+
+// Tests that the validators to commit in the v2 layout keep the BLS public key
+// committed for them before, only the joining ones taking theirs from heimdall.
+func TestSetBLSPublicKeys(t *testing.T) {
+	t.Parallel()
+
+	// The keys are served base64 encoded, like the other bytes of heimdall
+	blob, err := os.ReadFile(filepath.Join("testdata", "span_bls.json"))
+	require.NoError(t, err)
+
+	var response heimdall.SpanResponse
+	require.NoError(t, json.Unmarshal(blob, &response))
+
+	store := NewSpanStore(rawdb.NewMemoryDatabase(), nil)
+	require.NoError(t, store.store(&response.Result))
+
+	var (
+		alice     = common.HexToAddress("0x96C42C56fdb78294F96B0cFa33c92bed7D75F96a")
+		bob       = common.HexToAddress("0x9fB29AAc15b9A4B7F17c3385939b007540f4d791")
+		committed = bytes.Repeat([]byte{0xaa}, valset.BLSPublicKeyLength)
+	)
+
+	current := valset.NewValidatorSet([]*valset.Validator{{Address: alice, VotingPower: 20, BLSPublicKey: committed}})
+
+	// The validators of the current set don't need heimdall
+	engine := &Bor{config: &params.BorConfig{ValidatorExtraV2Block: big.NewInt(0)}}
+
+	validators := []*valset.Validator{valset.NewValidator(alice, 20)}
+	require.NoError(t, engine.setBLSPublicKeys(context.Background(), current, validators, 6655))
+	require.Equal(t, committed, validators[0].BLSPublicKey)
+
+	// The joining ones take the key registered for the span they take effect in
+	engine.spanStore = store
+
+	validators = []*valset.Validator{valset.NewValidator(alice, 20), valset.NewValidator(bob, 30)}
+	require.NoError(t, engine.setBLSPublicKeys(context.Background(), current, validators, 6655))
+	require.Equal(t, committed, validators[0].BLSPublicKey)
+	require.Equal(t, bytes.Repeat([]byte{0x05}, valset.BLSPublicKeyLength), validators[1].BLSPublicKey)
+
+	// And can't be committed without one
+	validators = []*valset.Validator{valset.NewValidator(common.Address{0x1}, 10)}
+	require.ErrorIs(t, engine.setBLSPublicKeys(context.Background(), current, validators, 6655), errMissingBLSPublicKey)
+
+	// Nothing is set before the fork
+	engine.config.ValidatorExtraV2Block = big.NewInt(10000)
+
+	validators = []*valset.Validator{valset.NewValidator(bob, 30)}
+	require.NoError(t, engine.setBLSPublicKeys(context.Background(), current, validators, 6655))
+	require.Nil(t, validators[0].BLSPublicKey)
+}
+
+// Tests that the BLS public keys of the validators committed in the v2 layout
+// are checked against the ones committed before.
+func TestVerifyBLSPublicKeys(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = valset.NewValidator(common.Address{0x1}, 10)
+		bob   = valset.NewValidator(common.Address{0x2}, 10)
+	)
+
+	alice.BLSPublicKey = bytes.Repeat([]byte{0x1}, valset.BLSPublicKeyLength)
+	current := valset.NewValidatorSet([]*valset.Validator{alice.Copy(), valset.NewValidator(common.Address{0x3}, 10)})
+
+	// Joining validators commit their own key
+	bob.BLSPublicKey = bytes.Repeat([]byte{0x2}, valset.BLSPublicKeyLength)
+	require.NoError(t, verifyBLSPublicKeys([]*valset.Validator{alice, bob}, current))
+
+	// But must have one
+	bob.BLSPublicKey = make([]byte, valset.BLSPublicKeyLength)
+	require.ErrorIs(t, verifyBLSPublicKeys([]*valset.Validator{alice, bob}, current), errInvalidBLSPublicKey)
+
+	bob.BLSPublicKey = nil
+	require.ErrorIs(t, verifyBLSPublicKeys([]*valset.Validator{bob}, current), errInvalidBLSPublicKey)
+
+	// Validators without a key committed before take the one in the header
+	carol := valset.NewValidator(common.Address{0x3}, 10)
+	carol.BLSPublicKey = bytes.Repeat([]byte{0x3}, valset.BLSPublicKeyLength)
+	require.NoError(t, verifyBLSPublicKeys([]*valset.Validator{carol}, current))
+
+	// The others keep theirs
+	alice.BLSPublicKey = bytes.Repeat([]byte{0xff}, valset.BLSPublicKeyLength)
+	require.ErrorIs(t, verifyBLSPublicKeys([]*valset.Validator{alice}, current), errInvalidBLSPublicKey)
+}
+
+// countingSpanner is a spanner capping the committed spans to a producer
+// count, recording the block it was read at.
+type countingSpanner struct {
+	Spanner
+	count uint64
+	read  common.Hash
+}
+
+func (s *countingSpanner) GetProducerCount(_ context.Context, headerHash common.Hash) (uint64, error) {
+	s.read = headerHash
+	return s.count, nil
+}
+
+// Tests that the producers of a span are capped by the producer count of the
+// contract before the block committing it, once the cap is switched on.
+func TestSpanProducers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = valset.Validator{Address: common.Address{0x1}, VotingPower: 10}
+		bob   = valset.Validator{Address: common.Address{0x2}, VotingPower: 10}
+
+		spanner = &countingSpanner{count: 1}
+		engine  = &Bor{config: &params.BorConfig{Sprint: map[string]uint64{"0": 16}, ProducerCountBlock: big.NewInt(300)}, spanner: spanner}
+		chain   = &healChain{}
+	)
+
+	for number := uint64(0); number < 250; number++ {
+		chain.headers = append(chain.headers, &types.Header{Number: new(big.Int).SetUint64(number)})
+	}
+
+	// The span after block 255 is committed at block 240, before the fork
+	next := testNextSpan(alice, bob)
+
+	producers, err := engine.spanProducers(context.Background(), chain, next)
+	require.NoError(t, err)
+	require.Equal(t, next.SelectedProducers, producers)
+
+	// Afterwards the count is read before the committing block, or at the head
+	// if the span isn't committed yet
+	engine.config.ProducerCountBlock = big.NewInt(0)
+
+	producers, err = engine.spanProducers(context.Background(), chain, next)
+	require.NoError(t, err)
+	require.Equal(t, []valset.Validator{alice}, producers)
+	require.Equal(t, chain.headers[239].Hash(), spanner.read)
+
+	later := testNextSpan(alice, bob)
+	later.StartBlock = 512
+
+	_, err = engine.spanProducers(context.Background(), chain, later)
+	require.NoError(t, err)
+	require.Equal(t, chain.CurrentHeader().Hash(), spanner.read)
+
+	// Spanners not reading the count don't cap the producers
+	engine.spanner = nil
+
+	producers, err = engine.spanProducers(context.Background(), chain, next)
+	require.NoError(t, err)
+	require.Equal(t, next.SelectedProducers, producers)
+}
+
+func TestVerifySpan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chainID = big.NewInt(15001)
+		alice   = valset.Validator{Address: common.Address{0x1}, VotingPower: 10}
+	)
+
+	current := testNextSpan(alice)
+	current.ID, current.StartBlock, current.EndBlock = 0, 0, 255
+
+	require.NoError(t, verifySpan(testNextSpan(alice), current, chainID))
+
+	gap := testNextSpan(alice)
+	gap.StartBlock = 300
+	require.ErrorIs(t, verifySpan(gap, current, chainID), errUnverifiedSpan)
+
+	// Validators of two thirds of the voting power aren't a quorum
+	rotated := testNextSpan(alice)
+	rotated.ValidatorSet = *valset.NewValidatorSet([]*valset.Validator{
+		valset.NewValidator(common.Address{0x1}, 10),
+		valset.NewValidator(common.Address{0x2}, 10),
+		valset.NewValidator(common.Address{0x4}, 10),
+	})
+
+	err := verifySpan(rotated, current, chainID)
+	require.ErrorIs(t, err, errUnverifiedSpan)
+	require.ErrorContains(t, err, "20/30 voting power of span 0")
+}
+
+// committedSpanner is a spanner serving the span and producers committed to
+// the validator contract.
+type committedSpanner struct {
+	Spanner
+	span      *span.Span
+	producers []*valset.Validator
+	committed []uint64 // Ids of the spans committed
+}
+
+func (s *committedSpanner) GetCurrentSpan(context.Context, common.Hash) (*span.Span, error) {
+	return s.span, nil
+}
+
+func (s *committedSpanner) GetCurrentValidatorsByHash(context.Context, common.Hash, uint64) ([]*valset.Validator, error) {
+	return s.producers, nil
+}
+
+func (s *committedSpanner) CommitSpan(_ context.Context, heimdallSpan span.HeimdallSpan, _ *state.StateDB, _ *types.Header, _ core.ChainContext) error {
+	s.committed = append(s.committed, heimdallSpan.ID)
+	return nil
+}
+
+// Tests that the spans served by heimdall are verified against the span and
+// producers committed on chain, not against anything heimdall served before.
+func TestVerifyHeimdallSpan(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = valset.Validator{Address: common.Address{0x1}, VotingPower: 10}
+
+		spanner = &committedSpanner{
+			span: &span.Span{ID: 0, StartBlock: 0, EndBlock: 255},
+			producers: []*valset.Validator{
+				valset.NewValidator(common.Address{0x1}, 10),
+				valset.NewValidator(common.Address{0x2}, 10),
+			},
+		}
+		engine = &Bor{spanner: spanner, chainConfig: &params.ChainConfig{ChainID: big.NewInt(15001)}}
+		header = &types.Header{Number: big.NewInt(240)}
+	)
+
+	require.NoError(t, engine.verifyHeimdallSpan(context.Background(), testNextSpan(alice), header))
+
+	// A span dropping the committed producers out of the validator set fails
+	swapped := testNextSpan(alice)
+	swapped.ValidatorSet = *valset.NewValidatorSet([]*valset.Validator{
+		valset.NewValidator(common.Address{0x1}, 10),
+		valset.NewValidator(common.Address{0x3}, 10),
+		valset.NewValidator(common.Address{0x4}, 10),
+	})
+
+	err := engine.verifyHeimdallSpan(context.Background(), swapped, header)
+	require.ErrorIs(t, err, errUnverifiedSpan)
+	require.ErrorContains(t, err, "10/20 voting power of span 0")
+
+	// So does a span not following the committed one
+	spanner.span = &span.Span{ID: 0, StartBlock: 0, EndBlock: 127}
+	require.ErrorIs(t, engine.verifyHeimdallSpan(context.Background(), testNextSpan(alice), header), errUnverifiedSpan)
+
+	// And a span nothing is committed for yet
+	spanner.span, spanner.producers = &span.Span{ID: 0, StartBlock: 0, EndBlock: 255}, nil
+	require.ErrorIs(t, engine.verifyHeimdallSpan(context.Background(), testNextSpan(alice), header), errUnverifiedSpan)
+}
+
+// Tests that a span failing the check is still committed, so that blocks
+// committing a valid span rotating more of the stake are never rejected.
+func TestFetchAndCommitSpanUnverified(t *testing.T) {
+	t.Parallel()
+
+	var (
+		heimdall = &spanHeimdallClient{lastID: 10}
+		spanner  = &committedSpanner{
+			span:      &span.Span{ID: 0, StartBlock: 0, EndBlock: 255},
+			producers: []*valset.Validator{valset.NewValidator(common.Address{0x1}, 10)},
+		}
+		engine = &Bor{
+			chainConfig:    &params.ChainConfig{ChainID: big.NewInt(15001)},
+			spanner:        spanner,
+			HeimdallClient: heimdall,
+			spanStore:      NewSpanStore(rawdb.NewMemoryDatabase(), heimdall),
+			verifySpans:    true,
+		}
+		header = &types.Header{Number: big.NewInt(240)}
+	)
+
+	// The validators of the span served by heimdall are unrelated to the committed producers
+	served, err := engine.spanStore.GetSpanById(context.Background(), 1)
+	require.NoError(t, err)
+	require.ErrorIs(t, engine.verifyHeimdallSpan(context.Background(), served, header), errUnverifiedSpan)
+
+	require.NoError(t, engine.FetchAndCommitSpan(context.Background(), 1, nil, header, nil))
+	require.Equal(t, []uint64{1}, spanner.committed)
+}
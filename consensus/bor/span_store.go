@@ -0,0 +1,243 @@
+package bor
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang/snappy"
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	inmemorySpans = 64 // Number of recent spans to keep in memory
+
+	// maxSpanLookups is the maximum number of spans visited while searching
+	// the span of a block.
+	maxSpanLookups = 64
+
+	// spanSnappyVersion prefixes persisted spans compressed with snappy. Spans
+	// persisted before are plain json, which can never start with the version
+	// byte.
+	spanSnappyVersion = 0x01
+)
+
+var (
+	spanPrefix    = []byte("bor-span-")        // spanPrefix + id (uint64 big endian) -> span
+	lastSpanIDKey = []byte("bor-last-span-id") // highest span id stored locally
+
+	// errUnknownSpan is returned if a span is neither stored locally nor
+	// available from heimdall.
+	errUnknownSpan = errors.New("unknown span")
+)
+
+// spanKey = spanPrefix + id (uint64 big endian)
+func spanKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(append([]byte{}, spanPrefix...), id)
+}
+
+// SpanStore caches the producer spans fetched from heimdall, both in memory
+// and in the database, so that they are re-used across restarts instead of
+// being re-fetched.
+type SpanStore struct {
+	db       ethdb.Database
+	heimdall IHeimdallClient
+	cache    *lru.ARCCache // Recently used spans, keyed by id
+
+	lastID uint64     // Highest span id stored locally
+	lock   sync.Mutex // Protects heimdall and lastID
+}
+
+// NewSpanStore creates a span store on top of the given database, fetching
+// missing spans from the given heimdall client.
+func NewSpanStore(db ethdb.Database, heimdall IHeimdallClient) *SpanStore {
+	cache, _ := lru.NewARC(inmemorySpans)
+
+	store := &SpanStore{
+		db:       db,
+		heimdall: heimdall,
+		cache:    cache,
+	}
+
+	if blob, err := db.Get(lastSpanIDKey); err == nil && len(blob) == 8 {
+		store.lastID = binary.BigEndian.Uint64(blob)
+	}
+
+	return store
+}
+
+// setHeimdallClient replaces the client missing spans are fetched from.
+func (s *SpanStore) setHeimdallClient(heimdall IHeimdallClient) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.heimdall = heimdall
+}
+
+// GetSpanById returns the span with the given id, fetching it from heimdall
+// if it's not stored locally.
+//
+//nolint:revive,stylecheck
+func (s *SpanStore) GetSpanById(ctx context.Context, id uint64) (*span.HeimdallSpan, error) {
+	if heimdallSpan, err := s.storedSpan(id); heimdallSpan != nil || err != nil {
+		return heimdallSpan, err
+	}
+
+	s.lock.Lock()
+	heimdall := s.heimdall
+	s.lock.Unlock()
+
+	if heimdall == nil {
+		return nil, errUnknownSpan
+	}
+
+	heimdallSpan, err := heimdall.Span(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errHeimdallUnavailable, err)
+	}
+
+	if heimdallSpan.ID != id {
+		return nil, errUnknownSpan
+	}
+
+	heimdallSpan.ValidatorSet.UpdateValidatorMap()
+
+	if err := s.store(heimdallSpan); err != nil {
+		log.Warn("Failed to store span", "id", id, "err", err)
+	}
+
+	return heimdallSpan, nil
+}
+
+// GetSpanByBlock returns the span containing the given block number, starting
+// the search at the highest span stored locally.
+func (s *SpanStore) GetSpanByBlock(ctx context.Context, number uint64) (*span.HeimdallSpan, error) {
+	return s.spanByBlock(number, func(id uint64) (*span.HeimdallSpan, error) {
+		return s.GetSpanById(ctx, id)
+	})
+}
+
+// storedSpanByBlock returns the span containing the given block number if it's
+// stored locally, without fetching any span from heimdall.
+func (s *SpanStore) storedSpanByBlock(number uint64) (*span.HeimdallSpan, error) {
+	return s.spanByBlock(number, func(id uint64) (*span.HeimdallSpan, error) {
+		heimdallSpan, err := s.storedSpan(id)
+		if heimdallSpan == nil && err == nil {
+			return nil, errUnknownSpan
+		}
+
+		return heimdallSpan, err
+	})
+}
+
+// spanByBlock searches the span containing the given block number through the
+// given span lookup, starting at the highest span stored locally.
+func (s *SpanStore) spanByBlock(number uint64, lookup func(id uint64) (*span.HeimdallSpan, error)) (*span.HeimdallSpan, error) {
+	s.lock.Lock()
+	id := s.lastID
+	s.lock.Unlock()
+
+	for i := 0; i < maxSpanLookups; i++ {
+		heimdallSpan, err := lookup(id)
+		if err != nil {
+			return nil, err
+		}
+
+		// Jump by the length of the current span, spans are mostly of equal length
+		length := heimdallSpan.EndBlock - heimdallSpan.StartBlock + 1
+
+		switch {
+		case number < heimdallSpan.StartBlock:
+			if id == 0 {
+				return nil, errUnknownSpan
+			}
+
+			id -= min(id, 1+(heimdallSpan.StartBlock-1-number)/length)
+		case number > heimdallSpan.EndBlock:
+			id += 1 + (number-heimdallSpan.EndBlock-1)/length
+		default:
+			return heimdallSpan, nil
+		}
+	}
+
+	return nil, errUnknownSpan
+}
+
+// store persists the given span, updating the highest span id stored.
+func (s *SpanStore) store(heimdallSpan *span.HeimdallSpan) error {
+	blob, err := json.Marshal(heimdallSpan)
+	if err != nil {
+		return err
+	}
+
+	enc := append([]byte{spanSnappyVersion}, snappy.Encode(nil, blob)...)
+
+	if err := s.db.Put(spanKey(heimdallSpan.ID), enc); err != nil {
+		return err
+	}
+
+	s.cache.Add(heimdallSpan.ID, heimdallSpan)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if heimdallSpan.ID > s.lastID {
+		s.lastID = heimdallSpan.ID
+
+		return s.db.Put(lastSpanIDKey, binary.BigEndian.AppendUint64(nil, heimdallSpan.ID))
+	}
+
+	return nil
+}
+
+// storedSpan returns the span with the given id if it's stored locally, or nil
+// if it's not, without fetching it from heimdall.
+func (s *SpanStore) storedSpan(id uint64) (*span.HeimdallSpan, error) {
+	if cached, ok := s.cache.Get(id); ok {
+		return cached.(*span.HeimdallSpan), nil
+	}
+
+	if has, err := s.db.Has(spanKey(id)); err != nil || !has {
+		return nil, err
+	}
+
+	blob, err := s.db.Get(spanKey(id))
+	if err != nil {
+		return nil, err
+	}
+
+	heimdallSpan, err := decodeSpan(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Add(id, heimdallSpan)
+
+	return heimdallSpan, nil
+}
+
+// decodeSpan decodes a persisted span, either compressed or plain json.
+func decodeSpan(blob []byte) (*span.HeimdallSpan, error) {
+	if len(blob) > 0 && blob[0] == spanSnappyVersion {
+		var err error
+		if blob, err = snappy.Decode(nil, blob[1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	heimdallSpan := new(span.HeimdallSpan)
+	if err := json.Unmarshal(blob, heimdallSpan); err != nil {
+		return nil, err
+	}
+
+	heimdallSpan.ValidatorSet.UpdateValidatorMap()
+
+	return heimdallSpan, nil
+}
@@ -0,0 +1,188 @@
+package bor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// spanHeimdallClient is a heimdall client serving spans of 100 blocks, with the
+// first span ending at block 255.
+type spanHeimdallClient struct {
+	IHeimdallClient
+
+	lastID uint64 // Highest span id known to heimdall
+	calls  int    // Number of span requests served
+}
+
+func (h *spanHeimdallClient) Span(_ context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	h.calls++
+
+	if spanID > h.lastID {
+		return nil, errors.New("span not found")
+	}
+
+	start, end := uint64(0), uint64(255)
+	if spanID > 0 {
+		start = 256 + (spanID-1)*100
+		end = start + 99
+	}
+
+	validators := buildRandomValidatorSet(4)
+
+	return &span.HeimdallSpan{
+		Span:         span.Span{ID: spanID, StartBlock: start, EndBlock: end},
+		ValidatorSet: *valset.NewValidatorSet(validators),
+		ChainID:      "15001",
+	}, nil
+}
+
+func TestSpanStoreGetSpanById(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		heimdall = &spanHeimdallClient{lastID: 10}
+		store    = NewSpanStore(db, heimdall)
+	)
+
+	fetched, err := store.GetSpanById(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), fetched.ID)
+	require.Equal(t, 1, heimdall.calls)
+
+	// Cached spans are not re-fetched
+	_, err = store.GetSpanById(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, 1, heimdall.calls)
+
+	// Local lookups don't fetch missing spans
+	local, err := store.storedSpan(4)
+	require.NoError(t, err)
+	require.Nil(t, local)
+
+	local, err = store.storedSpan(3)
+	require.NoError(t, err)
+	require.Equal(t, fetched, local)
+	require.Equal(t, 1, heimdall.calls)
+
+	// Stored spans survive a restart, even without heimdall
+	store = NewSpanStore(db, nil)
+
+	stored, err := store.GetSpanById(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, fetched.Span, stored.Span)
+	require.Equal(t, len(fetched.ValidatorSet.Validators), len(stored.ValidatorSet.Validators))
+	require.Equal(t, uint64(3), store.lastID)
+
+	_, err = store.GetSpanById(context.Background(), 4)
+	require.ErrorIs(t, err, errUnknownSpan)
+}
+
+// errSpanDB is the error returned by failingSpanDB.
+var errSpanDB = errors.New("disk failure")
+
+// failingSpanDB is a database failing every read.
+type failingSpanDB struct {
+	ethdb.Database
+}
+
+func (db failingSpanDB) Has([]byte) (bool, error)   { return false, errSpanDB }
+func (db failingSpanDB) Get([]byte) ([]byte, error) { return nil, errSpanDB }
+
+// Tests that the spans failing to be read aren't taken as missing, so they're
+// neither fetched from heimdall in their place nor reported as unknown.
+func TestSpanStoreReadFailure(t *testing.T) {
+	t.Parallel()
+
+	var (
+		heimdall = &spanHeimdallClient{lastID: 10}
+		store    = NewSpanStore(failingSpanDB{rawdb.NewMemoryDatabase()}, heimdall)
+	)
+
+	_, err := store.storedSpan(3)
+	require.ErrorIs(t, err, errSpanDB)
+
+	_, err = store.GetSpanById(context.Background(), 3)
+	require.ErrorIs(t, err, errSpanDB)
+	require.Zero(t, heimdall.calls)
+}
+
+func TestSpanStoreFormats(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db       = rawdb.NewMemoryDatabase()
+		heimdall = &spanHeimdallClient{lastID: 10}
+	)
+
+	fetched, err := NewSpanStore(db, heimdall).GetSpanById(context.Background(), 3)
+	require.NoError(t, err)
+
+	// Spans are compressed
+	blob, err := db.Get(spanKey(3))
+	require.NoError(t, err)
+	require.Equal(t, byte(spanSnappyVersion), blob[0])
+
+	// Spans persisted before compression was added still load
+	legacy, err := json.Marshal(fetched)
+	require.NoError(t, err)
+	require.NoError(t, db.Put(spanKey(3), legacy))
+
+	stored, err := NewSpanStore(db, nil).GetSpanById(context.Background(), 3)
+	require.NoError(t, err)
+	require.Equal(t, fetched.Span, stored.Span)
+	require.Equal(t, len(fetched.ValidatorSet.Validators), len(stored.ValidatorSet.Validators))
+}
+
+func TestSpanStoreGetSpanByBlock(t *testing.T) {
+	t.Parallel()
+
+	var (
+		heimdall = &spanHeimdallClient{lastID: 100}
+		store    = NewSpanStore(rawdb.NewMemoryDatabase(), heimdall)
+	)
+
+	tests := []struct {
+		number uint64
+		id     uint64
+	}{
+		{0, 0},
+		{255, 0},
+		{256, 1},
+		{5000, 48},
+		{355, 1},
+		{356, 2},
+		{10255, 100},
+	}
+
+	for _, test := range tests {
+		found, err := store.GetSpanByBlock(context.Background(), test.number)
+		require.NoError(t, err, test.number)
+		require.Equal(t, test.id, found.ID, test.number)
+		require.LessOrEqual(t, found.StartBlock, test.number)
+		require.GreaterOrEqual(t, found.EndBlock, test.number)
+	}
+
+	// Blocks beyond the last span are unknown
+	_, err := store.GetSpanByBlock(context.Background(), 10256)
+	require.Error(t, err)
+
+	// Only the spans fetched so far are found without heimdall
+	found, err := store.storedSpanByBlock(5000)
+	require.NoError(t, err)
+	require.Equal(t, uint64(48), found.ID)
+
+	calls := heimdall.calls
+	_, err = store.storedSpanByBlock(7000)
+	require.ErrorIs(t, err, errUnknownSpan)
+	require.Equal(t, calls, heimdall.calls)
+}
@@ -0,0 +1,86 @@
+package bor
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+)
+
+// Sprint describes the sprint containing a block, along with the span it
+// belongs to and the producers selected for that span.
+type Sprint struct {
+	Number         uint64             `json:"number"`
+	Length         uint64             `json:"length"`
+	StartBlock     uint64             `json:"startBlock"`
+	EndBlock       uint64             `json:"endBlock"`
+	SpanID         uint64             `json:"spanId"`
+	SpanStartBlock uint64             `json:"spanStartBlock"`
+	SpanEndBlock   uint64             `json:"spanEndBlock"`
+	Producers      []valset.Validator `json:"producers"`
+}
+
+// GetSprint returns the sprint containing the given block, assembled from the
+// sprint config in effect at the block and the locally indexed spans.
+func (c *Bor) GetSprint(ctx context.Context, chain consensus.ChainHeaderReader, number uint64) (*Sprint, error) {
+	heimdallSpan, err := c.spanStore.GetSpanByBlock(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	producers, err := c.spanProducers(ctx, chain, heimdallSpan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sprint{
+		Number:         c.config.CalculateSprintNumber(number),
+		Length:         c.config.CalculateSprint(number),
+		StartBlock:     c.config.CalculateSprintStart(number),
+		EndBlock:       c.config.CalculateSprintEnd(number),
+		SpanID:         heimdallSpan.ID,
+		SpanStartBlock: heimdallSpan.StartBlock,
+		SpanEndBlock:   heimdallSpan.EndBlock,
+		Producers:      producers,
+	}, nil
+}
+
+// producerCounter is implemented by the spanners reading the producer count
+// the validator contract caps the committed spans to.
+type producerCounter interface {
+	GetProducerCount(ctx context.Context, headerHash common.Hash) (uint64, error)
+}
+
+// spanProducers returns the producers of a span as committed to the validator
+// contract: the ones selected by heimdall, capped to the producer count of the
+// contract before the block committing the span once the cap is switched on.
+// The count of the head is used for the spans not committed yet.
+func (c *Bor) spanProducers(ctx context.Context, chain consensus.ChainHeaderReader, heimdallSpan *span.HeimdallSpan) ([]valset.Validator, error) {
+	counter, ok := c.spanner.(producerCounter)
+	if !ok || heimdallSpan.StartBlock == 0 {
+		return heimdallSpan.SelectedProducers, nil
+	}
+
+	// The span is committed at the first block of the last sprint before it
+	end := heimdallSpan.StartBlock - 1
+	commit := end - c.config.CalculateSprint(end) + 1
+
+	if !c.config.IsProducerCount(new(big.Int).SetUint64(commit)) {
+		return heimdallSpan.SelectedProducers, nil
+	}
+
+	parent := chain.GetHeaderByNumber(commit - 1)
+	if parent == nil {
+		parent = chain.CurrentHeader()
+	}
+
+	count, err := counter.GetProducerCount(ctx, parent.Hash())
+	if err != nil {
+		return nil, err
+	}
+
+	return span.LimitProducers(heimdallSpan.SelectedProducers, count), nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/log"
@@ -76,8 +77,13 @@ func ApplyMessage(
 	blockContext := core.NewEVMBlockContext(header, chainContext, &header.Coinbase)
 
 	// Create a new environment which holds all relevant information
-	// about the transaction and calling mechanisms.
-	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, state, chainConfig, vm.Config{})
+	// about the transaction and calling mechanisms. The system call is traced
+	// by the hooks set on the state, if any.
+	hooks := state.Logger()
+	vmenv := vm.NewEVM(blockContext, vm.TxContext{}, state, chainConfig, vm.Config{Tracer: hooks})
+
+	tx := SystemTransaction(msg)
+	startSystemCall(hooks, vmenv, tx, msg)
 
 	// nolint : contextcheck
 	// Apply the transaction to the current state (included in the env)
@@ -90,6 +96,8 @@ func ApplyMessage(
 		nil,
 	)
 
+	endSystemCall(hooks, header, tx, msg.Gas()-gasLeft, err)
+
 	success := big.NewInt(5).SetBytes(ret)
 
 	validatorContract := common.HexToAddress(chainConfig.Bor.ValidatorContract)
@@ -140,3 +148,63 @@ func ApplyBorMessage(vmenv *vm.EVM, msg Callmsg) (*core.ExecutionResult, error)
 		ReturnData: ret,
 	}, nil
 }
+
+// SystemTransaction returns the synthetic transaction a system message is traced
+// as, since it isn't part of the transactions of the block.
+func SystemTransaction(msg Callmsg) *types.Transaction {
+	return types.NewTx(&types.LegacyTx{
+		GasPrice: msg.GasPrice(),
+		Gas:      msg.Gas(),
+		To:       msg.To(),
+		Value:    msg.Value(),
+		Data:     msg.Data(),
+	})
+}
+
+// startSystemCall notifies the tracing hooks of the start of a system call, as
+// a system call and as the start of the synthetic transaction it's traced as.
+func startSystemCall(hooks *tracing.Hooks, vmenv *vm.EVM, tx *types.Transaction, msg Callmsg) {
+	if hooks == nil {
+		return
+	}
+
+	if hooks.OnSystemCallStart != nil {
+		hooks.OnSystemCallStart()
+	}
+
+	if hooks.OnTxStart != nil {
+		env := vmenv.GetVMContext()
+		env.GasPrice = msg.GasPrice()
+
+		hooks.OnTxStart(env, tx, msg.From())
+	}
+}
+
+// endSystemCall notifies the tracing hooks of the end of a system call, along
+// with the receipt of the synthetic transaction it's traced as.
+func endSystemCall(hooks *tracing.Hooks, header *types.Header, tx *types.Transaction, gasUsed uint64, err error) {
+	if hooks == nil {
+		return
+	}
+
+	if hooks.OnTxEnd != nil {
+		receipt := &types.Receipt{
+			Type:        tx.Type(),
+			Status:      types.ReceiptStatusSuccessful,
+			TxHash:      tx.Hash(),
+			GasUsed:     gasUsed,
+			BlockNumber: header.Number,
+		}
+
+		if err != nil {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+
+		// Reverts are reported through the receipt, the error is for invalid transactions
+		hooks.OnTxEnd(receipt, nil)
+	}
+
+	if hooks.OnSystemCallEnd != nil {
+		hooks.OnSystemCallEnd()
+	}
+}
@@ -0,0 +1,47 @@
+package bor
+
+// stateSyncBudget bounds the gas used and the event data committed by the
+// state-sync events of a sprint. Events beyond the budget are left in the
+// queue and committed at the next sprint, as the next fetch resumes from the
+// last state id of the StateReceiver contract. Zero limits are unlimited.
+type stateSyncBudget struct {
+	gasLimit  uint64
+	sizeLimit uint64
+
+	gasUsed uint64
+	size    uint64
+	events  int
+}
+
+func newStateSyncBudget(gasLimit uint64, sizeLimit uint64) *stateSyncBudget {
+	return &stateSyncBudget{
+		gasLimit:  gasLimit,
+		sizeLimit: sizeLimit,
+	}
+}
+
+// fits reports whether an event with the given data size may be committed.
+// The first event of a sprint always fits, so that the queue keeps draining
+// even if a single event exceeds the budget.
+func (b *stateSyncBudget) fits(size int) bool {
+	if b.events == 0 {
+		return true
+	}
+
+	if b.gasLimit > 0 && b.gasUsed >= b.gasLimit {
+		return false
+	}
+
+	if b.sizeLimit > 0 && b.size+uint64(size) > b.sizeLimit {
+		return false
+	}
+
+	return true
+}
+
+// add accounts a committed event against the budget.
+func (b *stateSyncBudget) add(size int, gasUsed uint64) {
+	b.events++
+	b.size += uint64(size)
+	b.gasUsed += gasUsed
+}
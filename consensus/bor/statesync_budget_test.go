@@ -0,0 +1,188 @@
+package bor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/statefull"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// stateSyncEvents is a source serving the given state-sync events.
+type stateSyncEvents []*clerk.EventRecordWithTime
+
+func (s stateSyncEvents) StateSyncEvents(_ context.Context, fromID uint64, _ int64) ([]*clerk.EventRecordWithTime, error) {
+	var events []*clerk.EventRecordWithTime
+
+	for _, event := range s {
+		if event.ID >= fromID {
+			events = append(events, event)
+		}
+	}
+
+	return events, nil
+}
+
+// committingContract is a genesis contract client committing every state-sync
+// event for the given gas.
+type committingContract struct {
+	gas uint64
+}
+
+func (c *committingContract) CommitState(event *clerk.EventRecordWithTime, state *state.StateDB, header *types.Header, chCtx statefull.ChainContext) (uint64, error) {
+	return c.gas, nil
+}
+
+func (c *committingContract) LastStateId(_ context.Context, state *state.StateDB, number uint64, hash common.Hash) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+// newStateSyncEngine returns an engine committing the given state-sync events,
+// each for the given gas, from a chain configured by the given bor config.
+func newStateSyncEngine(borConfig *params.BorConfig, gas uint64, events ...*clerk.EventRecordWithTime) *Bor {
+	borConfig.Sprint = map[string]uint64{"0": 16}
+	borConfig.IndoreBlock = big.NewInt(0)
+
+	return &Bor{
+		chainConfig:            &params.ChainConfig{ChainID: big.NewInt(1337), Bor: borConfig},
+		config:                 borConfig,
+		db:                     rawdb.NewMemoryDatabase(),
+		stateSyncSource:        stateSyncEvents(events),
+		GenesisContractsClient: &committingContract{gas: gas},
+	}
+}
+
+// testStateSyncEvents returns n contiguous state-sync events from id 1 on.
+func testStateSyncEvents(n int) []*clerk.EventRecordWithTime {
+	events := make([]*clerk.EventRecordWithTime, n)
+
+	for i := range events {
+		events[i] = &clerk.EventRecordWithTime{
+			EventRecord: clerk.EventRecord{ID: uint64(i + 1), Data: make([]byte, 32), ChainID: "1337"},
+			Time:        time.Unix(1, 0),
+		}
+	}
+
+	return events
+}
+
+// commitStatesAt commits the state-sync events of the given sprint start block.
+func commitStatesAt(t *testing.T, b *Bor, number int64) []*types.StateSyncData {
+	t.Helper()
+
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(b.db), nil)
+	require.NoError(t, err)
+
+	header := &types.Header{Number: big.NewInt(number), Time: uint64(time.Now().Unix())}
+
+	stateSyncs, err := b.CommitStates(context.Background(), statedb, header, statefull.ChainContext{Bor: b})
+	require.NoError(t, err)
+
+	return stateSyncs
+}
+
+func TestStateSyncBudget(t *testing.T) {
+	t.Parallel()
+
+	// Unlimited budgets fit everything
+	unlimited := newStateSyncBudget(0, 0)
+	for i := 0; i < 100; i++ {
+		require.True(t, unlimited.fits(1<<20))
+		unlimited.add(1<<20, 1_000_000)
+	}
+
+	// The gas budget stops the sprint once it's used up
+	gas := newStateSyncBudget(100_000, 0)
+	require.True(t, gas.fits(10))
+	gas.add(10, 60_000)
+	require.True(t, gas.fits(10))
+	gas.add(10, 60_000)
+	require.False(t, gas.fits(10))
+
+	// The size budget is checked before committing an event
+	size := newStateSyncBudget(0, 100)
+	require.True(t, size.fits(60))
+	size.add(60, 1)
+	require.False(t, size.fits(60))
+	require.True(t, size.fits(40))
+
+	// The first event of a sprint is committed even if it exceeds the budget
+	oversized := newStateSyncBudget(10, 10)
+	require.True(t, oversized.fits(1000))
+	oversized.add(1000, 1000)
+	require.False(t, oversized.fits(1))
+}
+
+func TestCommitStatesBudget(t *testing.T) {
+	t.Parallel()
+
+	config := &params.BorConfig{
+		StateSyncGasLimit: map[string]uint64{"32": 100_000},
+	}
+
+	// The budget doesn't apply to the sprints before the one it's switched on at
+	b := newStateSyncEngine(config, 60_000, testStateSyncEvents(3)...)
+	require.Len(t, commitStatesAt(t, b, 16), 3)
+
+	b = newStateSyncEngine(config, 60_000, testStateSyncEvents(3)...)
+	require.Len(t, commitStatesAt(t, b, 32), 2)
+}
+
+func TestCommitStatesOverride(t *testing.T) {
+	t.Parallel()
+
+	config := &params.BorConfig{
+		OverrideStateSyncRecords: map[string]int{"16": 3, "32": 10},
+	}
+
+	// Event 3 is missing, the gap falls before the override count
+	events := testStateSyncEvents(5)
+	events = append(events[:2], events[3:]...)
+
+	b := newStateSyncEngine(config, 0, events...)
+	require.Len(t, commitStatesAt(t, b, 16), 2)
+
+	// Overrides beyond the records served commit all of them
+	b = newStateSyncEngine(config, 0, testStateSyncEvents(5)...)
+	require.Len(t, commitStatesAt(t, b, 32), 5)
+
+	b = newStateSyncEngine(config, 0, testStateSyncEvents(5)...)
+	require.Len(t, commitStatesAt(t, b, 16), 3)
+}
+
+func TestCommitStatesContiguous(t *testing.T) {
+	t.Parallel()
+
+	config := &params.BorConfig{
+		ContiguousStateSyncBlock: big.NewInt(32),
+	}
+
+	// Events served out of order stop the sprint before the fork
+	events := testStateSyncEvents(3)
+	events[0], events[1] = events[1], events[0]
+
+	b := newStateSyncEngine(config, 0, events...)
+	require.Empty(t, commitStatesAt(t, b, 16))
+
+	// And are reordered after it, up to the first missing id
+	events = testStateSyncEvents(5)
+	events = append(events[:3], events[4:]...)
+	events[0], events[1] = events[1], events[0]
+
+	b = newStateSyncEngine(config, 0, events...)
+	stateSyncs := commitStatesAt(t, b, 32)
+	require.Len(t, stateSyncs, 3)
+
+	for i, stateSync := range stateSyncs {
+		require.Equal(t, uint64(i+1), stateSync.ID)
+	}
+}
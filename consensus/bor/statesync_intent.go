@@ -0,0 +1,69 @@
+package bor
+
+import (
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// beginStateSync writes ahead the intent to commit the given state-sync events
+// in a sprint start block being imported, before any of them is executed.
+func (c *Bor) beginStateSync(header *types.Header, events []*clerk.EventRecordWithTime) *rawdb.BorStateSyncIntent {
+	if len(events) == 0 || c.db == nil {
+		return nil
+	}
+
+	intent := &rawdb.BorStateSyncIntent{
+		Number: header.Number.Uint64(),
+		Hash:   header.Hash(),
+		FromID: events[0].ID,
+		ToID:   events[len(events)-1].ID,
+	}
+
+	rawdb.WriteBorStateSyncIntent(c.db, intent)
+
+	return intent
+}
+
+// completeStateSync marks the intent to commit state-sync events done, with the
+// number of events actually committed.
+func (c *Bor) completeStateSync(intent *rawdb.BorStateSyncIntent, applied int) {
+	if intent == nil {
+		return
+	}
+
+	intent.Applied, intent.Done = uint64(applied), true
+
+	rawdb.WriteBorStateSyncIntent(c.db, intent)
+}
+
+// checkStateSyncIntent reports the state-sync commits which were in flight when
+// the node stopped, if their block wasn't persisted. The events are committed
+// again when the block is imported again, so this only surfaces bridging work
+// which was cut short instead of silently relying on the determinism of the
+// re-execution.
+func checkStateSyncIntent(db ethdb.Database) {
+	intent := rawdb.ReadBorStateSyncIntent(db)
+	if intent == nil {
+		return
+	}
+
+	defer rawdb.DeleteBorStateSyncIntent(db)
+
+	if rawdb.HasHeader(db, intent.Hash, intent.Number) && rawdb.HasBorStateSyncEvents(db, intent.Hash, intent.Number) {
+		if events := uint64(len(rawdb.ReadBorStateSyncEvents(db, intent.Hash, intent.Number))); intent.Done && events != intent.Applied {
+			log.Warn("Persisted state-sync events differ from the ones committed", "number", intent.Number, "hash", intent.Hash, "committed", intent.Applied, "persisted", events)
+		}
+
+		return
+	}
+
+	if !intent.Done {
+		log.Warn("State-sync commits were interrupted, events partially applied to a discarded block", "number", intent.Number, "hash", intent.Hash, "fromID", intent.FromID, "toID", intent.ToID)
+		return
+	}
+
+	log.Warn("State-sync commits were applied to a block which wasn't persisted", "number", intent.Number, "hash", intent.Hash, "fromID", intent.FromID, "toID", intent.ToID, "events", intent.Applied)
+}
@@ -0,0 +1,100 @@
+package bor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
+	"github.com/ethereum/go-ethereum/consensus/bor/statefull"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestStateSyncIntent(t *testing.T) {
+	t.Parallel()
+
+	b := &Bor{db: rawdb.NewMemoryDatabase()}
+
+	header := &types.Header{Number: big.NewInt(16), ParentHash: common.Hash{0x01}}
+	hash := header.Hash()
+	events := []*clerk.EventRecordWithTime{
+		{EventRecord: clerk.EventRecord{ID: 5}},
+		{EventRecord: clerk.EventRecord{ID: 6}},
+		{EventRecord: clerk.EventRecord{ID: 7}},
+	}
+
+	// Nothing is recorded without events to commit
+	require.Nil(t, b.beginStateSync(header, nil))
+	require.Nil(t, rawdb.ReadBorStateSyncIntent(b.db))
+
+	intent := b.beginStateSync(header, events)
+	require.Equal(t, &rawdb.BorStateSyncIntent{Number: 16, Hash: hash, FromID: 5, ToID: 7}, rawdb.ReadBorStateSyncIntent(b.db))
+
+	b.completeStateSync(intent, 2)
+	require.Equal(t, &rawdb.BorStateSyncIntent{Number: 16, Hash: hash, FromID: 5, ToID: 7, Applied: 2, Done: true}, rawdb.ReadBorStateSyncIntent(b.db))
+
+	// The record is reported once on startup
+	checkStateSyncIntent(b.db)
+	require.Nil(t, rawdb.ReadBorStateSyncIntent(b.db))
+}
+
+// assemblingChain is the chain a block is assembled on, collecting the
+// state-sync events committed by the block.
+type assemblingChain struct {
+	consensus.ChainHeaderReader
+	config *params.ChainConfig
+}
+
+func (c *assemblingChain) Config() *params.ChainConfig                   { return c.config }
+func (c *assemblingChain) SetStateSync(stateData []*types.StateSyncData) {}
+
+func (c *assemblingChain) SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) event.Subscription {
+	return nil
+}
+
+// Tests that the intent is only recorded by blocks being imported, not by the
+// blocks assembled by the miner, which aren't sealed yet.
+func TestStateSyncIntentImportOnly(t *testing.T) {
+	t.Parallel()
+
+	b := newStateSyncEngine(&params.BorConfig{}, 1, testStateSyncEvents(3)...)
+	b.spanner = &committedSpanner{span: &span.Span{ID: 0, StartBlock: 0, EndBlock: 255}}
+
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(b.db), nil)
+	require.NoError(t, err)
+
+	header := &types.Header{Number: big.NewInt(16), ParentHash: common.Hash{0x01}, Time: uint64(time.Now().Unix())}
+
+	block, err := b.FinalizeAndAssemble(&assemblingChain{config: b.chainConfig}, header, statedb, &types.Body{}, nil)
+	require.NoError(t, err)
+	require.Len(t, statedb.BorStateSyncData, 3)
+	require.NotNil(t, block)
+	require.Nil(t, rawdb.ReadBorStateSyncIntent(b.db))
+
+	// Nor does committing the events out of any block, e.g. when tracing
+	require.Len(t, commitStatesAt(t, b, 16), 3)
+	require.Nil(t, rawdb.ReadBorStateSyncIntent(b.db))
+
+	// Importing the block does
+	statedb, err = state.New(types.EmptyRootHash, state.NewDatabase(b.db), nil)
+	require.NoError(t, err)
+
+	stateSyncs, err := b.commitStates(context.Background(), statedb, header, statefull.ChainContext{Bor: b}, true)
+	require.NoError(t, err)
+	require.Len(t, stateSyncs, 3)
+	require.Equal(t, &rawdb.BorStateSyncIntent{Number: 16, Hash: header.Hash(), FromID: 1, ToID: 3, Applied: 3, Done: true}, rawdb.ReadBorStateSyncIntent(b.db))
+
+	// Engines without a database don't record anything
+	require.Nil(t, (&Bor{}).beginStateSync(header, testStateSyncEvents(1)))
+}
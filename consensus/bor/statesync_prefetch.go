@@ -0,0 +1,127 @@
+package bor
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/statefull"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// stateSyncPrefetchTimeout bounds fetching the pending state-sync events from
+// Heimdall for a prefetch.
+const stateSyncPrefetchTimeout = 2 * time.Second
+
+// stateSyncPrefetcher is implemented by genesis contract clients able to
+// execute the commit of an event against a throwaway state.
+type stateSyncPrefetcher interface {
+	PrefetchState(event *clerk.EventRecordWithTime, state *state.StateDB, header *types.Header, chCtx statefull.ChainContext) (uint64, error)
+}
+
+// maybePrefetchStateSync starts prefetching the state touched by the
+// state-sync events to be committed by the next block, if it starts a new
+// sprint. The heavy sprint start block then mostly finds the state receiver
+// contract and the onStateReceive targets in the trie caches. Only blocks near
+// the chain head are considered, so syncing doesn't query Heimdall twice per
+// sprint, and every sprint is prefetched once even though the miner finalizes
+// the same block repeatedly.
+func (c *Bor) maybePrefetchStateSync(chain consensus.ChainHeaderReader, header *types.Header, statedb *state.StateDB) {
+	if c.stateSyncs() == nil {
+		return
+	}
+
+	prefetcher, ok := c.GenesisContractsClient.(stateSyncPrefetcher)
+	if !ok {
+		return
+	}
+
+	next := header.Number.Uint64() + 1
+	if !IsSprintStart(next, c.config.CalculateSprint(next)) || !c.config.IsIndore(new(big.Int).SetUint64(next)) {
+		return
+	}
+
+	var (
+		period   = c.config.CalculatePeriod(next)
+		nextTime = header.Time + period
+		sprint   = time.Duration(c.config.CalculateSprint(next)*period) * time.Second
+	)
+
+	if time.Since(time.Unix(int64(nextTime), 0)) > sprint {
+		return
+	}
+
+	if prev := c.stateSyncPrefetched.Load(); prev >= next || !c.stateSyncPrefetched.CompareAndSwap(prev, next) {
+		return
+	}
+
+	// The header of the block to be committing the events, as far as the
+	// execution of the commits is concerned
+	nextHeader := &types.Header{
+		ParentHash: header.Hash(),
+		Number:     new(big.Int).SetUint64(next),
+		GasLimit:   header.GasLimit,
+		Time:       nextTime,
+		Difficulty: header.Difficulty,
+		BaseFee:    header.BaseFee,
+	}
+
+	go c.prefetchStateSync(prefetcher, chain, nextHeader, statedb.Copy())
+}
+
+// prefetchStateSync executes the commits of the state-sync events pending for
+// the sprint starting with the given header against a copy of its parent state.
+func (c *Bor) prefetchStateSync(prefetcher stateSyncPrefetcher, chain consensus.ChainHeaderReader, header *types.Header, statedb *state.StateDB) {
+	lastStateID := rawdb.ReadBorLastStateSyncID(c.db)
+	if lastStateID == nil {
+		return
+	}
+
+	var (
+		start  = time.Now()
+		number = header.Number.Uint64()
+		from   = *lastStateID + 1
+		to     = time.Unix(int64(header.Time-c.config.CalculateStateSyncDelay(number)), 0)
+	)
+
+	ctx, cancel := context.WithTimeout(c.engineCtx(), stateSyncPrefetchTimeout)
+	defer cancel()
+
+	events, err := c.stateSyncs().StateSyncEvents(ctx, from, to.Unix())
+	if err != nil {
+		log.Debug("Failed to fetch state-sync events to prefetch", "number", number, "fromID", from, "err", err)
+		return
+	}
+
+	if c.config.IsContiguousStateSync(header.Number) {
+		events, _ = clerk.Contiguous(events, from)
+	}
+
+	cx := statefull.ChainContext{Chain: chain, Bor: c}
+	budget := newStateSyncBudget(c.config.CalculateStateSyncGasLimit(number), c.config.CalculateStateSyncSizeLimit(number))
+
+	var prefetched int
+
+	for _, event := range events {
+		if !budget.fits(len(event.Data)) {
+			break
+		}
+
+		gasUsed, err := prefetcher.PrefetchState(event, statedb, header, cx)
+		if err != nil {
+			log.Debug("Failed to prefetch state-sync event", "number", number, "id", event.ID, "err", err)
+			break
+		}
+
+		budget.add(len(event.Data), gasUsed)
+
+		prefetched++
+	}
+
+	log.Debug("Prefetched state-sync events", "number", number, "fromID", from, "events", prefetched, "elapsed", time.Since(start))
+}
@@ -0,0 +1,106 @@
+package bor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
+	"github.com/ethereum/go-ethereum/consensus/bor/statefull"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/tests/bor/mocks"
+)
+
+// prefetchingContract is a genesis contract client reporting the events it's
+// asked to prefetch.
+type prefetchingContract struct {
+	prefetched chan uint64
+}
+
+func (c *prefetchingContract) CommitState(event *clerk.EventRecordWithTime, state *state.StateDB, header *types.Header, chCtx statefull.ChainContext) (uint64, error) {
+	return 0, nil
+}
+
+func (c *prefetchingContract) LastStateId(_ context.Context, state *state.StateDB, number uint64, hash common.Hash) (*big.Int, error) {
+	return big.NewInt(0), nil
+}
+
+func (c *prefetchingContract) PrefetchState(event *clerk.EventRecordWithTime, state *state.StateDB, header *types.Header, chCtx statefull.ChainContext) (uint64, error) {
+	c.prefetched <- event.ID
+	return 0, nil
+}
+
+func TestPrefetchStateSync(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctrl = gomock.NewController(t)
+		db   = rawdb.NewMemoryDatabase()
+
+		contract = &prefetchingContract{prefetched: make(chan uint64, 4)}
+		heimdall = mocks.NewMockIHeimdallClient(ctrl)
+	)
+
+	borConfig := &params.BorConfig{
+		Sprint:      map[string]uint64{"0": 16},
+		Period:      map[string]uint64{"0": 2},
+		IndoreBlock: big.NewInt(0),
+	}
+
+	b := &Bor{
+		config:                 borConfig,
+		db:                     db,
+		HeimdallClient:         heimdall,
+		GenesisContractsClient: contract,
+	}
+
+	rawdb.WriteBorLastStateSyncID(db, 4)
+
+	events := []*clerk.EventRecordWithTime{
+		{EventRecord: clerk.EventRecord{ID: 5}},
+		{EventRecord: clerk.EventRecord{ID: 6}},
+	}
+	heimdall.EXPECT().StateSyncEvents(gomock.Any(), uint64(5), gomock.Any()).Return(events, nil).Times(1)
+
+	statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(db), nil)
+	require.NoError(t, err)
+
+	header := func(number int64, at time.Time) *types.Header {
+		return &types.Header{
+			Number:     big.NewInt(number),
+			Time:       uint64(at.Unix()),
+			Difficulty: big.NewInt(1),
+		}
+	}
+
+	// Blocks not followed by a sprint start, and old blocks, aren't prefetched for
+	b.maybePrefetchStateSync(nil, header(14, time.Now()), statedb)
+	b.maybePrefetchStateSync(nil, header(31, time.Now().Add(-time.Hour)), statedb)
+
+	// The events are prefetched once per sprint
+	b.maybePrefetchStateSync(nil, header(15, time.Now()), statedb)
+	b.maybePrefetchStateSync(nil, header(15, time.Now()), statedb)
+
+	for _, id := range []uint64{5, 6} {
+		select {
+		case got := <-contract.prefetched:
+			require.Equal(t, id, got)
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not prefetched", id)
+		}
+	}
+
+	select {
+	case id := <-contract.prefetched:
+		t.Fatalf("event %d prefetched twice", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
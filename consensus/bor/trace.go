@@ -0,0 +1,181 @@
+package bor
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TraceLevel is the verbosity of the consensus tracer.
+type TraceLevel int
+
+const (
+	// TraceOff disables consensus tracing.
+	TraceOff TraceLevel = iota
+
+	// TraceVerification traces the outcome of header and seal verifications.
+	TraceVerification
+
+	// TraceSnapshots additionally traces every header applied to a snapshot.
+	TraceSnapshots
+)
+
+const (
+	// defaultTraceBlocks is the number of blocks traced if not specified.
+	defaultTraceBlocks = 64
+
+	// traceCapacity is the number of entries kept in the trace ring buffer.
+	traceCapacity = 4096
+)
+
+var errInvalidTraceLevel = errors.New("invalid trace level")
+
+// TraceEntry is a single consensus decision or snapshot transition recorded
+// by the tracer.
+type TraceEntry struct {
+	Time    time.Time              `json:"time"`
+	Number  uint64                 `json:"number"`
+	Hash    common.Hash            `json:"hash"`
+	Event   string                 `json:"event"`
+	Error   string                 `json:"error,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// consensusTracer records consensus decisions into a ring buffer while
+// enabled. It disables itself once the configured number of blocks has been
+// traced, so it can't be left on by accident on a production node.
+type consensusTracer struct {
+	mu        sync.Mutex
+	level     TraceLevel
+	remaining uint64 // blocks left to trace before disabling
+	highest   uint64 // highest block traced since enabled
+
+	entries []TraceEntry
+	next    int // index of the next entry to write
+	full    bool
+}
+
+func newConsensusTracer() *consensusTracer {
+	return &consensusTracer{
+		entries: make([]TraceEntry, traceCapacity),
+	}
+}
+
+// setLevel enables tracing at the given level for the given number of blocks,
+// or disables it for TraceOff. The entries recorded so far are kept.
+func (t *consensusTracer) setLevel(level TraceLevel, blocks uint64) error {
+	if level < TraceOff || level > TraceSnapshots {
+		return errInvalidTraceLevel
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.level = level
+	t.remaining = blocks
+	t.highest = 0
+
+	return nil
+}
+
+// enabled reports whether entries of the given level are recorded. It's
+// cheap enough to guard the construction of the entries' details.
+func (t *consensusTracer) enabled(level TraceLevel) bool {
+	if t == nil {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.level >= level
+}
+
+// record adds an entry about the given header if tracing is enabled at the
+// given level.
+func (t *consensusTracer) record(level TraceLevel, header *types.Header, event string, err error, details map[string]interface{}) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.level < level {
+		return
+	}
+
+	number := header.Number.Uint64()
+
+	// Count the blocks traced, disabling the tracer once a block beyond the
+	// requested number shows up
+	if number > t.highest {
+		if t.remaining == 0 {
+			t.level = TraceOff
+			return
+		}
+
+		t.remaining--
+		t.highest = number
+	}
+
+	entry := TraceEntry{
+		Time:    time.Now(),
+		Number:  number,
+		Hash:    header.Hash(),
+		Event:   event,
+		Details: details,
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	t.entries[t.next] = entry
+	t.next = (t.next + 1) % len(t.entries)
+
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+// snapshot returns the recorded entries, oldest first.
+func (t *consensusTracer) snapshot() []TraceEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.full {
+		return append([]TraceEntry(nil), t.entries[:t.next]...)
+	}
+
+	entries := make([]TraceEntry, 0, len(t.entries))
+	entries = append(entries, t.entries[t.next:]...)
+
+	return append(entries, t.entries[:t.next]...)
+}
+
+// TraceAPI is the debug API to trace the consensus engine at run time.
+type TraceAPI struct {
+	bor *Bor
+}
+
+// SetBorTrace enables tracing of the consensus engine at the given level for
+// the next blocks (64 if not specified), after which tracing is disabled.
+// Level 0 disables tracing, 1 traces header and seal verifications and 2
+// additionally traces snapshot transitions.
+func (api *TraceAPI) SetBorTrace(level TraceLevel, blocks *uint64) error {
+	count := uint64(defaultTraceBlocks)
+	if blocks != nil && *blocks > 0 {
+		count = *blocks
+	}
+
+	return api.bor.tracer.setLevel(level, count)
+}
+
+// GetBorTrace returns the consensus trace recorded so far, oldest first.
+func (api *TraceAPI) GetBorTrace() []TraceEntry {
+	return api.bor.tracer.snapshot()
+}
@@ -0,0 +1,63 @@
+package bor
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func traceHeader(number int64) *types.Header {
+	return &types.Header{Number: big.NewInt(number)}
+}
+
+func TestConsensusTracer(t *testing.T) {
+	t.Parallel()
+
+	tracer := newConsensusTracer()
+
+	// Nothing is recorded while disabled
+	tracer.record(TraceVerification, traceHeader(1), "verifyHeader", nil, nil)
+	require.Empty(t, tracer.snapshot())
+
+	require.ErrorIs(t, tracer.setLevel(TraceSnapshots+1, 1), errInvalidTraceLevel)
+
+	// Verification level skips snapshot transitions
+	require.NoError(t, tracer.setLevel(TraceVerification, 2))
+	require.True(t, tracer.enabled(TraceVerification))
+	require.False(t, tracer.enabled(TraceSnapshots))
+
+	tracer.record(TraceSnapshots, traceHeader(10), "applySnapshot", nil, nil)
+	tracer.record(TraceVerification, traceHeader(10), "verifySeal", nil, nil)
+	tracer.record(TraceVerification, traceHeader(10), "verifyHeader", errors.New("bad seal"), nil)
+	tracer.record(TraceVerification, traceHeader(11), "verifyHeader", nil, nil)
+
+	// The tracer disables itself on the first block beyond the requested ones
+	tracer.record(TraceVerification, traceHeader(12), "verifyHeader", nil, nil)
+	require.False(t, tracer.enabled(TraceVerification))
+
+	entries := tracer.snapshot()
+	require.Len(t, entries, 3)
+	require.Equal(t, "verifySeal", entries[0].Event)
+	require.Equal(t, "bad seal", entries[1].Error)
+	require.Equal(t, uint64(11), entries[2].Number)
+}
+
+func TestConsensusTracerRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	tracer := newConsensusTracer()
+	require.NoError(t, tracer.setLevel(TraceVerification, traceCapacity+10))
+
+	for i := int64(1); i <= traceCapacity+10; i++ {
+		tracer.record(TraceVerification, traceHeader(i), "verifyHeader", nil, nil)
+	}
+
+	entries := tracer.snapshot()
+	require.Len(t, entries, traceCapacity)
+	require.Equal(t, uint64(11), entries[0].Number)
+	require.Equal(t, uint64(traceCapacity+10), entries[len(entries)-1].Number)
+}
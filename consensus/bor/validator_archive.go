@@ -0,0 +1,60 @@
+package bor
+
+import (
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// SetValidatorArchive sets whether the validator set taking over at every
+// sprint boundary is archived to the database, so that the validators of any
+// historical block can be queried without replaying headers.
+func (c *Bor) SetValidatorArchive(enabled bool) {
+	c.validatorArchive = enabled
+}
+
+// archiveValidators stores the validator set taking over after the given sprint
+// end header. Sets are keyed by the hash of the header, so that the sets of
+// reorged-out sprints don't shadow the canonical ones.
+func (c *Bor) archiveValidators(header *types.Header, validators *valset.ValidatorSet) {
+	blob, err := json.Marshal(validators.Validators)
+	if err != nil {
+		log.Error("Failed to encode validator set to archive", "number", header.Number, "err", err)
+		return
+	}
+
+	rawdb.WriteBorValidatorSet(c.db, header.Hash(), header.Number.Uint64(), blob)
+}
+
+// readArchivedValidators retrieves the validator set archived for the given
+// sprint end block, or nil if it wasn't archived.
+func readArchivedValidators(db ethdb.KeyValueReader, hash common.Hash, number uint64) ([]*valset.Validator, error) {
+	blob := rawdb.ReadBorValidatorSet(db, hash, number)
+	if len(blob) == 0 {
+		return nil, nil
+	}
+
+	var validators []*valset.Validator
+	if err := json.Unmarshal(blob, &validators); err != nil {
+		return nil, err
+	}
+
+	return validators, nil
+}
+
+// lastSprintEnd returns the number of the latest sprint end block at or before
+// the given block, whose header carries the validator set in charge after it.
+// It reports false within the first sprint, whose validators come from the
+// genesis span.
+func lastSprintEnd(number uint64, sprint uint64) (uint64, bool) {
+	if number+1 < sprint {
+		return 0, false
+	}
+
+	return number - (number+1)%sprint, true
+}
@@ -0,0 +1,62 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestLastSprintEnd(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		number uint64
+		end    uint64
+		ok     bool
+	}{
+		{0, 0, false},
+		{14, 0, false},
+		{15, 15, true},
+		{16, 15, true},
+		{30, 15, true},
+		{31, 31, true},
+	} {
+		end, ok := lastSprintEnd(tt.number, 16)
+		require.Equal(t, tt.ok, ok, "number %d", tt.number)
+		require.Equal(t, tt.end, end, "number %d", tt.number)
+	}
+}
+
+func TestArchiveValidators(t *testing.T) {
+	t.Parallel()
+
+	b := &Bor{db: rawdb.NewMemoryDatabase()}
+	b.SetValidatorArchive(true)
+
+	header := &types.Header{Number: big.NewInt(15), Extra: []byte{0x01}}
+	validators := valset.NewValidatorSet([]*valset.Validator{
+		valset.NewValidator(common.Address{0x01}, 10),
+		valset.NewValidator(common.Address{0x02}, 20),
+	})
+
+	archived, err := readArchivedValidators(b.db, header.Hash(), 15)
+	require.NoError(t, err)
+	require.Nil(t, archived)
+
+	b.archiveValidators(header, validators)
+
+	archived, err = readArchivedValidators(b.db, header.Hash(), 15)
+	require.NoError(t, err)
+	require.Equal(t, validators.Validators, archived)
+
+	// Sets archived for other blocks of the same height aren't returned
+	archived, err = readArchivedValidators(b.db, common.Hash{0x01}, 15)
+	require.NoError(t, err)
+	require.Nil(t, archived)
+}
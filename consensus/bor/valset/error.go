@@ -1,6 +1,10 @@
 package valset
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
 
 // TotalVotingPowerExceededError is returned when the maximum allowed total voting power is exceeded
 type TotalVotingPowerExceededError struct {
@@ -30,3 +34,41 @@ func (e *InvalidStartEndBlockError) Error() string {
 		e.End,
 	)
 }
+
+// InvalidValidatorBytesError is returned if the validator set bytes of a
+// header aren't a whole number of validator records, or are in an unknown
+// layout.
+type InvalidValidatorBytesError struct {
+	Version byte
+	Length  int
+}
+
+func (e *InvalidValidatorBytesError) Error() string {
+	if e.Version != ValidatorBytesV1 && e.Version != ValidatorBytesV2 {
+		return fmt.Sprintf("invalid validator bytes length %d, unknown version %d", e.Length, e.Version)
+	}
+
+	return fmt.Sprintf("invalid v%d validator bytes length %d, not a multiple of %d", e.Version, e.Length, recordLength(e.Version))
+}
+
+// UnsortedValidatorsError is returned if the validators of a header aren't
+// sorted by ascending address, or if an address appears more than once.
+type UnsortedValidatorsError struct {
+	Index   int
+	Address common.Address
+}
+
+func (e *UnsortedValidatorsError) Error() string {
+	return fmt.Sprintf("validator %d (%s) not sorted by ascending address or duplicate", e.Index, e.Address)
+}
+
+// ZeroPowerValidatorError is returned if a validator of a header has no voting
+// power.
+type ZeroPowerValidatorError struct {
+	Index   int
+	Address common.Address
+}
+
+func (e *ZeroPowerValidatorError) Error() string {
+	return fmt.Sprintf("validator %d (%s) has no voting power", e.Index, e.Address)
+}
@@ -0,0 +1,319 @@
+package valset
+
+// SSZ encoding and merkleization of validator sets and sprints, so that proofs
+// about them can be verified by consumers standardizing on SSZ (e.g. SNARK
+// circuits, beacon chain light clients). The schemas are:
+//
+//	class Validator(Container):
+//	    id: uint64
+//	    address: Bytes20
+//	    voting_power: uint64
+//	    proposer_priority: uint64  # two's complement of the signed priority
+//
+//	class ValidatorSet(Container):
+//	    validators: List[Validator, MaxSSZValidators]
+//	    proposer: Bytes20          # zero if the set has no proposer
+//
+//	class SprintMetadata(Container):
+//	    start_block: uint64
+//	    end_block: uint64
+//	    validator_set: ValidatorSet
+
+import (
+	"errors"
+	"fmt"
+
+	ssz "github.com/ferranbt/fastssz"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MaxSSZValidators is the capacity of the validator list in the SSZ schema of a
+// validator set, which determines the depth of its merkle tree.
+const MaxSSZValidators = 1024
+
+const (
+	validatorSSZSize        = 8 + common.AddressLength + 8 + 8
+	validatorSetSSZFixed    = 4 + common.AddressLength
+	sprintMetadataSSZFixed  = 8 + 8 + 4
+	validatorSetSSZListName = "ValidatorSet.Validators"
+)
+
+// errUnknownProposer is returned when decoding a validator set whose proposer
+// is not part of the set.
+var errUnknownProposer = errors.New("proposer not in validator set")
+
+// MarshalSSZ ssz marshals the validator.
+func (v *Validator) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(v)
+}
+
+// MarshalSSZTo ssz marshals the validator into the given buffer.
+func (v *Validator) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if v.VotingPower < 0 {
+		return nil, fmt.Errorf("negative voting power %d of validator %s", v.VotingPower, v.Address)
+	}
+
+	dst = ssz.MarshalUint64(dst, v.ID)
+	dst = append(dst, v.Address[:]...)
+	dst = ssz.MarshalUint64(dst, uint64(v.VotingPower))
+	dst = ssz.MarshalUint64(dst, uint64(v.ProposerPriority))
+
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals the validator.
+func (v *Validator) UnmarshalSSZ(buf []byte) error {
+	if len(buf) != validatorSSZSize {
+		return ssz.ErrSize
+	}
+
+	v.ID = ssz.UnmarshallUint64(buf[0:8])
+	copy(v.Address[:], buf[8:28])
+	v.VotingPower = int64(ssz.UnmarshallUint64(buf[28:36]))
+	v.ProposerPriority = int64(ssz.UnmarshallUint64(buf[36:44]))
+
+	if v.VotingPower < 0 {
+		return fmt.Errorf("voting power of validator %s overflows", v.Address)
+	}
+
+	return nil
+}
+
+// SizeSSZ returns the ssz encoded size of the validator in bytes.
+func (v *Validator) SizeSSZ() int {
+	return validatorSSZSize
+}
+
+// HashTreeRoot ssz hashes the validator.
+func (v *Validator) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(v)
+}
+
+// HashTreeRootWith ssz hashes the validator with a hasher.
+func (v *Validator) HashTreeRootWith(hh ssz.HashWalker) error {
+	indx := hh.Index()
+
+	hh.PutUint64(v.ID)
+	hh.PutBytes(v.Address[:])
+	hh.PutUint64(uint64(v.VotingPower))
+	hh.PutUint64(uint64(v.ProposerPriority))
+
+	hh.Merkleize(indx)
+
+	return nil
+}
+
+// GetTree returns the ssz merkle tree of the validator, for building proofs.
+func (v *Validator) GetTree() (*ssz.Node, error) {
+	return ssz.ProofTree(v)
+}
+
+// MarshalSSZ ssz marshals the validator set.
+func (vals *ValidatorSet) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(vals)
+}
+
+// MarshalSSZTo ssz marshals the validator set into the given buffer.
+func (vals *ValidatorSet) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if size := len(vals.Validators); size > MaxSSZValidators {
+		return nil, ssz.ErrListTooBigFn(validatorSetSSZListName, size, MaxSSZValidators)
+	}
+
+	dst = ssz.WriteOffset(dst, validatorSetSSZFixed)
+	dst = append(dst, vals.proposerAddress().Bytes()...)
+
+	var err error
+
+	for _, val := range vals.Validators {
+		if dst, err = val.MarshalSSZTo(dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals the validator set. The proposer is set to a copy
+// of the validator with the encoded address.
+func (vals *ValidatorSet) UnmarshalSSZ(buf []byte) error {
+	if len(buf) < validatorSetSSZFixed {
+		return ssz.ErrSize
+	}
+
+	if ssz.ReadOffset(buf[0:4]) != validatorSetSSZFixed {
+		return ssz.ErrInvalidVariableOffset
+	}
+
+	num, err := ssz.DivideInt2(len(buf)-validatorSetSSZFixed, validatorSSZSize, MaxSSZValidators)
+	if err != nil {
+		return err
+	}
+
+	validators := make([]*Validator, num)
+
+	for i := range validators {
+		start := validatorSetSSZFixed + i*validatorSSZSize
+
+		validators[i] = new(Validator)
+		if err := validators[i].UnmarshalSSZ(buf[start : start+validatorSSZSize]); err != nil {
+			return err
+		}
+	}
+
+	vals.Validators = validators
+	vals.Proposer = nil
+	vals.UpdateValidatorMap()
+
+	if len(vals.validatorsMap) != len(vals.Validators) {
+		return errors.New("duplicate validator in validator set")
+	}
+
+	if proposer := common.BytesToAddress(buf[4:validatorSetSSZFixed]); proposer != (common.Address{}) {
+		_, val := vals.GetByAddress(proposer)
+		if val == nil {
+			return errUnknownProposer
+		}
+
+		vals.Proposer = val.Copy()
+	}
+
+	vals.totalVotingPower = 0
+
+	if len(vals.Validators) > 0 {
+		return vals.UpdateTotalVotingPower()
+	}
+
+	return nil
+}
+
+// SizeSSZ returns the ssz encoded size of the validator set in bytes.
+func (vals *ValidatorSet) SizeSSZ() int {
+	return validatorSetSSZFixed + len(vals.Validators)*validatorSSZSize
+}
+
+// HashTreeRoot ssz hashes the validator set.
+func (vals *ValidatorSet) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(vals)
+}
+
+// HashTreeRootWith ssz hashes the validator set with a hasher.
+func (vals *ValidatorSet) HashTreeRootWith(hh ssz.HashWalker) error {
+	indx := hh.Index()
+
+	num := uint64(len(vals.Validators))
+	if num > MaxSSZValidators {
+		return ssz.ErrIncorrectListSize
+	}
+
+	subIndx := hh.Index()
+
+	for _, val := range vals.Validators {
+		if err := val.HashTreeRootWith(hh); err != nil {
+			return err
+		}
+	}
+
+	hh.MerkleizeWithMixin(subIndx, num, MaxSSZValidators)
+
+	hh.PutBytes(vals.proposerAddress().Bytes())
+
+	hh.Merkleize(indx)
+
+	return nil
+}
+
+// GetTree returns the ssz merkle tree of the validator set, for building proofs.
+func (vals *ValidatorSet) GetTree() (*ssz.Node, error) {
+	return ssz.ProofTree(vals)
+}
+
+// proposerAddress returns the address of the proposer, or the zero address if
+// there's none.
+func (vals *ValidatorSet) proposerAddress() common.Address {
+	if vals.Proposer == nil {
+		return common.Address{}
+	}
+
+	return vals.Proposer.Address
+}
+
+// SprintMetadata describes a sprint and the validator set producing it.
+type SprintMetadata struct {
+	StartBlock   uint64        `json:"startBlock"`
+	EndBlock     uint64        `json:"endBlock"`
+	ValidatorSet *ValidatorSet `json:"validatorSet"`
+}
+
+// MarshalSSZ ssz marshals the sprint metadata.
+func (s *SprintMetadata) MarshalSSZ() ([]byte, error) {
+	return ssz.MarshalSSZ(s)
+}
+
+// MarshalSSZTo ssz marshals the sprint metadata into the given buffer.
+func (s *SprintMetadata) MarshalSSZTo(dst []byte) ([]byte, error) {
+	dst = ssz.MarshalUint64(dst, s.StartBlock)
+	dst = ssz.MarshalUint64(dst, s.EndBlock)
+	dst = ssz.WriteOffset(dst, sprintMetadataSSZFixed)
+
+	return s.validatorSet().MarshalSSZTo(dst)
+}
+
+// UnmarshalSSZ ssz unmarshals the sprint metadata.
+func (s *SprintMetadata) UnmarshalSSZ(buf []byte) error {
+	if len(buf) < sprintMetadataSSZFixed {
+		return ssz.ErrSize
+	}
+
+	if ssz.ReadOffset(buf[16:20]) != sprintMetadataSSZFixed {
+		return ssz.ErrInvalidVariableOffset
+	}
+
+	s.StartBlock = ssz.UnmarshallUint64(buf[0:8])
+	s.EndBlock = ssz.UnmarshallUint64(buf[8:16])
+	s.ValidatorSet = new(ValidatorSet)
+
+	return s.ValidatorSet.UnmarshalSSZ(buf[sprintMetadataSSZFixed:])
+}
+
+// SizeSSZ returns the ssz encoded size of the sprint metadata in bytes.
+func (s *SprintMetadata) SizeSSZ() int {
+	return sprintMetadataSSZFixed + s.validatorSet().SizeSSZ()
+}
+
+// HashTreeRoot ssz hashes the sprint metadata.
+func (s *SprintMetadata) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(s)
+}
+
+// HashTreeRootWith ssz hashes the sprint metadata with a hasher.
+func (s *SprintMetadata) HashTreeRootWith(hh ssz.HashWalker) error {
+	indx := hh.Index()
+
+	hh.PutUint64(s.StartBlock)
+	hh.PutUint64(s.EndBlock)
+
+	if err := s.validatorSet().HashTreeRootWith(hh); err != nil {
+		return err
+	}
+
+	hh.Merkleize(indx)
+
+	return nil
+}
+
+// GetTree returns the ssz merkle tree of the sprint metadata, for building
+// proofs.
+func (s *SprintMetadata) GetTree() (*ssz.Node, error) {
+	return ssz.ProofTree(s)
+}
+
+// validatorSet returns the validator set of the sprint, or an empty one if it's
+// not set.
+func (s *SprintMetadata) validatorSet() *ValidatorSet {
+	if s.ValidatorSet == nil {
+		return &ValidatorSet{}
+	}
+
+	return s.ValidatorSet
+}
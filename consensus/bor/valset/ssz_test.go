@@ -0,0 +1,155 @@
+package valset
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestValidatorSetSSZRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	validators := GetValidators()
+	for i, val := range validators {
+		val.ID = uint64(i + 1)
+	}
+
+	vals := NewValidatorSet(validators[:])
+	vals.IncrementProposerPriority(3)
+
+	blob, err := vals.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, blob, vals.SizeSSZ())
+
+	decoded := new(ValidatorSet)
+	require.NoError(t, decoded.UnmarshalSSZ(blob))
+
+	require.Equal(t, vals.Validators, decoded.Validators)
+	require.Equal(t, vals.GetProposer(), decoded.GetProposer())
+	require.Equal(t, vals.TotalVotingPower(), decoded.TotalVotingPower())
+	require.True(t, decoded.HasAddress(validators[2].Address))
+
+	root, err := vals.HashTreeRoot()
+	require.NoError(t, err)
+
+	decodedRoot, err := decoded.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, root, decodedRoot)
+
+	// The proof tree must agree with the hasher
+	tree, err := vals.GetTree()
+	require.NoError(t, err)
+	require.Equal(t, root[:], tree.Hash())
+
+	// Any change of a validator changes the root
+	decoded.Validators[1].VotingPower++
+
+	changedRoot, err := decoded.HashTreeRoot()
+	require.NoError(t, err)
+	require.NotEqual(t, root, changedRoot)
+}
+
+func TestValidatorSSZRoot(t *testing.T) {
+	t.Parallel()
+
+	val := &Validator{
+		ID:               7,
+		Address:          common.HexToAddress("0x96C42C56fdb78294F96B0cFa33c92bed7D75F96a"),
+		VotingPower:      100,
+		ProposerPriority: -5,
+	}
+
+	// A container of four basic fields is the merkle root of four chunks
+	chunk := func(b []byte) []byte {
+		c := make([]byte, 32)
+		copy(c, b)
+
+		return c
+	}
+	uint64Chunk := func(i uint64) []byte {
+		return chunk(binary.LittleEndian.AppendUint64(nil, i))
+	}
+	hash := func(a, b []byte) []byte {
+		h := sha256.Sum256(append(append([]byte{}, a...), b...))
+		return h[:]
+	}
+
+	expected := hash(
+		hash(uint64Chunk(val.ID), chunk(val.Address[:])),
+		hash(uint64Chunk(uint64(val.VotingPower)), uint64Chunk(uint64(val.ProposerPriority))),
+	)
+
+	root, err := val.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, expected, root[:])
+
+	blob, err := val.MarshalSSZ()
+	require.NoError(t, err)
+
+	decoded := new(Validator)
+	require.NoError(t, decoded.UnmarshalSSZ(blob))
+	require.Equal(t, val, decoded)
+}
+
+func TestSprintMetadataSSZ(t *testing.T) {
+	t.Parallel()
+
+	validators := GetValidators()
+
+	sprint := &SprintMetadata{
+		StartBlock:   1600,
+		EndBlock:     1615,
+		ValidatorSet: NewValidatorSet(validators[:2]),
+	}
+
+	blob, err := sprint.MarshalSSZ()
+	require.NoError(t, err)
+
+	decoded := new(SprintMetadata)
+	require.NoError(t, decoded.UnmarshalSSZ(blob))
+	require.Equal(t, sprint.StartBlock, decoded.StartBlock)
+	require.Equal(t, sprint.EndBlock, decoded.EndBlock)
+	require.Equal(t, sprint.ValidatorSet.Validators, decoded.ValidatorSet.Validators)
+
+	root, err := sprint.HashTreeRoot()
+	require.NoError(t, err)
+
+	decodedRoot, err := decoded.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, root, decodedRoot)
+
+	// Sprints without a validator set encode an empty one
+	_, err = (&SprintMetadata{StartBlock: 1}).MarshalSSZ()
+	require.NoError(t, err)
+}
+
+func TestValidatorSetSSZInvalid(t *testing.T) {
+	t.Parallel()
+
+	validators := GetValidators()
+	vals := NewValidatorSet(validators[:2])
+
+	blob, err := vals.MarshalSSZ()
+	require.NoError(t, err)
+
+	// Truncated validator
+	require.Error(t, new(ValidatorSet).UnmarshalSSZ(blob[:len(blob)-1]))
+
+	// Proposer outside the set
+	unknown := append([]byte{}, blob...)
+	copy(unknown[4:validatorSetSSZFixed], validators[3].Address[:])
+	require.ErrorIs(t, new(ValidatorSet).UnmarshalSSZ(unknown), errUnknownProposer)
+
+	// Duplicate validator
+	duplicate := append(append([]byte{}, blob...), blob[validatorSetSSZFixed:validatorSetSSZFixed+validatorSSZSize]...)
+	require.Error(t, new(ValidatorSet).UnmarshalSSZ(duplicate))
+
+	// Negative voting power can't be encoded
+	vals.Validators[0].VotingPower = -1
+	_, err = vals.MarshalSSZ()
+	require.Error(t, err)
+}
@@ -2,7 +2,6 @@ package valset
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"math/big"
 	"sort"
@@ -17,6 +16,7 @@ type Validator struct {
 	Address          common.Address `json:"signer"`
 	VotingPower      int64          `json:"power"`
 	ProposerPriority int64          `json:"accum"`
+	BLSPublicKey     []byte         `json:"blsPubKey,omitempty"` // Compressed BLS12-381 key, base64 encoded in heimdall spans and snapshots
 }
 
 // NewValidator creates new validator
@@ -117,27 +117,147 @@ func (v *Validator) MinimalVal() MinimalVal {
 	}
 }
 
-// ParseValidators returns validator set bytes
+// Versions of the layout of the validator set in the extra-data of a header.
+const (
+	// ValidatorBytesV1 is the original layout: the bare concatenation of the
+	// validator records, each the address followed by the voting power.
+	ValidatorBytesV1 byte = 1
+
+	// ValidatorBytesV2 is the layout prefixed by its version byte, with the BLS
+	// public key of the validator appended to each record, as registered in the
+	// heimdall span the validator set takes effect in.
+	ValidatorBytesV2 byte = 2
+)
+
+// BLSPublicKeyLength is the length of a compressed BLS12-381 public key.
+const BLSPublicKeyLength = 48
+
+// validatorBytesLength is the length of a validator record in the extra-data
+// of a header: the address followed by the voting power.
+const validatorBytesLength = common.AddressLength + 20
+
+// validatorBytesV2Length is the length of a v2 validator record: the v1 record
+// followed by the BLS public key.
+const validatorBytesV2Length = validatorBytesLength + BLSPublicKeyLength
+
+// recordLength returns the length of a validator record in the given layout.
+func recordLength(version byte) int {
+	if version == ValidatorBytesV2 {
+		return validatorBytesV2Length
+	}
+
+	return validatorBytesLength
+}
+
+// HeaderBytesV2 returns the v2 header record of the validator. A validator
+// without a BLS public key gets a zero one.
+func (v *Validator) HeaderBytesV2() []byte {
+	result := make([]byte, validatorBytesV2Length)
+	copy(result, v.HeaderBytes())
+	copy(result[validatorBytesLength:], v.BLSPublicKey)
+
+	return result
+}
+
+// VersionedHeaderBytes returns the header record of the validator in the given
+// layout.
+func (v *Validator) VersionedHeaderBytes(version byte) []byte {
+	if version == ValidatorBytesV2 {
+		return v.HeaderBytesV2()
+	}
+
+	return v.HeaderBytes()
+}
+
+// EncodeValidators returns the validator set bytes of the given validators in
+// the given layout, to be carried in the extra-data of a sprint end header.
+func EncodeValidators(validators []*Validator, version byte) []byte {
+	var result []byte
+
+	if version != ValidatorBytesV1 {
+		result = append(result, version)
+	}
+
+	for _, validator := range validators {
+		result = append(result, validator.VersionedHeaderBytes(version)...)
+	}
+
+	return result
+}
+
+// ValidatorBytesVersion returns the layout of the given validator set bytes.
+// Versioned layouts start with their version byte, which can't be mistaken for
+// the unversioned v1 layout: the versioned layouts are an odd number of bytes
+// long, while v1 records are 40 bytes each.
+func ValidatorBytesVersion(validatorsBytes []byte) byte {
+	if len(validatorsBytes)%2 == 0 {
+		return ValidatorBytesV1
+	}
+
+	return validatorsBytes[0]
+}
+
+// ParseValidators returns validator set bytes, in any of the known layouts
 func ParseValidators(validatorsBytes []byte) ([]*Validator, error) {
-	if len(validatorsBytes)%40 != 0 {
-		return nil, errors.New("Invalid validators bytes")
+	version := ValidatorBytesVersion(validatorsBytes)
+
+	switch version {
+	case ValidatorBytesV1:
+	case ValidatorBytesV2:
+		validatorsBytes = validatorsBytes[1:]
+	default:
+		return nil, &InvalidValidatorBytesError{Version: version, Length: len(validatorsBytes)}
 	}
 
-	result := make([]*Validator, len(validatorsBytes)/40)
+	length := recordLength(version)
+
+	if len(validatorsBytes)%length != 0 {
+		return nil, &InvalidValidatorBytesError{Version: version, Length: len(validatorsBytes)}
+	}
 
-	for i := 0; i < len(validatorsBytes); i += 40 {
+	result := make([]*Validator, len(validatorsBytes)/length)
+
+	for i := 0; i < len(validatorsBytes); i += length {
 		address := make([]byte, 20)
 		power := make([]byte, 20)
 
 		copy(address, validatorsBytes[i:i+20])
 		copy(power, validatorsBytes[i+20:i+40])
 
-		result[i/40] = NewValidator(common.BytesToAddress(address), big.NewInt(0).SetBytes(power).Int64())
+		validator := NewValidator(common.BytesToAddress(address), big.NewInt(0).SetBytes(power).Int64())
+
+		if version == ValidatorBytesV2 {
+			validator.BLSPublicKey = common.CopyBytes(validatorsBytes[i+validatorBytesLength : i+length])
+		}
+
+		result[i/length] = validator
 	}
 
 	return result, nil
 }
 
+// ParseValidatorsStrict parses validator set bytes like ParseValidators, but
+// also requires the validators to be sorted by strictly ascending address and
+// to have voting power, as a producer is expected to encode them.
+func ParseValidatorsStrict(validatorsBytes []byte) ([]*Validator, error) {
+	validators, err := ParseValidators(validatorsBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, validator := range validators {
+		if i > 0 && bytes.Compare(validators[i-1].Address.Bytes(), validator.Address.Bytes()) >= 0 {
+			return nil, &UnsortedValidatorsError{Index: i, Address: validator.Address}
+		}
+
+		if validator.VotingPower <= 0 {
+			return nil, &ZeroPowerValidatorError{Index: i, Address: validator.Address}
+		}
+	}
+
+	return validators, nil
+}
+
 // ---
 
 // MinimalVal is the minimal validator representation
@@ -0,0 +1,134 @@
+package valset
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fuzzValidators returns validators to seed the fuzzers with, in the layout of
+// the validator sets carried by sprint end headers.
+func fuzzValidators(n int, power int64) []*Validator {
+	validators := make([]*Validator, n)
+
+	for i := range validators {
+		validators[i] = NewValidator(common.BytesToAddress([]byte{byte(i + 1)}), power*int64(i+1))
+		validators[i].BLSPublicKey = bytes.Repeat([]byte{byte(i + 1)}, 48)
+	}
+
+	return validators
+}
+
+// addValidatorSeeds seeds a fuzzer with validator set bytes in all layouts,
+// well-formed and malformed.
+func addValidatorSeeds(f *testing.F, add func(validators []byte)) {
+	f.Helper()
+
+	for _, version := range []byte{ValidatorBytesV1, ValidatorBytesV2} {
+		add(EncodeValidators(fuzzValidators(1, 1), version))
+		add(EncodeValidators(fuzzValidators(4, 10000), version))
+		add(EncodeValidators(fuzzValidators(7, MaxTotalVotingPower/8), version))
+	}
+
+	add(nil)
+	add([]byte{ValidatorBytesV2})
+	add([]byte{0xff, 0x00, 0x01})
+	add(bytes.Repeat([]byte{0xff}, 40))
+}
+
+// FuzzParseValidators checks that parsing validator set bytes never panics,
+// that the strict parser only accepts what the lenient one does, and that the
+// validators it accepts encode back to the same bytes.
+func FuzzParseValidators(f *testing.F) {
+	addValidatorSeeds(f, func(validators []byte) { f.Add(validators) })
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		validators, err := ParseValidators(data)
+
+		strict, strictErr := ParseValidatorsStrict(data)
+		if strictErr != nil {
+			return
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, validators, strict)
+
+		version := ValidatorBytesVersion(data)
+
+		encoded := EncodeValidators(strict, version)
+		if len(strict) == 0 && version == ValidatorBytesV1 {
+			require.Empty(t, encoded)
+			return
+		}
+
+		reparsed, err := ParseValidatorsStrict(encoded)
+		require.NoError(t, err)
+		require.Equal(t, strict, reparsed)
+	})
+}
+
+// FuzzValidatorSetUpdate applies adversarial validator set updates, as parsed
+// from the extra-data of sprint end headers, to a validator set. An update
+// must either be rejected leaving the set untouched, or result in a valid set.
+func FuzzValidatorSetUpdate(f *testing.F) {
+	addValidatorSeeds(f, func(validators []byte) {
+		f.Add(EncodeValidators(fuzzValidators(4, 10000), ValidatorBytesV1), validators)
+	})
+
+	f.Fuzz(func(t *testing.T, current []byte, changes []byte) {
+		validators, err := ParseValidatorsStrict(current)
+		if err != nil || len(validators) == 0 {
+			return
+		}
+
+		total := int64(0)
+		for _, validator := range validators {
+			total = safeAddClip(total, validator.VotingPower)
+		}
+
+		if total > MaxTotalVotingPower {
+			return
+		}
+
+		updates, err := ParseValidators(changes)
+		if err != nil {
+			return
+		}
+
+		set := NewValidatorSet(validators)
+		before := set.Copy()
+
+		if err := set.UpdateWithChangeSet(updates); err != nil {
+			require.Equal(t, before.Validators, set.Validators)
+			require.Equal(t, before.TotalVotingPower(), set.TotalVotingPower())
+
+			return
+		}
+
+		require.NotEmpty(t, set.Validators)
+
+		sum := int64(0)
+
+		for i, validator := range set.Validators {
+			require.Positive(t, validator.VotingPower)
+
+			if i > 0 {
+				require.Negative(t, bytes.Compare(set.Validators[i-1].Address.Bytes(), validator.Address.Bytes()))
+			}
+
+			index, _ := set.GetByAddress(validator.Address)
+			require.Equal(t, i, index)
+
+			sum += validator.VotingPower
+		}
+
+		require.Equal(t, sum, set.TotalVotingPower())
+		require.LessOrEqual(t, sum, MaxTotalVotingPower)
+
+		set.IncrementProposerPriority(1)
+		require.True(t, set.HasAddress(set.GetProposer().Address))
+	})
+}
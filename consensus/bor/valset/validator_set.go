@@ -37,7 +37,9 @@ const (
 // On the other hand, the .ProposerPriority of each validator and
 // the designated .GetProposer() of a set changes every round,
 // upon calling .IncrementProposerPriority().
-// NOTE: Not goroutine-safe.
+// NOTE: Not goroutine-safe to modify. Sets shared between goroutines, e.g. the
+// ones of cached snapshots and spans, must be treated as immutable: the read
+// accessors never modify the set, and changes are applied to a Copy.
 // NOTE: All get/set to validators should copy the value for safety.
 type ValidatorSet struct {
 	// NOTE: persisted via reflect, must be exported.
@@ -261,20 +263,19 @@ func validatorListCopy(valsList []*Validator) []*Validator {
 	return valsCopy
 }
 
-// Copy each validator into a new ValidatorSet.
+// Copy each validator into a new ValidatorSet. The address index is shared
+// with the copy, as it's only ever replaced and never modified in place.
 func (vals *ValidatorSet) Copy() *ValidatorSet {
-	valCopy := validatorListCopy(vals.Validators)
-	validatorsMap := make(map[common.Address]int, len(vals.Validators))
-
-	for i, val := range valCopy {
-		validatorsMap[val.Address] = i
+	var proposer *Validator
+	if vals.Proposer != nil {
+		proposer = vals.Proposer.Copy()
 	}
 
 	return &ValidatorSet{
 		Validators:       validatorListCopy(vals.Validators),
-		Proposer:         vals.Proposer,
+		Proposer:         proposer,
 		totalVotingPower: vals.totalVotingPower,
-		validatorsMap:    validatorsMap,
+		validatorsMap:    vals.validatorsMap,
 	}
 }
 
@@ -317,44 +318,96 @@ func (vals *ValidatorSet) Size() int {
 
 // Force recalculation of the set's total voting power.
 func (vals *ValidatorSet) UpdateTotalVotingPower() error {
+	sum, err := vals.sumVotingPower()
+	if err != nil {
+		return err
+	}
+
+	vals.totalVotingPower = sum
+
+	return nil
+}
+
+// sumVotingPower computes the total voting power of the validators.
+func (vals *ValidatorSet) sumVotingPower() (int64, error) {
 	sum := int64(0)
 	for _, val := range vals.Validators {
 		// mind overflow
 		sum = safeAddClip(sum, val.VotingPower)
 		if sum > MaxTotalVotingPower {
-			return &TotalVotingPowerExceededError{sum, vals.Validators}
+			return 0, &TotalVotingPowerExceededError{sum, vals.Validators}
 		}
 	}
 
-	vals.totalVotingPower = sum
-
-	return nil
+	return sum, nil
 }
 
 // TotalVotingPower returns the sum of the voting powers of all validators.
-// It recomputes the total voting power if required.
+// It recomputes the total voting power if it isn't cached, without caching
+// it, so that reading a shared set never modifies it.
 func (vals *ValidatorSet) TotalVotingPower() int64 {
-	if vals.totalVotingPower == 0 {
-		log.Debug("invoking updateTotalVotingPower before returning it")
+	if vals.totalVotingPower != 0 {
+		return vals.totalVotingPower
+	}
+
+	sum, err := vals.sumVotingPower()
+	if err != nil {
+		// Can/should we do better?
+		panic(err)
+	}
+
+	return sum
+}
 
-		if err := vals.UpdateTotalVotingPower(); err != nil {
-			// Can/should we do better?
-			panic(err)
+// QuorumPower returns the voting power a quorum of the set needs, which is more
+// than two thirds of the total voting power.
+func (vals *ValidatorSet) QuorumPower() int64 {
+	total := vals.TotalVotingPower()
+
+	// total*2/3 + 1, without overflowing
+	return total/3*2 + total%3*2/3 + 1
+}
+
+// VotingPowerOf returns the sum of the voting powers of the validators with the
+// given addresses. Addresses outside of the set and repeated ones are ignored.
+func (vals *ValidatorSet) VotingPowerOf(addresses []common.Address) int64 {
+	wanted := make(map[common.Address]struct{}, len(addresses))
+	for _, address := range addresses {
+		wanted[address] = struct{}{}
+	}
+
+	sum := int64(0)
+
+	for _, val := range vals.Validators {
+		if _, ok := wanted[val.Address]; ok {
+			// mind overflow
+			sum = safeAddClip(sum, val.VotingPower)
 		}
 	}
 
-	return vals.totalVotingPower
+	return sum
+}
+
+// HasTwoThirdsMajority returns whether the validators with the given addresses
+// hold more than two thirds of the voting power of the set.
+func (vals *ValidatorSet) HasTwoThirdsMajority(addresses []common.Address) bool {
+	if vals.IsNilOrEmpty() {
+		return false
+	}
+
+	return vals.VotingPowerOf(addresses) >= vals.QuorumPower()
 }
 
 // GetProposer returns the current proposer. If the validator set is empty, nil
-// is returned.
+// is returned. A set without a designated proposer (e.g. as decoded from a
+// span) has it derived from the priorities on every call.
 func (vals *ValidatorSet) GetProposer() (proposer *Validator) {
 	if len(vals.Validators) == 0 {
 		return nil
 	}
 
 	if vals.Proposer == nil {
-		vals.Proposer = vals.findProposer()
+		return vals.findProposer().Copy()
 	}
 
 	return vals.Proposer.Copy()
@@ -663,6 +716,8 @@ func (vals *ValidatorSet) updateValidators(updates []*Validator, deletes []*Vali
 	vals.UpdateValidatorMap()
 }
 
+// UpdateValidatorMap rebuilds the address index of the validators, which has to
+// be done whenever the validator list is replaced, e.g. after decoding a set.
 func (vals *ValidatorSet) UpdateValidatorMap() {
 	vals.validatorsMap = make(map[common.Address]int, len(vals.Validators))
 
@@ -1,6 +1,9 @@
 package valset
 
 import (
+	"bytes"
+	"math/big"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -164,6 +167,89 @@ func TestGetValidatorByAddressAndIndex(t *testing.T) {
 	require.Equal(t, addr, common.Address{})
 }
 
+func TestCopyAddressIndex(t *testing.T) {
+	t.Parallel()
+
+	vals := GetValidators()
+	valSet := NewValidatorSet(vals[:3])
+
+	// Updating a copy re-indexes it without disturbing the index of the original
+	valCopy := valSet.Copy()
+	require.NoError(t, valCopy.UpdateWithChangeSet([]*Validator{vals[3], NewValidator(vals[0].Address, 0)}))
+
+	require.True(t, valSet.HasAddress(vals[0].Address))
+	require.False(t, valSet.HasAddress(vals[3].Address))
+	require.False(t, valCopy.HasAddress(vals[0].Address))
+
+	for i, val := range valCopy.Validators {
+		idx, _ := valCopy.GetByAddress(val.Address)
+		require.Equal(t, i, idx)
+	}
+
+	for i, val := range valSet.Validators {
+		idx, _ := valSet.GetByAddress(val.Address)
+		require.Equal(t, i, idx)
+	}
+}
+
+func TestConcurrentReads(t *testing.T) {
+	t.Parallel()
+
+	// A set as decoded from a span, without a cached proposer or total power
+	validators := GetValidators()
+
+	vals := &ValidatorSet{Validators: validators[:]}
+	vals.UpdateValidatorMap()
+
+	proposer := vals.findProposer().Address
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if vals.GetProposer().Address != proposer || vals.TotalVotingPower() != 1000 {
+					t.Error("inconsistent read of shared validator set")
+					return
+				}
+
+				changed := vals.Copy()
+				changed.IncrementProposerPriority(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// Reading never modifies the shared set
+	require.Nil(t, vals.Proposer)
+	require.Zero(t, vals.totalVotingPower)
+}
+
+func BenchmarkGetByAddress(b *testing.B) {
+	validators := make([]*Validator, 128)
+	for i := range validators {
+		validators[i] = NewValidator(common.BigToAddress(big.NewInt(int64(i+1))), 100)
+	}
+
+	valSet := NewValidatorSet(validators)
+	last := validators[len(validators)-1].Address
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if !valSet.HasAddress(last) {
+			b.Fatal("validator not found")
+		}
+
+		valSet.GetByAddress(last)
+	}
+}
+
 func TestUpdateWithChangeSet(t *testing.T) {
 	t.Parallel()
 
@@ -313,3 +399,140 @@ func TestCheckEmptyId(t *testing.T) {
 		})
 	}
 }
+
+func TestParseValidatorsStrict(t *testing.T) {
+	t.Parallel()
+
+	encode := func(validators ...*Validator) []byte {
+		var data []byte
+		for _, validator := range validators {
+			data = append(data, validator.HeaderBytes()...)
+		}
+
+		return data
+	}
+
+	var (
+		low  = NewValidator(common.HexToAddress("0x01"), 10)
+		high = NewValidator(common.HexToAddress("0x02"), 20)
+	)
+
+	validators, err := ParseValidatorsStrict(encode(low, high))
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+
+	var bytesErr *InvalidValidatorBytesError
+
+	_, err = ParseValidatorsStrict(encode(low, high)[1:])
+	require.ErrorAs(t, err, &bytesErr)
+
+	var sortErr *UnsortedValidatorsError
+
+	_, err = ParseValidatorsStrict(encode(high, low))
+	require.ErrorAs(t, err, &sortErr)
+	require.Equal(t, 1, sortErr.Index)
+
+	_, err = ParseValidatorsStrict(encode(low, low))
+	require.ErrorAs(t, err, &sortErr)
+
+	var powerErr *ZeroPowerValidatorError
+
+	_, err = ParseValidatorsStrict(encode(low, NewValidator(common.HexToAddress("0x03"), 0)))
+	require.ErrorAs(t, err, &powerErr)
+	require.Equal(t, common.HexToAddress("0x03"), powerErr.Address)
+
+	// The lenient parser accepts what the producer wouldn't encode
+	validators, err = ParseValidators(encode(high, low))
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+}
+
+func TestParseValidatorsVersioned(t *testing.T) {
+	t.Parallel()
+
+	blsKey := bytes.Repeat([]byte{0xbb}, BLSPublicKeyLength)
+
+	low := NewValidator(common.HexToAddress("0x01"), 10)
+	low.BLSPublicKey = blsKey
+
+	high := NewValidator(common.HexToAddress("0x02"), 20)
+
+	// The v1 layout is the unversioned legacy one, without the BLS keys
+	v1 := EncodeValidators([]*Validator{low, high}, ValidatorBytesV1)
+	require.Equal(t, append(low.HeaderBytes(), high.HeaderBytes()...), v1)
+	require.Equal(t, ValidatorBytesV1, ValidatorBytesVersion(v1))
+
+	validators, err := ParseValidators(v1)
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+	require.Nil(t, validators[0].BLSPublicKey)
+
+	// The v2 layout is prefixed by its version and carries the BLS keys
+	v2 := EncodeValidators([]*Validator{low, high}, ValidatorBytesV2)
+	require.Len(t, v2, 1+2*validatorBytesV2Length)
+	require.Equal(t, ValidatorBytesV2, ValidatorBytesVersion(v2))
+
+	validators, err = ParseValidatorsStrict(v2)
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+	require.Equal(t, low.Address, validators[0].Address)
+	require.Equal(t, blsKey, validators[0].BLSPublicKey)
+	require.Equal(t, high.VotingPower, validators[1].VotingPower)
+	require.Equal(t, make([]byte, BLSPublicKeyLength), validators[1].BLSPublicKey)
+
+	var bytesErr *InvalidValidatorBytesError
+
+	_, err = ParseValidators(v2[:len(v2)-2])
+	require.ErrorAs(t, err, &bytesErr)
+	require.Equal(t, ValidatorBytesV2, bytesErr.Version)
+
+	unknown := append([]byte{0x03}, v2[1:]...)
+	_, err = ParseValidators(unknown)
+	require.ErrorAs(t, err, &bytesErr)
+	require.Equal(t, byte(0x03), bytesErr.Version)
+}
+
+func TestValidatorSet_TwoThirdsMajority(t *testing.T) {
+	t.Parallel()
+
+	var (
+		a = common.HexToAddress("0x01")
+		b = common.HexToAddress("0x02")
+		c = common.HexToAddress("0x03")
+		d = common.HexToAddress("0x04")
+	)
+
+	// Stake weighted: a and b hold 70 of 100, while c and d are a head count majority
+	valSet := NewValidatorSet([]*Validator{
+		NewValidator(a, 40),
+		NewValidator(b, 30),
+		NewValidator(c, 20),
+		NewValidator(d, 10),
+	})
+
+	require.Equal(t, int64(67), valSet.QuorumPower())
+	require.Equal(t, int64(70), valSet.VotingPowerOf([]common.Address{a, b, b, common.HexToAddress("0x05")}))
+
+	require.True(t, valSet.HasTwoThirdsMajority([]common.Address{a, b}))
+	require.False(t, valSet.HasTwoThirdsMajority([]common.Address{b, c, d}))
+	require.False(t, valSet.HasTwoThirdsMajority([]common.Address{a, a, a}))
+	require.True(t, valSet.HasTwoThirdsMajority([]common.Address{a, b, c, d}))
+
+	// Exactly two thirds isn't a majority
+	valSet = NewValidatorSet([]*Validator{NewValidator(a, 1), NewValidator(b, 1), NewValidator(c, 1)})
+
+	require.Equal(t, int64(3), valSet.QuorumPower())
+	require.False(t, valSet.HasTwoThirdsMajority([]common.Address{a, b}))
+	require.True(t, valSet.HasTwoThirdsMajority([]common.Address{a, b, c}))
+
+	// Large voting powers don't overflow
+	valSet = NewValidatorSet([]*Validator{NewValidator(a, MaxTotalVotingPower)})
+
+	quorum := new(big.Int).Mul(big.NewInt(MaxTotalVotingPower), big.NewInt(2))
+	quorum.Div(quorum, big.NewInt(3)).Add(quorum, big.NewInt(1))
+
+	require.Equal(t, quorum.Int64(), valSet.QuorumPower())
+	require.True(t, valSet.HasTwoThirdsMajority([]common.Address{a}))
+
+	require.False(t, NewValidatorSet(nil).HasTwoThirdsMajority([]common.Address{a}))
+}
@@ -0,0 +1,128 @@
+package valset
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vectorsDir holds the proposer priority test vectors shared with Heimdall, see
+// testdata/vectors/README.md.
+var vectorsDir = filepath.Join("testdata", "vectors")
+
+// vectorStep is a single operation on the validator set of a test vector: either
+// incrementing the proposer priorities, or applying a change set.
+type vectorStep struct {
+	Increment int           `json:"increment,omitempty"` // Number of times to increment the priorities
+	Update    []*Validator  `json:"update,omitempty"`    // Change set to apply, a zero power removes the validator
+	Error     bool          `json:"error,omitempty"`     // Whether the change set is expected to be rejected
+	Expected  *ValidatorSet `json:"expected,omitempty"`  // Validator set expected after the step
+}
+
+// vector is a validator set along with the operations replayed on it and the
+// expected outcome of each.
+type vector struct {
+	Description  string        `json:"description"`
+	Source       string        `json:"source"`
+	ValidatorSet *ValidatorSet `json:"validatorSet"`
+	Steps        []vectorStep  `json:"steps"`
+}
+
+// loadVectors loads all the test vectors in the given directory.
+func loadVectors(t *testing.T, dir string) map[string]*vector {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	vectors := make(map[string]*vector, len(files))
+
+	for _, file := range files {
+		blob, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		var v vector
+		require.NoError(t, json.Unmarshal(blob, &v), file)
+		require.NotNil(t, v.ValidatorSet, file)
+		require.NotEmpty(t, v.Steps, file)
+
+		vectors[filepath.Base(file)] = &v
+	}
+
+	return vectors
+}
+
+// newVectorSet rebuilds a decoded validator set, whose indexes and total power
+// aren't part of the encoding.
+func newVectorSet(t *testing.T, vals *ValidatorSet) *ValidatorSet {
+	t.Helper()
+
+	vals = vals.Copy()
+	vals.UpdateValidatorMap()
+	require.NoError(t, vals.UpdateTotalVotingPower())
+
+	return vals
+}
+
+// requireVectorSet checks that a validator set matches the expected one on the
+// addresses, powers and priorities of the validators, and on the proposer if
+// one is expected.
+func requireVectorSet(t *testing.T, step int, expected, actual *ValidatorSet) {
+	t.Helper()
+
+	require.Len(t, actual.Validators, len(expected.Validators), "step %d", step)
+
+	for i, want := range expected.Validators {
+		have := actual.Validators[i]
+
+		require.Equal(t, want.Address, have.Address, "step %d, validator %d", step, i)
+		require.Equal(t, want.VotingPower, have.VotingPower, "step %d, validator %d", step, i)
+		require.Equal(t, want.ProposerPriority, have.ProposerPriority, "step %d, validator %d", step, i)
+	}
+
+	if expected.Proposer != nil {
+		proposer := actual.GetProposer()
+		require.NotNil(t, proposer, "step %d", step)
+		require.Equal(t, expected.Proposer.Address, proposer.Address, "step %d, proposer", step)
+		require.Equal(t, expected.Proposer.ProposerPriority, proposer.ProposerPriority, "step %d, proposer", step)
+	}
+}
+
+// Tests that the proposer priorities evolve exactly like in Heimdall, which the
+// validator set must agree with on every block.
+func TestProposerPriorityVectors(t *testing.T) {
+	t.Parallel()
+
+	for name, v := range loadVectors(t, vectorsDir) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			vals := newVectorSet(t, v.ValidatorSet)
+
+			for i, step := range v.Steps {
+				switch {
+				case step.Increment > 0:
+					vals.IncrementProposerPriority(step.Increment)
+
+				case step.Error:
+					before := vals.Copy()
+
+					require.Error(t, vals.UpdateWithChangeSet(step.Update), "step %d", i)
+					requireVectorSet(t, i, before, vals)
+
+					continue
+
+				default:
+					require.NoError(t, vals.UpdateWithChangeSet(step.Update), "step %d", i)
+				}
+
+				require.NotNil(t, step.Expected, "step %d", i)
+				requireVectorSet(t, i, step.Expected, vals)
+			}
+		})
+	}
+}
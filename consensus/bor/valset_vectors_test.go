@@ -0,0 +1,126 @@
+package bor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+)
+
+// knownVectorDivergences lists the updates of the proposer priority vectors that
+// getUpdatedValidatorSet doesn't apply like Heimdall. It writes the new powers
+// into the set before applying the change set, so the change set is verified and
+// the priority of joining validators computed against the total power prior to
+// the update, and the power changes are kept even if the update is rejected.
+// Fixing either is a hard fork, this only keeps the divergences from growing.
+var knownVectorDivergences = map[string]map[int]string{
+	"join_leave.json": {
+		1: "joining validator priority computed from the power before the update",
+	},
+	"overflow.json": {
+		6: "update rejected as the total power before the update is at the cap",
+	},
+}
+
+// Tests that applying the validator sets of sprint end headers agrees with the
+// proposer priority vectors shared with Heimdall, see
+// valset/testdata/vectors/README.md. The headers carry the complete validator
+// set rather than the change set, so every update is replayed with the set
+// expected after it.
+func TestGetUpdatedValidatorSetVectors(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob(filepath.Join("valset", "testdata", "vectors", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			t.Parallel()
+
+			blob, err := os.ReadFile(file)
+			require.NoError(t, err)
+
+			var vector struct {
+				ValidatorSet *valset.ValidatorSet `json:"validatorSet"`
+				Steps        []struct {
+					Increment int                  `json:"increment"`
+					Error     bool                 `json:"error"`
+					Expected  *valset.ValidatorSet `json:"expected"`
+				} `json:"steps"`
+			}
+
+			require.NoError(t, json.Unmarshal(blob, &vector))
+
+			vals := vector.ValidatorSet.Copy()
+			vals.UpdateValidatorMap()
+			require.NoError(t, vals.UpdateTotalVotingPower())
+
+			for i, step := range vector.Steps {
+				switch {
+				case step.Error:
+					// Rejected change sets never make it into a header
+					continue
+
+				case step.Increment > 0:
+					vals.IncrementProposerPriority(step.Increment)
+
+				default:
+					header := make([]*valset.Validator, len(step.Expected.Validators))
+					for j, validator := range step.Expected.Validators {
+						header[j] = valset.NewValidator(validator.Address, validator.VotingPower)
+					}
+
+					vals = getUpdatedValidatorSet(vals.Copy(), header)
+
+					if reason, ok := knownVectorDivergences[filepath.Base(file)][i]; ok {
+						require.False(t, matchesVectorSet(vals, step.Expected), "step %d no longer diverges (%s)", i, reason)
+
+						// Carry on from the set Heimdall arrives at
+						vals = step.Expected.Copy()
+						vals.UpdateValidatorMap()
+						require.NoError(t, vals.UpdateTotalVotingPower())
+
+						continue
+					}
+				}
+
+				require.Len(t, vals.Validators, len(step.Expected.Validators), "step %d", i)
+
+				for j, want := range step.Expected.Validators {
+					have := vals.Validators[j]
+
+					require.Equal(t, want.Address, have.Address, "step %d, validator %d", i, j)
+					require.Equal(t, want.VotingPower, have.VotingPower, "step %d, validator %d", i, j)
+					require.Equal(t, want.ProposerPriority, have.ProposerPriority, "step %d, validator %d", i, j)
+				}
+
+				if step.Expected.Proposer != nil {
+					require.Equal(t, step.Expected.Proposer.Address, vals.GetProposer().Address, "step %d", i)
+				}
+			}
+		})
+	}
+}
+
+// matchesVectorSet returns whether the validators of a set have the addresses,
+// powers and priorities expected by a proposer priority vector.
+func matchesVectorSet(vals, expected *valset.ValidatorSet) bool {
+	if len(vals.Validators) != len(expected.Validators) {
+		return false
+	}
+
+	for i, want := range expected.Validators {
+		have := vals.Validators[i]
+
+		if have.Address != want.Address || have.VotingPower != want.VotingPower || have.ProposerPriority != want.ProposerPriority {
+			return false
+		}
+	}
+
+	return true
+}
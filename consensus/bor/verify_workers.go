@@ -0,0 +1,55 @@
+package bor
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// SetVerifyWorkers sets the number of workers recovering the signers of the
+// header batches being verified, 0 for one per CPU.
+func (c *Bor) SetVerifyWorkers(workers int) {
+	c.verifyWorkers = workers
+}
+
+// recoverSigners recovers the signers of the headers on a pool of workers into
+// the signature cache, so that the sequential verification of the batch finds
+// them there instead of recovering them one by one. Recovery errors are left
+// for the verification to report.
+func (c *Bor) recoverSigners(headers []*types.Header, abort <-chan struct{}) {
+	workers := c.verifyWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > len(headers) {
+		workers = len(headers)
+	}
+
+	// A single worker would only race the verification for the same headers
+	if workers <= 1 {
+		return
+	}
+
+	var next atomic.Int64
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-abort:
+					return
+				default:
+				}
+
+				index := int(next.Add(1) - 1)
+				if index >= len(headers) {
+					return
+				}
+
+				_, _ = ecrecover(headers[index], c.signatures, c.config)
+			}
+		}()
+	}
+}
@@ -0,0 +1,53 @@
+package bor
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+func TestRecoverSigners(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+
+	config := &params.BorConfig{Sprint: map[string]uint64{"0": 16}}
+
+	headers := make([]*types.Header, 64)
+	for i := range headers {
+		header := &types.Header{
+			Number:     big.NewInt(int64(i + 1)),
+			Difficulty: big.NewInt(1),
+			Extra:      make([]byte, types.ExtraVanityLength+types.ExtraSealLength),
+		}
+
+		sig, err := crypto.Sign(SealHash(header, config).Bytes(), key)
+		require.NoError(t, err)
+
+		copy(header.Extra[types.ExtraVanityLength:], sig)
+		headers[i] = header
+	}
+
+	signatures, _ := lru.NewARC(inmemorySignatures)
+
+	c := &Bor{config: config, signatures: signatures, verifyWorkers: 4}
+	c.recoverSigners(headers, make(chan struct{}))
+
+	require.Eventually(t, func() bool { return signatures.Len() == len(headers) }, time.Second, time.Millisecond)
+
+	for _, header := range headers {
+		cached, ok := signatures.Get(header.Hash())
+		require.True(t, ok)
+		require.Equal(t, signer, cached.(common.Address))
+	}
+}
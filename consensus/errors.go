@@ -42,3 +42,23 @@ var (
 	// ErrUnexpectedWithdrawals is returned if a pre-Shanghai block has withdrawals.
 	ErrUnexpectedWithdrawals = errors.New("unexpected withdrawals")
 )
+
+// BlockError is implemented by the errors of consensus engines rejecting a
+// block which carry the context of the block.
+type BlockError interface {
+	error
+
+	// LogContext returns the context of the rejected block as key-value pairs.
+	LogContext() []interface{}
+}
+
+// ErrorContext returns the context of the rejected block carried by the error
+// or any error it wraps, nil if none.
+func ErrorContext(err error) []interface{} {
+	var blockErr BlockError
+	if errors.As(err, &blockErr) {
+		return blockErr.LogContext()
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// borService serves the bor methods behind the console helpers, recording the
+// blocks requested.
+type borService struct {
+	requested []rpc.BlockNumber
+}
+
+const testBorSigner = "0x0000000000000000000000000000000000000001"
+
+var testBorValidator = map[string]interface{}{"ID": 1, "signer": testBorSigner, "power": 10, "accum": 0}
+
+func (s *borService) GetSnapshot(number *rpc.BlockNumber) map[string]interface{} {
+	s.requested = append(s.requested, *number)
+
+	return map[string]interface{}{
+		"number":       100,
+		"hash":         "0x01",
+		"validatorSet": map[string]interface{}{"validators": []interface{}{testBorValidator}, "proposer": testBorValidator},
+		"recents":      map[string]interface{}{"99": testBorSigner},
+	}
+}
+
+func (s *borService) GetSnapshotProposerSequence(blockNrOrHash *rpc.BlockNumberOrHash) map[string]interface{} {
+	number, _ := blockNrOrHash.Number()
+	s.requested = append(s.requested, number)
+
+	return map[string]interface{}{
+		"Signers": []interface{}{map[string]interface{}{"Signer": testBorSigner, "Difficulty": 1}},
+		"Diff":    1,
+		"Author":  testBorSigner,
+	}
+}
+
+func (s *borService) GetSprintByBlock(number *rpc.BlockNumber) map[string]interface{} {
+	s.requested = append(s.requested, *number)
+
+	return map[string]interface{}{
+		"number":         6,
+		"length":         16,
+		"startBlock":     96,
+		"endBlock":       111,
+		"spanId":         1,
+		"spanStartBlock": 0,
+		"spanEndBlock":   255,
+		"producers":      []interface{}{testBorValidator},
+	}
+}
+
+// Tests that the bor console helpers request the blocks given, or the head by
+// default, and render their tables.
+func TestBorHelpers(t *testing.T) {
+	t.Parallel()
+
+	service := new(borService)
+
+	server := rpc.NewServer("", 0, 0)
+	defer server.Stop()
+
+	require.NoError(t, server.RegisterName("bor", service))
+
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	printer := new(bytes.Buffer)
+
+	console, err := New(Config{DataDir: t.TempDir(), DocRoot: "testdata", Client: client, Printer: printer})
+	require.NoError(t, err)
+
+	defer console.Stop(false)
+
+	helpers := map[string]string{
+		"printValidators":       "Validators at block 100",
+		"printSnapshot":         "RECENT BLOCK",
+		"printProposerSequence": "Block author",
+		"printSprint":           "Sprint 6: blocks 96-111",
+	}
+
+	for helper, want := range helpers {
+		for call, number := range map[string]rpc.BlockNumber{"bor." + helper + "()": rpc.LatestBlockNumber, "bor." + helper + "(100)": 100} {
+			printer.Reset()
+			service.requested = nil
+
+			console.Evaluate(call)
+			require.Contains(t, printer.String(), want, call)
+			require.Contains(t, printer.String(), testBorSigner, call)
+			require.Equal(t, []rpc.BlockNumber{number}, service.requested, call)
+		}
+	}
+}
@@ -33,11 +33,14 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/common/mclock"
 	"github.com/ethereum/go-ethereum/common/prque"
+	blocktracing "github.com/ethereum/go-ethereum/common/tracing"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -48,6 +51,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/downloader/whitelist"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/internal/syncx"
 	"github.com/ethereum/go-ethereum/internal/version"
 	"github.com/ethereum/go-ethereum/log"
@@ -101,6 +105,8 @@ var (
 	blockReorgAddMeter  = metrics.NewRegisteredMeter("chain/reorg/add", nil)
 	blockReorgDropMeter = metrics.NewRegisteredMeter("chain/reorg/drop", nil)
 
+	blockReorgRefusedMeter = metrics.NewRegisteredMeter("chain/reorg/refused", nil)
+
 	blockPrefetchExecuteTimer   = metrics.NewRegisteredTimer("chain/prefetch/executes", nil)
 	blockPrefetchInterruptMeter = metrics.NewRegisteredMeter("chain/prefetch/interrupts", nil)
 
@@ -236,6 +242,9 @@ type BlockChain struct {
 	gcproc        time.Duration                    // Accumulates canonical block processing for trie dumping
 	lastWrite     uint64                           // Last block when the state was flushed
 	flushInterval atomic.Int64                     // Time interval (processing time) after which to flush a state
+	maintenance   atomic.Pointer[MaintenanceGate]  // Gate deferring trie flushes while the local node is about to produce
+	maxReorgDepth atomic.Uint64                    // Number of blocks beyond which reorgs are refused (0 = no limit)
+	deepReorgOK   atomic.Uint64                    // Depth of a deeper reorg allowed once by the operator
 	triedb        *triedb.Database                 // The database handler for maintaining trie nodes.
 	stateCache    state.Database                   // State database to reuse between imports (contains state cache)
 	txIndexer     *txIndexer                       // Transaction indexer, might be nil if not enabled
@@ -287,10 +296,12 @@ type BlockChain struct {
 	logger                       *tracing.Hooks
 
 	// Bor related changes
-	borReceiptsCache *lru.Cache[common.Hash, *types.Receipt] // Cache for the most recent bor receipt receipts per block
-	stateSyncData    []*types.StateSyncData                  // State sync data
-	stateSyncFeed    event.Feed                              // State sync feed
-	chain2HeadFeed   event.Feed                              // Reorg/NewHead/Fork data feed
+	borReceiptsCache    *lru.Cache[common.Hash, *types.Receipt] // Cache for the most recent bor receipt receipts per block
+	stateSyncData       []*types.StateSyncData                  // State sync data
+	stateSyncFeed       event.Feed                              // State sync feed
+	stateSyncCommitFeed event.Feed                              // State syncs committed on the canonical chain
+	chain2HeadFeed      event.Feed                              // Reorg/NewHead/Fork data feed
+	blockStatsFeed      event.Feed                              // Execution stats of imported blocks
 }
 
 // NewBlockChain returns a fully initialised block chain using information
@@ -1136,6 +1147,7 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 			rawdb.DeleteReceipts(db, hash, num)
 			rawdb.DeleteBorReceipt(db, hash, num)
 			rawdb.DeleteBorTxLookupEntry(db, hash, num)
+			rawdb.DeleteBorStateSyncEvents(db, hash, num)
 		}
 		// Todo(rjl493456442) txlookup, bloombits, etc
 	}
@@ -1314,6 +1326,13 @@ func (bc *BlockChain) writeHeadBlock(block *types.Block) {
 	rawdb.WriteHeadFastBlockHash(batch, block.Hash())
 	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
 	rawdb.WriteTxLookupEntriesByBlock(batch, block)
+	stateSyncs := rawdb.ReadBorStateSyncEvents(bc.db, block.Hash(), block.NumberU64())
+	rawdb.WriteBorStateSyncLookupEntries(batch, block.NumberU64(), stateSyncs)
+
+	if len(stateSyncs) > 0 {
+		rawdb.WriteBorLastStateSyncID(batch, stateSyncs[len(stateSyncs)-1].ID)
+	}
+
 	rawdb.WriteHeadBlockHash(batch, block.Hash())
 
 	// Flush the whole batch into the disk, exit the node if failed
@@ -1854,6 +1873,12 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 		}
 	}
 
+	// Index the state-sync events committed in this block, so that they can be
+	// queried by block or by state id without scanning the bor receipts.
+	if bc.chainConfig.Bor != nil && bc.chainConfig.Bor.Sprint != nil && bc.chainConfig.Bor.IsSprintStart(block.NumberU64()) {
+		rawdb.WriteBorStateSyncEvents(blockBatch, block.Hash(), block.NumberU64(), statedb.BorStateSyncData)
+	}
+
 	rawdb.WritePreimages(blockBatch, statedb.Preimages())
 
 	if err := blockBatch.Write(); err != nil {
@@ -1894,8 +1919,12 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 	// Find the next state trie we need to commit
 	chosen := current - state.TriesInMemory
 	flushInterval := time.Duration(bc.flushInterval.Load())
-	// If we exceeded time allowance, flush an entire trie to disk
-	if bc.gcproc > flushInterval {
+	// If we exceeded time allowance, flush an entire trie to disk. The flush is
+	// deferred while the local node is about to produce a block, up to twice the
+	// allowance.
+	if bc.gcproc > flushInterval && bc.gcproc < 2*flushInterval && !bc.maintenanceWindow(block.Header()) {
+		log.Debug("Trie commit deferred to a maintenance window", "number", chosen, "time", bc.gcproc)
+	} else if bc.gcproc > flushInterval {
 		// If the header is missing (canonical chain behind), we're reorging a low
 		// diff sidechain. Suspend committing until this operation is completed.
 		header := bc.GetHeaderByNumber(chosen)
@@ -1981,6 +2010,10 @@ func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types
 			bc.logsFeed.Send(stateSyncLogs)
 		}
 
+		if len(state.BorStateSyncData) > 0 {
+			bc.stateSyncCommitFeed.Send(StateSyncCommitEvent{Number: block.NumberU64(), Hash: block.Hash(), Events: state.BorStateSyncData})
+		}
+
 		// In theory, we should fire a ChainHeadEvent when we inject
 		// a canonical block, but sometimes we can insert a batch of
 		// canonical blocks. Avoid firing too many ChainHeadEvents,
@@ -2426,6 +2459,16 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 		blockWriteTimer.Update(time.Since(wstart) - statedb.AccountCommits - statedb.StorageCommits - statedb.SnapshotCommits - statedb.TrieDBCommits)
 		blockInsertTimer.UpdateSince(start)
 
+		blocktracing.RecordBlockStage(blocktracing.BlockExecute, block.Hash(), block.NumberU64(), pstart, start.Add(proctime),
+			attribute.Int64("validation_us", vtime.Microseconds()), attribute.Int64("consensus_us", statedb.BorConsensusTime.Microseconds()))
+		blocktracing.RecordBlockStage(blocktracing.BlockCommit, block.Hash(), block.NumberU64(), wstart, time.Now())
+
+		bc.blockStatsFeed.Send(BlockStatsEvent{Block: block, ExecTime: ptime})
+
+		if bc.chainConfig.Bor != nil {
+			debug.ReportLatency("execute", block.NumberU64(), proctime, time.Duration(bc.chainConfig.Bor.CalculatePeriod(block.NumberU64()))*time.Second)
+		}
+
 		// Report the import stats before returning the various results
 		stats.processed++
 		stats.usedGas += usedGas
@@ -2452,31 +2495,13 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 		}
 		// BOR
 
-		switch status {
-		case CanonStatTy:
-			log.Debug("Inserted new block", "number", block.Number(), "hash", block.Hash(),
-				"uncles", len(block.Uncles()), "txs", len(block.Transactions()), "gas", block.GasUsed(),
-				"elapsed", common.PrettyDuration(time.Since(start)),
-				"root", block.Root())
+		logInsertedBlock(status, block, start)
 
+		if status == CanonStatTy {
 			lastCanon = block
 
 			// Only count canonical blocks for GC processing time
 			bc.gcproc += proctime
-
-		case SideStatTy:
-			log.Debug("Inserted forked block", "number", block.Number(), "hash", block.Hash(),
-				"diff", block.Difficulty(), "elapsed", common.PrettyDuration(time.Since(start)),
-				"txs", len(block.Transactions()), "gas", block.GasUsed(), "uncles", len(block.Uncles()),
-				"root", block.Root())
-
-		default:
-			// This in theory is impossible, but lets be nice to our future selves and leave
-			// a log, instead of trying to track down blocks imports that don't emit logs.
-			log.Warn("Inserted block with unknown status", "number", block.Number(), "hash", block.Hash(),
-				"diff", block.Difficulty(), "elapsed", common.PrettyDuration(time.Since(start)),
-				"txs", len(block.Transactions()), "gas", block.GasUsed(), "uncles", len(block.Uncles()),
-				"root", block.Root())
 		}
 	}
 
@@ -2598,6 +2623,41 @@ func (bc *BlockChain) processBlock(block *types.Block, statedb *state.StateDB, s
 	return &blockProcessingResult{usedGas: usedGas, procTime: proctime, status: status}, nil
 }
 
+// logInsertedBlock reports the import of a block. The log context is only built
+// if debug logging is enabled, as boxing the fields allocates on every imported
+// block otherwise.
+func logInsertedBlock(status WriteStatus, block *types.Block, start time.Time) {
+	switch status {
+	case CanonStatTy:
+		if !log.Root().Enabled(context.Background(), log.LevelDebug) {
+			return
+		}
+
+		log.Debug("Inserted new block", "number", block.Number(), "hash", block.Hash(),
+			"uncles", len(block.Uncles()), "txs", len(block.Transactions()), "gas", block.GasUsed(),
+			"elapsed", common.PrettyDuration(time.Since(start)),
+			"root", block.Root())
+
+	case SideStatTy:
+		if !log.Root().Enabled(context.Background(), log.LevelDebug) {
+			return
+		}
+
+		log.Debug("Inserted forked block", "number", block.Number(), "hash", block.Hash(),
+			"diff", block.Difficulty(), "elapsed", common.PrettyDuration(time.Since(start)),
+			"txs", len(block.Transactions()), "gas", block.GasUsed(), "uncles", len(block.Uncles()),
+			"root", block.Root())
+
+	default:
+		// This in theory is impossible, but lets be nice to our future selves and leave
+		// a log, instead of trying to track down blocks imports that don't emit logs.
+		log.Warn("Inserted block with unknown status", "number", block.Number(), "hash", block.Hash(),
+			"diff", block.Difficulty(), "elapsed", common.PrettyDuration(time.Since(start)),
+			"txs", len(block.Transactions()), "gas", block.GasUsed(), "uncles", len(block.Uncles()),
+			"root", block.Root())
+	}
+}
+
 // insertSideChain is called when an import batch hits upon a pruned ancestor
 // error, which happens when a sidechain with a sufficiently old fork-block is
 // found.
@@ -2919,6 +2979,15 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 		}
 	}
 
+	// Refuse to drop more blocks than allowed, the operator must step in
+	if err := bc.checkReorgDepth(uint64(len(oldChain))); err != nil {
+		blockReorgRefusedMeter.Mark(1)
+		log.Error("Refused deep chain reorg", "number", commonBlock.Number(), "hash", commonBlock.Hash(),
+			"drop", len(oldChain), "add", len(newChain), "limit", bc.maxReorgDepth.Load())
+
+		return err
+	}
+
 	// Ensure the user sees large reorgs
 	if len(oldChain) > 0 && len(newChain) > 0 {
 		bc.chain2HeadFeed.Send(Chain2HeadEvent{
@@ -2950,7 +3019,7 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 		// rewind the canonical chain to a lower point.
 		home, err := os.UserHomeDir()
 		if err != nil {
-			fmt.Println("Impossible reorg : Unable to get user home dir", "Error", err)
+			log.Error("Impossible reorg : Unable to get user home dir", "Error", err)
 		}
 
 		outPath := filepath.Join(home, "impossible-reorgs", fmt.Sprintf("%v-impossibleReorg", time.Now().Format(time.RFC3339)))
@@ -3076,6 +3145,13 @@ func (bc *BlockChain) reorg(oldHead *types.Header, newHead *types.Block) error {
 		bc.logsFeed.Send(rebirthLogs)
 	}
 
+	// State syncs committed by the new canon chain
+	for i := len(newChain) - 1; i >= 1; i-- {
+		if events := rawdb.ReadBorStateSyncEvents(bc.db, newChain[i].Hash(), newChain[i].NumberU64()); len(events) > 0 {
+			bc.stateSyncCommitFeed.Send(StateSyncCommitEvent{Number: newChain[i].NumberU64(), Hash: newChain[i].Hash(), Events: events})
+		}
+	}
+
 	return nil
 }
 
@@ -3252,15 +3328,25 @@ func summarizeBadBlock(block *types.Block, receipts []*types.Receipt, config *pa
 		vcs = fmt.Sprintf("\nVCS: %s", vcs)
 	}
 
+	// Consensus errors may carry the context of the rejected block
+	var errContext string
+
+	if ctx := consensus.ErrorContext(err); len(ctx) > 0 {
+		errContext = "\nContext:"
+		for i := 0; i+1 < len(ctx); i += 2 {
+			errContext += fmt.Sprintf(" %v=%v", ctx[i], ctx[i+1])
+		}
+	}
+
 	return fmt.Sprintf(`
 ########## BAD BLOCK #########
 Block: %v (%#x)
-Error: %v
+Error: %v%v
 Platform: %v%v
 Chain config: %#v
 Receipts: %v
 ##############################
-`, block.Number(), block.Hash(), err, platform, vcs, config, receiptString)
+`, block.Number(), block.Hash(), err, errContext, platform, vcs, config, receiptString)
 }
 
 // InsertHeaderChain attempts to insert the given header chain in to the local
@@ -3304,6 +3390,61 @@ func (bc *BlockChain) SetTrieFlushInterval(interval time.Duration) {
 	bc.flushInterval.Store(int64(interval))
 }
 
+// MaintenanceGate reports whether expensive background work may run on top of
+// the given head without risking to delay the block production of the node.
+type MaintenanceGate func(head *types.Header) bool
+
+// SetMaintenanceGate sets the gate expensive background work, such as trie
+// flushes, is deferred behind.
+func (bc *BlockChain) SetMaintenanceGate(gate MaintenanceGate) {
+	bc.maintenance.Store(&gate)
+}
+
+// maintenanceWindow reports whether expensive background work may run on top
+// of the given head.
+func (bc *BlockChain) maintenanceWindow(head *types.Header) bool {
+	gate := bc.maintenance.Load()
+	if gate == nil || *gate == nil {
+		return true
+	}
+
+	return (*gate)(head)
+}
+
+// SetMaxReorgDepth sets the number of blocks beyond which reorgs are refused,
+// guarding against pathological deep reorgs. Zero disables the limit.
+func (bc *BlockChain) SetMaxReorgDepth(depth uint64) {
+	bc.maxReorgDepth.Store(depth)
+}
+
+// MaxReorgDepth returns the number of blocks beyond which reorgs are refused.
+func (bc *BlockChain) MaxReorgDepth() uint64 {
+	return bc.maxReorgDepth.Load()
+}
+
+// AllowDeepReorg lets the next reorg deeper than the maximum depth through, as
+// long as it drops at most the given number of blocks.
+func (bc *BlockChain) AllowDeepReorg(depth uint64) {
+	bc.deepReorgOK.Store(depth)
+}
+
+// checkReorgDepth returns an error if a reorg dropping the given number of
+// blocks is deeper than allowed. A deep reorg allowed by the operator is let
+// through once.
+func (bc *BlockChain) checkReorgDepth(depth uint64) error {
+	limit := bc.maxReorgDepth.Load()
+	if limit == 0 || depth <= limit {
+		return nil
+	}
+
+	if allowed := bc.deepReorgOK.Load(); depth <= allowed && bc.deepReorgOK.CompareAndSwap(allowed, 0) {
+		log.Warn("Deep chain reorg allowed by operator", "drop", depth, "limit", limit)
+		return nil
+	}
+
+	return fmt.Errorf("%w: %d blocks dropped, limit %d", ErrDeepReorg, depth, limit)
+}
+
 // GetTrieFlushInterval gets the in-memory tries flushAlloc interval
 func (bc *BlockChain) GetTrieFlushInterval() time.Duration {
 	return time.Duration(bc.flushInterval.Load())
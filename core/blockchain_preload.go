@@ -0,0 +1,63 @@
+package core
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Preload reads the code and up to slots storage slots of the given contracts
+// at the head state, warming the code and snapshot caches so that the first
+// blocks processed after a restart don't pay for the cold reads. Storage is
+// only preloaded if the state snapshot is available.
+func (bc *BlockChain) Preload(contracts []common.Address, slots int) {
+	start := time.Now()
+	head := bc.CurrentBlock()
+
+	statedb, err := bc.StateAt(head.Root)
+	if err != nil {
+		log.Warn("Failed to preload contracts", "number", head.Number, "err", err)
+		return
+	}
+
+	var (
+		code   int
+		loaded int
+	)
+
+	for _, addr := range contracts {
+		if bc.insertStopped() {
+			return
+		}
+
+		code += len(statedb.GetCode(addr))
+
+		if bc.snaps == nil || slots <= 0 {
+			continue
+		}
+
+		snap := bc.snaps.Snapshot(head.Root)
+		if snap == nil {
+			continue
+		}
+
+		accountHash := crypto.Keccak256Hash(addr.Bytes())
+
+		it, err := bc.snaps.StorageIterator(head.Root, accountHash, common.Hash{})
+		if err != nil {
+			continue
+		}
+
+		for n := 0; n < slots && it.Next(); n++ {
+			if _, err := snap.Storage(accountHash, it.Hash()); err == nil {
+				loaded++
+			}
+		}
+
+		it.Release()
+	}
+
+	log.Info("Preloaded contracts", "contracts", len(contracts), "code", common.StorageSize(code), "slots", loaded, "elapsed", common.PrettyDuration(time.Since(start)))
+}
@@ -535,3 +535,13 @@ func (bc *BlockChain) GetStateSync() []*types.StateSyncData {
 func (bc *BlockChain) SubscribeStateSyncEvent(ch chan<- StateSyncEvent) event.Subscription {
 	return bc.scope.Track(bc.stateSyncFeed.Subscribe(ch))
 }
+
+// SubscribeStateSyncCommitEvent registers a subscription of StateSyncCommitEvent.
+func (bc *BlockChain) SubscribeStateSyncCommitEvent(ch chan<- StateSyncCommitEvent) event.Subscription {
+	return bc.scope.Track(bc.stateSyncCommitFeed.Subscribe(ch))
+}
+
+// SubscribeBlockStatsEvent registers a subscription of BlockStatsEvent.
+func (bc *BlockChain) SubscribeBlockStatsEvent(ch chan<- BlockStatsEvent) event.Subscription {
+	return bc.scope.Track(bc.blockStatsFeed.Subscribe(ch))
+}
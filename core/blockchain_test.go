@@ -678,6 +678,50 @@ func testReorgLong(t *testing.T, full bool, scheme string) {
 	testReorg(t, []int64{0, 0, -9}, []int64{0, 0, 0, -9}, 393280+params.GenesisDifficulty.Int64(), full, scheme)
 }
 
+// Tests that reorgs deeper than the maximum depth are refused, until allowed
+// once by the operator.
+func TestReorgDepthLimit(t *testing.T) {
+	genDb, _, blockchain, err := newCanonical(ethash.NewFaker(), 0, true, rawdb.HashScheme)
+	if err != nil {
+		t.Fatalf("failed to create pristine chain: %v", err)
+	}
+	defer blockchain.Stop()
+
+	blockchain.SetMaxReorgDepth(4)
+
+	genesis := blockchain.GetBlockByHash(blockchain.CurrentBlock().Hash())
+	easyBlocks, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, 8, func(i int, b *BlockGen) {})
+	diffBlocks, _ := GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), genDb, 9, func(i int, b *BlockGen) {
+		b.OffsetTime(-9)
+	})
+
+	if _, err := blockchain.InsertChain(easyBlocks); err != nil {
+		t.Fatalf("failed to insert easy chain: %v", err)
+	}
+	if _, err := blockchain.InsertChain(diffBlocks); !errors.Is(err, ErrDeepReorg) {
+		t.Fatalf("deep reorg error mismatch: have %v, want %v", err, ErrDeepReorg)
+	}
+	if head := blockchain.CurrentBlock().Hash(); head != easyBlocks[len(easyBlocks)-1].Hash() {
+		t.Fatalf("head reorged: have %x, want %x", head, easyBlocks[len(easyBlocks)-1].Hash())
+	}
+	// A shallower allowance doesn't let the reorg through
+	blockchain.AllowDeepReorg(7)
+	if _, err := blockchain.InsertChain(diffBlocks); !errors.Is(err, ErrDeepReorg) {
+		t.Fatalf("deep reorg error mismatch: have %v, want %v", err, ErrDeepReorg)
+	}
+	blockchain.AllowDeepReorg(8)
+	if _, err := blockchain.InsertChain(diffBlocks); err != nil {
+		t.Fatalf("failed to reorg allowed: %v", err)
+	}
+	if head := blockchain.CurrentBlock().Hash(); head != diffBlocks[len(diffBlocks)-1].Hash() {
+		t.Fatalf("head not reorged: have %x, want %x", head, diffBlocks[len(diffBlocks)-1].Hash())
+	}
+	// The allowance is used up by the reorg
+	if err := blockchain.checkReorgDepth(8); !errors.Is(err, ErrDeepReorg) {
+		t.Fatalf("deep reorg error mismatch: have %v, want %v", err, ErrDeepReorg)
+	}
+}
+
 // Tests that reorganising a short difficult chain after a long easy one
 // overwrites the canonical numbers and links in the database.
 func TestReorgShortHeaders(t *testing.T) {
@@ -4620,3 +4664,19 @@ func TestEIP3651(t *testing.T) {
 		t.Fatalf("sender balance incorrect: expected %d, got %d", expected, actual)
 	}
 }
+
+// Tests that reporting an imported block doesn't allocate if debug logging is
+// disabled, as it's done for every block in the import path.
+func TestLogInsertedBlockAllocs(t *testing.T) {
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)})
+	start := time.Now()
+
+	for _, status := range []WriteStatus{CanonStatTy, SideStatTy} {
+		allocs := testing.AllocsPerRun(100, func() {
+			logInsertedBlock(status, block, start)
+		})
+		if allocs != 0 {
+			t.Errorf("status %d: allocations mismatch: have %v, want 0", status, allocs)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package core
 
 import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
@@ -9,6 +12,14 @@ type StateSyncEvent struct {
 	Data *types.StateSyncData
 }
 
+// StateSyncCommitEvent is posted for every block committing state syncs once
+// it's on the canonical chain, including the blocks a reorg makes canonical.
+type StateSyncCommitEvent struct {
+	Number uint64
+	Hash   common.Hash
+	Events []*types.StateSyncData
+}
+
 var (
 	Chain2HeadReorgEvent     = "reorg"
 	Chain2HeadCanonicalEvent = "head"
@@ -21,3 +32,10 @@ type Chain2HeadEvent struct {
 	OldChain []*types.Block
 	Type     string
 }
+
+// BlockStatsEvent is posted for every block imported through the chain, with
+// the time spent executing its transactions.
+type BlockStatsEvent struct {
+	Block    *types.Block
+	ExecTime time.Duration
+}
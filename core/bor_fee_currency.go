@@ -0,0 +1,62 @@
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// CheckFeeCurrency returns an error if the chain schedules paying the fees
+// through a fee currency, but none is given, so that a node which can't
+// process the blocks of the chain fails at startup rather than at the fork.
+func CheckFeeCurrency(config *params.ChainConfig, currency vm.FeeCurrency) error {
+	if config.Bor == nil || config.Bor.FeeCurrencyBlock == nil {
+		return nil
+	}
+
+	if currency == nil {
+		return ErrNoFeeCurrency
+	}
+
+	return nil
+}
+
+// FeeCurrency returns the fee currency paying the transaction fees of the chain
+// from the fee currency block on, set in its VM config, or nil if none. The
+// chain may be nil, as block generation applies transactions without one.
+func (bc *BlockChain) FeeCurrency() vm.FeeCurrency {
+	if bc == nil {
+		return nil
+	}
+
+	return bc.vmConfig.FeeCurrency
+}
+
+// feeCurrencyChain is a chain context serving the fee currency of the chain.
+type feeCurrencyChain interface {
+	FeeCurrency() vm.FeeCurrency
+}
+
+// chainFeeCurrency returns the fee currency of the given chain context, or nil
+// if it has none.
+func chainFeeCurrency(chain ChainContext) vm.FeeCurrency {
+	if chain, ok := chain.(feeCurrencyChain); ok {
+		return chain.FeeCurrency()
+	}
+
+	return nil
+}
+
+// activeFeeCurrency returns the fee currency paying the fees of the messages
+// applied in the given EVM, or nil if they're paid in the native token.
+func activeFeeCurrency(evm *vm.EVM) (vm.FeeCurrency, error) {
+	bor := evm.ChainConfig().Bor
+	if bor == nil || !bor.IsFeeCurrency(evm.Context.BlockNumber) {
+		return nil, nil
+	}
+
+	if evm.Context.FeeCurrency == nil {
+		return nil, ErrNoFeeCurrency
+	}
+
+	return evm.Context.FeeCurrency, nil
+}
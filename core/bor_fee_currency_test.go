@@ -0,0 +1,151 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// tokenFeeCurrency charges the fees in a token whose balances are kept in the
+// storage of the token contract.
+type tokenFeeCurrency struct {
+	token common.Address
+}
+
+func (f *tokenFeeCurrency) BalanceOf(statedb vm.StateDB, account common.Address) *uint256.Int {
+	value := statedb.GetState(f.token, common.BytesToHash(account.Bytes()))
+	return new(uint256.Int).SetBytes(value.Bytes())
+}
+
+func (f *tokenFeeCurrency) setBalance(evm *vm.EVM, account common.Address, balance *uint256.Int) {
+	evm.StateDB.SetState(f.token, common.BytesToHash(account.Bytes()), balance.Bytes32())
+}
+
+func (f *tokenFeeCurrency) BuyGas(evm *vm.EVM, from common.Address, fee *uint256.Int, maxFee *uint256.Int) error {
+	balance := f.BalanceOf(evm.StateDB, from)
+	if balance.Cmp(maxFee) < 0 {
+		return fmt.Errorf("%w: token balance %v, want %v", ErrInsufficientFunds, balance, maxFee)
+	}
+
+	f.setBalance(evm, from, balance.Sub(balance, fee))
+
+	return nil
+}
+
+func (f *tokenFeeCurrency) RefundGas(evm *vm.EVM, from common.Address, refund *uint256.Int) {
+	balance := f.BalanceOf(evm.StateDB, from)
+	f.setBalance(evm, from, balance.Add(balance, refund))
+}
+
+func (f *tokenFeeCurrency) PayFees(evm *vm.EVM, _ common.Address, tip *uint256.Int, burntContract common.Address, burnt *uint256.Int) {
+	coinbase := f.BalanceOf(evm.StateDB, evm.Context.Coinbase)
+	f.setBalance(evm, evm.Context.Coinbase, coinbase.Add(coinbase, tip))
+
+	burn := f.BalanceOf(evm.StateDB, burntContract)
+	f.setBalance(evm, burntContract, burn.Add(burn, burnt))
+}
+
+func TestFeeCurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		token    = common.HexToAddress("0x000000000000000000000000000000000000fee0")
+		burnt    = common.HexToAddress("0x000000000000000000000000000000000000dead")
+		coinbase = common.Address{0x1}
+		sender   = common.Address{0x2}
+		receiver = common.Address{0x3}
+
+		baseFee = big.NewInt(params.GWei)
+		tip     = big.NewInt(2 * params.GWei)
+
+		currency = &tokenFeeCurrency{token: token}
+	)
+
+	bor := *params.BorTestChainConfig.Bor
+	bor.BurntContract = map[string]string{"0": burnt.Hex()}
+	bor.FeeCurrencyBlock = big.NewInt(10)
+
+	config := *params.BorTestChainConfig
+	config.Bor = &bor
+
+	apply := func(number int64, currency vm.FeeCurrency) (*state.StateDB, *ExecutionResult, error) {
+		t.Helper()
+
+		statedb, err := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		require.NoError(t, err)
+
+		statedb.AddBalance(sender, uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+		statedb.SetState(token, common.BytesToHash(sender.Bytes()), uint256.NewInt(params.Ether).Bytes32())
+
+		msg := &Message{
+			From:      sender,
+			To:        &receiver,
+			Value:     big.NewInt(1),
+			GasLimit:  50000,
+			GasPrice:  new(big.Int).Add(baseFee, tip),
+			GasFeeCap: new(big.Int).Add(baseFee, tip),
+			GasTipCap: tip,
+		}
+
+		blockCtx := vm.BlockContext{
+			CanTransfer: CanTransfer,
+			Transfer:    Transfer,
+			Coinbase:    coinbase,
+			BlockNumber: big.NewInt(number),
+			Difficulty:  big.NewInt(1),
+			BaseFee:     baseFee,
+			GasLimit:    30_000_000,
+			FeeCurrency: currency,
+		}
+
+		evm := vm.NewEVM(blockCtx, NewEVMTxContext(msg), statedb, &config, vm.Config{})
+		result, err := ApplyMessage(evm, msg, new(GasPool).AddGas(blockCtx.GasLimit), context.Background())
+
+		return statedb, result, err
+	}
+
+	// Before the fork, the fees are paid in the native token
+	statedb, result, err := apply(9, currency)
+	require.NoError(t, err)
+	require.False(t, result.FeeCurrency)
+	require.Equal(t, uint64(params.Ether-1-21000*3*params.GWei), statedb.GetBalance(sender).Uint64())
+	require.Equal(t, uint64(21000*2*params.GWei), statedb.GetBalance(coinbase).Uint64())
+
+	// After it, they can't be paid without a fee currency
+	require.ErrorIs(t, CheckFeeCurrency(&config, nil), ErrNoFeeCurrency)
+
+	_, _, err = apply(10, nil)
+	require.ErrorIs(t, err, ErrNoFeeCurrency)
+
+	require.NoError(t, CheckFeeCurrency(&config, currency))
+
+	// With one, only the value is paid in the native token
+	statedb, result, err = apply(10, currency)
+	require.NoError(t, err)
+	require.True(t, result.FeeCurrency)
+	require.Equal(t, uint64(params.Ether-1), statedb.GetBalance(sender).Uint64())
+	require.True(t, statedb.GetBalance(coinbase).IsZero())
+	require.True(t, statedb.GetBalance(burnt).IsZero())
+
+	require.Equal(t, uint64(params.Ether-21000*3*params.GWei), currency.BalanceOf(statedb, sender).Uint64())
+	require.Equal(t, uint64(21000*2*params.GWei), currency.BalanceOf(statedb, coinbase).Uint64())
+	require.Equal(t, uint64(21000*params.GWei), currency.BalanceOf(statedb, burnt).Uint64())
+
+	// Senders unable to afford the maximum fee in the token are rejected
+	currency.token = common.Address{0x4}
+
+	_, _, err = apply(10, currency)
+	require.ErrorIs(t, err, ErrInsufficientFunds)
+}
@@ -32,6 +32,10 @@ var (
 	// ErrNoGenesis is returned when there is no Genesis Block.
 	ErrNoGenesis = errors.New("genesis not found in chain")
 
+	// ErrDeepReorg is returned if a reorg drops more blocks than the maximum
+	// depth configured, unless allowed by the operator.
+	ErrDeepReorg = errors.New("reorg deeper than allowed")
+
 	errSideChainReceipts = errors.New("side blocks can't be accepted as ancient chain data")
 )
 
@@ -115,4 +119,8 @@ var (
 
 	// ErrBlobTxCreate is returned if a blob transaction has no explicit to field.
 	ErrBlobTxCreate = errors.New("blob transaction of type create")
+
+	// ErrNoFeeCurrency is returned if the fees of a transaction are to be paid
+	// through a fee currency, but none is installed in the node.
+	ErrNoFeeCurrency = errors.New("fee currency active but not installed")
 )
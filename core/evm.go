@@ -77,6 +77,7 @@ func NewEVMBlockContext(header *types.Header, chain ChainContext, author *common
 		BlobBaseFee: blobBaseFee,
 		GasLimit:    header.GasLimit,
 		Random:      random,
+		FeeCurrency: chainFeeCurrency(chain),
 	}
 }
 
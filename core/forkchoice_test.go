@@ -321,3 +321,6 @@ func (w *chainValidatorFake) RemoveMilestoneID(milestoneId string) {
 func (w *chainValidatorFake) GetMilestoneIDsList() []string {
 	return nil
 }
+func (w *chainValidatorFake) GetLockedMilestone() (bool, uint64, common.Hash) {
+	return false, 0, common.Hash{}
+}
@@ -187,7 +187,8 @@ func (task *ExecutionTask) Settle() {
 		task.finalStateDB.AddLog(l)
 	}
 
-	if *task.shouldDelayFeeCal {
+	// Fees paid through the fee currency are settled already
+	if *task.shouldDelayFeeCal && !task.result.FeeCurrency {
 		if task.config.IsLondon(task.blockNumber) {
 			task.finalStateDB.AddBalance(task.result.BurntContractAddress, cmath.BigIntToUint256Int(task.result.FeeBurnt), tracing.BalanceChangeTransfer)
 		}
@@ -920,6 +920,9 @@ func DeleteBlock(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 
 	// delete bor receipt
 	DeleteBorReceipt(db, hash, number)
+
+	// delete bor state-sync events
+	DeleteBorStateSyncEvents(db, hash, number)
 }
 
 // DeleteBlockWithoutNumber removes all block data associated with a hash, except
@@ -18,15 +18,18 @@ package rawdb
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethdb"
 )
 
 type tableSize struct {
-	name string
-	size common.StorageSize
+	name  string
+	size  common.StorageSize
+	codec string // Compression codec of the table, empty if unknown or uncompressed
 }
 
 // freezerInfo contains the basic information of the freezer.
@@ -88,6 +91,17 @@ func inspectFreezers(db ethdb.Database) ([]freezerInfo, error) {
 			if err != nil {
 				return nil, err
 			}
+			if datadir, err := db.AncientDatadir(); err == nil && datadir != "" {
+				dir := resolveChainFreezerDir(datadir)
+				for i, table := range info.sizes {
+					if chainFreezerNoSnappy[table.name] {
+						continue
+					}
+					if codec, err := readTableCodec(dir, table.name); err == nil {
+						info.sizes[i].codec = codec.String()
+					}
+				}
+			}
 			infos = append(infos, info)
 
 		case StateFreezerName:
@@ -153,3 +167,104 @@ func InspectFreezerTable(ancient string, freezerName string, tableName string, s
 
 	return nil
 }
+
+// readTableCodec reads the compression codec of a freezer table from its
+// metadata file, without opening the table itself.
+func readTableCodec(dir string, table string) (FreezerCodec, error) {
+	file, err := os.Open(filepath.Join(dir, fmt.Sprintf("%s.meta", table)))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	meta, err := readMetadata(file)
+	if err != nil {
+		return 0, err
+	}
+
+	return meta.Codec, nil
+}
+
+// codecBenchItems is the number of most recent items sampled per table when
+// benchmarking the freezer codecs.
+const codecBenchItems = 1024
+
+// codecBench is the result of compressing a sample of a freezer table with a
+// specific codec.
+type codecBench struct {
+	table  string
+	codec  FreezerCodec
+	raw    common.StorageSize // Total size of the sampled items
+	size   common.StorageSize // Total size of the compressed items
+	encode time.Duration      // Time spent compressing the sampled items
+	decode time.Duration      // Time spent decompressing the sampled items
+}
+
+// ratio returns the compression ratio of the benchmarked codec.
+func (b *codecBench) ratio() float64 {
+	if b.size == 0 {
+		return 0
+	}
+
+	return float64(b.raw) / float64(b.size)
+}
+
+// benchmarkCodecs compresses the most recent items of the codec selectable chain
+// freezer tables with every supported codec, measuring the resulting size and
+// the time spent on it.
+func benchmarkCodecs(reader ethdb.AncientReader) ([]codecBench, error) {
+	ancients, err := reader.Ancients()
+	if err != nil {
+		return nil, err
+	}
+
+	tail, err := reader.Tail()
+	if err != nil {
+		return nil, err
+	}
+
+	start, count := tail, ancients-tail
+	if count > codecBenchItems {
+		start, count = ancients-codecBenchItems, codecBenchItems
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	var results []codecBench
+
+	for _, table := range chainFreezerCodecTables {
+		items, err := reader.AncientRange(table, start, count, 0)
+		if err != nil {
+			continue // bor receipts might be missing for old blocks
+		}
+
+		for _, codec := range []FreezerCodec{FreezerCodecSnappy, FreezerCodecZstd} {
+			bench := codecBench{table: table, codec: codec}
+			blobs := make([][]byte, len(items))
+
+			begin := time.Now()
+
+			for i, item := range items {
+				blobs[i] = codec.encode(nil, item)
+				bench.raw += common.StorageSize(len(item))
+				bench.size += common.StorageSize(len(blobs[i]))
+			}
+
+			bench.encode = time.Since(begin)
+			begin = time.Now()
+
+			for _, blob := range blobs {
+				if _, err := codec.decode(blob); err != nil {
+					return nil, err
+				}
+			}
+
+			bench.decode = time.Since(begin)
+			results = append(results, bench)
+		}
+	}
+
+	return results, nil
+}
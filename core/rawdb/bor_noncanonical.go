@@ -0,0 +1,79 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// nonCanonicalPruneTailKey tracks the block number below which non-canonical
+// (reorged-out) blocks have been pruned.
+var nonCanonicalPruneTailKey = []byte("NonCanonicalPruneTail")
+
+// ReadNonCanonicalPruneTail retrieves the block number below which non-canonical
+// blocks have been pruned, or nil if they were never pruned.
+func ReadNonCanonicalPruneTail(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(nonCanonicalPruneTailKey)
+	if len(data) != 8 {
+		return nil
+	}
+
+	number := binary.BigEndian.Uint64(data)
+
+	return &number
+}
+
+// WriteNonCanonicalPruneTail stores the block number below which non-canonical
+// blocks have been pruned.
+func WriteNonCanonicalPruneTail(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(nonCanonicalPruneTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store non-canonical prune tail", "err", err)
+	}
+}
+
+// ReadNonCanonicalHashes retrieves the hashes of all the blocks stored at the
+// given height which are not part of the canonical chain.
+func ReadNonCanonicalHashes(db ethdb.Database, number uint64) []common.Hash {
+	canonical := ReadCanonicalHash(db, number)
+
+	var hashes []common.Hash
+
+	for _, hash := range ReadAllHashes(db, number) {
+		if hash != canonical {
+			hashes = append(hashes, hash)
+		}
+	}
+
+	return hashes
+}
+
+// PruneNonCanonicalBlocks deletes all non-canonical blocks within the [from, to)
+// range, returning the number of blocks deleted.
+func PruneNonCanonicalBlocks(db ethdb.Database, from, to uint64) (int, error) {
+	var (
+		batch   = db.NewBatch()
+		deleted int
+	)
+
+	for number := from; number < to; number++ {
+		for _, hash := range ReadNonCanonicalHashes(db, number) {
+			DeleteBlock(batch, hash, number)
+
+			deleted++
+		}
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return deleted, err
+			}
+
+			batch.Reset()
+		}
+	}
+
+	WriteNonCanonicalPruneTail(batch, to)
+
+	return deleted, batch.Write()
+}
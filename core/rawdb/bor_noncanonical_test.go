@@ -0,0 +1,60 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestPruneNonCanonicalBlocks(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+
+	// Store a canonical and a reorged-out block at every height
+	for number := uint64(1); number <= 10; number++ {
+		canonical := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number), Extra: []byte("canonical")})
+		side := types.NewBlockWithHeader(&types.Header{Number: new(big.Int).SetUint64(number), Extra: []byte("side")})
+
+		WriteBlock(db, canonical)
+		WriteBlock(db, side)
+		WriteCanonicalHash(db, canonical.Hash(), number)
+
+		if hashes := ReadNonCanonicalHashes(db, number); len(hashes) != 1 || hashes[0] != side.Hash() {
+			t.Fatalf("block %d: non-canonical hashes mismatch: have %v, want [%x]", number, hashes, side.Hash())
+		}
+	}
+
+	if tail := ReadNonCanonicalPruneTail(db); tail != nil {
+		t.Fatalf("unexpected prune tail: %d", *tail)
+	}
+
+	deleted, err := PruneNonCanonicalBlocks(db, 1, 6)
+	if err != nil {
+		t.Fatalf("failed to prune: %v", err)
+	}
+
+	if deleted != 5 {
+		t.Fatalf("deleted blocks mismatch: have %d, want 5", deleted)
+	}
+
+	if tail := ReadNonCanonicalPruneTail(db); tail == nil || *tail != 6 {
+		t.Fatalf("prune tail mismatch: have %v, want 6", tail)
+	}
+
+	for number := uint64(1); number <= 10; number++ {
+		hashes := ReadNonCanonicalHashes(db, number)
+		if number < 6 && len(hashes) != 0 {
+			t.Fatalf("block %d: non-canonical blocks not pruned", number)
+		}
+
+		if number >= 6 && len(hashes) != 1 {
+			t.Fatalf("block %d: non-canonical blocks pruned beyond range", number)
+		}
+
+		if ReadHeader(db, ReadCanonicalHash(db, number), number) == nil {
+			t.Fatalf("block %d: canonical header pruned", number)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	// borStateSyncPrefix + num (uint64 big endian) + hash -> state-sync events committed in the block
+	borStateSyncPrefix = []byte(borStateSyncPrefixStr)
+
+	// borStateSyncLookupPrefix + state id (uint64 big endian) -> canonical block number
+	borStateSyncLookupPrefix = []byte(borStateSyncLookupPrefixStr)
+
+	// borLastStateSyncIDKey tracks the id of the latest state-sync event committed on the canonical chain
+	borLastStateSyncIDKey = []byte("matic-bor-last-state-id")
+
+	// borStateSyncIntentKey tracks the state-sync events being committed by the latest sprint start block
+	borStateSyncIntentKey = []byte("matic-bor-state-sync-intent")
+)
+
+const (
+	borStateSyncPrefixStr       = "matic-bor-state-sync-"
+	borStateSyncLookupPrefixStr = "matic-bor-state-lookup-"
+)
+
+// borStateSyncKey = borStateSyncPrefix + num (uint64 big endian) + hash
+func borStateSyncKey(number uint64, hash common.Hash) []byte {
+	return append(append(borStateSyncPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// borStateSyncLookupKey = borStateSyncLookupPrefix + state id (uint64 big endian)
+func borStateSyncLookupKey(id uint64) []byte {
+	return append(borStateSyncLookupPrefix, encodeBlockNumber(id)...)
+}
+
+// HasBorStateSyncEvents verifies the existence of the state-sync index entry of
+// a block. Blocks which were not processed by the indexer (e.g. snap synced
+// blocks) don't have an entry, while indexed blocks without events do.
+func HasBorStateSyncEvents(db ethdb.KeyValueReader, hash common.Hash, number uint64) bool {
+	has, _ := db.Has(borStateSyncKey(number, hash))
+	return has
+}
+
+// ReadBorStateSyncEvents retrieves the state-sync events committed in the given block.
+func ReadBorStateSyncEvents(db ethdb.KeyValueReader, hash common.Hash, number uint64) []*types.StateSyncData {
+	data, _ := db.Get(borStateSyncKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+
+	var events []*types.StateSyncData
+	if err := rlp.DecodeBytes(data, &events); err != nil {
+		log.Error("Invalid bor state-sync events RLP", "hash", hash, "number", number, "err", err)
+		return nil
+	}
+
+	return events
+}
+
+// WriteBorStateSyncEvents stores the state-sync events committed in a block. An
+// entry is written even if the block didn't commit any events, to mark the block
+// as indexed.
+func WriteBorStateSyncEvents(db ethdb.KeyValueWriter, hash common.Hash, number uint64, events []*types.StateSyncData) {
+	if events == nil {
+		events = []*types.StateSyncData{}
+	}
+
+	bytes, err := rlp.EncodeToBytes(events)
+	if err != nil {
+		log.Crit("Failed to encode bor state-sync events", "err", err)
+	}
+
+	if err := db.Put(borStateSyncKey(number, hash), bytes); err != nil {
+		log.Crit("Failed to store bor state-sync events", "err", err)
+	}
+}
+
+// DeleteBorStateSyncEvents removes the state-sync events associated with a block.
+// The reverse lookup entries are not removed: they are rewritten whenever a block
+// committing the same events becomes canonical, and readers verify them against
+// the canonical chain.
+func DeleteBorStateSyncEvents(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(borStateSyncKey(number, hash)); err != nil {
+		log.Crit("Failed to delete bor state-sync events", "err", err)
+	}
+}
+
+// WriteBorStateSyncLookupEntries stores the reverse lookup entries from state id
+// to block number for the events committed in a canonical block.
+func WriteBorStateSyncLookupEntries(db ethdb.KeyValueWriter, number uint64, events []*types.StateSyncData) {
+	for _, event := range events {
+		if err := db.Put(borStateSyncLookupKey(event.ID), encodeBlockNumber(number)); err != nil {
+			log.Crit("Failed to store bor state-sync lookup entry", "err", err)
+		}
+	}
+}
+
+// ReadBorStateSyncLookupEntry retrieves the number of the block in which the
+// state-sync event with the given id was committed.
+func ReadBorStateSyncLookupEntry(db ethdb.KeyValueReader, id uint64) *uint64 {
+	data, _ := db.Get(borStateSyncLookupKey(id))
+	if len(data) != 8 {
+		return nil
+	}
+
+	number := binary.BigEndian.Uint64(data)
+
+	return &number
+}
+
+// ReadBorStateSyncEvent retrieves a state-sync event committed on the canonical
+// chain by its state id, along with the hash and number of the block it was
+// committed in.
+func ReadBorStateSyncEvent(db ethdb.Reader, id uint64) (*types.StateSyncData, common.Hash, uint64) {
+	number := ReadBorStateSyncLookupEntry(db, id)
+	if number == nil {
+		return nil, common.Hash{}, 0
+	}
+
+	hash := ReadCanonicalHash(db, *number)
+	if hash == (common.Hash{}) {
+		return nil, common.Hash{}, 0
+	}
+
+	for _, event := range ReadBorStateSyncEvents(db, hash, *number) {
+		if event.ID == id {
+			return event, hash, *number
+		}
+	}
+
+	return nil, common.Hash{}, 0
+}
+
+// ReadBorLastStateSyncID retrieves the id of the latest state-sync event
+// committed on the canonical chain.
+func ReadBorLastStateSyncID(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(borLastStateSyncIDKey)
+	if len(data) != 8 {
+		return nil
+	}
+
+	id := binary.BigEndian.Uint64(data)
+
+	return &id
+}
+
+// WriteBorLastStateSyncID stores the id of the latest state-sync event
+// committed on the canonical chain.
+func WriteBorLastStateSyncID(db ethdb.KeyValueWriter, id uint64) {
+	if err := db.Put(borLastStateSyncIDKey, encodeBlockNumber(id)); err != nil {
+		log.Crit("Failed to store the last bor state-sync id", "err", err)
+	}
+}
+
+// BorStateSyncIntent is the write-ahead record of the state-sync events being
+// committed by a sprint start block being imported, marked done once they were
+// all executed.
+type BorStateSyncIntent struct {
+	Number  uint64
+	Hash    common.Hash
+	FromID  uint64 // Id of the first event to commit
+	ToID    uint64 // Id of the last event to commit
+	Applied uint64 // Number of events committed, set once done
+	Done    bool
+}
+
+// ReadBorStateSyncIntent retrieves the record of the latest state-sync commits,
+// or nil if there is none.
+func ReadBorStateSyncIntent(db ethdb.KeyValueReader) *BorStateSyncIntent {
+	data, _ := db.Get(borStateSyncIntentKey)
+	if len(data) == 0 {
+		return nil
+	}
+
+	intent := new(BorStateSyncIntent)
+	if err := rlp.DecodeBytes(data, intent); err != nil {
+		log.Error("Invalid bor state-sync intent RLP", "err", err)
+		return nil
+	}
+
+	return intent
+}
+
+// WriteBorStateSyncIntent stores the record of the latest state-sync commits.
+func WriteBorStateSyncIntent(db ethdb.KeyValueWriter, intent *BorStateSyncIntent) {
+	bytes, err := rlp.EncodeToBytes(intent)
+	if err != nil {
+		log.Crit("Failed to encode bor state-sync intent", "err", err)
+	}
+
+	if err := db.Put(borStateSyncIntentKey, bytes); err != nil {
+		log.Crit("Failed to store bor state-sync intent", "err", err)
+	}
+}
+
+// DeleteBorStateSyncIntent removes the record of the latest state-sync commits.
+func DeleteBorStateSyncIntent(db ethdb.KeyValueWriter) {
+	if err := db.Delete(borStateSyncIntentKey); err != nil {
+		log.Crit("Failed to delete bor state-sync intent", "err", err)
+	}
+}
@@ -0,0 +1,118 @@
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that state-sync events can be stored and retrieved by block and by id.
+func TestBorStateSyncEventStorage(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+
+	var (
+		number = uint64(16)
+		hash   = common.Hash{0x01}
+		events = []*types.StateSyncData{
+			{ID: 1, Contract: common.Address{0x11}, Data: "0102", TxHash: common.Hash{0x21}},
+			{ID: 2, Contract: common.Address{0x12}, Data: "0304", TxHash: common.Hash{0x22}},
+		}
+	)
+
+	require.False(t, HasBorStateSyncEvents(db, hash, number))
+	require.Nil(t, ReadBorStateSyncEvents(db, hash, number))
+
+	WriteCanonicalHash(db, hash, number)
+	WriteBorStateSyncEvents(db, hash, number, events)
+
+	require.True(t, HasBorStateSyncEvents(db, hash, number))
+	require.Equal(t, events, ReadBorStateSyncEvents(db, hash, number))
+
+	// Events are not found by id until the block is marked canonical
+	event, _, _ := ReadBorStateSyncEvent(db, 2)
+	require.Nil(t, event)
+
+	WriteBorStateSyncLookupEntries(db, number, events)
+
+	var (
+		blockHash   common.Hash
+		blockNumber uint64
+	)
+
+	event, blockHash, blockNumber = ReadBorStateSyncEvent(db, 2)
+	require.Equal(t, events[1], event)
+	require.Equal(t, hash, blockHash)
+	require.Equal(t, number, blockNumber)
+
+	// Unknown ids are not found
+	event, _, _ = ReadBorStateSyncEvent(db, 3)
+	require.Nil(t, event)
+
+	// Events committed in a non-canonical block are not found by id
+	WriteCanonicalHash(db, common.Hash{0x02}, number)
+
+	event, _, _ = ReadBorStateSyncEvent(db, 1)
+	require.Nil(t, event)
+
+	DeleteBorStateSyncEvents(db, hash, number)
+	require.False(t, HasBorStateSyncEvents(db, hash, number))
+	require.Nil(t, ReadBorStateSyncEvents(db, hash, number))
+}
+
+// Tests that sprint blocks without events are still marked as indexed, and that
+// deleting a block removes its state-sync events.
+func TestBorStateSyncEmptyAndDeleteBlock(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+
+	var (
+		number = uint64(32)
+		hash   = common.Hash{0x03}
+	)
+
+	WriteBorStateSyncEvents(db, hash, number, nil)
+	require.True(t, HasBorStateSyncEvents(db, hash, number))
+	require.Empty(t, ReadBorStateSyncEvents(db, hash, number))
+
+	DeleteBlock(db, hash, number)
+	require.False(t, HasBorStateSyncEvents(db, hash, number))
+}
+
+// Tests the storage of the last state-sync id committed on the canonical chain.
+func TestBorLastStateSyncID(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+	require.Nil(t, ReadBorLastStateSyncID(db))
+
+	WriteBorLastStateSyncID(db, 42)
+	require.Equal(t, uint64(42), *ReadBorLastStateSyncID(db))
+
+	WriteBorLastStateSyncID(db, 43)
+	require.Equal(t, uint64(43), *ReadBorLastStateSyncID(db))
+}
+
+// Tests the storage of the write-ahead record of state-sync commits.
+func TestBorStateSyncIntent(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+	require.Nil(t, ReadBorStateSyncIntent(db))
+
+	intent := &BorStateSyncIntent{Number: 16, Hash: common.Hash{0x01}, FromID: 5, ToID: 7}
+	WriteBorStateSyncIntent(db, intent)
+	require.Equal(t, intent, ReadBorStateSyncIntent(db))
+
+	intent.Applied, intent.Done = 3, true
+	WriteBorStateSyncIntent(db, intent)
+	require.Equal(t, intent, ReadBorStateSyncIntent(db))
+
+	DeleteBorStateSyncIntent(db)
+	require.Nil(t, ReadBorStateSyncIntent(db))
+}
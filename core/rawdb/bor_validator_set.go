@@ -0,0 +1,38 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// borValidatorSetPrefix + num (uint64 big endian) + hash -> validator set taking over after the sprint end block
+var borValidatorSetPrefix = []byte("matic-bor-validator-set-")
+
+// borValidatorSetKey = borValidatorSetPrefix + num (uint64 big endian) + hash
+func borValidatorSetKey(number uint64, hash common.Hash) []byte {
+	return append(append(borValidatorSetPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// ReadBorValidatorSet retrieves the encoded validator set archived for the
+// given sprint end block.
+func ReadBorValidatorSet(db ethdb.KeyValueReader, hash common.Hash, number uint64) []byte {
+	data, _ := db.Get(borValidatorSetKey(number, hash))
+	return data
+}
+
+// WriteBorValidatorSet archives the encoded validator set taking over after the
+// given sprint end block.
+func WriteBorValidatorSet(db ethdb.KeyValueWriter, hash common.Hash, number uint64, validators []byte) {
+	if err := db.Put(borValidatorSetKey(number, hash), validators); err != nil {
+		log.Crit("Failed to store bor validator set", "err", err)
+	}
+}
+
+// DeleteBorValidatorSet removes the validator set archived for a sprint end
+// block.
+func DeleteBorValidatorSet(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(borValidatorSetKey(number, hash)); err != nil {
+		log.Crit("Failed to delete bor validator set", "err", err)
+	}
+}
@@ -0,0 +1,34 @@
+package rawdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Tests that archived validator sets are stored per sprint end block.
+func TestBorValidatorSetStorage(t *testing.T) {
+	t.Parallel()
+
+	db := NewMemoryDatabase()
+
+	var (
+		number = uint64(15)
+		hash   = common.Hash{0x01}
+		fork   = common.Hash{0x02}
+	)
+
+	require.Nil(t, ReadBorValidatorSet(db, hash, number))
+
+	WriteBorValidatorSet(db, hash, number, []byte("canonical"))
+	WriteBorValidatorSet(db, fork, number, []byte("fork"))
+
+	require.Equal(t, []byte("canonical"), ReadBorValidatorSet(db, hash, number))
+	require.Equal(t, []byte("fork"), ReadBorValidatorSet(db, fork, number))
+
+	DeleteBorValidatorSet(db, fork, number)
+	require.Nil(t, ReadBorValidatorSet(db, fork, number))
+	require.Equal(t, []byte("canonical"), ReadBorValidatorSet(db, hash, number))
+}
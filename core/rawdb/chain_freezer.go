@@ -56,13 +56,35 @@ func NewChainFreezer(datadir string, namespace string, readonly bool, offset uin
 	return NewFreezer(datadir, namespace, readonly, offset, freezerTableSize, chainFreezerNoSnappy)
 }
 
+// MigrateChainFreezerCodec rewrites the codec selectable tables of the chain
+// freezer in the given ancient directory with the given compression codec.
+// The freezer must not be opened by anyone else during the migration.
+func MigrateChainFreezerCodec(ancient string, codec FreezerCodec) error {
+	freezer, err := NewChainFreezer(resolveChainFreezerDir(ancient), "", false, 0)
+	if err != nil {
+		return err
+	}
+	defer freezer.Close()
+
+	for _, table := range chainFreezerCodecTables {
+		if err := freezer.MigrateTableCodec(table, codec); err != nil {
+			return fmt.Errorf("failed to migrate table %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
 // newChainFreezer initializes the freezer for ancient chain segment.
 //
 //   - if the empty directory is given, initializes the pure in-memory
 //     state freezer (e.g. dev mode).
 //   - if non-empty directory is given, initializes the regular file-based
 //     state freezer.
-func newChainFreezer(datadir string, namespace string, readonly bool, offset uint64) (*chainFreezer, error) {
+//
+// The codecs select the compression codec of newly created tables, nil leaves
+// the tables on the codec they were created with.
+func newChainFreezer(datadir string, namespace string, readonly bool, offset uint64, codecs map[string]FreezerCodec) (*chainFreezer, error) {
 	var (
 		err     error
 		freezer ethdb.AncientStore
@@ -71,7 +93,7 @@ func newChainFreezer(datadir string, namespace string, readonly bool, offset uin
 	if datadir == "" {
 		freezer = NewMemoryFreezer(readonly, chainFreezerNoSnappy)
 	} else {
-		freezer, err = NewFreezer(datadir, namespace, readonly, offset, freezerTableSize, chainFreezerNoSnappy)
+		freezer, err = newFreezer(datadir, namespace, readonly, offset, freezerTableSize, chainFreezerNoSnappy, codecs)
 	}
 
 	if err != nil {
@@ -264,9 +264,15 @@ func resolveOffset(db ethdb.KeyValueStore, isLastOffset bool) uint64 {
 // NewDatabaseWithFreezer creates a high level database on top of a given key-
 // value data store with a freezer moving immutable chain segments into cold
 // storage.
+func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace string, readonly, disableFreeze, isLastOffset bool) (ethdb.Database, error) {
+	return newDatabaseWithFreezer(db, ancient, namespace, readonly, disableFreeze, isLastOffset, nil)
+}
+
+// newDatabaseWithFreezer creates a high level database on top of a given key-
+// value data store with a freezer using the given codecs for new tables.
 //
 //nolint:gocognit
-func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace string, readonly, disableFreeze, isLastOffset bool) (ethdb.Database, error) {
+func newDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace string, readonly, disableFreeze, isLastOffset bool, codecs map[string]FreezerCodec) (ethdb.Database, error) {
 	offset := resolveOffset(db, isLastOffset)
 	log.Info("Resolving ancient pruner offset", "isLastOffset", isLastOffset, "offset", offset)
 
@@ -279,7 +285,7 @@ func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace st
 	}
 
 	// Create the idle freezer instance
-	frdb, err := newChainFreezer(chainFreezerDir, namespace, readonly, offset)
+	frdb, err := newChainFreezer(chainFreezerDir, namespace, readonly, offset, codecs)
 	if err != nil {
 		return nil, err
 	}
@@ -493,6 +499,10 @@ type OpenOptions struct {
 	DisableFreeze bool
 	IsLastOffset  bool
 
+	// FreezerCodec is the compression codec of newly created chain freezer
+	// tables ("snappy" | "zstd"), empty keeps the codec of existing tables.
+	FreezerCodec string
+
 	// Ephemeral means that filesystem sync operations should be avoided: data integrity in the face of
 	// a crash is not important. This option should typically be used in tests.
 	Ephemeral bool
@@ -544,7 +554,19 @@ func Open(o OpenOptions) (ethdb.Database, error) {
 		return kvdb, nil
 	}
 
-	frdb, err := NewDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly, o.DisableFreeze, o.IsLastOffset)
+	var codecs map[string]FreezerCodec
+
+	if o.FreezerCodec != "" {
+		codec, err := ParseFreezerCodec(o.FreezerCodec)
+		if err != nil {
+			kvdb.Close()
+			return nil, err
+		}
+
+		codecs = codec.codecTables(chainFreezerNoSnappy)
+	}
+
+	frdb, err := newDatabaseWithFreezer(kvdb, o.AncientsDirectory, o.Namespace, o.ReadOnly, o.DisableFreeze, o.IsLastOffset, codecs)
 	if err != nil {
 		kvdb.Close()
 		return nil, err
@@ -653,6 +675,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		bloomBits       stat
 		beaconHeaders   stat
 		cliqueSnaps     stat
+		borStateSyncs   stat
+		borStateLookups stat
+		borValSets      stat
 
 		// Les statistic
 		chtTrieNodes   stat
@@ -717,6 +742,12 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 			beaconHeaders.Add(size)
 		case bytes.HasPrefix(key, CliqueSnapshotPrefix) && len(key) == 7+common.HashLength:
 			cliqueSnaps.Add(size)
+		case bytes.HasPrefix(key, borStateSyncPrefix) && len(key) == (len(borStateSyncPrefix)+8+common.HashLength):
+			borStateSyncs.Add(size)
+		case bytes.HasPrefix(key, borStateSyncLookupPrefix) && len(key) == (len(borStateSyncLookupPrefix)+8):
+			borStateLookups.Add(size)
+		case bytes.HasPrefix(key, borValidatorSetPrefix) && len(key) == (len(borValidatorSetPrefix)+8+common.HashLength):
+			borValSets.Add(size)
 		case bytes.HasPrefix(key, ChtTablePrefix) ||
 			bytes.HasPrefix(key, ChtIndexTablePrefix) ||
 			bytes.HasPrefix(key, ChtPrefix): // Canonical hash trie
@@ -775,6 +806,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
 		{"Key-Value store", "Beacon sync headers", beaconHeaders.Size(), beaconHeaders.Count()},
 		{"Key-Value store", "Clique snapshots", cliqueSnaps.Size(), cliqueSnaps.Count()},
+		{"Key-Value store", "Bor state-sync events", borStateSyncs.Size(), borStateSyncs.Count()},
+		{"Key-Value store", "Bor state-sync index", borStateLookups.Size(), borStateLookups.Count()},
+		{"Key-Value store", "Bor validator sets", borValSets.Size(), borValSets.Count()},
 		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
 		{"Light client", "CHT trie nodes", chtTrieNodes.Size(), chtTrieNodes.Count()},
 		{"Light client", "Bloom trie nodes", bloomTrieNodes.Size(), bloomTrieNodes.Count()},
@@ -787,12 +821,17 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 
 	for _, ancient := range ancients {
 		for _, table := range ancient.sizes {
+			//nolint: staticcheck
+			category := strings.Title(table.name)
+			if table.codec != "" {
+				category = fmt.Sprintf("%s (%s)", category, table.codec)
+			}
+
 			stats = append(stats, []string{
 				fmt.Sprintf("Ancient store (%s)",
 					//nolint: staticcheck
 					strings.Title(ancient.name)),
-				//nolint: staticcheck
-				strings.Title(table.name),
+				category,
 				table.size.String(),
 				fmt.Sprintf("%d", ancient.count()),
 			})
@@ -814,6 +853,50 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 	return nil
 }
 
+// InspectChainFreezerCodecs benchmarks the freezer codecs on the most recent
+// items of the chain freezer in the given ancient directory, to help operators
+// decide whether a codec migration is worth it.
+func InspectChainFreezerCodecs(ancient string) error {
+	freezer, err := NewChainFreezer(resolveChainFreezerDir(ancient), "", true, 0)
+	if err != nil {
+		return err
+	}
+	defer freezer.Close()
+
+	benches, err := benchmarkCodecs(freezer)
+	if err != nil {
+		return err
+	}
+
+	if len(benches) == 0 {
+		log.Info("No ancient items to benchmark", "ancient", ancient)
+		return nil
+	}
+
+	var rows [][]string
+
+	for _, bench := range benches {
+		rows = append(rows, []string{
+			//nolint: staticcheck
+			strings.Title(bench.table),
+			bench.codec.String(),
+			bench.size.String(),
+			fmt.Sprintf("%.2f", bench.ratio()),
+			common.PrettyDuration(bench.encode).String(),
+			common.PrettyDuration(bench.decode).String(),
+		})
+	}
+
+	fmt.Printf("Freezer codec benchmark (last %d items)\n", codecBenchItems)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Category", "Codec", "Size", "Ratio", "Encode", "Decode"})
+	table.AppendBulk(rows)
+	table.Render()
+
+	return nil
+}
+
 // printChainMetadata prints out chain metadata to stderr.
 func printChainMetadata(db ethdb.KeyValueStore) {
 	fmt.Fprintf(os.Stderr, "Chain metadata\n")
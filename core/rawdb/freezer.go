@@ -85,6 +85,13 @@ type Freezer struct {
 // The 'tables' argument defines the data tables. If the value of a map
 // entry is true, snappy compression is disabled for the table.
 func NewFreezer(datadir string, namespace string, readonly bool, offset uint64, maxTableSize uint32, tables map[string]bool) (*Freezer, error) {
+	return newFreezer(datadir, namespace, readonly, offset, maxTableSize, tables, nil)
+}
+
+// newFreezer creates a freezer instance like NewFreezer, using the given
+// compression codecs for newly created tables. Tables without a codec keep the
+// codec they were created with.
+func newFreezer(datadir string, namespace string, readonly bool, offset uint64, maxTableSize uint32, tables map[string]bool, codecs map[string]FreezerCodec) (*Freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -125,6 +132,11 @@ func NewFreezer(datadir string, namespace string, readonly bool, offset uint64,
 	// Create the tables.
 	for name, disableSnappy := range tables {
 		table, err := newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, disableSnappy, readonly)
+		if codec, ok := codecs[name]; ok && err == nil {
+			if err = table.initCodec(codec); err != nil {
+				table.Close()
+			}
+		}
 		if err != nil {
 			for _, table := range freezer.tables {
 				table.Close()
@@ -425,6 +437,49 @@ func (f *Freezer) MigrateTable(kind string, convert convertLegacyFn) error {
 	if !ok {
 		return errUnknownTable
 	}
+	return f.migrateTable(table, table.codec, convert)
+}
+
+// MigrateTableCodec rewrites the entries of a given compressed table with the
+// given compression codec, reopening the table afterwards.
+func (f *Freezer) MigrateTableCodec(kind string, codec FreezerCodec) error {
+	if f.readonly {
+		return errReadOnly
+	}
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	table, ok := f.tables[kind]
+	if !ok {
+		return errUnknownTable
+	}
+	if table.noCompression || table.codec == codec {
+		return nil
+	}
+	log.Info("Migrating freezer table codec", "table", kind, "have", table.codec, "want", codec)
+
+	if err := f.migrateTable(table, codec, func(blob []byte) ([]byte, error) { return blob, nil }); err != nil {
+		return err
+	}
+	// The old table only holds released file descriptors at this point, closing
+	// it is best effort.
+	path := filepath.Dir(table.index.Name())
+	table.Close()
+
+	migrated, err := newTable(path, kind, table.readMeter, table.writeMeter, table.sizeGauge, table.maxFileSize, table.noCompression, false)
+	if err != nil {
+		return err
+	}
+	f.tables[kind] = migrated
+	f.writeBatch = newFreezerBatch(f)
+
+	return nil
+}
+
+// migrateTable rewrites the entries of the given table into a new table using
+// the given codec, converting them with the given function.
+func (f *Freezer) migrateTable(table *freezerTable, codec FreezerCodec, convert convertLegacyFn) error {
+	kind := table.name
 	// forEach iterates every entry in the table serially and in order, calling `fn`
 	// with the item as argument. If `fn` returns an error the iteration stops
 	// and that error will be returned.
@@ -464,6 +519,9 @@ func (f *Freezer) MigrateTable(kind string, convert convertLegacyFn) error {
 	if err != nil {
 		return err
 	}
+	if err := newTable.initCodec(codec); err != nil {
+		return err
+	}
 	var (
 		batch  = newTable.newBatch(f.offset.Load())
 		out    []byte
@@ -22,7 +22,6 @@ import (
 
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/golang/snappy"
 )
 
 // This is the maximum amount of data that will be buffered in memory
@@ -89,7 +88,7 @@ func (batch *freezerBatch) commit() (item uint64, writeSize int64, err error) {
 type freezerTableBatch struct {
 	t *freezerTable
 
-	sb          *snappyBuffer
+	cb          *compressBuffer
 	encBuffer   writeBuffer
 	dataBuffer  []byte
 	indexBuffer []byte
@@ -102,7 +101,7 @@ type freezerTableBatch struct {
 func (t *freezerTable) newBatch(offset uint64) *freezerTableBatch {
 	batch := &freezerTableBatch{t: t, offset: offset}
 	if !t.noCompression {
-		batch.sb = new(snappyBuffer)
+		batch.cb = &compressBuffer{codec: t.codec}
 	}
 
 	batch.reset()
@@ -135,8 +134,8 @@ func (batch *freezerTableBatch) Append(item uint64, data interface{}) error {
 	}
 
 	encItem := batch.encBuffer.data
-	if batch.sb != nil {
-		encItem = batch.sb.compress(encItem)
+	if batch.cb != nil {
+		encItem = batch.cb.compress(encItem)
 	}
 
 	return batch.appendItem(encItem)
@@ -151,8 +150,8 @@ func (batch *freezerTableBatch) AppendRaw(item uint64, blob []byte) error {
 	}
 
 	encItem := blob
-	if batch.sb != nil {
-		encItem = batch.sb.compress(blob)
+	if batch.cb != nil {
+		encItem = batch.cb.compress(blob)
 	}
 
 	return batch.appendItem(encItem)
@@ -235,28 +234,16 @@ func (batch *freezerTableBatch) commit() error {
 	return nil
 }
 
-// snappyBuffer writes snappy in block format, and can be reused. It is
-// reset when WriteTo is called.
-type snappyBuffer struct {
-	dst []byte
+// compressBuffer compresses items with the codec of the table, and can be
+// reused. It is reset when WriteTo is called.
+type compressBuffer struct {
+	codec FreezerCodec
+	dst   []byte
 }
 
-// compress snappy-compresses the data.
-func (s *snappyBuffer) compress(data []byte) []byte {
-	// The snappy library does not care what the capacity of the buffer is,
-	// but only checks the length. If the length is too small, it will
-	// allocate a brand new buffer.
-	// To avoid that, we check the required size here, and grow the size of the
-	// buffer to utilize the full capacity.
-	if n := snappy.MaxEncodedLen(len(data)); len(s.dst) < n {
-		if cap(s.dst) < n {
-			s.dst = make([]byte, n)
-		}
-
-		s.dst = s.dst[:n]
-	}
-
-	s.dst = snappy.Encode(s.dst, data)
+// compress compresses the data.
+func (s *compressBuffer) compress(data []byte) []byte {
+	s.dst = s.codec.encode(s.dst, data)
 
 	return s.dst
 }
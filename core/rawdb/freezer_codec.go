@@ -0,0 +1,175 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// FreezerCodec identifies the compression algorithm of a compressed freezer
+// table. The codec is recorded in the table metadata when the table is created,
+// and never changes afterwards unless the table is migrated.
+type FreezerCodec uint8
+
+const (
+	// FreezerCodecSnappy compresses items with snappy. It's the default codec,
+	// and the codec of all tables created before codecs were selectable.
+	FreezerCodecSnappy FreezerCodec = iota
+
+	// FreezerCodecZstd compresses items with zstd, trading CPU time for a
+	// considerably smaller disk footprint.
+	FreezerCodecZstd
+)
+
+// errUnknownFreezerCodec is returned if a freezer codec is not supported.
+var errUnknownFreezerCodec = errors.New("unknown freezer codec")
+
+// chainFreezerCodecTables lists the chain freezer tables whose codec can be
+// selected. All other compressed tables always use snappy.
+var chainFreezerCodecTables = []string{
+	ChainFreezerBodiesTable,
+	ChainFreezerReceiptTable,
+	freezerBorReceiptTable,
+}
+
+// ParseFreezerCodec parses the name of a freezer codec. The empty name selects
+// the default codec.
+func ParseFreezerCodec(name string) (FreezerCodec, error) {
+	switch name {
+	case "", "snappy":
+		return FreezerCodecSnappy, nil
+	case "zstd":
+		return FreezerCodecZstd, nil
+	default:
+		return 0, fmt.Errorf("%w: %q, supported ones: snappy, zstd", errUnknownFreezerCodec, name)
+	}
+}
+
+// String implements fmt.Stringer.
+func (c FreezerCodec) String() string {
+	switch c {
+	case FreezerCodecSnappy:
+		return "snappy"
+	case FreezerCodecZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+// codecTables returns the codec of new tables for the given freezer tables.
+func (c FreezerCodec) codecTables(tables map[string]bool) map[string]FreezerCodec {
+	codecs := make(map[string]FreezerCodec)
+
+	for _, name := range chainFreezerCodecTables {
+		if _, ok := tables[name]; ok {
+			codecs[name] = c
+		}
+	}
+
+	return codecs
+}
+
+var (
+	zstdEncoder     *zstd.Encoder
+	zstdDecoder     *zstd.Decoder
+	zstdEncoderOnce sync.Once
+	zstdDecoderOnce sync.Once
+)
+
+// getZstdEncoder returns the shared zstd encoder, which is safe for concurrent
+// use through EncodeAll.
+func getZstdEncoder() *zstd.Encoder {
+	zstdEncoderOnce.Do(func() {
+		zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithZeroFrames(true))
+	})
+
+	return zstdEncoder
+}
+
+// getZstdDecoder returns the shared zstd decoder, which is safe for concurrent
+// use through DecodeAll.
+func getZstdDecoder() *zstd.Decoder {
+	zstdDecoderOnce.Do(func() {
+		zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	})
+
+	return zstdDecoder
+}
+
+// encode compresses src, reusing the capacity of dst if possible.
+func (c FreezerCodec) encode(dst, src []byte) []byte {
+	switch c {
+	case FreezerCodecZstd:
+		return getZstdEncoder().EncodeAll(src, dst[:0])
+	default:
+		// The snappy library does not care what the capacity of the buffer is,
+		// but only checks the length. If the length is too small, it will
+		// allocate a brand new buffer.
+		// To avoid that, we check the required size here, and grow the size of the
+		// buffer to utilize the full capacity.
+		if n := snappy.MaxEncodedLen(len(src)); len(dst) < n {
+			if cap(dst) < n {
+				dst = make([]byte, n)
+			}
+
+			dst = dst[:n]
+		}
+
+		return snappy.Encode(dst, src)
+	}
+}
+
+// decode decompresses src into a newly allocated buffer.
+func (c FreezerCodec) decode(src []byte) ([]byte, error) {
+	switch c {
+	case FreezerCodecSnappy:
+		return snappy.Decode(nil, src)
+	case FreezerCodecZstd:
+		return getZstdDecoder().DecodeAll(src, nil)
+	default:
+		return nil, errUnknownFreezerCodec
+	}
+}
+
+// decodedLen returns the length of the decompressed src.
+func (c FreezerCodec) decodedLen(src []byte) (int, error) {
+	switch c {
+	case FreezerCodecSnappy:
+		return snappy.DecodedLen(src)
+	case FreezerCodecZstd:
+		var header zstd.Header
+		if err := header.Decode(src); err != nil {
+			return 0, err
+		}
+
+		// Empty items are encoded without the content size
+		if !header.HasFCS {
+			data, err := c.decode(src)
+			return len(data), err
+		}
+
+		return int(header.FrameContentSize), nil
+	default:
+		return 0, errUnknownFreezerCodec
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/stretchr/testify/require"
+)
+
+var freezerCodecTestTableDef = map[string]bool{"test": false}
+
+// codecTestItem returns a compressible test item.
+func codecTestItem(i int) []byte {
+	return bytes.Repeat([]byte(fmt.Sprintf("item-%d,", i)), 64)
+}
+
+func TestFreezerCodecRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, codec := range []FreezerCodec{FreezerCodecSnappy, FreezerCodecZstd} {
+		for _, item := range [][]byte{{}, {0x1}, codecTestItem(1)} {
+			blob := codec.encode(nil, item)
+
+			size, err := codec.decodedLen(blob)
+			require.NoError(t, err)
+			require.Equal(t, len(item), size, codec)
+
+			data, err := codec.decode(blob)
+			require.NoError(t, err)
+			require.True(t, bytes.Equal(item, data), codec)
+		}
+	}
+}
+
+func TestParseFreezerCodec(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]FreezerCodec{"": FreezerCodecSnappy, "snappy": FreezerCodecSnappy, "zstd": FreezerCodecZstd} {
+		codec, err := ParseFreezerCodec(name)
+		require.NoError(t, err)
+		require.Equal(t, want, codec)
+	}
+
+	_, err := ParseFreezerCodec("lz4")
+	require.ErrorIs(t, err, errUnknownFreezerCodec)
+}
+
+// writeCodecTestItems appends n test items to the test table of the freezer.
+func writeCodecTestItems(t *testing.T, f *Freezer, n int) {
+	t.Helper()
+
+	_, err := f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for i := 0; i < n; i++ {
+			if err := op.AppendRaw("test", uint64(i), codecTestItem(i)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+// checkCodecTestItems checks that the freezer holds n test items, compressed
+// with the given codec.
+func checkCodecTestItems(t *testing.T, f *Freezer, n int, codec FreezerCodec) {
+	t.Helper()
+
+	require.Equal(t, codec, f.tables["test"].codec)
+
+	for i := 0; i < n; i++ {
+		item, err := f.Ancient("test", uint64(i))
+		require.NoError(t, err)
+		require.Equal(t, codecTestItem(i), item)
+	}
+}
+
+func TestFreezerCodecSelection(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	zstd := map[string]FreezerCodec{"test": FreezerCodecZstd}
+
+	f, err := newFreezer(dir, "", false, 0, 2049, freezerCodecTestTableDef, zstd)
+	require.NoError(t, err)
+	writeCodecTestItems(t, f, 10)
+	checkCodecTestItems(t, f, 10, FreezerCodecZstd)
+	require.NoError(t, f.Close())
+
+	// Reopening without a codec keeps the codec of the table
+	f, err = NewFreezer(dir, "", false, 0, 2049, freezerCodecTestTableDef)
+	require.NoError(t, err)
+	checkCodecTestItems(t, f, 10, FreezerCodecZstd)
+	require.NoError(t, f.Close())
+
+	// Selecting a different codec doesn't touch a non-empty table
+	snappy := map[string]FreezerCodec{"test": FreezerCodecSnappy}
+
+	f, err = newFreezer(dir, "", false, 0, 2049, freezerCodecTestTableDef, snappy)
+	require.NoError(t, err)
+	checkCodecTestItems(t, f, 10, FreezerCodecZstd)
+	require.NoError(t, f.Close())
+
+	codec, err := readTableCodec(dir, "test")
+	require.NoError(t, err)
+	require.Equal(t, FreezerCodecZstd, codec)
+}
+
+func TestFreezerMigrateTableCodec(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	f, err := NewFreezer(dir, "", false, 0, 2049, freezerCodecTestTableDef)
+	require.NoError(t, err)
+	writeCodecTestItems(t, f, 100)
+	require.NoError(t, f.MigrateTableCodec("test", FreezerCodecZstd))
+	require.NoError(t, f.Close())
+
+	f, err = NewFreezer(dir, "", false, 0, 2049, freezerCodecTestTableDef)
+	require.NoError(t, err)
+	checkCodecTestItems(t, f, 100, FreezerCodecZstd)
+
+	// The table keeps accepting items with the migrated codec
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		return op.AppendRaw("test", 100, codecTestItem(100))
+	})
+	require.NoError(t, err)
+	checkCodecTestItems(t, f, 101, FreezerCodecZstd)
+	require.NoError(t, f.Close())
+}
+
+func BenchmarkFreezerCodec(b *testing.B) {
+	item := codecTestItem(1)
+
+	for _, codec := range []FreezerCodec{FreezerCodecSnappy, FreezerCodecZstd} {
+		blob := codec.encode(nil, item)
+
+		b.Run(fmt.Sprintf("encode/%s", codec), func(b *testing.B) {
+			var buf []byte
+
+			b.SetBytes(int64(len(item)))
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				buf = codec.encode(buf, item)
+			}
+		})
+		b.Run(fmt.Sprintf("decode/%s", codec), func(b *testing.B) {
+			b.SetBytes(int64(len(item)))
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := codec.decode(blob); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
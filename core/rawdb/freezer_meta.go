@@ -36,6 +36,10 @@ type freezerTableMeta struct {
 	// plus the number of items hidden in the table, so it should never
 	// be lower than the "actual tail".
 	VirtualTail uint64
+
+	// Codec is the compression codec of the table items. It's ignored for
+	// tables without compression.
+	Codec FreezerCodec `rlp:"optional"`
 }
 
 // newMetadata initializes the metadata object with the given virtual tail.
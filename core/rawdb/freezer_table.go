@@ -30,7 +30,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
-	"github.com/golang/snappy"
 )
 
 var (
@@ -100,7 +99,8 @@ type freezerTable struct {
 	// should never be lower than itemOffset.
 	itemHidden atomic.Uint64
 
-	noCompression bool // if true, disables snappy compression. Note: does not work retroactively
+	noCompression bool         // if true, disables compression. Note: does not work retroactively
+	codec         FreezerCodec // compression codec of the items, read from the metadata
 	readonly      bool
 	maxFileSize   uint32 // Max file size for data-files
 	name          string
@@ -127,6 +127,34 @@ func newFreezerTable(path, name string, disableSnappy, readonly bool) (*freezerT
 	return newTable(path, name, metrics.NilMeter{}, metrics.NilMeter{}, metrics.NilGauge{}, freezerTableSize, disableSnappy, readonly)
 }
 
+// initCodec sets the compression codec of the table, if the table doesn't hold
+// any items yet. The codec of tables which already hold items can only be
+// changed by migrating them.
+func (t *freezerTable) initCodec(codec FreezerCodec) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.noCompression || t.codec == codec {
+		return nil
+	}
+
+	if t.readonly || t.items.Load() > t.itemHidden.Load() {
+		t.logger.Warn("Freezer table codec differs from the configured one, migrate to apply", "have", t.codec, "want", codec)
+		return nil
+	}
+
+	meta := newMetadata(t.itemHidden.Load())
+	meta.Codec = codec
+
+	if err := writeMetadata(t.meta, meta); err != nil {
+		return err
+	}
+
+	t.codec = codec
+
+	return t.meta.Sync()
+}
+
 // newTable opens a freezer table, creating the data and index files if they are
 // non-existent. Both files are truncated to the shortest common length to ensure
 // they don't go out of sync.
@@ -262,6 +290,7 @@ func (t *freezerTable) repair() error {
 	}
 
 	t.itemHidden.Store(meta.VirtualTail)
+	t.codec = meta.Codec
 
 	// Read the last index, use the default value in case the freezer is empty
 	if offsetsSize == indexEntrySize {
@@ -557,7 +586,10 @@ func (t *freezerTable) truncateTail(items uint64) error {
 	// Update the virtual tail marker and hidden these entries in table.
 	t.itemHidden.Store(items)
 
-	if err := writeMetadata(t.meta, newMetadata(items)); err != nil {
+	meta := newMetadata(items)
+	meta.Codec = t.codec
+
+	if err := writeMetadata(t.meta, meta); err != nil {
 		return err
 	}
 	// Hidden items still fall in the current tail file, no data file
@@ -817,14 +849,14 @@ func (t *freezerTable) RetrieveItems(start, count, maxBytes uint64) ([][]byte, e
 
 		decompressedSize := diskSize
 		if !t.noCompression {
-			decompressedSize, _ = snappy.DecodedLen(item)
+			decompressedSize, _ = t.codec.decodedLen(item)
 		}
 		if i > 0 && maxBytes != 0 && uint64(outputSize+decompressedSize) > maxBytes {
 			break
 		}
 
 		if !t.noCompression {
-			data, err := snappy.Decode(nil, item)
+			data, err := t.codec.decode(item)
 			if err != nil {
 				return nil, err
 			}
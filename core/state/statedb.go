@@ -177,6 +177,10 @@ type StateDB struct {
 	// Bor metrics
 	BorConsensusTime time.Duration
 
+	// BorStateSyncData holds the state-sync events committed into this state
+	// by the bor engine at a sprint-start block
+	BorStateSyncData []*types.StateSyncData
+
 	AccountUpdated int
 	StorageUpdated atomic.Int64
 	AccountDeleted int
@@ -498,6 +502,11 @@ func (s *StateDB) SetLogger(l *tracing.Hooks) {
 	s.logger = l
 }
 
+// Logger returns the logger for account update hooks, nil if none is set.
+func (s *StateDB) Logger() *tracing.Hooks {
+	return s.logger
+}
+
 // StartPrefetcher initializes a new trie prefetcher to pull in nodes from the
 // state trie concurrently while the state is mutated so that when we reach the
 // commit phase, most of the needed data is already hot.
@@ -1153,6 +1162,8 @@ func (s *StateDB) Copy() *StateDB {
 		// miner to operate trie-backed only.
 		snaps: s.snaps,
 		snap:  s.snap,
+
+		BorStateSyncData: slices.Clone(s.BorStateSyncData),
 	}
 	if s.witness != nil {
 		state.witness = s.witness.Copy()
@@ -78,6 +78,7 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		signer  = types.MakeSigner(p.config, header.Number, header.Time)
 	)
 	context = NewEVMBlockContext(header, p.hc, nil)
+	context.FeeCurrency = cfg.FeeCurrency
 	vmenv := vm.NewEVM(context, vm.TxContext{}, statedb, p.config, cfg)
 	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
 		ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
@@ -159,29 +160,32 @@ func ApplyTransactionWithEVM(msg *Message, config *params.ChainConfig, gp *GasPo
 	// stop recording read and write
 	statedb.SetMVHashmap(nil)
 
-	if evm.ChainConfig().IsLondon(blockNumber) {
-		statedb.AddBalance(result.BurntContractAddress, cmath.BigIntToUint256Int(result.FeeBurnt), tracing.BalanceChangeTransfer)
-	}
-
-	// TODO(raneet10) Double check
-	statedb.AddBalance(evm.Context.Coinbase, cmath.BigIntToUint256Int(result.FeeTipped), tracing.BalanceChangeTransfer)
-	output1 := new(big.Int).SetBytes(result.SenderInitBalance.Bytes())
-	output2 := new(big.Int).SetBytes(coinbaseBalance.Bytes())
-
-	// Deprecating transfer log and will be removed in future fork. PLEASE DO NOT USE this transfer log going forward. Parameters won't get updated as expected going forward with EIP1559
-	// add transfer log
-	AddFeeTransferLog(
-		statedb,
-
-		msg.From,
-		evm.Context.Coinbase,
+	// Fees paid through the fee currency are settled already
+	if !result.FeeCurrency {
+		if evm.ChainConfig().IsLondon(blockNumber) {
+			statedb.AddBalance(result.BurntContractAddress, cmath.BigIntToUint256Int(result.FeeBurnt), tracing.BalanceChangeTransfer)
+		}
 
-		result.FeeTipped,
-		result.SenderInitBalance,
-		coinbaseBalance.ToBig(),
-		output1.Sub(output1, result.FeeTipped),
-		output2.Add(output2, result.FeeTipped),
-	)
+		// TODO(raneet10) Double check
+		statedb.AddBalance(evm.Context.Coinbase, cmath.BigIntToUint256Int(result.FeeTipped), tracing.BalanceChangeTransfer)
+		output1 := new(big.Int).SetBytes(result.SenderInitBalance.Bytes())
+		output2 := new(big.Int).SetBytes(coinbaseBalance.Bytes())
+
+		// Deprecating transfer log and will be removed in future fork. PLEASE DO NOT USE this transfer log going forward. Parameters won't get updated as expected going forward with EIP1559
+		// add transfer log
+		AddFeeTransferLog(
+			statedb,
+
+			msg.From,
+			evm.Context.Coinbase,
+
+			result.FeeTipped,
+			result.SenderInitBalance,
+			coinbaseBalance.ToBig(),
+			output1.Sub(output1, result.FeeTipped),
+			output2.Add(output2, result.FeeTipped),
+		)
+	}
 
 	if result.Err == vm.ErrInterrupt {
 		return nil, result.Err
@@ -43,6 +43,7 @@ type ExecutionResult struct {
 	FeeBurnt             *big.Int
 	BurntContractAddress common.Address
 	FeeTipped            *big.Int
+	FeeCurrency          bool // Whether the fees were paid through the fee currency, rather than left to the caller
 }
 
 // Unwrap returns the internal evm error which allows us for further
@@ -238,6 +239,7 @@ type StateTransition struct {
 	initialGas   uint64
 	state        vm.StateDB
 	evm          *vm.EVM
+	feeCurrency  vm.FeeCurrency // Fee currency paying the fees, nil if paid in the native token
 
 	// If true, fee burning and tipping won't happen during transition. Instead, their values will be included in the
 	// ExecutionResult, which caller can use the values to update the balance of burner and coinbase account.
@@ -290,7 +292,21 @@ func (st *StateTransition) buyGas() error {
 	if overflow {
 		return fmt.Errorf("%w: address %v required balance exceeds 256 bits", ErrInsufficientFunds, st.msg.From.Hex())
 	}
-	if have, want := st.state.GetBalance(st.msg.From), balanceCheckU256; have.Cmp(want) < 0 {
+
+	mgvalU256, _ := uint256.FromBig(mgval)
+
+	if st.feeCurrency != nil {
+		// Only the value is left to pay in the native token
+		value := uint256.MustFromBig(st.msg.Value)
+		if have := st.state.GetBalance(st.msg.From); have.Cmp(value) < 0 {
+			return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, value)
+		}
+
+		maxFee := new(uint256.Int).Sub(balanceCheckU256, value)
+		if err := st.feeCurrency.BuyGas(st.evm, st.msg.From, mgvalU256, maxFee); err != nil {
+			return err
+		}
+	} else if have, want := st.state.GetBalance(st.msg.From), balanceCheckU256; have.Cmp(want) < 0 {
 		return fmt.Errorf("%w: address %v have %v want %v", ErrInsufficientFunds, st.msg.From.Hex(), have, want)
 	}
 
@@ -304,8 +320,10 @@ func (st *StateTransition) buyGas() error {
 	st.gasRemaining = st.msg.GasLimit
 
 	st.initialGas = st.msg.GasLimit
-	mgvalU256, _ := uint256.FromBig(mgval)
-	st.state.SubBalance(st.msg.From, mgvalU256, tracing.BalanceDecreaseGasBuy)
+
+	if st.feeCurrency == nil {
+		st.state.SubBalance(st.msg.From, mgvalU256, tracing.BalanceDecreaseGasBuy)
+	}
 	return nil
 }
 
@@ -422,6 +440,13 @@ func (st *StateTransition) TransitionDb(interruptCtx context.Context) (*Executio
 	// 5. there is no overflow when calculating intrinsic gas
 	// 6. caller has enough balance to cover asset transfer for **topmost** call
 
+	feeCurrency, err := activeFeeCurrency(st.evm)
+	if err != nil {
+		return nil, err
+	}
+
+	st.feeCurrency = feeCurrency
+
 	// Check clauses 1-3, buy gas if everything is correct
 	if err := st.preCheck(); err != nil {
 		return nil, err
@@ -527,12 +552,21 @@ func (st *StateTransition) TransitionDb(interruptCtx context.Context) (*Executio
 		burntContractAddress = common.HexToAddress(st.evm.ChainConfig().Bor.CalculateBurntContract(st.evm.Context.BlockNumber.Uint64()))
 		burnAmount = new(big.Int).Mul(new(big.Int).SetUint64(st.gasUsed()), st.evm.Context.BaseFee)
 
-		if !st.noFeeBurnAndTip {
+		if !st.noFeeBurnAndTip && st.feeCurrency == nil {
 			st.state.AddBalance(burntContractAddress, cmath.BigIntToUint256Int(burnAmount), tracing.BalanceChangeTransfer)
 		}
 	}
 
-	if !st.noFeeBurnAndTip {
+	if st.feeCurrency != nil {
+		// Paid right away even if the caller settles the native fees, as the
+		// fee currency may keep its balances anywhere in the state
+		burnt := new(uint256.Int)
+		if burnAmount != nil {
+			burnt = cmath.BigIntToUint256Int(burnAmount)
+		}
+
+		st.feeCurrency.PayFees(st.evm, msg.From, cmath.BigIntToUint256Int(amount), burntContractAddress, burnt)
+	} else if !st.noFeeBurnAndTip {
 		st.state.AddBalance(st.evm.Context.Coinbase, cmath.BigIntToUint256Int(amount), tracing.BalanceIncreaseRewardTransactionFee)
 
 		// add the coinbase to the witness iff the fee is greater than 0
@@ -568,6 +602,7 @@ func (st *StateTransition) TransitionDb(interruptCtx context.Context) (*Executio
 		FeeBurnt:             burnAmount,
 		BurntContractAddress: burntContractAddress,
 		FeeTipped:            amount,
+		FeeCurrency:          st.feeCurrency != nil,
 	}, nil
 }
 
@@ -587,7 +622,12 @@ func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
 	// Return ETH for remaining gas, exchanged at the original rate.
 	remaining := uint256.NewInt(st.gasRemaining)
 	remaining.Mul(remaining, uint256.MustFromBig(st.msg.GasPrice))
-	st.state.AddBalance(st.msg.From, remaining, tracing.BalanceIncreaseGasReturn)
+
+	if st.feeCurrency != nil {
+		st.feeCurrency.RefundGas(st.evm, st.msg.From, remaining)
+	} else {
+		st.state.AddBalance(st.msg.From, remaining, tracing.BalanceIncreaseGasReturn)
+	}
 
 	if st.evm.Config.Tracer != nil && st.evm.Config.Tracer.OnGasChange != nil && st.gasRemaining > 0 {
 		st.evm.Config.Tracer.OnGasChange(st.gasRemaining, 0, tracing.GasChangeTxLeftOverReturned)
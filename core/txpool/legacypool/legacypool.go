@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -665,6 +666,7 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 			}
 			return nil
 		},
+		FeeCurrency: pool.feeCurrency(),
 	}
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err
@@ -672,6 +674,30 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction, local bool) error {
 	return nil
 }
 
+// feeCurrency returns the fee currency paying the fees of the transactions
+// included on top of the current head, or nil if they're paid in the native
+// token.
+func (pool *LegacyPool) feeCurrency() vm.FeeCurrency {
+	bor := pool.chainconfig.Bor
+	if bor == nil || !bor.IsFeeCurrency(new(big.Int).Add(pool.currentHead.Load().Number, common.Big1)) {
+		return nil
+	}
+	if chain, ok := pool.chain.(interface{ FeeCurrency() vm.FeeCurrency }); ok {
+		return chain.FeeCurrency()
+	}
+	return nil
+}
+
+// spendable returns the funds of the account which its pooled transactions can
+// spend, counting its balance in the fee currency if the fees are paid in it.
+func (pool *LegacyPool) spendable(addr common.Address, currency vm.FeeCurrency) *uint256.Int {
+	balance := pool.currentState.GetBalance(addr)
+	if currency == nil {
+		return balance
+	}
+	return new(uint256.Int).Add(balance, currency.BalanceOf(pool.currentState, addr))
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -1467,6 +1493,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 
 	// Iterate over all accounts and promote any executable transactions
 	gasLimit := pool.currentHead.Load().GasLimit
+	currency := pool.feeCurrency()
 	for _, addr := range accounts {
 		list := pool.queue[addr]
 		if list == nil {
@@ -1480,7 +1507,7 @@ func (pool *LegacyPool) promoteExecutables(accounts []common.Address) []*types.T
 		}
 		log.Trace("Removed old queued transactions", "count", len(forwards))
 		// Drop all transactions that are too costly (low balance or out of gas)
-		drops, _ := list.Filter(pool.currentState.GetBalance(addr), gasLimit)
+		drops, _ := list.Filter(pool.spendable(addr, currency), gasLimit)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			pool.all.Remove(hash)
@@ -1670,6 +1697,7 @@ func (pool *LegacyPool) truncateQueue() {
 func (pool *LegacyPool) demoteUnexecutables() {
 	// Iterate over all accounts and demote any non-executable transactions
 	currentHeader := pool.currentHead.Load()
+	currency := pool.feeCurrency()
 	for addr, list := range pool.pending {
 		nonce := pool.currentState.GetNonce(addr)
 
@@ -1681,7 +1709,7 @@ func (pool *LegacyPool) demoteUnexecutables() {
 			log.Trace("Removed old pending transaction", "hash", hash)
 		}
 		// Drop all transactions that are too costly (low balance or out of gas), and queue any invalids back for later
-		drops, invalids := list.Filter(pool.currentState.GetBalance(addr), currentHeader.GasLimit)
+		drops, invalids := list.Filter(pool.spendable(addr, currency), currentHeader.GasLimit)
 		for _, tx := range drops {
 			hash := tx.Hash()
 			log.Trace("Removed unpayable pending transaction", "hash", hash)
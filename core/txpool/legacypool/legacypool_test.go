@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
@@ -411,6 +412,99 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+// testFeeCurrency is a fee currency whose balances are kept in the storage of
+// the token contract. Only its balances are of interest to the pool.
+type testFeeCurrency struct {
+	token common.Address
+}
+
+func (f *testFeeCurrency) BalanceOf(db vm.StateDB, account common.Address) *uint256.Int {
+	value := db.GetState(f.token, common.BytesToHash(account.Bytes()))
+	return new(uint256.Int).SetBytes(value.Bytes())
+}
+
+func (f *testFeeCurrency) BuyGas(*vm.EVM, common.Address, *uint256.Int, *uint256.Int) error {
+	return nil
+}
+
+func (f *testFeeCurrency) RefundGas(*vm.EVM, common.Address, *uint256.Int) {}
+
+func (f *testFeeCurrency) PayFees(*vm.EVM, common.Address, *uint256.Int, common.Address, *uint256.Int) {
+}
+
+// feeCurrencyBlockChain is a test chain paying the transaction fees through a
+// fee currency.
+type feeCurrencyBlockChain struct {
+	*testBlockChain
+	currency vm.FeeCurrency
+}
+
+func (bc *feeCurrencyBlockChain) FeeCurrency() vm.FeeCurrency {
+	return bc.currency
+}
+
+// Tests that the balance in the fee currency of the chain counts towards the
+// funds of the transactors, while their value is still paid in the native token.
+func TestFeeCurrencyFunds(t *testing.T) {
+	t.Parallel()
+
+	config := *params.TestChainConfig
+	config.Bor = &params.BorConfig{FeeCurrencyBlock: big.NewInt(0)}
+
+	currency := &testFeeCurrency{token: common.HexToAddress("0x000000000000000000000000000000000000fee0")}
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	blockchain := &feeCurrencyBlockChain{newTestBlockChain(&config, 10000000, statedb, new(event.Feed)), currency}
+
+	pool := New(testTxPoolConfig, blockchain)
+	if err := pool.Init(testTxPoolConfig.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to init pool: %v", err)
+	}
+	defer pool.Close()
+
+	<-pool.initDoneCh
+
+	setTokenBalance := func(addr common.Address, amount uint64) {
+		pool.mu.Lock()
+		pool.currentState.SetState(currency.token, common.BytesToHash(addr.Bytes()), uint256.NewInt(amount).Bytes32())
+		pool.mu.Unlock()
+	}
+
+	// A native balance covering the value only can't pay the fees
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	testAddBalance(pool, from, big.NewInt(100))
+
+	tx := transaction(0, 100000, key)
+	if err, want := pool.addRemoteSync(tx), core.ErrInsufficientFunds; !errors.Is(err, want) {
+		t.Fatalf("want %v have %v", want, err)
+	}
+
+	// Once the fees are covered in the fee currency, the transaction is executable
+	setTokenBalance(from, 100000)
+
+	if err := pool.addRemoteSync(tx); err != nil {
+		t.Fatalf("failed to add transaction paying its fees in the fee currency: %v", err)
+	}
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("pool stats mismatch: have %d pending, %d queued, want 1 pending, 0 queued", pending, queued)
+	}
+
+	// The value can't be paid in the fee currency though
+	key, _ = crypto.GenerateKey()
+	from = crypto.PubkeyToAddress(key.PublicKey)
+
+	setTokenBalance(from, 1000000)
+
+	if err, want := pool.addRemoteSync(transaction(0, 100000, key)), core.ErrInsufficientFunds; !errors.Is(err, want) {
+		t.Fatalf("want %v have %v", want, err)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 func TestQueue(t *testing.T) {
 	t.Parallel()
 
@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -194,6 +195,12 @@ type ValidationOptionsWithState struct {
 	// ExistingCost is a mandatory callback to retrieve an already pooled
 	// transaction's cost with the given nonce to check for overdrafts.
 	ExistingCost func(addr common.Address, nonce uint64) *big.Int
+
+	// FeeCurrency is an optional fee currency paying the fees of the transactions
+	// in place of the native token. If set, the balance in it counts towards the
+	// funds of the transactors, while their value is still to be covered by the
+	// native balance.
+	FeeCurrency vm.FeeCurrency
 }
 
 // ValidateTransactionWithState is a helper method to check whether a transaction
@@ -224,6 +231,12 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 		balance = opts.State.GetBalance(from).ToBig()
 		cost    = tx.Cost()
 	)
+	if opts.FeeCurrency != nil {
+		if balance.Cmp(tx.Value()) < 0 {
+			return fmt.Errorf("%w: balance %v, tx value %v, overshot %v", core.ErrInsufficientFunds, balance, tx.Value(), new(big.Int).Sub(tx.Value(), balance))
+		}
+		balance.Add(balance, opts.FeeCurrency.BalanceOf(opts.State, from).ToBig())
+	}
 	if balance.Cmp(cost) < 0 {
 		return fmt.Errorf("%w: balance %v, tx cost %v, overshot %v", core.ErrInsufficientFunds, balance, cost, new(big.Int).Sub(cost, balance))
 	}
@@ -113,6 +113,10 @@ type BlockExtraData struct {
 	// length of TxDependency[i]       ->   k (k = a whole number)
 	// k elements in TxDependency[i]   ->   transaction indexes on which transaction i is dependent on
 	TxDependency [][]uint64
+
+	// MilestoneNumber is the end block of the latest milestone observed by the
+	// producer, set once the milestone reference fork is active.
+	MilestoneNumber uint64 `rlp:"optional"`
 }
 
 // field type overrides for gencodec
@@ -481,6 +485,22 @@ func (h *Header) GetValidatorBytes(chainConfig *params.ChainConfig) []byte {
 	return blockExtraData.ValidatorBytes
 }
 
+// GetMilestoneNumber returns the end block of the milestone referenced by the
+// header, or 0 if it doesn't reference one.
+func (h *Header) GetMilestoneNumber(chainConfig *params.ChainConfig) uint64 {
+	if !chainConfig.IsCancun(h.Number) || len(h.Extra) < ExtraVanityLength+ExtraSealLength {
+		return 0
+	}
+
+	var blockExtraData BlockExtraData
+	if err := rlp.DecodeBytes(h.Extra[ExtraVanityLength:len(h.Extra)-ExtraSealLength], &blockExtraData); err != nil {
+		log.Debug("error while decoding block extra data", "err", err)
+		return 0
+	}
+
+	return blockExtraData.MilestoneNumber
+}
+
 func (b *Block) BaseFee() *big.Int {
 	if b.header.BaseFee == nil {
 		return nil
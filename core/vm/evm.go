@@ -86,6 +86,8 @@ type BlockContext struct {
 	BaseFee     *big.Int       // Provides information for BASEFEE (0 if vm runs with NoBaseFee flag and 0 gas price)
 	BlobBaseFee *big.Int       // Provides information for BLOBBASEFEE (0 if vm runs with NoBaseFee flag and 0 blob gas price)
 	Random      *common.Hash   // Provides information for PREVRANDAO
+
+	FeeCurrency FeeCurrency // Fee currency of the chain paying the transaction fees from its fork on, nil if none
 }
 
 // TxContext provides the EVM with information about a transaction.
@@ -105,3 +105,32 @@ type CallContext interface {
 	// Create creates a new contract
 	Create(env *EVM, me ContractRef, data []byte, gas, value *big.Int) ([]byte, common.Address, error)
 }
+
+// FeeCurrency handles the payment of the transaction fees in place of the
+// native token, e.g. charging them in an ERC-20 token through a paymaster
+// system contract. It lets app-chains customize their fee economics without
+// patching the state transition: the gas accounting is left unchanged, only
+// who pays the fees and in what is up to the fee currency. The value of the
+// transactions is still transferred in the native token.
+//
+// Amounts are in wei of the native token, converting them is up to the fee
+// currency. They may be zero, e.g. for calls simulated without a gas price.
+// Implementations are part of consensus and must be deterministic, with all
+// their effects going through the state of the given EVM.
+type FeeCurrency interface {
+	// BalanceOf returns the balance of the account in the fee currency, which
+	// the transaction pool counts towards the fees the account can pay.
+	BalanceOf(db StateDB, account common.Address) *uint256.Int
+
+	// BuyGas charges the sender the given fee for the gas limit of its message
+	// upfront, failing with core.ErrInsufficientFunds if the sender can't afford
+	// the given maximum fee.
+	BuyGas(evm *EVM, from common.Address, fee *uint256.Int, maxFee *uint256.Int) error
+
+	// RefundGas returns the fee of the gas left unused to the sender.
+	RefundGas(evm *EVM, from common.Address, refund *uint256.Int)
+
+	// PayFees pays the tip of the message of the sender to the block producer
+	// and its base fee to the burnt contract.
+	PayFees(evm *EVM, from common.Address, tip *uint256.Int, burntContract common.Address, burnt *uint256.Int)
+}
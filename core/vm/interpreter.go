@@ -59,6 +59,8 @@ type Config struct {
 	EnablePreimageRecording bool  // Enables recording of SHA3/keccak preimages
 	ExtraEips               []int // Additional EIPS that are to be enabled
 	EnableWitnessCollection bool  // true if witness collection is enabled
+
+	FeeCurrency FeeCurrency // Fee currency paying the transaction fees from the fee currency block of the bor config on, nil if none
 }
 
 // ScopeContext contains the things that are per-call, such as stack and memory,
@@ -22,8 +22,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -141,3 +144,62 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// AllowDeepReorg lets the next reorg deeper than the maximum depth configured
+// through, as long as it drops at most the given number of blocks.
+func (api *AdminAPI) AllowDeepReorg(depth uint64) (bool, error) {
+	limit := api.eth.BlockChain().MaxReorgDepth()
+	if limit == 0 {
+		return false, errors.New("reorg depth not limited")
+	}
+
+	if depth <= limit {
+		return false, fmt.Errorf("depth %d within the limit of %d blocks", depth, limit)
+	}
+
+	api.eth.BlockChain().AllowDeepReorg(depth)
+
+	return true, nil
+}
+
+// WhitelistEntry is a single finalized block known to the whitelist service.
+type WhitelistEntry struct {
+	Number hexutil.Uint64 `json:"number"`
+	Hash   common.Hash    `json:"hash"`
+}
+
+// WhitelistState is the state of the checkpoint and milestone whitelist as
+// returned by admin_getWhitelist. Absent entries are reported as nil.
+type WhitelistState struct {
+	Checkpoint      *WhitelistEntry `json:"checkpoint"`
+	Milestone       *WhitelistEntry `json:"milestone"`
+	LockedMilestone *WhitelistEntry `json:"lockedMilestone"`
+	MilestoneIDs    []string        `json:"milestoneIDs"`
+}
+
+func newWhitelistEntry(exists bool, number uint64, hash common.Hash) *WhitelistEntry {
+	if !exists {
+		return nil
+	}
+
+	return &WhitelistEntry{Number: hexutil.Uint64(number), Hash: hash}
+}
+
+// GetWhitelist returns the checkpoint and milestone the node currently refuses
+// to reorg below, along with the milestone locked for voting, if any.
+func (api *AdminAPI) GetWhitelist() (*WhitelistState, error) {
+	validator := api.eth.Downloader().GetWhitelistService()
+	if validator == nil {
+		return nil, errors.New("whitelist service not available")
+	}
+
+	ids := validator.GetMilestoneIDsList()
+	sort.Strings(ids)
+
+	return &WhitelistState{
+		Checkpoint:      newWhitelistEntry(validator.GetWhitelistedCheckpoint()),
+		Milestone:       newWhitelistEntry(validator.GetWhitelistedMilestone()),
+		LockedMilestone: newWhitelistEntry(validator.GetLockedMilestone()),
+		MilestoneIDs:    ids,
+	}, nil
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/bloombits"
@@ -82,7 +83,7 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 	if number == rpc.FinalizedBlockNumber {
 		finalBlockNumber, err := getFinalizedBlockNumber(b.eth)
 		if err != nil {
-			return nil, errors.New("finalized block not found")
+			return nil, bor.ErrFinalityNotAvailable
 		}
 
 		block := b.eth.blockchain.CurrentFinalizedBlock(finalBlockNumber)
@@ -91,7 +92,7 @@ func (b *EthAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumb
 			return block.Header(), nil
 		}
 
-		return nil, errors.New("finalized block not found")
+		return nil, bor.ErrFinalityNotAvailable
 	}
 
 	if number == rpc.SafeBlockNumber {
@@ -148,7 +149,7 @@ func (b *EthAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumbe
 	if number == rpc.FinalizedBlockNumber {
 		finalBlocknumber, err := getFinalizedBlockNumber(b.eth)
 		if err != nil {
-			return nil, errors.New("finalized block not found")
+			return nil, bor.ErrFinalityNotAvailable
 		}
 
 		return b.eth.blockchain.CurrentFinalizedBlock(finalBlocknumber), nil
@@ -468,6 +469,12 @@ func (b *EthAPIBackend) Engine() consensus.Engine {
 	return b.eth.engine
 }
 
+// FeeCurrency returns the fee currency paying the transaction fees of the chain
+// from the fee currency block of the bor config on, or nil if none.
+func (b *EthAPIBackend) FeeCurrency() vm.FeeCurrency {
+	return b.eth.blockchain.FeeCurrency()
+}
+
 func (b *EthAPIBackend) CurrentHeader() *types.Header {
 	return b.eth.blockchain.CurrentHeader()
 }
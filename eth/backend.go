@@ -25,6 +25,7 @@ import (
 	"math/big"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
@@ -33,6 +34,9 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
+	"github.com/ethereum/go-ethereum/consensus/bor/clock"
+	"github.com/ethereum/go-ethereum/consensus/bor/forkwatch"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/core"
@@ -49,7 +53,9 @@ import (
 	"github.com/ethereum/go-ethereum/eth/filters"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/eth/protocols/finality"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
+	"github.com/ethereum/go-ethereum/eth/protocols/vset"
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
@@ -110,6 +116,14 @@ type Ethereum struct {
 
 	closeCh chan struct{} // Channel to signal the background processes to exit
 
+	alerts      *alert.Client         // Webhook client for consensus alerts, nil if disabled
+	maintenance *maintenanceScheduler // Defers background tasks to sprint positions we don't produce at
+	clock       *clock.Checker        // Clock sanity check sealing is refused on, nil if disabled
+	forks       *forkwatch.Watcher    // Fork schedule check against the network manifest, nil if disabled
+	anomalyFeed event.Feed            // Feed of blocks deviating from their producer's profile
+
+	milestoneTip atomic.Uint64 // End block of the latest milestone fetched from heimdall, 0 if none yet
+
 	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
 }
 
@@ -197,12 +211,40 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		return nil, genesisErr
 	}
 
+	if err := core.CheckFeeCurrency(chainConfig, config.FeeCurrency); err != nil {
+		return nil, err
+	}
+
 	blockChainAPI := ethapi.NewBlockChainAPI(eth.APIBackend)
 	engine, err := ethconfig.CreateConsensusEngine(chainConfig, config, chainDb, blockChainAPI)
 	eth.engine = engine
 	if err != nil {
 		return nil, err
 	}
+
+	eth.alerts = alert.NewClient(config.AlertWebhook, stack.Config().NodeName())
+	if borEngine, ok := engine.(*bor.Bor); ok {
+		borEngine.SetAlertClient(eth.alerts)
+		borEngine.SetVerifyWorkers(config.VerifyWorkers)
+		borEngine.SetSignatureCache(config.SignatureCache)
+		borEngine.SetSnapshotPersistence(config.SnapshotInterval, config.SprintSnapshots)
+		borEngine.SetSnapshotDiffs(config.SnapshotDiffs)
+		borEngine.SetValidatorArchive(config.ValidatorArchive)
+		borEngine.SetSpanVerification(config.VerifySpans)
+
+		eth.clock = clock.NewChecker(config.ClockServers, config.ClockMaxOffset)
+		borEngine.SetClockChecker(eth.clock)
+
+		eth.forks = forkwatch.NewWatcher(config.ForkManifestURL, config.ForkManifestSigner, chainConfig)
+
+		if config.ReportDoubleSign {
+			if reporter, ok := borEngine.HeimdallClient.(bor.EvidenceReporter); ok {
+				borEngine.SetEvidenceReporter(reporter)
+			} else {
+				log.Warn("Heimdall client can't submit double sign evidence, reporting disabled")
+			}
+		}
+	}
 	// END: Bor changes
 
 	bcVersion := rawdb.ReadDatabaseVersion(chainDb)
@@ -226,6 +268,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		vmConfig = vm.Config{
 			EnablePreimageRecording: config.EnablePreimageRecording,
 			EnableWitnessCollection: config.EnableWitnessCollection,
+			FeeCurrency:             config.FeeCurrency,
 		}
 		cacheConfig = &core.CacheConfig{
 			TrieCleanLimit:      config.TrieCleanCache,
@@ -270,6 +313,27 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 
 	_ = eth.engine.VerifyHeader(eth.blockchain, eth.blockchain.CurrentHeader()) // TODO think on it
 
+	eth.blockchain.SetMaxReorgDepth(config.MaxReorgDepth)
+
+	preload := config.PreloadContracts
+	if chainConfig.Bor != nil {
+		preload = append(preload, common.HexToAddress(chainConfig.Bor.ValidatorContract), common.HexToAddress(chainConfig.Bor.StateReceiverContract))
+	}
+
+	if len(preload) > 0 {
+		go eth.blockchain.Preload(preload, config.PreloadStorageSlots)
+	}
+
+	eth.maintenance = &maintenanceScheduler{chain: eth.blockchain, closeCh: eth.closeCh}
+	if borEngine, ok := eth.engine.(*bor.Bor); ok {
+		eth.maintenance.gate = func(head *types.Header) bool {
+			return borEngine.IsMaintenanceWindow(eth.blockchain, head)
+		}
+		eth.blockchain.SetMaintenanceGate(eth.maintenance.gate)
+
+		borEngine.PrepareSnapshots(eth.blockchain)
+	}
+
 	// BOR changes
 	eth.APIBackend.gpo.ProcessCache()
 	// BOR changes
@@ -316,6 +380,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 
 	eth.miner = miner.New(eth, &config.Miner, eth.blockchain.Config(), eth.EventMux(), eth.engine, eth.isLocalBlock)
 	eth.miner.SetExtra(makeExtraData(config.Miner.ExtraData))
+	eth.miner.SetAlertClient(eth.alerts)
 
 	// Setup DNS discovery iterators.
 	dnsclient := dnsdisc.NewClient(dnsdisc.Config{})
@@ -336,6 +401,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	stack.RegisterProtocols(eth.Protocols())
 	stack.RegisterLifecycle(eth)
 
+	if config.HealthEndpoints {
+		eth.registerHealthEndpoints(stack)
+	}
+
 	// Successful startup; push a marker and check previous unclean shutdowns.
 	eth.shutdownTracker.MarkStartup()
 
@@ -600,6 +669,11 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler), s.snapDialCandidates)...)
 	}
 
+	if s.blockchain.Config().Bor != nil {
+		protos = append(protos, vset.MakeProtocols((*vsetHandler)(s.handler))...)
+		protos = append(protos, finality.MakeProtocols((*finalityHandler)(s.handler))...)
+	}
+
 	return protos
 }
 
@@ -632,6 +706,13 @@ func (s *Ethereum) Start() error {
 	go s.startMilestoneWhitelistService()
 	go s.startNoAckMilestoneService()
 	go s.startNoAckMilestoneByIDService()
+	go s.startAlertService()
+	go s.startNonCanonicalPruner()
+	go s.startClockService()
+	go s.startForkWatchService()
+	go s.startAnomalyDetector()
+	go s.startProducerPeerService()
+	go s.startSpanCheckService()
 
 	return nil
 }
@@ -699,6 +780,11 @@ func retryHeimdallHandler(fn heimdallHandler, tickerDuration time.Duration, time
 	}
 
 	ethHandler, bor, err := getHandler()
+	if errors.Is(err, ErrBorConsensusWithoutHeimdall) {
+		log.Debug("Not starting heimdall service, running without heimdall", "service", fnName)
+		return
+	}
+
 	if err != nil {
 		log.Error("error while getting the ethHandler", "err", err)
 		return
@@ -742,6 +828,8 @@ func (s *Ethereum) handleWhitelistCheckpoint(ctx context.Context, ethHandler *et
 	}
 
 	ethHandler.downloader.ProcessCheckpoint(blockNum, blockHash)
+	advanceFinalized(s.blockchain, blockNum, blockHash)
+	s.handler.broadcastFinality(finality.Checkpoint, blockNum, blockHash)
 
 	return nil
 }
@@ -753,6 +841,9 @@ func (s *Ethereum) handleMilestone(ctx context.Context, ethHandler *ethHandler,
 	// Create a new bor verifier, which will be used to verify checkpoints and milestones
 	verifier := newBorVerifier()
 	num, hash, err := ethHandler.fetchWhitelistMilestone(ctx, bor, s, verifier)
+	if num > 0 {
+		s.milestoneTip.Store(num)
+	}
 
 	// If the current chain head is behind the received milestone, add it to the future milestone
 	// list. Also, the hash mismatch (end block hash) error will lead to rewind so also
@@ -770,6 +861,8 @@ func (s *Ethereum) handleMilestone(ctx context.Context, ethHandler *ethHandler,
 	}
 
 	ethHandler.downloader.ProcessMilestone(num, hash)
+	advanceFinalized(s.blockchain, num, hash)
+	s.handler.broadcastFinality(finality.Milestone, num, hash)
 
 	return nil
 }
@@ -0,0 +1,101 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// alertCheckInterval is the interval the chain head and heimdall are checked at.
+	alertCheckInterval = 10 * time.Second
+
+	// heimdallPingTimeout is the maximum time a heimdall reachability check may take.
+	heimdallPingTimeout = 5 * time.Second
+)
+
+// alertMonitor tracks the chain head and the heimdall reachability, and raises
+// an alert if either of them stalls for longer than allowed.
+type alertMonitor struct {
+	alerts *alert.Client
+
+	headStall    time.Duration // Time the head may not move before alerting (0 = disabled)
+	heimdallDown time.Duration // Time heimdall may be unreachable before alerting (0 = disabled)
+
+	head              common.Hash // Last seen chain head
+	headSince         time.Time   // Time the last seen chain head was first observed
+	heimdallDownSince time.Time   // Time of the first failed heimdall check, zero if reachable
+}
+
+// checkHead records the current chain head, alerting if it didn't move for too long.
+func (m *alertMonitor) checkHead(now time.Time, head *types.Header) {
+	if hash := head.Hash(); hash != m.head || m.headSince.IsZero() {
+		m.head, m.headSince = hash, now
+		return
+	}
+
+	if stalled := now.Sub(m.headSince); m.headStall > 0 && stalled > m.headStall {
+		m.alerts.Notify(alert.HeadStalled, fmt.Sprintf("chain head %d didn't move for %v", head.Number.Uint64(), common.PrettyDuration(stalled)))
+	}
+}
+
+// checkHeimdall records the result of a heimdall reachability check, alerting if
+// heimdall was unreachable for too long.
+func (m *alertMonitor) checkHeimdall(now time.Time, err error) {
+	if err == nil {
+		m.heimdallDownSince = time.Time{}
+		return
+	}
+
+	if m.heimdallDownSince.IsZero() {
+		m.heimdallDownSince = now
+		return
+	}
+
+	if down := now.Sub(m.heimdallDownSince); m.heimdallDown > 0 && down > m.heimdallDown {
+		m.alerts.Notify(alert.HeimdallUnreachable, fmt.Sprintf("heimdall unreachable for %v: %v", common.PrettyDuration(down), err))
+	}
+}
+
+// startAlertService periodically checks the chain head and the heimdall
+// connection, and posts an alert to the configured webhook if either stalls.
+func (s *Ethereum) startAlertService() {
+	if s.alerts == nil {
+		return
+	}
+
+	monitor := &alertMonitor{
+		alerts:       s.alerts,
+		headStall:    s.config.AlertHeadStall,
+		heimdallDown: s.config.AlertHeimdallDown,
+	}
+
+	borEngine, _ := s.engine.(*bor.Bor)
+
+	ticker := time.NewTicker(alertCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+
+			monitor.checkHead(now, s.blockchain.CurrentBlock())
+
+			if borEngine != nil && borEngine.HeimdallClient != nil && monitor.heimdallDown > 0 {
+				ctx, cancel := context.WithTimeout(context.Background(), heimdallPingTimeout)
+				_, err := borEngine.HeimdallClient.FetchMilestoneCount(ctx)
+				cancel()
+
+				monitor.checkHeimdall(now, err)
+			}
+		case <-s.closeCh:
+			return
+		}
+	}
+}
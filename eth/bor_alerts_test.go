@@ -0,0 +1,109 @@
+package eth
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newTestAlertMonitor(t *testing.T) (*alertMonitor, chan alert.Kind) {
+	t.Helper()
+
+	kinds := make(chan alert.Kind, 4)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Kind alert.Kind `json:"kind"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err == nil {
+			kinds <- body.Kind
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	monitor := &alertMonitor{
+		alerts:       alert.NewClient(server.URL, "test"),
+		headStall:    time.Minute,
+		heimdallDown: 5 * time.Minute,
+	}
+
+	return monitor, kinds
+}
+
+func expectAlert(t *testing.T, kinds chan alert.Kind, want alert.Kind) {
+	t.Helper()
+
+	select {
+	case kind := <-kinds:
+		require.Equal(t, want, kind)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("alert %s not received", want)
+	}
+}
+
+func expectNoAlert(t *testing.T, kinds chan alert.Kind) {
+	t.Helper()
+
+	select {
+	case kind := <-kinds:
+		t.Fatalf("unexpected alert %s", kind)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAlertMonitorHeadStalled(t *testing.T) {
+	t.Parallel()
+
+	monitor, kinds := newTestAlertMonitor(t)
+
+	var (
+		now   = time.Now()
+		head1 = &types.Header{Number: big.NewInt(1)}
+		head2 = &types.Header{Number: big.NewInt(2)}
+	)
+
+	monitor.checkHead(now, head1)
+	monitor.checkHead(now.Add(30*time.Second), head1)
+	expectNoAlert(t, kinds)
+
+	// A new head resets the stall timer
+	monitor.checkHead(now.Add(50*time.Second), head2)
+	monitor.checkHead(now.Add(90*time.Second), head2)
+	expectNoAlert(t, kinds)
+
+	monitor.checkHead(now.Add(2*time.Minute), head2)
+	expectAlert(t, kinds, alert.HeadStalled)
+}
+
+func TestAlertMonitorHeimdallUnreachable(t *testing.T) {
+	t.Parallel()
+
+	monitor, kinds := newTestAlertMonitor(t)
+
+	var (
+		now = time.Now()
+		err = errors.New("connection refused")
+	)
+
+	monitor.checkHeimdall(now, err)
+	monitor.checkHeimdall(now.Add(4*time.Minute), err)
+	expectNoAlert(t, kinds)
+
+	// A successful check resets the timer
+	monitor.checkHeimdall(now.Add(5*time.Minute), nil)
+	monitor.checkHeimdall(now.Add(6*time.Minute), err)
+	monitor.checkHeimdall(now.Add(10*time.Minute), err)
+	expectNoAlert(t, kinds)
+
+	monitor.checkHeimdall(now.Add(12*time.Minute), err)
+	expectAlert(t, kinds, alert.HeimdallUnreachable)
+}
@@ -0,0 +1,210 @@
+package eth
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	// anomalyWarmup is the number of blocks of a producer that are profiled
+	// before its blocks are checked for anomalies.
+	anomalyWarmup = 32
+
+	// anomalyDecay is the weight of a new block in the exponentially weighted
+	// profile of its producer.
+	anomalyDecay = 0.05
+)
+
+// Block properties profiled per producer.
+const (
+	AnomalyGas  = "gas"  // Gas used by the block
+	AnomalyTxs  = "txs"  // Number of transactions in the block
+	AnomalyExec = "exec" // Time spent executing the block, in seconds
+)
+
+var anomalyMeters = map[string]metrics.Meter{
+	AnomalyGas:  metrics.NewRegisteredMeter("chain/anomaly/gas", nil),
+	AnomalyTxs:  metrics.NewRegisteredMeter("chain/anomaly/txs", nil),
+	AnomalyExec: metrics.NewRegisteredMeter("chain/anomaly/exec", nil),
+}
+
+// BlockAnomaly is a block whose gas usage, transaction count or execution time
+// deviates strongly from the historical profile of its producer.
+type BlockAnomaly struct {
+	Number    uint64
+	Hash      common.Hash
+	Producer  common.Address
+	Property  string  // Deviating property (AnomalyGas, AnomalyTxs or AnomalyExec)
+	Value     float64 // Value of the property in the block
+	Mean      float64 // Mean of the property in the producer's profile
+	Deviation float64 // Distance of the value from the mean, in standard deviations
+}
+
+// ewma is an exponentially weighted moving mean and variance.
+type ewma struct {
+	mean     float64
+	variance float64
+}
+
+// update adds a sample to the moving mean and variance.
+func (e *ewma) update(x float64) {
+	diff := x - e.mean
+	incr := anomalyDecay * diff
+
+	e.mean += incr
+	e.variance = (1 - anomalyDecay) * (e.variance + diff*incr)
+}
+
+// deviation returns the distance of x from the mean in standard deviations. The
+// standard deviation is floored to a tenth of the mean, so that producers with
+// very uniform blocks aren't flagged for marginal changes.
+func (e *ewma) deviation(x float64) float64 {
+	stddev := math.Max(math.Sqrt(e.variance), 0.1*math.Abs(e.mean)+1e-9)
+	return math.Abs(x-e.mean) / stddev
+}
+
+// producerProfile is the historical profile of the blocks of a producer.
+type producerProfile struct {
+	blocks     uint64 // Number of blocks profiled
+	execBlocks uint64 // Number of blocks with a known execution time profiled
+
+	properties map[string]*ewma
+}
+
+// anomalyDetector flags blocks deviating strongly from the historical profile
+// of their producer, to spot malfunctioning or malicious producers early.
+type anomalyDetector struct {
+	threshold float64 // Standard deviations a block may deviate before it's flagged
+	profiles  map[common.Address]*producerProfile
+}
+
+func newAnomalyDetector(threshold float64) *anomalyDetector {
+	return &anomalyDetector{
+		threshold: threshold,
+		profiles:  make(map[common.Address]*producerProfile),
+	}
+}
+
+// check checks the block against the profile of its producer, returning the
+// anomalies found, and adds the block to the profile afterwards. An execution
+// time of zero means it's unknown (e.g. for locally sealed blocks).
+func (d *anomalyDetector) check(block *types.Block, producer common.Address, exec time.Duration) []*BlockAnomaly {
+	profile, ok := d.profiles[producer]
+	if !ok {
+		profile = &producerProfile{
+			properties: map[string]*ewma{
+				AnomalyGas:  {},
+				AnomalyTxs:  {},
+				AnomalyExec: {},
+			},
+		}
+		d.profiles[producer] = profile
+	}
+
+	values := map[string]float64{
+		AnomalyGas: float64(block.GasUsed()),
+		AnomalyTxs: float64(len(block.Transactions())),
+	}
+	if exec > 0 {
+		values[AnomalyExec] = exec.Seconds()
+	}
+
+	var anomalies []*BlockAnomaly
+
+	for _, property := range []string{AnomalyGas, AnomalyTxs, AnomalyExec} {
+		value, ok := values[property]
+		if !ok {
+			continue
+		}
+
+		profiled := profile.blocks
+		if property == AnomalyExec {
+			profiled = profile.execBlocks
+		}
+
+		stats := profile.properties[property]
+
+		if profiled >= anomalyWarmup {
+			if deviation := stats.deviation(value); deviation > d.threshold {
+				anomalies = append(anomalies, &BlockAnomaly{
+					Number:    block.NumberU64(),
+					Hash:      block.Hash(),
+					Producer:  producer,
+					Property:  property,
+					Value:     value,
+					Mean:      stats.mean,
+					Deviation: deviation,
+				})
+			}
+		}
+
+		stats.update(value)
+	}
+
+	profile.blocks++
+	if exec > 0 {
+		profile.execBlocks++
+	}
+
+	return anomalies
+}
+
+// SubscribeBlockAnomalies registers a subscription for the blocks deviating
+// strongly from the profile of their producer.
+func (s *Ethereum) SubscribeBlockAnomalies(ch chan<- *BlockAnomaly) event.Subscription {
+	return s.anomalyFeed.Subscribe(ch)
+}
+
+// startAnomalyDetector profiles the imported blocks per producer, flagging the
+// ones deviating strongly from the profile through metrics, events and alerts.
+func (s *Ethereum) startAnomalyDetector() {
+	if s.config.AlertAnomalyThreshold <= 0 {
+		return
+	}
+
+	detector := newAnomalyDetector(s.config.AlertAnomalyThreshold)
+
+	events := make(chan core.BlockStatsEvent, 16)
+	sub := s.blockchain.SubscribeBlockStatsEvent(events)
+
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-events:
+			producer, err := s.engine.Author(ev.Block.Header())
+			if err != nil {
+				continue
+			}
+
+			for _, anomaly := range detector.check(ev.Block, producer, ev.ExecTime) {
+				anomalyMeters[anomaly.Property].Mark(1)
+
+				log.Warn("Block deviates from its producer's profile", "number", anomaly.Number, "hash", anomaly.Hash, "producer", producer,
+					"property", anomaly.Property, "value", anomaly.Value, "mean", anomaly.Mean, "deviation", anomaly.Deviation)
+
+				s.anomalyFeed.Send(anomaly)
+
+				if s.alerts != nil {
+					s.alerts.Notify(alert.BlockAnomaly, fmt.Sprintf("block %d by %s has %s %v, %.1f standard deviations off the mean %v",
+						anomaly.Number, producer, anomaly.Property, anomaly.Value, anomaly.Deviation, anomaly.Mean))
+				}
+			}
+
+		case <-sub.Err():
+			return
+
+		case <-s.closeCh:
+			return
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newAnomalyTestBlock(number uint64, gasUsed uint64) *types.Block {
+	return types.NewBlockWithHeader(&types.Header{
+		Number:  new(big.Int).SetUint64(number),
+		GasUsed: gasUsed,
+	})
+}
+
+func TestAnomalyDetector(t *testing.T) {
+	t.Parallel()
+
+	var (
+		detector = newAnomalyDetector(4)
+		producer = common.HexToAddress("0x01")
+		newcomer = common.HexToAddress("0x02")
+	)
+
+	// Build up a profile of blocks using around 1M gas, executing in ~100ms
+	for i := uint64(0); i < anomalyWarmup; i++ {
+		gas := 1_000_000 + (i%5)*10_000
+		require.Empty(t, detector.check(newAnomalyTestBlock(i, gas), producer, 100*time.Millisecond))
+	}
+
+	// Blocks in line with the profile aren't flagged
+	require.Empty(t, detector.check(newAnomalyTestBlock(100, 1_030_000), producer, 110*time.Millisecond))
+
+	// Neither are blocks of unknown execution time
+	require.Empty(t, detector.check(newAnomalyTestBlock(101, 1_000_000), producer, 0))
+
+	// A block using ten times the gas and taking ten times as long is flagged twice
+	anomalies := detector.check(newAnomalyTestBlock(102, 10_000_000), producer, time.Second)
+	require.Len(t, anomalies, 2)
+	require.Equal(t, AnomalyGas, anomalies[0].Property)
+	require.Equal(t, AnomalyExec, anomalies[1].Property)
+	require.Equal(t, producer, anomalies[0].Producer)
+	require.Equal(t, uint64(102), anomalies[0].Number)
+	require.Greater(t, anomalies[0].Deviation, 4.0)
+
+	// Producers without a profile yet aren't checked
+	require.Empty(t, detector.check(newAnomalyTestBlock(103, 10_000_000), newcomer, time.Second))
+}
@@ -2,21 +2,28 @@ package eth
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var errBorEngineNotAvailable error = errors.New("Only available in Bor engine")
 
+// milestoneVoteConfirmations is the number of blocks which must be built on
+// top of the end block of a milestone before the node votes for it.
+const milestoneVoteConfirmations = 16
+
 // GetRootHash returns root hash for given start and end block
 func (b *EthAPIBackend) GetRootHash(ctx context.Context, starBlockNr uint64, endBlockNr uint64) (string, error) {
 	var api *bor.API
@@ -53,23 +60,32 @@ func (b *EthAPIBackend) GetVoteOnHash(ctx context.Context, starBlockNr uint64, e
 		return false, errBorEngineNotAvailable
 	}
 
-	// Confirmation of 16 blocks on the endblock
-	tipConfirmationBlockNr := endBlockNr + uint64(16)
+	if starBlockNr > endBlockNr {
+		return false, fmt.Errorf("invalid milestone range: start block %d is after end block %d", starBlockNr, endBlockNr)
+	}
+
+	// The hash may come with or without the 0x prefix, in any case
+	if !isHexHash(hash) {
+		return false, fmt.Errorf("invalid milestone hash %q", hash)
+	}
+
+	milestoneHash := common.HexToHash(hash)
+
+	// Confirmation of milestoneVoteConfirmations blocks on the endblock
+	tipConfirmationBlockNr := endBlockNr + milestoneVoteConfirmations
 
 	// Check if tipConfirmation block exit
-	_, err := b.BlockByNumber(ctx, rpc.BlockNumber(tipConfirmationBlockNr))
-	if err != nil {
+	tipConfirmationBlock, err := b.BlockByNumber(ctx, rpc.BlockNumber(tipConfirmationBlockNr))
+	if err != nil || tipConfirmationBlock == nil {
 		return false, errTipConfirmationBlock
 	}
 
 	// Check if end block exist
 	localEndBlock, err := b.BlockByNumber(ctx, rpc.BlockNumber(endBlockNr))
-	if err != nil {
+	if err != nil || localEndBlock == nil {
 		return false, errEndBlock
 	}
 
-	localEndBlockHash := localEndBlock.Hash().String()
-
 	downloader := b.eth.handler.downloader
 	isLocked := downloader.LockMutex(endBlockNr)
 
@@ -78,9 +94,9 @@ func (b *EthAPIBackend) GetVoteOnHash(ctx context.Context, starBlockNr uint64, e
 		return false, errors.New("whitelisted number or locked sprint number is more than the received end block number")
 	}
 
-	if localEndBlockHash != hash {
+	if localEndBlock.Hash() != milestoneHash {
 		downloader.UnlockMutex(false, "", endBlockNr, common.Hash{})
-		return false, fmt.Errorf("hash mismatch: localChainHash %s, milestoneHash %s", localEndBlockHash, hash)
+		return false, fmt.Errorf("hash mismatch: localChainHash %s, milestoneHash %s", localEndBlock.Hash(), milestoneHash)
 	}
 
 	downloader.UnlockMutex(true, milestoneId, endBlockNr, localEndBlock.Hash())
@@ -88,6 +104,22 @@ func (b *EthAPIBackend) GetVoteOnHash(ctx context.Context, starBlockNr uint64, e
 	return true, nil
 }
 
+// isHexHash reports whether s is a hex encoded 32 byte hash, with or without
+// the 0x prefix.
+func isHexHash(s string) bool {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+
+	if len(s) != 2*common.HashLength {
+		return false
+	}
+
+	_, err := hex.DecodeString(s)
+
+	return err == nil
+}
+
 // GetBorBlockReceipt returns bor block receipt
 func (b *EthAPIBackend) GetBorBlockReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	receipt := b.eth.blockchain.GetBorReceiptByHash(hash)
@@ -124,7 +156,56 @@ func (b *EthAPIBackend) SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) e
 	return b.eth.BlockChain().SubscribeStateSyncEvent(ch)
 }
 
+// SubscribeStateSyncCommitEvent subscribes to the state syncs committed on the
+// canonical chain
+func (b *EthAPIBackend) SubscribeStateSyncCommitEvent(ch chan<- core.StateSyncCommitEvent) event.Subscription {
+	return b.eth.BlockChain().SubscribeStateSyncCommitEvent(ch)
+}
+
 // SubscribeChain2HeadEvent subscribes to reorg/head/fork event
 func (b *EthAPIBackend) SubscribeChain2HeadEvent(ch chan<- core.Chain2HeadEvent) event.Subscription {
 	return b.eth.BlockChain().SubscribeChain2HeadEvent(ch)
 }
+
+// BorMetadataEnabled reports whether the bor metadata of blocks is added to
+// the block responses.
+func (b *EthAPIBackend) BorMetadataEnabled() bool {
+	return b.eth.config.RPCBorMetadata
+}
+
+// GetBorBlockMetadata returns the Bor specific context of the given block: its
+// signer and whether it sealed in turn, its sprint and span, and whether the
+// block committed state-sync events.
+func (b *EthAPIBackend) GetBorBlockMetadata(ctx context.Context, header *types.Header) (*ethapi.RPCBorMetadata, error) {
+	engine, ok := b.eth.Engine().(*bor.Bor)
+	if !ok {
+		return nil, errBorEngineNotAvailable
+	}
+
+	var (
+		number    = header.Number.Uint64()
+		borConfig = b.ChainConfig().Bor
+		txHash    = types.GetDerivedBorTxHash(types.BorReceiptKey(number, header.Hash()))
+	)
+
+	tx, _, _, _ := rawdb.ReadBorTransactionWithBlockHash(b.eth.ChainDb(), txHash, header.Hash())
+
+	metadata := &ethapi.RPCBorMetadata{
+		Sprint:            hexutil.Uint64(borConfig.CalculateSprintNumber(number)),
+		SprintStart:       hexutil.Uint64(borConfig.CalculateSprintStart(number)),
+		SprintEnd:         hexutil.Uint64(borConfig.CalculateSprintEnd(number)),
+		ContainsStateSync: tx != nil,
+	}
+
+	// Only the context available locally is served, to avoid replaying headers
+	// or querying heimdall for every block
+	local := engine.LocalBlockMetadata(header)
+	metadata.Signer, metadata.InTurn = local.Signer, local.InTurn
+
+	if local.SpanID != nil {
+		span := hexutil.Uint64(*local.SpanID)
+		metadata.Span = &span
+	}
+
+	return metadata, nil
+}
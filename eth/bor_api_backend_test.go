@@ -0,0 +1,27 @@
+package eth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestIsHexHash(t *testing.T) {
+	t.Parallel()
+
+	hash := common.HexToHash("0xc0ffee").Hex()
+
+	require.True(t, isHexHash(hash))
+	require.True(t, isHexHash(hash[2:]))
+	require.True(t, isHexHash(strings.ToUpper(hash[2:])))
+	require.True(t, isHexHash("0X"+hash[2:]))
+
+	require.False(t, isHexHash(""))
+	require.False(t, isHexHash("0x"))
+	require.False(t, isHexHash("0x"+hash))
+	require.False(t, isHexHash(hash[:len(hash)-2]))
+	require.False(t, isHexHash(hash[:len(hash)-1]+"z"))
+}
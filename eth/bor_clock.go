@@ -0,0 +1,54 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
+	"github.com/ethereum/go-ethereum/consensus/bor/clock"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// clockCheckInterval is the interval the local clock is checked against NTP at.
+const clockCheckInterval = 10 * time.Minute
+
+// startClockService checks the local clock at startup and periodically after,
+// loudly reporting any skew beyond the configured bound. While the clock is
+// skewed, the bor engine refuses to seal.
+func (s *Ethereum) startClockService() {
+	if s.clock == nil {
+		return
+	}
+
+	ticker := time.NewTicker(clockCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		s.checkClock()
+
+		select {
+		case <-ticker.C:
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// checkClock measures the local clock offset, alerting if it is out of bounds.
+func (s *Ethereum) checkClock() {
+	offset, err := s.clock.Check()
+
+	switch {
+	case errors.Is(err, clock.ErrClockSkew):
+		log.Error("Local clock is skewed, refusing to seal blocks", "offset", common.PrettyDuration(offset), "err", err)
+		log.Error("Please enable network time synchronisation in system settings.")
+
+		s.alerts.Notify(alert.ClockSkew, fmt.Sprintf("local clock off by %v, sealing refused", common.PrettyDuration(offset)))
+	case err != nil:
+		log.Warn("Clock sanity check failed", "err", err)
+	default:
+		log.Debug("Clock sanity check done", "offset", common.PrettyDuration(offset))
+	}
+}
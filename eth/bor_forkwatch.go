@@ -0,0 +1,69 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// forkWatchInterval is the interval the fork manifest of the network is fetched at.
+const forkWatchInterval = time.Hour
+
+// startForkWatchService compares the local fork schedule against the manifest
+// of the network at startup and periodically after, loudly reporting any fork
+// this node would not activate along with the network. Manifests are published
+// well ahead of the forks, leaving operators time to upgrade before their node
+// splits off the chain.
+func (s *Ethereum) startForkWatchService() {
+	if s.forks == nil {
+		return
+	}
+
+	ticker := time.NewTicker(forkWatchInterval)
+	defer ticker.Stop()
+
+	for {
+		s.checkForks()
+
+		select {
+		case <-ticker.C:
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// checkForks fetches the fork manifest, alerting on incompatibilities with the
+// local fork schedule.
+func (s *Ethereum) checkForks() {
+	issues, err := s.forks.Check(context.Background())
+	if err != nil {
+		log.Warn("Fork manifest check failed", "err", err)
+		return
+	}
+
+	if len(issues) == 0 {
+		log.Debug("Fork manifest check done")
+		return
+	}
+
+	head := s.blockchain.CurrentHeader().Number.Uint64()
+
+	msgs := make([]string, 0, len(issues))
+
+	for _, issue := range issues {
+		if issue.Block > head {
+			log.Error("Incompatible with an upcoming fork, upgrade the node", "fork", issue.Name, "block", issue.Block, "local", issue.Local, "known", issue.Known, "remaining", issue.Block-head)
+			msgs = append(msgs, fmt.Sprintf("%v, in %d blocks", issue, issue.Block-head))
+		} else {
+			log.Error("Incompatible with an activated fork, the node is off the network", "fork", issue.Name, "block", issue.Block, "local", issue.Local, "known", issue.Known, "head", head)
+			msgs = append(msgs, fmt.Sprintf("%v, already activated", issue))
+		}
+	}
+
+	s.alerts.Notify(alert.ForkIncompatible, strings.Join(msgs, "; "))
+}
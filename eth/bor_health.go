@@ -0,0 +1,150 @@
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// healthRPCTimeout is the maximum time the RPC call of the readiness check may take.
+const healthRPCTimeout = 5 * time.Second
+
+// healthStatus is the response body of the health endpoints.
+type healthStatus struct {
+	Healthy   bool     `json:"healthy"`
+	Head      uint64   `json:"head"`
+	Milestone uint64   `json:"milestone,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// healthChecker answers the readiness and liveness probes of orchestrators.
+//
+// The node is ready once its head is within a number of blocks of the latest
+// milestone and it answers RPC calls, so that load balancers only route to
+// nodes serving the current chain. The node is live as long as its import and
+// sealing loops progress, so that it's only restarted when it's stuck rather
+// than when the network or heimdall stall.
+type healthChecker struct {
+	maxLag       uint64        // Blocks the head may trail the milestone tip by while ready
+	stallTimeout time.Duration // Time the import or sealing loop may not progress while live
+
+	head        func() *types.Header            // Current head of the chain
+	milestone   func() uint64                   // Latest milestone end block, 0 if unknown; nil without heimdall
+	call        func(ctx context.Context) error // Cheap RPC call proving RPC is served
+	sealingIdle func() (time.Duration, bool)    // Idle time of the sealing loop and whether sealing is enabled
+
+	lock      sync.Mutex
+	lastHead  common.Hash // Last seen chain head
+	headSince time.Time   // Time the last seen chain head was first observed
+}
+
+// ready checks whether the node follows the tip of the chain and serves RPC.
+func (h *healthChecker) ready(ctx context.Context) *healthStatus {
+	head := h.head()
+	status := &healthStatus{Head: head.Number.Uint64()}
+
+	if h.milestone != nil {
+		status.Milestone = h.milestone()
+
+		switch {
+		case status.Milestone == 0:
+			status.Errors = append(status.Errors, "milestone tip not known yet")
+		case status.Head+h.maxLag < status.Milestone:
+			status.Errors = append(status.Errors, fmt.Sprintf("head is %d blocks behind the milestone tip", status.Milestone-status.Head))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthRPCTimeout)
+	defer cancel()
+
+	if err := h.call(ctx); err != nil {
+		status.Errors = append(status.Errors, fmt.Sprintf("rpc unavailable: %v", err))
+	}
+
+	status.Healthy = len(status.Errors) == 0
+
+	return status
+}
+
+// live checks whether the import and sealing loops progress. The import loop
+// is only considered stuck if the head doesn't move while the milestones show
+// the network moving on, as a halted network stalls every node alike.
+func (h *healthChecker) live(now time.Time) *healthStatus {
+	head := h.head()
+	status := &healthStatus{Head: head.Number.Uint64()}
+
+	if h.milestone != nil {
+		status.Milestone = h.milestone()
+	}
+
+	h.lock.Lock()
+	if hash := head.Hash(); hash != h.lastHead || h.headSince.IsZero() {
+		h.lastHead, h.headSince = hash, now
+	}
+	stalled := now.Sub(h.headSince)
+	h.lock.Unlock()
+
+	if stalled > h.stallTimeout && status.Milestone > status.Head {
+		status.Errors = append(status.Errors, fmt.Sprintf("head didn't move for %v while the milestone tip is ahead", common.PrettyDuration(stalled)))
+	}
+
+	if idle, sealing := h.sealingIdle(); sealing && idle > h.stallTimeout {
+		status.Errors = append(status.Errors, fmt.Sprintf("sealing loop didn't attempt work for %v", common.PrettyDuration(idle)))
+	}
+
+	status.Healthy = len(status.Errors) == 0
+
+	return status
+}
+
+// serveHealth writes a health status, failing the probe if it isn't healthy.
+func serveHealth(w http.ResponseWriter, status *healthStatus) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !status.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Debug("Failed to write health status", "err", err)
+	}
+}
+
+// registerHealthEndpoints serves the readiness check on /readyz and the
+// liveness check on /livez of the HTTP-RPC server.
+func (s *Ethereum) registerHealthEndpoints(stack *node.Node) {
+	checker := &healthChecker{
+		maxLag:       s.config.HealthMaxLag,
+		stallTimeout: s.config.HealthStallTimeout,
+		head:         s.blockchain.CurrentHeader,
+		call: func(ctx context.Context) error {
+			client := stack.Attach()
+			defer client.Close()
+
+			var number hexutil.Uint64
+
+			return client.CallContext(ctx, &number, "eth_blockNumber")
+		},
+		sealingIdle: s.miner.SealingIdle,
+	}
+
+	if _, _, err := s.getHandler(); err == nil {
+		checker.milestone = s.milestoneTip.Load
+	}
+
+	stack.RegisterHandler("Readiness check", "/readyz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveHealth(w, checker.ready(r.Context()))
+	}))
+	stack.RegisterHandler("Liveness check", "/livez", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveHealth(w, checker.live(time.Now()))
+	}))
+}
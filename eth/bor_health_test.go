@@ -0,0 +1,104 @@
+package eth
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newTestHealthChecker() (*healthChecker, *types.Header, *uint64, *error) {
+	var (
+		head      = &types.Header{Number: big.NewInt(1000)}
+		milestone = uint64(1000)
+		rpcErr    error
+	)
+
+	checker := &healthChecker{
+		maxLag:       16,
+		stallTimeout: time.Minute,
+		head:         func() *types.Header { return head },
+		milestone:    func() uint64 { return milestone },
+		call:         func(context.Context) error { return rpcErr },
+		sealingIdle:  func() (time.Duration, bool) { return 0, false },
+	}
+
+	return checker, head, &milestone, &rpcErr
+}
+
+func TestHealthReady(t *testing.T) {
+	t.Parallel()
+
+	checker, _, milestone, rpcErr := newTestHealthChecker()
+
+	require.True(t, checker.ready(context.Background()).Healthy)
+
+	// The head may trail the milestone tip by the allowed lag
+	*milestone = 1016
+	require.True(t, checker.ready(context.Background()).Healthy)
+
+	*milestone = 1017
+	status := checker.ready(context.Background())
+	require.False(t, status.Healthy)
+	require.Equal(t, []string{"head is 17 blocks behind the milestone tip"}, status.Errors)
+
+	// Readiness waits for the first milestone
+	*milestone = 0
+	require.False(t, checker.ready(context.Background()).Healthy)
+
+	// Nodes running without heimdall only need to serve RPC
+	checker.milestone = nil
+	require.True(t, checker.ready(context.Background()).Healthy)
+
+	*rpcErr = errors.New("overloaded")
+	require.False(t, checker.ready(context.Background()).Healthy)
+}
+
+func TestHealthLive(t *testing.T) {
+	t.Parallel()
+
+	checker, head, milestone, _ := newTestHealthChecker()
+
+	now := time.Now()
+	require.True(t, checker.live(now).Healthy)
+
+	// A halted network doesn't fail the liveness check
+	require.True(t, checker.live(now.Add(time.Hour)).Healthy)
+
+	// But the head not following the milestones does
+	*milestone = 1100
+	status := checker.live(now.Add(time.Hour))
+	require.False(t, status.Healthy)
+	require.Len(t, status.Errors, 1)
+
+	// Until it moves again
+	head.Number = big.NewInt(1001)
+	require.True(t, checker.live(now.Add(time.Hour)).Healthy)
+
+	checker.sealingIdle = func() (time.Duration, bool) { return 2 * time.Minute, true }
+	require.False(t, checker.live(now.Add(time.Hour)).Healthy)
+
+	checker.sealingIdle = func() (time.Duration, bool) { return 2 * time.Minute, false }
+	require.True(t, checker.live(now.Add(time.Hour)).Healthy)
+}
+
+func TestServeHealth(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	serveHealth(rec, &healthStatus{Healthy: true, Head: 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"healthy":true,"head":10}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	serveHealth(rec, &healthStatus{Head: 10, Milestone: 20, Errors: []string{"behind"}})
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.JSONEq(t, `{"healthy":false,"head":10,"milestone":20,"errors":["behind"]}`, rec.Body.String())
+}
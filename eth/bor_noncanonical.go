@@ -0,0 +1,95 @@
+package eth
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// nonCanonicalPruneInterval is the interval non-canonical blocks are pruned at.
+	nonCanonicalPruneInterval = 10 * time.Minute
+
+	// maxNonCanonicalPruneRange is the maximum number of heights visited in a
+	// single pruning round, to spread the initial pruning of a large database.
+	maxNonCanonicalPruneRange = 100_000
+)
+
+// startNonCanonicalPruner periodically deletes the non-canonical (reorged-out)
+// blocks older than the configured number of heimdall checkpoints. Without a
+// retention, they're kept until the chain segment moves into the freezer.
+func (s *Ethereum) startNonCanonicalPruner() {
+	borEngine, ok := s.engine.(*bor.Bor)
+	if !ok || borEngine.HeimdallClient == nil || s.config.NonCanonicalRetention == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(nonCanonicalPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.maintenance.run("prune-noncanonical", func() {
+				s.pruneNonCanonical(borEngine, s.config.NonCanonicalRetention)
+			})
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// pruneNonCanonical deletes the non-canonical blocks covered by the checkpoints
+// preceding the last retention ones.
+func (s *Ethereum) pruneNonCanonical(engine *bor.Bor, retention uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), heimdallPingTimeout)
+	defer cancel()
+
+	count, err := engine.HeimdallClient.FetchCheckpointCount(ctx)
+	if err != nil {
+		log.Debug("Failed to fetch checkpoint count for pruning", "err", err)
+		return
+	}
+
+	if count <= int64(retention) {
+		return
+	}
+
+	checkpoint, err := engine.HeimdallClient.FetchCheckpoint(ctx, count-int64(retention))
+	if err != nil {
+		log.Debug("Failed to fetch checkpoint for pruning", "number", count-int64(retention), "err", err)
+		return
+	}
+
+	// Side chains below the freezer tail were already wiped when freezing
+	from, _ := s.chainDb.Ancients()
+	if tail := rawdb.ReadNonCanonicalPruneTail(s.chainDb); tail != nil && *tail > from {
+		from = *tail
+	}
+
+	to := checkpoint.EndBlock.Uint64() + 1
+	if head := s.blockchain.CurrentBlock().Number.Uint64(); to > head {
+		to = head
+	}
+
+	if to > from+maxNonCanonicalPruneRange {
+		to = from + maxNonCanonicalPruneRange
+	}
+
+	if from >= to {
+		return
+	}
+
+	start := time.Now()
+
+	deleted, err := rawdb.PruneNonCanonicalBlocks(s.chainDb, from, to)
+	if err != nil {
+		log.Error("Failed to prune non-canonical blocks", "from", from, "to", to, "err", err)
+		return
+	}
+
+	log.Info("Pruned non-canonical blocks", "from", from, "to", to, "deleted", deleted, "elapsed", time.Since(start))
+}
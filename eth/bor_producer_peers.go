@@ -0,0 +1,146 @@
+package eth
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// producerPeerInterval is the interval the producers of the current and
+	// next span are resolved and their connections pinned at.
+	producerPeerInterval = 30 * time.Second
+
+	// producerPeerTimeout bounds the span lookups of a single update.
+	producerPeerTimeout = 10 * time.Second
+)
+
+// peerPinner is the subset of the p2p server used to keep connections to peers.
+type peerPinner interface {
+	AddPeer(node *enode.Node)
+	RemovePeer(node *enode.Node)
+	AddTrustedPeer(node *enode.Node)
+	RemoveTrustedPeer(node *enode.Node)
+}
+
+// producerPeers is an address book of the endpoints announced by the block
+// producers, keeping the connections to the producers it's told about pinned.
+// Pinned endpoints are both static, so they are redialed when disconnected,
+// and trusted, so they are accepted above the peer limit.
+type producerPeers struct {
+	book   map[common.Address][]*enode.Node // Endpoints announced per producer address
+	server peerPinner
+	pinned map[enode.ID]*enode.Node // Endpoints currently pinned
+}
+
+func newProducerPeers(book map[common.Address][]*enode.Node, server peerPinner) *producerPeers {
+	return &producerPeers{
+		book:   book,
+		server: server,
+		pinned: make(map[enode.ID]*enode.Node),
+	}
+}
+
+// update pins the endpoints of the given producers, releasing the endpoints
+// of the producers pinned before that aren't among them anymore. Producers
+// missing from the address book are ignored.
+func (p *producerPeers) update(producers []common.Address) (pinned, released int) {
+	want := make(map[enode.ID]*enode.Node)
+
+	for _, producer := range producers {
+		for _, node := range p.book[producer] {
+			want[node.ID()] = node
+		}
+	}
+
+	for id, node := range p.pinned {
+		if _, ok := want[id]; ok {
+			continue
+		}
+
+		p.server.RemovePeer(node)
+		p.server.RemoveTrustedPeer(node)
+		delete(p.pinned, id)
+
+		released++
+	}
+
+	for id, node := range want {
+		if _, ok := p.pinned[id]; ok {
+			continue
+		}
+
+		p.server.AddTrustedPeer(node)
+		p.server.AddPeer(node)
+		p.pinned[id] = node
+
+		pinned++
+	}
+
+	return pinned, released
+}
+
+// startProducerPeerService keeps the node connected to the producers of the
+// current and next span whose endpoints are in the configured address book.
+func (s *Ethereum) startProducerPeerService() {
+	if len(s.config.ProducerPeers) == 0 {
+		return
+	}
+
+	borEngine, ok := s.engine.(*bor.Bor)
+	if !ok {
+		log.Warn("Producer peers are only supported with bor consensus")
+		return
+	}
+
+	peers := newProducerPeers(s.config.ProducerPeers, s.p2pServer)
+
+	ticker := time.NewTicker(producerPeerInterval)
+	defer ticker.Stop()
+
+	for {
+		s.updateProducerPeers(borEngine, peers)
+
+		select {
+		case <-ticker.C:
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// updateProducerPeers pins the connections to the producers of the span of
+// the head block and of the span after it, if it's already known.
+func (s *Ethereum) updateProducerPeers(borEngine *bor.Bor, peers *producerPeers) {
+	ctx, cancel := context.WithTimeout(context.Background(), producerPeerTimeout)
+	defer cancel()
+
+	head := s.blockchain.CurrentBlock().Number.Uint64()
+
+	current, err := borEngine.GetSprint(ctx, s.blockchain, head)
+	if err != nil {
+		log.Debug("Failed to resolve the producers of the current span", "number", head, "err", err)
+		return
+	}
+
+	producers := make([]common.Address, 0, 2*len(current.Producers))
+	for _, producer := range current.Producers {
+		producers = append(producers, producer.Address)
+	}
+
+	if next, err := borEngine.GetSprint(ctx, s.blockchain, current.SpanEndBlock+1); err == nil {
+		for _, producer := range next.Producers {
+			producers = append(producers, producer.Address)
+		}
+	} else {
+		log.Debug("Producers of the next span not known yet", "span", current.SpanID+1, "err", err)
+	}
+
+	if pinned, released := peers.update(producers); pinned > 0 || released > 0 {
+		log.Info("Updated producer peer connections", "span", current.SpanID, "pinned", pinned, "released", released, "total", len(peers.pinned))
+	}
+}
@@ -0,0 +1,93 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// fakePinner records the peers pinned through it.
+type fakePinner struct {
+	static  map[enode.ID]bool
+	trusted map[enode.ID]bool
+}
+
+func newFakePinner() *fakePinner {
+	return &fakePinner{static: make(map[enode.ID]bool), trusted: make(map[enode.ID]bool)}
+}
+
+func (f *fakePinner) AddPeer(node *enode.Node)           { f.static[node.ID()] = true }
+func (f *fakePinner) RemovePeer(node *enode.Node)        { delete(f.static, node.ID()) }
+func (f *fakePinner) AddTrustedPeer(node *enode.Node)    { f.trusted[node.ID()] = true }
+func (f *fakePinner) RemoveTrustedPeer(node *enode.Node) { delete(f.trusted, node.ID()) }
+
+func testProducerNode(t *testing.T) *enode.Node {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	return enode.NewV4(&key.PublicKey, nil, 30303, 30303)
+}
+
+func TestProducerPeers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice   = common.Address{0x1}
+		bob     = common.Address{0x2}
+		carol   = common.Address{0x3}
+		unknown = common.Address{0x4}
+
+		aliceNode   = testProducerNode(t)
+		bobNode     = testProducerNode(t)
+		bobSentry   = testProducerNode(t)
+		carolSentry = testProducerNode(t)
+	)
+
+	server := newFakePinner()
+	peers := newProducerPeers(map[common.Address][]*enode.Node{
+		alice: {aliceNode},
+		bob:   {bobNode, bobSentry},
+		carol: {carolSentry},
+	}, server)
+
+	pinned := func(nodes ...*enode.Node) {
+		t.Helper()
+
+		require.Len(t, server.static, len(nodes))
+		require.Len(t, server.trusted, len(nodes))
+
+		for _, node := range nodes {
+			require.True(t, server.static[node.ID()], "node not static")
+			require.True(t, server.trusted[node.ID()], "node not trusted")
+		}
+	}
+
+	// Producers without announced endpoints are skipped
+	added, released := peers.update([]common.Address{alice, bob, unknown})
+	require.Equal(t, 3, added)
+	require.Equal(t, 0, released)
+	pinned(aliceNode, bobNode, bobSentry)
+
+	// Repeated producers and unchanged sets don't touch the connections
+	added, released = peers.update([]common.Address{bob, alice, bob})
+	require.Equal(t, 0, added)
+	require.Equal(t, 0, released)
+	pinned(aliceNode, bobNode, bobSentry)
+
+	// Producers rotating out are released
+	added, released = peers.update([]common.Address{bob, carol})
+	require.Equal(t, 1, added)
+	require.Equal(t, 1, released)
+	pinned(bobNode, bobSentry, carolSentry)
+
+	added, released = peers.update(nil)
+	require.Equal(t, 0, added)
+	require.Equal(t, 3, released)
+	pinned()
+}
@@ -0,0 +1,136 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// spanCheckInterval is the interval the upcoming span is validated at.
+	spanCheckInterval = time.Minute
+
+	// spanCheckTimeout bounds the span and contract lookups of a single check.
+	spanCheckTimeout = 30 * time.Second
+
+	// spanCheckLookahead is the number of blocks before a span boundary the
+	// upcoming span starts being validated at.
+	spanCheckLookahead = 1024
+)
+
+// startSpanCheckService validates the producer set of the upcoming span as its
+// boundary approaches, alerting in advance if it looks unhealthy rather than
+// having the chain discover it at the boundary.
+func (s *Ethereum) startSpanCheckService() {
+	borEngine, ok := s.engine.(*bor.Bor)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(spanCheckInterval)
+	defer ticker.Stop()
+
+	var healthy uint64 // Last span reported healthy, not to log it every check
+
+	for {
+		s.checkNextSpan(borEngine, &healthy)
+
+		select {
+		case <-ticker.C:
+		case <-s.closeCh:
+			return
+		}
+	}
+}
+
+// checkNextSpan validates the span after the one of the head block if its
+// boundary is within the lookahead, along with the reachability of its
+// producers.
+func (s *Ethereum) checkNextSpan(borEngine *bor.Bor, healthy *uint64) {
+	ctx, cancel := context.WithTimeout(context.Background(), spanCheckTimeout)
+	defer cancel()
+
+	head := s.blockchain.CurrentHeader()
+	number := head.Number.Uint64()
+
+	current, err := borEngine.GetSprint(ctx, s.blockchain, number)
+	if err != nil {
+		log.Debug("Failed to resolve the current span", "number", number, "err", err)
+		return
+	}
+
+	if current.SpanEndBlock > number+spanCheckLookahead {
+		return
+	}
+
+	check, err := borEngine.CheckNextSpan(ctx, s.blockchain, head)
+	if err != nil {
+		log.Warn("Upcoming span not available", "span", current.SpanID+1, "remaining", current.SpanEndBlock-number, "err", err)
+		return
+	}
+
+	issues := check.Issues
+
+	// Without an etherbase the node isn't a producer, and all of them count
+	self, _ := s.Etherbase()
+
+	if s.p2pServer != nil && !reachableProducers(check.Producers, self, s.config.ProducerPeers, s.connectedPeers()) {
+		issues = append(issues, "no producer reachable among the peers")
+	}
+
+	if len(issues) == 0 {
+		if *healthy != check.SpanID {
+			log.Info("Upcoming span validated", "span", check.SpanID, "start", check.StartBlock, "producers", len(check.Producers), "committed", check.Committed)
+			*healthy = check.SpanID
+		}
+
+		return
+	}
+
+	*healthy = 0
+
+	log.Error("Upcoming span looks unhealthy", "span", check.SpanID, "start", check.StartBlock, "remaining", current.SpanEndBlock+1-number, "committed", check.Committed, "issues", strings.Join(issues, "; "))
+	s.alerts.Notify(alert.SpanUnhealthy, fmt.Sprintf("span %d starting at block %d, in %d blocks: %s", check.SpanID, check.StartBlock, current.SpanEndBlock+1-number, strings.Join(issues, "; ")))
+}
+
+// connectedPeers returns the ids of the peers the node is connected to.
+func (s *Ethereum) connectedPeers() map[enode.ID]bool {
+	connected := make(map[enode.ID]bool)
+
+	for _, peer := range s.p2pServer.Peers() {
+		connected[peer.ID()] = true
+	}
+
+	return connected
+}
+
+// reachableProducers reports whether a producer other than the node itself is
+// connected through one of its endpoints in the address book. Reachability is
+// assumed when it can't be told, that is if the node is the only producer or
+// the address book knows none of the others.
+func reachableProducers(producers []common.Address, self common.Address, book map[common.Address][]*enode.Node, connected map[enode.ID]bool) bool {
+	known := false
+
+	for _, producer := range producers {
+		if producer == self {
+			continue
+		}
+
+		for _, node := range book[producer] {
+			known = true
+
+			if connected[node.ID()] {
+				return true
+			}
+		}
+	}
+
+	return !known
+}
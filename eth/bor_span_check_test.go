@@ -0,0 +1,41 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestReachableProducers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		self  = common.Address{0x1}
+		alice = common.Address{0x2}
+		bob   = common.Address{0x3}
+
+		selfNode  = testProducerNode(t)
+		aliceNode = testProducerNode(t)
+		bobSentry = testProducerNode(t)
+
+		book = map[common.Address][]*enode.Node{
+			self:  {selfNode},
+			alice: {aliceNode},
+			bob:   {bobSentry},
+		}
+	)
+
+	// Connected to one of the other producers
+	require.True(t, reachableProducers([]common.Address{self, alice, bob}, self, book, map[enode.ID]bool{bobSentry.ID(): true}))
+
+	// Connected to none of them, being connected to itself doesn't count
+	require.False(t, reachableProducers([]common.Address{self, alice, bob}, self, book, map[enode.ID]bool{selfNode.ID(): true}))
+
+	// Alone in the span, or with producers missing from the address book
+	require.True(t, reachableProducers([]common.Address{self}, self, book, nil))
+	require.True(t, reachableProducers([]common.Address{self, {0x4}}, self, book, nil))
+	require.True(t, reachableProducers([]common.Address{alice, bob}, common.Address{}, nil, nil))
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -303,6 +304,19 @@ func (d *Downloader) Progress() ethereum.SyncProgress {
 // RegisterPeer injects a new download peer into the set of block source to be
 // used for fetching hashes and blocks from.
 func (d *Downloader) RegisterPeer(id string, version uint, peer Peer) error {
+	return d.registerPeer(id, version, peer, false)
+}
+
+// RegisterHistoryPeer injects a new download peer lacking the bor extensions,
+// e.g. a vanilla go-ethereum node. Such peers still serve headers and bodies,
+// but can't be expected to have the latest whitelisted blocks, so they aren't
+// rejected for missing them. The downloaded chain is validated against the
+// whitelist on import all the same.
+func (d *Downloader) RegisterHistoryPeer(id string, version uint, peer Peer) error {
+	return d.registerPeer(id, version, peer, true)
+}
+
+func (d *Downloader) registerPeer(id string, version uint, peer Peer, history bool) error {
 	var logger log.Logger
 	if len(id) < 16 {
 		// Tests use short IDs, don't choke on them
@@ -311,9 +325,12 @@ func (d *Downloader) RegisterPeer(id string, version uint, peer Peer) error {
 		logger = log.New("peer", id[:8])
 	}
 
-	logger.Trace("Registering sync peer")
+	logger.Trace("Registering sync peer", "history", history)
+
+	conn := newPeerConnection(id, version, peer, logger)
+	conn.history = history
 
-	if err := d.peers.Register(newPeerConnection(id, version, peer, logger)); err != nil {
+	if err := d.peers.Register(conn); err != nil {
 		logger.Error("Failed to register sync peer", "err", err)
 		return err
 	}
@@ -920,7 +937,12 @@ func (d *Downloader) findAncestor(p *peerConnection, remoteHeader *types.Header)
 			return 0, err
 		}
 
-		if errors.Is(err, whitelist.ErrNoRemote) {
+		if errors.Is(err, whitelist.ErrNoRemote) && p.history && d.isWhitelisted(remoteHeight) {
+			// Peers without the bor extensions may lag behind the whitelisted blocks while
+			// still serving history, the downloaded chain ends below them and is validated
+			// on import instead
+			log.Debug("Remote peer lacks bor extensions, skipping validation", "id", p.id, "local", localHeight, "remote", remoteHeight, "err", err)
+		} else if errors.Is(err, whitelist.ErrNoRemote) {
 			// Don't validate the peer against whitelisted milestones until the different of
 			// our local height and remote peer's height is less than `maxValidationThreshold`
 			if localHeight >= remoteHeight-d.maxValidationThreshold {
@@ -962,6 +984,18 @@ func (d *Downloader) findAncestor(p *peerConnection, remoteHeader *types.Header)
 	return ancestor, nil
 }
 
+// isWhitelisted reports whether the given block is at or below the last
+// whitelisted milestone or checkpoint.
+func (d *Downloader) isWhitelisted(number uint64) bool {
+	if exists, milestone, _ := d.GetWhitelistedMilestone(); exists && number <= milestone {
+		return true
+	}
+
+	exists, checkpoint, _ := d.GetWhitelistedCheckpoint()
+
+	return exists && number <= checkpoint
+}
+
 func (d *Downloader) findAncestorSpanSearch(p *peerConnection, mode SyncMode, remoteHeight, localHeight uint64, floor int64) (uint64, error) {
 	from, count, skip, max := calculateRequestSpan(remoteHeight, localHeight)
 
@@ -1484,7 +1518,8 @@ func (d *Downloader) processHeaders(origin uint64, td, ttd *big.Int, beaconMode
 
 					if len(chunkHeaders) > 0 {
 						if n, err := d.lightchain.InsertHeaderChain(chunkHeaders); err != nil {
-							log.Warn("Invalid header encountered", "number", chunkHeaders[n].Number, "hash", chunkHashes[n], "parent", chunkHeaders[n].ParentHash, "err", err)
+							logCtx := []interface{}{"number", chunkHeaders[n].Number, "hash", chunkHashes[n], "parent", chunkHeaders[n].ParentHash, "err", err}
+							log.Warn("Invalid header encountered", append(logCtx, consensus.ErrorContext(err)...)...)
 
 							return fmt.Errorf("%w: %v", errInvalidChain, err)
 						}
@@ -1622,7 +1657,8 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 	// consensus-layer.
 	if index, err := d.blockchain.InsertChain(blocks); err != nil {
 		if index < len(results) {
-			log.Debug("Downloaded item processing failed", "number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err)
+			logCtx := []interface{}{"number", results[index].Header.Number, "hash", results[index].Header.Hash(), "err", err}
+			log.Debug("Downloaded item processing failed", append(logCtx, consensus.ErrorContext(err)...)...)
 
 			// In post-merge, notify the engine API of encountered bad chains
 			if d.badBlock != nil {
@@ -1638,7 +1674,7 @@ func (d *Downloader) importBlockResults(results []*fetchResult) error {
 			// when it needs to preprocess blocks to import a sidechain.
 			// The importer will put together a new list of blocks to import, which is a superset
 			// of the blocks delivered from the downloader, and the indexing will be off.
-			log.Debug("Downloaded item processing failed on sidechain import", "index", index, "err", err)
+			log.Debug("Downloaded item processing failed on sidechain import", append([]interface{}{"index", index, "err", err}, consensus.ErrorContext(err)...)...)
 		}
 
 		// If we've received too long future chain error (from whitelisting service),
@@ -1480,11 +1480,14 @@ type whitelistFake struct {
 
 	// validate is the dynamic function to be called while syncing
 	validate func(count int) (bool, error)
+
+	// milestone is the number of the whitelisted milestone, if not zero
+	milestone uint64
 }
 
 // newWhitelistFake returns a new mock whitelist
 func newWhitelistFake(validate func(count int) (bool, error)) *whitelistFake {
-	return &whitelistFake{0, validate}
+	return &whitelistFake{validate: validate}
 }
 
 // IsValidPeer is the mock function which the downloader will use to validate the chain
@@ -1510,7 +1513,7 @@ func (w *whitelistFake) PurgeWhitelistedCheckpoint() {}
 func (w *whitelistFake) ProcessMilestone(_ uint64, _ common.Hash)       {}
 func (w *whitelistFake) ProcessFutureMilestone(_ uint64, _ common.Hash) {}
 func (w *whitelistFake) GetWhitelistedMilestone() (bool, uint64, common.Hash) {
-	return false, 0, common.Hash{}
+	return w.milestone != 0, w.milestone, common.Hash{}
 }
 func (w *whitelistFake) PurgeWhitelistedMilestone() {}
 
@@ -1529,6 +1532,9 @@ func (w *whitelistFake) RemoveMilestoneID(milestoneId string) {
 func (w *whitelistFake) GetMilestoneIDsList() []string {
 	return nil
 }
+func (w *whitelistFake) GetLockedMilestone() (bool, uint64, common.Hash) {
+	return false, 0, common.Hash{}
+}
 
 // TestFakedSyncProgress67WhitelistMismatch tests if in case of whitelisted
 // checkpoint mismatch with opposite peer, the sync should fail.
@@ -1645,3 +1651,94 @@ func TestFakedSyncProgress67BypassWhitelistValidation(t *testing.T) {
 	err := tester.sync("light", nil, mode)
 	assert.NoError(t, err, "failed synchronisation")
 }
+
+// TestFakedSyncProgress67HistoryPeerNoRemote tests that a peer lacking the bor
+// extensions isn't rejected for missing the whitelisted blocks when its chain
+// ends below them, even when it's close to the local chain.
+func TestFakedSyncProgress67HistoryPeerNoRemote(t *testing.T) {
+	t.Parallel()
+
+	protocol := uint(eth.ETH67)
+	mode := FullSync
+
+	tester := newTester(t)
+	validate := func(count int) (bool, error) {
+		return false, whitelist.ErrNoRemote
+	}
+
+	defer tester.terminate()
+
+	chainA := testChainForkLightA.blocks
+	tester.newPeer("light", protocol, chainA[1:])
+	tester.downloader.peers.Peer("light").history = true
+
+	validator := newWhitelistFake(validate)
+	validator.milestone = uint64(len(chainA))
+	tester.downloader.ChainValidator = validator
+
+	// Set the max validation threshold equal to chain length to enforce validation
+	tester.downloader.maxValidationThreshold = uint64(len(chainA) - 1)
+
+	if err := tester.sync("light", nil, mode); err != nil {
+		t.Fatalf("failed to synchronise with history peer: %v", err)
+	}
+}
+
+// TestFakedSyncProgress67HistoryPeerPastWhitelist tests that a peer lacking the
+// bor extensions is still rejected for missing the whitelisted blocks when its
+// chain goes past them.
+func TestFakedSyncProgress67HistoryPeerPastWhitelist(t *testing.T) {
+	t.Parallel()
+
+	protocol := uint(eth.ETH67)
+	mode := FullSync
+
+	tester := newTester(t)
+	validate := func(count int) (bool, error) {
+		return false, whitelist.ErrNoRemote
+	}
+
+	defer tester.terminate()
+
+	chainA := testChainForkLightA.blocks
+	tester.newPeer("light", protocol, chainA[1:])
+	tester.downloader.peers.Peer("light").history = true
+
+	validator := newWhitelistFake(validate)
+	validator.milestone = uint64(len(chainA) - 2)
+	tester.downloader.ChainValidator = validator
+
+	// Set the max validation threshold equal to chain length to enforce validation
+	tester.downloader.maxValidationThreshold = uint64(len(chainA) - 1)
+
+	err := tester.sync("light", nil, mode)
+	assert.ErrorIs(t, err, whitelist.ErrNoRemote, "synchronised with history peer past the whitelisted blocks")
+}
+
+// TestFakedSyncProgress67BorPeerNoRemote tests that a peer running the bor
+// extensions is rejected for missing the whitelisted blocks when it's close to
+// the local chain.
+func TestFakedSyncProgress67BorPeerNoRemote(t *testing.T) {
+	t.Parallel()
+
+	protocol := uint(eth.ETH67)
+	mode := FullSync
+
+	tester := newTester(t)
+	validate := func(count int) (bool, error) {
+		return false, whitelist.ErrNoRemote
+	}
+
+	tester.downloader.ChainValidator = newWhitelistFake(validate)
+
+	defer tester.terminate()
+
+	chainA := testChainForkLightA.blocks
+	tester.newPeer("light", protocol, chainA[1:])
+
+	// Set the max validation threshold equal to chain length to enforce validation
+	tester.downloader.maxValidationThreshold = uint64(len(chainA) - 1)
+
+	err := tester.sync("light", nil, mode)
+	assert.ErrorIs(t, err, whitelist.ErrNoRemote, "synchronised with bor peer missing whitelisted blocks")
+}
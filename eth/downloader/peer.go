@@ -51,6 +51,7 @@ type peerConnection struct {
 	peer Peer
 
 	version uint       // Eth protocol version number to switch strategies
+	history bool       // Whether the peer lacks the bor extensions, e.g. vanilla go-ethereum
 	log     log.Logger // Contextual logger to add extra infos to peer logs
 	lock    sync.RWMutex
 }
@@ -37,7 +37,7 @@ func (w *checkpoint) IsValidChain(currentHeader *types.Header, chain []*types.He
 	if res {
 		CheckpointChainMeter.Mark(int64(1))
 	} else {
-		CheckpointPeerMeter.Mark(int64(-1))
+		CheckpointChainMeter.Mark(int64(-1))
 	}
 
 	return res, err
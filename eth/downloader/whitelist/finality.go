@@ -58,14 +58,11 @@ func (f *finality[T]) IsValidChain(currentHeader *types.Header, chain []*types.H
 // reportWhitelist logs the block number and hash if a new and unique entry is being inserted
 // and it doesn't log for duplicate/redundant entries.
 func (f *finality[T]) reportWhitelist(block uint64, hash common.Hash) {
-	msg := fmt.Sprintf("Whitelisting new %s from heimdall", f.name)
-	if !f.doExist {
-		log.Info(msg, "block", block, "hash", hash)
-	} else {
-		if f.Number != block && f.Hash != hash {
-			log.Info(msg, "block", block, "hash", hash)
-		}
+	if f.doExist && (f.Number == block || f.Hash == hash) {
+		return
 	}
+
+	log.Info(fmt.Sprintf("Whitelisting new %s from heimdall", f.name), "block", block, "hash", hash)
 }
 
 func (f *finality[T]) Process(block uint64, hash common.Hash) {
@@ -93,7 +90,7 @@ func (f *finality[T]) Get() (bool, uint64, common.Hash) {
 
 	block, hash, err := rawdb.ReadFinality[T](f.db)
 	if err != nil {
-		fmt.Println("Error while reading whitelisted state from Db", "err", err)
+		log.Error("Error while reading whitelisted state from Db", "err", err)
 		return false, f.Number, f.Hash
 	}
 
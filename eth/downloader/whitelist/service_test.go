@@ -162,6 +162,10 @@ func TestMilestone(t *testing.T) {
 	require.True(t, milestone.Locked, "expected true as final confirmation regarding the lock has been made")
 	require.Equal(t, len(milestone.LockedMilestoneIDs), 1, "expected 1 as previous milestonesIDs has been removed in previous step")
 
+	locked, lockedNumber, _ := s.GetLockedMilestone()
+	require.True(t, locked, "expected true as the sprint is locked")
+	require.Equal(t, uint64(15), lockedNumber, "expected 15 as the locked milestone number")
+
 	//Adding the milestone
 	s.ProcessMilestone(11, common.Hash{})
 
@@ -31,17 +31,20 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/span"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdallapp"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdallgrpc"
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdallrecord"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/miner"
+	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -144,6 +147,13 @@ type Config struct {
 	// This is the number of blocks for which logs will be cached in the filter system.
 	FilterLogCacheSize int
 
+	// Contracts whose code and storage are preloaded into the caches at startup,
+	// along with the genesis system contracts on bor chains
+	PreloadContracts []common.Address
+
+	// Maximum number of storage slots preloaded per contract
+	PreloadStorageSlots int
+
 	// Mining options
 	Miner miner.Config
 
@@ -173,6 +183,9 @@ type Config struct {
 	// Maximum size (in bytes) a result of an rpc request could have
 	RPCReturnDataLimit uint64
 
+	// RPCBorMetadata adds the bor metadata of blocks to the block responses.
+	RPCBorMetadata bool
+
 	// RPCEVMTimeout is the global timeout for eth-call.
 	RPCEVMTimeout time.Duration
 
@@ -189,9 +202,24 @@ type Config struct {
 	// No heimdall service
 	WithoutHeimdall bool
 
+	// Heimdall client used instead of the one configured above (e.g. when embedding the node)
+	HeimdallClient bor.IHeimdallClient `toml:"-"`
+
+	// Fee currency paying the transaction fees from the fee currency block of the bor config on (e.g. when embedding the node for an app-chain)
+	FeeCurrency vm.FeeCurrency `toml:"-"`
+
 	// Address to connect to Heimdall gRPC server
 	HeimdallgRPCAddress string
 
+	// File the responses of the Heimdall service are recorded to
+	HeimdallRecordFile string
+
+	// File of recorded Heimdall responses served instead of a Heimdall service
+	HeimdallReplayFile string
+
+	// File of recorded state-sync events committed when running without Heimdall
+	StateSyncReplayFile string
+
 	// Run heimdall service as a child process
 	RunHeimdall bool
 
@@ -215,6 +243,76 @@ type Config struct {
 
 	// EnableBlockTracking allows logging of information collected while tracking block lifecycle
 	EnableBlockTracking bool
+
+	// URL of the webhook consensus alerts are posted to, alerts are disabled if empty
+	AlertWebhook string
+
+	// Submit the evidence of double signing validators to heimdall for slashing
+	ReportDoubleSign bool
+
+	// Number of blocks beyond which reorgs are refused until allowed with
+	// admin_allowDeepReorg (0 = no limit)
+	MaxReorgDepth uint64
+
+	// Number of heimdall checkpoints non-canonical blocks are retained for
+	// (0 = until the blocks are frozen)
+	NonCanonicalRetention uint64
+
+	// Number of workers recovering the signers of header batches being
+	// verified by bor (0 = number of CPUs)
+	VerifyWorkers int
+
+	// Number of recent block signers cached by bor (0 = 4096)
+	SignatureCache int
+
+	// Number of blocks after which the bor snapshot is stored to the database
+	// (0 = 1024), and whether the snapshot of every sprint is stored instead
+	SnapshotInterval uint64
+	SprintSnapshots  bool
+
+	// Whether the bor snapshots between the ones stored in full are stored as
+	// diffs over the previous one, every sprint
+	SnapshotDiffs bool
+
+	// Whether the validator set of every sprint is archived by bor
+	ValidatorArchive bool
+
+	// Whether the spans served by heimdall are checked against the span
+	// committed on chain, warning about the ones failing the check
+	VerifySpans bool
+
+	// Endpoints announced by the block producers, the node stays connected to
+	// the producers of the current and next span
+	ProducerPeers map[common.Address][]*enode.Node `toml:"-"`
+
+	// Maximum offset of the local clock from NTP before sealing is refused (0 = disabled)
+	ClockMaxOffset time.Duration
+
+	// NTP servers the local clock is checked against (empty = clock.DefaultServers)
+	ClockServers []string
+
+	// URL of the signed fork manifest of the network the local fork schedule is
+	// checked against (empty = disabled), and the address it must be signed by
+	ForkManifestURL    string
+	ForkManifestSigner common.Address
+
+	// Whether the /readyz and /livez endpoints are served on the HTTP-RPC server
+	HealthEndpoints bool
+
+	// Number of blocks the head may trail the milestone tip by while ready
+	HealthMaxLag uint64
+
+	// Time the import or sealing loop may not progress before the node isn't live
+	HealthStallTimeout time.Duration
+
+	// Time the chain head may not move before an alert is raised (0 = disabled)
+	AlertHeadStall time.Duration
+
+	// Time heimdall may be unreachable before an alert is raised (0 = disabled)
+	AlertHeimdallDown time.Duration
+
+	// Standard deviations a block may deviate from its producer's profile before it's flagged (0 = disabled)
+	AlertAnomalyThreshold float64
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain configuration.
@@ -226,18 +324,55 @@ func CreateConsensusEngine(chainConfig *params.ChainConfig, ethConfig *Config, d
 		// If Matic bor consensus is requested, set it up
 		// In order to pass the ethereum transaction tests, we need to set the burn contract which is in the bor config
 		// Then, bor != nil will also be enabled for ethash and clique. Only enable Bor for real if there is a validator contract present.
-		genesisContractsClient := contract.NewGenesisContractsClient(chainConfig, chainConfig.Bor.ValidatorContract, chainConfig.Bor.StateReceiverContract, blockchainAPI)
-		spanner := span.NewChainSpanner(blockchainAPI, contract.ValidatorSet(), chainConfig, common.HexToAddress(chainConfig.Bor.ValidatorContract))
+		if err := chainConfig.Bor.Validate(); err != nil {
+			return nil, err
+		}
+
+		caller := ethapi.NewBorCaller(blockchainAPI)
+		genesisContractsClient := contract.NewGenesisContractsClient(chainConfig, chainConfig.Bor.ValidatorContract, chainConfig.Bor.StateReceiverContract, caller)
+		spanner := span.NewChainSpanner(caller, contract.ValidatorSet(), chainConfig, common.HexToAddress(chainConfig.Bor.ValidatorContract))
 
 		if ethConfig.WithoutHeimdall {
-			return bor.New(chainConfig, db, blockchainAPI, spanner, nil, genesisContractsClient, ethConfig.DevFakeAuthor), nil
+			engine := bor.New(chainConfig, db, caller, spanner, nil, genesisContractsClient, ethConfig.DevFakeAuthor)
+
+			if ethConfig.StateSyncReplayFile == "" {
+				log.Warn("Running without heimdall, spans are generated locally from the genesis validators and state syncs are skipped")
+
+				return engine, nil
+			}
+
+			feed, err := heimdallrecord.NewStateSyncFeed(ethConfig.StateSyncReplayFile, chainConfig.ChainID.String())
+			if err != nil {
+				return nil, err
+			}
+
+			first, last := feed.Range()
+			log.Warn("Running without heimdall, spans are generated locally from the genesis validators and state syncs are replayed",
+				"file", ethConfig.StateSyncReplayFile, "events", feed.Len(), "first", first, "last", last)
+
+			engine.SetStateSyncSource(feed)
+
+			return engine, nil
 		} else {
 			if ethConfig.DevFakeAuthor {
 				log.Warn("Sanitizing DevFakeAuthor", "Use DevFakeAuthor with", "--bor.withoutheimdall")
 			}
 
+			if ethConfig.StateSyncReplayFile != "" {
+				log.Warn("Ignoring the state-sync replay file", "Use it with", "--bor.withoutheimdall")
+			}
+
 			var heimdallClient bor.IHeimdallClient
-			if ethConfig.RunHeimdall && ethConfig.UseHeimdallApp {
+			if ethConfig.HeimdallReplayFile != "" {
+				replayer, err := heimdallrecord.NewReplayer(ethConfig.HeimdallReplayFile)
+				if err != nil {
+					return nil, err
+				}
+
+				heimdallClient = replayer
+			} else if ethConfig.HeimdallClient != nil {
+				heimdallClient = ethConfig.HeimdallClient
+			} else if ethConfig.RunHeimdall && ethConfig.UseHeimdallApp {
 				heimdallClient = heimdallapp.NewHeimdallAppClient()
 			} else if ethConfig.HeimdallgRPCAddress != "" {
 				heimdallClient = heimdallgrpc.NewHeimdallGRPCClient(ethConfig.HeimdallgRPCAddress)
@@ -245,7 +380,16 @@ func CreateConsensusEngine(chainConfig *params.ChainConfig, ethConfig *Config, d
 				heimdallClient = heimdall.NewHeimdallClient(ethConfig.HeimdallURL)
 			}
 
-			return bor.New(chainConfig, db, blockchainAPI, spanner, heimdallClient, genesisContractsClient, false), nil
+			if ethConfig.HeimdallRecordFile != "" && ethConfig.HeimdallReplayFile == "" {
+				recorder, err := heimdallrecord.NewRecorder(heimdallClient, ethConfig.HeimdallRecordFile)
+				if err != nil {
+					return nil, err
+				}
+
+				heimdallClient = recorder
+			}
+
+			return bor.New(chainConfig, db, caller, spanner, heimdallClient, genesisContractsClient, false), nil
 		}
 	}
 	// If defaulting to proof-of-work, enforce an already merged network since
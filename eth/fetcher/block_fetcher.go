@@ -22,8 +22,11 @@ import (
 	"math/rand"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/prque"
+	"github.com/ethereum/go-ethereum/common/tracing"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
@@ -917,8 +920,15 @@ func (f *BlockFetcher) importBlocks(peer string, block *types.Block) {
 			log.Debug("Unknown parent of propagated block", "peer", peer, "number", block.Number(), "hash", hash, "parent", block.ParentHash())
 			return
 		}
+		traceBlockArrival(peer, block)
+
 		// Quickly validate the header and propagate the block if it passes
-		switch err := f.verifyHeader(block.Header()); err {
+		vstart := time.Now()
+		err := f.verifyHeader(block.Header())
+
+		tracing.RecordBlockStage(tracing.BlockVerify, hash, block.NumberU64(), vstart, time.Now(), attribute.Bool("valid", err == nil || err == consensus.ErrFutureBlock))
+
+		switch err {
 		case nil:
 			// All ok, quickly propagate to our peers
 			blockBroadcastOutTimer.UpdateSince(block.ReceivedAt)
@@ -977,6 +987,25 @@ func (f *BlockFetcher) importBlocks(peer string, block *types.Block) {
 	}()
 }
 
+// traceBlockArrival records the announcement and retrieval of a propagated block
+// as stages of its lifecycle.
+func traceBlockArrival(peer string, block *types.Block) {
+	if !tracing.BlockTracingEnabled() {
+		return
+	}
+
+	hash, number := block.Hash(), block.NumberU64()
+
+	if block.AnnouncedAt == nil {
+		// The full block was propagated to us
+		tracing.RecordBlockStage(tracing.BlockAnnounce, hash, number, block.ReceivedAt, block.ReceivedAt, attribute.String("peer", peer), attribute.Bool("propagated", true))
+		return
+	}
+
+	tracing.RecordBlockStage(tracing.BlockAnnounce, hash, number, *block.AnnouncedAt, *block.AnnouncedAt, attribute.String("peer", peer), attribute.Bool("propagated", false))
+	tracing.RecordBlockStage(tracing.BlockFetch, hash, number, *block.AnnouncedAt, block.ReceivedAt, attribute.String("peer", peer))
+}
+
 // forgetHash removes all traces of a block announcement from the fetcher's
 // internal state.
 func (f *BlockFetcher) forgetHash(hash common.Hash) {
@@ -299,3 +299,17 @@ func (mr *MockBackendMockRecorder) SubscribeStateSyncEvent(arg0 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeStateSyncEvent", reflect.TypeOf((*MockBackend)(nil).SubscribeStateSyncEvent), arg0)
 }
+
+// SubscribeStateSyncCommitEvent mocks base method.
+func (m *MockBackend) SubscribeStateSyncCommitEvent(arg0 chan<- core.StateSyncCommitEvent) event.Subscription {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeStateSyncCommitEvent", arg0)
+	ret0, _ := ret[0].(event.Subscription)
+	return ret0
+}
+
+// SubscribeStateSyncCommitEvent indicates an expected call of SubscribeStateSyncCommitEvent.
+func (mr *MockBackendMockRecorder) SubscribeStateSyncCommitEvent(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeStateSyncCommitEvent", reflect.TypeOf((*MockBackend)(nil).SubscribeStateSyncCommitEvent), arg0)
+}
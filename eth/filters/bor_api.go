@@ -6,6 +6,8 @@ import (
 
 	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -85,3 +87,93 @@ func (api *FilterAPI) NewDeposits(ctx context.Context, crit ethereum.StateSyncFi
 
 	return rpcSub, nil
 }
+
+// StateSyncCriteria restricts the state syncs notified to the ones received by
+// the given contracts, or notifies all of them if there are none.
+type StateSyncCriteria struct {
+	Contracts []common.Address `json:"contracts"`
+}
+
+// CommittedStateSync is a state sync committed on the canonical chain.
+type CommittedStateSync struct {
+	StateID     hexutil.Uint64 `json:"stateId"`
+	Contract    common.Address `json:"contract"` // Receiver contract
+	Data        hexutil.Bytes  `json:"data"`
+	L1TxHash    common.Hash    `json:"l1TxHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"` // Block committing the state sync
+	BlockHash   common.Hash    `json:"blockHash"`
+}
+
+// matches returns whether the state sync is received by one of the contracts
+// of the criteria.
+func (crit *StateSyncCriteria) matches(data *types.StateSyncData) bool {
+	if crit == nil || len(crit.Contracts) == 0 {
+		return true
+	}
+
+	for _, contract := range crit.Contracts {
+		if contract == data.Contract {
+			return true
+		}
+	}
+
+	return false
+}
+
+// committedStateSyncs returns the state syncs committed by a block matching the
+// criteria, in the order they were committed.
+func committedStateSyncs(ev core.StateSyncCommitEvent, crit *StateSyncCriteria) []*CommittedStateSync {
+	var syncs []*CommittedStateSync
+
+	for _, data := range ev.Events {
+		if !crit.matches(data) {
+			continue
+		}
+
+		syncs = append(syncs, &CommittedStateSync{
+			StateID:     hexutil.Uint64(data.ID),
+			Contract:    data.Contract,
+			Data:        common.FromHex(data.Data),
+			L1TxHash:    data.TxHash,
+			BlockNumber: hexutil.Uint64(ev.Number),
+			BlockHash:   ev.Hash,
+		})
+	}
+
+	return syncs
+}
+
+// StateSyncs sends a notification for every state sync committed on the
+// canonical chain, in the order of their state ids. The state syncs committed
+// by a block are notified once it becomes canonical, so a reorg can notify
+// the same state sync again for the block committing it on the new chain.
+func (api *FilterAPI) StateSyncs(ctx context.Context, crit *StateSyncCriteria) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		commits := make(chan core.StateSyncCommitEvent, 10)
+		commitsSub := api.events.SubscribeStateSyncCommits(commits)
+
+		defer commitsSub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-commits:
+				for _, sync := range committedStateSyncs(ev, crit) {
+					notifier.Notify(rpcSub.ID, sync)
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
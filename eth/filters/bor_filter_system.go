@@ -14,6 +14,12 @@ func (es *EventSystem) handleStateSyncEvent(filters filterIndex, ev core.StateSy
 	}
 }
 
+func (es *EventSystem) handleStateSyncCommitEvent(filters filterIndex, ev core.StateSyncCommitEvent) {
+	for _, f := range filters[StateSyncCommitSubscription] {
+		f.stateSyncCommits <- ev
+	}
+}
+
 // SubscribeNewDeposits creates a subscription that writes details about the new state sync events (from mainchain to Bor)
 func (es *EventSystem) SubscribeNewDeposits(data chan *types.StateSyncData) *Subscription {
 	sub := &subscription{
@@ -30,3 +36,21 @@ func (es *EventSystem) SubscribeNewDeposits(data chan *types.StateSyncData) *Sub
 
 	return es.subscribe(sub)
 }
+
+// SubscribeStateSyncCommits creates a subscription that writes the state syncs
+// committed by every block once it's on the canonical chain.
+func (es *EventSystem) SubscribeStateSyncCommits(commits chan core.StateSyncCommitEvent) *Subscription {
+	sub := &subscription{
+		id:               rpc.NewID(),
+		typ:              StateSyncCommitSubscription,
+		created:          time.Now(),
+		logs:             make(chan []*types.Log),
+		txs:              make(chan []*types.Transaction),
+		headers:          make(chan *types.Header),
+		stateSyncCommits: commits,
+		installed:        make(chan struct{}),
+		err:              make(chan error),
+	}
+
+	return es.subscribe(sub)
+}
@@ -3,9 +3,14 @@ package filters
 import (
 	"context"
 	"math/big"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	types "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
@@ -156,3 +161,64 @@ func TestBorFilters(t *testing.T) {
 		t.Error("expected 0 log, got", len(logs))
 	}
 }
+
+// Tests that the state syncs committed on the canonical chain are delivered to
+// the subscribers, restricted to the contracts they ask for.
+func TestStateSyncCommitSubscription(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend, sys = newTestFilterSystem(t, rawdb.NewMemoryDatabase(), Config{})
+		api          = NewFilterAPI(sys, true)
+
+		bridge = common.HexToAddress("0x0000000000000000000000000000000000001001")
+		token  = common.HexToAddress("0x0000000000000000000000000000000000001010")
+
+		ev = core.StateSyncCommitEvent{
+			Number: 16,
+			Hash:   common.HexToHash("0x16"),
+			Events: []*types.StateSyncData{
+				{ID: 7, Contract: bridge, Data: "c0ffee", TxHash: common.HexToHash("0x7")},
+				{ID: 8, Contract: token, Data: "", TxHash: common.HexToHash("0x8")},
+			},
+		}
+	)
+
+	commits := make(chan core.StateSyncCommitEvent)
+	sub := api.events.SubscribeStateSyncCommits(commits)
+
+	defer sub.Unsubscribe()
+
+	backend.stateSyncCommitFeed.Send(ev)
+
+	select {
+	case got := <-commits:
+		if got.Hash != ev.Hash || len(got.Events) != len(ev.Events) {
+			t.Fatalf("received commits of block %x with %d events, want %x with %d", got.Hash, len(got.Events), ev.Hash, len(ev.Events))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("committed state syncs not delivered")
+	}
+
+	syncs := committedStateSyncs(ev, nil)
+	if len(syncs) != 2 {
+		t.Fatalf("notified %d state syncs, want 2", len(syncs))
+	}
+
+	want := &CommittedStateSync{
+		StateID:     7,
+		Contract:    bridge,
+		Data:        hexutil.Bytes{0xc0, 0xff, 0xee},
+		L1TxHash:    common.HexToHash("0x7"),
+		BlockNumber: 16,
+		BlockHash:   common.HexToHash("0x16"),
+	}
+	if !reflect.DeepEqual(syncs[0], want) {
+		t.Fatalf("notified %+v, want %+v", syncs[0], want)
+	}
+
+	syncs = committedStateSyncs(ev, &StateSyncCriteria{Contracts: []common.Address{token}})
+	if len(syncs) != 1 || uint64(syncs[0].StateID) != 8 {
+		t.Fatalf("notified %+v, want the state sync 8 only", syncs)
+	}
+}
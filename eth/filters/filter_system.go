@@ -75,6 +75,7 @@ type Backend interface {
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
 	SubscribePendingLogsEvent(ch chan<- []*types.Log) event.Subscription
 	SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) event.Subscription
+	SubscribeStateSyncCommitEvent(ch chan<- core.StateSyncCommitEvent) event.Subscription
 
 	BloomStatus() (uint64, uint64)
 	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
@@ -175,6 +176,9 @@ const (
 	BlocksSubscription
 	// StateSyncSubscription to listen main chain state
 	StateSyncSubscription
+	// StateSyncCommitSubscription queries for the state syncs committed on the
+	// canonical chain
+	StateSyncCommitSubscription
 	// LastIndexSubscription keeps track of the last index
 	LastIndexSubscription
 )
@@ -206,7 +210,8 @@ type subscription struct {
 	installed chan struct{} // closed when the filter is installed
 	err       chan error    // closed when the filter is uninstalled
 
-	stateSyncData chan *types.StateSyncData
+	stateSyncData    chan *types.StateSyncData
+	stateSyncCommits chan core.StateSyncCommitEvent
 }
 
 // EventSystem creates subscriptions, processes events and broadcasts them to the
@@ -232,8 +237,10 @@ type EventSystem struct {
 	chainCh       chan core.ChainEvent       // Channel to receive new chain event
 
 	// Bor related subscription and channels
-	stateSyncSub event.Subscription       // Subscription for new state event
-	stateSyncCh  chan core.StateSyncEvent // Channel to receive deposit state change event
+	stateSyncSub       event.Subscription             // Subscription for new state event
+	stateSyncCh        chan core.StateSyncEvent       // Channel to receive deposit state change event
+	stateSyncCommitSub event.Subscription             // Subscription for committed state syncs
+	stateSyncCommitCh  chan core.StateSyncCommitEvent // Channel to receive committed state syncs
 }
 
 // NewEventSystem creates a new manager that listens for event on the given mux,
@@ -254,6 +261,8 @@ func NewEventSystem(sys *FilterSystem) *EventSystem {
 		pendingLogsCh: make(chan []*types.Log, logsChanSize),
 		chainCh:       make(chan core.ChainEvent, chainEvChanSize),
 		stateSyncCh:   make(chan core.StateSyncEvent, stateEvChanSize),
+
+		stateSyncCommitCh: make(chan core.StateSyncCommitEvent, stateEvChanSize),
 	}
 
 	// Subscribe events
@@ -263,6 +272,7 @@ func NewEventSystem(sys *FilterSystem) *EventSystem {
 	m.chainSub = m.backend.SubscribeChainEvent(m.chainCh)
 	m.pendingLogsSub = m.backend.SubscribePendingLogsEvent(m.pendingLogsCh)
 	m.stateSyncSub = m.backend.SubscribeStateSyncEvent(m.stateSyncCh)
+	m.stateSyncCommitSub = m.backend.SubscribeStateSyncCommitEvent(m.stateSyncCommitCh)
 
 	// Make sure none of the subscriptions are empty
 	if m.txsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil || m.pendingLogsSub == nil {
@@ -462,6 +472,7 @@ func (es *EventSystem) eventLoop() {
 		es.rmLogsSub.Unsubscribe()
 		es.chainSub.Unsubscribe()
 		es.stateSyncSub.Unsubscribe()
+		es.stateSyncCommitSub.Unsubscribe()
 	}()
 
 	index := make(filterIndex)
@@ -483,6 +494,8 @@ func (es *EventSystem) eventLoop() {
 			es.handleChainEvent(index, ev)
 		case ev := <-es.stateSyncCh:
 			es.handleStateSyncEvent(index, ev)
+		case ev := <-es.stateSyncCommitCh:
+			es.handleStateSyncCommitEvent(index, ev)
 
 		case f := <-es.install:
 			index[f.typ][f.id] = f
@@ -50,13 +50,18 @@ type testBackend struct {
 	pendingBlock    *types.Block
 	pendingReceipts types.Receipts
 
-	stateSyncFeed event.Feed
+	stateSyncFeed       event.Feed
+	stateSyncCommitFeed event.Feed
 }
 
 func (b *testBackend) SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) event.Subscription {
 	return b.stateSyncFeed.Subscribe(ch)
 }
 
+func (b *testBackend) SubscribeStateSyncCommitEvent(ch chan<- core.StateSyncCommitEvent) event.Subscription {
+	return b.stateSyncCommitFeed.Subscribe(ch)
+}
+
 func (b *testBackend) ChainConfig() *params.ChainConfig {
 	return params.TestChainConfig
 }
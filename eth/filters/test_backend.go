@@ -26,7 +26,8 @@ type TestBackend struct {
 	pendingLogsFeed event.Feed
 	chainFeed       event.Feed
 
-	stateSyncFeed event.Feed
+	stateSyncFeed       event.Feed
+	stateSyncCommitFeed event.Feed
 }
 
 func (b *TestBackend) BloomStatus() (uint64, uint64) {
@@ -178,6 +179,10 @@ func (b *TestBackend) SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) eve
 	return b.stateSyncFeed.Subscribe(ch)
 }
 
+func (b *TestBackend) SubscribeStateSyncCommitEvent(ch chan<- core.StateSyncCommitEvent) event.Subscription {
+	return b.stateSyncCommitFeed.Subscribe(ch)
+}
+
 func (b *TestBackend) ChainConfig() *params.ChainConfig { panic("not implemented") }
 
 func (b *TestBackend) CurrentHeader() *types.Header { panic("not implemented") }
@@ -24,8 +24,11 @@ import (
 	"sync/atomic"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/tracing"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/forkid"
@@ -36,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/fetcher"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/eth/protocols/finality"
 	"github.com/ethereum/go-ethereum/eth/protocols/snap"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
@@ -118,6 +122,7 @@ type handler struct {
 	blockFetcher *fetcher.BlockFetcher
 	txFetcher    *fetcher.TxFetcher
 	peers        *peerSet
+	finality     *finalityRelay
 
 	ethAPI *ethapi.BlockChainAPI // EthAPI to interact
 
@@ -156,6 +161,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		txpool:              config.TxPool,
 		chain:               config.Chain,
 		peers:               newPeerSet(),
+		finality:            newFinalityRelay(),
 		ethAPI:              config.EthAPI,
 		requiredBlocks:      config.RequiredBlocks,
 		enableBlockTracking: config.enableBlockTracking,
@@ -365,8 +371,16 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 	if p == nil {
 		return errors.New("peer dropped during handling")
 	}
-	// Register the peer in the downloader. If the downloader considers it banned, we disconnect
-	if err := h.downloader.RegisterPeer(peer.ID(), peer.Version(), peer); err != nil {
+	// Register the peer in the downloader. If the downloader considers it banned, we disconnect.
+	// Peers without the bor extensions, e.g. vanilla go-ethereum nodes, only serve history.
+	// It's up to the peer not to run them, so the downloader only lets these peers skip
+	// whitelist validation below the whitelisted blocks.
+	register := h.downloader.RegisterPeer
+	if h.chain.Config().Bor != nil && !peer.RunningCap(finality.ProtocolName, finality.ProtocolVersions) {
+		register = h.downloader.RegisterHistoryPeer
+	}
+
+	if err := register(peer.ID(), peer.Version(), peer); err != nil {
 		peer.Log().Error("Failed to register peer in eth syncer", "err", err)
 		return err
 	}
@@ -551,6 +565,11 @@ func (h *handler) BroadcastBlock(block *types.Block, propagate bool) {
 	hash := block.Hash()
 	peers := h.peers.peersWithoutBlock(hash)
 
+	start := time.Now()
+	defer func() {
+		tracing.RecordBlockStage(tracing.BlockBroadcast, hash, block.NumberU64(), start, time.Now(), attribute.Bool("propagate", propagate), attribute.Int("peers", len(peers)))
+	}()
+
 	// If propagation is requested, send to a subset of the peer
 	if propagate {
 		// Calculate the TD of the block (it's not imported yet, so block.Td is not valid)
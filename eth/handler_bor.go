@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -109,6 +110,25 @@ func (h *ethHandler) fetchNoAckMilestoneByID(ctx context.Context, bor *bor.Bor,
 	return err
 }
 
+// advanceFinalized moves the finalized and safe heads of the chain to the
+// block with the given number and hash once it's whitelisted, as long as the
+// block is canonical and ahead of the current finalized head.
+func advanceFinalized(chain *core.BlockChain, number uint64, hash common.Hash) {
+	header := chain.GetHeaderByNumber(number)
+	if header == nil || header.Hash() != hash {
+		return
+	}
+
+	if current := chain.CurrentFinalBlock(); current != nil && current.Number.Uint64() >= number {
+		return
+	}
+
+	chain.SetFinalized(header)
+	chain.SetSafe(header)
+
+	log.Debug("Advanced finalized head", "number", number, "hash", hash)
+}
+
 // reportCommonErrors reports common errors which can occur while fetching data from heimdall. It also
 // returns back the wrapped erorr if required to the caller.
 func reportCommonErrors(msg string, err error, wrapError error, ctx ...interface{}) error {
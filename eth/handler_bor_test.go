@@ -187,3 +187,31 @@ func createMockMilestones(count int) []*milestone.Milestone {
 
 	return milestones
 }
+
+func TestAdvanceFinalized(t *testing.T) {
+	t.Parallel()
+
+	handler := newTestHandlerWithBlocks(16)
+	defer handler.close()
+
+	chain := handler.chain
+	require.Nil(t, chain.CurrentFinalBlock())
+
+	// A hash mismatch doesn't finalize the block
+	advanceFinalized(chain, 8, common.Hash{0x1})
+	require.Nil(t, chain.CurrentFinalBlock())
+
+	header := chain.GetHeaderByNumber(8)
+	advanceFinalized(chain, 8, header.Hash())
+	require.Equal(t, header.Hash(), chain.CurrentFinalBlock().Hash())
+	require.Equal(t, header.Hash(), chain.CurrentSafeBlock().Hash())
+
+	// An older whitelisted block, e.g. a checkpoint, doesn't move the head back
+	older := chain.GetHeaderByNumber(4)
+	advanceFinalized(chain, 4, older.Hash())
+	require.Equal(t, header.Hash(), chain.CurrentFinalBlock().Hash())
+
+	// Blocks beyond the local head can't be finalized yet
+	advanceFinalized(chain, 32, common.Hash{0x1})
+	require.Equal(t, header.Hash(), chain.CurrentFinalBlock().Hash())
+}
@@ -0,0 +1,213 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/eth/protocols/finality"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// finalityQuorum is the number of distinct peers which have to announce the
+// same checkpoint or milestone before it's whitelisted. Heimdall remains the
+// authority, peers only speed up learning about what it already decided.
+const finalityQuorum = 3
+
+// finalityRelay tracks the `finality` peers, the latest checkpoint and
+// milestone announced by each of them and the latest ones known locally.
+type finalityRelay struct {
+	lock      sync.Mutex
+	peers     map[string]*finality.Peer
+	announced map[string]map[finality.Kind]finality.NewFinalityPacket
+	latest    map[finality.Kind]finality.NewFinalityPacket
+}
+
+func newFinalityRelay() *finalityRelay {
+	return &finalityRelay{
+		peers:     make(map[string]*finality.Peer),
+		announced: make(map[string]map[finality.Kind]finality.NewFinalityPacket),
+		latest:    make(map[finality.Kind]finality.NewFinalityPacket),
+	}
+}
+
+// register adds a peer to the relay and returns the latest finality known
+// locally to announce to it.
+func (r *finalityRelay) register(peer *finality.Peer) []finality.NewFinalityPacket {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.peers[peer.ID()] = peer
+	r.announced[peer.ID()] = make(map[finality.Kind]finality.NewFinalityPacket)
+
+	known := make([]finality.NewFinalityPacket, 0, len(r.latest))
+	for _, ann := range r.latest {
+		known = append(known, ann)
+	}
+
+	return known
+}
+
+// unregister removes a peer and its announcements from the relay.
+func (r *finalityRelay) unregister(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.peers, id)
+	delete(r.announced, id)
+}
+
+// announce records the announcement of a peer, replacing its previous one of
+// the same kind, and reports whether a quorum of peers agrees on it.
+func (r *finalityRelay) announce(id string, ann finality.NewFinalityPacket) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	announced, ok := r.announced[id]
+	if !ok {
+		return false
+	}
+
+	announced[ann.Type] = ann
+
+	if latest, ok := r.latest[ann.Type]; ok && latest.Number >= ann.Number {
+		return false
+	}
+
+	var votes int
+
+	for _, announced := range r.announced {
+		if announced[ann.Type] == ann {
+			votes++
+		}
+	}
+
+	return votes >= finalityQuorum
+}
+
+// update records the latest finality known locally and returns the peers to
+// announce it to, or nil if it isn't newer than the one already known.
+func (r *finalityRelay) update(ann finality.NewFinalityPacket) []*finality.Peer {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if latest, ok := r.latest[ann.Type]; ok && latest.Number >= ann.Number {
+		return nil
+	}
+
+	r.latest[ann.Type] = ann
+
+	peers := make([]*finality.Peer, 0, len(r.peers))
+	for _, peer := range r.peers {
+		peers = append(peers, peer)
+	}
+
+	return peers
+}
+
+// broadcastFinality announces a newly whitelisted checkpoint or milestone to
+// all `finality` peers.
+func (h *handler) broadcastFinality(kind finality.Kind, number uint64, hash common.Hash) {
+	peers := h.finality.update(finality.NewFinalityPacket{Type: kind, Number: number, Hash: hash})
+
+	for _, peer := range peers {
+		go func(peer *finality.Peer) {
+			if err := peer.SendNewFinality(kind, number, hash); err != nil {
+				peer.Log().Debug("Failed to announce finality", "kind", kind, "err", err)
+			}
+		}(peer)
+	}
+}
+
+// finalityHandler implements the finality.Backend interface to gossip the
+// latest checkpoint and milestone between bor peers.
+type finalityHandler handler
+
+// RunPeer is invoked when a peer joins on the `finality` protocol. The latest
+// known checkpoint and milestone are announced to it right away.
+func (h *finalityHandler) RunPeer(peer *finality.Peer, hand finality.Handler) error {
+	for _, ann := range h.finality.register(peer) {
+		if err := peer.SendNewFinality(ann.Type, ann.Number, ann.Hash); err != nil {
+			h.finality.unregister(peer.ID())
+			return err
+		}
+	}
+
+	defer h.finality.unregister(peer.ID())
+
+	return hand(peer)
+}
+
+// Handle is invoked from a peer's message handler when it receives a new remote
+// message that the handler couldn't consume and serve itself.
+func (h *finalityHandler) Handle(peer *finality.Peer, packet finality.Packet) error {
+	ann, ok := packet.(*finality.NewFinalityPacket)
+	if !ok {
+		return fmt.Errorf("unexpected finality packet: %s", packet.Name())
+	}
+
+	if h.finality.announce(peer.ID(), *ann) {
+		(*handler)(h).applyFinality(ann)
+	}
+
+	return nil
+}
+
+// applyFinality whitelists a checkpoint or milestone a quorum of peers agrees
+// on, as long as it's newer than the whitelisted one and its end block is in
+// the local canonical chain, and relays it further.
+func (h *handler) applyFinality(ann *finality.NewFinalityPacket) {
+	service := h.downloader.GetWhitelistService()
+	if service == nil {
+		return
+	}
+
+	var (
+		exists bool
+		number uint64
+	)
+
+	switch ann.Type {
+	case finality.Checkpoint:
+		exists, number, _ = service.GetWhitelistedCheckpoint()
+	case finality.Milestone:
+		exists, number, _ = service.GetWhitelistedMilestone()
+	}
+
+	if exists && number >= ann.Number {
+		return
+	}
+
+	header := h.chain.GetHeaderByNumber(ann.Number)
+	if header == nil || header.Hash() != ann.Hash {
+		return
+	}
+
+	switch ann.Type {
+	case finality.Checkpoint:
+		service.ProcessCheckpoint(ann.Number, ann.Hash)
+	case finality.Milestone:
+		service.ProcessMilestone(ann.Number, ann.Hash)
+	}
+
+	advanceFinalized(h.chain, ann.Number, ann.Hash)
+	h.broadcastFinality(ann.Type, ann.Number, ann.Hash)
+
+	log.Debug("Whitelisted finality announced by peers", "kind", ann.Type, "number", ann.Number, "hash", ann.Hash)
+}
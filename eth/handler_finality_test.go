@@ -0,0 +1,63 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/eth/protocols/finality"
+)
+
+func TestFinalityRelayQuorum(t *testing.T) {
+	t.Parallel()
+
+	relay := newFinalityRelay()
+
+	for i := 0; i < finalityQuorum+1; i++ {
+		relay.register(finality.NewFakePeer(finality.FIN1, fmt.Sprintf("peer%d-0123456789", i), nil))
+	}
+
+	ann := finality.NewFinalityPacket{Type: finality.Milestone, Number: 64, Hash: common.Hash{0x1}}
+
+	// A single peer can't get a milestone whitelisted, nor can a conflicting one
+	require.False(t, relay.announce("peer0-0123456789", ann))
+	require.False(t, relay.announce("peer0-0123456789", ann))
+	require.False(t, relay.announce("peer1-0123456789", finality.NewFinalityPacket{Type: finality.Milestone, Number: 64, Hash: common.Hash{0x2}}))
+
+	// Nor can the same block announced as a checkpoint
+	require.False(t, relay.announce("peer1-0123456789", finality.NewFinalityPacket{Type: finality.Checkpoint, Number: 64, Hash: common.Hash{0x1}}))
+
+	require.False(t, relay.announce("peer1-0123456789", ann))
+	require.True(t, relay.announce("peer2-0123456789", ann))
+
+	// Unregistered peers don't count
+	relay.unregister("peer2-0123456789")
+	require.False(t, relay.announce("peer2-0123456789", ann))
+	require.False(t, relay.announce("peer0-0123456789", ann))
+
+	// Once known locally, the milestone and any older one are no longer applied
+	require.Len(t, relay.update(ann), finalityQuorum)
+	require.Nil(t, relay.update(ann))
+	require.False(t, relay.announce("peer3-0123456789", ann))
+
+	// Joining peers are told about the latest known finality
+	require.Equal(t, []finality.NewFinalityPacket{ann}, relay.register(finality.NewFakePeer(finality.FIN1, "peer4-0123456789", nil)))
+}
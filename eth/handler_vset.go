@@ -0,0 +1,42 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/eth/protocols/vset"
+)
+
+// vsetHandler implements the vset.Backend interface to serve the sprint boundary
+// validator sets to light peers.
+type vsetHandler handler
+
+func (h *vsetHandler) Chain() vset.Chain { return h.chain }
+
+// RunPeer is invoked when a peer joins on the `vset` protocol. Full nodes only
+// serve requests, so there's no peer maintenance to do.
+func (h *vsetHandler) RunPeer(peer *vset.Peer, hand vset.Handler) error {
+	return hand(peer)
+}
+
+// Handle is invoked from a peer's message handler when it receives a new remote
+// message that the handler couldn't consume and serve itself.
+func (h *vsetHandler) Handle(peer *vset.Peer, packet vset.Packet) error {
+	// Full nodes never request validator sets, so any response is unsolicited
+	return fmt.Errorf("unexpected vset packet: %s", packet.Name())
+}
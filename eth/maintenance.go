@@ -0,0 +1,64 @@
+package eth
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxMaintenanceDeferral is the maximum time a background task is deferred for
+// while waiting for a maintenance window.
+const maxMaintenanceDeferral = 2 * time.Minute
+
+// maintenanceScheduler defers expensive background tasks (compactions, prune
+// steps, ...) to the sprint positions where the local node is neither the
+// in-turn nor the first backup producer, minimizing self-inflicted missed slots.
+type maintenanceScheduler struct {
+	chain   *core.BlockChain
+	gate    core.MaintenanceGate // Nil if all positions are maintenance windows
+	closeCh chan struct{}
+}
+
+// wait blocks until a maintenance window opens or the maximum deferral passes,
+// returning false if the node shuts down in the meantime.
+func (s *maintenanceScheduler) wait(task string) bool {
+	if s.gate == nil || s.gate(s.chain.CurrentBlock()) {
+		return true
+	}
+
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := s.chain.SubscribeChainHeadEvent(heads)
+
+	defer sub.Unsubscribe()
+
+	timeout := time.NewTimer(maxMaintenanceDeferral)
+	defer timeout.Stop()
+
+	start := time.Now()
+
+	for {
+		select {
+		case ev := <-heads:
+			if s.gate(ev.Block.Header()) {
+				log.Debug("Running deferred maintenance task", "task", task, "deferred", time.Since(start))
+				return true
+			}
+		case <-timeout.C:
+			log.Debug("No maintenance window found, running task anyway", "task", task)
+			return true
+		case <-sub.Err():
+			return true
+		case <-s.closeCh:
+			return false
+		}
+	}
+}
+
+// run executes the task in the next maintenance window, unless the node shuts
+// down before.
+func (s *maintenanceScheduler) run(task string, fn func()) {
+	if s.wait(task) {
+		fn()
+	}
+}
@@ -0,0 +1,72 @@
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestMaintenanceScheduler(t *testing.T) {
+	t.Parallel()
+
+	gspec := &core.Genesis{Config: params.TestChainConfig}
+
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil, nil, nil)
+	require.NoError(t, err)
+
+	defer chain.Stop()
+
+	_, blocks, _ := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 4, nil)
+
+	// Only heads at even heights are maintenance windows
+	scheduler := &maintenanceScheduler{
+		chain:   chain,
+		gate:    func(head *types.Header) bool { return head.Number.Uint64()%2 == 0 },
+		closeCh: make(chan struct{}),
+	}
+
+	// The genesis is a window, so tasks run right away
+	ran := make(chan uint64, 1)
+	scheduler.run("test", func() { ran <- chain.CurrentBlock().Number.Uint64() })
+	require.Equal(t, uint64(0), <-ran)
+
+	// Otherwise they're deferred to the next window
+	_, err = chain.InsertChain(blocks[:1])
+	require.NoError(t, err)
+
+	go scheduler.run("test", func() { ran <- chain.CurrentBlock().Number.Uint64() })
+
+	select {
+	case <-ran:
+		t.Fatal("task ran outside of a maintenance window")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	_, err = chain.InsertChain(blocks[1:2])
+	require.NoError(t, err)
+
+	select {
+	case number := <-ran:
+		require.Equal(t, uint64(2), number)
+	case <-time.After(5 * time.Second):
+		t.Fatal("task not run in maintenance window")
+	}
+
+	// Deferred tasks are dropped on shutdown
+	_, err = chain.InsertChain(blocks[2:3])
+	require.NoError(t, err)
+
+	done := make(chan bool, 1)
+	go func() { done <- scheduler.wait("test") }()
+
+	close(scheduler.closeCh)
+	require.False(t, <-done)
+}
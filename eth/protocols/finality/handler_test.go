@@ -0,0 +1,86 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package finality
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+)
+
+// testBackend collects delivered packets.
+type testBackend struct {
+	delivered chan Packet
+}
+
+func (b *testBackend) RunPeer(peer *Peer, handler Handler) error { return handler(peer) }
+
+func (b *testBackend) Handle(peer *Peer, packet Packet) error {
+	b.delivered <- packet
+	return nil
+}
+
+func TestNewFinalityExchange(t *testing.T) {
+	t.Parallel()
+
+	var (
+		backend = &testBackend{delivered: make(chan Packet, 1)}
+
+		localRW, remoteRW = p2p.MsgPipe()
+		localPeer         = NewFakePeer(FIN1, "0123456789abcdef", localRW)
+		remotePeer        = NewFakePeer(FIN1, "fedcba9876543210", remoteRW)
+	)
+
+	defer localRW.Close()
+	defer remoteRW.Close()
+
+	go Handle(backend, remotePeer)
+
+	require.NoError(t, localPeer.SendNewFinality(Milestone, 128, common.Hash{0x1}))
+
+	packet := (<-backend.delivered).(*NewFinalityPacket)
+	require.Equal(t, &NewFinalityPacket{Type: Milestone, Number: 128, Hash: common.Hash{0x1}}, packet)
+}
+
+func TestHandleInvalidMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code uint64
+		data interface{}
+		want error
+	}{
+		{0x05, []byte{}, errInvalidMsgCode},
+		{NewFinalityMsg, &NewFinalityPacket{Type: 2}, errInvalidKind},
+		{NewFinalityMsg, []byte{0x1}, errDecode},
+	}
+
+	for _, test := range tests {
+		rw1, rw2 := p2p.MsgPipe()
+
+		go p2p.Send(rw1, test.code, test.data)
+
+		err := HandleMessage(&testBackend{}, NewFakePeer(FIN1, "0123456789abcdef", rw2))
+		require.ErrorIs(t, err, test.want)
+
+		rw1.Close()
+		rw2.Close()
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package finality implements a lightweight protocol gossiping the latest
+// checkpoint and milestone known to bor peers, so that nodes with a flaky
+// Heimdall connection still learn about finality promptly.
+package finality
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	FIN1 = 1
+)
+
+// ProtocolName is the official short name of the `finality` protocol used
+// during devp2p capability negotiation.
+const ProtocolName = "finality"
+
+// ProtocolVersions are the supported versions of the `finality` protocol
+// (first is primary).
+var ProtocolVersions = []uint{FIN1}
+
+// protocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var protocolLengths = map[uint]uint64{FIN1: 1}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 1024
+
+const (
+	NewFinalityMsg = 0x00
+)
+
+// Kind is the kind of finality announced.
+type Kind uint8
+
+const (
+	// Checkpoint announces the end block of the latest checkpoint.
+	Checkpoint Kind = iota
+
+	// Milestone announces the end block of the latest milestone.
+	Milestone
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Checkpoint:
+		return "checkpoint"
+	case Milestone:
+		return "milestone"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+	errInvalidKind    = errors.New("invalid finality kind")
+)
+
+// Packet represents a p2p message in the `finality` protocol.
+type Packet interface {
+	Name() string // Name returns a string corresponding to the message type.
+	Kind() byte   // Kind returns the message type.
+}
+
+// NewFinalityPacket announces the end block of the latest checkpoint or
+// milestone whitelisted by the sender.
+type NewFinalityPacket struct {
+	Type   Kind        // Whether a checkpoint or a milestone is announced
+	Number uint64      // Number of the end block
+	Hash   common.Hash // Hash of the end block
+}
+
+func (*NewFinalityPacket) Name() string { return "NewFinality" }
+func (*NewFinalityPacket) Kind() byte   { return NewFinalityMsg }
@@ -0,0 +1,259 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vset
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// maxValidatorSets is the maximum number of validator sets to serve in a single
+// response. This number is there to limit the number of disk lookups.
+const maxValidatorSets = 128
+
+// maxHeaders is the maximum number of headers to serve in a single response,
+// including the ones endorsing the validator sets.
+const maxHeaders = 4096
+
+// Chain defines the chain data retrieval methods needed to serve validator sets.
+type Chain interface {
+	// Config retrieves the chain's fork configuration.
+	Config() *params.ChainConfig
+
+	// CurrentHeader retrieves the current head header of the canonical chain.
+	CurrentHeader() *types.Header
+
+	// GetHeaderByNumber retrieves a canonical header from the database by number.
+	GetHeaderByNumber(number uint64) *types.Header
+}
+
+// Handler is a callback to invoke from an outside runner after the boilerplate
+// exchanges have passed.
+type Handler func(peer *Peer) error
+
+// Backend defines the data retrieval methods to serve remote requests and the
+// callback methods to invoke on remote deliveries.
+type Backend interface {
+	// Chain retrieves the chain to serve data from.
+	Chain() Chain
+
+	// RunPeer is invoked when a peer joins on the `vset` protocol. If all is
+	// passed, control should be given back to the `handler` to process the
+	// inbound messages going forward.
+	RunPeer(peer *Peer, handler Handler) error
+
+	// Handle is a callback to be invoked when a data packet is received from
+	// the remote peer. Only packets not consumed by the protocol handler will
+	// be forwarded to the backend.
+	Handle(peer *Peer, packet Packet) error
+}
+
+// MakeProtocols constructs the P2P protocol definitions for `vset`.
+func MakeProtocols(backend Backend) []p2p.Protocol {
+	protocols := make([]p2p.Protocol, len(ProtocolVersions))
+
+	for i, version := range ProtocolVersions {
+		version := version // Closure
+
+		protocols[i] = p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  protocolLengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				return backend.RunPeer(NewPeer(version, p, rw), func(peer *Peer) error {
+					return Handle(backend, peer)
+				})
+			},
+			NodeInfo: func() interface{} {
+				return nil
+			},
+			PeerInfo: func(id enode.ID) interface{} {
+				return nil
+			},
+		}
+	}
+
+	return protocols
+}
+
+// Handle is the callback invoked to manage the life cycle of a `vset` peer.
+// When this function terminates, the peer is disconnected.
+func Handle(backend Backend, peer *Peer) error {
+	for {
+		if err := HandleMessage(backend, peer); err != nil {
+			peer.Log().Debug("Message handling failed in `vset`", "err", err)
+			return err
+		}
+	}
+}
+
+// HandleMessage is invoked whenever an inbound message is received from a
+// remote peer on the `vset` protocol. The remote connection is torn down upon
+// returning any error.
+func HandleMessage(backend Backend, peer *Peer) error {
+	// Read the next message from the remote peer, and ensure it's fully consumed
+	msg, err := peer.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+
+	defer msg.Discard()
+
+	// Handle the message depending on its contents
+	switch msg.Code {
+	case GetValidatorSetsMsg:
+		// Decode the validator set retrieval request
+		var req GetValidatorSetsPacket
+		if err := msg.Decode(&req); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		// Service the request, potentially returning nothing in case of errors
+		headers := ServiceGetValidatorSetsQuery(backend.Chain(), &req)
+
+		// Send back anything accumulated (or empty in case of errors)
+		return p2p.Send(peer.rw, ValidatorSetsMsg, &ValidatorSetsPacket{
+			ID:      req.ID,
+			Headers: headers,
+		})
+
+	case ValidatorSetsMsg:
+		// A batch of validator sets arrived to one of our previous requests
+		res := new(ValidatorSetsPacket)
+		if err := msg.Decode(res); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		// Ensure the headers are consecutive
+		for i := 1; i < len(res.Headers); i++ {
+			if res.Headers[i-1].Number.Uint64()+1 != res.Headers[i].Number.Uint64() {
+				return fmt.Errorf("validator set headers not consecutive: #%d [%v] vs #%d [%v]", i-1, res.Headers[i-1].Number, i, res.Headers[i].Number)
+			}
+		}
+
+		return backend.Handle(peer, res)
+
+	default:
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+}
+
+// ServiceGetValidatorSetsQuery assembles the response to a validator set query:
+// the consecutive headers from the end of the first requested sprint to the end
+// of the last one, followed by the headers endorsing the validator set of the
+// last one, see VerifyValidatorSets. The run ends early at the head of the
+// chain or once the response is full.
+//
+// It is exposed to allow external packages to test protocol behavior.
+func ServiceGetValidatorSetsQuery(chain Chain, req *GetValidatorSetsPacket) []*types.Header {
+	config := chain.Config().Bor
+	if config == nil || req.Count == 0 || config.CalculateSprint(req.From) == 0 {
+		return nil
+	}
+
+	var (
+		head  = chain.CurrentHeader().Number.Uint64()
+		first = config.CalculateSprintEnd(req.From)
+		last  = first
+	)
+
+	if first > head {
+		return nil
+	}
+
+	for sets := uint64(1); sets < req.Count && sets < maxValidatorSets; sets++ {
+		end := config.CalculateSprintEnd(last + 1)
+		if end > head {
+			break
+		}
+
+		last = end
+	}
+
+	var (
+		sealing = sealingValidators(chain, last)
+		signers = make(map[common.Address]bool)
+		headers []*types.Header
+	)
+
+	for number := first; number <= head && len(headers) < maxHeaders; number++ {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			break
+		}
+
+		headers = append(headers, header)
+
+		if number < last {
+			continue
+		}
+
+		if signer, err := recoverSigner(config, header); err == nil {
+			signers[signer] = true
+		}
+
+		if endorsed(sealing, signers) {
+			break
+		}
+	}
+
+	return headers
+}
+
+// sealingValidators returns the validators of the sprint ending at the given
+// block, as carried by the end header of the previous sprint. The first sprint
+// has no such header, its validators are assumed to carry over.
+func sealingValidators(chain Chain, end uint64) []*valset.Validator {
+	config := chain.Config()
+
+	if sprint := config.Bor.CalculateSprint(end); end >= sprint {
+		end -= sprint
+	}
+
+	header := chain.GetHeaderByNumber(end)
+	if header == nil {
+		return nil
+	}
+
+	validators, _ := valset.ParseValidators(header.GetValidatorBytes(config))
+
+	return validators
+}
+
+// endorsed reports whether the signers hold more than two thirds of the voting
+// power of the validators.
+func endorsed(validators []*valset.Validator, signers map[common.Address]bool) bool {
+	var total, signed int64
+
+	for _, validator := range validators {
+		total += validator.VotingPower
+
+		if signers[validator.Address] {
+			signed += validator.VotingPower
+		}
+	}
+
+	return total > 0 && 3*signed > 2*total
+}
@@ -0,0 +1,336 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vset
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testChain is a chain of headers sealed in turn by the validators of their
+// sprint, where every sprint end header carries the validator set of the next
+// sprint.
+type testChain struct {
+	config  *params.ChainConfig
+	headers []*types.Header
+	genesis *valset.ValidatorSet // Validator set of the first sprint
+}
+
+func (c *testChain) Config() *params.ChainConfig  { return c.config }
+func (c *testChain) CurrentHeader() *types.Header { return c.headers[len(c.headers)-1] }
+
+func (c *testChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+
+	return c.headers[number]
+}
+
+// newTestKeys creates n validator keys.
+func newTestKeys(t *testing.T, n int) []*ecdsa.PrivateKey {
+	t.Helper()
+
+	keys := make([]*ecdsa.PrivateKey, n)
+
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		require.NoError(t, err)
+
+		keys[i] = key
+	}
+
+	return keys
+}
+
+// testValidators returns the validators of the given keys, of equal voting
+// power, sorted by address like in the extra-data of the sprint end headers.
+func testValidators(keys []*ecdsa.PrivateKey) []*valset.Validator {
+	validators := make([]*valset.Validator, len(keys))
+	for i, key := range keys {
+		validators[i] = &valset.Validator{Address: crypto.PubkeyToAddress(key.PublicKey), VotingPower: 10}
+	}
+
+	sort.Sort(valset.ValidatorsByAddress(validators))
+
+	return validators
+}
+
+// sealTestHeaders extends the parent with the given number of headers, sealed
+// in turn by the validators of the current set and carrying the next set at
+// the sprint ends, returning the headers and the set after the last one.
+func sealTestHeaders(t *testing.T, config *params.ChainConfig, parent *types.Header, current *valset.ValidatorSet, keys []*ecdsa.PrivateKey, next []*valset.Validator, n int) ([]*types.Header, *valset.ValidatorSet) {
+	t.Helper()
+
+	signers := make(map[common.Address]*ecdsa.PrivateKey, len(keys))
+	for _, key := range keys {
+		signers[crypto.PubkeyToAddress(key.PublicKey)] = key
+	}
+
+	headers := make([]*types.Header, 0, n)
+
+	for i := 0; i < n; i++ {
+		var (
+			proposer = current.GetProposer().Address
+			number   = parent.Number.Uint64() + 1
+		)
+
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: new(big.Int).SetUint64(bor.Difficulty(current, proposer)),
+			Extra:      make([]byte, types.ExtraVanityLength),
+		}
+
+		if config.Bor.IsSprintStart(number + 1) {
+			for _, validator := range next {
+				header.Extra = append(header.Extra, validator.HeaderBytes()...)
+			}
+		}
+
+		header.Extra = append(header.Extra, make([]byte, types.ExtraSealLength)...)
+
+		sig, err := crypto.Sign(bor.SealHash(header, config.Bor).Bytes(), signers[proposer])
+		require.NoError(t, err)
+
+		copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sig)
+
+		if config.Bor.IsSprintStart(number + 1) {
+			current = bor.NextValidatorSet(current, next)
+		}
+
+		headers = append(headers, header)
+		parent = header
+	}
+
+	return headers, current
+}
+
+// newTestChain creates a chain of the given length, sealed by 4 validators of
+// equal voting power.
+func newTestChain(t *testing.T, length int) (*testChain, []*ecdsa.PrivateKey) {
+	t.Helper()
+
+	var (
+		config     = params.BorUnittestChainConfig
+		keys       = newTestKeys(t, 4)
+		validators = testValidators(keys)
+		genesis    = &types.Header{Number: big.NewInt(0), Difficulty: big.NewInt(1), Extra: make([]byte, types.ExtraVanityLength+types.ExtraSealLength)}
+		chain      = &testChain{config: config, headers: []*types.Header{genesis}, genesis: valset.NewValidatorSet(validators)}
+	)
+
+	headers, _ := sealTestHeaders(t, config, genesis, chain.genesis, keys, validators, length-1)
+	chain.headers = append(chain.headers, headers...)
+
+	return chain, keys
+}
+
+// headerNumbers returns the numbers of the given headers.
+func headerNumbers(headers []*types.Header) []uint64 {
+	var numbers []uint64
+	for _, header := range headers {
+		numbers = append(numbers, header.Number.Uint64())
+	}
+
+	return numbers
+}
+
+func TestServiceGetValidatorSets(t *testing.T) {
+	t.Parallel()
+
+	chain, _ := newTestChain(t, 200)
+
+	// The validators of the sprint end header and the ones of the next two
+	// sprints hold three quarters of the voting power
+	tests := []struct {
+		from, count uint64
+		first, last uint64
+	}{
+		{0, 1, 31, 64},
+		{31, 2, 31, 96},
+		{32, 10, 63, 199}, // run to the head of the chain
+		{200, 1, 0, 0},    // sprint not finished yet
+		{0, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		headers := ServiceGetValidatorSetsQuery(chain, &GetValidatorSetsPacket{From: test.from, Count: test.count})
+
+		if test.last == 0 {
+			require.Empty(t, headers, "from %d count %d", test.from, test.count)
+			continue
+		}
+
+		numbers := headerNumbers(headers)
+		require.Equal(t, test.first, numbers[0], "from %d count %d", test.from, test.count)
+		require.Equal(t, test.last, numbers[len(numbers)-1], "from %d count %d", test.from, test.count)
+		require.Len(t, numbers, int(test.last-test.first+1), "from %d count %d", test.from, test.count)
+	}
+}
+
+func TestVerifyValidatorSets(t *testing.T) {
+	t.Parallel()
+
+	chain, _ := newTestChain(t, 200)
+
+	// Follow the rotation from the genesis validator set. The last sprint end
+	// header isn't endorsed by enough descendants yet.
+	headers := ServiceGetValidatorSetsQuery(chain, &GetValidatorSetsPacket{From: 0, Count: 10})
+
+	endorsed, next, err := VerifyValidatorSets(chain.config, chain.genesis, headers)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{31, 63, 95, 127, 159}, headerNumbers(endorsed))
+
+	// The following run is verified against the set returned
+	headers = ServiceGetValidatorSetsQuery(chain, &GetValidatorSetsPacket{From: 160, Count: 1})
+
+	_, _, err = VerifyValidatorSets(chain.config, next, headers)
+	require.ErrorIs(t, err, errUnendorsed)
+
+	// Skipping a rotation is rejected
+	_, _, err = VerifyValidatorSets(chain.config, chain.genesis, chain.headers[63:100])
+	require.ErrorIs(t, err, errInvalidDifficulty)
+
+	// So are headers within a sprint, and runs with gaps
+	_, _, err = VerifyValidatorSets(chain.config, chain.genesis, chain.headers[30:100])
+	require.ErrorIs(t, err, errNotSprintEnd)
+
+	gap := append(append([]*types.Header{}, chain.headers[31:40]...), chain.headers[41:100]...)
+	_, _, err = VerifyValidatorSets(chain.config, chain.genesis, gap)
+	require.ErrorIs(t, err, errBrokenChain)
+}
+
+// Tests that a sprint end header forged by a single trusted validator, handing
+// the chain over to a validator set of its own, is rejected.
+func TestVerifyValidatorSetsForged(t *testing.T) {
+	t.Parallel()
+
+	chain, keys := newTestChain(t, 100)
+
+	var (
+		attackers = newTestKeys(t, 1)
+		forged    = testValidators(attackers)
+		sprint    = chain.genesis
+	)
+
+	// The in-turn validator of the first sprint seals a sprint end header
+	// carrying the set of the attacker, who seals the descendants
+	headers, current := sealTestHeaders(t, chain.config, chain.headers[30], sprint, keys, forged, 1)
+
+	descendants, _ := sealTestHeaders(t, chain.config, headers[0], current, attackers, forged, 100)
+	headers = append(headers, descendants...)
+
+	_, _, err := VerifyValidatorSets(chain.config, sprint, headers)
+	require.ErrorIs(t, err, errUnendorsed)
+	require.ErrorContains(t, err, "10/40 voting power")
+
+	// The honest header is endorsed by the descendants sealed in turn
+	endorsed, _, err := VerifyValidatorSets(chain.config, sprint, chain.headers[31:])
+	require.NoError(t, err)
+	require.Equal(t, []uint64{31, 63}, headerNumbers(endorsed))
+
+	// A trusted validator sealing out of turn with the in-turn difficulty is
+	// rejected as well
+	outOfTurn := types.CopyHeader(chain.headers[31])
+
+	for _, key := range keys {
+		if crypto.PubkeyToAddress(key.PublicKey) == sprint.GetProposer().Address {
+			continue
+		}
+
+		sig, err := crypto.Sign(bor.SealHash(outOfTurn, chain.config.Bor).Bytes(), key)
+		require.NoError(t, err)
+
+		copy(outOfTurn.Extra[len(outOfTurn.Extra)-types.ExtraSealLength:], sig)
+
+		break
+	}
+
+	_, _, err = VerifyValidatorSets(chain.config, sprint, []*types.Header{outOfTurn})
+	require.ErrorIs(t, err, errInvalidDifficulty)
+}
+
+// testBackend serves the test chain and collects delivered packets.
+type testBackend struct {
+	chain     *testChain
+	delivered chan Packet
+}
+
+func (b *testBackend) Chain() Chain                              { return b.chain }
+func (b *testBackend) RunPeer(peer *Peer, handler Handler) error { return handler(peer) }
+
+func (b *testBackend) Handle(peer *Peer, packet Packet) error {
+	b.delivered <- packet
+	return nil
+}
+
+func TestValidatorSetsExchange(t *testing.T) {
+	t.Parallel()
+
+	chain, _ := newTestChain(t, 100)
+
+	var (
+		server = &testBackend{chain: chain}
+		client = &testBackend{delivered: make(chan Packet, 1)}
+
+		serverRW, clientRW = p2p.MsgPipe()
+		serverPeer         = NewFakePeer(VSET1, "0123456789abcdef", serverRW)
+		clientPeer         = NewFakePeer(VSET1, "fedcba9876543210", clientRW)
+	)
+
+	defer serverRW.Close()
+	defer clientRW.Close()
+
+	go Handle(server, serverPeer)
+	go Handle(client, clientPeer)
+
+	require.NoError(t, clientPeer.RequestValidatorSets(7, 0, 2))
+
+	packet := (<-client.delivered).(*ValidatorSetsPacket)
+	require.Equal(t, uint64(7), packet.ID)
+	require.Len(t, packet.Headers, 96-31+1)
+
+	for i, header := range packet.Headers {
+		require.Equal(t, chain.headers[31+i].Hash(), header.Hash())
+	}
+}
+
+func TestHandleInvalidMessage(t *testing.T) {
+	t.Parallel()
+
+	rw1, rw2 := p2p.MsgPipe()
+	defer rw1.Close()
+	defer rw2.Close()
+
+	go p2p.Send(rw1, 0x05, []byte{})
+
+	err := HandleMessage(&testBackend{}, NewFakePeer(VSET1, "0123456789abcdef", rw2))
+	require.True(t, errors.Is(err, errInvalidMsgCode))
+}
@@ -0,0 +1,87 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package vset implements a lightweight protocol serving the sprint boundary
+// validator sets of the bor chain to light peers.
+package vset
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	VSET1 = 1
+)
+
+// ProtocolName is the official short name of the `vset` protocol used during
+// devp2p capability negotiation.
+const ProtocolName = "vset"
+
+// ProtocolVersions are the supported versions of the `vset` protocol (first
+// is primary).
+var ProtocolVersions = []uint{VSET1}
+
+// protocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var protocolLengths = map[uint]uint64{VSET1: 2}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 10 * 1024 * 1024
+
+const (
+	GetValidatorSetsMsg = 0x00
+	ValidatorSetsMsg    = 0x01
+)
+
+var (
+	errMsgTooLarge    = errors.New("message too long")
+	errDecode         = errors.New("invalid message")
+	errInvalidMsgCode = errors.New("invalid message code")
+)
+
+// Packet represents a p2p message in the `vset` protocol.
+type Packet interface {
+	Name() string // Name returns a string corresponding to the message type.
+	Kind() byte   // Kind returns the message type.
+}
+
+// GetValidatorSetsPacket represents a validator set query.
+type GetValidatorSetsPacket struct {
+	ID    uint64 // Request ID to match up responses with
+	From  uint64 // Block number within the first sprint to retrieve the validator set of
+	Count uint64 // Maximum number of consecutive sprints to retrieve
+}
+
+// ValidatorSetsPacket represents a validator set query response.
+//
+// The headers are consecutive, starting with the last header of the first
+// requested sprint. Every sprint end header carries the validator set of the
+// next sprint in its extra-data, and is endorsed by the validators of its own
+// sprint sealing it and its descendants, so a light client holding a trusted
+// validator set can verify every rotation in turn, see VerifyValidatorSets.
+type ValidatorSetsPacket struct {
+	ID      uint64          // ID of the request this is a response for
+	Headers []*types.Header // Consecutive headers, starting with a sprint end
+}
+
+func (*GetValidatorSetsPacket) Name() string { return "GetValidatorSets" }
+func (*GetValidatorSetsPacket) Kind() byte   { return GetValidatorSetsMsg }
+
+func (*ValidatorSetsPacket) Name() string { return "ValidatorSets" }
+func (*ValidatorSetsPacket) Kind() byte   { return ValidatorSetsMsg }
@@ -0,0 +1,189 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vset
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	// errNotSprintEnd is returned if a header run served as a validator set proof
+	// doesn't start with the last header of a sprint.
+	errNotSprintEnd = errors.New("header is not a sprint end")
+
+	// errBrokenChain is returned if the headers served as a validator set proof
+	// aren't consecutive.
+	errBrokenChain = errors.New("headers are not consecutive")
+
+	// errUntrustedSigner is returned if a header served as a validator set proof
+	// is not sealed by a validator of the set of its sprint.
+	errUntrustedSigner = errors.New("header not sealed by a trusted validator")
+
+	// errInvalidDifficulty is returned if the difficulty of a header served as a
+	// validator set proof doesn't match the turn of its signer.
+	errInvalidDifficulty = errors.New("invalid difficulty")
+
+	// errMissingSignature is returned if a header's extra-data section doesn't
+	// seem to contain a 65 byte secp256k1 signature.
+	errMissingSignature = errors.New("extra-data 65 byte signature suffix missing")
+
+	// errNoValidators is returned if a sprint end header carries no validators.
+	errNoValidators = errors.New("header carries no validators")
+
+	// errUnendorsed is returned if no sprint end header of a validator set proof
+	// is endorsed by more than two thirds of the voting power it was sealed under.
+	errUnendorsed = errors.New("validator set not endorsed by a quorum")
+)
+
+// pendingSet is a sprint end header of a validator set proof waiting for the
+// validators it was sealed under to endorse it.
+type pendingSet struct {
+	header  *types.Header
+	sealing *valset.ValidatorSet // Validator set the header was sealed under
+	signers []common.Address     // Validators of the sealing set having sealed the header or a descendant
+	next    *valset.ValidatorSet // Validator set carried by the header
+}
+
+// VerifyValidatorSets verifies a run of consecutive headers starting with a
+// sprint end header sealed under the trusted validator set, i.e. the one of the
+// snapshot of its sprint. Every header must be sealed by a validator of the set
+// of its sprint with the difficulty of its turn, the sets rotating at the
+// sprint ends the way the snapshots do.
+//
+// A single validator could seal a sprint end header carrying any validator set,
+// so the set carried by a sprint end header is only adopted once validators
+// holding more than two thirds of the voting power the header was sealed under
+// have sealed it or one of its descendants. The endorsed sprint end headers are
+// returned in order, along with the validator set of the sprint following the
+// last one, to verify the next run against.
+//
+// Starting from a trusted validator set (e.g. the genesis one), a light client
+// can follow the signer rotation by verifying the runs in order. A rotation
+// replacing more than a third of the voting power at once is never endorsed,
+// the client needs another trusted set to follow the chain across it.
+func VerifyValidatorSets(config *params.ChainConfig, trusted *valset.ValidatorSet, headers []*types.Header) ([]*types.Header, *valset.ValidatorSet, error) {
+	if config.Bor == nil {
+		return nil, nil, errors.New("not a bor chain")
+	}
+
+	if len(headers) == 0 || headers[0].Number == nil || !config.Bor.IsSprintStart(headers[0].Number.Uint64()+1) {
+		return nil, nil, errNotSprintEnd
+	}
+
+	var (
+		current = trusted.Copy()
+		pending []*pendingSet
+	)
+
+	for i, header := range headers {
+		if i > 0 && (header.Number == nil || header.Number.Uint64() != headers[i-1].Number.Uint64()+1 || header.ParentHash != headers[i-1].Hash()) {
+			return nil, nil, fmt.Errorf("%w: #%d [%v] after #%d", errBrokenChain, i, header.Number, headers[i-1].Number)
+		}
+
+		number := header.Number.Uint64()
+
+		signer, err := recoverSigner(config.Bor, header)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !current.HasAddress(signer) {
+			return nil, nil, fmt.Errorf("%w: %v at #%d", errUntrustedSigner, signer, number)
+		}
+
+		if want := bor.Difficulty(current, signer); header.Difficulty == nil || header.Difficulty.Uint64() != want {
+			return nil, nil, fmt.Errorf("%w: %v at #%d, want %d", errInvalidDifficulty, header.Difficulty, number, want)
+		}
+
+		for _, set := range pending {
+			if set.sealing.HasAddress(signer) && !slices.Contains(set.signers, signer) {
+				set.signers = append(set.signers, signer)
+			}
+		}
+
+		if !config.Bor.IsSprintStart(number + 1) {
+			continue
+		}
+
+		parse := valset.ParseValidators
+		if config.Bor.IsStrictExtra(header.Number) {
+			parse = valset.ParseValidatorsStrict
+		}
+
+		validators, err := parse(header.GetValidatorBytes(config))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(validators) == 0 {
+			return nil, nil, fmt.Errorf("%w: #%d", errNoValidators, number)
+		}
+
+		next := bor.NextValidatorSet(current, validators)
+		pending = append(pending, &pendingSet{header: header, sealing: current, signers: []common.Address{signer}, next: next})
+		current = next
+	}
+
+	// The validator sets are adopted in order, up to the first one lacking a quorum
+	var endorsed []*types.Header
+
+	for _, set := range pending {
+		if !set.sealing.HasTwoThirdsMajority(set.signers) {
+			break
+		}
+
+		endorsed = append(endorsed, set.header)
+		trusted = set.next
+	}
+
+	if len(endorsed) == 0 {
+		first := pending[0]
+		return nil, nil, fmt.Errorf("%w: #%d sealed by validators of %d/%d voting power", errUnendorsed,
+			first.header.Number, first.sealing.VotingPowerOf(first.signers), first.sealing.TotalVotingPower())
+	}
+
+	return endorsed, trusted, nil
+}
+
+// recoverSigner returns the address of the validator having sealed the header.
+func recoverSigner(config *params.BorConfig, header *types.Header) (common.Address, error) {
+	if len(header.Extra) < types.ExtraVanityLength+types.ExtraSealLength {
+		return common.Address{}, errMissingSignature
+	}
+
+	signature := header.Extra[len(header.Extra)-types.ExtraSealLength:]
+
+	pubkey, err := crypto.Ecrecover(bor.SealHash(header, config).Bytes(), signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	var signer common.Address
+
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	return signer, nil
+}
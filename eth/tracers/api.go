@@ -110,11 +110,12 @@ type Backend interface {
 // API is the collection of tracing APIs exposed over the private debugging endpoint.
 type API struct {
 	backend Backend
+	regen   *stateRegenerator
 }
 
 // NewAPI creates a new API definition for the tracing methods of the Ethereum service.
 func NewAPI(backend Backend) *API {
-	return &API{backend: backend}
+	return &API{backend: backend, regen: newStateRegenerator(backend)}
 }
 
 // chainContext constructs the context reader which is used by the evm for reading
@@ -206,6 +207,10 @@ type TraceConfig struct {
 	TracerConfig    json.RawMessage
 	BorTraceEnabled *bool
 	BorTx           *bool
+	// Async makes the trace return a StateRegenError with the id of a queued
+	// regeneration job instead of blocking, if the historical state has to be
+	// regenerated. Only supported when tracing blocks and calls on top of blocks.
+	Async bool
 }
 
 // TraceCallConfig is the config for traceCall API. It holds one more
@@ -792,7 +797,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		ioflag = *config.IOFlag
 	}
 
-	statedb, release, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false)
+	statedb, release, err := api.stateAtBlock(ctx, parent, reexec, config.Async)
 	if err != nil {
 		return nil, err
 	}
@@ -804,13 +809,21 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		statedb.AddEmptyMVHashMap()
 	}
 
+	txs, stateSyncPresent, stateSyncHash := api.getAllBlockTransactions(ctx, block)
+
+	// The state-sync transaction only stands for the system calls of the block,
+	// which are traced instead if the engine executes them on demand
+	caller, systemCalls := api.backend.Engine().(systemCaller)
+	if systemCalls && stateSyncPresent {
+		txs, stateSyncPresent = txs[:len(txs)-1], false
+	}
+
 	// Execute all the transaction contained within the block concurrently
 	var (
-		txs, stateSyncPresent, stateSyncHash = api.getAllBlockTransactions(ctx, block)
-		blockHash                            = block.Hash()
-		signer                               = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
-		results                              = make([]*txTraceResult, len(txs))
-		pend                                 sync.WaitGroup
+		blockHash = block.Hash()
+		signer    = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
+		results   = make([]*txTraceResult, len(txs))
+		pend      sync.WaitGroup
 	)
 
 	threads := runtime.NumCPU()
@@ -938,28 +951,31 @@ txloop:
 				break
 			}
 
-			if london {
-				statedb.AddBalance(result.BurntContractAddress, uint256.NewInt(result.FeeBurnt.Uint64()), tracing.BalanceChangeTransfer)
-			}
+			// Fees paid through the fee currency are settled already
+			if !result.FeeCurrency {
+				if london {
+					statedb.AddBalance(result.BurntContractAddress, uint256.NewInt(result.FeeBurnt.Uint64()), tracing.BalanceChangeTransfer)
+				}
 
-			statedb.AddBalance(blockCtx.Coinbase, uint256.NewInt(result.FeeTipped.Uint64()), tracing.BalanceChangeTransfer)
-			output1 := new(big.Int).SetBytes(result.SenderInitBalance.Bytes())
-			output2 := new(big.Int).SetBytes(coinbaseBalance.Bytes())
+				statedb.AddBalance(blockCtx.Coinbase, uint256.NewInt(result.FeeTipped.Uint64()), tracing.BalanceChangeTransfer)
+				output1 := new(big.Int).SetBytes(result.SenderInitBalance.Bytes())
+				output2 := new(big.Int).SetBytes(coinbaseBalance.Bytes())
 
-			// Deprecating transfer log and will be removed in future fork. PLEASE DO NOT USE this transfer log going forward. Parameters won't get updated as expected going forward with EIP1559
-			// add transfer log
-			core.AddFeeTransferLog(
-				statedb,
+				// Deprecating transfer log and will be removed in future fork. PLEASE DO NOT USE this transfer log going forward. Parameters won't get updated as expected going forward with EIP1559
+				// add transfer log
+				core.AddFeeTransferLog(
+					statedb,
 
-				msg.From,
-				blockCtx.Coinbase,
+					msg.From,
+					blockCtx.Coinbase,
 
-				result.FeeTipped,
-				result.SenderInitBalance,
-				coinbaseBalance,
-				output1.Sub(output1, result.FeeTipped),
-				output2.Add(output2, result.FeeTipped),
-			)
+					result.FeeTipped,
+					result.SenderInitBalance,
+					coinbaseBalance,
+					output1.Sub(output1, result.FeeTipped),
+					output2.Add(output2, result.FeeTipped),
+				)
+			}
 
 			// Finalize the state so any modifications are written to the trie
 			// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
@@ -994,6 +1010,15 @@ txloop:
 		return nil, failed
 	}
 
+	if systemCalls && *config.BorTraceEnabled {
+		traces, err := api.traceSystemCalls(ctx, caller, block, statedb, len(txs), config)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, traces...)
+	}
+
 	if !*config.BorTraceEnabled && stateSyncPresent {
 		return results[:len(results)-1], nil
 	} else {
@@ -1288,7 +1313,7 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 	if config != nil && config.TxIndex != nil {
 		_, _, statedb, release, err = api.backend.StateAtTransaction(ctx, block, int(*config.TxIndex), reexec)
 	} else {
-		statedb, release, err = api.backend.StateAtBlock(ctx, block, reexec, nil, true, false)
+		statedb, release, err = api.stateAtBlock(ctx, block, reexec, config != nil && config.Async)
 	}
 	if err != nil {
 		return nil, err
@@ -1346,19 +1371,9 @@ func (api *API) traceTx(ctx context.Context, tx *types.Transaction, message *cor
 	if config == nil {
 		config = &TraceConfig{}
 	}
-	// Default tracer is the struct logger
-	if config.Tracer == nil {
-		logger := logger.NewStructLogger(config.Config)
-		tracer = &Tracer{
-			Hooks:     logger.Hooks(),
-			GetResult: logger.GetResult,
-			Stop:      logger.Stop,
-		}
-	} else {
-		tracer, err = DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
-		if err != nil {
-			return nil, err
-		}
+
+	if tracer, err = newTracer(txctx, config); err != nil {
+		return nil, err
 	}
 	// The actual TxContext will be created as part of ApplyTransactionWithEVM.
 	vmenv := vm.NewEVM(vmctx, vm.TxContext{GasPrice: message.GasPrice, BlobFeeCap: message.BlobGasFeeCap}, statedb, api.backend.ChainConfig(), vm.Config{Tracer: tracer.Hooks, NoBaseFee: true})
@@ -1410,6 +1425,22 @@ func (api *API) traceTx(ctx context.Context, tx *types.Transaction, message *cor
 	return tracer.GetResult()
 }
 
+// newTracer creates the tracer of the given configuration for a transaction,
+// the struct logger by default.
+func newTracer(txctx *Context, config *TraceConfig) (*Tracer, error) {
+	if config.Tracer == nil {
+		logger := logger.NewStructLogger(config.Config)
+
+		return &Tracer{
+			Hooks:     logger.Hooks(),
+			GetResult: logger.GetResult,
+			Stop:      logger.Stop,
+		}, nil
+	}
+
+	return DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
+}
+
 // APIs return the collection of RPC services the tracer package offers.
 func APIs(backend Backend) []rpc.API {
 	// Append all the local APIs and return
@@ -33,6 +33,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/bor/statefull"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -593,6 +594,99 @@ func TestTraceBlock(t *testing.T) {
 	}
 }
 
+// systemCallEngine is a consensus engine executing a system call to a contract
+// at the end of every block, the way bor commits state syncs.
+type systemCallEngine struct {
+	consensus.Engine
+	config   *params.ChainConfig
+	contract common.Address
+}
+
+func (e *systemCallEngine) message() statefull.Callmsg {
+	return statefull.GetSystemMessage(e.contract, []byte{0x1})
+}
+
+func (e *systemCallEngine) ApplySystemCalls(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) error {
+	_, err := statefull.ApplyMessage(context.Background(), e.message(), state, header, e.config, statefull.ChainContext{Chain: chain, Bor: e})
+	return err
+}
+
+// Tests that the system calls of a block are traced after its transactions as
+// synthetic transactions, once bor tracing is enabled.
+func TestTraceBlockSystemCalls(t *testing.T) {
+	t.Parallel()
+
+	var (
+		accounts = newAccounts(2)
+		contract = common.HexToAddress("0x0000000000000000000000000000000000001001")
+		genesis  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc: types.GenesisAlloc{
+				accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+				// PUSH1 1 PUSH1 0 MSTORE STOP
+				contract: {Balance: common.Big0, Code: []byte{0x60, 0x01, 0x60, 0x00, 0x52, 0x00}},
+			},
+		}
+		signer = types.HomesteadSigner{}
+	)
+
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(i),
+			To:       &accounts[1].addr,
+			Value:    big.NewInt(1000),
+			Gas:      params.TxGas,
+			GasPrice: b.BaseFee(),
+		}), signer, accounts[0].key)
+		b.AddTx(tx)
+	})
+	defer backend.chain.Stop()
+
+	config := *params.TestChainConfig
+	config.Bor = &params.BorConfig{ValidatorContract: "0x0000000000000000000000000000000000001000"}
+
+	engine := &systemCallEngine{Engine: backend.engine, config: &config, contract: contract}
+	backend.engine = engine
+
+	api := NewAPI(backend)
+
+	// The system calls are only traced with bor tracing enabled
+	results, err := api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(1), nil)
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("have %d traces without bor tracing, want 1", len(results))
+	}
+
+	results, err = api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(1), &TraceConfig{BorTraceEnabled: newBoolPtr(true)})
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("have %d traces with bor tracing, want 2", len(results))
+	}
+
+	if have, want := results[1].TxHash, statefull.SystemTransaction(engine.message()).Hash(); have != want {
+		t.Errorf("system call traced as %x, want %x", have, want)
+	}
+
+	if results[1].Error != "" {
+		t.Fatalf("system call trace failed: %v", results[1].Error)
+	}
+
+	var trace logger.ExecutionResult
+	if err := json.Unmarshal(results[1].Result.(json.RawMessage), &trace); err != nil {
+		t.Fatalf("failed to decode system call trace: %v", err)
+	}
+
+	if trace.Failed || len(trace.StructLogs) != 4 {
+		t.Errorf("system call trace failed %v with %d steps, want 4 steps", trace.Failed, len(trace.StructLogs))
+	}
+}
+
 // txTraceResult is the result of a single transaction trace.
 type txTraceResultTest struct {
 	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
@@ -0,0 +1,338 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// maxPendingRegenJobs is the maximum number of state regeneration jobs that
+	// may be waiting in the queue. Further requests are rejected until the
+	// worker catches up.
+	maxPendingRegenJobs = 16
+
+	// regenJobTTL is the time a regenerated state (or the failure of a job) is
+	// retained after the job finished, giving the client time to pick it up.
+	regenJobTTL = 10 * time.Minute
+)
+
+var (
+	errRegenQueueFull  = errors.New("too many pending state regeneration jobs")
+	errUnknownRegenJob = errors.New("unknown state regeneration job")
+)
+
+// Status values of a state regeneration job.
+const (
+	RegenQueued  = "queued"
+	RegenRunning = "running"
+	RegenDone    = "done"
+	RegenFailed  = "failed"
+)
+
+// StateRegenJob is the progress report of a state regeneration job.
+type StateRegenJob struct {
+	ID       string         `json:"id"`
+	Number   hexutil.Uint64 `json:"number"`
+	Hash     common.Hash    `json:"hash"`
+	Status   string         `json:"status"`
+	Position int            `json:"position"` // Number of jobs ahead in the queue
+	Elapsed  string         `json:"elapsed"`  // Time since the job was queued
+	Error    string         `json:"error,omitempty"`
+}
+
+// StateRegenError is returned by the async tracing methods if the requested
+// state has to be regenerated first. The job can be polled for its progress
+// with debug_stateRegenerationStatus, after which the trace can be retried.
+type StateRegenError struct {
+	Job *StateRegenJob
+}
+
+func (e *StateRegenError) Error() string {
+	return fmt.Sprintf("historical state is being regenerated (job %s, status %s)", e.Job.ID, e.Job.Status)
+}
+
+// ErrorCode returns the JSON-RPC error code of the error.
+func (e *StateRegenError) ErrorCode() int { return -32002 }
+
+// ErrorData returns the regeneration job, so clients can pick up its id.
+func (e *StateRegenError) ErrorData() interface{} { return e.Job }
+
+// regenJob is a queued state regeneration of a single block.
+type regenJob struct {
+	id     string
+	block  *types.Block
+	reexec uint64
+
+	status   string
+	err      error
+	queued   time.Time
+	finished time.Time
+
+	statedb *state.StateDB
+	release StateReleaseFunc
+}
+
+// stateRegenerator regenerates historical states for tracing in the background.
+// Jobs are processed one at a time, which bounds the resources a node spends on
+// regeneration regardless of the number of trace requests hitting it.
+type stateRegenerator struct {
+	backend Backend
+
+	jobs    map[string]*regenJob      // Jobs by id
+	byBlock map[common.Hash]*regenJob // Jobs by the hash of the regenerated block
+	queue   []*regenJob               // Jobs waiting for the worker
+	running bool                      // Whether the worker goroutine is alive
+
+	lock sync.Mutex
+}
+
+func newStateRegenerator(backend Backend) *stateRegenerator {
+	return &stateRegenerator{
+		backend: backend,
+		jobs:    make(map[string]*regenJob),
+		byBlock: make(map[common.Hash]*regenJob),
+	}
+}
+
+// request queues the regeneration of the state at the given block, or returns
+// the existing job if one is already tracked for it.
+func (r *stateRegenerator) request(block *types.Block, reexec uint64) (*StateRegenJob, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.expire()
+
+	if job, ok := r.byBlock[block.Hash()]; ok && job.status != RegenFailed {
+		return r.report(job), nil
+	}
+
+	if len(r.queue) >= maxPendingRegenJobs {
+		return nil, errRegenQueueFull
+	}
+
+	job := &regenJob{
+		id:     string(rpc.NewID()),
+		block:  block,
+		reexec: reexec,
+		status: RegenQueued,
+		queued: time.Now(),
+	}
+	if old, ok := r.byBlock[block.Hash()]; ok {
+		delete(r.jobs, old.id)
+	}
+
+	r.jobs[job.id] = job
+	r.byBlock[block.Hash()] = job
+	r.queue = append(r.queue, job)
+
+	if !r.running {
+		r.running = true
+		go r.loop()
+	}
+
+	log.Debug("Queued state regeneration", "id", job.id, "number", block.NumberU64(), "hash", block.Hash(), "reexec", reexec)
+
+	return r.report(job), nil
+}
+
+// status returns the progress report of the job with the given id.
+func (r *stateRegenerator) status(id string) (*StateRegenJob, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.expire()
+
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, errUnknownRegenJob
+	}
+
+	return r.report(job), nil
+}
+
+// state returns a copy of the regenerated state at the given block, if a job
+// for it finished successfully.
+func (r *stateRegenerator) state(hash common.Hash) (*state.StateDB, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.expire()
+
+	job, ok := r.byBlock[hash]
+	if !ok || job.status != RegenDone {
+		return nil, false
+	}
+
+	return job.statedb.Copy(), true
+}
+
+// loop processes the queued jobs one by one, terminating once the queue drains.
+func (r *stateRegenerator) loop() {
+	for {
+		r.lock.Lock()
+		if len(r.queue) == 0 {
+			r.running = false
+			r.lock.Unlock()
+
+			return
+		}
+
+		job := r.queue[0]
+		r.queue = r.queue[1:]
+		job.status = RegenRunning
+		r.lock.Unlock()
+
+		start := time.Now()
+		statedb, release, err := r.backend.StateAtBlock(context.Background(), job.block, job.reexec, nil, true, false)
+
+		r.lock.Lock()
+		job.finished = time.Now()
+
+		if err != nil {
+			job.status, job.err = RegenFailed, err
+			log.Debug("State regeneration failed", "id", job.id, "number", job.block.NumberU64(), "err", err)
+		} else {
+			job.status, job.statedb, job.release = RegenDone, statedb, release
+			log.Debug("Regenerated historical state", "id", job.id, "number", job.block.NumberU64(), "elapsed", common.PrettyDuration(time.Since(start)))
+		}
+		r.lock.Unlock()
+	}
+}
+
+// expire drops the jobs which finished longer than regenJobTTL ago, releasing
+// the states they hold. The lock is assumed to be held.
+func (r *stateRegenerator) expire() {
+	for id, job := range r.jobs {
+		if job.finished.IsZero() || time.Since(job.finished) < regenJobTTL {
+			continue
+		}
+
+		if job.release != nil {
+			job.release()
+		}
+
+		delete(r.jobs, id)
+
+		if r.byBlock[job.block.Hash()] == job {
+			delete(r.byBlock, job.block.Hash())
+		}
+	}
+}
+
+// report assembles the progress report of a job. The lock is assumed to be held.
+func (r *stateRegenerator) report(job *regenJob) *StateRegenJob {
+	report := &StateRegenJob{
+		ID:     job.id,
+		Number: hexutil.Uint64(job.block.NumberU64()),
+		Hash:   job.block.Hash(),
+		Status: job.status,
+	}
+
+	if job.finished.IsZero() {
+		report.Elapsed = common.PrettyDuration(time.Since(job.queued)).String()
+	} else {
+		report.Elapsed = common.PrettyDuration(job.finished.Sub(job.queued)).String()
+	}
+
+	if job.err != nil {
+		report.Error = job.err.Error()
+	}
+
+	for i, queued := range r.queue {
+		if queued == job {
+			report.Position = i
+			break
+		}
+	}
+
+	return report
+}
+
+// stateAtBlock retrieves the state at the given block for tracing. In async mode
+// the call never blocks on regenerating the state: if it is neither available
+// in the database nor regenerated by a finished job, a regeneration job is
+// queued and a StateRegenError carrying its id is returned instead.
+func (api *API) stateAtBlock(ctx context.Context, block *types.Block, reexec uint64, async bool) (*state.StateDB, StateReleaseFunc, error) {
+	if !async {
+		return api.backend.StateAtBlock(ctx, block, reexec, nil, true, false)
+	}
+
+	if statedb, ok := api.regen.state(block.Hash()); ok {
+		return statedb, func() {}, nil
+	}
+
+	if statedb, release, err := api.backend.StateAtBlock(ctx, block, 0, nil, true, false); err == nil {
+		return statedb, release, nil
+	}
+
+	job, err := api.regen.request(block, reexec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, nil, &StateRegenError{Job: job}
+}
+
+// RequestStateRegeneration queues the regeneration of the state at the given
+// block in the background, returning the job that can be polled for progress.
+// Once the job is done, async traces relying on the state are served from it.
+func (api *API) RequestStateRegeneration(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash, reexec *uint64) (*StateRegenJob, error) {
+	var (
+		block *types.Block
+		err   error
+	)
+
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		block, err = api.blockByHash(ctx, hash)
+	} else if number, ok := blockNrOrHash.Number(); ok {
+		if number == rpc.PendingBlockNumber {
+			return nil, errors.New("regenerating the pending state is not supported")
+		}
+
+		block, err = api.blockByNumber(ctx, number)
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	limit := defaultTraceReexec
+	if reexec != nil {
+		limit = *reexec
+	}
+
+	return api.regen.request(block, limit)
+}
+
+// StateRegenerationStatus returns the progress of a state regeneration job.
+func (api *API) StateRegenerationStatus(id string) (*StateRegenJob, error) {
+	return api.regen.status(id)
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracers
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// regenBackend pretends that no historical state is available without
+// re-execution, and holds up every regeneration until it is allowed to proceed.
+type regenBackend struct {
+	*testBackend
+	proceed chan struct{}
+}
+
+func (b *regenBackend) StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, StateReleaseFunc, error) {
+	if reexec == 0 {
+		return nil, nil, errStateNotFound
+	}
+	<-b.proceed
+
+	return b.testBackend.StateAtBlock(ctx, block, reexec, base, readOnly, preferDisk)
+}
+
+func TestAsyncTraceBlock(t *testing.T) {
+	t.Parallel()
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			accounts[0].addr: {Balance: big.NewInt(params.Ether)},
+		},
+	}
+	backend := &regenBackend{
+		testBackend: newTestBackend(t, 5, genesis, func(i int, b *core.BlockGen) {}),
+		proceed:     make(chan struct{}),
+	}
+	defer backend.chain.Stop()
+
+	api := NewAPI(backend)
+
+	// The first async trace must not block but hand out a regeneration job
+	_, err := api.TraceBlockByNumber(context.Background(), 3, &TraceConfig{Async: true})
+
+	var regenErr *StateRegenError
+	if !errors.As(err, &regenErr) {
+		t.Fatalf("expected state regeneration error, got %v", err)
+	}
+	if regenErr.Job.Number != 2 {
+		t.Fatalf("regenerating wrong block: have %d, want 2", regenErr.Job.Number)
+	}
+	// Retrying before the job finished must not queue a duplicate
+	_, err = api.TraceBlockByNumber(context.Background(), 3, &TraceConfig{Async: true})
+	if !errors.As(err, &regenErr) {
+		t.Fatalf("expected state regeneration error, got %v", err)
+	}
+	id := regenErr.Job.ID
+
+	job, err := api.RequestStateRegeneration(context.Background(), rpc.BlockNumberOrHashWithNumber(2), nil)
+	if err != nil {
+		t.Fatalf("failed to request regeneration: %v", err)
+	}
+	if job.ID != id {
+		t.Fatalf("duplicate job queued: have %s, want %s", job.ID, id)
+	}
+	// Let the regeneration run and wait for it to finish
+	close(backend.proceed)
+
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		job, err = api.StateRegenerationStatus(id)
+		if err != nil {
+			t.Fatalf("failed to retrieve job status: %v", err)
+		}
+		if job.Status == RegenDone {
+			break
+		}
+		if job.Status == RegenFailed || time.Since(start) > 5*time.Second {
+			t.Fatalf("regeneration did not finish: %+v", job)
+		}
+	}
+	// The trace is now served from the regenerated state
+	if _, err := api.TraceBlockByNumber(context.Background(), 3, &TraceConfig{Async: true}); err != nil {
+		t.Fatalf("failed to trace block from regenerated state: %v", err)
+	}
+
+	if _, err := api.StateRegenerationStatus("0x00"); !errors.Is(err, errUnknownRegenJob) {
+		t.Fatalf("unexpected error for unknown job: %v", err)
+	}
+}
+
+func TestStateRegenQueueLimit(t *testing.T) {
+	t.Parallel()
+
+	genesis := &core.Genesis{Config: params.TestChainConfig}
+	backend := &regenBackend{
+		testBackend: newTestBackend(t, maxPendingRegenJobs+2, genesis, func(i int, b *core.BlockGen) {}),
+		proceed:     make(chan struct{}),
+	}
+	defer backend.chain.Stop()
+	defer close(backend.proceed)
+
+	regen := newStateRegenerator(backend)
+
+	// The first job is picked up by the worker, the rest fill up the queue
+	for i := 0; i <= maxPendingRegenJobs; i++ {
+		block := backend.chain.GetBlockByNumber(uint64(i))
+		job, err := regen.request(block, 1)
+		if err != nil {
+			t.Fatalf("job %d: failed to queue: %v", i, err)
+		}
+		if i == 0 {
+			for start := time.Now(); ; time.Sleep(time.Millisecond) {
+				if job, _ = regen.status(job.ID); job.Status == RegenRunning {
+					break
+				}
+				if time.Since(start) > 5*time.Second {
+					t.Fatal("worker did not pick up the first job")
+				}
+			}
+		}
+	}
+
+	block := backend.chain.GetBlockByNumber(maxPendingRegenJobs + 1)
+	if _, err := regen.request(block, 1); !errors.Is(err, errRegenQueueFull) {
+		t.Fatalf("expected queue full error, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package tracers
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// systemCaller is implemented by the consensus engines executing system calls
+// at the end of blocks, outside of their transactions, like the state syncs and
+// span commits of bor.
+type systemCaller interface {
+	ApplySystemCalls(chain consensus.ChainHeaderReader, header *types.Header, state *state.StateDB) error
+}
+
+// traceSystemCalls executes the system calls of the block on top of the state
+// after its transactions, tracing each as a transaction indexed after the ones
+// of the block.
+func (api *API) traceSystemCalls(ctx context.Context, caller systemCaller, block *types.Block, statedb *state.StateDB, index int, config *TraceConfig) ([]*txTraceResult, error) {
+	tracer := &systemCallTracer{block: block, config: config, index: index}
+
+	statedb.SetLogger(tracer.hooks())
+	defer statedb.SetLogger(nil)
+
+	if err := caller.ApplySystemCalls(&chainHeaderReader{ctx: ctx, backend: api.backend}, block.Header(), statedb); err != nil {
+		return nil, err
+	}
+
+	return tracer.results, nil
+}
+
+// systemCallTracer traces every system call of a block with a tracer of its
+// own, created as the synthetic transaction of the call starts.
+type systemCallTracer struct {
+	block  *types.Block
+	config *TraceConfig
+	index  int // Index of the next system call
+
+	current *Tracer     // Tracer of the system call in progress, nil if it failed to be created
+	txHash  common.Hash // Hash of the synthetic transaction of the system call in progress
+	err     error       // Error creating the tracer of the system call in progress
+
+	results []*txTraceResult
+}
+
+func (t *systemCallTracer) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnTxStart:       t.OnTxStart,
+		OnTxEnd:         t.OnTxEnd,
+		OnEnter:         t.OnEnter,
+		OnExit:          t.OnExit,
+		OnOpcode:        t.OnOpcode,
+		OnFault:         t.OnFault,
+		OnGasChange:     t.OnGasChange,
+		OnBalanceChange: t.OnBalanceChange,
+		OnNonceChange:   t.OnNonceChange,
+		OnCodeChange:    t.OnCodeChange,
+		OnStorageChange: t.OnStorageChange,
+		OnLog:           t.OnLog,
+	}
+}
+
+func (t *systemCallTracer) OnTxStart(env *tracing.VMContext, tx *types.Transaction, from common.Address) {
+	txctx := &Context{
+		BlockHash:   t.block.Hash(),
+		BlockNumber: t.block.Number(),
+		TxIndex:     t.index,
+		TxHash:      tx.Hash(),
+	}
+	t.index++
+	t.txHash = tx.Hash()
+
+	if t.current, t.err = newTracer(txctx, t.config); t.err == nil && t.current.OnTxStart != nil {
+		t.current.OnTxStart(env, tx, from)
+	}
+}
+
+func (t *systemCallTracer) OnTxEnd(receipt *types.Receipt, err error) {
+	result := &txTraceResult{TxHash: t.txHash}
+
+	if t.current == nil {
+		result.Error = t.err.Error()
+	} else {
+		if t.current.OnTxEnd != nil {
+			t.current.OnTxEnd(receipt, err)
+		}
+
+		if res, err := t.current.GetResult(); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Result = res
+		}
+	}
+
+	t.results = append(t.results, result)
+	t.current = nil
+}
+
+func (t *systemCallTracer) OnEnter(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+	if t.current != nil && t.current.OnEnter != nil {
+		t.current.OnEnter(depth, typ, from, to, input, gas, value)
+	}
+}
+
+func (t *systemCallTracer) OnExit(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+	if t.current != nil && t.current.OnExit != nil {
+		t.current.OnExit(depth, output, gasUsed, err, reverted)
+	}
+}
+
+func (t *systemCallTracer) OnOpcode(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, rData []byte, depth int, err error) {
+	if t.current != nil && t.current.OnOpcode != nil {
+		t.current.OnOpcode(pc, op, gas, cost, scope, rData, depth, err)
+	}
+}
+
+func (t *systemCallTracer) OnFault(pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, depth int, err error) {
+	if t.current != nil && t.current.OnFault != nil {
+		t.current.OnFault(pc, op, gas, cost, scope, depth, err)
+	}
+}
+
+func (t *systemCallTracer) OnGasChange(old, new uint64, reason tracing.GasChangeReason) {
+	if t.current != nil && t.current.OnGasChange != nil {
+		t.current.OnGasChange(old, new, reason)
+	}
+}
+
+func (t *systemCallTracer) OnBalanceChange(a common.Address, prev, new *big.Int, reason tracing.BalanceChangeReason) {
+	if t.current != nil && t.current.OnBalanceChange != nil {
+		t.current.OnBalanceChange(a, prev, new, reason)
+	}
+}
+
+func (t *systemCallTracer) OnNonceChange(a common.Address, prev, new uint64) {
+	if t.current != nil && t.current.OnNonceChange != nil {
+		t.current.OnNonceChange(a, prev, new)
+	}
+}
+
+func (t *systemCallTracer) OnCodeChange(a common.Address, prevCodeHash common.Hash, prev []byte, codeHash common.Hash, code []byte) {
+	if t.current != nil && t.current.OnCodeChange != nil {
+		t.current.OnCodeChange(a, prevCodeHash, prev, codeHash, code)
+	}
+}
+
+func (t *systemCallTracer) OnStorageChange(a common.Address, k, prev, new common.Hash) {
+	if t.current != nil && t.current.OnStorageChange != nil {
+		t.current.OnStorageChange(a, k, prev, new)
+	}
+}
+
+func (t *systemCallTracer) OnLog(log *types.Log) {
+	if t.current != nil && t.current.OnLog != nil {
+		t.current.OnLog(log)
+	}
+}
+
+// chainHeaderReader serves the headers of the canonical chain through the
+// backend to the consensus engine executing the system calls of a block.
+type chainHeaderReader struct {
+	ctx     context.Context
+	backend Backend
+}
+
+func (r *chainHeaderReader) Config() *params.ChainConfig {
+	return r.backend.ChainConfig()
+}
+
+func (r *chainHeaderReader) CurrentHeader() *types.Header {
+	header, _ := r.backend.HeaderByNumber(r.ctx, rpc.LatestBlockNumber)
+	return header
+}
+
+func (r *chainHeaderReader) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header, _ := r.backend.HeaderByHash(r.ctx, hash)
+	if header == nil || header.Number.Uint64() != number {
+		return nil
+	}
+
+	return header
+}
+
+func (r *chainHeaderReader) GetHeaderByNumber(number uint64) *types.Header {
+	header, _ := r.backend.HeaderByNumber(r.ctx, rpc.BlockNumber(number))
+	return header
+}
+
+func (r *chainHeaderReader) GetHeaderByHash(hash common.Hash) *types.Header {
+	header, _ := r.backend.HeaderByHash(r.ctx, hash)
+	return header
+}
+
+// GetTd is not needed to execute system calls.
+func (r *chainHeaderReader) GetTd(hash common.Hash, number uint64) *big.Int {
+	return nil
+}
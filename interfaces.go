@@ -289,6 +289,7 @@ type ChainValidator interface {
 	UnlockSprint(endBlockNum uint64)
 	RemoveMilestoneID(milestoneId string)
 	GetMilestoneIDsList() []string
+	GetLockedMilestone() (bool, uint64, common.Hash)
 }
 
 // BlockNumberReader provides access to the current block number.
@@ -185,11 +185,46 @@ func Commands() map[string]MarkDownCommandFactory {
 				UI: ui,
 			}, nil
 		},
+		"dump-consensus": func() (MarkDownCommand, error) {
+			return &DumpConsensusCommand{
+				Meta: meta,
+			}, nil
+		},
+		"export": func() (MarkDownCommand, error) {
+			return &ExportCommand{
+				Meta: meta,
+			}, nil
+		},
+		"load-consensus": func() (MarkDownCommand, error) {
+			return &LoadConsensusCommand{
+				Meta: meta,
+			}, nil
+		},
+		"heimdall-proxy": func() (MarkDownCommand, error) {
+			return &HeimdallProxyCommand{
+				UI: ui,
+			}, nil
+		},
+		"loadtest": func() (MarkDownCommand, error) {
+			return &LoadtestCommand{
+				UI: ui,
+			}, nil
+		},
 		"removedb": func() (MarkDownCommand, error) {
 			return &RemoveDBCommand{
 				Meta2: meta2,
 			}, nil
 		},
+		"simulate": func() (MarkDownCommand, error) {
+			return &SimulateCommand{
+				UI: ui,
+			}, nil
+		},
+		"simulate rewards": func() (MarkDownCommand, error) {
+			return &SimulateRewardsCommand{
+				UI: ui,
+			}, nil
+		},
 		"snapshot": func() (MarkDownCommand, error) {
 			return &SnapshotCommand{
 				UI: ui,
@@ -210,6 +245,11 @@ func Commands() map[string]MarkDownCommandFactory {
 				Meta: meta,
 			}, nil
 		},
+		"snapshot migrate-ancient-codec": func() (MarkDownCommand, error) {
+			return &MigrateAncientCodecCommand{
+				Meta: meta,
+			}, nil
+		},
 	}
 }
 
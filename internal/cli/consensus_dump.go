@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+	"github.com/ethereum/go-ethereum/internal/cli/server"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+// DumpConsensusCommand is the command to write the bor consensus state of the
+// chain to a file
+type DumpConsensusCommand struct {
+	*Meta
+
+	datadirAncient string
+	from           uint64
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *DumpConsensusCommand) MarkDown() string {
+	items := []string{
+		"# Dump consensus",
+		"The ```bor dump-consensus <file>``` command writes the bor consensus state of the canonical chain at the given datadir location to a file, for bootstrapping another node holding the same chain with ```bor load-consensus```.",
+		`
+The state is written as one JSON object per line: the latest snapshot stored up to the head block, in full even if stored as
+a diff, the producer spans stored and the state-sync events committed along with the id of the last one. The node loading
+it takes over from the snapshot without replaying the sprints before it. The file is gzipped if its name ends with .gz.
+The node must be stopped while the state is dumped.
+`,
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *DumpConsensusCommand) Help() string {
+	return `Usage: bor dump-consensus <file>
+
+  This command will write the bor consensus state of the chain to a file` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *DumpConsensusCommand) Synopsis() string {
+	return "Dump the bor consensus state of the chain"
+}
+
+// Flags: datadir, datadir.ancient, from
+func (c *DumpConsensusCommand) Flags() *flagset.Flagset {
+	flags := c.NewFlagSet("dump-consensus")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "datadir.ancient",
+		Value:   &c.datadirAncient,
+		Usage:   "Path of the ancient data directory",
+		Default: "",
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "from",
+		Value:   &c.from,
+		Usage:   "First block of which the committed state-sync events are dumped",
+		Default: 0,
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *DumpConsensusCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Expected one argument: the file to dump to")
+		return 1
+	}
+
+	if c.dataDir == "" {
+		c.UI.Error("datadir is required")
+		return 1
+	}
+
+	summary, err := c.dump(args[0])
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Dumped the snapshot of block %d, %d spans and %d state-sync events of %d blocks to %s", summary.Snapshot, summary.Spans, summary.StateSyncs, summary.StateSyncBlocks, args[0]))
+
+	return 0
+}
+
+// dump writes the consensus state of the chain to the given file.
+func (c *DumpConsensusCommand) dump(fn string) (*bor.ConsensusDumpSummary, error) {
+	stack, err := node.New(&node.Config{DataDir: c.dataDir})
+	if err != nil {
+		return nil, err
+	}
+	defer stack.Close()
+
+	dbHandles, err := server.MakeDatabaseHandles(0)
+	if err != nil {
+		return nil, err
+	}
+
+	chaindb, err := stack.OpenDatabaseWithFreezer(chaindataPath, 1024, dbHandles, c.datadirAncient, "", true, false, false)
+	if err != nil {
+		return nil, err
+	}
+	defer chaindb.Close()
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	buffered := bufio.NewWriter(fh)
+
+	var writer io.Writer = buffered
+
+	var gz *gzip.Writer
+
+	if strings.HasSuffix(fn, ".gz") {
+		gz = gzip.NewWriter(buffered)
+		writer = gz
+	}
+
+	summary, err := bor.DumpConsensusState(chaindb, writer, c.from)
+	if err != nil {
+		return nil, err
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	return summary, buffered.Flush()
+}
+
+// LoadConsensusCommand is the command to load the bor consensus state dumped
+// by the dump-consensus command
+type LoadConsensusCommand struct {
+	*Meta
+
+	datadirAncient string
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *LoadConsensusCommand) MarkDown() string {
+	items := []string{
+		"# Load consensus",
+		"The ```bor load-consensus <file>``` command stores the bor consensus state written by ```bor dump-consensus``` into the database at the given datadir location.",
+		`
+The database must hold the same chain as the one dumped, or at least its genesis block if the chain is imported afterwards.
+The file is read as gzipped if its name ends with .gz. The node must be stopped while the state is loaded.
+`,
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *LoadConsensusCommand) Help() string {
+	return `Usage: bor load-consensus <file>
+
+  This command will load the bor consensus state of the chain from a file` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *LoadConsensusCommand) Synopsis() string {
+	return "Load the bor consensus state of the chain"
+}
+
+// Flags: datadir, datadir.ancient
+func (c *LoadConsensusCommand) Flags() *flagset.Flagset {
+	flags := c.NewFlagSet("load-consensus")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "datadir.ancient",
+		Value:   &c.datadirAncient,
+		Usage:   "Path of the ancient data directory",
+		Default: "",
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *LoadConsensusCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Expected one argument: the file to load from")
+		return 1
+	}
+
+	if c.dataDir == "" {
+		c.UI.Error("datadir is required")
+		return 1
+	}
+
+	summary, err := c.load(args[0])
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Loaded the snapshot of block %d, %d spans and %d state-sync events of %d blocks from %s", summary.Snapshot, summary.Spans, summary.StateSyncs, summary.StateSyncBlocks, args[0]))
+
+	return 0
+}
+
+// load stores the consensus state read from the given file.
+func (c *LoadConsensusCommand) load(fn string) (*bor.ConsensusDumpSummary, error) {
+	stack, err := node.New(&node.Config{DataDir: c.dataDir})
+	if err != nil {
+		return nil, err
+	}
+	defer stack.Close()
+
+	dbHandles, err := server.MakeDatabaseHandles(0)
+	if err != nil {
+		return nil, err
+	}
+
+	chaindb, err := stack.OpenDatabaseWithFreezer(chaindataPath, 1024, dbHandles, c.datadirAncient, "", false, false, false)
+	if err != nil {
+		return nil, err
+	}
+	defer chaindb.Close()
+
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = bufio.NewReader(fh)
+
+	if strings.HasSuffix(fn, ".gz") {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+
+		reader = gz
+	}
+
+	return bor.LoadConsensusState(chaindb, reader)
+}
@@ -62,10 +62,15 @@ func (c *DumpconfigCommand) Run(args []string) int {
 	userConfig.TxPool.LifeTimeRaw = userConfig.TxPool.LifeTime.String()
 	userConfig.Sealer.GasPriceRaw = userConfig.Sealer.GasPrice.String()
 	userConfig.Sealer.RecommitRaw = userConfig.Sealer.Recommit.String()
+	userConfig.Sealer.PolicyTimeoutRaw = userConfig.Sealer.PolicyTimeout.String()
 	userConfig.Gpo.MaxPriceRaw = userConfig.Gpo.MaxPrice.String()
 	userConfig.Gpo.IgnorePriceRaw = userConfig.Gpo.IgnorePrice.String()
 	userConfig.Cache.TrieTimeoutRaw = userConfig.Cache.TrieTimeout.String()
 	userConfig.P2P.TxArrivalWaitRaw = userConfig.P2P.TxArrivalWait.String()
+	userConfig.Alerts.HeadStallRaw = userConfig.Alerts.HeadStall.String()
+	userConfig.Alerts.HeimdallDownRaw = userConfig.Alerts.HeimdallDown.String()
+	userConfig.Clock.MaxOffsetRaw = userConfig.Clock.MaxOffset.String()
+	userConfig.Health.StallTimeoutRaw = userConfig.Health.StallTimeout.String()
 
 	if err := toml.NewEncoder(os.Stdout).Encode(userConfig); err != nil {
 		c.UI.Error(err.Error())
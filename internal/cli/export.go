@@ -0,0 +1,406 @@
+package cli
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+	"github.com/ethereum/go-ethereum/internal/cli/server"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Formats the blocks are exported in
+const (
+	exportFormatRLP   = "rlp"   // RLP encoded blocks, as written by the chain export of geth
+	exportFormatJSONL = "jsonl" // One JSON object per block and line
+)
+
+// exportReportInterval is the interval the export progress is logged at.
+const exportReportInterval = 8 * time.Second
+
+// ExportCommand is the command to stream a filtered range of the chain to a file
+type ExportCommand struct {
+	*Meta
+
+	datadirAncient string
+	from           uint64
+	to             uint64
+	format         string
+	sprintEnd      bool
+	signer         string
+	stateSync      bool
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *ExportCommand) MarkDown() string {
+	items := []string{
+		"# Export",
+		"The ```bor export <file>``` command streams a range of the canonical chain at the given datadir location to a file, keeping only the blocks matching the given filters.",
+		`
+The filters are combined, a block is exported only if it matches all of them. Blocks are written either RLP encoded, like the
+chain export of geth, or as one JSON object per line carrying the header, the transactions, the signer and whether the
+block committed state-sync events. The file is gzipped if its name ends with .gz. The node must be stopped
+while the chain is exported.
+`,
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *ExportCommand) Help() string {
+	return `Usage: bor export <file>
+
+  This command will stream the blocks of the chain matching the given filters to a file` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *ExportCommand) Synopsis() string {
+	return "Export a filtered range of the chain"
+}
+
+// Flags: datadir, datadir.ancient, from, to, format, sprint-end, signer, state-sync
+func (c *ExportCommand) Flags() *flagset.Flagset {
+	flags := c.NewFlagSet("export")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "datadir.ancient",
+		Value:   &c.datadirAncient,
+		Usage:   "Path of the ancient data directory",
+		Default: "",
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "from",
+		Value:   &c.from,
+		Usage:   "First block of the range to export",
+		Default: 0,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "to",
+		Value:   &c.to,
+		Usage:   "Last block of the range to export (0 = head block)",
+		Default: 0,
+	})
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "format",
+		Value:   &c.format,
+		Usage:   "Format of the exported blocks ('rlp' or 'jsonl')",
+		Default: exportFormatRLP,
+	})
+	flags.BoolFlag(&flagset.BoolFlag{
+		Name:    "sprint-end",
+		Value:   &c.sprintEnd,
+		Usage:   "Only export the last block of every sprint",
+		Default: false,
+	})
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "signer",
+		Value:   &c.signer,
+		Usage:   "Only export the blocks signed by the given address",
+		Default: "",
+	})
+	flags.BoolFlag(&flagset.BoolFlag{
+		Name:    "state-sync",
+		Value:   &c.stateSync,
+		Usage:   "Only export the blocks committing state-sync events",
+		Default: false,
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *ExportCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	args = flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Expected one argument: the file to export to")
+		return 1
+	}
+
+	datadir := c.dataDir
+	if datadir == "" {
+		c.UI.Error("datadir is required")
+		return 1
+	}
+
+	if c.format != exportFormatRLP && c.format != exportFormatJSONL {
+		c.UI.Error(fmt.Sprintf("unknown export format %q", c.format))
+		return 1
+	}
+
+	filter := exportFilter{
+		sprintEnd: c.sprintEnd,
+		stateSync: c.stateSync,
+	}
+
+	if c.signer != "" {
+		if !common.IsHexAddress(c.signer) {
+			c.UI.Error(fmt.Sprintf("invalid signer address %q", c.signer))
+			return 1
+		}
+
+		signer := common.HexToAddress(c.signer)
+		filter.signer = &signer
+	}
+
+	// Create the node
+	node, err := node.New(&node.Config{
+		DataDir: datadir,
+	})
+
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer node.Close()
+
+	dbHandles, err := server.MakeDatabaseHandles(0)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	start := time.Now()
+
+	exported, scanned, err := c.export(node, dbHandles, args[0], filter)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Exported %d of %d blocks to %s in %v", exported, scanned, args[0], common.PrettyDuration(time.Since(start))))
+
+	return 0
+}
+
+// export streams the blocks of the configured range matching the filter to
+// the given file, returning the number of blocks exported and scanned.
+func (c *ExportCommand) export(stack *node.Node, dbHandles int, fn string, filter exportFilter) (int, int, error) {
+	chaindb, err := stack.OpenDatabaseWithFreezer(chaindataPath, 1024, dbHandles, c.datadirAncient, "", true, false, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer chaindb.Close()
+
+	config := rawdb.ReadChainConfig(chaindb, rawdb.ReadCanonicalHash(chaindb, 0))
+	if config == nil {
+		return 0, 0, errors.New("failed to load the chain config")
+	}
+
+	if config.Bor == nil && (filter.sprintEnd || filter.signer != nil) {
+		return 0, 0, errors.New("the sprint-end and signer filters require a bor chain")
+	}
+
+	head := rawdb.ReadHeadBlock(chaindb)
+	if head == nil {
+		return 0, 0, errors.New("failed to load head block")
+	}
+
+	last := c.to
+	if last == 0 || last > head.NumberU64() {
+		last = head.NumberU64()
+	}
+
+	if c.from > last {
+		return 0, 0, fmt.Errorf("first block %d is past the last block %d", c.from, last)
+	}
+
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer fh.Close()
+
+	buffered := bufio.NewWriter(fh)
+
+	var writer io.Writer = buffered
+
+	if strings.HasSuffix(fn, ".gz") {
+		gz := gzip.NewWriter(buffered)
+		defer gz.Close()
+
+		writer = gz
+	}
+
+	log.Info("Exporting blocks", "file", fn, "from", c.from, "to", last, "format", c.format)
+
+	var (
+		exported int
+		scanned  int
+		reported = time.Now()
+	)
+
+	for number := c.from; number <= last; number++ {
+		hash := rawdb.ReadCanonicalHash(chaindb, number)
+		if hash == (common.Hash{}) {
+			return exported, scanned, fmt.Errorf("canonical block %d not found", number)
+		}
+
+		header := rawdb.ReadHeader(chaindb, hash, number)
+		if header == nil {
+			return exported, scanned, fmt.Errorf("header of block %d not found", number)
+		}
+
+		scanned++
+
+		match, signer, err := filter.match(chaindb, config, header)
+		if err != nil {
+			return exported, scanned, fmt.Errorf("failed to filter block %d: %w", number, err)
+		}
+
+		if match {
+			block := rawdb.ReadBlock(chaindb, hash, number)
+			if block == nil {
+				return exported, scanned, fmt.Errorf("body of block %d not found", number)
+			}
+
+			if err := c.write(writer, chaindb, config, block, signer); err != nil {
+				return exported, scanned, err
+			}
+
+			exported++
+		}
+
+		if time.Since(reported) >= exportReportInterval {
+			log.Info("Exporting blocks", "number", number, "exported", exported, "scanned", scanned)
+			reported = time.Now()
+		}
+	}
+
+	if gz, ok := writer.(*gzip.Writer); ok {
+		if err := gz.Close(); err != nil {
+			return exported, scanned, err
+		}
+	}
+
+	return exported, scanned, buffered.Flush()
+}
+
+// exportedBlock is a block exported in the JSONL format.
+type exportedBlock struct {
+	Header       *types.Header      `json:"header"`
+	Transactions types.Transactions `json:"transactions"`
+	Signer       *common.Address    `json:"signer,omitempty"`
+	StateSync    bool               `json:"stateSync"`
+}
+
+// write writes a block to the export in the configured format, recovering its
+// signer if the filter didn't already.
+func (c *ExportCommand) write(w io.Writer, db ethdb.Reader, config *params.ChainConfig, block *types.Block, signer *common.Address) error {
+	if c.format == exportFormatRLP {
+		return block.EncodeRLP(w)
+	}
+
+	if signer == nil && config.Bor != nil && block.NumberU64() > 0 {
+		if recovered, err := recoverSigner(block.Header(), config.Bor); err == nil {
+			signer = &recovered
+		}
+	}
+
+	blob, err := json.Marshal(&exportedBlock{
+		Header:       block.Header(),
+		Transactions: block.Transactions(),
+		Signer:       signer,
+		StateSync:    hasStateSync(db, block.Hash(), block.NumberU64()),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(blob, '\n'))
+
+	return err
+}
+
+// exportFilter selects the blocks streamed by the export command, a block is
+// exported only if it matches every criterion set.
+type exportFilter struct {
+	sprintEnd bool            // Only the last blocks of sprints
+	signer    *common.Address // Only the blocks signed by the address
+	stateSync bool            // Only the blocks committing state-sync events
+}
+
+// match returns whether the block of the given header passes the filter, along
+// with its signer if it had to be recovered.
+func (f *exportFilter) match(db ethdb.Reader, config *params.ChainConfig, header *types.Header) (bool, *common.Address, error) {
+	number := header.Number.Uint64()
+
+	if f.sprintEnd && (number+1)%config.Bor.CalculateSprint(number) != 0 {
+		return false, nil, nil
+	}
+
+	var signer *common.Address
+
+	if f.signer != nil {
+		// The genesis block isn't sealed by anyone
+		if number == 0 {
+			return false, nil, nil
+		}
+
+		recovered, err := recoverSigner(header, config.Bor)
+		if err != nil {
+			return false, nil, err
+		}
+
+		if recovered != *f.signer {
+			return false, nil, nil
+		}
+
+		signer = &recovered
+	}
+
+	if f.stateSync && !hasStateSync(db, header.Hash(), number) {
+		return false, nil, nil
+	}
+
+	return true, signer, nil
+}
+
+// recoverSigner returns the address that sealed a bor header.
+func recoverSigner(header *types.Header, config *params.BorConfig) (common.Address, error) {
+	if len(header.Extra) < types.ExtraSealLength {
+		return common.Address{}, errors.New("missing signature")
+	}
+
+	signature := header.Extra[len(header.Extra)-types.ExtraSealLength:]
+
+	pubkey, err := crypto.Ecrecover(bor.SealHash(header, config).Bytes(), signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	var signer common.Address
+
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	return signer, nil
+}
+
+// hasStateSync returns whether the block committed state-sync events, which is
+// when a bor receipt was stored for it.
+func hasStateSync(db ethdb.Reader, hash common.Hash, number uint64) bool {
+	return rawdb.ReadRawBorReceipt(db, hash, number) != nil
+}
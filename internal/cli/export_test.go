@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFilter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = &params.ChainConfig{Bor: &params.BorConfig{Sprint: map[string]uint64{"0": 4}}}
+	)
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	other := common.Address{0x1}
+
+	// Blocks 1 to 8 are sealed by the signer, 7 committed state-sync events
+	headers := make(map[uint64]*types.Header)
+
+	for number := uint64(1); number <= 8; number++ {
+		header := &types.Header{
+			Number:     new(big.Int).SetUint64(number),
+			Difficulty: big.NewInt(1),
+			Extra:      make([]byte, types.ExtraVanityLength+types.ExtraSealLength),
+		}
+
+		sig, err := crypto.Sign(bor.SealHash(header, config.Bor).Bytes(), key)
+		require.NoError(t, err)
+
+		copy(header.Extra[len(header.Extra)-types.ExtraSealLength:], sig)
+
+		headers[number] = header
+	}
+
+	rawdb.WriteBorReceipt(db, headers[7].Hash(), 7, &types.ReceiptForStorage{Status: types.ReceiptStatusSuccessful})
+
+	matching := func(filter exportFilter) []uint64 {
+		t.Helper()
+
+		var numbers []uint64
+
+		for number := uint64(1); number <= 8; number++ {
+			match, recovered, err := filter.match(db, config, headers[number])
+			require.NoError(t, err)
+
+			if match {
+				if filter.signer != nil {
+					require.Equal(t, signer, *recovered)
+				}
+
+				numbers = append(numbers, number)
+			}
+		}
+
+		return numbers
+	}
+
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8}, matching(exportFilter{}))
+	require.Equal(t, []uint64{3, 7}, matching(exportFilter{sprintEnd: true}))
+	require.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7, 8}, matching(exportFilter{signer: &signer}))
+	require.Empty(t, matching(exportFilter{signer: &other}))
+	require.Equal(t, []uint64{7}, matching(exportFilter{stateSync: true}))
+	require.Equal(t, []uint64{7}, matching(exportFilter{sprintEnd: true, signer: &signer, stateSync: true}))
+	require.Empty(t, matching(exportFilter{sprintEnd: true, signer: &other}))
+}
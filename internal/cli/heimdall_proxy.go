@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/bor/heimdallrecord"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+
+	"github.com/mitchellh/cli"
+)
+
+// HeimdallProxyCommand is the command to record the heimdall responses served
+// to nodes, or to replay such a recording
+type HeimdallProxyCommand struct {
+	UI cli.Ui
+
+	heimdall string
+	addr     string
+	record   string
+	replay   string
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *HeimdallProxyCommand) MarkDown() string {
+	items := []string{
+		"# Heimdall proxy",
+		"The ```heimdall-proxy``` command serves the Heimdall REST API to the nodes pointed at it with ```--bor.heimdall```. " +
+			"With ```--record``` it forwards their requests to Heimdall and appends the responses to a recording, " +
+			"with ```--replay``` it answers them from such a recording instead, without Heimdall.",
+		"Recordings captured in production reproduce the sprints, spans and state-syncs of an incident deterministically, " +
+			"through the full Heimdall client of the node under test. End-to-end tests replay them with ```heimdallrecord.NewHTTPReplayer```.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *HeimdallProxyCommand) Help() string {
+	return `Usage: bor heimdall-proxy (--record <file> | --replay <file>) [--heimdall URL] [--addr host:port]
+
+  Record the Heimdall responses served to nodes, or replay such a recording` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *HeimdallProxyCommand) Synopsis() string {
+	return "Record or replay the Heimdall responses served to nodes"
+}
+
+func (c *HeimdallProxyCommand) Flags() *flagset.Flagset {
+	flags := flagset.NewFlagSet("heimdall-proxy")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "heimdall",
+		Usage:   "URL of the Heimdall service the requests are forwarded to when recording",
+		Value:   &c.heimdall,
+		Default: "http://localhost:1317",
+	})
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "addr",
+		Usage:   "Address and port the proxy listens on",
+		Value:   &c.addr,
+		Default: "127.0.0.1:1318",
+	})
+	flags.StringFlag(&flagset.StringFlag{
+		Name:  "record",
+		Usage: "File the Heimdall responses are appended to",
+		Value: &c.record,
+	})
+	flags.StringFlag(&flagset.StringFlag{
+		Name:  "replay",
+		Usage: "File of recorded Heimdall responses to serve instead of a Heimdall service",
+		Value: &c.replay,
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *HeimdallProxyCommand) Run(args []string) int {
+	flags := c.Flags()
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if (c.record == "") == (c.replay == "") {
+		c.UI.Error("exactly one of record and replay is required")
+		return 1
+	}
+
+	var handler http.Handler
+
+	if c.record != "" {
+		proxy, err := heimdallrecord.NewProxy(c.heimdall, c.record)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+
+		defer func() {
+			if err := proxy.Close(); err != nil {
+				c.UI.Error(fmt.Sprintf("Failed to close the recording: %v", err))
+			}
+		}()
+
+		handler = proxy
+	} else {
+		replayer, err := heimdallrecord.NewHTTPReplayer(c.replay)
+		if err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+
+		handler = replayer
+	}
+
+	listener, err := net.Listen("tcp", c.addr)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+
+	if c.record != "" {
+		c.UI.Output(fmt.Sprintf("Recording the responses of %s to %s, serving on http://%s", c.heimdall, c.record, listener.Addr()))
+	} else {
+		c.UI.Output(fmt.Sprintf("Replaying the responses recorded in %s, serving on http://%s", c.replay, listener.Addr()))
+	}
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case sig := <-signalCh:
+		c.UI.Output(fmt.Sprintf("Caught signal: %v", sig))
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			c.UI.Error(err.Error())
+			return 1
+		}
+	}
+
+	// Stop serving before the recording is closed
+	if err := server.Close(); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	return 0
+}
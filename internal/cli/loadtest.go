@@ -0,0 +1,767 @@
+package cli
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/mitchellh/cli"
+)
+
+const (
+	loadTransfer = "transfer" // Plain value transfers to fresh accounts
+	loadERC20    = "erc20"    // Token transfers, updating two balances and emitting an event
+	loadStorage  = "storage"  // Calls writing fresh storage slots
+
+	// loadSenderBacklog is the number of transactions queued per sender before
+	// the generator drops the ones it can't send in time.
+	loadSenderBacklog = 16
+
+	// loadPollInterval is the interval the node is polled for new blocks at.
+	loadPollInterval = 250 * time.Millisecond
+)
+
+var (
+	// loadTokenCode is the runtime code of the token called by the erc20
+	// workload. Any call is handled as transfer(address,uint256), without a
+	// balance check, so that every sender can transfer from the start:
+	//
+	//	PUSH1 0x24 CALLDATALOAD PUSH1 0x04 CALLDATALOAD CALLER  // caller, to, amount
+	//	DUP1 SLOAD DUP4 SWAP1 SUB DUP2 SSTORE                   // balance[caller] -= amount
+	//	DUP2 SLOAD DUP4 ADD DUP3 SSTORE                         // balance[to] += amount
+	//	DUP3 PUSH1 0 MSTORE DUP2 DUP2 PUSH32 <Transfer> PUSH1 0x20 PUSH1 0 LOG3
+	//	PUSH1 1 PUSH1 0 MSTORE PUSH1 0x20 PUSH1 0 RETURN       // return true
+	loadTokenCode = hexutil.MustDecode("0x6024356004353380548390038155815483018255826000528181" +
+		"7fddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef60206000a3" +
+		"600160005260206000f3")
+
+	// loadStorageCode is the runtime code of the contract called by the storage
+	// workload. It writes the block timestamp to the given number of slots,
+	// starting at the given one: (count, start) = calldata[4:36], calldata[36:68].
+	//
+	//	PUSH1 0x04 CALLDATALOAD PUSH1 0x24 CALLDATALOAD  // slot, count
+	//	JUMPDEST DUP2 ISZERO PUSH1 0x1b JUMPI            // loop until count is zero
+	//	TIMESTAMP DUP2 SSTORE                            // storage[slot] = timestamp
+	//	PUSH1 1 ADD SWAP1 PUSH1 1 SWAP1 SUB SWAP1        // slot++, count--
+	//	PUSH1 0x06 JUMP JUMPDEST STOP
+	loadStorageCode = hexutil.MustDecode("0x6004356024355b8115601b574281556001019060019003906006565b00")
+
+	// loadTransferSelector is the selector of transfer(address,uint256).
+	loadTransferSelector = hexutil.MustDecode("0xa9059cbb")
+)
+
+// LoadtestCommand is the command to generate transaction workloads against a
+// devnet
+type LoadtestCommand struct {
+	UI cli.Ui
+
+	rpc      string
+	key      string
+	accounts uint64
+	fund     uint64
+	rate     uint64
+	duration time.Duration
+	wait     time.Duration
+	mix      string
+	slots    uint64
+	seed     int
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *LoadtestCommand) MarkDown() string {
+	items := []string{
+		"# Loadtest",
+		"The ```loadtest``` command generates a transaction workload against a devnet and reports the throughput and inclusion latency. " +
+			"The sender accounts are funded by the given key and derived from the seed, so that runs with the same flags are reproducible.",
+		"The workload mixes plain value transfers (```transfer```), token transfers (```erc20```) and calls writing fresh storage slots (```storage```), " +
+			"weighted as given, e.g. ```transfer=70,erc20=20,storage=10```. The contracts are deployed by the funding key before the run.",
+		"Never run it against a public network: it spends the funds of the key.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *LoadtestCommand) Help() string {
+	return `Usage: bor loadtest --key <hex> [--rate N] [--duration D] [--mix transfer=70,erc20=20,storage=10]
+
+  Generate a transaction workload against a devnet and report throughput and latency` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *LoadtestCommand) Synopsis() string {
+	return "Generate a transaction workload against a devnet"
+}
+
+func (c *LoadtestCommand) Flags() *flagset.Flagset {
+	flags := flagset.NewFlagSet("loadtest")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "rpc",
+		Usage:   "JSON-RPC endpoint of the devnet node the transactions are sent to",
+		Value:   &c.rpc,
+		Default: "http://127.0.0.1:8545",
+	})
+	flags.StringFlag(&flagset.StringFlag{
+		Name:  "key",
+		Usage: "Hex encoded private key of the account funding the senders and deploying the contracts",
+		Value: &c.key,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "accounts",
+		Usage:   "Number of sender accounts",
+		Value:   &c.accounts,
+		Default: 16,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "fund",
+		Usage:   "Ether sent to every sender account before the run",
+		Value:   &c.fund,
+		Default: 1,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "rate",
+		Usage:   "Transactions sent per second",
+		Value:   &c.rate,
+		Default: 100,
+	})
+	flags.DurationFlag(&flagset.DurationFlag{
+		Name:    "duration",
+		Usage:   "Time transactions are sent for",
+		Value:   &c.duration,
+		Default: time.Minute,
+	})
+	flags.DurationFlag(&flagset.DurationFlag{
+		Name:    "wait",
+		Usage:   "Time the transactions still pending at the end of the run are waited for",
+		Value:   &c.wait,
+		Default: 30 * time.Second,
+	})
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "mix",
+		Usage:   "Comma separated <workload>=<weight> pairs of the transfer, erc20 and storage workloads",
+		Value:   &c.mix,
+		Default: "transfer=70,erc20=20,storage=10",
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "slots",
+		Usage:   "Number of storage slots written per storage transaction",
+		Value:   &c.slots,
+		Default: 32,
+	})
+	flags.IntFlag(&flagset.IntFlag{
+		Name:    "seed",
+		Usage:   "Seed of the sender accounts and the workload, for reproducible runs",
+		Value:   &c.seed,
+		Default: 1,
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *LoadtestCommand) Run(args []string) int {
+	flags := c.Flags()
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.key == "" {
+		c.UI.Error("key is required")
+		return 1
+	}
+
+	if c.accounts == 0 || c.rate == 0 || c.duration <= 0 {
+		c.UI.Error("accounts, rate and duration must be positive")
+		return 1
+	}
+
+	mix, err := parseLoadMix(c.mix)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(c.key, "0x"))
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("invalid key: %v", err))
+		return 1
+	}
+
+	// Stop sending on interrupt, still reporting what was measured
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client, err := ethclient.DialContext(ctx, c.rpc)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer client.Close()
+
+	test := &loadtest{
+		client: client,
+		mix:    mix,
+		slots:  c.slots,
+		rng:    rand.New(rand.NewSource(int64(c.seed))),
+		stats:  make(map[string]*loadStats),
+		sent:   make(map[common.Hash]*loadPending),
+	}
+
+	for _, kind := range mix.kinds {
+		test.stats[kind] = &loadStats{}
+	}
+
+	c.UI.Output(fmt.Sprintf("Funding %d senders and deploying the contracts", c.accounts))
+
+	if err := test.setup(ctx, key, c.accounts, new(big.Int).Mul(new(big.Int).SetUint64(c.fund), big.NewInt(params.Ether)), c.seed); err != nil {
+		c.UI.Error(fmt.Sprintf("setup failed: %v", err))
+		return 1
+	}
+
+	c.UI.Output(fmt.Sprintf("Sending %d transactions per second for %v", c.rate, c.duration))
+
+	if err := test.run(ctx, c.rate, c.duration, c.wait); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	c.UI.Output(test.report())
+
+	return 0
+}
+
+// loadMix is the weighted set of workloads of a run.
+type loadMix struct {
+	kinds   []string
+	weights []uint64
+	total   uint64
+}
+
+// parseLoadMix parses comma separated <workload>=<weight> pairs.
+func parseLoadMix(s string) (*loadMix, error) {
+	mix := new(loadMix)
+
+	for _, pair := range strings.Split(s, ",") {
+		kind, weight, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, want <workload>=<weight>", pair)
+		}
+
+		switch kind {
+		case loadTransfer, loadERC20, loadStorage:
+		default:
+			return nil, fmt.Errorf("unknown workload %q", kind)
+		}
+
+		if slices.Contains(mix.kinds, kind) {
+			return nil, fmt.Errorf("duplicate workload %q", kind)
+		}
+
+		w, err := strconv.ParseUint(weight, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight of workload %q: %v", kind, err)
+		}
+
+		if w == 0 {
+			continue
+		}
+
+		mix.kinds = append(mix.kinds, kind)
+		mix.weights = append(mix.weights, w)
+		mix.total += w
+	}
+
+	if mix.total == 0 {
+		return nil, errors.New("empty workload mix")
+	}
+
+	return mix, nil
+}
+
+// pick returns a workload at random, in proportion to the weights.
+func (m *loadMix) pick(rng *rand.Rand) string {
+	n := rng.Uint64() % m.total
+
+	for i, w := range m.weights {
+		if n < w {
+			return m.kinds[i]
+		}
+
+		n -= w
+	}
+
+	return m.kinds[len(m.kinds)-1]
+}
+
+// loadStats is the outcome of the transactions of a workload.
+type loadStats struct {
+	sent      uint64
+	failed    uint64          // Transactions rejected by the node
+	dropped   uint64          // Transactions not sent as the sender was still busy
+	latencies []time.Duration // Time from sending to inclusion of included transactions
+}
+
+// loadPending is a transaction sent and not yet seen in a block.
+type loadPending struct {
+	kind string
+	time time.Time
+}
+
+// loadJob is a transaction to be built and sent by a sender.
+type loadJob struct {
+	kind string
+	to   common.Address // Recipient of transfers
+	salt uint64         // First slot written by storage calls
+}
+
+// loadSender is an account sending transactions of the workload.
+type loadSender struct {
+	key   *ecdsa.PrivateKey
+	addr  common.Address
+	nonce uint64
+	jobs  chan loadJob
+}
+
+// loadtest is a workload run against a node.
+type loadtest struct {
+	client *ethclient.Client
+	signer types.Signer
+	mix    *loadMix
+	slots  uint64
+	rng    *rand.Rand
+
+	senders []*loadSender
+	token   common.Address
+	storage common.Address
+
+	tipCap *big.Int
+	feeCap *big.Int
+
+	start     time.Time
+	blocks    uint64 // Blocks mined during the run
+	blockTxs  uint64 // Transactions of any origin in those blocks
+	blockGas  uint64 // Gas used by those blocks
+	firstTime uint64 // Timestamp of the first of those blocks
+	lastTime  uint64 // Timestamp of the last of those blocks
+
+	stats map[string]*loadStats
+	sent  map[common.Hash]*loadPending
+	lock  sync.Mutex
+}
+
+// setup derives and funds the senders, and deploys the contracts.
+func (t *loadtest) setup(ctx context.Context, key *ecdsa.PrivateKey, accounts uint64, fund *big.Int, seed int) error {
+	chainID, err := t.client.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+
+	t.signer = types.LatestSignerForChainID(chainID)
+
+	if t.tipCap, err = t.client.SuggestGasTipCap(ctx); err != nil {
+		return err
+	}
+
+	head, err := t.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	t.feeCap = new(big.Int).Set(t.tipCap)
+	if head.BaseFee != nil {
+		t.feeCap.Add(t.feeCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	}
+
+	funder := &loadSender{key: key, addr: crypto.PubkeyToAddress(key.PublicKey)}
+	if funder.nonce, err = t.client.PendingNonceAt(ctx, funder.addr); err != nil {
+		return err
+	}
+
+	var txs []*types.Transaction
+
+	for i := uint64(0); i < accounts; i++ {
+		key, err := crypto.ToECDSA(crypto.Keccak256([]byte(fmt.Sprintf("bor loadtest %d %d", seed, i))))
+		if err != nil {
+			return err
+		}
+
+		sender := &loadSender{key: key, addr: crypto.PubkeyToAddress(key.PublicKey), jobs: make(chan loadJob, loadSenderBacklog)}
+		if sender.nonce, err = t.client.PendingNonceAt(ctx, sender.addr); err != nil {
+			return err
+		}
+
+		t.senders = append(t.senders, sender)
+
+		tx, err := t.send(ctx, funder, &sender.addr, fund, params.TxGas, nil)
+		if err != nil {
+			return fmt.Errorf("funding sender %v: %w", sender.addr, err)
+		}
+
+		txs = append(txs, tx)
+	}
+
+	if slices.Contains(t.mix.kinds, loadERC20) {
+		tx, err := t.send(ctx, funder, nil, nil, 200_000, deployCode(loadTokenCode))
+		if err != nil {
+			return fmt.Errorf("deploying token: %w", err)
+		}
+
+		t.token = crypto.CreateAddress(funder.addr, tx.Nonce())
+		txs = append(txs, tx)
+	}
+
+	if slices.Contains(t.mix.kinds, loadStorage) {
+		tx, err := t.send(ctx, funder, nil, nil, 200_000, deployCode(loadStorageCode))
+		if err != nil {
+			return fmt.Errorf("deploying storage contract: %w", err)
+		}
+
+		t.storage = crypto.CreateAddress(funder.addr, tx.Nonce())
+		txs = append(txs, tx)
+	}
+
+	for _, tx := range txs {
+		if err := t.waitMined(ctx, tx.Hash()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// run sends transactions at the given rate for the given duration, then
+// waits for the pending ones to be included.
+func (t *loadtest) run(ctx context.Context, rate uint64, duration time.Duration, wait time.Duration) error {
+	head, err := t.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	t.start = time.Now()
+
+	var (
+		senders sync.WaitGroup
+		polling = make(chan struct{})
+		done    = make(chan struct{})
+	)
+
+	for _, sender := range t.senders {
+		senders.Add(1)
+
+		go func(sender *loadSender) {
+			defer senders.Done()
+
+			for job := range sender.jobs {
+				if ctx.Err() == nil {
+					t.sendJob(ctx, sender, job)
+				}
+			}
+		}(sender)
+	}
+
+	go func() {
+		defer close(polling)
+		t.poll(ctx, head.Number.Uint64(), done)
+	}()
+
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	timer := time.NewTimer(duration)
+
+loop:
+	for i := 0; ; i++ {
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			break loop
+		case <-ctx.Done():
+			break loop
+		}
+
+		job := loadJob{kind: t.mix.pick(t.rng), salt: t.rng.Uint64()}
+		t.rng.Read(job.to[:])
+
+		select {
+		case t.senders[i%len(t.senders)].jobs <- job:
+		default:
+			t.lock.Lock()
+			t.stats[job.kind].dropped++
+			t.lock.Unlock()
+		}
+	}
+
+	ticker.Stop()
+
+	for _, sender := range t.senders {
+		close(sender.jobs)
+	}
+
+	senders.Wait()
+
+	// Wait for the transactions still pending, unless interrupted
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+
+	for waiting := true; waiting && t.pending() > 0; {
+		select {
+		case <-time.After(loadPollInterval):
+		case <-deadline.C:
+			waiting = false
+		case <-ctx.Done():
+			waiting = false
+		}
+	}
+
+	close(done)
+	<-polling
+
+	return nil
+}
+
+// sendJob builds and sends the transaction of a job.
+func (t *loadtest) sendJob(ctx context.Context, sender *loadSender, job loadJob) {
+	var (
+		to   *common.Address
+		gas  uint64
+		data []byte
+	)
+
+	switch job.kind {
+	case loadTransfer:
+		to, gas = &job.to, params.TxGas
+	case loadERC20:
+		to, gas = &t.token, 80_000
+		data = append(append(slices.Clone(loadTransferSelector), common.LeftPadBytes(job.to[:], 32)...), common.LeftPadBytes([]byte{1}, 32)...)
+	case loadStorage:
+		to, gas = &t.storage, 30_000+t.slots*23_000
+		data = append(append(make([]byte, 4), common.LeftPadBytes(new(big.Int).SetUint64(t.slots).Bytes(), 32)...), common.LeftPadBytes(new(big.Int).SetUint64(job.salt).Bytes(), 32)...)
+	}
+
+	value := common.Big0
+	if job.kind == loadTransfer {
+		value = common.Big1
+	}
+
+	tx, err := t.send(ctx, sender, to, value, gas, data)
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	stats := t.stats[job.kind]
+	stats.sent++
+
+	if err != nil {
+		stats.failed++
+		return
+	}
+
+	t.sent[tx.Hash()] = &loadPending{kind: job.kind, time: time.Now()}
+}
+
+// send signs and sends a dynamic fee transaction from the given sender,
+// creating a contract if no recipient is given.
+func (t *loadtest) send(ctx context.Context, sender *loadSender, to *common.Address, value *big.Int, gas uint64, data []byte) (*types.Transaction, error) {
+	tx, err := types.SignNewTx(sender.key, t.signer, &types.DynamicFeeTx{
+		ChainID:   t.signer.ChainID(),
+		Nonce:     sender.nonce,
+		GasTipCap: t.tipCap,
+		GasFeeCap: t.feeCap,
+		Gas:       gas,
+		To:        to,
+		Value:     value,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := t.client.SendTransaction(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	sender.nonce++
+
+	return tx, nil
+}
+
+// waitMined waits for a transaction to be included, failing if it reverted.
+func (t *loadtest) waitMined(ctx context.Context, hash common.Hash) error {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	for {
+		receipt, err := t.client.TransactionReceipt(ctx, hash)
+		if err == nil {
+			if receipt.Status != types.ReceiptStatusSuccessful {
+				return fmt.Errorf("transaction %v failed", hash)
+			}
+
+			return nil
+		}
+
+		select {
+		case <-time.After(loadPollInterval):
+		case <-ctx.Done():
+			return fmt.Errorf("transaction %v not mined: %w", hash, ctx.Err())
+		}
+	}
+}
+
+// poll follows the blocks after the given one until done is closed, recording
+// the inclusion of the transactions sent.
+func (t *loadtest) poll(ctx context.Context, number uint64, done chan struct{}) {
+	ticker := time.NewTicker(loadPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-done:
+			return
+		}
+
+		head, err := t.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			continue
+		}
+
+		for ; number < head.Number.Uint64(); number++ {
+			block, err := t.client.BlockByNumber(ctx, new(big.Int).SetUint64(number+1))
+			if err != nil {
+				break
+			}
+
+			t.include(block, time.Now())
+		}
+	}
+}
+
+// include records a block mined during the run and the transactions sent
+// included in it.
+func (t *loadtest) include(block *types.Block, now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.blocks == 0 {
+		t.firstTime = block.Time()
+	}
+
+	t.blocks++
+	t.blockTxs += uint64(len(block.Transactions()))
+	t.blockGas += block.GasUsed()
+	t.lastTime = block.Time()
+
+	for _, tx := range block.Transactions() {
+		if pending, ok := t.sent[tx.Hash()]; ok {
+			stats := t.stats[pending.kind]
+			stats.latencies = append(stats.latencies, now.Sub(pending.time))
+
+			delete(t.sent, tx.Hash())
+		}
+	}
+}
+
+// pending returns the number of transactions sent and not yet included.
+func (t *loadtest) pending() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return len(t.sent)
+}
+
+// report formats the outcome of the run.
+func (t *loadtest) report() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	elapsed := time.Since(t.start)
+
+	var sent, included uint64
+
+	out := []string{"Workload|Sent|Failed|Dropped|Included|p50|p90|p99|Max"}
+
+	for _, kind := range t.mix.kinds {
+		stats := t.stats[kind]
+
+		sent += stats.sent - stats.failed
+		included += uint64(len(stats.latencies))
+
+		out = append(out, fmt.Sprintf("%s|%d|%d|%d|%d|%v|%v|%v|%v",
+			kind,
+			stats.sent,
+			stats.failed,
+			stats.dropped,
+			len(stats.latencies),
+			common.PrettyDuration(latencyPercentile(stats.latencies, 50)),
+			common.PrettyDuration(latencyPercentile(stats.latencies, 90)),
+			common.PrettyDuration(latencyPercentile(stats.latencies, 99)),
+			common.PrettyDuration(latencyPercentile(stats.latencies, 100)),
+		))
+	}
+
+	summary := []string{
+		fmt.Sprintf("Elapsed|%v", common.PrettyDuration(elapsed)),
+		fmt.Sprintf("Sent|%d (%.1f/s)", sent, float64(sent)/elapsed.Seconds()),
+		fmt.Sprintf("Included|%d", included),
+		fmt.Sprintf("Pending|%d", len(t.sent)),
+		fmt.Sprintf("Blocks|%d", t.blocks),
+	}
+
+	if t.blocks > 0 {
+		summary = append(summary,
+			fmt.Sprintf("Txs per block|%.1f", float64(t.blockTxs)/float64(t.blocks)),
+			fmt.Sprintf("Gas per block|%d", t.blockGas/t.blocks),
+		)
+	}
+
+	if t.lastTime > t.firstTime {
+		summary = append(summary, fmt.Sprintf("Chain throughput|%.1f tx/s", float64(t.blockTxs)/float64(t.lastTime-t.firstTime)))
+	}
+
+	return formatList(out) + "\n\n" + formatKV(summary)
+}
+
+// latencyPercentile returns the given percentile of the latencies, rounding to
+// the nearest rank.
+func latencyPercentile(latencies []time.Duration, percentile int) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(latencies)
+	slices.Sort(sorted)
+
+	rank := (percentile*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// deployCode returns the creation code deploying the given runtime code:
+//
+//	PUSH1 <len> DUP1 PUSH1 0x0b PUSH1 0 CODECOPY PUSH1 0 RETURN <runtime>
+func deployCode(runtime []byte) []byte {
+	return append([]byte{0x60, byte(len(runtime)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}, runtime...)
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLoadMix(t *testing.T) {
+	t.Parallel()
+
+	mix, err := parseLoadMix("transfer=70, erc20=20,storage=10")
+	require.NoError(t, err)
+	require.Equal(t, []string{loadTransfer, loadERC20, loadStorage}, mix.kinds)
+	require.Equal(t, uint64(100), mix.total)
+
+	// Workloads weighted zero are left out
+	mix, err = parseLoadMix("transfer=1,storage=0")
+	require.NoError(t, err)
+	require.Equal(t, []string{loadTransfer}, mix.kinds)
+
+	for _, invalid := range []string{"", "transfer", "transfer=x", "swap=1", "transfer=1,transfer=2", "storage=0"} {
+		_, err := parseLoadMix(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestLoadMixPick(t *testing.T) {
+	t.Parallel()
+
+	mix, err := parseLoadMix("transfer=70,erc20=20,storage=10")
+	require.NoError(t, err)
+
+	picks := func(seed int64) []string {
+		rng := rand.New(rand.NewSource(seed))
+
+		kinds := make([]string, 10000)
+		for i := range kinds {
+			kinds[i] = mix.pick(rng)
+		}
+
+		return kinds
+	}
+
+	// The workload is reproducible from the seed
+	kinds := picks(1)
+	require.Equal(t, kinds, picks(1))
+	require.NotEqual(t, kinds, picks(2))
+
+	// And follows the weights
+	counts := make(map[string]int)
+	for _, kind := range kinds {
+		counts[kind]++
+	}
+
+	require.InDelta(t, 7000, counts[loadTransfer], 300)
+	require.InDelta(t, 2000, counts[loadERC20], 300)
+	require.InDelta(t, 1000, counts[loadStorage], 300)
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	t.Parallel()
+
+	require.Zero(t, latencyPercentile(nil, 50))
+
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(100-i) * time.Millisecond
+	}
+
+	require.Equal(t, 50*time.Millisecond, latencyPercentile(latencies, 50))
+	require.Equal(t, 99*time.Millisecond, latencyPercentile(latencies, 99))
+	require.Equal(t, 100*time.Millisecond, latencyPercentile(latencies, 100))
+	require.Equal(t, time.Millisecond, latencyPercentile(latencies, 0))
+
+	// The latencies are left in order
+	require.Equal(t, 100*time.Millisecond, latencies[0])
+}
+
+func TestDeployCode(t *testing.T) {
+	t.Parallel()
+
+	code := deployCode(loadStorageCode)
+
+	// The creation code copies the runtime code following it and returns it
+	require.Equal(t, byte(len(loadStorageCode)), code[1])
+	require.Equal(t, byte(len(code)-len(loadStorageCode)), code[4])
+	require.Equal(t, loadStorageCode, code[code[4]:])
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
+	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
@@ -42,6 +43,9 @@ import (
 type Config struct {
 	chain *chains.Chain
 
+	// heimdallClient overrides the heimdall client configured below
+	heimdallClient bor.IHeimdallClient
+
 	// Chain is the chain to sync with
 	Chain string `hcl:"chain,optional" toml:"chain,optional"`
 
@@ -69,6 +73,10 @@ type Config struct {
 	// DBEngine is used to select leveldb or pebble as database
 	DBEngine string `hcl:"db.engine,optional" toml:"db.engine,optional"`
 
+	// FreezerCodec is used to select snappy or zstd as compression codec of the
+	// ancient bodies and receipts
+	FreezerCodec string `hcl:"db.freezercodec,optional" toml:"db.freezercodec,optional"`
+
 	// KeyStoreDir is the directory to store keystores
 	KeyStoreDir string `hcl:"keystore,optional" toml:"keystore,optional"`
 
@@ -78,6 +86,15 @@ type Config struct {
 	// Maximum size (in bytes) a result of an rpc request could have (default=100000, use 0 for no limits)
 	RPCReturnDataLimit uint64 `hcl:"rpc.returndatalimit,optional" toml:"rpc.returndatalimit,optional"`
 
+	// Add the bor metadata (signer, sprint, span, state-sync) of blocks to the block responses
+	RPCBorMetadata bool `hcl:"rpc.bormetadata,optional" toml:"rpc.bormetadata,optional"`
+
+	// Maximum cumulative cost of the calls in a batch, where expensive methods cost more than one (default=0, use 0 for no limits)
+	RPCBatchCostLimit uint64 `hcl:"rpc.batchcostlimit,optional" toml:"rpc.batchcostlimit,optional"`
+
+	// Number of concurrently running HTTP calls divided fairly between client hosts (default=0, use 0 to disable)
+	RPCFairSchedulingSlots uint64 `hcl:"rpc.fairslots,optional" toml:"rpc.fairslots,optional"`
+
 	// SyncMode selects the sync protocol
 	SyncMode string `hcl:"syncmode,optional" toml:"syncmode,optional"`
 
@@ -93,6 +110,36 @@ type Config struct {
 	// BorLogs enables bor log retrieval
 	BorLogs bool `hcl:"bor.logs,optional" toml:"bor.logs,optional"`
 
+	// MaxReorgDepth is the number of blocks beyond which reorgs are refused until allowed with admin_allowDeepReorg
+	MaxReorgDepth uint64 `hcl:"bor.maxreorgdepth,optional" toml:"bor.maxreorgdepth,optional"`
+
+	// NonCanonicalRetention is the number of heimdall checkpoints reorged-out blocks are retained for
+	NonCanonicalRetention uint64 `hcl:"bor.noncanonicalretention,optional" toml:"bor.noncanonicalretention,optional"`
+
+	// VerifyWorkers is the number of goroutines recovering the signers of header batches being verified
+	VerifyWorkers uint64 `hcl:"bor.verifyworkers,optional" toml:"bor.verifyworkers,optional"`
+
+	// SignatureCache is the number of recent block signers kept in memory
+	SignatureCache uint64 `hcl:"bor.sigcache,optional" toml:"bor.sigcache,optional"`
+
+	// SnapshotInterval is the number of blocks after which the bor snapshot is stored to the database
+	SnapshotInterval uint64 `hcl:"bor.snapshotinterval,optional" toml:"bor.snapshotinterval,optional"`
+
+	// SprintSnapshots stores the bor snapshot of every sprint, for archive nodes
+	SprintSnapshots bool `hcl:"bor.sprintsnapshots,optional" toml:"bor.sprintsnapshots,optional"`
+
+	// SnapshotDiffs stores the bor snapshot of every sprint as a diff over the previous one, between the ones stored in full
+	SnapshotDiffs bool `hcl:"bor.snapshotdiffs,optional" toml:"bor.snapshotdiffs,optional"`
+
+	// ValidatorArchive archives the validator set of every sprint for historical queries
+	ValidatorArchive bool `hcl:"bor.validatorarchive,optional" toml:"bor.validatorarchive,optional"`
+
+	// VerifySpans warns about the spans served by heimdall failing the check against the span committed on chain
+	VerifySpans bool `hcl:"bor.verifyspans,optional" toml:"bor.verifyspans,optional"`
+
+	// ProducerPeers is a list of <validator address>=<enode URL> pairs of the block producers to stay connected to
+	ProducerPeers []string `hcl:"bor.producerpeers,optional" toml:"bor.producerpeers,optional"`
+
 	// Ethstats is the address of the ethstats server to send telemetry
 	Ethstats string `hcl:"ethstats,optional" toml:"ethstats,optional"`
 
@@ -142,6 +189,18 @@ type Config struct {
 
 	// Pprof has the pprof related settings
 	Pprof *PprofConfig `hcl:"pprof,block" toml:"pprof,block"`
+
+	// Alerts has the consensus alerting related settings
+	Alerts *AlertsConfig `hcl:"alerts,block" toml:"alerts,block"`
+
+	// Clock has the local clock sanity check related settings
+	Clock *ClockConfig `hcl:"clock,block" toml:"clock,block"`
+
+	// ForkWatch has the fork schedule check against the network manifest related settings
+	ForkWatch *ForkWatchConfig `hcl:"forkwatch,block" toml:"forkwatch,block"`
+
+	// Health has the readiness and liveness endpoints related settings
+	Health *HealthConfig `hcl:"health,block" toml:"health,block"`
 }
 
 type LoggingConfig struct {
@@ -181,10 +240,61 @@ type PprofConfig struct {
 	// Turn on block profiling with the given rate
 	BlockProfileRate int `hcl:"blockprofilerate,optional" toml:"blockprofilerate,optional"`
 
+	// Fraction of the slot time block execution or sealing may take before a profile is captured
+	LatencyFraction float64 `hcl:"latencyfraction,optional" toml:"latencyfraction,optional"`
+
+	// Number of latency profiles kept for debug_latencyProfiles
+	LatencyProfiles uint64 `hcl:"latencyprofiles,optional" toml:"latencyprofiles,optional"`
+
 	// // Write CPU profile to the given file
 	// CPUProfile string `hcl:"cpuprofile,optional" toml:"cpuprofile,optional"`
 }
 
+type AlertsConfig struct {
+	// Webhook is the url consensus alerts are posted to (Slack/PagerDuty compatible)
+	Webhook string `hcl:"webhook,optional" toml:"webhook,optional"`
+
+	// HeadStall is the time the chain head may not move before an alert is raised
+	HeadStall    time.Duration `hcl:"-,optional" toml:"-"`
+	HeadStallRaw string        `hcl:"headstall,optional" toml:"headstall,optional"`
+
+	// HeimdallDown is the time heimdall may be unreachable before an alert is raised
+	HeimdallDown    time.Duration `hcl:"-,optional" toml:"-"`
+	HeimdallDownRaw string        `hcl:"heimdalldown,optional" toml:"heimdalldown,optional"`
+
+	// AnomalyThreshold is the number of standard deviations a block may deviate from its producer's profile
+	AnomalyThreshold float64 `hcl:"anomalythreshold,optional" toml:"anomalythreshold,optional"`
+}
+
+type ClockConfig struct {
+	// MaxOffset is the maximum offset of the local clock from NTP before sealing is refused
+	MaxOffset    time.Duration `hcl:"-,optional" toml:"-"`
+	MaxOffsetRaw string        `hcl:"maxoffset,optional" toml:"maxoffset,optional"`
+
+	// Servers are the NTP servers the local clock is checked against
+	Servers []string `hcl:"servers,optional" toml:"servers,optional"`
+}
+
+type ForkWatchConfig struct {
+	// URL is the URL of the signed fork manifest of the network the local fork schedule is checked against
+	URL string `hcl:"url,optional" toml:"url,optional"`
+
+	// Signer is the address the fork manifest must be signed by
+	Signer string `hcl:"signer,optional" toml:"signer,optional"`
+}
+
+type HealthConfig struct {
+	// Enabled serves the /readyz and /livez endpoints on the HTTP-RPC server
+	Enabled bool `hcl:"enabled,optional" toml:"enabled,optional"`
+
+	// MaxLag is the number of blocks the head may trail the milestone tip by while the node is ready
+	MaxLag uint64 `hcl:"maxlag,optional" toml:"maxlag,optional"`
+
+	// StallTimeout is the time the import or sealing loop may not progress before the node isn't live
+	StallTimeout    time.Duration `hcl:"-,optional" toml:"-"`
+	StallTimeoutRaw string        `hcl:"stalltimeout,optional" toml:"stalltimeout,optional"`
+}
+
 type P2PConfig struct {
 	// MaxPeers sets the maximum number of connected peers
 	MaxPeers uint64 `hcl:"maxpeers,optional" toml:"maxpeers,optional"`
@@ -260,6 +370,15 @@ type HeimdallConfig struct {
 	// GRPCAddress is the address of the heimdall grpc server
 	GRPCAddress string `hcl:"grpc-address,optional" toml:"grpc-address,optional"`
 
+	// Record is the file the responses of heimdall are recorded to
+	Record string `hcl:"bor.heimdallrecord,optional" toml:"bor.heimdallrecord,optional"`
+
+	// Replay is the file of recorded heimdall responses served instead of heimdall
+	Replay string `hcl:"bor.heimdallreplay,optional" toml:"bor.heimdallreplay,optional"`
+
+	// StateSyncReplay is the file of recorded state-sync events committed when running without heimdall
+	StateSyncReplay string `hcl:"bor.statesyncreplay,optional" toml:"bor.statesyncreplay,optional"`
+
 	// RunHeimdall is used to run heimdall as a child process
 	RunHeimdall bool `hcl:"bor.runheimdall,optional" toml:"bor.runheimdall,optional"`
 
@@ -268,6 +387,9 @@ type HeimdallConfig struct {
 
 	// UseHeimdallApp is used to fetch data from heimdall app when running heimdall as a child process
 	UseHeimdallApp bool `hcl:"bor.useheimdallapp,optional" toml:"bor.useheimdallapp,optional"`
+
+	// ReportDoubleSign is used to submit the evidence of double signing validators to heimdall
+	ReportDoubleSign bool `hcl:"bor.reportdoublesign,optional" toml:"bor.reportdoublesign,optional"`
 }
 
 type TxPoolConfig struct {
@@ -329,6 +451,19 @@ type SealerConfig struct {
 	RecommitRaw string        `hcl:"recommit,optional" toml:"recommit,optional"`
 
 	CommitInterruptFlag bool `hcl:"commitinterrupt,optional" toml:"commitinterrupt,optional"`
+
+	// PolicyEndpoint is the JSON-RPC endpoint of the policy engine reviewing the candidate blocks
+	PolicyEndpoint string `hcl:"policyendpoint,optional" toml:"policyendpoint,optional"`
+
+	// PolicyTimeout is the maximum time allowance for the review of a candidate block
+	PolicyTimeout    time.Duration `hcl:"-,optional" toml:"-"`
+	PolicyTimeoutRaw string        `hcl:"policytimeout,optional" toml:"policytimeout,optional"`
+
+	// InterruptCarryover is the number of accounts left out by an interrupted commit to retry first in the next one
+	InterruptCarryover uint64 `hcl:"interruptcarryover,optional" toml:"interruptcarryover,optional"`
+
+	// WatchdogSlots is the number of block slots the sealing loop may not attempt work for before it's restarted
+	WatchdogSlots uint64 `hcl:"watchdogslots,optional" toml:"watchdogslots,optional"`
 }
 
 type JsonRPCConfig struct {
@@ -404,6 +539,12 @@ type APIConfig struct {
 	// API is the list of enabled api modules
 	API []string `hcl:"api,optional" toml:"api,optional"`
 
+	// APIAllow is the list of methods enabled even if their api module isn't, without the rest of it
+	APIAllow []string `hcl:"api-allow,optional" toml:"api-allow,optional"`
+
+	// APIDeny is the list of methods disabled even if their api module is enabled
+	APIDeny []string `hcl:"api-deny,optional" toml:"api-deny,optional"`
+
 	// VHost is the list of valid virtual hosts
 	VHost []string `hcl:"vhosts,optional" toml:"vhosts,optional"`
 
@@ -485,6 +626,12 @@ type TelemetryConfig struct {
 
 	// Open collector endpoint
 	OpenCollectorEndpoint string `hcl:"opencollector-endpoint,optional" toml:"opencollector-endpoint,optional"`
+
+	// BlockTracing traces the lifecycle of blocks to the open collector endpoint
+	BlockTracing bool `hcl:"opencollector-blocks,optional" toml:"opencollector-blocks,optional"`
+
+	// SprintTracing traces the work done at sprint boundaries to the open collector endpoint
+	SprintTracing bool `hcl:"opencollector-sprints,optional" toml:"opencollector-sprints,optional"`
 }
 
 type InfluxDBConfig struct {
@@ -556,6 +703,12 @@ type CacheConfig struct {
 
 	// Raise the open file descriptor resource limit (default = system fd limit)
 	FDLimit int `hcl:"fdlimit,optional" toml:"fdlimit,optional"`
+
+	// Preload is the list of contracts whose code and storage are preloaded at startup
+	Preload []string `hcl:"preload,optional" toml:"preload,optional"`
+
+	// PreloadSlots is the maximum number of storage slots preloaded per contract
+	PreloadSlots int `hcl:"preloadslots,optional" toml:"preloadslots,optional"`
 }
 
 type ExtraDBConfig struct {
@@ -580,6 +733,9 @@ type AccountsConfig struct {
 
 	// DisableBorWallet disables the personal wallet endpoints
 	DisableBorWallet bool `hcl:"disable-bor-wallet,optional" toml:"disable-bor-wallet,optional"`
+
+	// ExternalSigner is the URL or IPC path of an external signer (e.g. clef) serving the account API
+	ExternalSigner string `hcl:"signer,optional" toml:"signer,optional"`
 }
 
 type DeveloperConfig struct {
@@ -620,8 +776,11 @@ func DefaultConfig() *Config {
 			Debug:               false,
 			EnableBlockTracking: false,
 		},
-		RPCBatchLimit:      100,
-		RPCReturnDataLimit: 100000,
+		RPCBatchLimit:          100,
+		RPCReturnDataLimit:     100000,
+		RPCBatchCostLimit:      0,
+		RPCFairSchedulingSlots: 0,
+		RPCBorMetadata:         false,
 		P2P: &P2PConfig{
 			MaxPeers:      50,
 			MaxPendPeers:  50,
@@ -647,11 +806,13 @@ func DefaultConfig() *Config {
 			Without:     false,
 			GRPCAddress: "",
 		},
-		SyncMode:    "full",
-		GcMode:      "full",
-		StateScheme: "path",
-		Snapshot:    true,
-		BorLogs:     false,
+		SyncMode:      "full",
+		GcMode:        "full",
+		StateScheme:   "path",
+		Snapshot:      true,
+		BorLogs:       false,
+		MaxReorgDepth: 1024, // one bor checkpoint interval
+		VerifySpans:   false,
 		TxPool: &TxPoolConfig{
 			Locals:       []string{},
 			NoLocals:     false,
@@ -673,6 +834,10 @@ func DefaultConfig() *Config {
 			ExtraData:           "",
 			Recommit:            125 * time.Second,
 			CommitInterruptFlag: true,
+			InterruptCarryover:  0,
+			PolicyEndpoint:      "",
+			PolicyTimeout:       200 * time.Millisecond,
+			WatchdogSlots:       0,
 		},
 		Gpo: &GpoConfig{
 			Blocks:           20,
@@ -734,6 +899,8 @@ func DefaultConfig() *Config {
 			Expensive:             false,
 			PrometheusAddr:        "127.0.0.1:7071",
 			OpenCollectorEndpoint: "",
+			BlockTracing:          false,
+			SprintTracing:         false,
 			InfluxDB: &InfluxDBConfig{
 				V1Enabled:    false,
 				Endpoint:     "",
@@ -760,6 +927,8 @@ func DefaultConfig() *Config {
 			FilterLogCacheSize: ethconfig.Defaults.FilterLogCacheSize,
 			TrieTimeout:        60 * time.Minute,
 			FDLimit:            0,
+			Preload:            []string{},
+			PreloadSlots:       1024,
 		},
 		ExtraDB: &ExtraDBConfig{
 			// These are LevelDB defaults, specifying here for clarity in code and in logging.
@@ -775,6 +944,7 @@ func DefaultConfig() *Config {
 			AllowInsecureUnlock: false,
 			UseLightweightKDF:   false,
 			DisableBorWallet:    true,
+			ExternalSigner:      "",
 		},
 		GRPC: &GRPCConfig{
 			Addr: ":3131",
@@ -791,6 +961,8 @@ func DefaultConfig() *Config {
 			Addr:             "127.0.0.1",
 			MemProfileRate:   512 * 1024,
 			BlockProfileRate: 0,
+			LatencyFraction:  0,
+			LatencyProfiles:  8,
 			// CPUProfile:       "",
 		},
 		ParallelEVM: &ParallelEVMConfig{
@@ -798,6 +970,25 @@ func DefaultConfig() *Config {
 			SpeculativeProcesses: 8,
 			Enforce:              false,
 		},
+		Alerts: &AlertsConfig{
+			Webhook:          "",
+			HeadStall:        60 * time.Second,
+			HeimdallDown:     5 * time.Minute,
+			AnomalyThreshold: 0,
+		},
+		Clock: &ClockConfig{
+			MaxOffset: 0,
+			Servers:   []string{},
+		},
+		ForkWatch: &ForkWatchConfig{
+			URL:    "",
+			Signer: "",
+		},
+		Health: &HealthConfig{
+			Enabled:      false,
+			MaxLag:       64,
+			StallTimeout: 5 * time.Minute,
+		},
 	}
 }
 
@@ -844,6 +1035,7 @@ func (c *Config) fillTimeDurations() error {
 	}{
 		{"jsonrpc.evmtimeout", &c.JsonRPC.RPCEVMTimeout, &c.JsonRPC.RPCEVMTimeoutRaw},
 		{"miner.recommit", &c.Sealer.Recommit, &c.Sealer.RecommitRaw},
+		{"miner.policytimeout", &c.Sealer.PolicyTimeout, &c.Sealer.PolicyTimeoutRaw},
 		{"jsonrpc.timeouts.read", &c.JsonRPC.HttpTimeout.ReadTimeout, &c.JsonRPC.HttpTimeout.ReadTimeoutRaw},
 		{"jsonrpc.timeouts.write", &c.JsonRPC.HttpTimeout.WriteTimeout, &c.JsonRPC.HttpTimeout.WriteTimeoutRaw},
 		{"jsonrpc.timeouts.idle", &c.JsonRPC.HttpTimeout.IdleTimeout, &c.JsonRPC.HttpTimeout.IdleTimeoutRaw},
@@ -853,6 +1045,10 @@ func (c *Config) fillTimeDurations() error {
 		{"txpool.rejournal", &c.TxPool.Rejournal, &c.TxPool.RejournalRaw},
 		{"cache.timeout", &c.Cache.TrieTimeout, &c.Cache.TrieTimeoutRaw},
 		{"p2p.txarrivalwait", &c.P2P.TxArrivalWait, &c.P2P.TxArrivalWaitRaw},
+		{"alerts.headstall", &c.Alerts.HeadStall, &c.Alerts.HeadStallRaw},
+		{"alerts.heimdalldown", &c.Alerts.HeimdallDown, &c.Alerts.HeimdallDownRaw},
+		{"clock.maxoffset", &c.Clock.MaxOffset, &c.Clock.MaxOffsetRaw},
+		{"health.stalltimeout", &c.Health.StallTimeout, &c.Health.StallTimeoutRaw},
 	}
 
 	for _, x := range tds {
@@ -932,9 +1128,14 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 	n.HeimdallURL = c.Heimdall.URL
 	n.WithoutHeimdall = c.Heimdall.Without
 	n.HeimdallgRPCAddress = c.Heimdall.GRPCAddress
+	n.HeimdallRecordFile = c.Heimdall.Record
+	n.HeimdallReplayFile = c.Heimdall.Replay
+	n.StateSyncReplayFile = c.Heimdall.StateSyncReplay
 	n.RunHeimdall = c.Heimdall.RunHeimdall
 	n.RunHeimdallArgs = c.Heimdall.RunHeimdallArgs
 	n.UseHeimdallApp = c.Heimdall.UseHeimdallApp
+	n.ReportDoubleSign = c.Heimdall.ReportDoubleSign
+	n.HeimdallClient = c.heimdallClient
 
 	// Developer Fake Author for producing blocks without authorisation on bor consensus
 	n.DevFakeAuthor = c.DevFakeAuthor
@@ -975,6 +1176,10 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		n.Miner.GasCeil = c.Sealer.GasCeil
 		n.Miner.ExtraData = []byte(c.Sealer.ExtraData)
 		n.Miner.CommitInterruptFlag = c.Sealer.CommitInterruptFlag
+		n.Miner.InterruptCarryover = int(c.Sealer.InterruptCarryover)
+		n.Miner.PolicyEndpoint = c.Sealer.PolicyEndpoint
+		n.Miner.PolicyTimeout = c.Sealer.PolicyTimeout
+		n.Miner.WatchdogSlots = c.Sealer.WatchdogSlots
 
 		if etherbase := c.Sealer.Etherbase; etherbase != "" {
 			if !common.IsHexAddress(etherbase) {
@@ -1088,6 +1293,26 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		}
 	}
 
+	// ProducerPeers
+	{
+		n.ProducerPeers = map[common.Address][]*enode.Node{}
+
+		for _, entry := range c.ProducerPeers {
+			addr, url, ok := strings.Cut(entry, "=")
+			if !ok || !common.IsHexAddress(addr) {
+				return nil, fmt.Errorf("invalid producer peer %s: expected <address>=<enode URL>", entry)
+			}
+
+			node, err := enode.Parse(enode.ValidSchemes, url)
+			if err != nil {
+				return nil, fmt.Errorf("invalid producer peer enode %s: %v", url, err)
+			}
+
+			producer := common.HexToAddress(addr)
+			n.ProducerPeers[producer] = append(n.ProducerPeers[producer], node)
+		}
+	}
+
 	// cache
 	{
 		cache := c.Cache.Cache
@@ -1125,6 +1350,15 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 		n.TrieTimeout = c.Cache.TrieTimeout
 		n.TriesInMemory = c.Cache.TriesInMemory
 		n.FilterLogCacheSize = c.Cache.FilterLogCacheSize
+		n.PreloadStorageSlots = c.Cache.PreloadSlots
+
+		for _, contract := range c.Cache.Preload {
+			if !common.IsHexAddress(contract) {
+				return nil, fmt.Errorf("invalid preload contract address %q", contract)
+			}
+
+			n.PreloadContracts = append(n.PreloadContracts, common.HexToAddress(contract))
+		}
 	}
 
 	// LevelDB
@@ -1198,12 +1432,22 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 	}
 
 	n.BorLogs = c.BorLogs
+	n.MaxReorgDepth = c.MaxReorgDepth
+	n.NonCanonicalRetention = c.NonCanonicalRetention
+	n.VerifyWorkers = int(c.VerifyWorkers)
+	n.SignatureCache = int(c.SignatureCache)
+	n.SnapshotInterval = c.SnapshotInterval
+	n.SprintSnapshots = c.SprintSnapshots
+	n.SnapshotDiffs = c.SnapshotDiffs
+	n.ValidatorArchive = c.ValidatorArchive
+	n.VerifySpans = c.VerifySpans
 	n.DatabaseHandles = dbHandles
 
 	n.ParallelEVM.Enable = c.ParallelEVM.Enable
 	n.ParallelEVM.SpeculativeProcesses = c.ParallelEVM.SpeculativeProcesses
 	n.ParallelEVM.Enforce = c.ParallelEVM.Enforce
 	n.RPCReturnDataLimit = c.RPCReturnDataLimit
+	n.RPCBorMetadata = c.RPCBorMetadata
 
 	if c.Ancient != "" {
 		n.DatabaseFreezer = c.Ancient
@@ -1211,6 +1455,27 @@ func (c *Config) buildEth(stack *node.Node, accountManager *accounts.Manager) (*
 
 	n.EnableBlockTracking = c.Logging.EnableBlockTracking
 
+	// consensus alerts
+	n.AlertWebhook = c.Alerts.Webhook
+	n.AlertHeadStall = c.Alerts.HeadStall
+	n.AlertHeimdallDown = c.Alerts.HeimdallDown
+	n.AlertAnomalyThreshold = c.Alerts.AnomalyThreshold
+	n.ClockMaxOffset = c.Clock.MaxOffset
+	n.ClockServers = c.Clock.Servers
+
+	if c.ForkWatch.URL != "" {
+		if !common.IsHexAddress(c.ForkWatch.Signer) {
+			return nil, fmt.Errorf("forkwatch.signer is not an address: %s", c.ForkWatch.Signer)
+		}
+
+		n.ForkManifestURL = c.ForkWatch.URL
+		n.ForkManifestSigner = common.HexToAddress(c.ForkWatch.Signer)
+	}
+
+	n.HealthEndpoints = c.Health.Enabled
+	n.HealthMaxLag = c.Health.MaxLag
+	n.HealthStallTimeout = c.Health.StallTimeout
+
 	return &n, nil
 }
 
@@ -1331,6 +1596,7 @@ func (c *Config) buildNode() (*node.Config, error) {
 		Name:                  clientIdentifier,
 		DataDir:               c.DataDir,
 		DBEngine:              c.DBEngine,
+		FreezerCodec:          c.FreezerCodec,
 		KeyStoreDir:           c.KeyStoreDir,
 		UseLightweightKDF:     c.Accounts.UseLightweightKDF,
 		InsecureUnlockAllowed: c.Accounts.AllowInsecureUnlock,
@@ -1347,10 +1613,14 @@ func (c *Config) buildNode() (*node.Config, error) {
 			TxArrivalWait:   c.P2P.TxArrivalWait,
 		},
 		HTTPModules:         c.JsonRPC.Http.API,
+		HTTPAllowedMethods:  c.JsonRPC.Http.APIAllow,
+		HTTPDeniedMethods:   c.JsonRPC.Http.APIDeny,
 		HTTPCors:            c.JsonRPC.Http.Cors,
 		HTTPVirtualHosts:    c.JsonRPC.Http.VHost,
 		HTTPPathPrefix:      c.JsonRPC.Http.Prefix,
 		WSModules:           c.JsonRPC.Ws.API,
+		WSAllowedMethods:    c.JsonRPC.Ws.APIAllow,
+		WSDeniedMethods:     c.JsonRPC.Ws.APIDeny,
 		WSOrigins:           c.JsonRPC.Ws.Origins,
 		WSPathPrefix:        c.JsonRPC.Ws.Prefix,
 		GraphQLCors:         c.JsonRPC.Graphql.Cors,
@@ -1365,6 +1635,8 @@ func (c *Config) buildNode() (*node.Config, error) {
 		AuthAddr:                               c.JsonRPC.Auth.Addr,
 		AuthVirtualHosts:                       c.JsonRPC.Auth.VHosts,
 		RPCBatchLimit:                          c.RPCBatchLimit,
+		RPCBatchCostLimit:                      c.RPCBatchCostLimit,
+		RPCFairSchedulingSlots:                 c.RPCFairSchedulingSlots,
 		WSJsonRPCExecutionPoolSize:             c.JsonRPC.Ws.ExecutionPoolSize,
 		WSJsonRPCExecutionPoolRequestTimeout:   c.JsonRPC.Ws.ExecutionPoolRequestTimeout,
 		HTTPJsonRPCExecutionPoolSize:           c.JsonRPC.Http.ExecutionPoolSize,
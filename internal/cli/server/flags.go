@@ -63,6 +63,12 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Value:   &c.cliConfig.DBEngine,
 		Default: c.cliConfig.DBEngine,
 	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "db.freezercodec",
+		Usage:   "Compression codec of newly created ancient body and receipt tables ('snappy' or 'zstd')",
+		Value:   &c.cliConfig.FreezerCodec,
+		Default: c.cliConfig.FreezerCodec,
+	})
 	f.StringFlag(&flagset.StringFlag{
 		Name:    "keystore",
 		Usage:   "Path of the directory where keystores are located",
@@ -81,6 +87,24 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Value:   &c.cliConfig.RPCReturnDataLimit,
 		Default: c.cliConfig.RPCReturnDataLimit,
 	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "rpc.bormetadata",
+		Usage:   "Add the bor metadata of blocks (signer, in-turn, sprint, span and state-sync) to the eth_getBlockByNumber and eth_getBlockByHash responses as borMetadata",
+		Value:   &c.cliConfig.RPCBorMetadata,
+		Default: c.cliConfig.RPCBorMetadata,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "rpc.batchcostlimit",
+		Usage:   "Maximum cumulative cost of the calls in a batch, where expensive methods like eth_getLogs or traces cost more than one (use 0 for no limits)",
+		Value:   &c.cliConfig.RPCBatchCostLimit,
+		Default: c.cliConfig.RPCBatchCostLimit,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "rpc.fairslots",
+		Usage:   "Number of concurrently running HTTP calls, divided fairly between client hosts (use 0 to disable)",
+		Value:   &c.cliConfig.RPCFairSchedulingSlots,
+		Default: c.cliConfig.RPCFairSchedulingSlots,
+	})
 	f.StringFlag(&flagset.StringFlag{
 		Name:  "config",
 		Usage: "Path to the TOML configuration file",
@@ -122,6 +146,66 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Value:   &c.cliConfig.BorLogs,
 		Default: c.cliConfig.BorLogs,
 	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bor.maxreorgdepth",
+		Usage:   "Number of blocks beyond which reorgs are refused until allowed with admin_allowDeepReorg (0 = no limit)",
+		Value:   &c.cliConfig.MaxReorgDepth,
+		Default: c.cliConfig.MaxReorgDepth,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bor.noncanonicalretention",
+		Usage:   "Number of Heimdall checkpoints reorged-out blocks are retained for (0 = until frozen)",
+		Value:   &c.cliConfig.NonCanonicalRetention,
+		Default: c.cliConfig.NonCanonicalRetention,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bor.verifyworkers",
+		Usage:   "Number of workers recovering the signers of header batches being verified (0 = number of CPUs)",
+		Value:   &c.cliConfig.VerifyWorkers,
+		Default: c.cliConfig.VerifyWorkers,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bor.sigcache",
+		Usage:   "Number of recent block signers kept in memory for verification and RPC author lookups (0 = 4096)",
+		Value:   &c.cliConfig.SignatureCache,
+		Default: c.cliConfig.SignatureCache,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "bor.snapshotinterval",
+		Usage:   "Number of blocks after which the bor snapshot is stored to the database, rounded down to a multiple of the sprint (0 = 1024)",
+		Value:   &c.cliConfig.SnapshotInterval,
+		Default: c.cliConfig.SnapshotInterval,
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "bor.sprintsnapshots",
+		Usage:   "Store the bor snapshot of every sprint, so historical validator set queries don't replay headers (for archive nodes)",
+		Value:   &c.cliConfig.SprintSnapshots,
+		Default: c.cliConfig.SprintSnapshots,
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "bor.snapshotdiffs",
+		Usage:   "Store the bor snapshot of every sprint as a small diff over the previous one, the snapshots of the interval being stored in full",
+		Value:   &c.cliConfig.SnapshotDiffs,
+		Default: c.cliConfig.SnapshotDiffs,
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "bor.validatorarchive",
+		Usage:   "Archive the validator set of every sprint, so bor_getValidatorsAtBlock doesn't replay headers",
+		Value:   &c.cliConfig.ValidatorArchive,
+		Default: c.cliConfig.ValidatorArchive,
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "bor.verifyspans",
+		Usage:   "Warn about the spans served by Heimdall which don't follow the span committed on chain or keep at most 2/3 of the voting power of its producers as validators",
+		Value:   &c.cliConfig.VerifySpans,
+		Default: c.cliConfig.VerifySpans,
+	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "bor.producerpeers",
+		Usage:   "Comma separated <validator address>=<enode URL> pairs, the node stays connected to the ones producing the current and next span",
+		Value:   &c.cliConfig.ProducerPeers,
+		Default: c.cliConfig.ProducerPeers,
+	})
 
 	// logging related flags (log-level and verbosity is present above, it will be removed soon)
 	f.StringFlag(&flagset.StringFlag{
@@ -185,6 +269,24 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Value:   &c.cliConfig.Heimdall.GRPCAddress,
 		Default: c.cliConfig.Heimdall.GRPCAddress,
 	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "bor.heimdallrecord",
+		Usage:   "File to record the responses of the Heimdall service to",
+		Value:   &c.cliConfig.Heimdall.Record,
+		Default: c.cliConfig.Heimdall.Record,
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "bor.heimdallreplay",
+		Usage:   "File of recorded Heimdall responses to serve instead of a Heimdall service (for testing purpose)",
+		Value:   &c.cliConfig.Heimdall.Replay,
+		Default: c.cliConfig.Heimdall.Replay,
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "bor.statesyncreplay",
+		Usage:   "File of recorded state-sync events to commit when running without Heimdall, e.g. on a devnet forked from mainnet state",
+		Value:   &c.cliConfig.Heimdall.StateSyncReplay,
+		Default: c.cliConfig.Heimdall.StateSyncReplay,
+	})
 	f.BoolFlag(&flagset.BoolFlag{
 		Name:    "bor.runheimdall",
 		Usage:   "Run Heimdall service as a child process",
@@ -203,6 +305,12 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Value:   &c.cliConfig.Heimdall.UseHeimdallApp,
 		Default: c.cliConfig.Heimdall.UseHeimdallApp,
 	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "bor.reportdoublesign",
+		Usage:   "Submit the evidence of double signing validators to Heimdall for slashing",
+		Value:   &c.cliConfig.Heimdall.ReportDoubleSign,
+		Default: c.cliConfig.Heimdall.ReportDoubleSign,
+	})
 
 	// txpool options
 	f.SliceStringFlag(&flagset.SliceStringFlag{
@@ -333,6 +441,34 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Default: c.cliConfig.Sealer.CommitInterruptFlag,
 		Group:   "Sealer",
 	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "miner.interruptcarryover",
+		Usage:   "Number of accounts left out of an interrupted block commit to retry first in the next one (0 = disabled)",
+		Value:   &c.cliConfig.Sealer.InterruptCarryover,
+		Default: c.cliConfig.Sealer.InterruptCarryover,
+		Group:   "Sealer",
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "miner.policyendpoint",
+		Usage:   "JSON-RPC endpoint of a policy engine scoring or vetoing the candidate blocks before sealing (policy_reviewBlock)",
+		Value:   &c.cliConfig.Sealer.PolicyEndpoint,
+		Default: c.cliConfig.Sealer.PolicyEndpoint,
+		Group:   "Sealer",
+	})
+	f.DurationFlag(&flagset.DurationFlag{
+		Name:    "miner.policytimeout",
+		Usage:   "The maximum time allowance for the review of a candidate block, after which it's sealed regardless",
+		Value:   &c.cliConfig.Sealer.PolicyTimeout,
+		Default: c.cliConfig.Sealer.PolicyTimeout,
+		Group:   "Sealer",
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "miner.watchdogslots",
+		Usage:   "Number of block slots the sealing loop may not attempt work for before the miner is restarted and an alert raised (0 = disabled)",
+		Value:   &c.cliConfig.Sealer.WatchdogSlots,
+		Default: c.cliConfig.Sealer.WatchdogSlots,
+		Group:   "Sealer",
+	})
 
 	// ethstats
 	f.StringFlag(&flagset.StringFlag{
@@ -444,6 +580,20 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Default: c.cliConfig.Cache.FilterLogCacheSize,
 		Group:   "Cache",
 	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "cache.preload",
+		Usage:   "Comma separated contracts whose code and storage are preloaded into the caches at startup",
+		Value:   &c.cliConfig.Cache.Preload,
+		Default: c.cliConfig.Cache.Preload,
+		Group:   "Cache",
+	})
+	f.IntFlag(&flagset.IntFlag{
+		Name:    "cache.preloadslots",
+		Usage:   "Maximum number of storage slots preloaded per contract",
+		Value:   &c.cliConfig.Cache.PreloadSlots,
+		Default: c.cliConfig.Cache.PreloadSlots,
+		Group:   "Cache",
+	})
 	f.Uint64Flag(&flagset.Uint64Flag{
 		Name:    "txlookuplimit",
 		Usage:   "Number of recent blocks to maintain transactions index for",
@@ -639,6 +789,20 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Default: c.cliConfig.JsonRPC.Http.API,
 		Group:   "JsonRPC",
 	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "http.api.allow",
+		Usage:   "Methods offered over the HTTP-RPC interface even if their API isn't, without the rest of it (e.g. bor_getCurrentValidators)",
+		Value:   &c.cliConfig.JsonRPC.Http.APIAllow,
+		Default: c.cliConfig.JsonRPC.Http.APIAllow,
+		Group:   "JsonRPC",
+	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "http.api.deny",
+		Usage:   "Methods not offered over the HTTP-RPC interface even if their API is (e.g. debug_setHead)",
+		Value:   &c.cliConfig.JsonRPC.Http.APIDeny,
+		Default: c.cliConfig.JsonRPC.Http.APIDeny,
+		Group:   "JsonRPC",
+	})
 	f.Uint64Flag(&flagset.Uint64Flag{
 		Name:    "http.ep-size",
 		Usage:   "Maximum size of workers to run in rpc execution pool for HTTP requests",
@@ -690,6 +854,20 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Default: c.cliConfig.JsonRPC.Ws.API,
 		Group:   "JsonRPC",
 	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "ws.api.allow",
+		Usage:   "Methods offered over the WS-RPC interface even if their API isn't, without the rest of it (e.g. bor_getCurrentValidators)",
+		Value:   &c.cliConfig.JsonRPC.Ws.APIAllow,
+		Default: c.cliConfig.JsonRPC.Ws.APIAllow,
+		Group:   "JsonRPC",
+	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "ws.api.deny",
+		Usage:   "Methods not offered over the WS-RPC interface even if their API is (e.g. debug_setHead)",
+		Value:   &c.cliConfig.JsonRPC.Ws.APIDeny,
+		Default: c.cliConfig.JsonRPC.Ws.APIDeny,
+		Group:   "JsonRPC",
+	})
 	f.Uint64Flag(&flagset.Uint64Flag{
 		Name:    "ws.ep-size",
 		Usage:   "Maximum size of workers to run in rpc execution pool for WS requests",
@@ -885,6 +1063,20 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Default: c.cliConfig.Telemetry.OpenCollectorEndpoint,
 		Group:   "Telemetry",
 	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "metrics.opencollector-blocks",
+		Usage:   "Trace the lifecycle of blocks (announcement, fetch, verification, execution, commit, broadcast and sealing) to the OpenCollector endpoint",
+		Value:   &c.cliConfig.Telemetry.BlockTracing,
+		Default: c.cliConfig.Telemetry.BlockTracing,
+		Group:   "Telemetry",
+	})
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "metrics.opencollector-sprints",
+		Usage:   "Trace the work done at sprint boundaries (span fetch, validator set update, state-sync commit and snapshot store) to the OpenCollector endpoint",
+		Value:   &c.cliConfig.Telemetry.SprintTracing,
+		Default: c.cliConfig.Telemetry.SprintTracing,
+		Group:   "Telemetry",
+	})
 	// influx db v2
 	f.BoolFlag(&flagset.BoolFlag{
 		Name:    "metrics.influxdbv2",
@@ -950,6 +1142,13 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Value:   &c.cliConfig.Accounts.DisableBorWallet,
 		Default: c.cliConfig.Accounts.DisableBorWallet,
 	}))
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "signer",
+		Usage:   "External signer (url or path to ipc file) holding the accounts, e.g. clef, so the sealing key isn't unlocked in the node",
+		Value:   &c.cliConfig.Accounts.ExternalSigner,
+		Default: c.cliConfig.Accounts.ExternalSigner,
+		Group:   "Account Management",
+	})
 
 	// grpc
 	f.StringFlag(&flagset.StringFlag{
@@ -1031,6 +1230,18 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 		Value:   &c.cliConfig.Pprof.BlockProfileRate,
 		Default: c.cliConfig.Pprof.BlockProfileRate,
 	})
+	f.Float64Flag(&flagset.Float64Flag{
+		Name:    "pprof.latencyfraction",
+		Usage:   "Fraction of the slot time block execution or sealing may take before CPU and heap profiles are captured (0 = disabled)",
+		Value:   &c.cliConfig.Pprof.LatencyFraction,
+		Default: c.cliConfig.Pprof.LatencyFraction,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "pprof.latencyprofiles",
+		Usage:   "Number of latency profiles kept for retrieval through debug_latencyProfiles",
+		Value:   &c.cliConfig.Pprof.LatencyProfiles,
+		Default: c.cliConfig.Pprof.LatencyProfiles,
+	})
 	// f.StringFlag(&flagset.StringFlag{
 	// 	Name:    "pprof.cpuprofile",
 	// 	Usage:   "Write CPU profile to the given file",
@@ -1038,5 +1249,79 @@ func (c *Command) Flags(config *Config) *flagset.Flagset {
 	// 	Default: c.cliConfig.Pprof.CPUProfile,
 	// })
 
+	// alerts
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "alerts.webhook",
+		Usage:   "URL of the webhook (Slack/PagerDuty compatible) consensus alerts are posted to",
+		Value:   &c.cliConfig.Alerts.Webhook,
+		Default: c.cliConfig.Alerts.Webhook,
+	})
+	f.DurationFlag(&flagset.DurationFlag{
+		Name:    "alerts.headstall",
+		Usage:   "Time the chain head may not move before an alert is raised (0 = disabled)",
+		Value:   &c.cliConfig.Alerts.HeadStall,
+		Default: c.cliConfig.Alerts.HeadStall,
+	})
+	f.DurationFlag(&flagset.DurationFlag{
+		Name:    "alerts.heimdalldown",
+		Usage:   "Time heimdall may be unreachable before an alert is raised (0 = disabled)",
+		Value:   &c.cliConfig.Alerts.HeimdallDown,
+		Default: c.cliConfig.Alerts.HeimdallDown,
+	})
+	f.Float64Flag(&flagset.Float64Flag{
+		Name:    "alerts.anomalythreshold",
+		Usage:   "Standard deviations a block's gas usage, tx count or execution time may deviate from its producer's profile before it's flagged (0 = disabled)",
+		Value:   &c.cliConfig.Alerts.AnomalyThreshold,
+		Default: c.cliConfig.Alerts.AnomalyThreshold,
+	})
+
+	// clock
+	f.DurationFlag(&flagset.DurationFlag{
+		Name:    "clock.maxoffset",
+		Usage:   "Maximum offset of the local clock from NTP before sealing is refused (0 = disabled)",
+		Value:   &c.cliConfig.Clock.MaxOffset,
+		Default: c.cliConfig.Clock.MaxOffset,
+	})
+	f.SliceStringFlag(&flagset.SliceStringFlag{
+		Name:    "clock.servers",
+		Usage:   "Comma separated NTP servers the local clock is checked against (default pool.ntp.org, time.google.com, time.cloudflare.com)",
+		Value:   &c.cliConfig.Clock.Servers,
+		Default: c.cliConfig.Clock.Servers,
+	})
+
+	// fork watch
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "forkwatch.url",
+		Usage:   "URL of the signed fork manifest of the network the local fork schedule is checked against (empty = disabled)",
+		Value:   &c.cliConfig.ForkWatch.URL,
+		Default: c.cliConfig.ForkWatch.URL,
+	})
+	f.StringFlag(&flagset.StringFlag{
+		Name:    "forkwatch.signer",
+		Usage:   "Address the fork manifest must be signed by",
+		Value:   &c.cliConfig.ForkWatch.Signer,
+		Default: c.cliConfig.ForkWatch.Signer,
+	})
+
+	// health
+	f.BoolFlag(&flagset.BoolFlag{
+		Name:    "health.enabled",
+		Usage:   "Serve the /readyz and /livez endpoints on the HTTP-RPC server",
+		Value:   &c.cliConfig.Health.Enabled,
+		Default: c.cliConfig.Health.Enabled,
+	})
+	f.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "health.maxlag",
+		Usage:   "Number of blocks the head may trail the milestone tip by while the node is ready",
+		Value:   &c.cliConfig.Health.MaxLag,
+		Default: c.cliConfig.Health.MaxLag,
+	})
+	f.DurationFlag(&flagset.DurationFlag{
+		Name:    "health.stalltimeout",
+		Usage:   "Time the import or sealing loop may not progress before the node isn't live",
+		Value:   &c.cliConfig.Health.StallTimeout,
+		Default: c.cliConfig.Health.StallTimeout,
+	})
+
 	return f
 }
@@ -12,8 +12,10 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/external"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common/tracing"
 	"github.com/ethereum/go-ethereum/consensus/beacon" //nolint:typecheck
 	"github.com/ethereum/go-ethereum/consensus/bor"    //nolint:typecheck
 	"github.com/ethereum/go-ethereum/consensus/clique"
@@ -24,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/graphql"
 	"github.com/ethereum/go-ethereum/internal/cli/server/pprof"
 	"github.com/ethereum/go-ethereum/internal/cli/server/proto"
+	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/metrics/influxdb"
@@ -83,6 +86,15 @@ func WithGRPCListener(lis net.Listener) serverOption {
 	}
 }
 
+// WithHeimdallClient makes the node use the given heimdall client instead of
+// connecting to the configured heimdall service.
+func WithHeimdallClient(client bor.IHeimdallClient) serverOption {
+	return func(_ *Server, config *Config) error {
+		config.heimdallClient = client
+		return nil
+	}
+}
+
 func VerbosityIntToString(verbosity int) string {
 	mapIntToString := map[int]string{
 		5: "trace",
@@ -118,6 +130,8 @@ func NewServer(config *Config, opts ...serverOption) (*Server, error) {
 		pprof.StartPProf(fmt.Sprintf("%s:%d", config.Pprof.Addr, config.Pprof.Port))
 	}
 
+	debug.ConfigureLatencyProfiles(config.Pprof.LatencyFraction, int(config.Pprof.LatencyProfiles))
+
 	runtime.SetMutexProfileFraction(5)
 
 	srv := &Server{
@@ -167,6 +181,21 @@ func NewServer(config *Config, opts ...serverOption) (*Server, error) {
 	// proceed to authorize the local account manager in any case
 	accountManager.AddBackend(keystore.NewKeyStore(keydir, n, p))
 
+	// register the external signer (clef or any signer serving its account API),
+	// so that the sealing key doesn't have to be unlocked within the node
+	var extBackend *external.ExternalBackend
+
+	if config.Accounts.ExternalSigner != "" {
+		log.Info("Using external signer", "url", config.Accounts.ExternalSigner)
+
+		extBackend, err = external.NewExternalBackend(config.Accounts.ExternalSigner)
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to external signer: %v", err)
+		}
+
+		accountManager.AddBackend(extBackend)
+	}
+
 	// flag to set if we're authorizing consensus here
 	authorized := false
 
@@ -178,6 +207,10 @@ func NewServer(config *Config, opts ...serverOption) (*Server, error) {
 		// add keystore globally to the node's account manager if personal wallet is enabled
 		stack.AccountManager().AddBackend(keystore.NewKeyStore(keydir, n, p))
 
+		if extBackend != nil {
+			stack.AccountManager().AddBackend(extBackend)
+		}
+
 		// register the ethereum backend
 		ethCfg, err = config.buildEth(stack, stack.AccountManager())
 		if err != nil {
@@ -426,6 +459,24 @@ func (s *Server) setupMetrics(config *TelemetryConfig, serviceName string) error
 		s.tracer = tracerProvider
 
 		log.Info("Open collector tracing started", "address", config.OpenCollectorEndpoint)
+
+		if config.BlockTracing {
+			tracing.EnableBlockTracing()
+			log.Info("Block lifecycle tracing enabled")
+		}
+
+		if config.SprintTracing {
+			tracing.EnableSprintTracing()
+			log.Info("Sprint boundary tracing enabled")
+		}
+	} else {
+		if config.BlockTracing {
+			log.Warn("Block lifecycle tracing requires an open collector endpoint, disabled")
+		}
+
+		if config.SprintTracing {
+			log.Warn("Sprint boundary tracing requires an open collector endpoint, disabled")
+		}
 	}
 
 	return nil
@@ -501,6 +552,16 @@ func (s *Server) GetLatestBlockNumber() *big.Int {
 	return s.backend.BlockChain().CurrentBlock().Number
 }
 
+// Node returns the node stack of the server.
+func (s *Server) Node() *node.Node {
+	return s.node
+}
+
+// Backend returns the ethereum backend of the server.
+func (s *Server) Backend() *eth.Ethereum {
+	return s.backend
+}
+
 func (s *Server) GetGrpcAddr() string {
 	return s.config.GRPC.Addr[1:]
 }
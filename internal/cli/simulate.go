@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// SimulateCommand is the command to group the simulation commands
+type SimulateCommand struct {
+	UI cli.Ui
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *SimulateCommand) MarkDown() string {
+	items := []string{
+		"# Simulate",
+		"The ```simulate``` command groups offline simulations of the consensus rules:",
+		"- [```simulate rewards```](./simulate_rewards.md): Simulate the proposer rotation and fee capture of a stake distribution.",
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *SimulateCommand) Help() string {
+	return `Usage: bor simulate <subcommand>
+
+  This command groups offline simulations of the consensus rules.
+
+  Simulate the block production and fee capture of a stake distribution:
+
+    $ bor simulate rewards --stake-distribution stakes.json --sprints 1000`
+}
+
+// Synopsis implements the cli.Command interface
+func (c *SimulateCommand) Synopsis() string {
+	return "Simulate the consensus rules"
+}
+
+// Run implements the cli.Command interface
+func (c *SimulateCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
@@ -0,0 +1,264 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+	"github.com/ethereum/go-ethereum/internal/cli/flagset"
+
+	"github.com/mitchellh/cli"
+)
+
+// SimulateRewardsCommand is the command to simulate the block production of a
+// stake distribution
+type SimulateRewardsCommand struct {
+	UI cli.Ui
+
+	stakeDistribution string
+	sprints           uint64
+	sprintLength      uint64
+	fee               float64
+	missRate          float64
+	seed              int
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *SimulateRewardsCommand) MarkDown() string {
+	items := []string{
+		"# Simulate rewards",
+		"The ```simulate rewards``` command simulates the proposer rotation of a stake distribution under the current rules, " +
+			"with every validator as a producer. Producers missing their slot are replaced by the backup producers in turn, " +
+			"and each block produced captures the same fees.",
+		"The stake distribution is a JSON file listing the validators, e.g. ```[{\"signer\": \"0x...\", \"power\": 100}]```.",
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *SimulateRewardsCommand) Help() string {
+	return `Usage: bor simulate rewards --stake-distribution <file> [--sprints N]
+
+  Simulate the proposer rotation, fee capture and missed slots of a stake distribution` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *SimulateRewardsCommand) Synopsis() string {
+	return "Simulate the block production and fee capture of a stake distribution"
+}
+
+func (c *SimulateRewardsCommand) Flags() *flagset.Flagset {
+	flags := flagset.NewFlagSet("simulate rewards")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:  "stake-distribution",
+		Usage: "JSON file listing the signer and voting power of every validator",
+		Value: &c.stakeDistribution,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "sprints",
+		Usage:   "Number of sprints to simulate",
+		Value:   &c.sprints,
+		Default: 1000,
+	})
+	flags.Uint64Flag(&flagset.Uint64Flag{
+		Name:    "sprint",
+		Usage:   "Number of blocks per sprint",
+		Value:   &c.sprintLength,
+		Default: 16,
+	})
+	flags.Float64Flag(&flagset.Float64Flag{
+		Name:    "fee",
+		Usage:   "Average fees captured by the producer of a block",
+		Value:   &c.fee,
+		Default: 0,
+	})
+	flags.Float64Flag(&flagset.Float64Flag{
+		Name:    "missrate",
+		Usage:   "Probability of a producer missing its slot, from 0 to 1",
+		Value:   &c.missRate,
+		Default: 0,
+	})
+	flags.IntFlag(&flagset.IntFlag{
+		Name:    "seed",
+		Usage:   "Seed of the missed slots, for reproducible simulations",
+		Value:   &c.seed,
+		Default: 1,
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *SimulateRewardsCommand) Run(args []string) int {
+	flags := c.Flags()
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	if c.stakeDistribution == "" {
+		c.UI.Error("stake-distribution is required")
+		return 1
+	}
+
+	if c.sprints == 0 || c.sprintLength == 0 {
+		c.UI.Error("sprints and sprint must be positive")
+		return 1
+	}
+
+	if c.missRate < 0 || c.missRate > 1 {
+		c.UI.Error("missrate must be between 0 and 1")
+		return 1
+	}
+
+	validators, err := loadStakeDistribution(c.stakeDistribution)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	stats, unproduced := simulateRewards(validators, c.sprints, c.sprintLength, c.missRate, rand.New(rand.NewSource(int64(c.seed))))
+
+	var totalPower int64
+	for _, stat := range stats {
+		totalPower += stat.Power
+	}
+
+	total := c.sprints * c.sprintLength
+	produced := total - unproduced
+
+	out := []string{"Signer|Stake|Sprints|Produced|Backup|Missed|Block share|Fees"}
+
+	for _, stat := range stats {
+		var share float64
+		if produced > 0 {
+			share = float64(100*stat.Produced) / float64(produced)
+		}
+
+		out = append(out, fmt.Sprintf("%s|%.2f%%|%d|%d|%d|%d|%.2f%%|%.4f",
+			stat.Signer.Hex(),
+			float64(100*stat.Power)/float64(totalPower),
+			stat.Sprints,
+			stat.Produced,
+			stat.Backup,
+			stat.Missed,
+			share,
+			float64(stat.Produced)*c.fee,
+		))
+	}
+
+	c.UI.Output(formatList(out))
+	c.UI.Output("")
+	c.UI.Output(formatKV([]string{
+		fmt.Sprintf("Blocks|%d", total),
+		fmt.Sprintf("Unproduced|%d", unproduced),
+		fmt.Sprintf("Fees|%.4f", float64(produced)*c.fee),
+	}))
+
+	return 0
+}
+
+// loadStakeDistribution reads the validators of a stake distribution file.
+func loadStakeDistribution(path string) ([]*valset.Validator, error) {
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var validators []*valset.Validator
+	if err := json.Unmarshal(blob, &validators); err != nil {
+		return nil, fmt.Errorf("invalid stake distribution: %v", err)
+	}
+
+	if len(validators) == 0 {
+		return nil, errors.New("empty stake distribution")
+	}
+
+	seen := make(map[common.Address]bool, len(validators))
+
+	for _, validator := range validators {
+		if validator.VotingPower <= 0 {
+			return nil, fmt.Errorf("validator %s has no voting power", validator.Address)
+		}
+
+		if seen[validator.Address] {
+			return nil, fmt.Errorf("duplicate validator %s", validator.Address)
+		}
+
+		seen[validator.Address] = true
+	}
+
+	return validators, nil
+}
+
+// rewardStats is the simulated block production of a validator.
+type rewardStats struct {
+	Signer   common.Address
+	Power    int64
+	Sprints  uint64 // Sprints the validator was the in-turn producer of
+	Produced uint64 // Blocks produced, in-turn or as a backup
+	Backup   uint64 // Blocks produced as a backup of missing producers
+	Missed   uint64 // Slots missed as the in-turn producer
+}
+
+// simulateRewards simulates the given number of sprints produced by the
+// validators. The proposer rotates every sprint by proposer priority, and a
+// producer missing its slot is replaced by the next validator in order, as the
+// backup producers are. It returns the stats of the validators, sorted by
+// address, and the number of blocks no validator produced.
+func simulateRewards(validators []*valset.Validator, sprints uint64, sprintLength uint64, missRate float64, rng *rand.Rand) ([]*rewardStats, uint64) {
+	set := valset.NewValidatorSet(validators)
+
+	stats := make([]*rewardStats, set.Size())
+	for i, validator := range set.Validators {
+		stats[i] = &rewardStats{Signer: validator.Address, Power: validator.VotingPower}
+	}
+
+	var unproduced uint64
+
+	for sprint := uint64(0); sprint < sprints; sprint++ {
+		proposer, _ := set.GetByAddress(set.GetProposer().Address)
+		stats[proposer].Sprints++
+
+		for block := uint64(0); block < sprintLength; block++ {
+			produced := false
+
+			for rank := 0; rank < set.Size(); rank++ {
+				producer := stats[(proposer+rank)%set.Size()]
+
+				if missRate > 0 && rng.Float64() < missRate {
+					if rank == 0 {
+						producer.Missed++
+					}
+
+					continue
+				}
+
+				producer.Produced++
+				if rank > 0 {
+					producer.Backup++
+				}
+
+				produced = true
+
+				break
+			}
+
+			if !produced {
+				unproduced++
+			}
+		}
+
+		set.IncrementProposerPriority(1)
+	}
+
+	return stats, unproduced
+}
@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/valset"
+)
+
+func TestSimulateRewards(t *testing.T) {
+	t.Parallel()
+
+	validators := func() []*valset.Validator {
+		return []*valset.Validator{
+			valset.NewValidator(common.HexToAddress("0x01"), 300),
+			valset.NewValidator(common.HexToAddress("0x02"), 100),
+		}
+	}
+
+	// Without missed slots, the sprints are split by stake
+	stats, unproduced := simulateRewards(validators(), 400, 16, 0, rand.New(rand.NewSource(1)))
+	require.Zero(t, unproduced)
+	require.Equal(t, uint64(300), stats[0].Sprints)
+	require.Equal(t, uint64(100), stats[1].Sprints)
+	require.Equal(t, uint64(300*16), stats[0].Produced)
+	require.Zero(t, stats[0].Backup+stats[1].Backup)
+
+	// Missed slots are taken over by the backup producers
+	stats, unproduced = simulateRewards(validators(), 400, 16, 0.5, rand.New(rand.NewSource(1)))
+	require.NotZero(t, stats[0].Missed)
+	require.NotZero(t, stats[0].Backup)
+	require.NotZero(t, unproduced)
+	require.Equal(t, uint64(400*16), stats[0].Produced+stats[1].Produced+unproduced)
+
+	// Nobody produces when every slot is missed
+	_, unproduced = simulateRewards(validators(), 10, 16, 1, rand.New(rand.NewSource(1)))
+	require.Equal(t, uint64(10*16), unproduced)
+}
+
+func TestLoadStakeDistribution(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+		return path
+	}
+
+	validators, err := loadStakeDistribution(write("valid.json", `[{"signer": "0x0000000000000000000000000000000000000001", "power": 10}, {"signer": "0x0000000000000000000000000000000000000002", "power": 20}]`))
+	require.NoError(t, err)
+	require.Len(t, validators, 2)
+	require.Equal(t, int64(20), validators[1].VotingPower)
+
+	_, err = loadStakeDistribution(write("empty.json", `[]`))
+	require.Error(t, err)
+
+	_, err = loadStakeDistribution(write("zero.json", `[{"signer": "0x0000000000000000000000000000000000000001", "power": 0}]`))
+	require.Error(t, err)
+
+	_, err = loadStakeDistribution(write("duplicate.json", `[{"signer": "0x0000000000000000000000000000000000000001", "power": 1}, {"signer": "0x0000000000000000000000000000000000000001", "power": 2}]`))
+	require.Error(t, err)
+}
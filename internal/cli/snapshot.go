@@ -39,6 +39,7 @@ func (c *SnapshotCommand) MarkDown() string {
 		"- [```snapshot prune-state```](./snapshot_prune-state.md): Prune state databases at the given datadir location.",
 		"- [```snapshot prune-block```](./snapshot_prune-block.md): Prune ancient chaindata at the given datadir location.",
 		"- [```snapshot inspect-ancient-db```](./snapshot_inspect-ancient-db.md): Inspect few fields in ancient datastore.",
+		"- [```snapshot migrate-ancient-codec```](./snapshot_migrate-ancient-codec.md): Recompress ancient bodies and receipts with a given codec.",
 	}
 
 	return strings.Join(items, "\n\n")
@@ -60,7 +61,11 @@ func (c *SnapshotCommand) Help() string {
 
   Inspect ancient DB pruning related fields:
 
-    $ bor snapshot inspect-ancient-db`
+    $ bor snapshot inspect-ancient-db
+
+  Recompress the ancient bodies and receipts:
+
+    $ bor snapshot migrate-ancient-codec`
 }
 
 // Synopsis implements the cli.Command interface
@@ -657,3 +662,118 @@ func (c *InspectAncientDbCommand) inspectAncientDb(stack *node.Node, dbHandles i
 
 	return rawdb.AncientInspect(chaindb)
 }
+
+type MigrateAncientCodecCommand struct {
+	*Meta
+
+	datadirAncient string
+	codec          string
+	benchmark      bool
+}
+
+// MarkDown implements cli.MarkDown interface
+func (c *MigrateAncientCodecCommand) MarkDown() string {
+	items := []string{
+		"# Migrate ancient DB compression codec",
+		"The ```bor snapshot migrate-ancient-codec``` command will recompress the ancient bodies and receipts with the given codec using the given datadir location.",
+		`
+Zstd trades CPU time for a considerably smaller disk footprint of the ancient datastore. The node must be stopped while the
+migration is running, and the ` + "```db.freezercodec```" + ` option should be set to the same codec afterwards. With the
+` + "```benchmark```" + ` option, the codecs are only benchmarked on the most recent ancient items, leaving them as they are.
+`,
+		c.Flags().MarkDown(),
+	}
+
+	return strings.Join(items, "\n\n")
+}
+
+// Help implements the cli.Command interface
+func (c *MigrateAncientCodecCommand) Help() string {
+	return `Usage: bor snapshot migrate-ancient-codec <datadir>
+
+  This command will recompress the ancient bodies and receipts with the given codec using the given datadir location` + c.Flags().Help()
+}
+
+// Synopsis implements the cli.Command interface
+func (c *MigrateAncientCodecCommand) Synopsis() string {
+	return "Recompress the ancient bodies and receipts with a given codec"
+}
+
+// Flags: datadir, datadir.ancient, codec, benchmark
+func (c *MigrateAncientCodecCommand) Flags() *flagset.Flagset {
+	flags := c.NewFlagSet("migrate-ancient-codec")
+
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "datadir.ancient",
+		Value:   &c.datadirAncient,
+		Usage:   "Path of the ancient data directory",
+		Default: "",
+	})
+	flags.StringFlag(&flagset.StringFlag{
+		Name:    "codec",
+		Value:   &c.codec,
+		Usage:   "Compression codec to migrate to ('snappy' or 'zstd')",
+		Default: "zstd",
+	})
+	flags.BoolFlag(&flagset.BoolFlag{
+		Name:    "benchmark",
+		Value:   &c.benchmark,
+		Usage:   "Benchmark the codecs on the most recent ancient items instead of migrating",
+		Default: false,
+	})
+
+	return flags
+}
+
+// Run implements the cli.Command interface
+func (c *MigrateAncientCodecCommand) Run(args []string) int {
+	flags := c.Flags()
+
+	if err := flags.Parse(args); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	datadir := c.dataDir
+	if datadir == "" {
+		c.UI.Error("datadir is required")
+		return 1
+	}
+
+	codec, err := rawdb.ParseFreezerCodec(c.codec)
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	// Create the node
+	node, err := node.New(&node.Config{
+		DataDir: datadir,
+	})
+
+	if err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+	defer node.Close()
+
+	ancient := node.ResolveAncient(chaindataPath, c.datadirAncient)
+
+	if c.benchmark {
+		if err := rawdb.InspectChainFreezerCodecs(ancient); err != nil {
+			c.UI.Error(err.Error())
+			return 1
+		}
+
+		return 0
+	}
+
+	if err := rawdb.MigrateChainFreezerCodec(ancient, codec); err != nil {
+		c.UI.Error(err.Error())
+		return 1
+	}
+
+	log.Info("Ancient codec migrated successfully", "codec", codec)
+
+	return 0
+}
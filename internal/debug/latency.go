@@ -0,0 +1,178 @@
+package debug
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// latencyProfileMaxDuration caps the CPU profile taken after a slow block.
+	latencyProfileMaxDuration = 5 * time.Second
+
+	// latencyProfileCooldown is the minimum time between two latency profiles,
+	// so that a node falling behind isn't profiled continuously.
+	latencyProfileCooldown = time.Minute
+)
+
+// LatencyProfile is a CPU and heap profile captured after a block took longer
+// than the configured fraction of its slot time to execute or seal.
+type LatencyProfile struct {
+	ID      uint64        `json:"id"`
+	Kind    string        `json:"kind"` // Operation which was slow, e.g. "execute" or "seal"
+	Number  uint64        `json:"number"`
+	Elapsed time.Duration `json:"elapsed"`
+	Slot    time.Duration `json:"slot"`
+	Time    time.Time     `json:"time"`
+
+	cpu  []byte
+	heap []byte
+}
+
+// latencyProfiler captures profiles when consensus operations exceed a fraction
+// of the slot time, keeping the latest ones in memory.
+type latencyProfiler struct {
+	mu       sync.Mutex
+	fraction float64 // Fraction of the slot time an operation may take, 0 if disabled
+	keep     int     // Number of profiles kept
+
+	profiles  []*LatencyProfile
+	nextID    uint64
+	capturing bool
+	last      time.Time // Time the latest capture started
+}
+
+var latency = new(latencyProfiler)
+
+// ConfigureLatencyProfiles enables capturing profiles when block execution or
+// sealing takes longer than the given fraction of the slot time, keeping the
+// latest keep ones. A fraction of 0 disables the captures.
+func ConfigureLatencyProfiles(fraction float64, keep int) {
+	latency.mu.Lock()
+	defer latency.mu.Unlock()
+
+	latency.fraction = fraction
+	latency.keep = keep
+
+	if len(latency.profiles) > keep {
+		latency.profiles = latency.profiles[len(latency.profiles)-keep:]
+	}
+}
+
+// ReportLatency reports the time a consensus operation on a block took. If it
+// exceeds the configured fraction of the slot time, a heap profile is taken
+// right away and a CPU profile over the following slot, in the background. The
+// CPU profile can't cover the slow operation itself, but transient regressions
+// usually last for a few blocks.
+func ReportLatency(kind string, number uint64, elapsed, slot time.Duration) {
+	latency.report(kind, number, elapsed, slot)
+}
+
+func (p *latencyProfiler) report(kind string, number uint64, elapsed, slot time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.fraction <= 0 || p.keep <= 0 || slot <= 0 || elapsed < time.Duration(p.fraction*float64(slot)) {
+		return
+	}
+
+	if p.capturing || time.Since(p.last) < latencyProfileCooldown {
+		return
+	}
+
+	p.capturing, p.last = true, time.Now()
+	p.nextID++
+
+	profile := &LatencyProfile{
+		ID:      p.nextID,
+		Kind:    kind,
+		Number:  number,
+		Elapsed: elapsed,
+		Slot:    slot,
+		Time:    p.last,
+	}
+
+	log.Warn("Consensus operation exceeded its latency threshold, profiling", "kind", kind, "number", number, "elapsed", elapsed, "slot", slot, "id", profile.ID)
+
+	go p.capture(profile, min(slot, latencyProfileMaxDuration))
+}
+
+// capture takes the profiles and adds them to the kept ones.
+func (p *latencyProfiler) capture(profile *LatencyProfile, duration time.Duration) {
+	var heap bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&heap, 0); err == nil {
+		profile.heap = heap.Bytes()
+	}
+
+	// The CPU profile is skipped if one was started through the API meanwhile
+	var cpu bytes.Buffer
+	if err := pprof.StartCPUProfile(&cpu); err == nil {
+		time.Sleep(duration)
+		pprof.StopCPUProfile()
+
+		profile.cpu = cpu.Bytes()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.capturing = false
+
+	if p.keep <= 0 {
+		return
+	}
+
+	p.profiles = append(p.profiles, profile)
+	if len(p.profiles) > p.keep {
+		p.profiles = p.profiles[len(p.profiles)-p.keep:]
+	}
+}
+
+// LatencyProfiles returns the profiles captured after slow consensus operations,
+// oldest first.
+func (*HandlerT) LatencyProfiles() []*LatencyProfile {
+	latency.mu.Lock()
+	defer latency.mu.Unlock()
+
+	return append([]*LatencyProfile{}, latency.profiles...)
+}
+
+// WriteLatencyProfile writes the "cpu" or "heap" profile captured after a slow
+// consensus operation to file.
+func (*HandlerT) WriteLatencyProfile(id uint64, kind string, file string) error {
+	latency.mu.Lock()
+	defer latency.mu.Unlock()
+
+	for _, profile := range latency.profiles {
+		if profile.ID != id {
+			continue
+		}
+
+		var data []byte
+
+		switch kind {
+		case "cpu":
+			data = profile.cpu
+		case "heap":
+			data = profile.heap
+		default:
+			return fmt.Errorf("unknown profile %q", kind)
+		}
+
+		if len(data) == 0 {
+			return fmt.Errorf("no %s profile captured", kind)
+		}
+
+		log.Info("Writing latency profile", "id", id, "type", kind, "dump", file)
+
+		return os.WriteFile(expandHome(file), data, 0600)
+	}
+
+	return errors.New("latency profile not found")
+}
@@ -0,0 +1,50 @@
+package debug
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyProfiler(t *testing.T) {
+	p := &latencyProfiler{fraction: 0.5, keep: 1}
+
+	// Operations within the threshold aren't profiled
+	p.report("execute", 1, 40*time.Millisecond, 100*time.Millisecond)
+
+	p.mu.Lock()
+	if p.capturing || p.nextID != 0 {
+		t.Fatal("profiled an operation within the threshold")
+	}
+	p.mu.Unlock()
+
+	p.report("execute", 2, 60*time.Millisecond, 100*time.Millisecond)
+
+	// Captures are rate limited
+	p.report("seal", 3, time.Second, 100*time.Millisecond)
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		p.mu.Lock()
+		profiles, capturing := p.profiles, p.capturing
+		p.mu.Unlock()
+
+		if !capturing {
+			if len(profiles) != 1 {
+				t.Fatalf("have %d profiles, want 1", len(profiles))
+			}
+
+			if profile := profiles[0]; profile.Number != 2 || profile.Kind != "execute" || len(profile.heap) == 0 {
+				t.Fatalf("unexpected profile %+v", profile)
+			}
+
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("profile not captured")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
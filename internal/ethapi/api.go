@@ -985,6 +985,7 @@ func (api *BlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.Block
 		// append marshalled bor transaction
 		if err == nil && response != nil {
 			response = api.appendRPCMarshalBorTransaction(ctx, block, response, fullTx)
+			response = api.appendRPCBorMetadata(ctx, block, response)
 		}
 
 		return response, err
@@ -1001,7 +1002,8 @@ func (api *BlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash,
 		response, err := api.rpcMarshalBlock(ctx, block, true, fullTx)
 		// append marshalled bor transaction
 		if err == nil && response != nil {
-			return api.appendRPCMarshalBorTransaction(ctx, block, response, fullTx), err
+			response = api.appendRPCMarshalBorTransaction(ctx, block, response, fullTx)
+			return api.appendRPCBorMetadata(ctx, block, response), err
 		}
 
 		return response, err
@@ -1272,6 +1274,16 @@ func (context *ChainContext) GetHeader(hash common.Hash, number uint64) *types.H
 	return header
 }
 
+// FeeCurrency returns the fee currency of the chain if the backend serves one,
+// so that calls and traces pay their fees like the transactions of the chain.
+func (context *ChainContext) FeeCurrency() vm.FeeCurrency {
+	if b, ok := context.b.(interface{ FeeCurrency() vm.FeeCurrency }); ok {
+		return b.FeeCurrency()
+	}
+
+	return nil
+}
+
 func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *types.Header, overrides *StateOverride, blockOverrides *BlockOverrides, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
 	if err := overrides.Apply(state); err != nil {
 		return nil, err
@@ -425,11 +425,12 @@ func newTestAccountManager(t *testing.T) (*accounts.Manager, accounts.Account) {
 }
 
 type testBackend struct {
-	db      ethdb.Database
-	chain   *core.BlockChain
-	pending *types.Block
-	accman  *accounts.Manager
-	acc     accounts.Account
+	db          ethdb.Database
+	chain       *core.BlockChain
+	pending     *types.Block
+	accman      *accounts.Manager
+	acc         accounts.Account
+	borMetadata bool
 }
 
 func newTestBackend(t *testing.T, n int, gspec *core.Genesis, engine consensus.Engine, generator func(i int, b *core.BlockGen)) *testBackend {
@@ -660,6 +661,15 @@ func (b testBackend) PurgeWhitelistedMilestone() {
 	panic("implement me")
 }
 
+func (b testBackend) BorMetadataEnabled() bool {
+	return b.borMetadata
+}
+
+func (b testBackend) GetBorBlockMetadata(ctx context.Context, header *types.Header) (*RPCBorMetadata, error) {
+	signer, inTurn := header.Coinbase, true
+	return &RPCBorMetadata{Signer: &signer, InTurn: &inTurn, Sprint: hexutil.Uint64(header.Number.Uint64() / 16)}, nil
+}
+
 func (b testBackend) PurgeWhitelistedCheckpoint() {
 	panic("implement me")
 }
@@ -676,6 +686,10 @@ func (b testBackend) SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) even
 	panic("implement me")
 }
 
+func (b testBackend) SubscribeStateSyncCommitEvent(ch chan<- core.StateSyncCommitEvent) event.Subscription {
+	panic("implement me")
+}
+
 func (b testBackend) PeerStats() interface{} {
 	panic("implement me")
 }
@@ -2114,6 +2128,33 @@ func TestRPCGetBlockTransactionCountByHash(t *testing.T) {
 	require.Equal(t, expected, *cnt)
 }
 
+func TestRPCGetBlockBorMetadata(t *testing.T) {
+	t.Parallel()
+
+	var (
+		genesis = &core.Genesis{Config: params.TestChainConfig, Alloc: types.GenesisAlloc{}}
+		backend = newTestBackend(t, 2, genesis, beacon.New(ethash.NewFaker()), nil)
+		api     = NewBlockChainAPI(backend)
+		head    = backend.CurrentBlock()
+	)
+
+	// Responses stay spec compatible by default
+	block, err := api.GetBlockByNumber(context.Background(), rpc.LatestBlockNumber, false)
+	require.NoError(t, err)
+	require.NotContains(t, block, "borMetadata")
+
+	backend.borMetadata = true
+
+	block, err = api.GetBlockByNumber(context.Background(), rpc.LatestBlockNumber, false)
+	require.NoError(t, err)
+	inTurn := true
+	require.Equal(t, &RPCBorMetadata{Signer: &head.Coinbase, InTurn: &inTurn}, block["borMetadata"])
+
+	block, err = api.GetBlockByHash(context.Background(), head.Hash(), true)
+	require.NoError(t, err)
+	require.Contains(t, block, "borMetadata")
+}
+
 func TestRPCGetTransactionByBlockHashAndIndex(t *testing.T) {
 	var (
 		api, _, _ = setupTransactionsToApiTest(t)
@@ -2162,6 +2203,27 @@ func TestRPCGetTransactionReceiptsByBlock(t *testing.T) {
 	}
 }
 
+func TestRPCGetBorTransactionReceiptsByBlock(t *testing.T) {
+	api, blockNrOrHash, testSuite := setupBlocksToApiTest(t)
+
+	receipts, err := NewBorAPI(api.b).GetTransactionReceiptsByBlock(context.Background(), blockNrOrHash)
+	if err != nil {
+		t.Fatal("api error")
+	}
+
+	require.Len(t, receipts, len(testSuite))
+
+	for i, tt := range testSuite {
+		data, err := json.Marshal(receipts[i])
+		if err != nil {
+			t.Errorf("test %d: json marshal error", i)
+			continue
+		}
+		want, have := tt.want, string(data)
+		require.JSONEqf(t, want, have, "test %d: json not match, want: %s, have: %s", i, want, have)
+	}
+}
+
 func TestRPCGetBlockReceipts(t *testing.T) {
 	api, blockNrOrHash, testSuite := setupBlocksToApiTest(t)
 
@@ -102,6 +102,7 @@ type Backend interface {
 
 	// Bor related APIs
 	SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) event.Subscription
+	SubscribeStateSyncCommitEvent(ch chan<- core.StateSyncCommitEvent) event.Subscription
 	GetRootHash(ctx context.Context, starBlockNr uint64, endBlockNr uint64) (string, error)
 	GetVoteOnHash(ctx context.Context, startBlockNumber uint64, endBlockNumber uint64, hash string, milestoneID string) (bool, error)
 	GetBorBlockReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
@@ -113,6 +114,8 @@ type Backend interface {
 	PurgeWhitelistedCheckpoint()
 	GetWhitelistedMilestone() (bool, uint64, common.Hash)
 	PurgeWhitelistedMilestone()
+	BorMetadataEnabled() bool // adds the bor metadata of blocks to the block responses
+	GetBorBlockMetadata(ctx context.Context, header *types.Header) (*RPCBorMetadata, error)
 
 	// Networking related APIs
 	PeerStats() interface{}
@@ -2,10 +2,15 @@ package ethapi
 
 import (
 	"context"
+	"errors"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -55,6 +60,38 @@ func (s *BlockChainAPI) appendRPCMarshalBorTransaction(ctx context.Context, bloc
 	return fields
 }
 
+// RPCBorMetadata is the Bor specific context of a block, added to the block
+// responses as borMetadata if enabled, so that indexers don't need to query it
+// separately. The signer is nil if the block isn't sealed, whether it was sealed
+// in turn and its span are left out if they aren't known locally.
+type RPCBorMetadata struct {
+	Signer            *common.Address `json:"signer"`
+	InTurn            *bool           `json:"inTurn,omitempty"`
+	Sprint            hexutil.Uint64  `json:"sprint"`
+	SprintStart       hexutil.Uint64  `json:"sprintStart"`
+	SprintEnd         hexutil.Uint64  `json:"sprintEnd"`
+	Span              *hexutil.Uint64 `json:"span,omitempty"`
+	ContainsStateSync bool            `json:"containsStateSync"`
+}
+
+// appendRPCBorMetadata adds the Bor specific context of the block to a block
+// response, if enabled. Responses stay spec compatible by default.
+func (s *BlockChainAPI) appendRPCBorMetadata(ctx context.Context, block *types.Block, fields map[string]interface{}) map[string]interface{} {
+	if block == nil || !s.b.BorMetadataEnabled() {
+		return fields
+	}
+
+	metadata, err := s.b.GetBorBlockMetadata(ctx, block.Header())
+	if err != nil {
+		log.Debug("Failed to retrieve bor metadata of block", "number", block.Number(), "hash", block.Hash(), "err", err)
+		return fields
+	}
+
+	fields["borMetadata"] = metadata
+
+	return fields
+}
+
 // BorAPI provides an API to access Bor related information.
 type BorAPI struct {
 	b Backend
@@ -106,6 +143,127 @@ func (api *BorAPI) SendRawTransactionConditional(ctx context.Context, input hexu
 	return SubmitTransaction(ctx, api.b, tx)
 }
 
+// GetTransactionReceiptsByBlock returns the transaction receipts of the given
+// block, including the bor receipt of the state-sync transactions committed at
+// a sprint-start block.
+func (api *BorAPI) GetTransactionReceiptsByBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	return NewBlockChainAPI(api.b).GetTransactionReceiptsByBlock(ctx, blockNrOrHash)
+}
+
 func (api *BorAPI) GetVoteOnHash(ctx context.Context, starBlockNr uint64, endBlockNr uint64, hash string, milestoneId string) (bool, error) {
 	return api.b.GetVoteOnHash(ctx, starBlockNr, endBlockNr, hash, milestoneId)
 }
+
+// errStateSyncNotIndexed is returned if the state-sync events of a sprint-start
+// block are requested, but the block was not processed by the indexer.
+var errStateSyncNotIndexed = errors.New("state-sync events of the block are not indexed")
+
+// RPCStateSyncEvent represents a state-sync event committed at a sprint-start block
+type RPCStateSyncEvent struct {
+	ID          hexutil.Uint64 `json:"id"`
+	Contract    common.Address `json:"contract"`
+	Data        hexutil.Bytes  `json:"data"`
+	TxHash      common.Hash    `json:"txHash"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+}
+
+func newRPCStateSyncEvent(event *types.StateSyncData, blockHash common.Hash, blockNumber uint64) *RPCStateSyncEvent {
+	return &RPCStateSyncEvent{
+		ID:          hexutil.Uint64(event.ID),
+		Contract:    event.Contract,
+		Data:        common.FromHex(event.Data),
+		TxHash:      event.TxHash,
+		BlockHash:   blockHash,
+		BlockNumber: hexutil.Uint64(blockNumber),
+	}
+}
+
+// GetStateSyncEventsByBlock returns the state-sync events committed in the given block.
+func (api *BorAPI) GetStateSyncEventsByBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*RPCStateSyncEvent, error) {
+	header, err := api.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+
+	number := header.Number.Uint64()
+
+	// State-sync events are only committed at sprint-start blocks
+	if borConfig := api.b.ChainConfig().Bor; number == 0 || borConfig == nil || borConfig.Sprint == nil || !borConfig.IsSprintStart(number) {
+		return []*RPCStateSyncEvent{}, nil
+	}
+
+	// Blocks which were not executed locally (e.g. snap synced) are not indexed
+	if !rawdb.HasBorStateSyncEvents(api.b.ChainDb(), header.Hash(), number) {
+		return nil, errStateSyncNotIndexed
+	}
+
+	events := rawdb.ReadBorStateSyncEvents(api.b.ChainDb(), header.Hash(), number)
+	result := make([]*RPCStateSyncEvent, 0, len(events))
+
+	for _, event := range events {
+		result = append(result, newRPCStateSyncEvent(event, header.Hash(), number))
+	}
+
+	return result, nil
+}
+
+// GetStateSyncEventById returns the state-sync event with the given state id, if
+// it was committed on the canonical chain.
+func (api *BorAPI) GetStateSyncEventById(ctx context.Context, id uint64) (*RPCStateSyncEvent, error) {
+	event, blockHash, blockNumber := rawdb.ReadBorStateSyncEvent(api.b.ChainDb(), id)
+	if event == nil {
+		return nil, nil
+	}
+
+	return newRPCStateSyncEvent(event, blockHash, blockNumber), nil
+}
+
+// BorCaller executes the contract calls of the bor consensus engine against
+// the local chain. It adapts BlockChainAPI to the engine's api.Caller
+// interface, which doesn't depend on the RPC types of this package.
+type BorCaller struct {
+	api *BlockChainAPI
+}
+
+// NewBorCaller creates a contract caller for the bor consensus engine.
+func NewBorCaller(api *BlockChainAPI) *BorCaller {
+	return &BorCaller{api: api}
+}
+
+// Call executes the given message on top of the given block.
+func (c *BorCaller) Call(ctx context.Context, msg ethereum.CallMsg, blockNrOrHash *rpc.BlockNumberOrHash) ([]byte, error) {
+	return c.api.Call(ctx, borCallArgs(msg), blockNrOrHash, nil, nil)
+}
+
+// CallWithState executes the given message on the given state, falling back
+// to the state of the given block if it's nil.
+func (c *BorCaller) CallWithState(ctx context.Context, msg ethereum.CallMsg, blockNrOrHash *rpc.BlockNumberOrHash, state *state.StateDB) ([]byte, error) {
+	return c.api.CallWithState(ctx, borCallArgs(msg), blockNrOrHash, state, nil, nil)
+}
+
+// borCallArgs converts a call message into transaction arguments.
+func borCallArgs(msg ethereum.CallMsg) TransactionArgs {
+	gas := hexutil.Uint64(msg.Gas)
+	data := hexutil.Bytes(msg.Data)
+
+	args := TransactionArgs{
+		To:   msg.To,
+		Gas:  &gas,
+		Data: &data,
+	}
+
+	if msg.From != (common.Address{}) {
+		args.From = &msg.From
+	}
+
+	if msg.GasPrice != nil {
+		args.GasPrice = (*hexutil.Big)(msg.GasPrice)
+	}
+
+	if msg.Value != nil {
+		args.Value = (*hexutil.Big)(msg.Value)
+	}
+
+	return args
+}
@@ -429,6 +429,10 @@ func (b *backendMock) SubscribeStateSyncEvent(ch chan<- core.StateSyncEvent) eve
 	return nil
 }
 
+func (b *backendMock) SubscribeStateSyncCommitEvent(ch chan<- core.StateSyncCommitEvent) event.Subscription {
+	return nil
+}
+
 func (b *backendMock) GetRootHash(ctx context.Context, starBlockNr uint64, endBlockNr uint64) (string, error) {
 	return "", nil
 }
@@ -471,6 +475,12 @@ func (b *backendMock) PurgeWhitelistedCheckpoint() {}
 
 func (b *backendMock) PurgeWhitelistedMilestone() {}
 
+func (b *backendMock) BorMetadataEnabled() bool { return false }
+
+func (b *backendMock) GetBorBlockMetadata(ctx context.Context, header *types.Header) (*RPCBorMetadata, error) {
+	return nil, nil
+}
+
 func (b backendMock) PeerStats() interface{} {
 	return nil
 }
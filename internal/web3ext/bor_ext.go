@@ -9,25 +9,25 @@ web3._extend({
 			name: 'getSnapshot',
 			call: 'bor_getSnapshot',
 			params: 1,
-			inputFormatter: [null]
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
 		new web3._extend.Method({
 			name: 'getAuthor',
 			call: 'bor_getAuthor',
 			params: 1,
-			inputFormatter: [null]
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
 		new web3._extend.Method({
 			name: 'getSnapshotProposer',
 			call: 'bor_getSnapshotProposer',
 			params: 1,
-			inputFormatter: [null]
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
 		new web3._extend.Method({
 			name: 'getSnapshotProposerSequence',
 			call: 'bor_getSnapshotProposerSequence',
 			params: 1,
-			inputFormatter: [null]
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
 		new web3._extend.Method({
 			name: 'getSnapshotAtHash',
@@ -38,7 +38,7 @@ web3._extend({
 			name: 'getSigners',
 			call: 'bor_getSigners',
 			params: 1,
-			inputFormatter: [null]
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
 		new web3._extend.Method({
 			name: 'getSignersAtHash',
@@ -55,6 +55,91 @@ web3._extend({
 			call: 'bor_getCurrentValidators',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'getSignerMetrics',
+			call: 'bor_getSignerMetrics',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getValidatorsAtBlock',
+			call: 'bor_getValidatorsAtBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'status',
+			call: 'bor_status',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getProducerSlots',
+			call: 'bor_getProducerSlots',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getSprintByBlock',
+			call: 'bor_getSprintByBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'blockAtTime',
+			call: 'bor_blockAtTime',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'validatorsAtTime',
+			call: 'bor_validatorsAtTime',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getSpanById',
+			call: 'bor_getSpanById',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getDoubleSignEvidence',
+			call: 'bor_getDoubleSignEvidence',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'getNonCanonicalHeaders',
+			call: 'bor_getNonCanonicalHeaders',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'exportForkIncident',
+			call: 'bor_exportForkIncident',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'purgeSnapshot',
+			call: 'bor_purgeSnapshot',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'checkNextSpan',
+			call: 'bor_checkNextSpan',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'rebuildSnapshot',
+			call: 'bor_rebuildSnapshot',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRecents',
+			call: 'bor_getRecents',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getProofBundle',
+			call: 'bor_getProofBundle',
+			params: 2
+		}),
 		new web3._extend.Method({
 			name: 'getRootHash',
 			call: 'bor_getRootHash',
@@ -65,6 +150,23 @@ web3._extend({
 			call: 'bor_getVoteOnHash',
 			params: 4,
 		}),
+		new web3._extend.Method({
+			name: 'getStateSyncEventsByBlock',
+			call: 'bor_getStateSyncEventsByBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getStateSyncEventById',
+			call: 'bor_getStateSyncEventById',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionReceiptsByBlock',
+			call: 'bor_getTransactionReceiptsByBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
 		new web3._extend.Method({
 			name: 'sendRawTransactionConditional',
 			call: 'bor_sendRawTransactionConditional',
@@ -73,4 +175,76 @@ web3._extend({
 		}),
 	]
 });
+
+// Helpers rendering the consensus state as tables, for inspection during incidents
+(function() {
+	// table renders the rows under the given columns, right-aligning numbers.
+	function table(columns, rows) {
+		var right = columns.map(function(column, i) {
+			return rows.length > 0 && /^-?[0-9.]+%?$/.test(String(rows[0][i]));
+		});
+		var widths = columns.map(function(column) { return column.length; });
+		rows.forEach(function(row) {
+			row.forEach(function(cell, i) { widths[i] = Math.max(widths[i], String(cell).length); });
+		});
+		var line = function(cells) {
+			return cells.map(function(cell, i) {
+				var text = String(cell);
+				var fill = new Array(widths[i] - text.length + 1).join(' ');
+				return right[i] ? fill + text : text + fill;
+			}).join('  ').replace(/\s+$/, '');
+		};
+		var lines = [line(columns), line(widths.map(function(width) { return new Array(width + 1).join('-'); }))];
+		rows.forEach(function(row) { lines.push(line(row)); });
+		return lines.join('\n');
+	}
+
+	// validators renders a validator set, marking its proposer if any.
+	function validators(set, proposer) {
+		var total = set.reduce(function(sum, validator) { return sum + validator.power; }, 0);
+		var rows = set.map(function(validator, i) {
+			var share = total > 0 ? (100 * validator.power / total).toFixed(2) : '0.00';
+			var proposing = proposer && proposer.signer.toLowerCase() == validator.signer.toLowerCase();
+			return [i, validator.ID, validator.signer, validator.power, share + '%', validator.accum, proposing ? '*' : ''];
+		});
+		return table(['#', 'ID', 'SIGNER', 'POWER', 'SHARE', 'PRIORITY', 'PROPOSER'], rows) + '\nTotal voting power: ' + total;
+	}
+
+	// block defaults the block argument of the helpers to the chain head.
+	function block(number) {
+		return number === undefined ? 'latest' : number;
+	}
+
+	web3.bor.printValidators = function(number) {
+		var snap = web3.bor.getSnapshot(block(number));
+		console.log('Validators at block ' + snap.number + ' (' + snap.hash + ')\n');
+		console.log(validators(snap.validatorSet.validators, snap.validatorSet.proposer));
+	};
+
+	web3.bor.printSnapshot = function(number) {
+		var snap = web3.bor.getSnapshot(block(number));
+		console.log('Snapshot of block ' + snap.number + ' (' + snap.hash + ')\n');
+		console.log(validators(snap.validatorSet.validators, snap.validatorSet.proposer) + '\n');
+
+		var recents = Object.keys(snap.recents || {}).map(Number).sort(function(a, b) { return b - a; });
+		console.log(table(['RECENT BLOCK', 'SIGNER'], recents.map(function(recent) {
+			return [recent, snap.recents[recent]];
+		})));
+	};
+
+	web3.bor.printProposerSequence = function(number) {
+		var sequence = web3.bor.getSnapshotProposerSequence(block(number));
+		console.log(table(['#', 'SIGNER', 'DIFFICULTY', 'AUTHOR'], sequence.Signers.map(function(signer, i) {
+			return [i, signer.Signer, signer.Difficulty, signer.Signer.toLowerCase() == sequence.Author.toLowerCase() ? '*' : ''];
+		})));
+		console.log('\nBlock author ' + sequence.Author + ' at difficulty ' + sequence.Diff);
+	};
+
+	web3.bor.printSprint = function(number) {
+		var sprint = web3.bor.getSprintByBlock(block(number));
+		console.log('Sprint ' + sprint.number + ': blocks ' + sprint.startBlock + '-' + sprint.endBlock + ' (length ' + sprint.length + ')');
+		console.log('Span ' + sprint.spanId + ': blocks ' + sprint.spanStartBlock + '-' + sprint.spanEndBlock + '\n');
+		console.log(validators(sprint.producers, null));
+	};
+})();
 `
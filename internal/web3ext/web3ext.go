@@ -156,6 +156,15 @@ web3._extend({
 			call: 'admin_importChain',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getWhitelist',
+			call: 'admin_getWhitelist'
+		}),
+		new web3._extend.Method({
+			name: 'allowDeepReorg',
+			call: 'admin_allowDeepReorg',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'sleepBlocks',
 			call: 'admin_sleepBlocks',
@@ -268,6 +277,16 @@ web3._extend({
 			call: 'debug_getRawHeader',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'setBorTrace',
+			call: 'debug_setBorTrace',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getBorTrace',
+			call: 'debug_getBorTrace'
+		}),
 		new web3._extend.Method({
 			name: 'getRawBlock',
 			call: 'debug_getRawBlock',
@@ -415,6 +434,16 @@ web3._extend({
 			call: 'debug_writeMemProfile',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'latencyProfiles',
+			call: 'debug_latencyProfiles',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'writeLatencyProfile',
+			call: 'debug_writeLatencyProfile',
+			params: 3
+		}),
 		new web3._extend.Method({
 			name: 'traceBlock',
 			call: 'debug_traceBlock',
@@ -475,6 +504,17 @@ web3._extend({
 			params: 3,
 			inputFormatter: [null, null, null]
 		}),
+		new web3._extend.Method({
+			name: 'requestStateRegeneration',
+			call: 'debug_requestStateRegeneration',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'stateRegenerationStatus',
+			call: 'debug_stateRegenerationStatus',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'preimage',
 			call: 'debug_preimage',
@@ -0,0 +1,106 @@
+package miner
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	carryoverQueuedMeter  = metrics.NewRegisteredMeter("worker/carryover/queued", nil)
+	carryoverRetriedMeter = metrics.NewRegisteredMeter("worker/carryover/retried", nil)
+	carryoverStaleMeter   = metrics.NewRegisteredMeter("worker/carryover/stale", nil)
+	carryoverSizeGauge    = metrics.NewRegisteredGauge("worker/carryover/size", nil)
+)
+
+// carriedTx is a transaction left out of a block by a commit interrupt.
+type carriedTx struct {
+	from common.Address
+	hash common.Hash
+}
+
+// txCarryover queues the transactions a commit interrupt left out of a block, so
+// that the next block building round retries their accounts first, in the order
+// they would have been included in, instead of sorting them among all others.
+// A nil carry-over is disabled.
+type txCarryover struct {
+	limit int         // Maximum number of accounts carried over
+	queue []carriedTx // Transactions left out by the last interrupted round
+	lock  sync.Mutex
+}
+
+// newTxCarryover creates a carry-over queue of up to limit accounts, or nil if
+// the limit is zero.
+func newTxCarryover(limit int) *txCarryover {
+	if limit <= 0 {
+		return nil
+	}
+	return &txCarryover{limit: limit}
+}
+
+// record replaces the queue with the transactions left out of an interrupted
+// round: the one being executed when the interrupt hit, if any, followed by the
+// best remaining ones of each set. The sets are drained in the process.
+func (c *txCarryover) record(interrupted *carriedTx, sets ...*transactionsByPriceAndNonce) {
+	if c == nil {
+		return
+	}
+	queue := make([]carriedTx, 0, c.limit)
+	if interrupted != nil {
+		queue = append(queue, *interrupted)
+	}
+	for _, set := range sets {
+		for len(queue) < c.limit && !set.Empty() {
+			queue = append(queue, carriedTx{from: set.heads[0].from, hash: set.heads[0].tx.Hash})
+			set.Pop()
+		}
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queue = queue
+
+	carryoverQueuedMeter.Mark(int64(len(queue)))
+	carryoverSizeGauge.Update(int64(len(queue)))
+}
+
+// take empties the queue, returning the ranks of the carried over accounts whose
+// next pending transaction is still the one left out. Accounts whose transaction
+// was included or replaced meanwhile are dropped as stale.
+func (c *txCarryover) take(pending ...map[common.Address][]*txpool.LazyTransaction) map[common.Address]int {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	queue := c.queue
+	c.queue = nil
+	c.lock.Unlock()
+
+	if len(queue) == 0 {
+		return nil
+	}
+	carryoverSizeGauge.Update(0)
+
+	ranks := make(map[common.Address]int, len(queue))
+	for _, carried := range queue {
+		if _, ok := ranks[carried.from]; !ok && isNextPending(carried, pending) {
+			ranks[carried.from] = len(ranks) + 1
+			continue
+		}
+		carryoverStaleMeter.Mark(1)
+	}
+	return ranks
+}
+
+// isNextPending returns whether the carried transaction is the next pending one
+// of its account.
+func isNextPending(carried carriedTx, pending []map[common.Address][]*txpool.LazyTransaction) bool {
+	for _, txs := range pending {
+		if next := txs[carried.from]; len(next) > 0 && next[0].Hash == carried.hash {
+			return true
+		}
+	}
+	return false
+}
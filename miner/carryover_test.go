@@ -0,0 +1,154 @@
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// carryoverTxs creates two transactions for each of the given accounts, priced
+// after the position of the account.
+func carryoverTxs(t *testing.T, signer types.Signer, keys []*ecdsa.PrivateKey) map[common.Address][]*txpool.LazyTransaction {
+	t.Helper()
+
+	groups := make(map[common.Address][]*txpool.LazyTransaction, len(keys))
+
+	for i, key := range keys {
+		addr := crypto.PubkeyToAddress(key.PublicKey)
+
+		for nonce := uint64(0); nonce < 2; nonce++ {
+			tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{}, big.NewInt(100), 100, big.NewInt(int64(i+1)), nil), signer, key)
+			require.NoError(t, err)
+
+			groups[addr] = append(groups[addr], &txpool.LazyTransaction{
+				Hash:      tx.Hash(),
+				Tx:        tx,
+				Time:      tx.Time(),
+				GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+				GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+				Gas:       tx.Gas(),
+			})
+		}
+	}
+
+	return groups
+}
+
+// txSender returns the sender of a transaction.
+func txSender(t *testing.T, signer types.Signer, tx *txpool.LazyTransaction) common.Address {
+	t.Helper()
+
+	from, err := types.Sender(signer, tx.Tx)
+	require.NoError(t, err)
+
+	return from
+}
+
+// Tests that the accounts left out of an interrupted round are retried first in
+// the next one, in the order they were left out in.
+func TestCarryoverOrdering(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+
+	var (
+		signer    = types.HomesteadSigner{}
+		carryover = newTxCarryover(3)
+		addrs     = make([]common.Address, len(keys))
+	)
+
+	for i, key := range keys {
+		addrs[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+
+	// Interrupt a round while executing the cheapest account
+	pending := carryoverTxs(t, signer, keys)
+	interrupted := &carriedTx{from: addrs[0], hash: pending[addrs[0]][0].Hash}
+
+	carryover.record(interrupted, newTransactionsByPriceAndNonce(signer, pending, nil))
+
+	// The next round starts with the interrupted account, followed by the best
+	// ones left out, and only then sorts the rest by price
+	pending = carryoverTxs(t, signer, keys)
+
+	ranks := carryover.take(pending)
+	require.Equal(t, map[common.Address]int{addrs[0]: 1, addrs[4]: 2, addrs[3]: 3}, ranks)
+
+	txset := newTransactionsByPriceAndNonce(signer, pending, nil)
+	txset.Prioritize(ranks)
+
+	var order []common.Address
+
+	for tx, _ := txset.Peek(); tx != nil; tx, _ = txset.Peek() {
+		from := txSender(t, signer, tx)
+		require.Equal(t, ranks[from] != 0, txset.Carried())
+
+		order = append(order, from)
+		txset.Shift()
+	}
+
+	require.Equal(t, []common.Address{
+		addrs[0], addrs[0], addrs[4], addrs[4], addrs[3], addrs[3], addrs[2], addrs[2], addrs[1], addrs[1],
+	}, order)
+
+	// The queue is emptied by taking it
+	require.Nil(t, carryover.take(pending))
+}
+
+// Tests that carried over accounts whose transaction was included or replaced
+// meanwhile aren't prioritized.
+func TestCarryoverStale(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ecdsa.PrivateKey, 3)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+
+	var (
+		signer    = types.HomesteadSigner{}
+		carryover = newTxCarryover(len(keys))
+	)
+
+	pending := carryoverTxs(t, signer, keys)
+	carryover.record(nil, newTransactionsByPriceAndNonce(signer, pending, nil))
+
+	// The first transaction of the best account made it into a block
+	pending = carryoverTxs(t, signer, keys)
+
+	best := crypto.PubkeyToAddress(keys[2].PublicKey)
+	pending[best] = pending[best][1:]
+
+	ranks := carryover.take(pending)
+	require.Len(t, ranks, 2)
+	require.NotContains(t, ranks, best)
+}
+
+// Tests that a disabled carry-over never prioritizes anything.
+func TestCarryoverDisabled(t *testing.T) {
+	t.Parallel()
+
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := range keys {
+		keys[i], _ = crypto.GenerateKey()
+	}
+
+	carryover := newTxCarryover(0)
+	require.Nil(t, carryover)
+
+	pending := carryoverTxs(t, types.HomesteadSigner{}, keys)
+	carryover.record(nil, newTransactionsByPriceAndNonce(types.HomesteadSigner{}, pending, nil))
+
+	require.Nil(t, carryover.take(carryoverTxs(t, types.HomesteadSigner{}, keys)))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
@@ -52,8 +53,14 @@ type Config struct {
 	GasPrice            *big.Int       // Minimum gas price for mining a transaction
 	Recommit            time.Duration  // The time interval for miner to re-create mining work.
 	CommitInterruptFlag bool           // Interrupt commit when time is up ( default = true)
+	InterruptCarryover  int            // Number of accounts left out by an interrupted commit to retry first in the next one (0 = disabled)
 
 	NewPayloadTimeout time.Duration // The maximum time allowance for creating a new payload
+
+	PolicyEndpoint string        `toml:",omitempty"` // JSON-RPC endpoint of the policy engine reviewing the candidate blocks (empty = none)
+	PolicyTimeout  time.Duration // The maximum time allowance for the review of a candidate block
+
+	WatchdogSlots uint64 // Number of block slots the sealing loop may not attempt work for before it's restarted (0 = disabled)
 }
 
 // DefaultConfig contains default settings for miner.
@@ -67,6 +74,7 @@ var DefaultConfig = Config{
 	// run 3 rounds.
 	Recommit:          2 * time.Second,
 	NewPayloadTimeout: 2 * time.Second,
+	PolicyTimeout:     200 * time.Millisecond,
 }
 
 // Miner creates blocks and searches for proof-of-work values.
@@ -75,23 +83,38 @@ type Miner struct {
 	mux     *event.TypeMux
 	eth     Backend
 	engine  consensus.Engine
+	config  *Config
 	exitCh  chan struct{}
 	startCh chan struct{}
 	stopCh  chan chan struct{}
 	worker  *worker
+	alerts  *alert.Client // Webhook client the watchdog restarts are reported to, nil if disabled
+
+	// Settings applied to the worker, carried over to the new one if the
+	// watchdog restarts it
+	etherbase common.Address
+	extra     []byte
+	tip       *big.Int
+	recommit  time.Duration
 
-	wg sync.WaitGroup
+	lock sync.RWMutex // Protects the worker and its settings above
+	wg   sync.WaitGroup
 }
 
 func New(eth Backend, config *Config, chainConfig *params.ChainConfig, mux *event.TypeMux, engine consensus.Engine, isLocalBlock func(header *types.Header) bool) *Miner {
 	miner := &Miner{
-		mux:     mux,
-		eth:     eth,
-		engine:  engine,
-		exitCh:  make(chan struct{}),
-		stopCh:  make(chan chan struct{}),
-		startCh: make(chan struct{}),
-		worker:  newWorker(config, chainConfig, engine, eth, mux, isLocalBlock, true),
+		mux:       mux,
+		eth:       eth,
+		engine:    engine,
+		config:    config,
+		exitCh:    make(chan struct{}),
+		stopCh:    make(chan chan struct{}),
+		startCh:   make(chan struct{}),
+		worker:    newWorker(config, chainConfig, engine, eth, mux, isLocalBlock, true),
+		etherbase: config.Etherbase,
+		extra:     config.ExtraData,
+		tip:       config.GasPrice,
+		recommit:  config.Recommit,
 	}
 	miner.wg.Add(1)
 
@@ -101,9 +124,26 @@ func New(eth Backend, config *Config, chainConfig *params.ChainConfig, mux *even
 }
 
 func (miner *Miner) GetWorker() *worker {
+	return miner.currentWorker()
+}
+
+// currentWorker returns the worker sealing blocks, which the watchdog may
+// replace at any time.
+func (miner *Miner) currentWorker() *worker {
+	miner.lock.RLock()
+	defer miner.lock.RUnlock()
+
 	return miner.worker
 }
 
+// SetAlertClient sets the webhook client the watchdog restarts are reported to.
+func (miner *Miner) SetAlertClient(alerts *alert.Client) {
+	miner.lock.Lock()
+	defer miner.lock.Unlock()
+
+	miner.alerts = alerts
+}
+
 // update keeps track of the downloader events. Please be aware that this is a one shot type of update loop.
 // It's entered once and as soon as `Done` or `Failed` has been broadcasted the events are unregistered and
 // the loop is exited. This to prevent a major security vuln where external parties can DOS you with blocks
@@ -122,6 +162,16 @@ func (miner *Miner) update() {
 	canStart := true
 	dlEventCh := events.Chan()
 
+	// The watchdog only runs if enabled
+	var watchdog <-chan time.Time
+
+	if miner.config.WatchdogSlots > 0 {
+		ticker := time.NewTicker(watchdogInterval)
+		defer ticker.Stop()
+
+		watchdog = ticker.C
+	}
+
 	for {
 		select {
 		case ev := <-dlEventCh:
@@ -176,6 +226,10 @@ func (miner *Miner) update() {
 
 			miner.worker.stop()
 			close(ch)
+		case <-watchdog:
+			if idle, stalled := miner.worker.stalled(time.Now(), miner.config.WatchdogSlots); stalled {
+				miner.restartWorker(idle)
+			}
 		case <-miner.exitCh:
 			miner.worker.close()
 			return
@@ -197,7 +251,7 @@ func (miner *Miner) Close() {
 }
 
 func (miner *Miner) Mining() bool {
-	return miner.worker.IsRunning()
+	return miner.currentWorker().IsRunning()
 }
 
 func (miner *Miner) Hashrate() uint64 {
@@ -213,25 +267,38 @@ func (miner *Miner) SetExtra(extra []byte) error {
 		return fmt.Errorf("extra exceeds max length. %d > %v", len(extra), params.MaximumExtraDataSize)
 	}
 
-	miner.worker.setExtra(extra)
+	miner.lock.Lock()
+	miner.extra = extra
+	miner.lock.Unlock()
+
+	miner.currentWorker().setExtra(extra)
 
 	return nil
 }
 
 func (miner *Miner) SetGasTip(tip *big.Int) error {
-	miner.worker.setGasTip(tip)
+	miner.lock.Lock()
+	miner.tip = tip
+	miner.lock.Unlock()
+
+	miner.currentWorker().setGasTip(tip)
+
 	return nil
 }
 
 // SetRecommitInterval sets the interval for sealing work resubmitting.
 func (miner *Miner) SetRecommitInterval(interval time.Duration) {
-	miner.worker.setRecommitInterval(interval)
+	miner.lock.Lock()
+	miner.recommit = interval
+	miner.lock.Unlock()
+
+	miner.currentWorker().setRecommitInterval(interval)
 }
 
 // Pending returns the currently pending block and associated state. The returned
 // values can be nil in case the pending block is not initialized
 func (miner *Miner) Pending() (*types.Block, types.Receipts, *state.StateDB) {
-	return miner.worker.pending()
+	return miner.currentWorker().pending()
 }
 
 // PendingBlock returns the currently pending block. The returned block can be
@@ -241,26 +308,30 @@ func (miner *Miner) Pending() (*types.Block, types.Receipts, *state.StateDB) {
 // simultaneously, please use Pending(), as the pending state can
 // change between multiple method calls
 func (miner *Miner) PendingBlock() *types.Block {
-	return miner.worker.pendingBlock()
+	return miner.currentWorker().pendingBlock()
 }
 
 func (miner *Miner) SetEtherbase(addr common.Address) {
-	miner.worker.setEtherbase(addr)
+	miner.lock.Lock()
+	miner.etherbase = addr
+	miner.lock.Unlock()
+
+	miner.currentWorker().setEtherbase(addr)
 }
 
 // SetGasCeil sets the gaslimit to strive for when mining blocks post 1559.
 // For pre-1559 blocks, it sets the ceiling.
 func (miner *Miner) SetGasCeil(ceil uint64) {
-	miner.worker.setGasCeil(ceil)
+	miner.currentWorker().setGasCeil(ceil)
 }
 
 // SubscribePendingLogs starts delivering logs from pending transactions
 // to the given channel.
 func (miner *Miner) SubscribePendingLogs(ch chan<- []*types.Log) event.Subscription {
-	return miner.worker.pendingLogsFeed.Subscribe(ch)
+	return miner.currentWorker().pendingLogsFeed.Subscribe(ch)
 }
 
 // BuildPayload builds the payload according to the provided parameters.
 func (miner *Miner) BuildPayload(args *BuildPayloadArgs) (*Payload, error) {
-	return miner.worker.buildPayload(args)
+	return miner.currentWorker().buildPayload(args)
 }
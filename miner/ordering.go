@@ -31,6 +31,7 @@ type txWithMinerFee struct {
 	tx   *txpool.LazyTransaction
 	from common.Address
 	fees *uint256.Int
+	rank int // Position of the account in the carry-over queue, 0 if not carried over
 }
 
 // newTxWithMinerFee creates a wrapped transaction, calculating the effective
@@ -60,6 +61,14 @@ type txByPriceAndTime []*txWithMinerFee
 
 func (s txByPriceAndTime) Len() int { return len(s) }
 func (s txByPriceAndTime) Less(i, j int) bool {
+	// Accounts carried over from an interrupted round go first, in the order
+	// they were left out in
+	if s[i].rank != s[j].rank {
+		if s[i].rank == 0 || s[j].rank == 0 {
+			return s[i].rank != 0
+		}
+		return s[i].rank < s[j].rank
+	}
 	// If the prices are equal, use the time the transaction was first seen for
 	// deterministic sorting
 	cmp := s[i].fees.Cmp(s[j].fees)
@@ -134,11 +143,30 @@ func (t *transactionsByPriceAndNonce) Peek() (*txpool.LazyTransaction, *uint256.
 	return t.heads[0].tx, t.heads[0].fees
 }
 
+// Prioritize moves the accounts of the given ranks ahead of all others, in
+// ascending rank order, until they run out of transactions.
+func (t *transactionsByPriceAndNonce) Prioritize(ranks map[common.Address]int) {
+	if len(ranks) == 0 {
+		return
+	}
+	for _, head := range t.heads {
+		head.rank = ranks[head.from]
+	}
+	heap.Init(&t.heads)
+}
+
+// Carried returns whether the next transaction was carried over from an
+// interrupted round.
+func (t *transactionsByPriceAndNonce) Carried() bool {
+	return len(t.heads) > 0 && t.heads[0].rank != 0
+}
+
 // Shift replaces the current best head with the next one from the same account.
 func (t *transactionsByPriceAndNonce) Shift() {
 	acc := t.heads[0].from
 	if txs, ok := t.txs[acc]; ok && len(txs) > 0 {
 		if wrapped, err := newTxWithMinerFee(txs[0], acc, t.baseFee); err == nil {
+			wrapped.rank = t.heads[0].rank
 			t.heads[0], t.txs[acc] = wrapped, txs[1:]
 			heap.Fix(&t.heads, 0)
 			return
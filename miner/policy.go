@@ -0,0 +1,210 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	policyVetoMeter    = metrics.NewRegisteredMeter("worker/policy/veto", nil)
+	policyFailureMeter = metrics.NewRegisteredMeter("worker/policy/failure", nil)
+	policyTimer        = metrics.NewRegisteredTimer("worker/policy/review", nil)
+)
+
+// PolicyVerdict is the review of a candidate block by a block policy.
+type PolicyVerdict struct {
+	// Score ranks the candidates of the same height. A candidate scoring lower
+	// than the one already being sealed doesn't replace it.
+	Score int64 `json:"score"`
+
+	// Veto drops the candidate instead of sealing it.
+	Veto bool `json:"veto"`
+
+	// Reason optionally explains the verdict, for the logs.
+	Reason string `json:"reason,omitempty"`
+}
+
+// BlockPolicy scores or vetoes the candidate blocks of the worker before they
+// are sealed, e.g. to apply compliance filters or a custom ordering policy.
+// Reviews are bounded by a timeout, after which the candidate is sealed as if
+// there was no policy.
+type BlockPolicy interface {
+	Review(ctx context.Context, block *types.Block, receipts []*types.Receipt) (PolicyVerdict, error)
+}
+
+// noopPolicy is the default policy, sealing every candidate.
+type noopPolicy struct{}
+
+func (noopPolicy) Review(context.Context, *types.Block, []*types.Receipt) (PolicyVerdict, error) {
+	return PolicyVerdict{}, nil
+}
+
+// policyCandidate is the summary of a candidate block sent to an RPC policy.
+type policyCandidate struct {
+	Number       hexutil.Uint64       `json:"number"`
+	ParentHash   common.Hash          `json:"parentHash"`
+	Coinbase     common.Address       `json:"miner"`
+	Time         hexutil.Uint64       `json:"timestamp"`
+	GasLimit     hexutil.Uint64       `json:"gasLimit"`
+	GasUsed      hexutil.Uint64       `json:"gasUsed"`
+	Transactions []*policyCandidateTx `json:"transactions"`
+}
+
+// policyCandidateTx is the summary of a transaction of a candidate block.
+type policyCandidateTx struct {
+	Hash    common.Hash     `json:"hash"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	Value   *hexutil.Big    `json:"value"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+}
+
+// RPCPolicy is a block policy delegating the reviews to an external policy
+// engine serving the policy_reviewBlock JSON-RPC method.
+type RPCPolicy struct {
+	client *rpc.Client
+	signer types.Signer
+}
+
+// NewRPCPolicy connects to the policy engine at the given endpoint.
+func NewRPCPolicy(endpoint string, chainConfig *params.ChainConfig) (*RPCPolicy, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRPCPolicy(client, chainConfig), nil
+}
+
+func newRPCPolicy(client *rpc.Client, chainConfig *params.ChainConfig) *RPCPolicy {
+	return &RPCPolicy{
+		client: client,
+		signer: types.LatestSigner(chainConfig),
+	}
+}
+
+// Review sends the summary of the candidate block to the policy engine and
+// returns its verdict.
+func (p *RPCPolicy) Review(ctx context.Context, block *types.Block, receipts []*types.Receipt) (PolicyVerdict, error) {
+	candidate := &policyCandidate{
+		Number:       hexutil.Uint64(block.NumberU64()),
+		ParentHash:   block.ParentHash(),
+		Coinbase:     block.Coinbase(),
+		Time:         hexutil.Uint64(block.Time()),
+		GasLimit:     hexutil.Uint64(block.GasLimit()),
+		GasUsed:      hexutil.Uint64(block.GasUsed()),
+		Transactions: make([]*policyCandidateTx, 0, len(block.Transactions())),
+	}
+
+	for i, tx := range block.Transactions() {
+		from, err := types.Sender(p.signer, tx)
+		if err != nil {
+			return PolicyVerdict{}, err
+		}
+
+		summary := &policyCandidateTx{
+			Hash:  tx.Hash(),
+			From:  from,
+			To:    tx.To(),
+			Value: (*hexutil.Big)(tx.Value()),
+		}
+
+		if i < len(receipts) {
+			summary.GasUsed = hexutil.Uint64(receipts[i].GasUsed)
+		}
+
+		candidate.Transactions = append(candidate.Transactions, summary)
+	}
+
+	var verdict PolicyVerdict
+	if err := p.client.CallContext(ctx, &verdict, "policy_reviewBlock", candidate); err != nil {
+		return PolicyVerdict{}, err
+	}
+
+	return verdict, nil
+}
+
+// Close disconnects from the policy engine.
+func (p *RPCPolicy) Close() {
+	p.client.Close()
+}
+
+// policyGate decides which of the candidate blocks submitted for sealing are
+// sealed, according to the verdicts of a block policy.
+type policyGate struct {
+	policy  BlockPolicy
+	timeout time.Duration
+
+	number uint64 // Height of the candidate being sealed
+	score  int64  // Score of the candidate being sealed
+	active bool   // Whether a candidate has been admitted at all
+}
+
+func newPolicyGate(policy BlockPolicy, timeout time.Duration) *policyGate {
+	if policy == nil {
+		policy = noopPolicy{}
+	}
+
+	return &policyGate{
+		policy:  policy,
+		timeout: timeout,
+	}
+}
+
+// admit reviews a candidate block and reports whether it should replace the
+// one being sealed. Vetoed candidates, and candidates scoring lower than the
+// one of the same height being sealed, are rejected. A failing or slow policy
+// admits the candidate with a neutral score, so it can't stall block
+// production.
+func (g *policyGate) admit(block *types.Block, receipts []*types.Receipt) bool {
+	ctx := context.Background()
+
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	verdict, err := g.policy.Review(ctx, block, receipts)
+	policyTimer.UpdateSince(start)
+
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Warn("Block policy review timed out", "number", block.NumberU64(), "timeout", g.timeout)
+		} else {
+			log.Warn("Block policy review failed", "number", block.NumberU64(), "err", err)
+		}
+
+		policyFailureMeter.Mark(1)
+
+		verdict = PolicyVerdict{}
+	}
+
+	if verdict.Veto {
+		log.Info("Candidate block vetoed by policy", "number", block.NumberU64(), "txs", len(block.Transactions()), "reason", verdict.Reason)
+		policyVetoMeter.Mark(1)
+
+		return false
+	}
+
+	number := block.NumberU64()
+	if g.active && g.number == number && verdict.Score < g.score {
+		log.Debug("Candidate block scored lower by policy", "number", number, "score", verdict.Score, "sealing", g.score, "reason", verdict.Reason)
+		return false
+	}
+
+	g.number, g.score, g.active = number, verdict.Score, true
+
+	return true
+}
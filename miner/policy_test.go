@@ -0,0 +1,132 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fixedPolicy returns the verdicts of a function of the candidate block.
+type fixedPolicy func(block *types.Block) (PolicyVerdict, error)
+
+func (p fixedPolicy) Review(ctx context.Context, block *types.Block, receipts []*types.Receipt) (PolicyVerdict, error) {
+	return p(block)
+}
+
+func policyBlock(number int64, extra byte) *types.Block {
+	return types.NewBlockWithHeader(&types.Header{Number: big.NewInt(number), Extra: []byte{extra}})
+}
+
+func TestPolicyGate(t *testing.T) {
+	t.Parallel()
+
+	scores := map[byte]int64{1: 5, 2: 3, 3: 8}
+
+	gate := newPolicyGate(fixedPolicy(func(block *types.Block) (PolicyVerdict, error) {
+		switch extra := block.Extra()[0]; extra {
+		case 0xff:
+			return PolicyVerdict{Veto: true, Reason: "sanctioned"}, nil
+		case 0xee:
+			return PolicyVerdict{}, errors.New("unavailable")
+		default:
+			return PolicyVerdict{Score: scores[extra]}, nil
+		}
+	}), time.Second)
+
+	require.False(t, gate.admit(policyBlock(1, 0xff), nil))
+	require.True(t, gate.admit(policyBlock(1, 1), nil))
+
+	// A lower scoring candidate doesn't replace the one being sealed, a higher one does
+	require.False(t, gate.admit(policyBlock(1, 2), nil))
+	require.True(t, gate.admit(policyBlock(1, 3), nil))
+
+	// Scores are only compared within a height
+	require.True(t, gate.admit(policyBlock(2, 2), nil))
+
+	// A failing policy doesn't stall block production
+	require.True(t, gate.admit(policyBlock(3, 0xee), nil))
+
+	// Without a policy every candidate is sealed
+	gate = newPolicyGate(nil, time.Second)
+	require.True(t, gate.admit(policyBlock(1, 0), nil))
+	require.True(t, gate.admit(policyBlock(1, 1), nil))
+}
+
+func TestPolicyGateTimeout(t *testing.T) {
+	t.Parallel()
+
+	gate := newPolicyGate(reviewFunc(func(ctx context.Context, block *types.Block) (PolicyVerdict, error) {
+		select {
+		case <-ctx.Done():
+			return PolicyVerdict{}, ctx.Err()
+		case <-time.After(time.Second):
+			return PolicyVerdict{Veto: true}, nil
+		}
+	}), 50*time.Millisecond)
+
+	// A slow policy can't veto the candidate past the timeout
+	start := time.Now()
+	require.True(t, gate.admit(policyBlock(1, 0), nil))
+	require.Less(t, time.Since(start), time.Second)
+}
+
+// reviewFunc is a block policy honouring the review context.
+type reviewFunc func(ctx context.Context, block *types.Block) (PolicyVerdict, error)
+
+func (f reviewFunc) Review(ctx context.Context, block *types.Block, receipts []*types.Receipt) (PolicyVerdict, error) {
+	return f(ctx, block)
+}
+
+// policyService is a policy engine vetoing the transactions of a sender.
+type policyService struct {
+	blocked common.Address
+}
+
+func (s *policyService) ReviewBlock(candidate policyCandidate) PolicyVerdict {
+	for _, tx := range candidate.Transactions {
+		if tx.From == s.blocked {
+			return PolicyVerdict{Veto: true, Reason: "blocked sender"}
+		}
+	}
+
+	return PolicyVerdict{Score: int64(len(candidate.Transactions))}
+}
+
+func TestRPCPolicy(t *testing.T) {
+	t.Parallel()
+
+	key, _ := crypto.GenerateKey()
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+
+	server := rpc.NewServer("", 0, 0)
+	defer server.Stop()
+
+	require.NoError(t, server.RegisterName("policy", &policyService{blocked: sender}))
+
+	policy := newRPCPolicy(rpc.DialInProc(server), params.TestChainConfig)
+	defer policy.Close()
+
+	signer := types.LatestSigner(params.TestChainConfig)
+	tx := types.MustSignNewTx(key, signer, &types.LegacyTx{Nonce: 0, Gas: 21000, GasPrice: big.NewInt(1), To: &common.Address{}})
+
+	verdict, err := policy.Review(context.Background(), policyBlock(1, 0), nil)
+	require.NoError(t, err)
+	require.Equal(t, PolicyVerdict{}, verdict)
+
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)}).WithBody(types.Body{Transactions: []*types.Transaction{tx}})
+
+	verdict, err = policy.Review(context.Background(), block, nil)
+	require.NoError(t, err)
+	require.True(t, verdict.Veto)
+	require.Equal(t, "blocked sender", verdict.Reason)
+}
@@ -0,0 +1,108 @@
+package miner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/bor/alert"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// watchdogInterval is the interval the watchdog checks the sealing loop at.
+const watchdogInterval = time.Second
+
+var watchdogRestartsCounter = metrics.NewRegisteredCounter("worker/watchdog/restarts", nil)
+
+// slotDuration returns the time a block slot lasts, the recommit interval if
+// the chain has no fixed block period.
+func (w *worker) slotDuration() time.Duration {
+	var period uint64
+
+	switch {
+	case w.chainConfig.Bor != nil:
+		period = w.chainConfig.Bor.CalculatePeriod(w.chain.CurrentBlock().Number.Uint64() + 1)
+	case w.chainConfig.Clique != nil:
+		period = w.chainConfig.Clique.Period
+	}
+
+	if period == 0 {
+		return w.recommit
+	}
+
+	return time.Duration(period) * time.Second
+}
+
+// stalled returns for how long the sealing loop didn't pick up any work, and
+// whether that's more than the given number of slots while sealing is enabled.
+func (w *worker) stalled(now time.Time, slots uint64) (time.Duration, bool) {
+	if !w.IsRunning() {
+		return 0, false
+	}
+
+	idle := now.Sub(time.Unix(0, w.lastWork.Load()))
+
+	return idle, idle > time.Duration(slots)*w.slotDuration()
+}
+
+// SealingIdle returns for how long the sealing loop didn't pick up any work,
+// and whether sealing is enabled at all.
+func (miner *Miner) SealingIdle() (time.Duration, bool) {
+	w := miner.currentWorker()
+	if !w.IsRunning() {
+		return 0, false
+	}
+
+	return time.Since(time.Unix(0, w.lastWork.Load())), true
+}
+
+// restartWorker replaces a worker whose sealing loop stalled with a new one,
+// carrying over its settings and the pending logs subscriptions, and starts
+// sealing again. The stalled worker is closed in the background, as its loops
+// may never return if they are deadlocked.
+func (miner *Miner) restartWorker(idle time.Duration) {
+	old := miner.worker
+
+	log.Error("Sealing loop stalled, restarting the miner", "idle", common.PrettyDuration(idle), "slots", miner.config.WatchdogSlots)
+
+	// Stop the stalled worker from receiving events, a deadlocked worker
+	// would block the transaction pool and the chain feeds otherwise
+	old.running.Store(false)
+	old.txsSub.Unsubscribe()
+	old.chainHeadSub.Unsubscribe()
+
+	w := newWorkerWithFeed(old.config, old.chainConfig, miner.engine, miner.eth, miner.mux, old.isLocalBlock, true, old.pendingLogsFeed)
+	w.syncing.Store(old.syncing.Load())
+
+	miner.lock.Lock()
+	miner.worker = w
+
+	var (
+		etherbase = miner.etherbase
+		extra     = miner.extra
+		tip       = miner.tip
+		recommit  = miner.recommit
+		alerts    = miner.alerts
+	)
+	miner.lock.Unlock()
+
+	w.setEtherbase(etherbase)
+	w.setExtra(extra)
+
+	if tip != nil {
+		w.setGasTip(tip)
+	}
+
+	if recommit != miner.config.Recommit {
+		w.setRecommitInterval(recommit)
+	}
+
+	w.start()
+
+	go old.close()
+
+	watchdogRestartsCounter.Inc(1)
+
+	alerts.Notify(alert.SealingStalled, fmt.Sprintf("sealing loop didn't attempt work for %v, miner restarted", common.PrettyDuration(idle)))
+}
@@ -0,0 +1,54 @@
+package miner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestWatchdogRestartsStalledWorker(t *testing.T) {
+	t.Parallel()
+
+	minerBor := NewBorDefaultMiner(t)
+	defer func() {
+		minerBor.Cleanup(false)
+		minerBor.Ctrl.Finish()
+	}()
+
+	miner := minerBor.Miner
+	miner.SetEtherbase(common.Address{0x2})
+
+	// A stopped worker is never stalled
+	_, stalled := miner.currentWorker().stalled(time.Now().Add(time.Hour), 1)
+	require.False(t, stalled)
+
+	miner.Start()
+	waitForMiningState(t, miner, true)
+
+	old := miner.currentWorker()
+
+	_, stalled = old.stalled(time.Now(), 1)
+	require.False(t, stalled)
+
+	// Simulate a sealing loop that didn't pick up work for a while
+	old.lastWork.Store(time.Now().Add(-time.Hour).UnixNano())
+
+	idle, stalled := old.stalled(time.Now(), 1)
+	require.True(t, stalled)
+	require.Greater(t, idle, 59*time.Minute)
+
+	miner.restartWorker(idle)
+
+	w := miner.currentWorker()
+	require.NotSame(t, old, w)
+	require.False(t, old.IsRunning())
+	require.True(t, w.IsRunning())
+	require.True(t, miner.Mining())
+
+	// The settings and subscriptions are carried over
+	require.Equal(t, common.Address{0x2}, w.etherbase())
+	require.Same(t, old.pendingLogsFeed, w.pendingLogsFeed)
+}
@@ -28,6 +28,7 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 	"github.com/holiman/uint256"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/tracing"
@@ -42,6 +43,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/debug"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
@@ -200,7 +202,7 @@ type worker struct {
 	chain       *core.BlockChain
 
 	// Feeds
-	pendingLogsFeed event.Feed
+	pendingLogsFeed *event.Feed // Shared with the worker replacing this one if it's restarted
 
 	// Subscriptions
 	mux          *event.TypeMux
@@ -241,6 +243,8 @@ type worker struct {
 	newTxs  atomic.Int32 // New arrival transaction count since last sealing work submitting.
 	syncing atomic.Bool  // The indicator whether the node is still syncing.
 
+	lastWork atomic.Int64 // Time the sealing loop last picked up work or was started, in unix nanoseconds
+
 	// newpayloadTimeout is the maximum timeout allowance for creating payload.
 	// The default value is 2 seconds but node operator can set it to arbitrary
 	// large value. A large timeout allowance may cause Geth to fail creating
@@ -265,6 +269,9 @@ type worker struct {
 	interruptCommitFlag bool // Denotes whether interrupt commit is enabled or not
 	interruptCtx        context.Context
 	interruptedTxCache  *vm.TxCache
+	carryover           *txCarryover // Transactions left out by the last interrupted commit, nil if disabled
+
+	policy *policyGate // Scores or vetoes the candidate blocks before sealing
 
 	// noempty is the flag used to control whether the feature of pre-seal empty
 	// block is enabled. The default value is false(pre-seal is enabled by default).
@@ -274,9 +281,17 @@ type worker struct {
 	noempty atomic.Bool
 }
 
-//nolint:staticcheck
 func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, isLocalBlock func(header *types.Header) bool, init bool) *worker {
+	return newWorkerWithFeed(config, chainConfig, engine, eth, mux, isLocalBlock, init, new(event.Feed))
+}
+
+// newWorkerWithFeed creates a worker delivering the logs of pending transactions
+// to the given feed.
+//
+//nolint:staticcheck
+func newWorkerWithFeed(config *Config, chainConfig *params.ChainConfig, engine consensus.Engine, eth Backend, mux *event.TypeMux, isLocalBlock func(header *types.Header) bool, init bool, pendingLogsFeed *event.Feed) *worker {
 	worker := &worker{
+		pendingLogsFeed:     pendingLogsFeed,
 		config:              config,
 		chainConfig:         chainConfig,
 		engine:              engine,
@@ -299,8 +314,23 @@ func newWorker(config *Config, chainConfig *params.ChainConfig, engine consensus
 		resubmitIntervalCh:  make(chan time.Duration),
 		resubmitAdjustCh:    make(chan *intervalAdjust, resubmitAdjustChanSize),
 		interruptCommitFlag: config.CommitInterruptFlag,
+		carryover:           newTxCarryover(config.InterruptCarryover),
 	}
 	worker.noempty.Store(true)
+
+	var policy BlockPolicy
+
+	if config.PolicyEndpoint != "" {
+		rpcPolicy, err := NewRPCPolicy(config.PolicyEndpoint, chainConfig)
+		if err != nil {
+			log.Error("Failed to connect to the block policy, sealing every candidate", "endpoint", config.PolicyEndpoint, "err", err)
+		} else {
+			policy = rpcPolicy
+		}
+	}
+
+	worker.policy = newPolicyGate(policy, config.PolicyTimeout)
+
 	// Subscribe for transaction insertion events (whether from network or resurrects)
 	worker.txsSub = eth.TxPool().SubscribeTransactions(worker.txsCh, true)
 	// Subscribe events for blockchain
@@ -424,6 +454,7 @@ func (w *worker) pendingBlock() *types.Block {
 
 // start sets the running status as 1 and triggers new work submitting.
 func (w *worker) start() {
+	w.lastWork.Store(time.Now().UnixNano())
 	w.running.Store(true)
 	w.startCh <- struct{}{}
 }
@@ -444,6 +475,10 @@ func (w *worker) close() {
 	w.running.Store(false)
 	close(w.exitCh)
 	w.wg.Wait()
+
+	if closer, ok := w.policy.policy.(interface{ Close() }); ok {
+		closer.Close()
+	}
 }
 
 // recalcRecommit recalculates the resubmitting interval upon feedback.
@@ -590,6 +625,8 @@ func (w *worker) mainLoop() {
 	for {
 		select {
 		case req := <-w.newWorkCh:
+			w.lastWork.Store(time.Now().UnixNano())
+
 			if w.chainConfig.ChainID.Cmp(params.BorMainnetChainConfig.ChainID) == 0 || w.chainConfig.ChainID.Cmp(params.MumbaiChainConfig.ChainID) == 0 || w.chainConfig.ChainID.Cmp(params.AmoyChainConfig.ChainID) == 0 {
 				if w.eth.PeerCount() > 0 {
 					//nolint:contextcheck
@@ -704,6 +741,10 @@ func (w *worker) taskLoop() {
 			if sealHash == prev {
 				continue
 			}
+			// Let the block policy veto the candidate or keep sealing a better one
+			if !w.policy.admit(task.block, task.receipts) {
+				continue
+			}
 			// Interrupt previous sealing operation
 			interrupt()
 
@@ -761,6 +802,7 @@ func (w *worker) resultLoop() {
 			var (
 				sealhash = w.engine.SealHash(block.Header())
 				hash     = block.Hash()
+				sealed   = time.Now()
 			)
 
 			w.pendingMu.RLock()
@@ -801,7 +843,10 @@ func (w *worker) resultLoop() {
 
 				logs = append(logs, receipt.Logs...)
 			}
+			tracing.RecordBlockStage(tracing.BlockSeal, hash, block.NumberU64(), task.createdAt, sealed, attribute.String("sealhash", sealhash.Hex()))
+
 			// Commit block and state to database.
+			wstart := time.Now()
 			_, err = w.chain.WriteBlockAndSetHead(block, receipts, logs, task.state, true)
 
 			if err != nil {
@@ -809,6 +854,14 @@ func (w *worker) resultLoop() {
 				continue
 			}
 
+			tracing.RecordBlockStage(tracing.BlockCommit, hash, block.NumberU64(), wstart, time.Now())
+
+			// The time between signing the block and being able to broadcast it
+			// is lost from the slot
+			if w.chainConfig.Bor != nil {
+				debug.ReportLatency("seal", block.NumberU64(), time.Since(sealed), time.Duration(w.chainConfig.Bor.CalculatePeriod(block.NumberU64()))*time.Second)
+			}
+
 			log.Info("Successfully sealed new block", "number", block.Number(), "sealhash", sealhash, "hash", hash,
 				"elapsed", common.PrettyDuration(time.Since(task.createdAt)))
 
@@ -931,13 +984,19 @@ func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transac
 		}(chDeps)
 	}
 
-	var lastTxHash common.Hash
+	var (
+		lastTxHash  common.Hash
+		interrupted *carriedTx
+	)
 
 mainloop:
 	for {
 		// Check interruption signal and abort building if it's fired.
 		if interrupt != nil {
 			if signal := interrupt.Load(); signal != commitInterruptNone {
+				if signal == commitInterruptTimeout {
+					w.carryover.record(interrupted, plainTxs, blobTxs)
+				}
 				return signalToErr(signal)
 			}
 		}
@@ -952,6 +1011,7 @@ mainloop:
 			case <-w.interruptCtx.Done():
 				txCommitInterruptCounter.Inc(1)
 				log.Warn("Tx Level Interrupt", "hash", lastTxHash, "err", w.interruptCtx.Err())
+				w.carryover.record(interrupted, plainTxs, blobTxs)
 				break mainloop
 			default:
 			}
@@ -1056,6 +1116,7 @@ mainloop:
 		// Start executing the transaction
 		env.state.SetTxContext(tx.Hash(), env.tcount)
 
+		carried := txs.Carried()
 		logs, err := w.commitTransaction(env, tx)
 
 		// Check if we have a `delay` set in interrupt context. It's only set during tests.
@@ -1077,6 +1138,10 @@ mainloop:
 			coalescedLogs = append(coalescedLogs, logs...)
 			env.tcount++
 
+			if carried {
+				carryoverRetriedMeter.Mark(1)
+			}
+
 			if EnableMVHashMap && w.IsRunning() {
 				env.depsMVFullWriteList = append(env.depsMVFullWriteList, env.state.MVFullWriteList())
 				env.mvReadMapList = append(env.mvReadMapList, env.state.MVReadMap())
@@ -1096,6 +1161,13 @@ mainloop:
 
 			txs.Shift()
 
+		case errors.Is(err, vm.ErrInterrupt):
+			// Execution was cut short by the commit interrupt, remember the transaction
+			// so that its account is retried first in the next round
+			log.Debug("Transaction interrupted, account skipped", "hash", ltx.Hash)
+			interrupted = &carriedTx{from: from, hash: ltx.Hash}
+			txs.Pop()
+
 		default:
 			// Transaction is regarded as invalid, drop all consecutive transactions from
 			// the same sender because of `nonce-too-high` clause.
@@ -1157,6 +1229,11 @@ mainloop:
 				blockExtraData.TxDependency = nil
 			}
 		} else {
+			// keep the validator bytes and milestone reference set by the engine
+			if err := rlp.DecodeBytes(env.header.Extra[types.ExtraVanityLength:len(env.header.Extra)-types.ExtraSealLength], &blockExtraData); err != nil {
+				blockExtraData = types.BlockExtraData{}
+			}
+
 			blockExtraData.TxDependency = nil
 		}
 
@@ -1263,10 +1340,11 @@ func (w *worker) prepareWork(genParams *generateParams) (*environment, error) {
 
 	// Run the consensus preparation with the default or customized consensus engine.
 	if err := w.engine.Prepare(w.chain, header); err != nil {
-		switch err.(type) {
-		case *bor.UnauthorizedSignerError:
+		var signerErr *bor.UnauthorizedSignerError
+
+		if errors.As(err, &signerErr) {
 			log.Debug("Failed to prepare header for sealing", "err", err)
-		default:
+		} else {
 			log.Error("Failed to prepare header for sealing", "err", err)
 		}
 
@@ -1322,6 +1400,9 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := w.eth.TxPool().Pending(filter)
 
+	// Retry the accounts left out by an interrupted commit first
+	carried := w.carryover.take(pendingPlainTxs, pendingBlobTxs)
+
 	// Split the pending transactions into locals and remotes.
 	localPlainTxs, remotePlainTxs = make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
 	localBlobTxs, remoteBlobTxs = make(map[common.Address][]*txpool.LazyTransaction), pendingBlobTxs
@@ -1344,6 +1425,9 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 		plainTxs = newTransactionsByPriceAndNonce(env.signer, localPlainTxs, env.header.BaseFee)
 		blobTxs = newTransactionsByPriceAndNonce(env.signer, localBlobTxs, env.header.BaseFee)
 
+		plainTxs.Prioritize(carried)
+		blobTxs.Prioritize(carried)
+
 		if err := w.commitTransactions(env, plainTxs, blobTxs, interrupt, new(uint256.Int)); err != nil {
 			return err
 		}
@@ -1355,6 +1439,9 @@ func (w *worker) fillTransactions(interrupt *atomic.Int32, env *environment) err
 		plainTxs = newTransactionsByPriceAndNonce(env.signer, remotePlainTxs, env.header.BaseFee)
 		blobTxs = newTransactionsByPriceAndNonce(env.signer, remoteBlobTxs, env.header.BaseFee)
 
+		plainTxs.Prioritize(carried)
+		blobTxs.Prioritize(carried)
+
 		if err := w.commitTransactions(env, plainTxs, blobTxs, interrupt, new(uint256.Int)); err != nil {
 			return err
 		}
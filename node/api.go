@@ -217,9 +217,13 @@ func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *stri
 		CorsAllowedOrigins: api.node.config.HTTPCors,
 		Vhosts:             api.node.config.HTTPVirtualHosts,
 		Modules:            api.node.config.HTTPModules,
+		AllowedMethods:     api.node.config.HTTPAllowedMethods,
+		DeniedMethods:      api.node.config.HTTPDeniedMethods,
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			batchCostLimit:         int(api.node.config.RPCBatchCostLimit),
+			fairSchedulingSlots:    int(api.node.config.RPCFairSchedulingSlots),
 		},
 	}
 	if cors != nil {
@@ -299,12 +303,16 @@ func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, ap
 
 	// Determine config.
 	config := wsConfig{
-		Modules: api.node.config.WSModules,
-		Origins: api.node.config.WSOrigins,
+		Modules:        api.node.config.WSModules,
+		AllowedMethods: api.node.config.WSAllowedMethods,
+		DeniedMethods:  api.node.config.WSDeniedMethods,
+		Origins:        api.node.config.WSOrigins,
 		// ExposeAll: api.node.config.WSExposeAll,
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			batchCostLimit:         int(api.node.config.RPCBatchCostLimit),
+			fairSchedulingSlots:    int(api.node.config.RPCFairSchedulingSlots),
 		},
 	}
 	if apis != nil {
@@ -133,6 +133,15 @@ type Config struct {
 	// exposed.
 	HTTPModules []string
 
+	// HTTPAllowedMethods is a list of methods, in the namespace_method form, to
+	// expose via the HTTP RPC interface even if their module isn't. The other
+	// methods of their module aren't exposed.
+	HTTPAllowedMethods []string `toml:",omitempty"`
+
+	// HTTPDeniedMethods is a list of methods, in the namespace_method form, not
+	// to expose via the HTTP RPC interface even if their module is.
+	HTTPDeniedMethods []string `toml:",omitempty"`
+
 	// HTTPTimeouts allows for customization of the timeout values used by the HTTP RPC
 	// interface.
 	HTTPTimeouts rpc.HTTPTimeouts
@@ -172,6 +181,15 @@ type Config struct {
 	// exposed.
 	WSModules []string
 
+	// WSAllowedMethods is a list of methods, in the namespace_method form, to
+	// expose via the websocket RPC interface even if their module isn't. The
+	// other methods of their module aren't exposed.
+	WSAllowedMethods []string `toml:",omitempty"`
+
+	// WSDeniedMethods is a list of methods, in the namespace_method form, not
+	// to expose via the websocket RPC interface even if their module is.
+	WSDeniedMethods []string `toml:",omitempty"`
+
 	// WSExposeAll exposes all API modules via the WebSocket RPC interface rather
 	// than just the public ones.
 	//
@@ -217,8 +235,16 @@ type Config struct {
 
 	DBEngine string `toml:",omitempty"`
 
+	// FreezerCodec is the compression codec of newly created ancient body and
+	// receipt tables ("snappy" | "zstd"), empty keeps the existing codec.
+	FreezerCodec string `toml:",omitempty"`
+
 	// Maximum number of messages in a batch
 	RPCBatchLimit uint64 `toml:",omitempty"`
+	// Maximum cumulative cost of the calls in a batch
+	RPCBatchCostLimit uint64 `toml:",omitempty"`
+	// Number of concurrently running HTTP calls divided fairly between hosts
+	RPCFairSchedulingSlots uint64 `toml:",omitempty"`
 	// Configs for RPC execution pool
 	WSJsonRPCExecutionPoolSize             uint64        `toml:",omitempty"`
 	WSJsonRPCExecutionPoolRequestTimeout   time.Duration `toml:",omitempty"`
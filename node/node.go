@@ -437,6 +437,8 @@ func (n *Node) startRPC() error {
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		batchCostLimit:         int(n.config.RPCBatchCostLimit),
+		fairSchedulingSlots:    int(n.config.RPCFairSchedulingSlots),
 	}
 
 	initHttp := func(server *httpServer, port int) error {
@@ -448,6 +450,8 @@ func (n *Node) startRPC() error {
 			CorsAllowedOrigins: n.config.HTTPCors,
 			Vhosts:             n.config.HTTPVirtualHosts,
 			Modules:            n.config.HTTPModules,
+			AllowedMethods:     n.config.HTTPAllowedMethods,
+			DeniedMethods:      n.config.HTTPDeniedMethods,
 			prefix:             n.config.HTTPPathPrefix,
 			rpcEndpointConfig:  rpcConfig,
 		}); err != nil {
@@ -468,6 +472,8 @@ func (n *Node) startRPC() error {
 		if err := server.enableWS(openAPIs, wsConfig{
 			executionPoolSize: n.config.WSJsonRPCExecutionPoolSize,
 			Modules:           n.config.WSModules,
+			AllowedMethods:    n.config.WSAllowedMethods,
+			DeniedMethods:     n.config.WSDeniedMethods,
 			Origins:           n.config.WSOrigins,
 			prefix:            n.config.WSPathPrefix,
 			rpcEndpointConfig: rpcConfig,
@@ -840,6 +846,7 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient,
 			ReadOnly:          readonly,
 			DisableFreeze:     disableFreeze,
 			IsLastOffset:      isLastOffset,
+			FreezerCodec:      n.config.FreezerCodec,
 		})
 	}
 
@@ -40,6 +40,8 @@ import (
 // httpConfig is the JSON-RPC/HTTP configuration.
 type httpConfig struct {
 	Modules            []string
+	AllowedMethods     []string // Methods served even if their module isn't
+	DeniedMethods      []string // Methods not served even if their module is
 	CorsAllowedOrigins []string
 	Vhosts             []string
 	prefix             string // path prefix on which to mount http handler
@@ -55,7 +57,9 @@ type wsConfig struct {
 	executionPoolSize uint64
 	Origins           []string
 	Modules           []string
-	prefix            string // path prefix on which to mount ws handler
+	AllowedMethods    []string // Methods served even if their module isn't
+	DeniedMethods     []string // Methods not served even if their module is
+	prefix            string   // path prefix on which to mount ws handler
 	rpcEndpointConfig
 }
 
@@ -63,6 +67,8 @@ type rpcEndpointConfig struct {
 	jwtSecret              []byte // optional JWT secret
 	batchItemLimit         int
 	batchResponseSizeLimit int
+	batchCostLimit         int
+	fairSchedulingSlots    int // only applies to HTTP
 	httpBodyLimit          int
 }
 
@@ -333,10 +339,12 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	srv.SetRPCBatchLimit(h.RPCBatchLimit)
 
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetBatchCostLimit(config.batchCostLimit)
+	srv.SetFairScheduling(config.fairSchedulingSlots)
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
-	if err := RegisterApis(apis, config.Modules, srv); err != nil {
+	if err := registerApis(apis, config.Modules, config.AllowedMethods, config.DeniedMethods, srv); err != nil {
 		return err
 	}
 
@@ -373,10 +381,11 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	srv.SetRPCBatchLimit(h.RPCBatchLimit)
 
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit)
+	srv.SetBatchCostLimit(config.batchCostLimit)
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit)
 	}
-	if err := RegisterApis(apis, config.Modules, srv); err != nil {
+	if err := registerApis(apis, config.Modules, config.AllowedMethods, config.DeniedMethods, srv); err != nil {
 		return err
 	}
 
@@ -688,6 +697,16 @@ func (is *ipcServer) stop() error {
 // RegisterApis checks the given modules' availability, generates an allowlist based on the allowed modules,
 // and then registers all of the APIs exposed by the services.
 func RegisterApis(apis []rpc.API, modules []string, srv *rpc.Server) error {
+	return registerApis(apis, modules, nil, nil, srv)
+}
+
+// registerApis registers the APIs of the given modules like RegisterApis, and
+// refines them with method allow and deny lists: the allowed methods are also
+// served if their module isn't, without the rest of it, and the denied methods
+// aren't served even if their module is. Methods are listed in the
+// "namespace_method" form, the subscriptions of a namespace being covered by
+// its subscribe method. Listing a method which doesn't exist is an error.
+func registerApis(apis []rpc.API, modules []string, allowedMethods []string, deniedMethods []string, srv *rpc.Server) error {
 	if bad, available := checkModuleAvailability(modules, apis); len(bad) > 0 {
 		log.Error("Unavailable modules in HTTP API list", "unavailable", bad, "available", available)
 	}
@@ -696,14 +715,89 @@ func RegisterApis(apis []rpc.API, modules []string, srv *rpc.Server) error {
 	for _, module := range modules {
 		allowList[module] = true
 	}
+
+	allowed, err := parseMethodList(allowedMethods, apis)
+	if err != nil {
+		return err
+	}
+
+	denied, err := parseMethodList(deniedMethods, apis)
+	if err != nil {
+		return err
+	}
+
+	// Namespaces served only for some of their methods
+	restricted := make(map[string]bool)
+
+	for method := range allowed {
+		namespace, _, _ := strings.Cut(method, "_")
+		if !allowList[namespace] && len(allowList) > 0 {
+			restricted[namespace] = true
+		}
+	}
 	// Register all the APIs exposed by the services
 	for _, api := range apis {
-		if allowList[api.Namespace] || len(allowList) == 0 {
+		if allowList[api.Namespace] || len(allowList) == 0 || restricted[api.Namespace] {
 			if err := srv.RegisterName(api.Namespace, api.Service); err != nil {
 				return err
 			}
 		}
 	}
 
+	if len(allowed) == 0 && len(denied) == 0 {
+		return nil
+	}
+
+	// Methods of the namespaces not served can't be checked, they're not
+	// served either way
+	served := make(map[string]bool)
+	namespaces := make(map[string]bool)
+
+	for _, method := range srv.Methods() {
+		namespace, _, _ := strings.Cut(method, "_")
+
+		served[method] = true
+		namespaces[namespace] = true
+	}
+
+	for _, list := range []map[string]bool{allowed, denied} {
+		for method := range list {
+			if namespace, _, _ := strings.Cut(method, "_"); namespaces[namespace] && !served[method] {
+				return fmt.Errorf("unknown RPC method %q", method)
+			}
+		}
+	}
+
+	srv.FilterMethods(func(method string) bool {
+		namespace, _, _ := strings.Cut(method, "_")
+		return !denied[method] && (!restricted[namespace] || allowed[method])
+	})
+
 	return nil
 }
+
+// parseMethodList returns the set of methods in the given list, checking they
+// are in the "namespace_method" form and their namespace is available.
+func parseMethodList(methods []string, apis []rpc.API) (map[string]bool, error) {
+	list := make(map[string]bool, len(methods))
+
+	for _, method := range methods {
+		namespace, name, found := strings.Cut(method, "_")
+		if !found || namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid RPC method %q, expected namespace_method", method)
+		}
+
+		available := namespace == rpc.MetadataApi
+		for _, api := range apis {
+			available = available || api.Namespace == namespace
+		}
+
+		if !available {
+			return nil, fmt.Errorf("unknown RPC method %q, namespace %s not available", method, namespace)
+		}
+
+		list[method] = true
+	}
+
+	return list, nil
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -651,6 +652,54 @@ func TestHTTPWriteTimeout(t *testing.T) {
 	})
 }
 
+func TestRegisterApisMethodLists(t *testing.T) {
+	t.Parallel()
+
+	apis := []rpc.API{
+		{Namespace: "test", Service: &testService{}},
+		{Namespace: "bor", Service: &testService{}},
+	}
+
+	methods := func(modules, allowed, denied []string) ([]string, error) {
+		srv := rpc.NewServer("", 0, 0)
+		defer srv.Stop()
+
+		if err := registerApis(apis, modules, allowed, denied, srv); err != nil {
+			return nil, err
+		}
+
+		served := srv.Methods()
+		slices.Sort(served)
+
+		return served, nil
+	}
+
+	// Whole modules only
+	served, err := methods([]string{"test"}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rpc_modules", "test_greet", "test_sleep"}, served)
+
+	// A method of another module, without the rest of it, and one denied
+	served, err = methods([]string{"test"}, []string{"bor_greet"}, []string{"test_sleep"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"bor_greet", "rpc_modules", "test_greet"}, served)
+
+	// Denied methods of modules not served can't be checked, and aren't served
+	served, err = methods([]string{"test"}, nil, []string{"bor_sleep"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rpc_modules", "test_greet", "test_sleep"}, served)
+
+	// Invalid and unknown methods fail
+	_, err = methods([]string{"test"}, []string{"greet"}, nil)
+	assert.ErrorContains(t, err, "invalid RPC method")
+
+	_, err = methods([]string{"test"}, []string{"bor_setHead"}, nil)
+	assert.ErrorContains(t, err, "unknown RPC method")
+
+	_, err = methods([]string{"test"}, nil, []string{"debug_setHead"})
+	assert.ErrorContains(t, err, "namespace debug not available")
+}
+
 func apis() []rpc.API {
 	return []rpc.API{
 		{
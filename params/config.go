@@ -712,12 +712,23 @@ type BorConfig struct {
 	StateReceiverContract      string                 `json:"stateReceiverContract"`    // State receiver contract
 	OverrideStateSyncRecords   map[string]int         `json:"overrideStateSyncRecords"` // override state records count
 	BlockAlloc                 map[string]interface{} `json:"blockAlloc"`
-	BurntContract              map[string]string      `json:"burntContract"`              // governance contract where the token will be sent to and burnt in london fork
-	JaipurBlock                *big.Int               `json:"jaipurBlock"`                // Jaipur switch block (nil = no fork, 0 = already on jaipur)
-	DelhiBlock                 *big.Int               `json:"delhiBlock"`                 // Delhi switch block (nil = no fork, 0 = already on delhi)
-	IndoreBlock                *big.Int               `json:"indoreBlock"`                // Indore switch block (nil = no fork, 0 = already on indore)
-	StateSyncConfirmationDelay map[string]uint64      `json:"stateSyncConfirmationDelay"` // StateSync Confirmation Delay, in seconds, to calculate `to`
-	AhmedabadBlock             *big.Int               `json:"ahmedabadBlock"`             // Ahmedabad switch block (nil = no fork, 0 = already on ahmedabad)
+	BurntContract              map[string]string      `json:"burntContract"`                      // governance contract where the token will be sent to and burnt in london fork
+	JaipurBlock                *big.Int               `json:"jaipurBlock"`                        // Jaipur switch block (nil = no fork, 0 = already on jaipur)
+	DelhiBlock                 *big.Int               `json:"delhiBlock"`                         // Delhi switch block (nil = no fork, 0 = already on delhi)
+	IndoreBlock                *big.Int               `json:"indoreBlock"`                        // Indore switch block (nil = no fork, 0 = already on indore)
+	StateSyncConfirmationDelay map[string]uint64      `json:"stateSyncConfirmationDelay"`         // StateSync Confirmation Delay, in seconds, to calculate `to`
+	AhmedabadBlock             *big.Int               `json:"ahmedabadBlock"`                     // Ahmedabad switch block (nil = no fork, 0 = already on ahmedabad)
+	BackupDelays               map[string][]uint64    `json:"backupDelays,omitempty"`             // Wiggle time of the n-th backup producer, in seconds (empty = n * backupMultiplier)
+	BackoffByStake             map[string]bool        `json:"backoffByStake,omitempty"`           // Order the backup producers by voting power instead of their position
+	MilestoneRefBlock          *big.Int               `json:"milestoneRefBlock,omitempty"`        // Milestone reference header field switch block (nil = no fork, 0 = already active), requires cancun
+	StateSyncGasLimit          map[string]uint64      `json:"stateSyncGasLimit,omitempty"`        // Gas budget of the state-sync events committed per sprint (0 = unlimited)
+	StateSyncSizeLimit         map[string]uint64      `json:"stateSyncSizeLimit,omitempty"`       // Data size budget, in bytes, of the state-sync events committed per sprint (0 = unlimited)
+	ProducerCountBlock         *big.Int               `json:"producerCountBlock,omitempty"`       // Contract defined producer count switch block (nil = no fork, 0 = already active)
+	MixDigestBlock             *big.Int               `json:"mixDigestBlock,omitempty"`           // Mix digest repurposing switch block (nil = no fork, 0 = already active)
+	StrictExtraBlock           *big.Int               `json:"strictExtraBlock,omitempty"`         // Strict validator set extra-data validation switch block (nil = no fork, 0 = already active)
+	ValidatorExtraV2Block      *big.Int               `json:"validatorExtraV2Block,omitempty"`    // Versioned validator set extra-data switch block (nil = no fork, 0 = already active)
+	FeeCurrencyBlock           *big.Int               `json:"feeCurrencyBlock,omitempty"`         // Fee currency hooks switch block (nil = no fork, 0 = already active)
+	ContiguousStateSyncBlock   *big.Int               `json:"contiguousStateSyncBlock,omitempty"` // Ordered and contiguous state-sync events switch block (nil = no fork, 0 = already active)
 }
 
 // String implements the stringer interface, returning the consensus engine details.
@@ -725,6 +736,171 @@ func (c *BorConfig) String() string {
 	return "bor"
 }
 
+// BorFork is a bor hardfork switching a behaviour change on at a block.
+type BorFork struct {
+	Name    string   // Name of the switch block in the chain config
+	Block   *big.Int // Switch block (nil = not scheduled, 0 = active from genesis)
+	Follows string   // Fork which must be scheduled at or before this one, if any
+}
+
+// Forks returns the bor hardforks of the chain, scheduled or not. Forks of the
+// network upgrade sequence follow one another, behaviour changes switched on
+// by their own block can be scheduled independently. A new fork is added here
+// along with its switch block and IsX helper, so that it is ordered, listed
+// and watched like the others.
+func (c *BorConfig) Forks() []BorFork {
+	return []BorFork{
+		{Name: "jaipurBlock", Block: c.JaipurBlock},
+		{Name: "delhiBlock", Block: c.DelhiBlock, Follows: "jaipurBlock"},
+		{Name: "indoreBlock", Block: c.IndoreBlock, Follows: "delhiBlock"},
+		{Name: "ahmedabadBlock", Block: c.AhmedabadBlock, Follows: "indoreBlock"},
+		{Name: "milestoneRefBlock", Block: c.MilestoneRefBlock},
+		{Name: "producerCountBlock", Block: c.ProducerCountBlock},
+		{Name: "mixDigestBlock", Block: c.MixDigestBlock},
+		{Name: "strictExtraBlock", Block: c.StrictExtraBlock},
+		{Name: "validatorExtraV2Block", Block: c.ValidatorExtraV2Block},
+		{Name: "feeCurrencyBlock", Block: c.FeeCurrencyBlock},
+		{Name: "contiguousStateSyncBlock", Block: c.ContiguousStateSyncBlock},
+	}
+}
+
+// CheckForkOrder checks that no bor hardfork is scheduled before the one it
+// follows, nor without it.
+func (c *BorConfig) CheckForkOrder() error {
+	forks := c.Forks()
+
+	blocks := make(map[string]*big.Int, len(forks))
+	for _, fork := range forks {
+		blocks[fork.Name] = fork.Block
+	}
+
+	for _, fork := range forks {
+		if fork.Follows == "" || fork.Block == nil {
+			continue
+		}
+
+		switch previous := blocks[fork.Follows]; {
+		case previous == nil:
+			return fmt.Errorf("unsupported bor fork ordering: %v not enabled, but %v enabled at block %v",
+				fork.Follows, fork.Name, fork.Block)
+		case previous.Cmp(fork.Block) > 0:
+			return fmt.Errorf("unsupported bor fork ordering: %v enabled at block %v, but %v enabled at block %v",
+				fork.Follows, previous, fork.Name, fork.Block)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the bor consensus parameters for the invariants the engine
+// relies on, so that a misconfigured chain is refused at startup instead of
+// misbehaving once the faulty parameter is hit.
+func (c *BorConfig) Validate() error {
+	if !common.IsHexAddress(c.ValidatorContract) {
+		return fmt.Errorf("invalid bor config: validatorContract %q is not an address, set it to the validator set contract of the chain", c.ValidatorContract)
+	}
+
+	if !common.IsHexAddress(c.StateReceiverContract) {
+		return fmt.Errorf("invalid bor config: stateReceiverContract %q is not an address, set it to the state receiver contract of the chain", c.StateReceiverContract)
+	}
+
+	// The config maps are keyed by the block their value applies from
+	checks := []error{
+		checkBorBlockKeys("period", c.Period, true),
+		checkBorBlockKeys("producerDelay", c.ProducerDelay, true),
+		checkBorBlockKeys("sprint", c.Sprint, true),
+		checkBorBlockKeys("backupMultiplier", c.BackupMultiplier, true),
+		checkBorBlockKeys("backupDelays", c.BackupDelays, false),
+		checkBorBlockKeys("backoffByStake", c.BackoffByStake, false),
+		checkBorBlockKeys("overrideStateSyncRecords", c.OverrideStateSyncRecords, false),
+		checkBorBlockKeys("burntContract", c.BurntContract, false),
+		checkBorBlockKeys("stateSyncConfirmationDelay", c.StateSyncConfirmationDelay, false),
+		checkBorBlockKeys("stateSyncGasLimit", c.StateSyncGasLimit, false),
+		checkBorBlockKeys("stateSyncSizeLimit", c.StateSyncSizeLimit, false),
+	}
+	for _, err := range checks {
+		if err != nil {
+			return err
+		}
+	}
+
+	// A sprint length switch must start a sprint of the new length, the sprint
+	// boundaries are derived from the block number alone
+	for key, sprint := range c.Sprint {
+		number, _ := strconv.ParseUint(key, 10, 64)
+
+		if sprint == 0 {
+			return fmt.Errorf("invalid bor config: sprint of 0 blocks from block %d, set a sprint length", number)
+		}
+
+		if number%sprint != 0 {
+			return fmt.Errorf("invalid bor config: sprint of %d blocks from block %d, which doesn't start a sprint, switch it at a multiple of %d", sprint, number, sprint)
+		}
+	}
+
+	for key, period := range c.Period {
+		if period == 0 {
+			return fmt.Errorf("invalid bor config: period of 0 seconds from block %s, set a block period", key)
+		}
+	}
+
+	// The first block of a sprint is sealed after the producer delay, in place
+	// of the period, and may not be sealed sooner than the other blocks
+	for key := range mergeBorBlockKeys(c.Period, c.ProducerDelay) {
+		number, _ := strconv.ParseUint(key, 10, 64)
+
+		if delay, period := c.CalculateProducerDelay(number), c.CalculatePeriod(number); delay < period {
+			return fmt.Errorf("invalid bor config: producer delay of %d seconds from block %d is below the period of %d seconds, raise it to at least the period", delay, number, period)
+		}
+	}
+
+	for key, multiplier := range c.BackupMultiplier {
+		if multiplier == 0 {
+			return fmt.Errorf("invalid bor config: backup multiplier of 0 from block %s lets the backup producers seal alongside the in-turn one, set it to at least 1", key)
+		}
+	}
+
+	for key, schedule := range c.BackupDelays {
+		for i := 1; i < len(schedule); i++ {
+			if schedule[i] < schedule[i-1] {
+				return fmt.Errorf("invalid bor config: backup delay of backup producer %d from block %s is below the one of backup producer %d, the delays must not decrease", i+1, key, i)
+			}
+		}
+	}
+
+	return c.CheckForkOrder()
+}
+
+// checkBorBlockKeys checks that a block number keyed config map is keyed by
+// block numbers, and that it applies from genesis if required.
+func checkBorBlockKeys[T any](name string, field map[string]T, required bool) error {
+	for key := range field {
+		if _, err := strconv.ParseUint(key, 10, 64); err != nil {
+			return fmt.Errorf("invalid bor config: %s is keyed by %q, which is not a block number", name, key)
+		}
+	}
+
+	if _, ok := field["0"]; required && !ok {
+		return fmt.Errorf("invalid bor config: %s has no value from block 0, add a \"0\" entry", name)
+	}
+
+	return nil
+}
+
+// mergeBorBlockKeys returns the keys of the given block number keyed config
+// maps, the blocks any of their values changes at.
+func mergeBorBlockKeys(fields ...map[string]uint64) map[string]struct{} {
+	keys := make(map[string]struct{})
+
+	for _, field := range fields {
+		for key := range field {
+			keys[key] = struct{}{}
+		}
+	}
+
+	return keys
+}
+
 func (c *BorConfig) CalculateProducerDelay(number uint64) uint64 {
 	return borKeyValueConfigHelper(c.ProducerDelay, number)
 }
@@ -737,6 +913,34 @@ func (c *BorConfig) CalculateBackupMultiplier(number uint64) uint64 {
 	return borKeyValueConfigHelper(c.BackupMultiplier, number)
 }
 
+// CalculateBackupDelay returns the wiggle time of the backup producer with the
+// given succession. Successions beyond the configured schedule extend its last
+// entry by the backup multiplier per step.
+func (c *BorConfig) CalculateBackupDelay(number uint64, succession int) uint64 {
+	if succession <= 0 {
+		return 0
+	}
+
+	multiplier := c.CalculateBackupMultiplier(number)
+
+	schedule := borSinceKeyConfigHelper(c.BackupDelays, number)
+	if len(schedule) == 0 {
+		return uint64(succession) * multiplier
+	}
+
+	if succession <= len(schedule) {
+		return schedule[succession-1]
+	}
+
+	return schedule[len(schedule)-1] + uint64(succession-len(schedule))*multiplier
+}
+
+// IsBackoffByStake reports whether the backup producers are ordered by
+// voting power at the given block.
+func (c *BorConfig) IsBackoffByStake(number uint64) bool {
+	return borSinceKeyConfigHelper(c.BackoffByStake, number)
+}
+
 func (c *BorConfig) CalculatePeriod(number uint64) uint64 {
 	return borKeyValueConfigHelper(c.Period, number)
 }
@@ -757,6 +961,62 @@ func (c *BorConfig) CalculateStateSyncDelay(number uint64) uint64 {
 	return borKeyValueConfigHelper(c.StateSyncConfirmationDelay, number)
 }
 
+// IsMilestoneRef reports whether the headers at the given block reference the
+// latest milestone observed by their producer.
+func (c *BorConfig) IsMilestoneRef(number *big.Int) bool {
+	return isBlockForked(c.MilestoneRefBlock, number)
+}
+
+// IsProducerCount reports whether the number of producers of the spans
+// committed at the given block is capped by the validator set contract.
+func (c *BorConfig) IsProducerCount(number *big.Int) bool {
+	return isBlockForked(c.ProducerCountBlock, number)
+}
+
+// IsMixDigest reports whether the mix digest of the headers at the given
+// block may carry consensus data instead of the empty hash.
+func (c *BorConfig) IsMixDigest(number *big.Int) bool {
+	return isBlockForked(c.MixDigestBlock, number)
+}
+
+// IsStrictExtra reports whether the validator set carried in the extra-data
+// of the sprint end headers at the given block is strictly validated.
+func (c *BorConfig) IsStrictExtra(number *big.Int) bool {
+	return isBlockForked(c.StrictExtraBlock, number)
+}
+
+// IsValidatorExtraV2 reports whether the sprint end headers at the given block
+// carry their validator set in the versioned v2 layout, including the BLS
+// public key of every validator.
+func (c *BorConfig) IsValidatorExtraV2(number *big.Int) bool {
+	return isBlockForked(c.ValidatorExtraV2Block, number)
+}
+
+// IsFeeCurrency reports whether the transaction fees at the given block are
+// paid through the fee currency of the chain rather than in the native token.
+func (c *BorConfig) IsFeeCurrency(number *big.Int) bool {
+	return isBlockForked(c.FeeCurrencyBlock, number)
+}
+
+// IsContiguousStateSync reports whether the state-sync events committed at the
+// given block are ordered by state id, deduplicated and cut before any id
+// missing from the events served by heimdall.
+func (c *BorConfig) IsContiguousStateSync(number *big.Int) bool {
+	return isBlockForked(c.ContiguousStateSyncBlock, number)
+}
+
+// CalculateStateSyncGasLimit returns the gas budget of the state-sync events
+// committed at the given sprint start block, or 0 if unlimited.
+func (c *BorConfig) CalculateStateSyncGasLimit(number uint64) uint64 {
+	return borSinceKeyConfigHelper(c.StateSyncGasLimit, number)
+}
+
+// CalculateStateSyncSizeLimit returns the data size budget of the state-sync
+// events committed at the given sprint start block, or 0 if unlimited.
+func (c *BorConfig) CalculateStateSyncSizeLimit(number uint64) uint64 {
+	return borSinceKeyConfigHelper(c.StateSyncSizeLimit, number)
+}
+
 func (c *BorConfig) IsAhmedabad(number *big.Int) bool {
 	return isBlockForked(c.AhmedabadBlock, number)
 }
@@ -772,11 +1032,98 @@ func (c *BorConfig) IsAhmedabad(number *big.Int) bool {
 // 	return isBlockForked(c.NapoliBlock, number)
 // }
 
+// IsSprintStart reports whether the given block starts a sprint. No block does
+// if no sprint length is configured.
 func (c *BorConfig) IsSprintStart(number uint64) bool {
-	return number%c.CalculateSprint(number) == 0
+	sprint := c.CalculateSprint(number)
+	if sprint == 0 {
+		return false
+	}
+
+	return number%sprint == 0
+}
+
+// CalculateSprintStart returns the first block of the sprint containing the
+// given block, or the block itself if no sprint length is configured.
+func (c *BorConfig) CalculateSprintStart(number uint64) uint64 {
+	sprint := c.CalculateSprint(number)
+	if sprint == 0 {
+		return number
+	}
+
+	return number - number%sprint
+}
+
+// CalculateSprintEnd returns the last block of the sprint containing the given
+// block, or the block itself if no sprint length is configured.
+func (c *BorConfig) CalculateSprintEnd(number uint64) uint64 {
+	sprint := c.CalculateSprint(number)
+	if sprint == 0 {
+		return number
+	}
+
+	return c.CalculateSprintStart(number) + sprint - 1
+}
+
+// CalculateSprintNumber returns the index of the sprint containing the given
+// block, counting the sprints of every sprint length configured before it.
+func (c *BorConfig) CalculateSprintNumber(number uint64) uint64 {
+	keys := make([]uint64, 0, len(c.Sprint))
+
+	for k := range c.Sprint {
+		keyUint, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			panic(err)
+		}
+
+		if keyUint <= number {
+			keys = append(keys, keyUint)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	// sprintStarts returns the number of sprint starts of the given length below x
+	sprintStarts := func(x, sprint uint64) uint64 {
+		return (x + sprint - 1) / sprint
+	}
+
+	var count uint64
+
+	for i, from := range keys {
+		to := number + 1
+		if i+1 < len(keys) {
+			to = keys[i+1]
+		}
+
+		// The first sprint length applies from genesis, even if keyed later
+		if i == 0 {
+			from = 0
+		}
+
+		sprint := c.CalculateSprint(keys[i])
+		if sprint == 0 {
+			continue
+		}
+
+		count += sprintStarts(to, sprint) - sprintStarts(from, sprint)
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return count - 1
 }
 
-func borKeyValueConfigHelper[T uint64 | string](field map[string]T, number uint64) T {
+// borKeyValueConfigHelper returns the value of a block number keyed config map
+// which is active at the given block, or the zero value if the map is empty.
+func borKeyValueConfigHelper[T uint64 | string | bool | []uint64](field map[string]T, number uint64) T {
+	if len(field) == 0 {
+		var zero T
+		return zero
+	}
+
 	keys := make([]uint64, 0, len(field))
 	fieldUint := make(map[uint64]T)
 
@@ -802,6 +1149,27 @@ func borKeyValueConfigHelper[T uint64 | string](field map[string]T, number uint6
 	return fieldUint[keys[len(keys)-1]]
 }
 
+// borSinceKeyConfigHelper returns the value of a block number keyed config map
+// which is active at the given block, or the zero value before its first key.
+// It's used by the maps of the behaviour changes switched on at a block, which
+// must not apply to the blocks before it.
+func borSinceKeyConfigHelper[T uint64 | string | bool | []uint64](field map[string]T, number uint64) T {
+	var zero T
+
+	for k := range field {
+		keyUint, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			panic(err)
+		}
+
+		if keyUint <= number {
+			return borKeyValueConfigHelper(field, number)
+		}
+	}
+
+	return zero
+}
+
 func (c *BorConfig) CalculateBurntContract(number uint64) string {
 	return borKeyValueConfigHelper(c.BurntContract, number)
 }
@@ -910,6 +1278,17 @@ func (c *ChainConfig) Description() string {
 	if c.VerkleBlock != nil {
 		banner += fmt.Sprintf(" - Verkle:                      @%-10v\n", *c.VerkleBlock)
 	}
+
+	if c.Bor != nil {
+		banner += "\n"
+		banner += "Bor hard forks (block based):\n"
+
+		for _, fork := range c.Bor.Forks() {
+			if fork.Block != nil {
+				banner += fmt.Sprintf(" - %-28v #%-8v\n", fork.Name+":", fork.Block)
+			}
+		}
+	}
 	return banner
 }
 
@@ -1113,6 +1492,17 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 		}
 	}
 
+	if c.Bor != nil {
+		if err := c.Bor.CheckForkOrder(); err != nil {
+			return err
+		}
+
+		// Milestone references are carried in the extra-data layout introduced by cancun
+		if ref := c.Bor.MilestoneRefBlock; ref != nil && (c.CancunBlock == nil || c.CancunBlock.Cmp(ref) > 0) {
+			return fmt.Errorf("unsupported bor fork ordering: milestoneRefBlock enabled at block %v before cancunBlock", ref)
+		}
+	}
+
 	return nil
 }
 
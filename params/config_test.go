@@ -193,4 +193,173 @@ func TestBorKeyValueConfigHelper(t *testing.T) {
 	assert.Equal(t, borKeyValueConfigHelper(burntContract, 41824608-1), "0x70bcA57F4579f58670aB2d18Ef16e02C17553C38")
 	assert.Equal(t, borKeyValueConfigHelper(burntContract, 41824608), "0x617b94CCCC2511808A3C9478ebb96f455CF167aA")
 	assert.Equal(t, borKeyValueConfigHelper(burntContract, 41824608+1), "0x617b94CCCC2511808A3C9478ebb96f455CF167aA")
+
+	// Empty maps yield the zero value, so that callers can fall back to defaults
+	assert.Equal(t, borKeyValueConfigHelper(map[string]uint64{}, 10), uint64(0))
+	assert.Equal(t, borKeyValueConfigHelper(map[string]string(nil), 10), "")
+}
+
+func TestBorCalculateSprint(t *testing.T) {
+	t.Parallel()
+
+	config := &BorConfig{
+		Sprint: map[string]uint64{
+			"0":   64,
+			"256": 16,
+		},
+	}
+
+	assert.Equal(t, uint64(64), config.CalculateSprint(0))
+	assert.Equal(t, uint64(64), config.CalculateSprint(255))
+	assert.Equal(t, uint64(16), config.CalculateSprint(256))
+	assert.Equal(t, uint64(16), config.CalculateSprint(1000))
+
+	assert.Assert(t, config.IsSprintStart(192))
+	assert.Assert(t, !config.IsSprintStart(208))
+	assert.Assert(t, config.IsSprintStart(272))
+	assert.Assert(t, !config.IsSprintStart(280))
+
+	// An unset sprint map doesn't panic, so that the engine can apply its default
+	assert.Equal(t, uint64(0), (&BorConfig{}).CalculateSprint(0))
+}
+
+func TestBorCalculateSprintNumber(t *testing.T) {
+	t.Parallel()
+
+	config := &BorConfig{
+		Sprint: map[string]uint64{
+			"0":   64,
+			"256": 16,
+		},
+	}
+
+	assert.Equal(t, uint64(192), config.CalculateSprintStart(200))
+	assert.Equal(t, uint64(255), config.CalculateSprintEnd(200))
+	assert.Equal(t, uint64(256), config.CalculateSprintStart(256))
+	assert.Equal(t, uint64(271), config.CalculateSprintEnd(256))
+
+	assert.Equal(t, uint64(0), config.CalculateSprintNumber(0))
+	assert.Equal(t, uint64(0), config.CalculateSprintNumber(63))
+	assert.Equal(t, uint64(1), config.CalculateSprintNumber(64))
+	assert.Equal(t, uint64(3), config.CalculateSprintNumber(255))
+	assert.Equal(t, uint64(4), config.CalculateSprintNumber(256))
+	assert.Equal(t, uint64(4), config.CalculateSprintNumber(271))
+	assert.Equal(t, uint64(5), config.CalculateSprintNumber(272))
+
+	assert.Equal(t, uint64(0), (&BorConfig{}).CalculateSprintNumber(100))
+
+	// Nor do the sprint bounds, which degenerate to the block itself
+	assert.Assert(t, !(&BorConfig{}).IsSprintStart(64))
+	assert.Equal(t, uint64(100), (&BorConfig{}).CalculateSprintStart(100))
+	assert.Equal(t, uint64(100), (&BorConfig{}).CalculateSprintEnd(100))
+}
+
+func TestBorCalculateBackupDelay(t *testing.T) {
+	t.Parallel()
+
+	config := &BorConfig{
+		BackupMultiplier: map[string]uint64{"0": 2},
+		BackupDelays:     map[string][]uint64{"0": {}, "100": {1, 3}},
+		BackoffByStake: map[string]bool{
+			"0":   false,
+			"100": true,
+		},
+	}
+
+	// Before the schedule applies, the wiggle is linear in the succession
+	assert.Equal(t, uint64(0), config.CalculateBackupDelay(0, 0))
+	assert.Equal(t, uint64(2), config.CalculateBackupDelay(0, 1))
+	assert.Equal(t, uint64(6), config.CalculateBackupDelay(99, 3))
+
+	// Afterwards it follows the schedule, extended by the multiplier
+	assert.Equal(t, uint64(0), config.CalculateBackupDelay(100, 0))
+	assert.Equal(t, uint64(1), config.CalculateBackupDelay(100, 1))
+	assert.Equal(t, uint64(3), config.CalculateBackupDelay(100, 2))
+	assert.Equal(t, uint64(5), config.CalculateBackupDelay(100, 3))
+	assert.Equal(t, uint64(7), config.CalculateBackupDelay(100, 4))
+
+	assert.Assert(t, !config.IsBackoffByStake(99))
+	assert.Assert(t, config.IsBackoffByStake(100))
+
+	// A schedule switched on at a block doesn't apply to the blocks before it
+	config.BackupDelays = map[string][]uint64{"100": {1, 3}}
+	config.BackoffByStake = map[string]bool{"100": true}
+
+	assert.Equal(t, uint64(6), config.CalculateBackupDelay(99, 3))
+	assert.Equal(t, uint64(5), config.CalculateBackupDelay(100, 3))
+	assert.Assert(t, !config.IsBackoffByStake(99))
+	assert.Assert(t, config.IsBackoffByStake(100))
+}
+
+func TestBorCheckForkOrder(t *testing.T) {
+	t.Parallel()
+
+	for _, config := range []*ChainConfig{BorMainnetChainConfig, AmoyChainConfig, MumbaiChainConfig} {
+		assert.NilError(t, config.CheckConfigForkOrder())
+	}
+
+	// Forks of the upgrade sequence can coincide, but not go backwards
+	config := &BorConfig{JaipurBlock: big.NewInt(10), DelhiBlock: big.NewInt(10), IndoreBlock: big.NewInt(20)}
+	assert.NilError(t, config.CheckForkOrder())
+
+	config.IndoreBlock = big.NewInt(5)
+	assert.ErrorContains(t, config.CheckForkOrder(), "delhiBlock enabled at block 10, but indoreBlock enabled at block 5")
+
+	config.IndoreBlock, config.DelhiBlock = big.NewInt(20), nil
+	assert.ErrorContains(t, config.CheckForkOrder(), "delhiBlock not enabled, but indoreBlock enabled at block 20")
+
+	// Behaviour changes are scheduled independently
+	assert.NilError(t, (&BorConfig{StrictExtraBlock: big.NewInt(10), MixDigestBlock: big.NewInt(5)}).CheckForkOrder())
+
+	// Milestone references need the extra-data layout of cancun
+	chain, bor := *AmoyChainConfig, *AmoyChainConfig.Bor
+	chain.Bor, bor.MilestoneRefBlock = &bor, big.NewInt(5423599)
+	assert.ErrorContains(t, chain.CheckConfigForkOrder(), "milestoneRefBlock enabled at block 5423599 before cancunBlock")
+
+	bor.MilestoneRefBlock = chain.CancunBlock
+	assert.NilError(t, chain.CheckConfigForkOrder())
+}
+
+func TestBorConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, config := range []*ChainConfig{BorMainnetChainConfig, AmoyChainConfig, MumbaiChainConfig} {
+		assert.NilError(t, config.Bor.Validate())
+	}
+
+	valid := func() *BorConfig {
+		return &BorConfig{
+			Period:                map[string]uint64{"0": 2},
+			ProducerDelay:         map[string]uint64{"0": 6, "64": 4},
+			Sprint:                map[string]uint64{"0": 64, "256": 16},
+			BackupMultiplier:      map[string]uint64{"0": 2},
+			ValidatorContract:     "0x0000000000000000000000000000000000001000",
+			StateReceiverContract: "0x0000000000000000000000000000000000001001",
+		}
+	}
+	assert.NilError(t, valid().Validate())
+
+	tests := []struct {
+		mutate func(c *BorConfig)
+		err    string
+	}{
+		{func(c *BorConfig) { c.ValidatorContract = "" }, `validatorContract "" is not an address`},
+		{func(c *BorConfig) { c.StateReceiverContract = "0x1001" }, `stateReceiverContract "0x1001" is not an address`},
+		{func(c *BorConfig) { c.Period = map[string]uint64{"10": 2} }, `period has no value from block 0`},
+		{func(c *BorConfig) { c.BurntContract = map[string]string{"latest": "0xdead"} }, `burntContract is keyed by "latest"`},
+		{func(c *BorConfig) { c.Sprint["512"] = 0 }, "sprint of 0 blocks from block 512"},
+		{func(c *BorConfig) { c.Sprint["256"] = 100 }, "sprint of 100 blocks from block 256, which doesn't start a sprint"},
+		{func(c *BorConfig) { c.Period["0"] = 0 }, "period of 0 seconds from block 0"},
+		{func(c *BorConfig) { c.Period["128"] = 5 }, "producer delay of 4 seconds from block 128 is below the period of 5 seconds"},
+		{func(c *BorConfig) { c.BackupMultiplier["0"] = 0 }, "backup multiplier of 0 from block 0"},
+		{func(c *BorConfig) { c.BackupDelays = map[string][]uint64{"0": {4, 2}} }, "backup delay of backup producer 2 from block 0 is below the one of backup producer 1"},
+		{func(c *BorConfig) { c.JaipurBlock, c.DelhiBlock = big.NewInt(20), big.NewInt(10) }, "jaipurBlock enabled at block 20, but delhiBlock enabled at block 10"},
+	}
+
+	for _, test := range tests {
+		config := valid()
+		test.mutate(config)
+
+		assert.ErrorContains(t, config.Validate(), test.err)
+	}
 }
@@ -0,0 +1,60 @@
+package rpc
+
+import "strings"
+
+// defaultMethodCost is the cost of a call to a method without an entry in
+// methodCosts.
+const defaultMethodCost = 1
+
+// methodCosts assigns a relative cost to methods which are considerably more
+// expensive to serve than a plain state or chain lookup. It is used to bound
+// the cumulative cost of a batch, so that a batch of a few hundred traces is
+// not treated the same as a batch of a few hundred balance lookups.
+var methodCosts = map[string]int{
+	"eth_call":                          5,
+	"eth_estimateGas":                   5,
+	"eth_createAccessList":              5,
+	"eth_getLogs":                       10,
+	"eth_getBlockReceipts":              10,
+	"eth_getTransactionReceiptsByBlock": 10,
+	"eth_getProof":                      10,
+	"bor_getRootHash":                   10,
+	"debug_traceCall":                   20,
+	"debug_traceTransaction":            20,
+}
+
+// methodCostPrefixes assigns a cost to every method of a namespace which is
+// not listed in methodCosts.
+var methodCostPrefixes = map[string]int{
+	"debug_trace": 50,
+	"trace_":      50,
+}
+
+// methodCost returns the relative cost of a call to the given method.
+func methodCost(method string) int {
+	if cost, ok := methodCosts[method]; ok {
+		return cost
+	}
+
+	for prefix, cost := range methodCostPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return cost
+		}
+	}
+
+	return defaultMethodCost
+}
+
+// batchCost returns the cumulative cost of the calls in a batch. Responses and
+// notifications are free.
+func batchCost(msgs []*jsonrpcMessage) int {
+	var cost int
+
+	for _, msg := range msgs {
+		if msg.isCall() {
+			cost += methodCost(msg.Method)
+		}
+	}
+
+	return cost
+}
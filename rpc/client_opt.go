@@ -41,6 +41,7 @@ type clientConfig struct {
 	idgen              func() ID
 	batchItemLimit     int
 	batchResponseLimit int
+	batchCostLimit     int
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -303,6 +303,49 @@ func TestClientBatchRequestLimit(t *testing.T) {
 	}
 }
 
+// This checks that batches exceeding the cost limit are rejected as a whole,
+// while cheaper batches of the same size are served.
+func TestClientBatchCostLimit(t *testing.T) {
+	server := newTestServer()
+	defer server.Stop()
+	server.SetBatchCostLimit(methodCost("eth_getLogs"))
+	client := DialInProc(server)
+	defer client.Close()
+
+	cheap := []BatchElem{
+		{Method: "test_echo", Args: []interface{}{"x", 1}, Result: new(echoResult)},
+		{Method: "test_echo", Args: []interface{}{"x", 2}, Result: new(echoResult)},
+	}
+	if err := client.BatchCall(cheap); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	for i, elem := range cheap {
+		if elem.Error != nil {
+			t.Fatalf("batch elem %d has unexpected error: %v", i, elem.Error)
+		}
+	}
+
+	expensive := []BatchElem{
+		{Method: "eth_getLogs"},
+		{Method: "test_echo", Args: []interface{}{"x", 1}, Result: new(echoResult)},
+	}
+	if err := client.BatchCall(expensive); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+
+	// Check that the first response indicates an error with batch cost.
+	var err0 Error
+	if !errors.As(expensive[0].Error, &err0) {
+		t.Fatalf("batch elem 0 has wrong error type: %T", expensive[0].Error)
+	}
+	if err0.ErrorCode() != -32600 || err0.Error() != errMsgBatchCostTooLarge {
+		t.Fatalf("wrong error on batch elem zero: %v", err0)
+	}
+	if expensive[1].Error != ErrMissingBatchResponse {
+		t.Fatalf("batch elem 1 has unexpected error: %v", expensive[1].Error)
+	}
+}
+
 func TestClientNotify(t *testing.T) {
 	server := newTestServer()
 	defer server.Stop()
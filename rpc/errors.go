@@ -70,9 +70,10 @@ const (
 )
 
 const (
-	errMsgTimeout          = "request timed out"
-	errMsgResponseTooLarge = "response too large"
-	errMsgBatchTooLarge    = "batch too large"
+	errMsgTimeout           = "request timed out"
+	errMsgResponseTooLarge  = "response too large"
+	errMsgBatchTooLarge     = "batch too large"
+	errMsgBatchCostTooLarge = "batch cost too large"
 )
 
 type methodNotFoundError struct{ method string }
@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"sync"
+)
+
+// fairScheduler bounds the number of calls running concurrently and divides
+// the slots between the peers submitting them. A peer may hold at most its
+// fair share, i.e. the number of slots divided by the number of peers with
+// calls running or waiting, so that a single client sending large batches
+// can't occupy every slot while the calls of other clients queue up behind
+// it. A peer submitting alone can still use all slots.
+type fairScheduler struct {
+	mu      sync.Mutex
+	slots   int            // number of calls allowed to run concurrently
+	total   int            // running calls of all peers
+	running map[string]int // running calls per peer
+	waiting map[string]int // calls waiting for a slot per peer
+	wake    chan struct{}  // closed and replaced whenever the shares may have changed
+}
+
+func newFairScheduler(slots int) *fairScheduler {
+	return &fairScheduler{
+		slots:   slots,
+		running: make(map[string]int),
+		waiting: make(map[string]int),
+		wake:    make(chan struct{}),
+	}
+}
+
+// share returns the number of slots a single peer may hold. The caller must
+// hold f.mu.
+func (f *fairScheduler) share() int {
+	peers := len(f.running)
+
+	for peer := range f.waiting {
+		if _, ok := f.running[peer]; !ok {
+			peers++
+		}
+	}
+
+	if peers == 0 {
+		peers = 1
+	}
+
+	share := f.slots / peers
+	if share < 1 {
+		share = 1
+	}
+
+	return share
+}
+
+// acquire blocks until the given peer may run another call or ctx is done.
+// It reports whether a slot was acquired, in which case the caller must
+// release it once the call has finished.
+func (f *fairScheduler) acquire(ctx context.Context, peer string) bool {
+	f.mu.Lock()
+	f.waiting[peer]++
+
+	for f.total >= f.slots || f.running[peer] >= f.share() {
+		wake := f.wake
+		f.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			// Giving up may raise the share of the other peers.
+			f.mu.Lock()
+			f.leave(peer)
+			f.broadcast()
+			f.mu.Unlock()
+
+			return false
+		}
+
+		f.mu.Lock()
+	}
+
+	f.leave(peer)
+	f.running[peer]++
+	f.total++
+	f.mu.Unlock()
+
+	return true
+}
+
+// leave removes a waiting call of the given peer. The caller must hold f.mu.
+func (f *fairScheduler) leave(peer string) {
+	if f.waiting[peer]--; f.waiting[peer] <= 0 {
+		delete(f.waiting, peer)
+	}
+}
+
+// release returns a slot acquired by the given peer.
+func (f *fairScheduler) release(peer string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.total--
+	if f.running[peer]--; f.running[peer] <= 0 {
+		delete(f.running, peer)
+	}
+
+	f.broadcast()
+}
+
+// broadcast wakes up all calls waiting for a slot. The caller must hold f.mu.
+func (f *fairScheduler) broadcast() {
+	close(f.wake)
+	f.wake = make(chan struct{})
+}
+
+// schedulerPeer returns the key calls of a connection are scheduled under.
+// Connections from the same host share their fair share of the pool.
+func schedulerPeer(info PeerInfo) string {
+	if host, _, err := net.SplitHostPort(info.RemoteAddr); err == nil {
+		return host
+	}
+
+	return info.RemoteAddr
+}
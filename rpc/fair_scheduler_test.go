@@ -0,0 +1,79 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFairSchedulerShare(t *testing.T) {
+	t.Parallel()
+
+	f := newFairScheduler(4)
+	ctx := context.Background()
+
+	// A peer submitting alone can use every slot.
+	for i := 0; i < 4; i++ {
+		if !f.acquire(ctx, "indexer") {
+			t.Fatalf("acquire %d failed", i)
+		}
+	}
+
+	// Another peer has to wait for a slot to be released, after which the
+	// first peer can't reacquire it as it's above its share.
+	acquired := make(chan struct{})
+
+	go func() {
+		if f.acquire(ctx, "heimdall") {
+			close(acquired)
+		}
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquired a slot while all slots are in use")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	f.release("indexer")
+
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("waiting peer didn't get the released slot")
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	if f.acquire(timeout, "indexer") {
+		t.Fatal("acquired a slot above the fair share")
+	}
+
+	// Once the other peer is done, the whole pool is available again.
+	f.release("heimdall")
+
+	if !f.acquire(ctx, "indexer") {
+		t.Fatal("acquire failed after the other peer released its slot")
+	}
+}
+
+func TestSchedulerPeer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"127.0.0.1:8545", "127.0.0.1"},
+		{"[::1]:8545", "::1"},
+		{"pipe", "pipe"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		if got := schedulerPeer(PeerInfo{RemoteAddr: test.addr}); got != test.want {
+			t.Errorf("schedulerPeer(%q) = %q, want %q", test.addr, got, test.want)
+		}
+	}
+}
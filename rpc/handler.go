@@ -66,11 +66,14 @@ type handler struct {
 	allowSubscribe       bool
 	batchRequestLimit    int
 	batchResponseMaxSize int
+	batchCostLimit       int // maximum cumulative cost of the calls in a batch, 0 for no limit
 
 	subLock    sync.Mutex
 	serverSubs map[ID]*Subscription
 
 	executionPool *SafePool
+	scheduler     *fairScheduler // divides call slots between peers, nil if disabled
+	schedulerPeer string         // key the calls of this handler are scheduled under
 }
 
 type callProc struct {
@@ -193,7 +196,14 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	// Apply limit on total number of requests.
 	if h.batchRequestLimit != 0 && len(msgs) > h.batchRequestLimit {
 		h.startCallProc(func(cp *callProc) {
-			h.respondWithBatchTooLarge(cp, msgs)
+			h.respondWithBatchTooLarge(cp, msgs, errMsgBatchTooLarge)
+		})
+		return
+	}
+	// Apply limit on the cumulative cost of the calls.
+	if h.batchCostLimit != 0 && batchCost(msgs) > h.batchCostLimit {
+		h.startCallProc(func(cp *callProc) {
+			h.respondWithBatchTooLarge(cp, msgs, errMsgBatchCostTooLarge)
 		})
 		return
 	}
@@ -266,8 +276,8 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 	})
 }
 
-func (h *handler) respondWithBatchTooLarge(cp *callProc, batch []*jsonrpcMessage) {
-	resp := errorMessage(&invalidRequestError{errMsgBatchTooLarge})
+func (h *handler) respondWithBatchTooLarge(cp *callProc, batch []*jsonrpcMessage, message string) {
+	resp := errorMessage(&invalidRequestError{message})
 	// Find the first call and add its "id" field to the error.
 	// This is the best we can do, given that the protocol doesn't have a way
 	// of reporting an error for the entire batch.
@@ -405,10 +415,22 @@ func (h *handler) startCallProc(fn func(*callProc)) {
 
 	ctx, cancel := context.WithCancel(h.rootCtx)
 
+	// Wait for a call slot if they are shared between peers.
+	if h.scheduler != nil && !h.scheduler.acquire(ctx, h.schedulerPeer) {
+		cancel()
+		h.callWG.Done()
+
+		return
+	}
+
 	h.executionPool.Submit(context.Background(), func() error {
 		defer h.callWG.Done()
 		defer cancel()
 
+		if h.scheduler != nil {
+			defer h.scheduler.release(h.schedulerPeer)
+		}
+
 		fn(&callProc{ctx: ctx})
 
 		h.executionPool.processed.Add(1)
@@ -133,8 +133,10 @@ func errorMessage(err error) *jsonrpcMessage {
 		msg.Error.Code = ec.ErrorCode()
 	}
 
-	de, ok := err.(DataError)
-	if ok {
+	// Data is looked up through wrapped errors too, so that the context carried
+	// by errors from deeper layers reaches the client
+	var de DataError
+	if errors.As(err, &de) {
 		msg.Error.Data = de.ErrorData()
 	}
 
@@ -57,7 +57,10 @@ type Server struct {
 
 	batchItemLimit     int
 	batchResponseLimit int
+	batchCostLimit     int
 	httpBodyLimit      int
+
+	scheduler *fairScheduler
 }
 
 // NewServer creates a new server instance with no registered handlers.
@@ -114,6 +117,30 @@ func (s *Server) SetBatchLimits(itemLimit, maxResponseSize int) {
 	s.batchResponseLimit = maxResponseSize
 }
 
+// SetBatchCostLimit sets the maximum cumulative cost of the calls in a batch,
+// where every call costs according to the method called. Batches exceeding it
+// are rejected without executing any call. Use 0 for no limit.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+func (s *Server) SetBatchCostLimit(limit int) {
+	s.batchCostLimit = limit
+}
+
+// SetFairScheduling limits the number of calls received over HTTP which run
+// concurrently to 'slots' and divides them fairly between the hosts sending
+// requests, so that a host can't hold more than its share while other hosts
+// are waiting. Use 0 to disable.
+//
+// This method should be called before processing any requests via ServeHTTP.
+func (s *Server) SetFairScheduling(slots int) {
+	if slots > 0 {
+		s.scheduler = newFairScheduler(slots)
+	} else {
+		s.scheduler = nil
+	}
+}
+
 // SetHTTPBodyLimit sets the size limit for HTTP requests.
 //
 // This method should be called before processing any requests via ServeHTTP.
@@ -129,6 +156,23 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 	return s.services.registerName(name, receiver)
 }
 
+// Methods returns the names of the methods served, in the "namespace_method"
+// form. The subscriptions of a namespace are served through its subscribe
+// method.
+func (s *Server) Methods() []string {
+	return s.services.methods()
+}
+
+// FilterMethods stops serving the methods keep returns false for, given their
+// name in the "namespace_method" form. Subscriptions are served or not along
+// with the subscribe method of their namespace.
+//
+// This method should be called after registering the services and before
+// processing any requests via ServeCodec, ServeHTTP, ServeListener etc.
+func (s *Server) FilterMethods(keep func(method string) bool) {
+	s.services.filter(keep)
+}
+
 // ServeCodec reads incoming requests from codec, calls the appropriate callback and writes
 // the response back using the given codec. It will block until the codec is closed or the
 // server is stopped. In either case the codec is closed.
@@ -146,6 +190,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		batchCostLimit:     s.batchCostLimit,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed()
@@ -182,6 +227,9 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 	}
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.executionPool, s.batchItemLimit, s.batchResponseLimit)
+	h.batchCostLimit = s.batchCostLimit
+	h.scheduler = s.scheduler
+	h.schedulerPeer = schedulerPeer(PeerInfoFromContext(ctx))
 
 	h.allowSubscribe = false
 	defer h.close(io.EOF, nil)
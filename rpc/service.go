@@ -116,6 +116,51 @@ func (r *serviceRegistry) subscription(service, name string) *callback {
 	return r.services[service].subscriptions[name]
 }
 
+// methods returns the names of the registered methods. The subscriptions of a
+// service are listed as its subscribe method.
+func (r *serviceRegistry) methods() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var methods []string
+
+	for name, svc := range r.services {
+		for method := range svc.callbacks {
+			methods = append(methods, name+serviceMethodSeparator+method)
+		}
+
+		if len(svc.subscriptions) > 0 {
+			methods = append(methods, name+subscribeMethodSuffix)
+		}
+	}
+
+	return methods
+}
+
+// filter removes the registered methods keep returns false for, along with
+// the services left without methods. The subscriptions of a service are kept
+// or removed along with its subscribe method.
+func (r *serviceRegistry) filter(keep func(method string) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, svc := range r.services {
+		for method := range svc.callbacks {
+			if !keep(name + serviceMethodSeparator + method) {
+				delete(svc.callbacks, method)
+			}
+		}
+
+		if len(svc.subscriptions) > 0 && !keep(name+subscribeMethodSuffix) {
+			clear(svc.subscriptions)
+		}
+
+		if len(svc.callbacks) == 0 && len(svc.subscriptions) == 0 {
+			delete(r.services, name)
+		}
+	}
+}
+
 // suitableCallbacks iterates over the methods of the given type. It determines if a method
 // satisfies the criteria for an RPC callback or a subscription callback and adds it to the
 // collection of callbacks. See server documentation for a summary of these criteria.
@@ -306,6 +306,10 @@ var (
 		accounts.MimetypeClique,
 		0x02,
 	}
+	ApplicationBor = SigFormat{
+		accounts.MimetypeBor,
+		0x02,
+	}
 	TextPlain = SigFormat{
 		accounts.MimetypeTextPlain,
 		0x45,
@@ -26,9 +26,11 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/clique"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
@@ -175,6 +177,66 @@ func (api *SignerAPI) determineSignatureFormat(ctx context.Context, contentType
 		// Clique uses V on the form 0 or 1
 		useEthereumV = false
 		req = &SignDataRequest{ContentType: mediaType, Rawdata: cliqueRlp, Messages: messages, Hash: sighash}
+	case apitypes.ApplicationBor.Mime:
+		// Bor headers are sealed like clique ones, with the base fee appended to
+		// the sealing encoding from Jaipur on
+		borData, err := fromHex(data)
+		if err != nil {
+			return nil, useEthereumV, err
+		}
+
+		header := &types.Header{}
+		if err := rlp.DecodeBytes(borData, header); err != nil {
+			return nil, useEthereumV, err
+		}
+		// Add space in the extradata to put the signature
+		newExtra := make([]byte, len(header.Extra)+65)
+		copy(newExtra, header.Extra)
+		header.Extra = newExtra
+
+		// Get back the rlp data, encoded by us
+		sighash, borRlp := borHeaderHashAndRlp(header)
+
+		messages := []*apitypes.NameValueType{
+			{
+				Name:  "Bor header",
+				Typ:   "bor",
+				Value: fmt.Sprintf("bor header %d [%#x]", header.Number, sighash),
+			},
+			{
+				Name:  "Parent hash",
+				Typ:   "hash",
+				Value: header.ParentHash.Hex(),
+			},
+			{
+				Name:  "Timestamp",
+				Typ:   "uint64",
+				Value: fmt.Sprintf("%d", header.Time),
+			},
+			{
+				Name:  "State root",
+				Typ:   "hash",
+				Value: header.Root.Hex(),
+			},
+			{
+				Name:  "Transactions root",
+				Typ:   "hash",
+				Value: header.TxHash.Hex(),
+			},
+			{
+				Name:  "Gas",
+				Typ:   "uint64",
+				Value: fmt.Sprintf("%d used of %d", header.GasUsed, header.GasLimit),
+			},
+			{
+				Name:  "Difficulty",
+				Typ:   "uint256",
+				Value: header.Difficulty.String(),
+			},
+		}
+		// Bor uses V on the form 0 or 1
+		useEthereumV = false
+		req = &SignDataRequest{ContentType: mediaType, Rawdata: borRlp, Messages: messages, Hash: sighash}
 	case apitypes.DataTyped.Mime:
 		// EIP-712 conformant typed data
 		var err error
@@ -236,6 +298,15 @@ func cliqueHeaderHashAndRlp(header *types.Header) (hash, rlp []byte, err error)
 	return hash, rlp, err
 }
 
+// borHeaderHashAndRlp returns the seal hash and the sealing encoding of a bor
+// header. The encoding includes the base fee from Jaipur on, which the node
+// sends only then, so it's included whenever set.
+func borHeaderHashAndRlp(header *types.Header) (hash, rlp []byte) {
+	config := &params.BorConfig{JaipurBlock: common.Big0}
+
+	return bor.SealHash(header, config).Bytes(), bor.BorRLP(header, config)
+}
+
 // SignTypedData signs EIP-712 conformant typed data
 // hash = keccak256("\x19${byteVersion}${domainSeparator}${hashStruct(message)}")
 // It returns
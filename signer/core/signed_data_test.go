@@ -31,7 +31,10 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus/bor"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/signer/core"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
@@ -259,6 +262,55 @@ func TestSignData(t *testing.T) {
 	}
 }
 
+func TestSignBorHeader(t *testing.T) {
+	t.Parallel()
+	api, control := setup(t)
+	createAccount(control, api, t)
+	control.approveCh <- "A"
+
+	list, err := api.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := common.NewMixedcaseAddress(list[0])
+
+	// The sealing encodings of bor headers, as sent by the node before and
+	// after Jaipur
+	config := &params.BorConfig{JaipurBlock: big.NewInt(2000)}
+
+	for _, header := range []*types.Header{
+		{Number: big.NewInt(1234), Difficulty: big.NewInt(1), GasLimit: 30_000_000, Time: 5678, Extra: make([]byte, 32+65)},
+		{Number: big.NewInt(4321), Difficulty: big.NewInt(1), GasLimit: 30_000_000, Time: 8765, Extra: make([]byte, 32+65), BaseFee: big.NewInt(7)},
+	} {
+		control.approveCh <- "Y"
+		control.inputCh <- "a_long_password"
+
+		signature, err := api.SignData(context.Background(), apitypes.ApplicationBor.Mime, a, hexutil.Encode(bor.BorRLP(header, config)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(signature) != 65 || signature[64] > 1 {
+			t.Fatalf("Expected 65 byte signature with V on the form 0 or 1, got %x", signature)
+		}
+
+		pubkey, err := crypto.SigToPub(bor.SealHash(header, config).Bytes(), signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if signer := crypto.PubkeyToAddress(*pubkey); signer != list[0] {
+			t.Errorf("Expected header %d sealed by %v, got %v", header.Number, list[0], signer)
+		}
+	}
+
+	// Data other than a bor header is refused
+	if _, err := api.SignData(context.Background(), apitypes.ApplicationBor.Mime, a, hexutil.Encode([]byte("EHLO world"))); err == nil {
+		t.Error("Expected error signing malformed bor header")
+	}
+}
+
 func TestDomainChainId(t *testing.T) {
 	t.Parallel()
 	withoutChainID := apitypes.TypedData{
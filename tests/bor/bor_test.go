@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math/big"
@@ -23,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/bor"
 	"github.com/ethereum/go-ethereum/consensus/bor/clerk"
 	"github.com/ethereum/go-ethereum/consensus/bor/heimdall/checkpoint"
@@ -35,11 +37,13 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/tests/bor/mocks"
 	"github.com/ethereum/go-ethereum/triedb"
 )
@@ -508,6 +512,117 @@ func TestFetchStateSyncEvents(t *testing.T) {
 	insertNewBlock(t, chain, block)
 }
 
+// TestStateSyncEventIndex imports a chain across a sprint boundary with mocked
+// state-sync events, and checks that the committed events are indexed and served
+// by the bor state-sync RPCs until the block is rewound.
+func TestStateSyncEventIndex(t *testing.T) {
+	t.Parallel()
+	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelInfo, true)))
+	fdlimit.Raise(2048)
+
+	init := buildEthereumInstance(t, rawdb.NewMemoryDatabase())
+	chain := init.ethereum.BlockChain()
+	engine := init.ethereum.Engine()
+	_bor := engine.(*bor.Bor)
+
+	defer _bor.Close()
+
+	block := init.genesis.ToBlock()
+
+	res, _ := loadSpanFromFile(t)
+
+	currentValidators := []*valset.Validator{valset.NewValidator(addr, 10)}
+
+	spanner := getMockedSpanner(t, currentValidators)
+	_bor.SetSpanner(spanner)
+
+	for i := uint64(1); i < sprintSize; i++ {
+		if IsSpanEnd(i) {
+			currentValidators = res.Result.ValidatorSet.Validators
+		}
+
+		block = buildNextBlock(t, _bor, chain, block, nil, init.genesis.Config.Bor, nil, currentValidators)
+		insertNewBlock(t, chain, block)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := mocks.NewMockIHeimdallClient(ctrl)
+	h.EXPECT().Close().AnyTimes()
+	h.EXPECT().Span(gomock.Any(), uint64(1)).Return(&res.Result, nil).AnyTimes()
+	h.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(&checkpoint.Checkpoint{}, nil).AnyTimes()
+	h.EXPECT().FetchMilestone(gomock.Any()).Return(&milestone.Milestone{}, nil).AnyTimes()
+	h.EXPECT().FetchLastNoAckMilestone(gomock.Any()).Return("", nil).AnyTimes()
+	h.EXPECT().FetchNoAckMilestone(gomock.Any(), string("test")).Return(nil).AnyTimes()
+
+	to := int64(chain.GetHeaderByNumber(0).Time)
+	eventCount := 5
+
+	sample := getSampleEventRecord(t)
+	sample.Time = time.Unix(to-int64(eventCount+1), 0)
+	eventRecords := generateFakeStateSyncEvents(sample, eventCount)
+
+	h.EXPECT().StateSyncEvents(gomock.Any(), uint64(1), to).Return(eventRecords, nil).AnyTimes()
+	_bor.SetHeimdallClient(h)
+
+	block = buildNextBlock(t, _bor, chain, block, nil, init.genesis.Config.Bor, nil, res.Result.ValidatorSet.Validators)
+	insertNewBlock(t, chain, block)
+
+	ctx := context.Background()
+	api := ethapi.NewBorAPI(init.ethereum.APIBackend)
+
+	// The sprint start block serves all the committed events
+	events, err := api.GetStateSyncEventsByBlock(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(sprintSize)))
+	require.NoError(t, err)
+	require.Len(t, events, eventCount)
+
+	for i, event := range events {
+		require.Equal(t, eventRecords[i].ID, uint64(event.ID))
+		require.Equal(t, eventRecords[i].Contract, event.Contract)
+		require.Equal(t, block.Hash(), event.BlockHash)
+		require.Equal(t, sprintSize, uint64(event.BlockNumber))
+	}
+
+	// Non sprint start blocks don't commit any events
+	events, err = api.GetStateSyncEventsByBlock(ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(sprintSize-1)))
+	require.NoError(t, err)
+	require.Empty(t, events)
+	require.False(t, rawdb.HasBorStateSyncEvents(init.ethereum.ChainDb(), block.ParentHash(), sprintSize-1))
+
+	// Events are served by id, with the expected json fields
+	event, err := api.GetStateSyncEventById(ctx, eventRecords[2].ID)
+	require.NoError(t, err)
+	require.NotNil(t, event)
+	require.Equal(t, eventRecords[2].ID, uint64(event.ID))
+	require.Equal(t, block.Hash(), event.BlockHash)
+
+	blob, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(blob, &fields))
+
+	for _, field := range []string{"id", "contract", "data", "txHash", "blockHash", "blockNumber"} {
+		require.Contains(t, fields, field)
+	}
+
+	require.Len(t, fields, 6)
+	require.Equal(t, hexutil.Uint64(sprintSize).String(), fields["blockNumber"])
+
+	event, err = api.GetStateSyncEventById(ctx, uint64(eventCount+1))
+	require.NoError(t, err)
+	require.Nil(t, event)
+
+	// Rewinding the chain drops the indexed events
+	require.NoError(t, chain.SetHead(sprintSize-1))
+	require.False(t, rawdb.HasBorStateSyncEvents(init.ethereum.ChainDb(), block.Hash(), sprintSize))
+
+	event, err = api.GetStateSyncEventById(ctx, eventRecords[2].ID)
+	require.NoError(t, err)
+	require.Nil(t, event)
+}
+
 func validateStateSyncEvents(t *testing.T, expected []*clerk.EventRecordWithTime, got []*types.StateSyncData) {
 	require.Equal(t, len(expected), len(got), "number of state sync events should be equal")
 
@@ -589,7 +704,7 @@ func TestFetchStateSyncEvents_2(t *testing.T) {
 		insertNewBlock(t, chain, block)
 	}
 
-	lastStateID, _ := _bor.GenesisContractsClient.LastStateId(nil, sprintSize, block.Hash())
+	lastStateID, _ := _bor.GenesisContractsClient.LastStateId(context.Background(), nil, sprintSize, block.Hash())
 
 	// state 6 was not written
 	require.Equal(t, uint64(4), lastStateID.Uint64())
@@ -618,7 +733,7 @@ func TestFetchStateSyncEvents_2(t *testing.T) {
 		insertNewBlock(t, chain, block)
 	}
 
-	lastStateID, _ = _bor.GenesisContractsClient.LastStateId(nil, spanSize, block.Hash())
+	lastStateID, _ = _bor.GenesisContractsClient.LastStateId(context.Background(), nil, spanSize, block.Hash())
 	require.Equal(t, uint64(6), lastStateID.Uint64())
 }
 
@@ -692,9 +807,13 @@ func TestOutOfTurnSigning(t *testing.T) {
 
 	block = buildNextBlock(t, _bor, chain, block, signerKey, init.genesis.Config.Bor, nil, heimdallSpan.ValidatorSet.Validators, setParentTime, setDifficulty)
 	_, err := chain.InsertChain([]*types.Block{block})
+
+	var tooSoonErr *bor.BlockTooSoonError
+
+	require.ErrorAs(t, err, &tooSoonErr)
 	require.Equal(t,
 		bor.BlockTooSoonError{Number: spanSize, Succession: expectedSuccessionNumber},
-		*err.(*bor.BlockTooSoonError))
+		*tooSoonErr)
 
 	expectedDifficulty := uint64(len(heimdallSpan.ValidatorSet.Validators) - expectedSuccessionNumber - turn) // len(validators) - succession
 	header := block.Header()
@@ -708,9 +827,21 @@ func TestOutOfTurnSigning(t *testing.T) {
 
 	_, err = chain.InsertChain([]*types.Block{block})
 	require.NotNil(t, err)
+
+	var difficultyErr *bor.WrongDifficultyError
+
+	require.ErrorAs(t, err, &difficultyErr)
 	require.Equal(t,
 		bor.WrongDifficultyError{Number: spanSize, Expected: expectedDifficulty, Actual: 3, Signer: newAddr.Bytes()},
-		*err.(*bor.WrongDifficultyError))
+		*difficultyErr)
+
+	// The rejection carries the context of the block
+	var sealErr *bor.SealError
+
+	require.ErrorAs(t, err, &sealErr)
+	require.Equal(t, block.Hash(), sealErr.Hash)
+	require.Equal(t, newAddr, sealErr.Signer)
+	require.NotEqual(t, common.Address{}, sealErr.Proposer)
 
 	header.Difficulty = new(big.Int).SetUint64(expectedDifficulty)
 	sign(t, header, signerKey, init.genesis.Config.Bor)
@@ -762,9 +893,20 @@ func TestSignerNotFound(t *testing.T) {
 	block = buildNextBlock(t, _bor, chain, block, signerKey, init.genesis.Config.Bor, nil, heimdallSpan.ValidatorSet.Validators)
 
 	_, err := chain.InsertChain([]*types.Block{block})
+
+	var signerErr *bor.UnauthorizedSignerError
+
+	require.ErrorAs(t, err, &signerErr)
 	require.Equal(t,
-		*err.(*bor.UnauthorizedSignerError),
+		*signerErr,
 		bor.UnauthorizedSignerError{Number: 0, Signer: newAddr.Bytes()})
+
+	var sealErr *bor.SealError
+
+	require.ErrorAs(t, err, &sealErr)
+	require.Equal(t, block.NumberU64(), sealErr.Number)
+	require.Equal(t, block.Hash(), sealErr.Hash)
+	require.Equal(t, newAddr, sealErr.Signer)
 }
 
 // TestEIP1559Transition tests the following: